@@ -0,0 +1,39 @@
+// Package anomaly implements detection of anomalous points by scores where greater is more anomalous
+package anomaly
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrNotFitted             = errors.New("detector is not fitted")
+	ErrEmptyData             = errors.New("there are no points to fit")
+	ErrDimensionMismatch     = errors.New("point dimension doesn't match fitted dimension")
+	ErrContaminationNotValid = errors.New("contamination is not in range [0, 0.5]")
+	ErrParamNotValid         = errors.New("detector parameter is not valid")
+)
+
+func checkContamination(contamination float64) {
+	if contamination < 0 || contamination > 0.5 {
+		panic(ErrContaminationNotValid)
+	}
+}
+
+// threshold with a contamination fraction of scores above it, or fallback if contamination is zero
+func threshold(scores []float64, contamination, fallback float64) float64 {
+	if contamination == 0 {
+		return fallback
+	}
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+	i := int(float64(len(sorted)) * (1 - contamination))
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	if i > 0 {
+		// middle between the greatest inlier and the least outlier
+		return (sorted[i-1] + sorted[i]) / 2
+	}
+	return sorted[0]
+}
@@ -0,0 +1,92 @@
+package anomaly
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// normal cluster with some far outliers at the end
+func contaminated(inliers, outliers int, seed int64) []knn.Point {
+	rnd := rand.New(rand.NewSource(seed))
+	points := make([]knn.Point, 0, inliers+outliers)
+	for i := 0; i < inliers; i++ {
+		points = append(points, knn.Point{rnd.NormFloat64(), rnd.NormFloat64()})
+	}
+	for i := 0; i < outliers; i++ {
+		points = append(points, knn.Point{8 + rnd.Float64(), -8 - rnd.Float64()})
+		if i%2 == 1 {
+			points[len(points)-1] = knn.Point{-7 - rnd.Float64(), 9 + rnd.Float64()}
+		}
+	}
+	return points
+}
+
+type detector interface {
+	Predict(knn.Point) bool
+}
+
+func detected(d detector, points []knn.Point) int {
+	count := 0
+	for _, p := range points {
+		if d.Predict(p) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestThreshold(t *testing.T) {
+	scores := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if got := threshold(scores, 0.2, 0); got != 8.5 {
+		t.Errorf("threshold failed. Expected 8.5, but got %v", got)
+	}
+	if got := threshold(scores, 0, 0.5); got != 0.5 {
+		t.Errorf("threshold failed. Expected fallback 0.5, but got %v", got)
+	}
+}
+
+func TestIsolationForest(t *testing.T) {
+	points := contaminated(500, 10, 1)
+	forest := NewIsolationForest(0, 0, 0.02, 1)
+	forest.Fit(points)
+	if got := detected(forest, points[500:]); got != 10 {
+		t.Errorf("IsolationForest failed. Expected 10 outliers detected, but got %d", got)
+	}
+	if got := detected(forest, points[:500]); got > 5 {
+		t.Errorf("IsolationForest failed. Expected few false positives, but got %d", got)
+	}
+	if forest.Score(knn.Point{0, 0}) >= forest.Score(knn.Point{10, 10}) {
+		t.Errorf("Score failed. Center is more anomalous than far point")
+	}
+}
+
+func TestLOF(t *testing.T) {
+	points := contaminated(300, 6, 2)
+	for _, index := range []knn.Index{nil, knn.NewKDTree()} {
+		lof := NewLOF(10, knn.NewEuclideanDist(), index, 0.019)
+		lof.Fit(points)
+		outliers := 0
+		for i, score := range lof.TrainingScores() {
+			if score > lof.Threshold() {
+				if i < 300 {
+					t.Errorf("LOF failed. Inlier %v detected as outlier", points[i])
+				}
+				outliers++
+			}
+		}
+		if outliers != 6 {
+			t.Errorf("LOF failed. Expected 6 outliers detected, but got %d", outliers)
+		}
+		if !lof.Predict(knn.Point{-8, 8}) {
+			t.Errorf("Predict failed. Expected new far point to be an outlier")
+		}
+		if score := lof.Score(knn.Point{0.1, 0.1}); score > 1.2 {
+			t.Errorf("Score failed. Expected inlier score near 1, but got %v", score)
+		}
+		if len(lof.TrainingScores()) != len(points) {
+			t.Errorf("TrainingScores failed. Unexpected length %d", len(lof.TrainingScores()))
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package anomaly
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// node of isolation tree, leaves have feature -1
+type isoNode struct {
+	feature     int
+	split       float64
+	left, right *isoNode
+	size        int
+}
+
+// Isolation forest, anomalies are isolated by few random splits
+type IsolationForest struct {
+	trees         int
+	sampleSize    int
+	contamination float64
+	seed          int64
+	roots         []*isoNode
+	norm          float64 //average path length of sample size
+	threshold     float64
+	dim           int
+}
+
+// Create isolation forest with trees of samples of sampleSize points, 100 trees and 256 samples if zero
+//
+// contamination is the expected fraction of anomalies of training points, it sets the threshold of Predict,
+// which is 0.5 if contamination is zero
+func NewIsolationForest(trees, sampleSize int, contamination float64, seed int64) *IsolationForest {
+	if trees < 0 || sampleSize < 0 {
+		panic(ErrParamNotValid)
+	}
+	checkContamination(contamination)
+	if trees == 0 {
+		trees = 100
+	}
+	if sampleSize == 0 {
+		sampleSize = 256
+	}
+	return &IsolationForest{trees: trees, sampleSize: sampleSize, contamination: contamination, seed: seed}
+}
+
+// average path length of unsuccessful search in a binary search tree of n points
+func averagePath(n int) float64 {
+	switch {
+	case n <= 1:
+		return 0
+	case n == 2:
+		return 1
+	}
+	return 2*(math.Log(float64(n-1))+0.5772156649) - 2*float64(n-1)/float64(n)
+}
+
+func buildIsoTree(points []knn.Point, depth, limit int, rnd *rand.Rand) *isoNode {
+	if depth >= limit || len(points) <= 1 {
+		return &isoNode{feature: -1, size: len(points)}
+	}
+	// features with some spread
+	dim := len(points[0])
+	candidates := make([]int, 0, dim)
+	for d := 0; d < dim; d++ {
+		lo, hi := points[0][d], points[0][d]
+		for _, p := range points {
+			lo, hi = math.Min(lo, p[d]), math.Max(hi, p[d])
+		}
+		if hi > lo {
+			candidates = append(candidates, d)
+		}
+	}
+	if len(candidates) == 0 {
+		return &isoNode{feature: -1, size: len(points)}
+	}
+	feature := candidates[rnd.Intn(len(candidates))]
+	lo, hi := points[0][feature], points[0][feature]
+	for _, p := range points {
+		lo, hi = math.Min(lo, p[feature]), math.Max(hi, p[feature])
+	}
+	split := lo + rnd.Float64()*(hi-lo)
+	var left, right []knn.Point
+	for _, p := range points {
+		if p[feature] < split {
+			left = append(left, p)
+		} else {
+			right = append(right, p)
+		}
+	}
+	return &isoNode{
+		feature: feature,
+		split:   split,
+		left:    buildIsoTree(left, depth+1, limit, rnd),
+		right:   buildIsoTree(right, depth+1, limit, rnd),
+	}
+}
+
+func (node *isoNode) pathLength(point knn.Point) float64 {
+	depth := 0.0
+	for node.feature != -1 {
+		if point[node.feature] < node.split {
+			node = node.left
+		} else {
+			node = node.right
+		}
+		depth++
+	}
+	return depth + averagePath(node.size)
+}
+
+// Fit isolation trees of random samples of points
+func (forest *IsolationForest) Fit(points []knn.Point) {
+	if len(points) == 0 {
+		panic(ErrEmptyData)
+	}
+	forest.dim = len(points[0])
+	for _, p := range points {
+		if len(p) != forest.dim {
+			panic(ErrDimensionMismatch)
+		}
+	}
+	size := forest.sampleSize
+	if size > len(points) {
+		size = len(points)
+	}
+	limit := int(math.Ceil(math.Log2(float64(size))))
+	rnd := rand.New(rand.NewSource(forest.seed))
+	forest.roots = make([]*isoNode, forest.trees)
+	sample := make([]knn.Point, size)
+	for t := range forest.roots {
+		for i, p := range rnd.Perm(len(points))[:size] {
+			sample[i] = points[p]
+		}
+		forest.roots[t] = buildIsoTree(sample, 0, limit, rnd)
+	}
+	forest.norm = averagePath(size)
+	scores := make([]float64, len(points))
+	for i, p := range points {
+		scores[i] = forest.Score(p)
+	}
+	forest.threshold = threshold(scores, forest.contamination, 0.5)
+}
+
+// Anomaly score of point in (0, 1], near 1 for anomalies and below 0.5 for normal points
+func (forest *IsolationForest) Score(point knn.Point) float64 {
+	if forest.roots == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != forest.dim {
+		panic(ErrDimensionMismatch)
+	}
+	if forest.norm == 0 {
+		return 0.5
+	}
+	sum := 0.0
+	for _, root := range forest.roots {
+		sum += root.pathLength(point)
+	}
+	return math.Pow(2, -sum/float64(len(forest.roots))/forest.norm)
+}
+
+// Test if point is an anomaly, its score is greater than the threshold
+func (forest *IsolationForest) Predict(point knn.Point) bool {
+	return forest.Score(point) > forest.threshold
+}
+
+// Threshold of anomaly scores
+func (forest *IsolationForest) Threshold() float64 {
+	return forest.threshold
+}
+
+// Set threshold of anomaly scores
+func (forest *IsolationForest) SetThreshold(threshold float64) {
+	forest.threshold = threshold
+}
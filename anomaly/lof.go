@@ -0,0 +1,140 @@
+package anomaly
+
+import (
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// Local outlier factor, the ratio of the local density of neighbors to the local density of a point
+type LOF struct {
+	k             int
+	dist          knn.Distance
+	index         knn.Index
+	contamination float64
+	model         *knn.KNN
+	kDist         []float64 //distance to k-th neighbor of every training point
+	lrd           []float64 //local reachability density of every training point
+	scores        []float64
+	threshold     float64
+	dim           int
+}
+
+// Create LOF with k neighbors, neighbors are searched with index if it is not nil
+//
+// contamination is the expected fraction of anomalies of training points, it sets the threshold of Predict,
+// which is 1.5 if contamination is zero
+func NewLOF(k int, dist knn.Distance, index knn.Index, contamination float64) *LOF {
+	if k < 1 {
+		panic(knn.ErrKIsNotValid)
+	}
+	checkContamination(contamination)
+	return &LOF{k: k, dist: dist, index: index, contamination: contamination}
+}
+
+// indices and distances of k nearest training points, without training point skip
+func (lof *LOF) neighbors(point knn.Point, skip int) ([]int, []float64) {
+	k := lof.k
+	if skip >= 0 {
+		k++
+	}
+	found := lof.model.KNeighbors(point, k)
+	ids := make([]int, 0, len(found))
+	dists := make([]float64, 0, len(found))
+	for _, dd := range found {
+		id := dd.DataPoint().Label().(int)
+		if id == skip || len(ids) == lof.k {
+			continue
+		}
+		ids = append(ids, id)
+		dists = append(dists, dd.Dist())
+	}
+	return ids, dists
+}
+
+// local reachability density with neighbors
+func (lof *LOF) density(ids []int, dists []float64) float64 {
+	sum := 0.0
+	for i, id := range ids {
+		sum += math.Max(lof.kDist[id], dists[i])
+	}
+	// duplicated points have infinite density, it is bounded
+	return float64(len(ids)) / math.Max(sum, 1e-10)
+}
+
+// Fit local densities of points
+func (lof *LOF) Fit(points []knn.Point) {
+	if len(points) <= lof.k {
+		panic(knn.ErrNotEnoughData)
+	}
+	lof.dim = len(points[0])
+	data := make([]knn.DataPoint, len(points))
+	for i, p := range points {
+		if len(p) != lof.dim {
+			panic(ErrDimensionMismatch)
+		}
+		// label keeps index of point
+		data[i] = knn.NewDataPoint(i, p)
+	}
+	opts := []knn.Option{}
+	if lof.index != nil {
+		opts = append(opts, knn.WithIndex(lof.index))
+	}
+	lof.model = knn.NewKNN(lof.k, lof.dist, nil, data, opts...)
+	ids := make([][]int, len(points))
+	dists := make([][]float64, len(points))
+	lof.kDist = make([]float64, len(points))
+	for i, p := range points {
+		ids[i], dists[i] = lof.neighbors(p, i)
+		lof.kDist[i] = dists[i][len(dists[i])-1]
+	}
+	lof.lrd = make([]float64, len(points))
+	for i := range points {
+		lof.lrd[i] = lof.density(ids[i], dists[i])
+	}
+	lof.scores = make([]float64, len(points))
+	for i := range points {
+		lof.scores[i] = lof.factor(ids[i], lof.lrd[i])
+	}
+	lof.threshold = threshold(lof.scores, lof.contamination, 1.5)
+}
+
+func (lof *LOF) factor(ids []int, lrd float64) float64 {
+	sum := 0.0
+	for _, id := range ids {
+		sum += lof.lrd[id]
+	}
+	return sum / float64(len(ids)) / lrd
+}
+
+// Local outlier factor of a new point against training points, near 1 for inliers
+func (lof *LOF) Score(point knn.Point) float64 {
+	if lof.model == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != lof.dim {
+		panic(ErrDimensionMismatch)
+	}
+	ids, dists := lof.neighbors(point, -1)
+	return lof.factor(ids, lof.density(ids, dists))
+}
+
+// Local outlier factors of training points
+func (lof *LOF) TrainingScores() []float64 {
+	return lof.scores
+}
+
+// Test if point is an anomaly, its score is greater than the threshold
+func (lof *LOF) Predict(point knn.Point) bool {
+	return lof.Score(point) > lof.threshold
+}
+
+// Threshold of anomaly scores
+func (lof *LOF) Threshold() float64 {
+	return lof.threshold
+}
+
+// Set threshold of anomaly scores
+func (lof *LOF) SetThreshold(threshold float64) {
+	lof.threshold = threshold
+}
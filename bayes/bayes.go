@@ -0,0 +1,331 @@
+// Package bayes implements naive Bayes classifiers
+package bayes
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var (
+	ErrNotFitted         = errors.New("classifier is not fitted")
+	ErrEmptyData         = errors.New("there are no data points to fit")
+	ErrDimensionMismatch = errors.New("point dimension doesn't match fitted dimension")
+	ErrNegativeCount     = errors.New("feature count is negative")
+	ErrAlphaNotValid     = errors.New("smoothing alpha is lesser than zero")
+)
+
+// classes of data points in order of first appearance
+type classes struct {
+	labels   []any
+	logPrior []float64
+	dim      int
+}
+
+// group indices of data points by class and compute log priors
+func (cl *classes) fit(data []knn.DataPoint) [][]int {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	cl.dim = len(data[0].Point())
+	ids := make(map[any]int)
+	cl.labels = cl.labels[:0]
+	groups := make([][]int, 0, 10)
+	for i, dp := range data {
+		if len(dp.Point()) != cl.dim {
+			panic(ErrDimensionMismatch)
+		}
+		id, ok := ids[dp.Label()]
+		if !ok {
+			id = len(cl.labels)
+			ids[dp.Label()] = id
+			cl.labels = append(cl.labels, dp.Label())
+			groups = append(groups, nil)
+		}
+		groups[id] = append(groups[id], i)
+	}
+	cl.logPrior = make([]float64, len(groups))
+	for c, g := range groups {
+		cl.logPrior[c] = math.Log(float64(len(g)) / float64(len(data)))
+	}
+	return groups
+}
+
+func (cl *classes) check(point knn.Point) {
+	if cl.labels == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != cl.dim {
+		panic(ErrDimensionMismatch)
+	}
+}
+
+// label of greatest joint log likelihood
+func (cl *classes) predict(joint []float64) any {
+	best := 0
+	for c := range joint {
+		if joint[c] > joint[best] {
+			best = c
+		}
+	}
+	return cl.labels[best]
+}
+
+// normalize joint log likelihoods to probabilities by log-sum-exp
+func (cl *classes) proba(joint []float64) map[any]float64 {
+	maxLog := math.Inf(-1)
+	for _, j := range joint {
+		maxLog = math.Max(maxLog, j)
+	}
+	sum := 0.0
+	for _, j := range joint {
+		sum += math.Exp(j - maxLog)
+	}
+	proba := make(map[any]float64, len(joint))
+	for c, j := range joint {
+		proba[cl.labels[c]] = math.Exp(j-maxLog) / sum
+	}
+	return proba
+}
+
+// Gaussian naive Bayes for continuous features
+type GaussianNB struct {
+	classes
+	varSmoothing float64
+	mean         [][]float64
+	variance     [][]float64
+}
+
+// Create Gaussian naive Bayes, varSmoothing times the greatest feature variance is added to every variance
+func NewGaussianNB(varSmoothing float64) *GaussianNB {
+	return &GaussianNB{varSmoothing: varSmoothing}
+}
+
+// Fit mean and variance of every feature by class
+func (nb *GaussianNB) Fit(data []knn.DataPoint) {
+	groups := nb.classes.fit(data)
+	// smoothing relative to the greatest variance of features, so it doesn't depend on scale
+	maxVar := 0.0
+	for d := 0; d < nb.dim; d++ {
+		mean, sq := 0.0, 0.0
+		for _, dp := range data {
+			mean += dp.Point()[d]
+		}
+		mean /= float64(len(data))
+		for _, dp := range data {
+			sq += (dp.Point()[d] - mean) * (dp.Point()[d] - mean)
+		}
+		maxVar = math.Max(maxVar, sq/float64(len(data)))
+	}
+	epsilon := nb.varSmoothing * maxVar
+	if epsilon == 0 {
+		epsilon = 1e-12
+	}
+	nb.mean = make([][]float64, len(groups))
+	nb.variance = make([][]float64, len(groups))
+	for c, g := range groups {
+		nb.mean[c] = make([]float64, nb.dim)
+		nb.variance[c] = make([]float64, nb.dim)
+		for _, i := range g {
+			for d, v := range data[i].Point() {
+				nb.mean[c][d] += v
+			}
+		}
+		for d := range nb.mean[c] {
+			nb.mean[c][d] /= float64(len(g))
+		}
+		for _, i := range g {
+			for d, v := range data[i].Point() {
+				dif := v - nb.mean[c][d]
+				nb.variance[c][d] += dif * dif
+			}
+		}
+		for d := range nb.variance[c] {
+			nb.variance[c][d] = nb.variance[c][d]/float64(len(g)) + epsilon
+		}
+	}
+}
+
+func (nb *GaussianNB) joint(point knn.Point) []float64 {
+	nb.check(point)
+	joint := make([]float64, len(nb.labels))
+	for c := range joint {
+		j := nb.logPrior[c]
+		for d, v := range point {
+			dif := v - nb.mean[c][d]
+			j -= 0.5*math.Log(2*math.Pi*nb.variance[c][d]) + dif*dif/(2*nb.variance[c][d])
+		}
+		joint[c] = j
+	}
+	return joint
+}
+
+// Predict label of point
+func (nb *GaussianNB) Predict(point knn.Point) any {
+	return nb.predict(nb.joint(point))
+}
+
+// Predict class probabilities of point
+func (nb *GaussianNB) PredictProba(point knn.Point) map[any]float64 {
+	return nb.proba(nb.joint(point))
+}
+
+// Multinomial naive Bayes for feature counts, like word counts of documents
+type MultinomialNB struct {
+	classes
+	alpha   float64
+	logProb [][]float64
+}
+
+// Create multinomial naive Bayes with Laplace (alpha = 1) or Lidstone smoothing
+func NewMultinomialNB(alpha float64) *MultinomialNB {
+	if alpha < 0 {
+		panic(ErrAlphaNotValid)
+	}
+	return &MultinomialNB{alpha: alpha}
+}
+
+// Fit smoothed log probabilities of features by class
+func (nb *MultinomialNB) Fit(data []knn.DataPoint) {
+	groups := nb.classes.fit(data)
+	nb.logProb = make([][]float64, len(groups))
+	for c, g := range groups {
+		counts := make([]float64, nb.dim)
+		total := 0.0
+		for _, i := range g {
+			for d, v := range data[i].Point() {
+				if v < 0 {
+					panic(ErrNegativeCount)
+				}
+				counts[d] += v
+				total += v
+			}
+		}
+		nb.logProb[c] = make([]float64, nb.dim)
+		for d := range counts {
+			nb.logProb[c][d] = math.Log((counts[d] + nb.alpha) / (total + nb.alpha*float64(nb.dim)))
+		}
+	}
+}
+
+func (nb *MultinomialNB) joint(point knn.Point) []float64 {
+	nb.check(point)
+	joint := make([]float64, len(nb.labels))
+	for c := range joint {
+		j := nb.logPrior[c]
+		for d, v := range point {
+			if v != 0 {
+				j += v * nb.logProb[c][d]
+			}
+		}
+		joint[c] = j
+	}
+	return joint
+}
+
+// Predict label of point
+func (nb *MultinomialNB) Predict(point knn.Point) any {
+	return nb.predict(nb.joint(point))
+}
+
+// Predict class probabilities of point
+func (nb *MultinomialNB) PredictProba(point knn.Point) map[any]float64 {
+	return nb.proba(nb.joint(point))
+}
+
+// Bernoulli naive Bayes for binary features, features greater than binarize are present
+type BernoulliNB struct {
+	classes
+	alpha    float64
+	binarize float64
+	logProb  [][]float64 //log probability of present feature
+	logNeg   [][]float64 //log probability of absent feature
+}
+
+// Create Bernoulli naive Bayes with Laplace (alpha = 1) or Lidstone smoothing
+func NewBernoulliNB(alpha, binarize float64) *BernoulliNB {
+	if alpha < 0 {
+		panic(ErrAlphaNotValid)
+	}
+	return &BernoulliNB{alpha: alpha, binarize: binarize}
+}
+
+// Fit smoothed probabilities of present features by class
+func (nb *BernoulliNB) Fit(data []knn.DataPoint) {
+	groups := nb.classes.fit(data)
+	nb.logProb = make([][]float64, len(groups))
+	nb.logNeg = make([][]float64, len(groups))
+	for c, g := range groups {
+		nb.logProb[c] = make([]float64, nb.dim)
+		nb.logNeg[c] = make([]float64, nb.dim)
+		for d := 0; d < nb.dim; d++ {
+			present := 0.0
+			for _, i := range g {
+				if data[i].Point()[d] > nb.binarize {
+					present++
+				}
+			}
+			p := (present + nb.alpha) / (float64(len(g)) + 2*nb.alpha)
+			nb.logProb[c][d] = math.Log(p)
+			nb.logNeg[c][d] = math.Log(1 - p)
+		}
+	}
+}
+
+func (nb *BernoulliNB) joint(point knn.Point) []float64 {
+	nb.check(point)
+	joint := make([]float64, len(nb.labels))
+	for c := range joint {
+		j := nb.logPrior[c]
+		for d, v := range point {
+			if v > nb.binarize {
+				j += nb.logProb[c][d]
+			} else {
+				j += nb.logNeg[c][d]
+			}
+		}
+		joint[c] = j
+	}
+	return joint
+}
+
+// Predict label of point
+func (nb *BernoulliNB) Predict(point knn.Point) any {
+	return nb.predict(nb.joint(point))
+}
+
+// Predict class probabilities of point
+func (nb *BernoulliNB) PredictProba(point knn.Point) map[any]float64 {
+	return nb.proba(nb.joint(point))
+}
+
+type gaussianSpec struct {
+	Labels       []any
+	LogPrior     []float64
+	Dim          int
+	VarSmoothing float64
+	Mean         [][]float64
+	Variance     [][]float64
+}
+
+// Encode classes and parameters of distributions
+func (nb *GaussianNB) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&gaussianSpec{Labels: nb.labels, LogPrior: nb.logPrior, Dim: nb.dim,
+		VarSmoothing: nb.varSmoothing, Mean: nb.mean, Variance: nb.variance})
+	return buf.Bytes(), err
+}
+
+// Decode classifier encoded by GobEncode
+func (nb *GaussianNB) GobDecode(b []byte) error {
+	var spec gaussianSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return err
+	}
+	*nb = GaussianNB{classes: classes{labels: spec.Labels, logPrior: spec.LogPrior, dim: spec.Dim},
+		varSmoothing: spec.VarSmoothing, mean: spec.Mean, variance: spec.Variance}
+	return nil
+}
@@ -0,0 +1,76 @@
+package bayes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/metrics"
+)
+
+type classifier interface {
+	Predict(point knn.Point) any
+}
+
+func score(model classifier, data []knn.DataPoint) float64 {
+	expected := make([]any, len(data))
+	predicted := make([]any, len(data))
+	for i, dp := range data {
+		expected[i] = dp.Label()
+		predicted[i] = model.Predict(dp.Point())
+	}
+	return metrics.Accuracy(expected, predicted)
+}
+
+func TestGaussianNB(t *testing.T) {
+	data := dataset.MakeBlobs(600, []knn.Point{{0, 0}, {4, 4}, {-4, 4}}, 1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.3, true, 1)
+	nb := NewGaussianNB(1e-9)
+	nb.Fit(train)
+	if acc := score(nb, test); acc < 0.95 {
+		t.Errorf("GaussianNB failed. Expected accuracy greater than 0.95, but got %v", acc)
+	}
+	proba := nb.PredictProba(knn.Point{4, 4})
+	if proba[1] < 0.99 {
+		t.Errorf("PredictProba failed. Expected class 1, but got %v", proba)
+	}
+}
+
+// documents of word counts, sports use words 0 and 1 and politics words 2 and 3
+func documents() []knn.DataPoint {
+	return []knn.DataPoint{
+		knn.NewDataPoint("sports", knn.Point{3, 2, 0, 0}),
+		knn.NewDataPoint("sports", knn.Point{2, 4, 1, 0}),
+		knn.NewDataPoint("sports", knn.Point{5, 1, 0, 1}),
+		knn.NewDataPoint("politics", knn.Point{0, 1, 4, 3}),
+		knn.NewDataPoint("politics", knn.Point{1, 0, 2, 5}),
+	}
+}
+
+func TestMultinomialNB(t *testing.T) {
+	nb := NewMultinomialNB(1)
+	nb.Fit(documents())
+	if got := nb.Predict(knn.Point{1, 1, 0, 0}); got != "sports" {
+		t.Errorf("MultinomialNB failed. Expected sports, but got %v", got)
+	}
+	if got := nb.Predict(knn.Point{0, 0, 1, 1}); got != "politics" {
+		t.Errorf("MultinomialNB failed. Expected politics, but got %v", got)
+	}
+	// very long documents don't underflow in log space
+	proba := nb.PredictProba(knn.Point{0, 0, 5000, 5000})
+	if math.IsNaN(proba["politics"]) || proba["politics"] != 1 {
+		t.Errorf("PredictProba failed. Unexpected probabilities %v", proba)
+	}
+}
+
+func TestBernoulliNB(t *testing.T) {
+	nb := NewBernoulliNB(1, 0)
+	nb.Fit(documents())
+	if got := nb.Predict(knn.Point{1, 1, 0, 0}); got != "sports" {
+		t.Errorf("BernoulliNB failed. Expected sports, but got %v", got)
+	}
+	if got := nb.Predict(knn.Point{0, 0, 1, 1}); got != "politics" {
+		t.Errorf("BernoulliNB failed. Expected politics, but got %v", got)
+	}
+}
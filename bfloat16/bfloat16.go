@@ -0,0 +1,91 @@
+// Package bfloat16 implements the brain floating point format, a float32 with the mantissa cut to 7 bits
+package bfloat16
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/stellviaproject/go-ia/float16"
+)
+
+const (
+	NaN    BFloat16 = 0x7FC0
+	InfPos BFloat16 = 0x7F80
+	InfNeg BFloat16 = 0xFF80
+)
+
+type BFloat16 uint16
+
+// Convert float32 to BFloat16 rounding to nearest, ties to even
+func FF32(value float32) BFloat16 {
+	bits := math.Float32bits(value)
+	if bits&0x7FFFFFFF > 0x7F800000 {
+		return NaN | BFloat16(bits>>16)&0x8000
+	}
+	// add half of the cut part, plus one on ties with odd result so they go to even
+	bits += 0x7FFF + (bits>>16)&1
+	return BFloat16(bits >> 16)
+}
+
+// Convert float64 to BFloat16 rounding to nearest, ties to even
+//
+// The float64 is rounded to float32 by odd rounding first, so there is no double rounding error
+func FF64(value float64) BFloat16 {
+	f32 := float32(value)
+	if !math.IsInf(float64(f32), 0) && !math.IsNaN(value) && float64(f32) != value {
+		bits := math.Float32bits(f32)
+		if bits&1 == 0 {
+			// make the last bit sticky towards the exact value
+			if math.Abs(value) > math.Abs(float64(f32)) {
+				bits++
+			} else {
+				bits--
+			}
+			f32 = math.Float32frombits(bits)
+		}
+	}
+	return FF32(f32)
+}
+
+// Convert to float32, it is exact
+func (bf BFloat16) ToF32() float32 {
+	return math.Float32frombits(uint32(bf) << 16)
+}
+
+// Convert to float64, it is exact
+func (bf BFloat16) ToF64() float64 {
+	return float64(bf.ToF32())
+}
+
+// Convert float32 to BFloat16 rounding to nearest, the receiver is ignored so it satisfies float16.Half
+func (bf BFloat16) FromF32(value float32) BFloat16 {
+	return FF32(value)
+}
+
+// Test if value is not a number
+func (bf BFloat16) IsNaN() bool {
+	return bf&0x7F80 == 0x7F80 && bf&0x007F != 0
+}
+
+// Convert Float16 to BFloat16 rounding to nearest, ties to even
+func FromFloat16(f16 float16.Float16) BFloat16 {
+	// Float16 is exact in float32, so there is a single rounding
+	return FF32(f16.ToF32())
+}
+
+// Convert to Float16 rounding to nearest, ties to even
+func (bf BFloat16) ToFloat16() float16.Float16 {
+	return float16.FF32(bf.ToF32())
+}
+
+// Shortest decimal representation that parses back to the same value
+func (bf BFloat16) String() string {
+	value := bf.ToF64()
+	for prec := 1; prec < 9; prec++ {
+		s := strconv.FormatFloat(value, 'g', prec, 64)
+		if parsed, err := strconv.ParseFloat(s, 64); err == nil && FF64(parsed) == bf {
+			return s
+		}
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
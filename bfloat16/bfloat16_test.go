@@ -0,0 +1,74 @@
+package bfloat16
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/float16"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for i := 0; i < 1<<16; i++ {
+		bf := BFloat16(i)
+		if bf.IsNaN() {
+			if !FF32(bf.ToF32()).IsNaN() {
+				t.Fatalf("RoundTrip failed. NaN %#x is not NaN after round trip", i)
+			}
+			continue
+		}
+		if got := FF32(bf.ToF32()); got != bf {
+			t.Fatalf("FF32 failed. Expected %#x after round trip, but got %#x", i, got)
+		}
+		if got := FF64(bf.ToF64()); got != bf {
+			t.Fatalf("FF64 failed. Expected %#x after round trip, but got %#x", i, got)
+		}
+	}
+}
+
+func TestRounding(t *testing.T) {
+	cases := []struct {
+		value    float64
+		expected BFloat16
+	}{
+		{1, 0x3F80},
+		{1 + 0x1p-8, 0x3F80},   // tie to even
+		{1 + 3*0x1p-8, 0x3F82}, // tie to even
+		{1 + 0x1p-8 + 0x1p-40, 0x3F81},
+		{-2, 0xC000},
+		{math.Inf(1), InfPos},
+		{math.MaxFloat32 * 2, InfPos},
+	}
+	for _, c := range cases {
+		if got := FF64(c.value); got != c.expected {
+			t.Errorf("FF64 failed. Expected %#x for %v, but got %#x", c.expected, c.value, got)
+		}
+	}
+	if s := FF32(0.1).String(); s != "0.1" {
+		t.Errorf("String failed. Expected 0.1, but got %s", s)
+	}
+}
+
+func TestFloat16Conversion(t *testing.T) {
+	for i := 0; i < 1<<16; i++ {
+		f16 := float16.Float16(i)
+		if f16.IsNaN() {
+			continue
+		}
+		bf := FromFloat16(f16)
+		if bf != FF64(f16.ToF64()) {
+			t.Fatalf("FromFloat16 failed. Expected %#x for %#x, but got %#x", FF64(f16.ToF64()), i, bf)
+		}
+		if back := bf.ToFloat16(); back != float16.FF64(bf.ToF64()) {
+			t.Fatalf("ToFloat16 failed. Expected %#x for %#x, but got %#x", float16.FF64(bf.ToF64()), bf, back)
+		}
+	}
+}
+
+func TestHalf(t *testing.T) {
+	src := []float16.Float16{float16.FF64(1.5), float16.FF64(-3), float16.FF64(0.25)}
+	dst := make([]BFloat16, len(src))
+	float16.Convert(dst, src)
+	if s := float16.SumHalf(dst); s != float16.SumHalf(src) || s != -1.25 {
+		t.Errorf("SumHalf failed. Expected -1.25, but got %v", s)
+	}
+}
@@ -0,0 +1,192 @@
+// Package calibration implements calibration of scores of classifiers into probabilities, by Platt scaling or
+// isotonic regression fitted on held-out data
+package calibration
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	ErrEmptyData      = errors.New("there are no scores to fit")
+	ErrLabelsMismatch = errors.New("number of labels doesn't match number of scores")
+	ErrNotFitted      = errors.New("calibrator is not fitted")
+)
+
+// Map of scores of a binary problem to probabilities of the positive class
+type Calibrator interface {
+	Fit(scores []float64, positive []bool)
+	Probability(score float64) float64
+}
+
+func checkScores(scores []float64, positive []bool) {
+	if len(scores) == 0 {
+		panic(ErrEmptyData)
+	}
+	if len(scores) != len(positive) {
+		panic(ErrLabelsMismatch)
+	}
+}
+
+// Platt scaling, a sigmoid 1 / (1 + exp(a*score + b)) fitted by maximum likelihood
+type Platt struct {
+	a, b   float64
+	fitted bool
+}
+
+// Create Platt scaling
+func NewPlatt() *Platt {
+	return &Platt{}
+}
+
+// Fit sigmoid by Newton's method with Platt's smoothed targets, so separable scores don't diverge
+func (p *Platt) Fit(scores []float64, positive []bool) {
+	checkScores(scores, positive)
+	var pos, neg float64
+	for _, y := range positive {
+		if y {
+			pos++
+		} else {
+			neg++
+		}
+	}
+	hi, lo := (pos+1)/(pos+2), 1/(neg+2)
+	targets := make([]float64, len(scores))
+	for i, y := range positive {
+		if y {
+			targets[i] = hi
+		} else {
+			targets[i] = lo
+		}
+	}
+	a, b := 0.0, math.Log((neg+1)/(pos+1))
+	loss := func(a, b float64) float64 {
+		sum := 0.0
+		for i, s := range scores {
+			f := a*s + b
+			// log(1 + exp(f)) - (1 - t) * f computed without overflow
+			if f >= 0 {
+				sum += targets[i]*f + math.Log1p(math.Exp(-f))
+			} else {
+				sum += (targets[i]-1)*f + math.Log1p(math.Exp(f))
+			}
+		}
+		return sum
+	}
+	current := loss(a, b)
+	for iter := 0; iter < 100; iter++ {
+		// gradient and hessian of negative log likelihood, with a small ridge to keep hessian definite
+		var ga, gb, haa, hab, hbb float64
+		for i, s := range scores {
+			q := 1 / (1 + math.Exp(a*s+b)) //probability of positive class
+			d := targets[i] - q
+			w := q * (1 - q)
+			ga += s * d
+			gb += d
+			haa += s * s * w
+			hab += s * w
+			hbb += w
+		}
+		haa += 1e-12
+		hbb += 1e-12
+		if math.Abs(ga) < 1e-9 && math.Abs(gb) < 1e-9 {
+			break
+		}
+		det := haa*hbb - hab*hab
+		da := -(hbb*ga - hab*gb) / det
+		db := -(haa*gb - hab*ga) / det
+		// backtracking line search
+		step := 1.0
+		for step > 1e-10 {
+			next := loss(a+step*da, b+step*db)
+			if next < current+1e-4*step*(ga*da+gb*db) {
+				a, b, current = a+step*da, b+step*db, next
+				break
+			}
+			step /= 2
+		}
+		if step <= 1e-10 {
+			break
+		}
+	}
+	p.a, p.b, p.fitted = a, b, true
+}
+
+// Probability of positive class of score
+func (p *Platt) Probability(score float64) float64 {
+	if !p.fitted {
+		panic(ErrNotFitted)
+	}
+	return 1 / (1 + math.Exp(p.a*score+p.b))
+}
+
+// Parameters a and b of sigmoid
+func (p *Platt) Params() (float64, float64) {
+	return p.a, p.b
+}
+
+// Isotonic regression, a nondecreasing step function of scores fitted by pool adjacent violators
+type Isotonic struct {
+	x, y []float64 //scores and probabilities of blocks, interpolated linearly between blocks
+}
+
+// Create isotonic regression
+func NewIsotonic() *Isotonic {
+	return &Isotonic{}
+}
+
+// Fit nondecreasing probabilities of scores
+func (iso *Isotonic) Fit(scores []float64, positive []bool) {
+	checkScores(scores, positive)
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return scores[order[i]] < scores[order[j]]
+	})
+	type block struct {
+		sum, weight, score float64 //sum of targets, number of scores and sum of scores
+		low, top           float64 //least and greatest score
+	}
+	blocks := make([]block, 0, len(scores))
+	for _, i := range order {
+		b := block{weight: 1, score: scores[i], low: scores[i], top: scores[i]}
+		if positive[i] {
+			b.sum = 1
+		}
+		// equal scores must share one value, and means must not decrease
+		for len(blocks) > 0 {
+			last := blocks[len(blocks)-1]
+			if last.sum/last.weight < b.sum/b.weight && last.top != b.low {
+				break
+			}
+			b = block{sum: last.sum + b.sum, weight: last.weight + b.weight, score: last.score + b.score,
+				low: last.low, top: b.top}
+			blocks = blocks[:len(blocks)-1]
+		}
+		blocks = append(blocks, b)
+	}
+	iso.x = make([]float64, len(blocks))
+	iso.y = make([]float64, len(blocks))
+	for k, b := range blocks {
+		iso.x[k], iso.y[k] = b.score/b.weight, b.sum/b.weight
+	}
+}
+
+// Probability of positive class of score, constant beyond fitted scores
+func (iso *Isotonic) Probability(score float64) float64 {
+	if iso.x == nil {
+		panic(ErrNotFitted)
+	}
+	k := sort.SearchFloat64s(iso.x, score)
+	switch {
+	case k == 0:
+		return iso.y[0]
+	case k == len(iso.x):
+		return iso.y[len(iso.y)-1]
+	}
+	u := (score - iso.x[k-1]) / (iso.x[k] - iso.x[k-1])
+	return iso.y[k-1] + u*(iso.y[k]-iso.y[k-1])
+}
@@ -0,0 +1,38 @@
+package calibration
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPlatt(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	scores := make([]float64, 5000)
+	positive := make([]bool, len(scores))
+	for i := range scores {
+		scores[i] = rnd.Float64()*6 - 3
+		positive[i] = rnd.Float64() < 1/(1+math.Exp(-2*scores[i]+0.5))
+	}
+	p := NewPlatt()
+	p.Fit(scores, positive)
+	a, b := p.Params()
+	if math.Abs(a+2) > 0.2 || math.Abs(b-0.5) > 0.2 {
+		t.Errorf("Platt failed. Expected params (-2, 0.5), but got (%v, %v)", a, b)
+	}
+	if p.Probability(3) < p.Probability(-3) {
+		t.Errorf("Platt failed. Expected increasing probabilities, but got %v and %v", p.Probability(-3), p.Probability(3))
+	}
+}
+
+func TestIsotonic(t *testing.T) {
+	iso := NewIsotonic()
+	// equal scores 1 share one probability, so they are pooled with the following violators
+	iso.Fit([]float64{4, 1, 3, 2, 1}, []bool{true, false, false, true, true})
+	expected := map[float64]float64{0: 0.5, 1.75: 0.5, 2.875: 0.75, 4: 1, 5: 1}
+	for score, e := range expected {
+		if p := iso.Probability(score); math.Abs(p-e) > 1e-9 {
+			t.Errorf("Isotonic failed. Expected probability %v of %v, but got %v", e, score, p)
+		}
+	}
+}
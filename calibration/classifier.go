@@ -0,0 +1,135 @@
+package calibration
+
+import (
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/model"
+)
+
+// Calibration method
+type Method int
+
+const (
+	Sigmoid  Method = iota //Platt scaling, for few calibration data or sigmoid shaped distortions
+	Monotone               //isotonic regression, for many calibration data
+)
+
+// Uncalibrated score of every class of point, greater is more likely, missing classes score zero
+type ScoreFunc func(point knn.Point) map[any]float64
+
+// Scores of a binary decision function, positive scores are the positive class
+func Decision(decision func(point knn.Point) float64, negative, positive any) ScoreFunc {
+	return func(point knn.Point) map[any]float64 {
+		s := decision(point)
+		return map[any]float64{negative: -s, positive: s}
+	}
+}
+
+// Configuration of calibrated classifier
+type Config struct {
+	Method  Method
+	Holdout float64 //fraction of data points held out to fit calibrators, 0.3 if zero
+	Seed    int64   //seed of stratified split of held out data points
+}
+
+// Classifier with probabilities calibrated on held-out data, one calibrator by class versus the rest
+type CalibratedClassifier struct {
+	classifier  model.PointModel
+	scores      ScoreFunc
+	config      Config
+	classes     []any
+	calibrators []Calibrator
+}
+
+// Create calibrated classifier of scores of classifier, like PredictProba of trees and knn or DecisionFunction of svm
+func NewCalibratedClassifier(classifier model.PointModel, scores ScoreFunc, config Config) *CalibratedClassifier {
+	if config.Holdout <= 0 {
+		config.Holdout = 0.3
+	}
+	return &CalibratedClassifier{classifier: classifier, scores: scores, config: config}
+}
+
+func (cc *CalibratedClassifier) newCalibrator() Calibrator {
+	if cc.config.Method == Monotone {
+		return NewIsotonic()
+	}
+	return NewPlatt()
+}
+
+// Fit classifier with data points that are not held out, then fit calibrators with scores of held out data points
+func (cc *CalibratedClassifier) Fit(data []knn.DataPoint) {
+	train, holdout := dataset.SplitTrainTest(data, cc.config.Holdout, true, cc.config.Seed)
+	cc.classifier.Fit(train)
+	cc.classes = cc.classes[:0]
+	seen := make(map[any]bool)
+	for _, dp := range data {
+		if !seen[dp.Label()] {
+			seen[dp.Label()] = true
+			cc.classes = append(cc.classes, dp.Label())
+		}
+	}
+	scores := make([]map[any]float64, len(holdout))
+	for i, dp := range holdout {
+		scores[i] = cc.scores(dp.Point())
+	}
+	// the first class of a binary problem is the complement of the second one
+	first := 0
+	if len(cc.classes) == 2 {
+		first = 1
+	}
+	cc.calibrators = make([]Calibrator, len(cc.classes))
+	for c := first; c < len(cc.classes); c++ {
+		values := make([]float64, len(holdout))
+		positive := make([]bool, len(holdout))
+		for i, dp := range holdout {
+			values[i] = scores[i][cc.classes[c]]
+			positive[i] = dp.Label() == cc.classes[c]
+		}
+		cc.calibrators[c] = cc.newCalibrator()
+		cc.calibrators[c].Fit(values, positive)
+	}
+}
+
+// Predict calibrated class probabilities of point, normalized to sum one
+func (cc *CalibratedClassifier) PredictProba(point knn.Point) map[any]float64 {
+	if cc.calibrators == nil {
+		panic(ErrNotFitted)
+	}
+	scores := cc.scores(point)
+	proba := make(map[any]float64, len(cc.classes))
+	if len(cc.classes) == 2 {
+		p := cc.calibrators[1].Probability(scores[cc.classes[1]])
+		proba[cc.classes[0]], proba[cc.classes[1]] = 1-p, p
+		return proba
+	}
+	sum := 0.0
+	for c, label := range cc.classes {
+		proba[label] = cc.calibrators[c].Probability(scores[label])
+		sum += proba[label]
+	}
+	for label := range proba {
+		if sum > 0 {
+			proba[label] /= sum
+		} else {
+			proba[label] = 1 / float64(len(cc.classes))
+		}
+	}
+	return proba
+}
+
+// Predict label of greatest calibrated probability
+func (cc *CalibratedClassifier) Predict(point knn.Point) any {
+	proba := cc.PredictProba(point)
+	best := cc.classes[0]
+	for _, label := range cc.classes {
+		if proba[label] > proba[best] {
+			best = label
+		}
+	}
+	return best
+}
+
+// Classes in order of first appearance in fitted data
+func (cc *CalibratedClassifier) Classes() []any {
+	return cc.classes
+}
@@ -0,0 +1,73 @@
+package calibration
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/svm"
+	"github.com/stellviaproject/go-ia/tree"
+)
+
+// mean squared error of probabilities of the true labels
+func brier(cc *CalibratedClassifier, data []knn.DataPoint) float64 {
+	sum := 0.0
+	for _, dp := range data {
+		proba := cc.PredictProba(dp.Point())
+		total := 0.0
+		for _, label := range cc.Classes() {
+			e := 0.0
+			if label == dp.Label() {
+				e = 1
+			}
+			sum += (proba[label] - e) * (proba[label] - e)
+			total += proba[label]
+		}
+		if math.Abs(total-1) > 1e-9 {
+			panic("probabilities don't sum one")
+		}
+	}
+	return sum / float64(len(data))
+}
+
+func TestCalibratedSVM(t *testing.T) {
+	data := dataset.MakeBlobs(600, []knn.Point{{0, 0}, {2, 2}}, 1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.3, true, 1)
+	s := svm.NewLinearSVM(0.01, 20, 1)
+	cc := NewCalibratedClassifier(s, Decision(s.DecisionFunction, 0, 1), Config{Method: Sigmoid, Seed: 1})
+	cc.Fit(train)
+	if b := brier(cc, test); b > 0.25 {
+		t.Errorf("CalibratedClassifier failed. Expected Brier score lesser than 0.25, but got %v", b)
+	}
+	if p := cc.PredictProba(knn.Point{3, 3})[1]; p < 0.9 {
+		t.Errorf("CalibratedClassifier failed. Expected probability greater than 0.9, but got %v", p)
+	}
+	if p := cc.PredictProba(knn.Point{1, 1})[1]; math.Abs(p-0.5) > 0.15 {
+		t.Errorf("CalibratedClassifier failed. Expected probability near 0.5 at the boundary, but got %v", p)
+	}
+}
+
+func TestCalibratedTree(t *testing.T) {
+	data := dataset.MakeBlobs(900, []knn.Point{{0, 0}, {2, 2}, {0, 3}}, 1, 2)
+	train, test := dataset.SplitTrainTest(data, 0.3, true, 2)
+	tr := tree.NewClassifier(tree.Config{})
+	// an unlimited tree predicts probabilities of 0 or 1, so its uncalibrated Brier score is high
+	cc := NewCalibratedClassifier(tr, tr.PredictProba, Config{Method: Monotone, Seed: 2})
+	cc.Fit(train)
+	uncalibrated := 0.0
+	for _, dp := range test {
+		proba := tr.PredictProba(dp.Point())
+		for _, label := range cc.Classes() {
+			e := 0.0
+			if label == dp.Label() {
+				e = 1
+			}
+			uncalibrated += (proba[label] - e) * (proba[label] - e)
+		}
+	}
+	uncalibrated /= float64(len(test))
+	if b := brier(cc, test); b >= uncalibrated {
+		t.Errorf("CalibratedClassifier failed. Expected Brier score lesser than %v, but got %v", uncalibrated, b)
+	}
+}
@@ -0,0 +1,195 @@
+package cluster
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// Label of points that don't belong to any cluster
+const Noise = -1
+
+var ErrDensityParamsNotValid = errors.New("eps is lesser than zero or minPts is lesser than one")
+
+// radius neighborhoods of points searched by a KNN, index is optional and it is used when not nil
+type neighborhoods struct {
+	model *knn.KNN
+}
+
+func newNeighborhoods(points []knn.Point, dist knn.Distance, index knn.Index) *neighborhoods {
+	data := make([]knn.DataPoint, len(points))
+	for i, p := range points {
+		// label keeps index of point
+		data[i] = knn.NewDataPoint(i, p)
+	}
+	opts := []knn.Option{}
+	if index != nil {
+		opts = append(opts, knn.WithIndex(index))
+	}
+	return &neighborhoods{model: knn.NewKNN(1, dist, nil, data, opts...)}
+}
+
+// indices and distances of points within radius, sorted by distance
+func (nh *neighborhoods) query(p knn.Point, radius float64) ([]int, []float64) {
+	found := nh.model.RadiusNeighbors(p, radius)
+	ids := make([]int, len(found))
+	dists := make([]float64, len(found))
+	for i, dd := range found {
+		ids[i] = dd.DataPoint().Label().(int)
+		dists[i] = dd.Dist()
+	}
+	return ids, dists
+}
+
+func checkDensityParams(eps float64, minPts int) {
+	if eps < 0 || minPts < 1 {
+		panic(ErrDensityParamsNotValid)
+	}
+}
+
+// Density-based clustering DBSCAN
+//
+// A point is core when it has at least minPts points, itself included, within eps. Clusters are the points
+// reachable from core points and the rest is Noise. Neighborhoods are searched with index if it is not nil.
+func DBSCAN(points []knn.Point, eps float64, minPts int, dist knn.Distance, index knn.Index) []int {
+	checkDensityParams(eps, minPts)
+	nh := newNeighborhoods(points, dist, index)
+	labels := make([]int, len(points))
+	visited := make([]bool, len(points))
+	for i := range labels {
+		labels[i] = Noise
+	}
+	cluster := 0
+	for i, p := range points {
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+		neighbors, _ := nh.query(p, eps)
+		if len(neighbors) < minPts {
+			continue
+		}
+		labels[i] = cluster
+		queue := neighbors
+		for len(queue) > 0 {
+			j := queue[0]
+			queue = queue[1:]
+			if labels[j] == Noise {
+				// border or core point of this cluster
+				labels[j] = cluster
+			}
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+			more, _ := nh.query(points[j], eps)
+			if len(more) >= minPts {
+				queue = append(queue, more...)
+			}
+		}
+		cluster++
+	}
+	return labels
+}
+
+// Result of OPTICS ordering
+type OPTICSResult struct {
+	Order        []int     //points in cluster order
+	Reachability []float64 //reachability distance of every point, +Inf if it is not reachable
+	CoreDist     []float64 //core distance of every point, +Inf if it is not core
+}
+
+// seeds of OPTICS by reachability, stale entries are skipped when popped
+type seed struct {
+	point int
+	reach float64
+}
+
+type seedHeap []seed
+
+func (h seedHeap) Len() int           { return len(h) }
+func (h seedHeap) Less(i, j int) bool { return h[i].reach < h[j].reach }
+func (h seedHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *seedHeap) Push(x any)        { *h = append(*h, x.(seed)) }
+func (h *seedHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Density-based cluster ordering OPTICS
+//
+// Core distance is the distance to the minPts-th point within maxEps, itself included. Clusters of any
+// eps <= maxEps are extracted from the result without running it again.
+func OPTICS(points []knn.Point, maxEps float64, minPts int, dist knn.Distance, index knn.Index) *OPTICSResult {
+	checkDensityParams(maxEps, minPts)
+	nh := newNeighborhoods(points, dist, index)
+	n := len(points)
+	res := &OPTICSResult{
+		Order:        make([]int, 0, n),
+		Reachability: make([]float64, n),
+		CoreDist:     make([]float64, n),
+	}
+	for i := range res.Reachability {
+		res.Reachability[i] = math.Inf(1)
+	}
+	processed := make([]bool, n)
+	process := func(i int, seeds *seedHeap) {
+		processed[i] = true
+		res.Order = append(res.Order, i)
+		neighbors, dists := nh.query(points[i], maxEps)
+		res.CoreDist[i] = math.Inf(1)
+		if len(neighbors) < minPts {
+			return
+		}
+		core := dists[minPts-1]
+		res.CoreDist[i] = core
+		for k, j := range neighbors {
+			if processed[j] {
+				continue
+			}
+			reach := math.Max(core, dists[k])
+			if reach < res.Reachability[j] {
+				res.Reachability[j] = reach
+				heap.Push(seeds, seed{point: j, reach: reach})
+			}
+		}
+	}
+	for i := range points {
+		if processed[i] {
+			continue
+		}
+		seeds := &seedHeap{}
+		process(i, seeds)
+		for seeds.Len() > 0 {
+			s := heap.Pop(seeds).(seed)
+			if processed[s.point] || s.reach > res.Reachability[s.point] {
+				continue
+			}
+			process(s.point, seeds)
+		}
+	}
+	return res
+}
+
+// Clusters of the ordering for eps <= maxEps, like DBSCAN except that some border points can be Noise
+func (res *OPTICSResult) ExtractDBSCAN(eps float64) []int {
+	labels := make([]int, len(res.Order))
+	cluster := -1
+	for _, i := range res.Order {
+		if res.Reachability[i] > eps {
+			if res.CoreDist[i] <= eps {
+				cluster++
+				labels[i] = cluster
+			} else {
+				labels[i] = Noise
+			}
+		} else {
+			labels[i] = cluster
+		}
+	}
+	return labels
+}
@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func moons() ([]knn.DataPoint, []knn.Point) {
+	data := dataset.MakeMoons(300, 0.03, 1)
+	points := make([]knn.Point, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+	}
+	// far outlier
+	points = append(points, knn.Point{10, 10})
+	return data, points
+}
+
+// test that every moon is a single cluster and the outlier is noise
+func checkMoons(t *testing.T, name string, data []knn.DataPoint, labels []int) {
+	clusters := make(map[any]int)
+	for i, dp := range data {
+		if labels[i] == Noise {
+			continue
+		}
+		if prev, ok := clusters[dp.Label()]; ok && prev != labels[i] {
+			t.Fatalf("%s failed. Moon %v split in clusters %d and %d", name, dp.Label(), prev, labels[i])
+		}
+		clusters[dp.Label()] = labels[i]
+	}
+	if len(clusters) != 2 || clusters[0] == clusters[1] {
+		t.Fatalf("%s failed. Unexpected clusters %v", name, clusters)
+	}
+	if labels[len(labels)-1] != Noise {
+		t.Errorf("%s failed. Expected outlier as noise, but got cluster %d", name, labels[len(labels)-1])
+	}
+}
+
+func TestDBSCAN(t *testing.T) {
+	data, points := moons()
+	labels := DBSCAN(points, 0.2, 4, knn.NewEuclideanDist(), nil)
+	checkMoons(t, "DBSCAN", data, labels)
+	indexed := DBSCAN(points, 0.2, 4, knn.NewEuclideanDist(), knn.NewKDTree())
+	for i := range labels {
+		if labels[i] != indexed[i] {
+			t.Fatalf("DBSCAN failed. Expected same clusters with index")
+		}
+	}
+}
+
+func TestOPTICS(t *testing.T) {
+	data, points := moons()
+	res := OPTICS(points, 1, 4, knn.NewEuclideanDist(), knn.NewKDTree())
+	if len(res.Order) != len(points) {
+		t.Fatalf("OPTICS failed. Expected %d ordered points, but got %d", len(points), len(res.Order))
+	}
+	checkMoons(t, "OPTICS", data, res.ExtractDBSCAN(0.2))
+}
@@ -0,0 +1,244 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var ErrMatrixNotSquare = errors.New("distance matrix is not a square matrix")
+
+// Linkage of clusters, distance between clusters computed from distances between their points
+type Linkage int
+
+const (
+	Single   Linkage = iota //distance of closest points
+	Complete                //distance of farthest points
+	Average                 //mean distance of pairs of points
+	Ward                    //increase of variance, distances must be euclidean
+)
+
+// Merge of two clusters, clusters lesser than n are points and cluster n+i is the result of merge i
+type Merge struct {
+	A, B   int     //merged clusters
+	Height float64 //linkage distance of merged clusters
+	Size   int     //number of points of the new cluster
+}
+
+// Dendrogram of hierarchical clustering of n points, merges are sorted by height
+type Dendrogram struct {
+	N      int
+	Merges []Merge
+}
+
+// Agglomerative hierarchical clustering of a distance matrix of shape (n, n)
+//
+// It runs the nearest neighbor chain algorithm, O(n²) time and memory for every linkage
+func Agglomerative(distances *graph.Tensor, linkage Linkage) *Dendrogram {
+	shape := distances.Shape()
+	if shape.Dim() != 2 || shape[0] != shape[1] {
+		panic(ErrMatrixNotSquare)
+	}
+	n := shape[0]
+	dist := make([][]float64, n)
+	index := make([]int, 2)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		index[0] = i
+		for j := range dist[i] {
+			index[1] = j
+			dist[i][j] = tensorValue(distances, index)
+			if linkage == Ward {
+				// Ward updates are exact on squared distances
+				dist[i][j] *= dist[i][j]
+			}
+		}
+	}
+	size := make([]int, n)
+	active := make([]bool, n)
+	for i := range size {
+		size[i] = 1
+		active[i] = true
+	}
+	// merges of slots, slot a keeps point a and the clusters merged with it
+	merges := make([]Merge, 0, n)
+	chain := make([]int, 0, n)
+	for len(merges) < n-1 {
+		if len(chain) == 0 {
+			for i := range active {
+				if active[i] {
+					chain = append(chain, i)
+					break
+				}
+			}
+		}
+		a := chain[len(chain)-1]
+		b, best := -1, math.Inf(1)
+		if len(chain) > 1 {
+			// previous cluster wins ties, so the chain always ends
+			b = chain[len(chain)-2]
+			best = dist[a][b]
+		}
+		for c := range active {
+			if active[c] && c != a && dist[a][c] < best {
+				b, best = c, dist[a][c]
+			}
+		}
+		if len(chain) < 2 || b != chain[len(chain)-2] {
+			chain = append(chain, b)
+			continue
+		}
+		chain = chain[:len(chain)-2]
+		if b < a {
+			a, b = b, a
+		}
+		for c := range active {
+			if active[c] && c != a && c != b {
+				d := lanceWilliams(linkage, dist[a][c], dist[b][c], dist[a][b], size[a], size[b], size[c])
+				dist[a][c], dist[c][a] = d, d
+			}
+		}
+		size[a] += size[b]
+		active[b] = false
+		merges = append(merges, Merge{A: a, B: b, Height: best, Size: size[a]})
+	}
+	sort.SliceStable(merges, func(i, j int) bool {
+		return merges[i].Height < merges[j].Height
+	})
+	// relabel slots with cluster ids in order of height
+	uf := newUnionFind(n)
+	for i := range merges {
+		m := &merges[i]
+		ra, rb := uf.find(m.A), uf.find(m.B)
+		m.A, m.B = uf.id[ra], uf.id[rb]
+		if m.A > m.B {
+			m.A, m.B = m.B, m.A
+		}
+		if linkage == Ward {
+			m.Height = math.Sqrt(m.Height)
+		}
+		uf.union(ra, rb, n+i)
+	}
+	return &Dendrogram{N: n, Merges: merges}
+}
+
+func tensorValue(ts *graph.Tensor, index []int) float64 {
+	switch v := ts.Get(index).(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	}
+	return ts.Get(index).(interface{ ToF64() float64 }).ToF64()
+}
+
+// distance of cluster c to the merge of a and b
+func lanceWilliams(linkage Linkage, dac, dbc, dab float64, na, nb, nc int) float64 {
+	switch linkage {
+	case Single:
+		return math.Min(dac, dbc)
+	case Complete:
+		return math.Max(dac, dbc)
+	case Average:
+		return (float64(na)*dac + float64(nb)*dbc) / float64(na+nb)
+	}
+	fa, fb, fc := float64(na), float64(nb), float64(nc)
+	return ((fa+fc)*dac + (fb+fc)*dbc - fc*dab) / (fa + fb + fc)
+}
+
+// union find of points with the id of the cluster of every root
+type unionFind struct {
+	parent []int
+	id     []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n), id: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+		uf.id[i] = i
+	}
+	return uf
+}
+
+func (uf *unionFind) find(i int) int {
+	for uf.parent[i] != i {
+		uf.parent[i] = uf.parent[uf.parent[i]]
+		i = uf.parent[i]
+	}
+	return i
+}
+
+func (uf *unionFind) union(a, b, id int) {
+	uf.parent[b] = a
+	uf.id[a] = id
+}
+
+// labels of points after applying the first count merges, numbered from zero in order of first point
+func (dg *Dendrogram) labels(count int) []int {
+	uf := newUnionFind(dg.N)
+	// merges refer to cluster ids, so keep a point of every cluster
+	point := make([]int, dg.N+len(dg.Merges))
+	for i := 0; i < dg.N; i++ {
+		point[i] = i
+	}
+	for i, m := range dg.Merges {
+		point[dg.N+i] = point[m.A]
+		if i < count {
+			uf.union(uf.find(point[m.A]), uf.find(point[m.B]), dg.N+i)
+		}
+	}
+	labels := make([]int, dg.N)
+	ids := make(map[int]int)
+	for i := range labels {
+		root := uf.find(i)
+		id, ok := ids[root]
+		if !ok {
+			id = len(ids)
+			ids[root] = id
+		}
+		labels[i] = id
+	}
+	return labels
+}
+
+// Cut dendrogram in k clusters
+func (dg *Dendrogram) CutK(k int) []int {
+	if k <= 0 || k > dg.N {
+		panic(ErrKNotValid)
+	}
+	return dg.labels(dg.N - k)
+}
+
+// Cut dendrogram at height, clusters are merged while their height is lesser or equal to height
+func (dg *Dendrogram) CutHeight(height float64) []int {
+	count := sort.Search(len(dg.Merges), func(i int) bool {
+		return dg.Merges[i].Height > height
+	})
+	return dg.labels(count)
+}
+
+// Graph of dendrogram, every point and merge is a node and every cluster has an edge to the merge that contains it
+//
+// Points are named by its index and merges by its cluster id with the height, node values are cluster ids
+func (dg *Dendrogram) Graph() graph.Graph {
+	g := graph.New("dendrogram")
+	for i := 0; i < dg.N; i++ {
+		g.AddNode(fmt.Sprint(i), i)
+	}
+	for i, m := range dg.Merges {
+		id := g.AddNode(fmt.Sprintf("c%d_h%.3g", dg.N+i, m.Height), dg.N+i)
+		g.AddWeightedEdge(m.A, id, m.Height)
+		g.AddWeightedEdge(m.B, id, m.Height)
+	}
+	return g
+}
+
+// Write DOT representation of dendrogram graph to file
+func (dg *Dendrogram) ToDot(fileName string) error {
+	g := dg.Graph()
+	return g.ToDot(fileName)
+}
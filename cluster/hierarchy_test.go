@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func linePoints() []knn.Point {
+	return []knn.Point{{0}, {1}, {3}, {7}}
+}
+
+func TestAgglomerative(t *testing.T) {
+	matrix := knn.DistanceMatrix(linePoints(), knn.NewEuclideanDist(), 1)
+	cases := []struct {
+		linkage Linkage
+		merges  []Merge
+	}{
+		{Single, []Merge{{0, 1, 1, 2}, {2, 4, 2, 3}, {3, 5, 4, 4}}},
+		{Complete, []Merge{{0, 1, 1, 2}, {2, 4, 3, 3}, {3, 5, 7, 4}}},
+		{Average, []Merge{{0, 1, 1, 2}, {2, 4, 2.5, 3}, {3, 5, 17.0 / 3, 4}}},
+	}
+	for _, c := range cases {
+		dg := Agglomerative(matrix, c.linkage)
+		for i, m := range c.merges {
+			got := dg.Merges[i]
+			if got.A != m.A || got.B != m.B || got.Size != m.Size || !nearFloat(got.Height, m.Height) {
+				t.Errorf("Agglomerative failed. Expected merge %v with linkage %d, but got %v", m, c.linkage, got)
+			}
+		}
+	}
+	dg := Agglomerative(matrix, Single)
+	if labels := dg.CutK(2); labels[0] != 0 || labels[2] != 0 || labels[3] != 1 {
+		t.Errorf("CutK failed. Unexpected labels %v", labels)
+	}
+	if labels := dg.CutHeight(1.5); labels[0] != labels[1] || labels[1] == labels[2] || labels[2] == labels[3] {
+		t.Errorf("CutHeight failed. Unexpected labels %v", labels)
+	}
+	g := dg.Graph()
+	if g.LenNodes() != 7 || !strings.Contains(g.String(), "0 -> c4_h1") {
+		t.Errorf("Graph failed. Unexpected dot %s", g.String())
+	}
+}
+
+func nearFloat(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
+
+func TestAgglomerativeBlobs(t *testing.T) {
+	data := dataset.MakeBlobs(90, blobCenters, 1, 3)
+	points := make([]knn.Point, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+	}
+	matrix := knn.DistanceMatrix(points, knn.NewEuclideanDist(), 2)
+	for _, linkage := range []Linkage{Single, Complete, Average, Ward} {
+		labels := Agglomerative(matrix, linkage).CutK(3)
+		checkBlobs(t, "Agglomerative", data, func(p knn.Point) int {
+			for i := range points {
+				if &points[i][0] == &p[0] {
+					return labels[i]
+				}
+			}
+			return -1
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Package cluster groups points of data sets without labels
+package cluster
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/progress"
+)
+
+var (
+	ErrKNotValid         = errors.New("number of clusters is not in range [1, len(points)]")
+	ErrNotFitted         = errors.New("model is not fitted")
+	ErrDimensionMismatch = errors.New("point dimension doesn't match centroid dimension")
+)
+
+// squared euclidean distance
+func sqDist(a, b knn.Point) float64 {
+	if len(a) != len(b) {
+		panic(ErrDimensionMismatch)
+	}
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// index of nearest centroid and squared distance to it
+func nearestCentroid(centroids []knn.Point, p knn.Point) (int, float64) {
+	best, bestDist := 0, math.Inf(1)
+	for i, c := range centroids {
+		if d := sqDist(c, p); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best, bestDist
+}
+
+// k-means++ seeding, every new centroid is chosen with probability proportional to squared distance to nearest centroid
+func seedCentroids(points []knn.Point, k int, rnd *rand.Rand) []knn.Point {
+	if k <= 0 || k > len(points) {
+		panic(ErrKNotValid)
+	}
+	centroids := make([]knn.Point, 0, k)
+	centroids = append(centroids, clone(points[rnd.Intn(len(points))]))
+	dists := make([]float64, len(points))
+	for i, p := range points {
+		dists[i] = sqDist(centroids[0], p)
+	}
+	for len(centroids) < k {
+		total := 0.0
+		for _, d := range dists {
+			total += d
+		}
+		next := rnd.Intn(len(points))
+		if total > 0 {
+			r := rnd.Float64() * total
+			for i, d := range dists {
+				r -= d
+				if r < 0 {
+					next = i
+					break
+				}
+			}
+		}
+		c := clone(points[next])
+		centroids = append(centroids, c)
+		for i, p := range points {
+			if d := sqDist(c, p); d < dists[i] {
+				dists[i] = d
+			}
+		}
+	}
+	return centroids
+}
+
+func clone(p knn.Point) knn.Point {
+	return append(knn.Point{}, p...)
+}
+
+// K-means clustering by Lloyd's algorithm with k-means++ seeding
+type KMeans struct {
+	k         int
+	maxIter   int
+	tol       float64
+	seed      int64
+	centroids []knn.Point
+	inertia   float64
+	progress  progress.Func
+}
+
+// Create k-means with k clusters, it stops after maxIter iterations or when centroids move less than tol
+func NewKMeans(k, maxIter int, tol float64, seed int64) *KMeans {
+	if k <= 0 {
+		panic(ErrKNotValid)
+	}
+	return &KMeans{k: k, maxIter: maxIter, tol: tol, seed: seed}
+}
+
+// Set function receiving progress of Fit after every iteration, its total is maxIter
+func (km *KMeans) OnProgress(fn progress.Func) {
+	km.progress = fn
+}
+
+// Fit centroids to points and return the cluster of every point
+func (km *KMeans) Fit(points []knn.Point) []int {
+	labels, _ := km.FitContext(context.Background(), points)
+	return labels
+}
+
+// Fit until ctx is done, then centroids are not fitted and it returns the error of ctx
+func (km *KMeans) FitContext(ctx context.Context, points []knn.Point) ([]int, error) {
+	rnd := rand.New(rand.NewSource(km.seed))
+	km.centroids = seedCentroids(points, km.k, rnd)
+	labels := make([]int, len(points))
+	dim := len(points[0])
+	tracker := progress.NewTracker(km.maxIter, km.progress)
+	for iter := 0; iter < km.maxIter; iter++ {
+		if err := ctx.Err(); err != nil {
+			km.centroids = nil
+			return nil, err
+		}
+		for i, p := range points {
+			labels[i], _ = nearestCentroid(km.centroids, p)
+		}
+		sums := make([]knn.Point, km.k)
+		counts := make([]int, km.k)
+		for i := range sums {
+			sums[i] = knn.NewPoint(dim)
+		}
+		for i, p := range points {
+			counts[labels[i]]++
+			for d, v := range p {
+				sums[labels[i]][d] += v
+			}
+		}
+		shift := 0.0
+		for c := range sums {
+			if counts[c] == 0 {
+				// empty cluster keeps its centroid
+				continue
+			}
+			for d := range sums[c] {
+				sums[c][d] /= float64(counts[c])
+			}
+			shift += sqDist(sums[c], km.centroids[c])
+			km.centroids[c] = sums[c]
+		}
+		tracker.Step(1)
+		if shift <= km.tol*km.tol {
+			break
+		}
+	}
+	tracker.Finish()
+	km.inertia = 0
+	for i, p := range points {
+		var d float64
+		labels[i], d = nearestCentroid(km.centroids, p)
+		km.inertia += d
+	}
+	return labels, nil
+}
+
+// Cluster of point
+func (km *KMeans) Predict(point knn.Point) int {
+	if km.centroids == nil {
+		panic(ErrNotFitted)
+	}
+	c, _ := nearestCentroid(km.centroids, point)
+	return c
+}
+
+// Centroids of clusters
+func (km *KMeans) Centroids() []knn.Point {
+	return km.centroids
+}
+
+// Sum of squared distances of fitted points to their centroids
+func (km *KMeans) Inertia() float64 {
+	return km.inertia
+}
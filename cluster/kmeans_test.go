@@ -0,0 +1,97 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/progress"
+)
+
+var blobCenters = []knn.Point{{0, 0}, {10, 10}, {-10, 10}}
+
+// test that every blob is a single cluster
+func checkBlobs(t *testing.T, name string, data []knn.DataPoint, predict func(knn.Point) int) {
+	clusters := make(map[any]int)
+	for _, dp := range data {
+		c := predict(dp.Point())
+		if prev, ok := clusters[dp.Label()]; ok && prev != c {
+			t.Fatalf("%s failed. Blob %v split in clusters %d and %d", name, dp.Label(), prev, c)
+		}
+		clusters[dp.Label()] = c
+	}
+	if len(clusters) != 3 || clusters[0] == clusters[1] || clusters[1] == clusters[2] || clusters[0] == clusters[2] {
+		t.Fatalf("%s failed. Unexpected clusters %v", name, clusters)
+	}
+}
+
+func TestKMeans(t *testing.T) {
+	data := dataset.MakeBlobs(300, blobCenters, 1, 1)
+	points := make([]knn.Point, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+	}
+	km := NewKMeans(3, 100, 1e-6, 1)
+	labels := km.Fit(points)
+	for i, p := range points {
+		if km.Predict(p) != labels[i] {
+			t.Fatalf("Predict failed. Expected cluster %d, but got %d", labels[i], km.Predict(p))
+		}
+	}
+	checkBlobs(t, "KMeans", data, km.Predict)
+	if km.Inertia() > 2*300*2 {
+		t.Errorf("KMeans failed. Inertia %v is too big", km.Inertia())
+	}
+}
+
+func TestKMeansContext(t *testing.T) {
+	data := dataset.MakeBlobs(300, blobCenters, 1, 1)
+	points := make([]knn.Point, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+	}
+	km := NewKMeans(3, 100, 1e-6, 1)
+	var last progress.Report
+	km.OnProgress(func(r progress.Report) {
+		last = r
+	})
+	if _, err := km.FitContext(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+	if last.Done != 100 || last.Percent != 100 {
+		t.Errorf("OnProgress failed. Expected finished report, but got %v", last)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := km.FitContext(ctx, points); !errors.Is(err, context.Canceled) {
+		t.Errorf("FitContext failed. Expected %v, but got %v", context.Canceled, err)
+	}
+	if km.Centroids() != nil {
+		t.Errorf("FitContext failed. Expected no centroids after cancellation")
+	}
+}
+
+func TestMiniBatchKMeans(t *testing.T) {
+	data := dataset.MakeBlobs(3000, blobCenters, 1, 2)
+	points := make([]knn.Point, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+	}
+	mb := NewMiniBatchKMeans(3, 1)
+	mb.Fit(SliceBatches(points, 100))
+	checkBlobs(t, "MiniBatchKMeans", data, mb.Predict)
+	for _, c := range mb.Centroids() {
+		i, d := nearestCentroid(blobCenters, c)
+		if d > 0.1 {
+			t.Errorf("MiniBatchKMeans failed. Centroid %v is far from blob center %v", c, blobCenters[i])
+		}
+	}
+	defer func() {
+		if r := recover(); r != ErrBatchSizeNotValid {
+			t.Errorf("SliceBatches failed. Expected %v, but got %v", ErrBatchSizeNotValid, r)
+		}
+	}()
+	SliceBatches(points, 0)
+}
@@ -0,0 +1,103 @@
+package cluster
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var ErrBatchSizeNotValid = errors.New("batch size is lesser than one")
+
+// Iterator of batches of points
+type BatchIterator interface {
+	Next() ([]knn.Point, bool) //next batch, false when there are no more batches
+}
+
+type sliceBatches struct {
+	points []knn.Point
+	size   int
+	pos    int
+}
+
+// Iterator of consecutive batches of a slice of points, last batch can be smaller
+func SliceBatches(points []knn.Point, size int) BatchIterator {
+	if size <= 0 {
+		panic(ErrBatchSizeNotValid)
+	}
+	return &sliceBatches{points: points, size: size}
+}
+
+func (sb *sliceBatches) Next() ([]knn.Point, bool) {
+	if sb.pos >= len(sb.points) {
+		return nil, false
+	}
+	end := sb.pos + sb.size
+	if end > len(sb.points) {
+		end = len(sb.points)
+	}
+	batch := sb.points[sb.pos:end]
+	sb.pos = end
+	return batch, true
+}
+
+// Mini-batch k-means, centroids are updated from batches of points so data set doesn't need to fit in memory
+//
+// Every centroid moves towards its points with a learning rate of one over the number of points assigned to it
+type MiniBatchKMeans struct {
+	k         int
+	rnd       *rand.Rand
+	centroids []knn.Point
+	counts    []int
+}
+
+// Create mini-batch k-means with k clusters
+func NewMiniBatchKMeans(k int, seed int64) *MiniBatchKMeans {
+	if k <= 0 {
+		panic(ErrKNotValid)
+	}
+	return &MiniBatchKMeans{k: k, rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Update centroids with a batch of points, first batch seeds centroids by k-means++ so it needs at least k points
+func (mb *MiniBatchKMeans) PartialFit(batch []knn.Point) {
+	if mb.centroids == nil {
+		mb.centroids = seedCentroids(batch, mb.k, mb.rnd)
+		mb.counts = make([]int, mb.k)
+	}
+	labels := make([]int, len(batch))
+	for i, p := range batch {
+		labels[i], _ = nearestCentroid(mb.centroids, p)
+	}
+	for i, p := range batch {
+		c := labels[i]
+		mb.counts[c]++
+		rate := 1 / float64(mb.counts[c])
+		for d, v := range p {
+			mb.centroids[c][d] += rate * (v - mb.centroids[c][d])
+		}
+	}
+}
+
+// Update centroids with every batch of iterator
+func (mb *MiniBatchKMeans) Fit(batches BatchIterator) {
+	for batch, ok := batches.Next(); ok; batch, ok = batches.Next() {
+		if len(batch) > 0 {
+			mb.PartialFit(batch)
+		}
+	}
+}
+
+// Cluster of point
+func (mb *MiniBatchKMeans) Predict(point knn.Point) int {
+	if mb.centroids == nil {
+		panic(ErrNotFitted)
+	}
+	c, _ := nearestCentroid(mb.centroids, point)
+	return c
+}
+
+// Centroids of clusters
+func (mb *MiniBatchKMeans) Centroids() []knn.Point {
+	return mb.centroids
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stellviaproject/go-ia/nn/onnx"
+	nntrain "github.com/stellviaproject/go-ia/nn/train"
+	"github.com/stellviaproject/go-ia/serving"
+)
+
+// convert dtype of tensor files of the JSON protocol of package serving or of weights of training checkpoints and
+// import ONNX networks as networks of package layers saved with gob, values are rounded to dtype
+func convert(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", stderr)
+	in := fs.String("in", "", "JSON tensor file, checkpoint of training saved by Trainer.Checkpoint or ONNX network")
+	out := fs.String("out", "-", "converted file, - for stdout")
+	dtype := fs.String("dtype", "float16", "dtype of converted values: float16, float32 or float64")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"in": *in}); err != nil {
+		return err
+	}
+	if _, err := serving.ParseDType(*dtype); err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(*in)) {
+	case ".onnx":
+		return convertONNX(*in, *out, *dtype, stdout)
+	case ".json":
+		return convertTensor(*in, *out, *dtype, stdout)
+	}
+	return convertCheckpoint(*in, *out, *dtype, stdout)
+}
+
+func convertTensor(in, out, dtype string, stdout io.Writer) error {
+	content, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	var tensor serving.Tensor
+	if err := json.Unmarshal(content, &tensor); err != nil {
+		return err
+	}
+	if _, err := serving.DecodeTensor(&tensor); err != nil {
+		return err
+	}
+	// decoding with another dtype rounds the values of the tensor
+	tensor.DType = dtype
+	converted, err := serving.DecodeTensor(&tensor)
+	if err != nil {
+		return err
+	}
+	return writeFile(out, stdout, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(serving.EncodeTensor(converted))
+	})
+}
+
+// import ONNX network and round its parameters
+func convertONNX(in, out, dtype string, stdout io.Writer) error {
+	file, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	net, err := onnx.Read(file)
+	if err != nil {
+		return err
+	}
+	if err := roundParams(net.Params(), dtype); err != nil {
+		return err
+	}
+	return writeFile(out, stdout, func(w io.Writer) error {
+		return gob.NewEncoder(w).Encode(net)
+	})
+}
+
+// round values to dtype in place
+func roundParams(values []float64, dtype string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	converted, err := serving.DecodeTensor(&serving.Tensor{DType: dtype, Shape: []int{len(values)}, Data: values})
+	if err != nil {
+		return err
+	}
+	copy(values, serving.EncodeTensor(converted).Data)
+	return nil
+}
+
+// round parameters of network of checkpoint, the optimizer state is kept so training can continue
+func convertCheckpoint(in, out, dtype string, stdout io.Writer) error {
+	cp, err := nntrain.LoadCheckpoint(in)
+	if err != nil {
+		return err
+	}
+	if err := roundParams(cp.Params, dtype); err != nil {
+		return err
+	}
+	return writeFile(out, stdout, func(w io.Writer) error {
+		return gob.NewEncoder(w).Encode(cp)
+	})
+}
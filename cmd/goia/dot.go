@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+	"github.com/stellviaproject/go-ia/tree"
+)
+
+var ErrNoGraph = errors.New("estimator of model has no graph, only trees can be drawn")
+
+func dot(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("dot", stderr)
+	path := fs.String("model", "model.gob", "file of model saved by train")
+	out := fs.String("out", "-", "DOT file, - for stdout")
+	maxNodes := fs.Int("max-nodes", 0, "greatest number of drawn nodes, zero draws every node")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	p, err := loadModel(*path)
+	if err != nil {
+		return err
+	}
+	t, ok := p.Estimator().(*tree.Tree)
+	if !ok {
+		return ErrNoGraph
+	}
+	g := t.Graph()
+	return writeFile(*out, stdout, func(w io.Writer) error {
+		return g.WriteDot(w, graph.DotOptions{MaxNodes: *maxNodes})
+	})
+}
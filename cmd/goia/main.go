@@ -0,0 +1,145 @@
+// Command goia trains the built-in estimators from CSV files, predicts with saved models, converts tensor files,
+// checkpoint weights and ONNX networks and writes DOT graphs of trees, without writing Go
+//
+// Usage:
+//
+//	goia train -data train.csv -model tree -out model.gob
+//	goia predict -model model.gob -data test.csv
+//	goia convert -in weights.json -out weights16.json -dtype float16
+//	goia convert -in checkpoint.gob -out checkpoint16.gob -dtype float16
+//	goia convert -in model.onnx -out network.gob -dtype float32
+//	goia dot -model model.gob -out tree.dot
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/stellviaproject/go-ia/dataset"
+)
+
+var (
+	ErrUnknownCommand = errors.New("unknown command")
+	ErrMissingFlag    = errors.New("missing required flag")
+	ErrNoLabel        = errors.New("samples have no labels")
+)
+
+// -label of the last column of CSV files, dataset.NoLabel is used by samples without labels
+const lastColumn = dataset.NoLabel - 1
+
+// usage of -label shared by commands
+var labelUsage = fmt.Sprintf("column of labels, %d for the last column or %d if samples have no labels", lastColumn, dataset.NoLabel)
+
+const usage = `usage: goia <command> [flags]
+
+commands:
+  train    fit an estimator to a CSV file and save it
+  predict  predict labels of a CSV file with a saved model
+  convert  convert the dtype of a JSON tensor file or of checkpoint weights, or import an ONNX network
+  dot      write the DOT graph of a saved tree
+
+run goia <command> -h for the flags of a command
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "goia:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run command of args writing results to stdout and reports to stderr
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return ErrUnknownCommand
+	}
+	commands := map[string]func(args []string, stdout, stderr io.Writer) error{
+		"train":   train,
+		"predict": predict,
+		"convert": convert,
+		"dot":     dot,
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, args[0])
+	}
+	return command(args[1:], stdout, stderr)
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("goia "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func required(values map[string]string) error {
+	for name, value := range values {
+		if value == "" {
+			return fmt.Errorf("%w: -%s", ErrMissingFlag, name)
+		}
+	}
+	return nil
+}
+
+// flags of CSV files shared by commands
+type csvFlags struct {
+	path   string
+	header bool
+	comma  string
+	label  int
+}
+
+func (cf *csvFlags) register(fs *flag.FlagSet, label int) {
+	fs.StringVar(&cf.path, "data", "", "CSV file of samples")
+	fs.BoolVar(&cf.header, "header", false, "first record has the column names")
+	fs.StringVar(&cf.comma, "comma", ",", "field delimiter")
+	fs.IntVar(&cf.label, "label", label, labelUsage)
+}
+
+// load CSV file, see lastColumn
+func (cf *csvFlags) load() (*dataset.Dataset, error) {
+	content, err := os.ReadFile(cf.path)
+	if err != nil {
+		return nil, err
+	}
+	schema := dataset.Schema{Header: cf.header, Label: cf.label}
+	if cf.comma != "" {
+		schema.Comma = []rune(cf.comma)[0]
+	}
+	if cf.label == lastColumn {
+		reader := csv.NewReader(bytes.NewReader(content))
+		reader.Comma, reader.Comment = schema.Comma, '#'
+		first, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		schema.Label = len(first) - 1
+	}
+	return dataset.LoadCSV(bytes.NewReader(content), schema)
+}
+
+// write file with content of write, stdout if path is "-", the file is removed if write fails
+func writeFile(path string, stdout io.Writer, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
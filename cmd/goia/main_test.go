@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	nntrain "github.com/stellviaproject/go-ia/nn/train"
+	"github.com/stellviaproject/go-ia/serving"
+)
+
+func writeCSV(t *testing.T, dir string) string {
+	var b strings.Builder
+	b.WriteString("x,y,class\n")
+	for _, dp := range dataset.MakeBlobs(200, []knn.Point{{0, 0}, {6, 6}}, 1, 1) {
+		p := dp.Point()
+		fmt.Fprintf(&b, "%g,%g,c%v\n", p[0], p[1], dp.Label())
+	}
+	path := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTrainPredict(t *testing.T) {
+	dir := t.TempDir()
+	data := writeCSV(t, dir)
+	for _, name := range []string{"logistic", "tree", "gaussiannb"} {
+		model := filepath.Join(dir, name+".gob")
+		var stdout, stderr bytes.Buffer
+		if err := run([]string{"train", "-data", data, "-header", "-model", name, "-out", model}, &stdout, &stderr); err != nil {
+			t.Fatalf("train %v failed. %v %v", name, err, stderr.String())
+		}
+		if !strings.Contains(stderr.String(), "test accuracy: 1.0000") && !strings.Contains(stderr.String(), "test accuracy: 0.9") {
+			t.Errorf("train %v failed. Expected test accuracy report, but got %q", name, stderr.String())
+		}
+		stdout.Reset()
+		stderr.Reset()
+		if err := run([]string{"predict", "-data", data, "-header", "-label", "2", "-model", model}, &stdout, &stderr); err != nil {
+			t.Fatalf("predict %v failed. %v", name, err)
+		}
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		if len(lines) != 200 || lines[0] != "c0" || lines[1] != "c1" {
+			t.Errorf("predict %v failed. Expected 200 labels starting with c0 c1, but got %v", name, lines[:2])
+		}
+		if !strings.HasPrefix(stderr.String(), "accuracy: ") {
+			t.Errorf("predict %v failed. Expected accuracy report, but got %q", name, stderr.String())
+		}
+	}
+	wide := filepath.Join(dir, "wide.csv")
+	os.WriteFile(wide, []byte("1,2,3\n4,5,6\n"), 0o644)
+	var stdout bytes.Buffer
+	if err := run([]string{"predict", "-data", wide, "-model", filepath.Join(dir, "logistic.gob")}, &stdout, &stdout); err == nil {
+		t.Errorf("predict failed. Expected error of dimension mismatch, but got nil")
+	}
+	if err := run([]string{"train", "-data", data, "-header", "-label", "-1"}, &stdout, &stdout); !errors.Is(err, ErrNoLabel) {
+		t.Errorf("train failed. Expected %v, but got %v", ErrNoLabel, err)
+	}
+	if err := run([]string{"dot", "-model", filepath.Join(dir, "tree.gob")}, &stdout, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stdout.String(), "digraph") {
+		t.Errorf("dot failed. Expected DOT graph, but got %q", stdout.String())
+	}
+	if err := run([]string{"dot", "-model", filepath.Join(dir, "logistic.gob")}, &stdout, &stdout); err != ErrNoGraph {
+		t.Errorf("dot failed. Expected %v, but got %v", ErrNoGraph, err)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "weights.json")
+	content, _ := json.Marshal(serving.Tensor{DType: "float64", Shape: []int{3}, Data: []float64{0.1, 1, 65519}})
+	os.WriteFile(in, content, 0o644)
+	var stdout bytes.Buffer
+	if err := run([]string{"convert", "-in", in, "-dtype", "float16"}, &stdout, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	var out serving.Tensor
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.DType != "float16" || out.Data[0] == 0.1 || out.Data[1] != 1 {
+		t.Errorf("convert failed. Expected values rounded to float16, but got %v %v", out.DType, out.Data)
+	}
+	checkpoint := filepath.Join(dir, "checkpoint.gob")
+	(&nntrain.Checkpoint{Epoch: 2, Params: []float64{0.1, 1}, History: []float64{1, 0.5}}).Save(checkpoint)
+	converted := filepath.Join(dir, "checkpoint16.gob")
+	if err := run([]string{"convert", "-in", checkpoint, "-out", converted}, &stdout, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if cp, err := nntrain.LoadCheckpoint(converted); err != nil || cp.Epoch != 2 || cp.Params[0] == 0.1 || cp.Params[1] != 1 {
+		t.Errorf("convert failed. Expected checkpoint weights rounded to float16, but got %v %v", cp, err)
+	}
+	network := filepath.Join(dir, "network.gob")
+	if err := run([]string{"convert", "-in", "../../nn/onnx/testdata/mlp.onnx", "-out", network, "-dtype", "float64"}, &stdout, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	file, _ := os.Open(network)
+	defer file.Close()
+	net := &layers.Sequential{}
+	if err := gob.NewDecoder(file).Decode(net); err != nil {
+		t.Fatal(err)
+	}
+	if y := net.Forward([]float64{1, 2}); y[0] != 15.75 {
+		t.Errorf("convert failed. Expected output 15.75 of ONNX network, but got %v", y)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var stderr bytes.Buffer
+	if err := run([]string{"fit"}, &stderr, &stderr); !errors.Is(err, ErrUnknownCommand) {
+		t.Errorf("run failed. Expected %v, but got %v", ErrUnknownCommand, err)
+	}
+	if err := run([]string{"train"}, &stderr, &stderr); !errors.Is(err, ErrMissingFlag) {
+		t.Errorf("run failed. Expected %v, but got %v", ErrMissingFlag, err)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/metrics"
+	"github.com/stellviaproject/go-ia/pipeline"
+)
+
+func loadModel(path string) (*pipeline.Pipeline, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return pipeline.Load(file)
+}
+
+func predict(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("predict", stderr)
+	var data csvFlags
+	data.register(fs, dataset.NoLabel)
+	path := fs.String("model", "model.gob", "file of model saved by train")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"data": data.path, "model": *path}); err != nil {
+		return err
+	}
+	p, err := loadModel(*path)
+	if err != nil {
+		return err
+	}
+	ds, err := data.load()
+	if err != nil {
+		return err
+	}
+	predicted, err := predictAll(p, ds.Data)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	for _, label := range predicted {
+		fmt.Fprintln(w, label)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if data.label != dataset.NoLabel {
+		expected := make([]any, len(ds.Data))
+		for i, dp := range ds.Data {
+			expected[i] = dp.Label()
+		}
+		fmt.Fprintf(stderr, "accuracy: %.4f (%d samples)\n", metrics.Accuracy(expected, predicted), len(expected))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/stellviaproject/go-ia/bayes"
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linear"
+	"github.com/stellviaproject/go-ia/metrics"
+	"github.com/stellviaproject/go-ia/pipeline"
+	"github.com/stellviaproject/go-ia/tree"
+)
+
+// estimators that can be saved in pipelines
+var estimators = map[string]func(depth int, lambda float64) pipeline.Estimator{
+	"logistic": func(depth int, lambda float64) pipeline.Estimator {
+		return linear.NewLogisticRegression(linear.LogisticConfig{Penalty: linear.L2, Lambda: lambda})
+	},
+	"tree": func(depth int, lambda float64) pipeline.Estimator {
+		return tree.NewClassifier(tree.Config{MaxDepth: depth})
+	},
+	"gaussiannb": func(depth int, lambda float64) pipeline.Estimator {
+		return bayes.NewGaussianNB(1e-9)
+	},
+}
+
+var scalers = map[string]func() pipeline.Transformer{
+	"standard": func() pipeline.Transformer { return knn.NewStandardScaler() },
+	"minmax":   func() pipeline.Transformer { return knn.NewMinMaxScaler() },
+	"robust":   func() pipeline.Transformer { return knn.NewRobustScaler() },
+	"none":     nil,
+}
+
+func train(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("train", stderr)
+	var data csvFlags
+	data.register(fs, lastColumn)
+	name := fs.String("model", "logistic", "estimator: logistic, tree or gaussiannb")
+	scale := fs.String("scale", "standard", "scaler of features: standard, minmax, robust or none")
+	depth := fs.Int("depth", 0, "greatest depth of tree, zero is unlimited")
+	lambda := fs.Float64("lambda", 0, "L2 penalty of logistic regression")
+	test := fs.Float64("test", 0.2, "fraction of samples held out to report accuracy, zero uses every sample")
+	seed := fs.Int64("seed", 1, "seed of train and test split")
+	out := fs.String("out", "model.gob", "file of saved model, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"data": data.path}); err != nil {
+		return err
+	}
+	if data.label == dataset.NoLabel {
+		return fmt.Errorf("%w: train needs a column of labels", ErrNoLabel)
+	}
+	newEstimator, ok := estimators[*name]
+	if !ok {
+		return fmt.Errorf("estimator %q is not valid", *name)
+	}
+	newScaler, ok := scalers[*scale]
+	if !ok {
+		return fmt.Errorf("scaler %q is not valid", *scale)
+	}
+	ds, err := data.load()
+	if err != nil {
+		return err
+	}
+	fit, held := ds.Data, ds.Data[:0]
+	if *test > 0 {
+		fit, held = dataset.SplitTrainTest(ds.Data, *test, true, *seed)
+	}
+	var steps []pipeline.Transformer
+	if newScaler != nil {
+		steps = append(steps, newScaler())
+	}
+	p := pipeline.NewPipeline(newEstimator(*depth, *lambda), steps...)
+	p.Fit(fit)
+	expected := make([]any, len(fit))
+	for i, dp := range fit {
+		expected[i] = dp.Label()
+	}
+	predicted, err := predictAll(p, fit)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "train accuracy: %.4f (%d samples)\n", metrics.Accuracy(expected, predicted), len(fit))
+	if len(held) > 0 {
+		expected = expected[:0]
+		for _, dp := range held {
+			expected = append(expected, dp.Label())
+		}
+		if predicted, err = predictAll(p, held); err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "test accuracy: %.4f (%d samples)\n", metrics.Accuracy(expected, predicted), len(held))
+	}
+	return writeFile(*out, stdout, p.Save)
+}
+
+// predictions of data, a panic of the model like a mismatch of dimensions is returned as error
+func predictAll(p *pipeline.Pipeline, data []knn.DataPoint) (predicted []any, err error) {
+	i := 0
+	defer func() {
+		if r := recover(); r != nil {
+			predicted, err = nil, fmt.Errorf("sample %d: %v", i+1, r)
+		}
+	}()
+	predicted = make([]any, len(data))
+	for ; i < len(data); i++ {
+		predicted[i] = p.Predict(data[i].Point())
+	}
+	return predicted, nil
+}
@@ -0,0 +1,95 @@
+package datasets
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Url of binary version of CIFAR-10
+var CIFAR10URL = "https://www.cs.toronto.edu/~kriz/cifar-10-binary.tar.gz"
+
+var CIFAR10Classes = []string{"airplane", "automobile", "bird", "cat", "deer", "dog", "frog", "horse", "ship", "truck"}
+
+const cifarRecord = 1 + 3*32*32
+
+// Read records of CIFAR-10 binary format, a label byte followed by 32x32 pixels of red, green and blue planes,
+// in a tensor of shape (samples, 3, 32, 32)
+func ReadCIFAR(r io.Reader) (*graph.Tensor, []int, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(raw) == 0 || len(raw)%cifarRecord != 0 {
+		return nil, nil, ErrInvalidFormat
+	}
+	n := len(raw) / cifarRecord
+	t := graph.NewTensor(nil, graph.Float32, graph.NewShape(n, 3, 32, 32))
+	labels := make([]int, n)
+	for i := 0; i < n; i++ {
+		record := raw[i*cifarRecord : (i+1)*cifarRecord]
+		labels[i] = int(record[0])
+		fillTensor(t, i, record[1:])
+	}
+	return t, labels, nil
+}
+
+// Load training or test split of CIFAR-10, the archive is downloaded to cache directory dir the first time
+func LoadCIFAR10(dir string, train bool) (*Images, error) {
+	file, err := Download(CIFAR10URL, dir, "cifar-10-binary.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	// training split is data_batch_1 to data_batch_5 and test split is test_batch
+	batches := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Base(hdr.Name)
+		if matched, _ := path.Match("data_batch_*.bin", name); matched && train || name == "test_batch.bin" && !train {
+			if batches[name], err = io.ReadAll(tr); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(batches) == 0 {
+		return nil, ErrInvalidFormat
+	}
+	names := make([]string, 0, len(batches))
+	for name := range batches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	readers := make([]io.Reader, len(names))
+	for i, name := range names {
+		readers[i] = bytes.NewReader(batches[name])
+	}
+	t, labels, err := ReadCIFAR(io.MultiReader(readers...))
+	if err != nil {
+		return nil, err
+	}
+	return &Images{Tensor: t, Labels: labels, Classes: CIFAR10Classes}, nil
+}
@@ -0,0 +1,55 @@
+package datasets
+
+import (
+	"archive/tar"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// CIFAR batch of n records, label of record i is i and its pixels are i
+func cifarBatch(n, offset int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		buf.WriteByte(byte(offset + i))
+		buf.Write(bytes.Repeat([]byte{byte(offset + i)}, cifarRecord-1))
+	}
+	return buf.Bytes()
+}
+
+func TestLoadCIFAR10(t *testing.T) {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	files := map[string][]byte{
+		"cifar-10-batches-bin/data_batch_1.bin": cifarBatch(2, 0),
+		"cifar-10-batches-bin/data_batch_2.bin": cifarBatch(1, 2),
+		"cifar-10-batches-bin/test_batch.bin":   cifarBatch(1, 9),
+	}
+	for _, name := range []string{"cifar-10-batches-bin/data_batch_1.bin", "cifar-10-batches-bin/test_batch.bin", "cifar-10-batches-bin/data_batch_2.bin"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name]))})
+		tw.Write(files[name])
+	}
+	tw.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(gzipBytes(archive.Bytes()))
+	}))
+	defer server.Close()
+	defer func(url string) { CIFAR10URL = url }(CIFAR10URL)
+	CIFAR10URL = server.URL + "/cifar.tar.gz"
+	dir := t.TempDir()
+	train, err := LoadCIFAR10(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if train.Len() != 3 || train.Labels[2] != 2 || train.Tensor.GetF32At([]int{1, 2, 31, 31}) != 1.0/255 {
+		t.Errorf("LoadCIFAR10 failed. Unexpected training split %v", train.Labels)
+	}
+	test, err := LoadCIFAR10(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.Len() != 1 || test.Labels[0] != 9 || test.Classes[9] != "truck" {
+		t.Errorf("LoadCIFAR10 failed. Unexpected test split %v", test.Labels)
+	}
+}
@@ -0,0 +1,185 @@
+// Package datasets downloads and parses standard image data sets like MNIST, Fashion-MNIST and CIFAR-10
+package datasets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrInvalidFormat = errors.New("data set file format is not valid")
+	ErrDownload      = errors.New("data set download failed")
+	ErrChecksum      = errors.New("data set download does not match its checksum")
+)
+
+// Client used for downloads
+var Client = http.DefaultClient
+
+// Directory of cached downloads, the go-ia directory of user cache if dir is empty
+func CacheDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "go-ia"), nil
+}
+
+// Expected SHA-256 sums in hex of downloads by file name, files without a sum here are checked against
+// the sum recorded next to them by their first download
+var Checksums = map[string]string{}
+
+// Download url to file name of directory unless it is already there, it returns the path of file.
+// Cached files are verified against their SHA-256 sum and downloaded again if they do not match
+func Download(url, dir, name string) (string, error) {
+	dir, err := CacheDir(dir)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	if sum, err := expectedSum(path, name); err == nil && sum != "" {
+		if got, err := fileSum(path); err == nil && got == sum {
+			return path, nil
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	resp, err := Client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %s returned %s", ErrDownload, url, resp.Status)
+	}
+	// download to a temporary file so interrupted downloads are not cached
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if want, ok := Checksums[name]; ok && !strings.EqualFold(want, sum) {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("%w: %s has SHA-256 %s, expected %s", ErrChecksum, url, sum, want)
+	}
+	if err := os.WriteFile(path+".sha256", []byte(sum+"\n"), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return path, os.Rename(tmp.Name(), path)
+}
+
+// expected sum of cached file, empty if it is unknown
+func expectedSum(path, name string) (string, error) {
+	if sum, ok := Checksums[name]; ok {
+		return strings.ToLower(sum), nil
+	}
+	raw, err := os.ReadFile(path + ".sha256")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Images of a data set with their class labels
+type Images struct {
+	Tensor  *graph.Tensor //float32 values in [0, 1] of shape (samples, channels, height, width)
+	Labels  []int
+	Classes []string //names of classes by label
+}
+
+// Number of images
+func (im *Images) Len() int {
+	return len(im.Labels)
+}
+
+// Image i as a tensor of shape (channels, height, width)
+func (im *Images) Image(i int) *graph.Tensor {
+	shape := im.Tensor.Shape()
+	out := graph.NewTensor(nil, graph.Float32, shape[1:])
+	src := []int{i, 0, 0, 0}
+	dst := make([]int, 3)
+	for dst[0] = 0; dst[0] < shape[1]; dst[0]++ {
+		for dst[1] = 0; dst[1] < shape[2]; dst[1]++ {
+			for dst[2] = 0; dst[2] < shape[3]; dst[2]++ {
+				copy(src[1:], dst)
+				out.SetF32(dst, im.Tensor.GetF32At(src))
+			}
+		}
+	}
+	return out
+}
+
+// Data points of flattened images in channel, row, column order, labeled by class index
+func (im *Images) DataPoints() []knn.DataPoint {
+	shape := im.Tensor.Shape()
+	data := make([]knn.DataPoint, len(im.Labels))
+	index := make([]int, 4)
+	for n := range data {
+		p := knn.NewPoint(shape[1] * shape[2] * shape[3])
+		index[0] = n
+		k := 0
+		for index[1] = 0; index[1] < shape[1]; index[1]++ {
+			for index[2] = 0; index[2] < shape[2]; index[2]++ {
+				for index[3] = 0; index[3] < shape[3]; index[3]++ {
+					p[k] = float64(im.Tensor.GetF32At(index))
+					k++
+				}
+			}
+		}
+		data[n] = knn.NewDataPoint(im.Labels[n], p)
+	}
+	return data
+}
+
+// fill tensor of shape (samples, channels, height, width) from bytes in sample, channel, row, column order
+func fillTensor(t *graph.Tensor, n int, pixels []byte) {
+	shape := t.Shape()
+	strides := shape.Strides()
+	data := t.F32Slice()
+	k := 0
+	for c := 0; c < shape[1]; c++ {
+		for y := 0; y < shape[2]; y++ {
+			for x := 0; x < shape[3]; x++ {
+				data[n*strides[0]+c*strides[1]+y*strides[2]+x*strides[3]] = float32(pixels[k]) / 255
+				k++
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package datasets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestDownloadChecksum(t *testing.T) {
+	body := []byte("data set")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(body)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	path, err := Download(server.URL, dir, "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Download(server.URL, dir, "file"); err != nil || requests != 1 {
+		t.Errorf("Download failed. Expected 1 request with cache, but got %v (%v)", requests, err)
+	}
+	// corrupted cache is downloaded again
+	os.WriteFile(path, []byte("corrupted"), 0o644)
+	if _, err := Download(server.URL, dir, "file"); err != nil || requests != 2 {
+		t.Errorf("Download failed. Expected 2 requests with corrupted cache, but got %v (%v)", requests, err)
+	}
+	if raw, _ := os.ReadFile(path); string(raw) != string(body) {
+		t.Errorf("Download failed. Expected %q, but got %q", body, raw)
+	}
+	sum := sha256.Sum256(body)
+	defer func() { Checksums = map[string]string{} }()
+	Checksums = map[string]string{"pinned": hex.EncodeToString(sum[:]), "wrong": hex.EncodeToString(make([]byte, 32))}
+	if _, err := Download(server.URL, dir, "pinned"); err != nil {
+		t.Errorf("Download failed. Expected no error of pinned checksum, but got %v", err)
+	}
+	if _, err := Download(server.URL, dir, "wrong"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Download failed. Expected %v, but got %v", ErrChecksum, err)
+	}
+	if _, err := os.Stat(dir + "/wrong"); err == nil {
+		t.Errorf("Download failed. Expected file of wrong checksum to be removed")
+	}
+}
@@ -0,0 +1,132 @@
+package datasets
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Base urls of gzip files of MNIST and Fashion-MNIST
+var (
+	MNISTURL        = "https://ossci-datasets.s3.amazonaws.com/mnist/"
+	FashionMNISTURL = "https://raw.githubusercontent.com/zalandoresearch/fashion-mnist/master/data/fashion/"
+)
+
+var FashionClasses = []string{"T-shirt/top", "Trouser", "Pullover", "Dress", "Coat", "Sandal", "Shirt", "Sneaker", "Bag", "Ankle boot"}
+
+const (
+	idxImages = 0x00000803
+	idxLabels = 0x00000801
+	idxMaxDim = 1 << 12 //largest rows or cols of an image
+	idxMaxLen = 1 << 30 //largest number of bytes of images or labels
+)
+
+// Read images of IDX format in a tensor of shape (samples, 1, rows, cols)
+func ReadIDXImages(r io.Reader) (*graph.Tensor, error) {
+	var header [4]uint32
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, err
+	}
+	if header[0] != idxImages || header[1] == 0 || header[2] == 0 || header[3] == 0 {
+		return nil, ErrInvalidFormat
+	}
+	n, rows, cols := int64(header[1]), int64(header[2]), int64(header[3])
+	if rows > idxMaxDim || cols > idxMaxDim || n*rows*cols > idxMaxLen {
+		return nil, fmt.Errorf("%w: %d images of %dx%d pixels are too large", ErrInvalidFormat, n, rows, cols)
+	}
+	t := graph.NewTensor(nil, graph.Float32, graph.NewShape(int(n), 1, int(rows), int(cols)))
+	pixels := make([]byte, rows*cols)
+	for i := 0; i < int(n); i++ {
+		if _, err := io.ReadFull(r, pixels); err != nil {
+			return nil, err
+		}
+		fillTensor(t, i, pixels)
+	}
+	return t, nil
+}
+
+// Read labels of IDX format
+func ReadIDXLabels(r io.Reader) ([]int, error) {
+	var header [2]uint32
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, err
+	}
+	if header[0] != idxLabels {
+		return nil, ErrInvalidFormat
+	}
+	if header[1] > idxMaxLen {
+		return nil, fmt.Errorf("%w: %d labels are too many", ErrInvalidFormat, header[1])
+	}
+	raw := make([]byte, header[1])
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, err
+	}
+	labels := make([]int, len(raw))
+	for i, b := range raw {
+		labels[i] = int(b)
+	}
+	return labels, nil
+}
+
+func readGzip(path string, read func(r io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	return read(gz)
+}
+
+// download and parse IDX gzip files of training or test split
+func loadIDX(base, prefix, dir string, train bool, classes []string) (*Images, error) {
+	split := "t10k"
+	if train {
+		split = "train"
+	}
+	imagesName, labelsName := split+"-images-idx3-ubyte.gz", split+"-labels-idx1-ubyte.gz"
+	imagesPath, err := Download(base+imagesName, dir, prefix+imagesName)
+	if err != nil {
+		return nil, err
+	}
+	labelsPath, err := Download(base+labelsName, dir, prefix+labelsName)
+	if err != nil {
+		return nil, err
+	}
+	im := &Images{Classes: classes}
+	if err := readGzip(imagesPath, func(r io.Reader) (err error) {
+		im.Tensor, err = ReadIDXImages(r)
+		return
+	}); err != nil {
+		return nil, err
+	}
+	if err := readGzip(labelsPath, func(r io.Reader) (err error) {
+		im.Labels, err = ReadIDXLabels(r)
+		return
+	}); err != nil {
+		return nil, err
+	}
+	if im.Tensor.Shape()[0] != len(im.Labels) {
+		return nil, fmt.Errorf("%w: %d images and %d labels", ErrInvalidFormat, im.Tensor.Shape()[0], len(im.Labels))
+	}
+	return im, nil
+}
+
+// Load training or test split of MNIST handwritten digits, files are downloaded to cache directory dir
+// the first time, see CacheDir
+func LoadMNIST(dir string, train bool) (*Images, error) {
+	return loadIDX(MNISTURL, "mnist-", dir, train, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"})
+}
+
+// Load training or test split of Fashion-MNIST, files are downloaded to cache directory dir the first time
+func LoadFashionMNIST(dir string, train bool) (*Images, error) {
+	return loadIDX(FashionMNISTURL, "fashion-", dir, train, FashionClasses)
+}
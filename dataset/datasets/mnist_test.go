@@ -0,0 +1,96 @@
+package datasets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipBytes(data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	gz.Close()
+	return buf.Bytes()
+}
+
+// IDX files of n images of 2x3 pixels, pixel j of image i is 10*i + j and label of image i is i
+func idxFiles(n int) ([]byte, []byte) {
+	var images, labels bytes.Buffer
+	binary.Write(&images, binary.BigEndian, [4]uint32{idxImages, uint32(n), 2, 3})
+	binary.Write(&labels, binary.BigEndian, [2]uint32{idxLabels, uint32(n)})
+	for i := 0; i < n; i++ {
+		for j := 0; j < 6; j++ {
+			images.WriteByte(byte(10*i + j))
+		}
+		labels.WriteByte(byte(i))
+	}
+	return images.Bytes(), labels.Bytes()
+}
+
+func TestLoadMNIST(t *testing.T) {
+	images, labels := idxFiles(4)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case strings.HasSuffix(r.URL.Path, "train-images-idx3-ubyte.gz"):
+			w.Write(gzipBytes(images))
+		case strings.HasSuffix(r.URL.Path, "train-labels-idx1-ubyte.gz"):
+			w.Write(gzipBytes(labels))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer func(url string) { MNISTURL = url }(MNISTURL)
+	MNISTURL = server.URL + "/"
+	dir := t.TempDir()
+	im, err := LoadMNIST(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shape := im.Tensor.Shape(); im.Len() != 4 || shape[1] != 1 || shape[2] != 2 || shape[3] != 3 {
+		t.Fatalf("LoadMNIST failed. Expected 4 images of shape (1, 2, 3), but got %v", shape)
+	}
+	if v := im.Tensor.GetF32At([]int{2, 0, 1, 2}); v != 25.0/255 {
+		t.Errorf("LoadMNIST failed. Expected pixel %v, but got %v", 25.0/255, v)
+	}
+	data := im.DataPoints()
+	if data[3].Label() != 3 || data[3].Point()[4] != float64(float32(34)/255) {
+		t.Errorf("DataPoints failed. Unexpected data point %v", data[3])
+	}
+	if v := im.Image(1).GetF32At([]int{0, 1, 0}); v != 13.0/255 {
+		t.Errorf("Image failed. Expected pixel %v, but got %v", 13.0/255, v)
+	}
+	// files are cached
+	if _, err := LoadMNIST(dir, true); err != nil || requests != 2 {
+		t.Errorf("LoadMNIST failed. Expected 2 requests with cache, but got %v (%v)", requests, err)
+	}
+	if _, err := LoadMNIST(dir, false); err == nil {
+		t.Errorf("LoadMNIST failed. Expected error of missing test split")
+	}
+}
+
+func TestReadIDX(t *testing.T) {
+	images, _ := idxFiles(2)
+	images[3] = 0x01 //magic number of labels
+	if _, err := ReadIDXImages(bytes.NewReader(images)); err != ErrInvalidFormat {
+		t.Errorf("ReadIDXImages failed. Expected %v, but got %v", ErrInvalidFormat, err)
+	}
+	var huge bytes.Buffer
+	binary.Write(&huge, binary.BigEndian, [4]uint32{idxImages, 1 << 31, 1 << 15, 1 << 15})
+	if _, err := ReadIDXImages(&huge); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ReadIDXImages failed. Expected %v, but got %v", ErrInvalidFormat, err)
+	}
+	huge.Reset()
+	binary.Write(&huge, binary.BigEndian, [2]uint32{idxLabels, 1<<32 - 1})
+	if _, err := ReadIDXLabels(&huge); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ReadIDXLabels failed. Expected %v, but got %v", ErrInvalidFormat, err)
+	}
+}
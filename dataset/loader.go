@@ -0,0 +1,470 @@
+package dataset
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrInvalidColumn   = errors.New("column is not in the records")
+	ErrInvalidValue    = errors.New("value is not valid")
+	ErrInvalidARFF     = errors.New("arff header is not valid")
+	ErrEmptyDataset    = errors.New("data set has no records")
+	ErrUnsupportedType = errors.New("attribute type is not supported")
+	ErrLabelNotClass   = errors.New("label is not a class index")
+)
+
+// Label column of unlabeled data
+const NoLabel = -1
+
+// Label of data points whose label value is missing, "?" or empty, unless the label is numeric and it is NaN
+type Missing struct{}
+
+func (Missing) String() string {
+	return "?"
+}
+
+// Encoding of categorical columns
+type Encoding int
+
+const (
+	Ordinal Encoding = iota //category is replaced by its index
+	OneHot                  //category is replaced by one indicator feature for every category
+)
+
+// Schema of delimited records
+type Schema struct {
+	Comma        rune     //field delimiter, comma if zero
+	Header       bool     //first record has the column names
+	Features     []int    //feature columns, every column but the label if empty
+	Label        int      //label column or NoLabel
+	Categorical  []int    //feature columns with categories instead of numbers
+	Encoding     Encoding //encoding of categorical columns
+	NumericLabel bool     //label is parsed as float64 instead of kept as string
+}
+
+// Data set loaded from records
+type Dataset struct {
+	Features   []string            //names of features, one hot features are named column=category
+	Label      string              //name of label column
+	Categories map[string][]string //categories of every categorical column in order of its index
+	Data       []knn.DataPoint     //loaded data points
+}
+
+// Features of data set as a Float64 tensor of shape (samples, features) and the label of every sample
+func (ds *Dataset) Tensor() (*graph.Tensor, []any) {
+	if len(ds.Data) == 0 {
+		panic(ErrEmptyDataset)
+	}
+	n, dim := len(ds.Data), len(ds.Features)
+	tensor := graph.NewTensor(nil, graph.Float64, graph.NewShape(n, dim))
+	labels := make([]any, n)
+	index := make([]int, 2)
+	for i, dp := range ds.Data {
+		index[0] = i
+		for j, v := range dp.Point() {
+			index[1] = j
+			tensor.SetF64(index, v)
+		}
+		labels[i] = dp.Label()
+	}
+	return tensor, labels
+}
+
+// Points of data set without labels
+func (ds *Dataset) Points() []knn.Point {
+	points := make([]knn.Point, len(ds.Data))
+	for i, dp := range ds.Data {
+		points[i] = dp.Point()
+	}
+	return points
+}
+
+// Data set of networks of package nn with the points as inputs and their class as the only target, like the
+// targets of train.CrossEntropy. Labels must be class indices of type int, like labels of MakeBlobs
+func ClassDataset(points []knn.DataPoint) data.Dataset {
+	inputs := make([][]float64, len(points))
+	targets := make([][]float64, len(points))
+	for i, dp := range points {
+		c, ok := dp.Label().(int)
+		if !ok || c < 0 {
+			panic(ErrLabelNotClass)
+		}
+		inputs[i] = dp.Point()
+		targets[i] = []float64{float64(c)}
+	}
+	return data.NewSliceDataset(inputs, targets)
+}
+
+// column of records being encoded
+type column struct {
+	name        string
+	categorical bool
+	fixed       bool //categories are declared and new ones are not valid
+	categories  []string
+	index       map[string]int
+}
+
+func newColumn(name string, categorical bool, categories []string) *column {
+	col := &column{
+		name:        name,
+		categorical: categorical,
+		index:       make(map[string]int),
+	}
+	for _, c := range categories {
+		col.category(c)
+	}
+	col.fixed = categories != nil
+	return col
+}
+
+// index of category, unknown categories are appended when column is not fixed
+func (col *column) category(value string) (int, bool) {
+	if id, ok := col.index[value]; ok {
+		return id, true
+	}
+	if col.fixed {
+		return 0, false
+	}
+	id := len(col.categories)
+	col.categories = append(col.categories, value)
+	col.index[value] = id
+	return id, true
+}
+
+// value is missing and it is loaded as NaN
+func isMissing(value string) bool {
+	return value == "" || value == "?"
+}
+
+// encoder of records in data points
+type encoder struct {
+	features     []*column
+	label        *column
+	encoding     Encoding
+	numericLabel bool
+}
+
+func (enc *encoder) parse(col *column, value string, line int) (float64, error) {
+	value = strings.TrimSpace(value)
+	if isMissing(value) {
+		return math.NaN(), nil
+	}
+	if col.categorical {
+		id, ok := col.category(value)
+		if !ok {
+			return 0, fmt.Errorf("%w: category %q of column %s at line %d", ErrInvalidValue, value, col.name, line)
+		}
+		return float64(id), nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q of column %s at line %d", ErrInvalidValue, value, col.name, line)
+	}
+	return v, nil
+}
+
+// encode records, fields[i] are the feature values of record i and labels[i] its label value
+func (enc *encoder) encode(fields [][]string, labels []string, lines []int) (*Dataset, error) {
+	if len(fields) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	raw := make([][]float64, len(fields))
+	for i, record := range fields {
+		raw[i] = make([]float64, len(record))
+		for j, value := range record {
+			v, err := enc.parse(enc.features[j], value, lines[i])
+			if err != nil {
+				return nil, err
+			}
+			raw[i][j] = v
+		}
+	}
+	ds := &Dataset{Categories: make(map[string][]string)}
+	for _, col := range enc.features {
+		if !col.categorical {
+			ds.Features = append(ds.Features, col.name)
+			continue
+		}
+		ds.Categories[col.name] = col.categories
+		if enc.encoding == OneHot {
+			for _, c := range col.categories {
+				ds.Features = append(ds.Features, col.name+"="+c)
+			}
+		} else {
+			ds.Features = append(ds.Features, col.name)
+		}
+	}
+	ds.Data = make([]knn.DataPoint, len(raw))
+	for i, values := range raw {
+		p := make(knn.Point, 0, len(ds.Features))
+		for j, v := range values {
+			col := enc.features[j]
+			if !col.categorical || enc.encoding != OneHot {
+				p = append(p, v)
+				continue
+			}
+			for c := range col.categories {
+				switch {
+				case math.IsNaN(v):
+					p = append(p, math.NaN())
+				case int(v) == c:
+					p = append(p, 1)
+				default:
+					p = append(p, 0)
+				}
+			}
+		}
+		var label any
+		if enc.label != nil {
+			value := strings.TrimSpace(labels[i])
+			if enc.numericLabel {
+				v, err := enc.parse(enc.label, value, lines[i])
+				if err != nil {
+					return nil, err
+				}
+				label = v
+			} else if isMissing(value) {
+				label = Missing{}
+			} else {
+				if enc.label.fixed {
+					if _, ok := enc.label.category(value); !ok {
+						return nil, fmt.Errorf("%w: category %q of column %s at line %d", ErrInvalidValue, value, enc.label.name, lines[i])
+					}
+				}
+				label = value
+			}
+		}
+		ds.Data[i] = knn.NewDataPoint(label, p)
+	}
+	if enc.label != nil {
+		ds.Label = enc.label.name
+		if enc.label.fixed {
+			ds.Categories[enc.label.name] = enc.label.categories
+		}
+	}
+	return ds, nil
+}
+
+// Load delimited records with the given schema
+//
+// Missing values, empty or "?", are loaded as NaN. Labels are strings unless schema has a numeric label,
+// missing ones are Missing.
+func LoadCSV(r io.Reader, schema Schema) (*Dataset, error) {
+	reader := csv.NewReader(r)
+	if schema.Comma != 0 {
+		reader.Comma = schema.Comma
+	}
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if schema.Header && len(records) > 0 {
+		return loadRecords(records[0], records[1:], 2, schema)
+	}
+	if len(records) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	names := make([]string, len(records[0]))
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+	}
+	return loadRecords(names, records, 1, schema)
+}
+
+func loadRecords(names []string, records [][]string, first int, schema Schema) (*Dataset, error) {
+	if schema.Label >= len(names) || schema.Label < NoLabel {
+		return nil, fmt.Errorf("%w: label %d", ErrInvalidColumn, schema.Label)
+	}
+	features := schema.Features
+	if len(features) == 0 {
+		for i := range names {
+			if i != schema.Label {
+				features = append(features, i)
+			}
+		}
+	}
+	categorical := make(map[int]bool)
+	for _, c := range schema.Categorical {
+		categorical[c] = true
+	}
+	enc := &encoder{
+		features:     make([]*column, len(features)),
+		encoding:     schema.Encoding,
+		numericLabel: schema.NumericLabel,
+	}
+	for i, c := range features {
+		if c < 0 || c >= len(names) {
+			return nil, fmt.Errorf("%w: feature %d", ErrInvalidColumn, c)
+		}
+		enc.features[i] = newColumn(names[c], categorical[c], nil)
+	}
+	if schema.Label != NoLabel {
+		enc.label = newColumn(names[schema.Label], false, nil)
+	}
+	fields := make([][]string, len(records))
+	labels := make([]string, len(records))
+	lines := make([]int, len(records))
+	for i, record := range records {
+		lines[i] = first + i
+		fields[i] = make([]string, len(features))
+		for j, c := range features {
+			if c >= len(record) {
+				return nil, fmt.Errorf("%w: feature %d at line %d", ErrInvalidColumn, c, lines[i])
+			}
+			fields[i][j] = record[c]
+		}
+		if schema.Label != NoLabel {
+			if schema.Label >= len(record) {
+				return nil, fmt.Errorf("%w: label %d at line %d", ErrInvalidColumn, schema.Label, lines[i])
+			}
+			labels[i] = record[schema.Label]
+		}
+	}
+	return enc.encode(fields, labels, lines)
+}
+
+// Load an ARFF file
+//
+// Numeric, real and integer attributes are numbers and nominal attributes are categories encoded with the given
+// encoding. The label is the attribute with the given name, the last attribute if label is empty. Nominal labels
+// are strings, or Missing if they are "?", and numeric labels are float64.
+func LoadARFF(r io.Reader, label string, encoding Encoding) (*Dataset, error) {
+	scanner := bufio.NewScanner(r)
+	names := make([]string, 0, 10)
+	nominal := make([][]string, 0, 10)
+	line := 0
+	data := false
+	for !data && scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '%' {
+			continue
+		}
+		lower := strings.ToLower(text)
+		switch {
+		case strings.HasPrefix(lower, "@relation"):
+		case strings.HasPrefix(lower, "@attribute"):
+			name, typ, err := parseAttribute(text[len("@attribute"):])
+			if err != nil {
+				return nil, fmt.Errorf("%w at line %d", err, line)
+			}
+			names = append(names, name)
+			nominal = append(nominal, typ)
+		case strings.HasPrefix(lower, "@data"):
+			data = true
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at line %d", ErrInvalidARFF, text, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !data || len(names) == 0 {
+		return nil, ErrInvalidARFF
+	}
+	labelCol := len(names) - 1
+	if label != "" {
+		labelCol = -1
+		for i, name := range names {
+			if name == label {
+				labelCol = i
+			}
+		}
+		if labelCol < 0 {
+			return nil, fmt.Errorf("%w: label %s", ErrInvalidColumn, label)
+		}
+	}
+	enc := &encoder{encoding: encoding}
+	for i, name := range names {
+		if i == labelCol {
+			enc.label = newColumn(name, nominal[i] != nil, nominal[i])
+			enc.numericLabel = nominal[i] == nil
+			continue
+		}
+		enc.features = append(enc.features, newColumn(name, nominal[i] != nil, nominal[i]))
+	}
+	fields := make([][]string, 0, 100)
+	labels := make([]string, 0, 100)
+	lines := make([]int, 0, 100)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '%' {
+			continue
+		}
+		if text[0] == '{' {
+			return nil, fmt.Errorf("%w: sparse data at line %d", ErrUnsupportedType, line)
+		}
+		values := strings.Split(text, ",")
+		if len(values) != len(names) {
+			return nil, fmt.Errorf("%w: expected %d values at line %d", ErrInvalidValue, len(names), line)
+		}
+		record := make([]string, 0, len(values)-1)
+		for i, v := range values {
+			v = unquote(strings.TrimSpace(v))
+			if i == labelCol {
+				labels = append(labels, v)
+			} else {
+				record = append(record, v)
+			}
+		}
+		fields = append(fields, record)
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return enc.encode(fields, labels, lines)
+}
+
+// parse attribute declaration, it returns categories of nominal attributes or nil for numeric attributes
+func parseAttribute(decl string) (string, []string, error) {
+	decl = strings.TrimSpace(decl)
+	var name string
+	if decl != "" && (decl[0] == '\'' || decl[0] == '"') {
+		end := strings.IndexByte(decl[1:], decl[0])
+		if end < 0 {
+			return "", nil, ErrInvalidARFF
+		}
+		name, decl = decl[1:end+1], decl[end+2:]
+	} else {
+		fields := strings.Fields(decl)
+		if len(fields) < 2 {
+			return "", nil, ErrInvalidARFF
+		}
+		name, decl = fields[0], decl[len(fields[0]):]
+	}
+	typ := strings.TrimSpace(decl)
+	if strings.HasPrefix(typ, "{") && strings.HasSuffix(typ, "}") {
+		values := strings.Split(typ[1:len(typ)-1], ",")
+		categories := make([]string, len(values))
+		for i, v := range values {
+			categories[i] = unquote(strings.TrimSpace(v))
+		}
+		return name, categories, nil
+	}
+	switch strings.ToLower(typ) {
+	case "numeric", "real", "integer":
+		return name, nil, nil
+	}
+	return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedType, typ)
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
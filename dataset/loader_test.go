@@ -0,0 +1,117 @@
+package dataset
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+const irisCSV = `sepal,petal,color,class
+5.1,1.4,red,setosa
+7.0,4.7,blue,versicolor
+6.3,?,red,virginica
+`
+
+func TestLoadCSV(t *testing.T) {
+	ds, err := LoadCSV(strings.NewReader(irisCSV), Schema{Header: true, Label: 3, Categorical: []int{2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.Data) != 3 || ds.Label != "class" || strings.Join(ds.Features, ",") != "sepal,petal,color" {
+		t.Fatalf("LoadCSV failed. Unexpected data set %v", ds)
+	}
+	p := ds.Data[1].Point()
+	if p[0] != 7 || p[1] != 4.7 || p[2] != 1 || ds.Data[1].Label() != "versicolor" {
+		t.Errorf("LoadCSV failed. Unexpected point %v with label %v", p, ds.Data[1].Label())
+	}
+	if !math.IsNaN(ds.Data[2].Point()[1]) {
+		t.Errorf("LoadCSV failed. Expected NaN for missing value, but got %v", ds.Data[2].Point()[1])
+	}
+	tensor, labels := ds.Tensor()
+	if tensor.GetF64At([]int{1, 0}) != 7 || labels[2] != "virginica" {
+		t.Errorf("Tensor failed. Unexpected tensor %v", tensor)
+	}
+}
+
+func TestLoadCSVOneHot(t *testing.T) {
+	schema := Schema{Header: true, Features: []int{2, 0}, Label: NoLabel, Categorical: []int{2}, Encoding: OneHot}
+	ds, err := LoadCSV(strings.NewReader(irisCSV), schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ds.Features, ",") != "color=red,color=blue,sepal" {
+		t.Fatalf("LoadCSV failed. Unexpected features %v", ds.Features)
+	}
+	p := ds.Data[1].Point()
+	if p[0] != 0 || p[1] != 1 || p[2] != 7 || ds.Data[1].Label() != nil {
+		t.Errorf("LoadCSV failed. Unexpected point %v", p)
+	}
+	if _, err := LoadCSV(strings.NewReader(irisCSV), Schema{Header: true, Label: 3}); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("LoadCSV failed. Expected ErrInvalidValue for categorical column, but got %v", err)
+	}
+}
+
+const weatherARFF = `% weather data
+@relation weather
+@attribute outlook {sunny, overcast, rainy}
+@attribute 'temp' numeric
+@attribute play {yes, no}
+
+@data
+sunny,85,no
+overcast,83,yes
+rainy,?,yes
+`
+
+func TestLoadARFF(t *testing.T) {
+	ds, err := LoadARFF(strings.NewReader(weatherARFF), "", Ordinal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.Data) != 3 || ds.Label != "play" || len(ds.Categories["outlook"]) != 3 {
+		t.Fatalf("LoadARFF failed. Unexpected data set %v", ds)
+	}
+	p := ds.Data[1].Point()
+	if p[0] != 1 || p[1] != 83 || ds.Data[1].Label() != "yes" {
+		t.Errorf("LoadARFF failed. Unexpected point %v with label %v", p, ds.Data[1].Label())
+	}
+	ds, err = LoadARFF(strings.NewReader(weatherARFF), "temp", OneHot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.Features) != 5 || ds.Data[0].Label() != 85.0 {
+		t.Errorf("LoadARFF failed. Unexpected features %v and label %v", ds.Features, ds.Data[0].Label())
+	}
+	missing := strings.Replace(weatherARFF, "overcast,83,yes", "overcast,83,?", 1)
+	ds, err = LoadARFF(strings.NewReader(missing), "", Ordinal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Data[1].Label() != (Missing{}) || ds.Data[2].Label() != "yes" {
+		t.Errorf("LoadARFF failed. Expected missing label, but got %v", ds.Data[1].Label())
+	}
+	bad := strings.Replace(weatherARFF, "rainy,?,yes", "windy,70,yes", 1)
+	if _, err := LoadARFF(strings.NewReader(bad), "", Ordinal); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("LoadARFF failed. Expected ErrInvalidValue for unknown category, but got %v", err)
+	}
+}
+
+func TestClassDataset(t *testing.T) {
+	ds := ClassDataset(MakeBlobs(6, []knn.Point{{0, 0}, {5, 5}}, 0.1, 1))
+	item, err := ds.GetItem(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Len() != 6 || item.Target.GetF64At([]int{0}) != 1 || item.Input.GetF64At([]int{1}) < 4 {
+		t.Errorf("ClassDataset failed. Unexpected item %v", item)
+	}
+	defer func() {
+		if r := recover(); r != ErrLabelNotClass {
+			t.Errorf("ClassDataset failed. Expected %v, but got %v", ErrLabelNotClass, r)
+		}
+	}()
+	ClassDataset([]knn.DataPoint{knn.NewDataPoint("a", knn.Point{0})})
+}
@@ -0,0 +1,145 @@
+package dataset
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var ErrNeighborsNotValid = errors.New("number of neighbors is lesser than one")
+
+// Balanced weight of every label, n / (labels * count of label), so every label has the same total weight
+func ClassWeights(data []knn.DataPoint) map[any]float64 {
+	groups := groupByLabel(data)
+	weights := make(map[any]float64, len(groups))
+	for _, group := range groups {
+		weights[group[0].Label()] = float64(len(data)) / float64(len(groups)*len(group))
+	}
+	return weights
+}
+
+// Weight of every data point by the weight of its label, 1 for labels missing in weights
+func SampleWeights(data []knn.DataPoint, weights map[any]float64) []float64 {
+	sample := make([]float64, len(data))
+	for i, dp := range data {
+		if w, ok := weights[dp.Label()]; ok {
+			sample[i] = w
+		} else {
+			sample[i] = 1
+		}
+	}
+	return sample
+}
+
+func greatestGroup(groups [][]knn.DataPoint) int {
+	n := 0
+	for _, group := range groups {
+		n = maxInt(n, len(group))
+	}
+	return n
+}
+
+// shuffled copy of resampled data points
+func mix(data []knn.DataPoint, rnd *rand.Rand) []knn.DataPoint {
+	rnd.Shuffle(len(data), func(i, j int) {
+		data[i], data[j] = data[j], data[i]
+	})
+	return data
+}
+
+// Duplicate random data points of every label until it has as many data points as the greatest label
+//
+// Data is not modified and the result is shuffled with the given seed
+func RandomOverSample(data []knn.DataPoint, seed int64) []knn.DataPoint {
+	rnd := rand.New(rand.NewSource(seed))
+	groups := groupByLabel(data)
+	n := greatestGroup(groups)
+	out := make([]knn.DataPoint, 0, n*len(groups))
+	for _, group := range groups {
+		out = append(out, group...)
+		for i := len(group); i < n; i++ {
+			out = append(out, group[rnd.Intn(len(group))])
+		}
+	}
+	return mix(out, rnd)
+}
+
+// Keep random data points of every label until it has as many data points as the smallest label
+//
+// Data is not modified and the result is shuffled with the given seed
+func RandomUnderSample(data []knn.DataPoint, seed int64) []knn.DataPoint {
+	rnd := rand.New(rand.NewSource(seed))
+	groups := groupByLabel(data)
+	n := len(data)
+	for _, group := range groups {
+		if len(group) < n {
+			n = len(group)
+		}
+	}
+	out := make([]knn.DataPoint, 0, n*len(groups))
+	for _, group := range groups {
+		for _, i := range rnd.Perm(len(group))[:n] {
+			out = append(out, group[i])
+		}
+	}
+	return mix(out, rnd)
+}
+
+// Synthesize data points of every label until it has as many data points as the greatest label (SMOTE)
+//
+// Every synthetic point is a random interpolation between a data point and one of its k nearest data points of
+// the same label, found with a kd-tree. Labels with one data point are duplicated. Data is not modified and the
+// result is shuffled with the given seed.
+func SMOTE(data []knn.DataPoint, k int, seed int64) []knn.DataPoint {
+	if k < 1 {
+		panic(ErrNeighborsNotValid)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	groups := groupByLabel(data)
+	n := greatestGroup(groups)
+	out := make([]knn.DataPoint, 0, n*len(groups))
+	for _, group := range groups {
+		out = append(out, group...)
+		if len(group) == n {
+			continue
+		}
+		if len(group) == 1 {
+			for i := 1; i < n; i++ {
+				out = append(out, group[0])
+			}
+			continue
+		}
+		neighbors := k + 1 //the point itself is its nearest neighbor
+		if neighbors > len(group) {
+			neighbors = len(group)
+		}
+		index := knn.NewKNN(neighbors, knn.NewEuclideanDist(), knn.NewMultiClassSelector(), group,
+			knn.WithIndex(knn.NewKDTree()))
+		nearest := make([][]knn.Point, len(group))
+		for i := len(group); i < n; i++ {
+			j := rnd.Intn(len(group))
+			base := group[j].Point()
+			if nearest[j] == nil {
+				for _, dd := range index.KNeighbors(base, neighbors) {
+					if dd.Dist() > 0 {
+						nearest[j] = append(nearest[j], dd.DataPoint().Point())
+					}
+				}
+			}
+			if len(nearest[j]) == 0 {
+				// every neighbor is a duplicate of base
+				out = append(out, group[j])
+				continue
+			}
+			other := nearest[j][rnd.Intn(len(nearest[j]))]
+			gap := rnd.Float64()
+			point := knn.NewPoint(len(base))
+			for d := range point {
+				point[d] = base[d] + gap*(other[d]-base[d])
+			}
+			out = append(out, knn.NewDataPoint(group[j].Label(), point))
+		}
+	}
+	return mix(out, rnd)
+}
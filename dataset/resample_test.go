@@ -0,0 +1,55 @@
+package dataset
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func TestClassWeights(t *testing.T) {
+	data := imbalanced()
+	counts := countLabels(data)
+	weights := ClassWeights(data)
+	for label, count := range counts {
+		if total := weights[label] * float64(count); math.Abs(total-float64(len(data))/float64(len(counts))) > 1e-9 {
+			t.Errorf("ClassWeights failed. Expected the same total weight by label, but got %v for %v", total, label)
+		}
+	}
+	sample := SampleWeights(data, weights)
+	if sample[0] != weights[data[0].Label()] {
+		t.Errorf("SampleWeights failed. Expected %v, but got %v", weights[data[0].Label()], sample[0])
+	}
+}
+
+func TestRandomSample(t *testing.T) {
+	data := imbalanced()
+	for label, c := range countLabels(RandomOverSample(data, 1)) {
+		if c != 90 {
+			t.Errorf("RandomOverSample failed. Expected 90 data points of %v, but got %v", label, c)
+		}
+	}
+	for label, c := range countLabels(RandomUnderSample(data, 1)) {
+		if c != 10 {
+			t.Errorf("RandomUnderSample failed. Expected 10 data points of %v, but got %v", label, c)
+		}
+	}
+}
+
+func TestSMOTE(t *testing.T) {
+	data := append(MakeBlobs(90, []knn.Point{{0, 0}}, 1, 1), MakeBlobs(10, []knn.Point{{10, 10}}, 0.5, 2)...)
+	for i := 90; i < len(data); i++ {
+		data[i] = knn.NewDataPoint(1, data[i].Point())
+	}
+	out := SMOTE(data, 3, 1)
+	counts := countLabels(out)
+	if counts[0] != 90 || counts[1] != 90 {
+		t.Fatalf("SMOTE failed. Expected 90 data points by label, but got %v", counts)
+	}
+	for _, dp := range out {
+		p := dp.Point()
+		if dp.Label() == 1 && (p[0] < 7 || p[1] < 7) {
+			t.Errorf("SMOTE failed. Expected synthetic points near minority blob, but got %v", p)
+		}
+	}
+}
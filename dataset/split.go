@@ -0,0 +1,71 @@
+package dataset
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var ErrInvalidRatio = errors.New("ratio is not in range (0, 1)")
+
+// Shuffle data points in place with the given seed
+func Shuffle(data []knn.DataPoint, seed int64) {
+	rnd := rand.New(rand.NewSource(seed))
+	rnd.Shuffle(len(data), func(i, j int) {
+		data[i], data[j] = data[j], data[i]
+	})
+}
+
+// Split data points in a train and a test set, ratio is the fraction of data points in the test set
+//
+// When stratifyByLabel is true every label keeps its proportion in both sets, labels must be comparable.
+// Data is not modified and both sets are shuffled with the given seed.
+func SplitTrainTest(data []knn.DataPoint, ratio float64, stratifyByLabel bool, seed int64) ([]knn.DataPoint, []knn.DataPoint) {
+	if ratio <= 0 || ratio >= 1 {
+		panic(ErrInvalidRatio)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	groups := [][]knn.DataPoint{data}
+	if stratifyByLabel {
+		groups = groupByLabel(data)
+	}
+	train := make([]knn.DataPoint, 0, len(data))
+	test := make([]knn.DataPoint, 0, int(float64(len(data))*ratio)+len(groups))
+	for _, group := range groups {
+		group = append([]knn.DataPoint{}, group...)
+		rnd.Shuffle(len(group), func(i, j int) {
+			group[i], group[j] = group[j], group[i]
+		})
+		n := int(math.Round(float64(len(group)) * ratio))
+		test = append(test, group[:n]...)
+		train = append(train, group[n:]...)
+	}
+	if stratifyByLabel {
+		// groups are concatenated, so labels must be mixed again
+		rnd.Shuffle(len(train), func(i, j int) {
+			train[i], train[j] = train[j], train[i]
+		})
+		rnd.Shuffle(len(test), func(i, j int) {
+			test[i], test[j] = test[j], test[i]
+		})
+	}
+	return train, test
+}
+
+// group data points by label in order of first appearance
+func groupByLabel(data []knn.DataPoint) [][]knn.DataPoint {
+	index := make(map[any]int)
+	groups := make([][]knn.DataPoint, 0, 10)
+	for _, dp := range data {
+		id, ok := index[dp.Label()]
+		if !ok {
+			id = len(groups)
+			index[dp.Label()] = id
+			groups = append(groups, nil)
+		}
+		groups[id] = append(groups[id], dp)
+	}
+	return groups
+}
@@ -0,0 +1,68 @@
+package dataset
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func imbalanced() []knn.DataPoint {
+	data := make([]knn.DataPoint, 0, 100)
+	for i := 0; i < 100; i++ {
+		label := "a"
+		if i%10 == 0 {
+			label = "b"
+		}
+		data = append(data, knn.NewDataPoint(label, knn.Point{float64(i)}))
+	}
+	return data
+}
+
+func countLabels(data []knn.DataPoint) map[any]int {
+	counts := make(map[any]int)
+	for _, dp := range data {
+		counts[dp.Label()]++
+	}
+	return counts
+}
+
+func TestSplitTrainTest(t *testing.T) {
+	data := imbalanced()
+	train, test := SplitTrainTest(data, 0.2, true, 1)
+	if len(train) != 80 || len(test) != 20 {
+		t.Fatalf("SplitTrainTest failed. Expected 80 and 20 points, but got %d and %d", len(train), len(test))
+	}
+	if counts := countLabels(test); counts["a"] != 18 || counts["b"] != 2 {
+		t.Errorf("SplitTrainTest failed. Expected 18 a and 2 b in test set, but got %v", counts)
+	}
+	seen := make(map[float64]bool)
+	for _, dp := range append(train, test...) {
+		seen[dp.Point()[0]] = true
+	}
+	if len(seen) != len(data) {
+		t.Errorf("SplitTrainTest failed. Expected %d different points, but got %d", len(data), len(seen))
+	}
+	if data[0].Point()[0] != 0 || data[99].Point()[0] != 99 {
+		t.Errorf("SplitTrainTest failed. Data was modified")
+	}
+	again, _ := SplitTrainTest(data, 0.2, true, 1)
+	for i := range train {
+		if train[i] != again[i] {
+			t.Fatalf("SplitTrainTest failed. Split is not deterministic")
+		}
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	data := imbalanced()
+	Shuffle(data, 2)
+	moved := 0
+	for i, dp := range data {
+		if dp.Point()[0] != float64(i) {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Errorf("Shuffle failed. Data points were not moved")
+	}
+}
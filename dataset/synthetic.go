@@ -0,0 +1,121 @@
+// Package dataset generates, loads and splits data sets of knn.DataPoint
+package dataset
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var (
+	ErrInvalidSize    = errors.New("data set size is not greater than zero")
+	ErrInvalidCenters = errors.New("centers are empty or have different dimensions")
+	ErrInvalidNoise   = errors.New("noise is lesser than zero")
+	ErrInvalidFactor  = errors.New("factor is not in range (0, 1)")
+)
+
+func checkSize(n int) {
+	if n <= 0 {
+		panic(ErrInvalidSize)
+	}
+}
+
+func checkNoise(noise float64) {
+	if noise < 0 {
+		panic(ErrInvalidNoise)
+	}
+}
+
+// Isotropic Gaussian blobs
+//
+// It generates n points around the centers with standard deviation std, points are assigned to centers in turn
+// and the label of every point is the index of its center
+func MakeBlobs(n int, centers []knn.Point, std float64, seed int64) []knn.DataPoint {
+	checkSize(n)
+	checkNoise(std)
+	if len(centers) == 0 {
+		panic(ErrInvalidCenters)
+	}
+	dim := len(centers[0])
+	for _, c := range centers {
+		if len(c) != dim {
+			panic(ErrInvalidCenters)
+		}
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	data := make([]knn.DataPoint, n)
+	for i := range data {
+		label := i % len(centers)
+		p := knn.NewPoint(dim)
+		for d := range p {
+			p[d] = centers[label][d] + rnd.NormFloat64()*std
+		}
+		data[i] = knn.NewDataPoint(label, p)
+	}
+	return data
+}
+
+// Two concentric circles in the plane
+//
+// Outer circle has radius one and label 0, inner circle has radius factor and label 1, gaussian noise
+// with standard deviation noise is added to every coordinate
+func MakeCircles(n int, noise, factor float64, seed int64) []knn.DataPoint {
+	checkSize(n)
+	checkNoise(noise)
+	if factor <= 0 || factor >= 1 {
+		panic(ErrInvalidFactor)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	outer := (n + 1) / 2
+	data := make([]knn.DataPoint, n)
+	for i := range data {
+		label, radius, count, j := 0, 1.0, outer, i
+		if i >= outer {
+			label, radius, count, j = 1, factor, n-outer, i-outer
+		}
+		angle := 2 * math.Pi * float64(j) / float64(count)
+		p := knn.Point{
+			radius*math.Cos(angle) + rnd.NormFloat64()*noise,
+			radius*math.Sin(angle) + rnd.NormFloat64()*noise,
+		}
+		data[i] = knn.NewDataPoint(label, p)
+	}
+	return data
+}
+
+// Two interleaving half circles in the plane
+//
+// Upper moon has label 0 and lower moon has label 1, gaussian noise with standard deviation noise
+// is added to every coordinate
+func MakeMoons(n int, noise float64, seed int64) []knn.DataPoint {
+	checkSize(n)
+	checkNoise(noise)
+	rnd := rand.New(rand.NewSource(seed))
+	upper := (n + 1) / 2
+	data := make([]knn.DataPoint, n)
+	for i := range data {
+		var p knn.Point
+		label := 0
+		if i < upper {
+			angle := math.Pi * float64(i) / float64(maxInt(upper-1, 1))
+			p = knn.Point{math.Cos(angle), math.Sin(angle)}
+		} else {
+			label = 1
+			angle := math.Pi * float64(i-upper) / float64(maxInt(n-upper-1, 1))
+			p = knn.Point{1 - math.Cos(angle), 0.5 - math.Sin(angle)}
+		}
+		p[0] += rnd.NormFloat64() * noise
+		p[1] += rnd.NormFloat64() * noise
+		data[i] = knn.NewDataPoint(label, p)
+	}
+	return data
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,60 @@
+package dataset
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func TestMakeBlobs(t *testing.T) {
+	centers := []knn.Point{{0, 0}, {10, 10}, {-10, 10}}
+	data := MakeBlobs(300, centers, 0.5, 1)
+	if len(data) != 300 {
+		t.Fatalf("MakeBlobs failed. Expected 300 points, but got %d", len(data))
+	}
+	dist := knn.NewEuclideanDist()
+	for _, dp := range data {
+		if d := dist.Eval(dp.Point(), centers[dp.Label().(int)]); d > 3 {
+			t.Fatalf("MakeBlobs failed. Point %v is far from its center %v", dp.Point(), centers[dp.Label().(int)])
+		}
+	}
+}
+
+func TestMakeCircles(t *testing.T) {
+	data := MakeCircles(101, 0, 0.5, 1)
+	for _, dp := range data {
+		p := dp.Point()
+		radius := math.Hypot(p[0], p[1])
+		expected := 1.0
+		if dp.Label().(int) == 1 {
+			expected = 0.5
+		}
+		if math.Abs(radius-expected) > 1e-9 {
+			t.Fatalf("MakeCircles failed. Expected radius %v, but got %v", expected, radius)
+		}
+	}
+}
+
+func TestMakeMoons(t *testing.T) {
+	data := MakeMoons(200, 0.05, 1)
+	counts := make(map[any]int)
+	for _, dp := range data {
+		counts[dp.Label()]++
+	}
+	if counts[0] != 100 || counts[1] != 100 {
+		t.Fatalf("MakeMoons failed. Unexpected classes %v", counts)
+	}
+	// moons are not linearly separable but knn separates them
+	model := knn.NewKNN(5, knn.NewEuclideanDist(), knn.NewMultiClassSelector(), data)
+	test := MakeMoons(100, 0.05, 2)
+	hits := 0
+	for _, dp := range test {
+		if model.Predict(dp.Point()) == dp.Label() {
+			hits++
+		}
+	}
+	if hits < 95 {
+		t.Errorf("MakeMoons failed. Expected at least 95 hits, but got %d", hits)
+	}
+}
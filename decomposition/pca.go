@@ -0,0 +1,224 @@
+// Package decomposition implements linear dimensionality reduction
+package decomposition
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+var (
+	ErrNotFitted          = errors.New("model is not fitted")
+	ErrEmptyData          = errors.New("there are no points to fit")
+	ErrDimensionMismatch  = errors.New("point dimension doesn't match fitted dimension")
+	ErrComponentsNotValid = errors.New("number of components is not in range [0, min(points, dimension)]")
+)
+
+// Principal component analysis
+type PCA struct {
+	components int
+	whiten     bool
+	mean       []float64
+	axes       [][]float64 //principal axes by rows
+	variance   []float64
+	totalVar   float64
+}
+
+// Create PCA keeping components principal axes, all of them if components is zero
+//
+// With whiten projections are scaled to unit variance
+func NewPCA(components int, whiten bool) *PCA {
+	if components < 0 {
+		panic(ErrComponentsNotValid)
+	}
+	return &PCA{components: components, whiten: whiten}
+}
+
+// Fit principal axes of points with the SVD of centered points
+func (pca *PCA) Fit(points []knn.Point) {
+	if len(points) == 0 {
+		panic(ErrEmptyData)
+	}
+	n, dim := len(points), len(points[0])
+	k := pca.components
+	if k == 0 {
+		k = n
+		if dim < k {
+			k = dim
+		}
+	}
+	if k > n || k > dim {
+		panic(ErrComponentsNotValid)
+	}
+	pca.mean = make([]float64, dim)
+	for _, p := range points {
+		if len(p) != dim {
+			panic(ErrDimensionMismatch)
+		}
+		for d, v := range p {
+			pca.mean[d] += v
+		}
+	}
+	for d := range pca.mean {
+		pca.mean[d] /= float64(n)
+	}
+	centered := linalg.NewMatrix(n, dim, nil)
+	for i, p := range points {
+		for d, v := range p {
+			centered.Set(i, d, v-pca.mean[d])
+		}
+	}
+	_, s, v := centered.SVD()
+	// variance is divided by n - 1 as the unbiased estimator, a single point has no variance
+	div := float64(n - 1)
+	if div == 0 {
+		div = 1
+	}
+	pca.totalVar = 0
+	for _, sv := range s {
+		pca.totalVar += sv * sv / div
+	}
+	pca.axes = make([][]float64, k)
+	pca.variance = make([]float64, k)
+	for c := 0; c < k; c++ {
+		pca.variance[c] = s[c] * s[c] / div
+		pca.axes[c] = make([]float64, dim)
+		// sign of every axis is fixed, so the greatest coordinate is positive
+		sign, greatest := 1.0, 0.0
+		for d := 0; d < dim; d++ {
+			pca.axes[c][d] = v.At(d, c)
+			if a := v.At(d, c); a*a > greatest {
+				greatest = a * a
+				if a < 0 {
+					sign = -1
+				} else {
+					sign = 1
+				}
+			}
+		}
+		for d := range pca.axes[c] {
+			pca.axes[c][d] *= sign
+		}
+	}
+}
+
+func (pca *PCA) check(dim int) {
+	if pca.axes == nil {
+		panic(ErrNotFitted)
+	}
+	if dim != len(pca.mean) {
+		panic(ErrDimensionMismatch)
+	}
+}
+
+// Project point on the principal axes
+func (pca *PCA) Transform(point knn.Point) knn.Point {
+	pca.check(len(point))
+	out := make(knn.Point, len(pca.axes))
+	for c, axis := range pca.axes {
+		sum := 0.0
+		for d, v := range point {
+			sum += (v - pca.mean[d]) * axis[d]
+		}
+		if pca.whiten && pca.variance[c] > 0 {
+			sum /= math.Sqrt(pca.variance[c])
+		}
+		out[c] = sum
+	}
+	return out
+}
+
+// Project every point on the principal axes
+func (pca *PCA) TransformAll(points []knn.Point) []knn.Point {
+	out := make([]knn.Point, len(points))
+	for i, p := range points {
+		out[i] = pca.Transform(p)
+	}
+	return out
+}
+
+// Fit principal axes and project points on them
+func (pca *PCA) FitTransform(points []knn.Point) []knn.Point {
+	pca.Fit(points)
+	return pca.TransformAll(points)
+}
+
+// Point of original space with projection, it is the point itself if every component was kept
+func (pca *PCA) InverseTransform(projection knn.Point) knn.Point {
+	if pca.axes == nil {
+		panic(ErrNotFitted)
+	}
+	if len(projection) != len(pca.axes) {
+		panic(ErrDimensionMismatch)
+	}
+	out := make(knn.Point, len(pca.mean))
+	copy(out, pca.mean)
+	for c, axis := range pca.axes {
+		coord := projection[c]
+		if pca.whiten {
+			coord *= math.Sqrt(pca.variance[c])
+		}
+		for d, a := range axis {
+			out[d] += coord * a
+		}
+	}
+	return out
+}
+
+// Principal axes by rows in decreasing order of variance
+func (pca *PCA) Components() [][]float64 {
+	return pca.axes
+}
+
+// Mean of fitted points
+func (pca *PCA) Mean() []float64 {
+	return pca.mean
+}
+
+// Variance of points along every principal axis
+func (pca *PCA) ExplainedVariance() []float64 {
+	return pca.variance
+}
+
+// Fraction of the total variance along every principal axis
+func (pca *PCA) ExplainedVarianceRatio() []float64 {
+	ratio := make([]float64, len(pca.variance))
+	for c, v := range pca.variance {
+		if pca.totalVar > 0 {
+			ratio[c] = v / pca.totalVar
+		}
+	}
+	return ratio
+}
+
+type pcaSpec struct {
+	Components int
+	Whiten     bool
+	Mean       []float64
+	Axes       [][]float64
+	Variance   []float64
+	TotalVar   float64
+}
+
+// Encode principal axes
+func (pca *PCA) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&pcaSpec{Components: pca.components, Whiten: pca.whiten, Mean: pca.mean,
+		Axes: pca.axes, Variance: pca.variance, TotalVar: pca.totalVar})
+	return buf.Bytes(), err
+}
+
+// Decode principal axes encoded by GobEncode
+func (pca *PCA) GobDecode(b []byte) error {
+	var spec pcaSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return err
+	}
+	*pca = PCA{components: spec.Components, whiten: spec.Whiten, mean: spec.Mean, axes: spec.Axes,
+		variance: spec.Variance, totalVar: spec.TotalVar}
+	return nil
+}
@@ -0,0 +1,77 @@
+package decomposition
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// points along direction (1, 1) with variance 4 and along (1, -1) with variance 0.25, centered at (3, -1)
+func elongated(n int, seed int64) []knn.Point {
+	rnd := rand.New(rand.NewSource(seed))
+	points := make([]knn.Point, n)
+	for i := range points {
+		a, b := 2*rnd.NormFloat64(), 0.5*rnd.NormFloat64()
+		points[i] = knn.Point{3 + (a+b)/math.Sqrt2, -1 + (a-b)/math.Sqrt2}
+	}
+	return points
+}
+
+func TestPCA(t *testing.T) {
+	points := elongated(2000, 1)
+	pca := NewPCA(0, false)
+	pca.Fit(points)
+	axis := pca.Components()[0]
+	if math.Abs(axis[0]-math.Sqrt2/2) > 0.02 || math.Abs(axis[1]-math.Sqrt2/2) > 0.02 {
+		t.Errorf("Components failed. Expected first axis (0.707, 0.707), but got %v", axis)
+	}
+	variance := pca.ExplainedVariance()
+	if math.Abs(variance[0]-4) > 0.3 || math.Abs(variance[1]-0.25) > 0.03 {
+		t.Errorf("ExplainedVariance failed. Expected [4 0.25], but got %v", variance)
+	}
+	ratio := pca.ExplainedVarianceRatio()
+	if math.Abs(ratio[0]+ratio[1]-1) > 1e-12 {
+		t.Errorf("ExplainedVarianceRatio failed. Expected sum 1, but got %v", ratio)
+	}
+	// with every component the inverse is exact
+	for _, p := range points[:10] {
+		back := pca.InverseTransform(pca.Transform(p))
+		if math.Abs(back[0]-p[0]) > 1e-9 || math.Abs(back[1]-p[1]) > 1e-9 {
+			t.Errorf("InverseTransform failed. Expected %v, but got %v", p, back)
+		}
+	}
+}
+
+func TestPCAReduction(t *testing.T) {
+	points := elongated(500, 2)
+	pca := NewPCA(1, false)
+	projected := pca.FitTransform(points)
+	if len(projected[0]) != 1 {
+		t.Fatalf("Transform failed. Expected 1 component, but got %d", len(projected[0]))
+	}
+	// reconstruction error is the discarded variance
+	sum := 0.0
+	for i, p := range points {
+		back := pca.InverseTransform(projected[i])
+		sum += (back[0]-p[0])*(back[0]-p[0]) + (back[1]-p[1])*(back[1]-p[1])
+	}
+	if mse := sum / float64(len(points)-1); math.Abs(mse-0.25) > 0.05 {
+		t.Errorf("InverseTransform failed. Expected error 0.25, but got %v", mse)
+	}
+}
+
+func TestWhiten(t *testing.T) {
+	points := elongated(1000, 3)
+	projected := NewPCA(0, true).FitTransform(points)
+	for c := 0; c < 2; c++ {
+		sum := 0.0
+		for _, p := range projected {
+			sum += p[c] * p[c]
+		}
+		if variance := sum / float64(len(projected)-1); math.Abs(variance-1) > 1e-9 {
+			t.Errorf("Whiten failed. Expected unit variance, but got %v", variance)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Package discriminant implements discriminant analysis for classification and supervised dimensionality reduction
+package discriminant
+
+import (
+	"errors"
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+var (
+	ErrNotFitted          = errors.New("model is not fitted")
+	ErrEmptyData          = errors.New("there are no data points to fit")
+	ErrDimensionMismatch  = errors.New("point dimension doesn't match fitted dimension")
+	ErrTooFewClasses      = errors.New("there are less than two classes")
+	ErrComponentsNotValid = errors.New("number of components is not in range [0, min(classes - 1, dimension)]")
+	ErrShrinkageNotValid  = errors.New("shrinkage is not in range [0, 1]")
+)
+
+// Linear discriminant analysis with covariance shared by classes
+type LDA struct {
+	components int
+	shrinkage  float64
+	classes    []any
+	means      [][]float64
+	logPrior   []float64
+	mean       []float64      //mean of every data point
+	precision  *linalg.Matrix //inverse of shared covariance
+	scalings   [][]float64    //discriminant axes by rows
+	ratio      []float64
+}
+
+// Create LDA keeping components discriminant axes, min(classes - 1, dimension) if components is zero
+//
+// Shrinkage in [0, 1] blends the within class covariance with its mean variance times identity, it regularizes
+// singular covariances
+func NewLDA(components int, shrinkage float64) *LDA {
+	if components < 0 {
+		panic(ErrComponentsNotValid)
+	}
+	if shrinkage < 0 || shrinkage > 1 {
+		panic(ErrShrinkageNotValid)
+	}
+	return &LDA{components: components, shrinkage: shrinkage}
+}
+
+// Fit class means, shared covariance and discriminant axes of the between and within class scatter matrices
+//
+// It returns ErrNotPositiveDefinite of linalg if the within class scatter is singular, use shrinkage then
+func (lda *LDA) Fit(data []knn.DataPoint) error {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	dim := len(data[0].Point())
+	ids := make(map[any]int)
+	lda.classes = nil
+	lda.means = nil
+	counts := make([]int, 0, 10)
+	lda.mean = make([]float64, dim)
+	for _, dp := range data {
+		if len(dp.Point()) != dim {
+			panic(ErrDimensionMismatch)
+		}
+		id, ok := ids[dp.Label()]
+		if !ok {
+			id = len(lda.classes)
+			ids[dp.Label()] = id
+			lda.classes = append(lda.classes, dp.Label())
+			lda.means = append(lda.means, make([]float64, dim))
+			counts = append(counts, 0)
+		}
+		counts[id]++
+		for d, v := range dp.Point() {
+			lda.means[id][d] += v
+			lda.mean[d] += v
+		}
+	}
+	classes := len(lda.classes)
+	if classes < 2 {
+		panic(ErrTooFewClasses)
+	}
+	k := lda.components
+	if k == 0 {
+		k = classes - 1
+		if dim < k {
+			k = dim
+		}
+	}
+	if k > classes-1 || k > dim {
+		panic(ErrComponentsNotValid)
+	}
+	n := float64(len(data))
+	for d := range lda.mean {
+		lda.mean[d] /= n
+	}
+	lda.logPrior = make([]float64, classes)
+	for c, m := range lda.means {
+		for d := range m {
+			m[d] /= float64(counts[c])
+		}
+		lda.logPrior[c] = math.Log(float64(counts[c]) / n)
+	}
+	// within class scatter
+	within := linalg.NewMatrix(dim, dim, nil)
+	for _, dp := range data {
+		m := lda.means[ids[dp.Label()]]
+		for i := 0; i < dim; i++ {
+			di := dp.Point()[i] - m[i]
+			for j := 0; j <= i; j++ {
+				within.Set(i, j, within.At(i, j)+di*(dp.Point()[j]-m[j]))
+			}
+		}
+	}
+	trace := 0.0
+	for i := 0; i < dim; i++ {
+		for j := 0; j < i; j++ {
+			within.Set(j, i, within.At(i, j))
+		}
+		trace += within.At(i, i)
+	}
+	for i := 0; i < dim; i++ {
+		for j := 0; j < dim; j++ {
+			v := (1 - lda.shrinkage) * within.At(i, j)
+			if i == j {
+				v += lda.shrinkage * trace / float64(dim)
+			}
+			within.Set(i, j, v)
+		}
+	}
+	// between class scatter
+	between := linalg.NewMatrix(dim, dim, nil)
+	for c, m := range lda.means {
+		for i := 0; i < dim; i++ {
+			for j := 0; j < dim; j++ {
+				between.Set(i, j, between.At(i, j)+float64(counts[c])*(m[i]-lda.mean[i])*(m[j]-lda.mean[j]))
+			}
+		}
+	}
+	// generalized problem between v = lambda within v, with within = L L^T it is symmetric in u = L^T v
+	l, err := within.Cholesky()
+	if err != nil {
+		lda.precision = nil
+		return err
+	}
+	lInv, err := l.Inverse()
+	if err != nil {
+		lda.precision = nil
+		return err
+	}
+	values, vectors := lInv.Mul(between).Mul(lInv.T()).SymEigen()
+	scalings := lInv.T().Mul(vectors)
+	// projections have unit variance within classes
+	dof := n - float64(classes)
+	if dof <= 0 {
+		dof = 1
+	}
+	total := 0.0
+	for _, v := range values {
+		total += math.Max(v, 0)
+	}
+	lda.scalings = make([][]float64, k)
+	lda.ratio = make([]float64, k)
+	for c := 0; c < k; c++ {
+		lda.scalings[c] = make([]float64, dim)
+		for d := 0; d < dim; d++ {
+			lda.scalings[c][d] = scalings.At(d, c) * math.Sqrt(dof)
+		}
+		if total > 0 {
+			lda.ratio[c] = math.Max(values[c], 0) / total
+		}
+	}
+	// shared covariance within / (n - classes) has precision (n - classes) * L^-T L^-1
+	lda.precision = lInv.T().Mul(lInv)
+	for i := 0; i < dim; i++ {
+		for j := 0; j < dim; j++ {
+			lda.precision.Set(i, j, lda.precision.At(i, j)*dof)
+		}
+	}
+	return nil
+}
+
+func (lda *LDA) check(point knn.Point) {
+	if lda.precision == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != len(lda.mean) {
+		panic(ErrDimensionMismatch)
+	}
+}
+
+// Project point on the discriminant axes
+func (lda *LDA) Transform(point knn.Point) knn.Point {
+	lda.check(point)
+	out := make(knn.Point, len(lda.scalings))
+	for c, axis := range lda.scalings {
+		for d, v := range point {
+			out[c] += (v - lda.mean[d]) * axis[d]
+		}
+	}
+	return out
+}
+
+// log joint likelihoods of point by class, up to the same constant
+func (lda *LDA) scores(point knn.Point) []float64 {
+	lda.check(point)
+	scores := make([]float64, len(lda.classes))
+	for c, m := range lda.means {
+		dif := make([]float64, len(point))
+		for d, v := range point {
+			dif[d] = v - m[d]
+		}
+		pd := lda.precision.MulVec(dif)
+		maha := 0.0
+		for d := range dif {
+			maha += dif[d] * pd[d]
+		}
+		scores[c] = lda.logPrior[c] - maha/2
+	}
+	return scores
+}
+
+// Predict label of point
+func (lda *LDA) Predict(point knn.Point) any {
+	scores := lda.scores(point)
+	best := 0
+	for c := range scores {
+		if scores[c] > scores[best] {
+			best = c
+		}
+	}
+	return lda.classes[best]
+}
+
+// Predict class probabilities of point
+func (lda *LDA) PredictProba(point knn.Point) map[any]float64 {
+	scores := lda.scores(point)
+	greatest := math.Inf(-1)
+	for _, s := range scores {
+		greatest = math.Max(greatest, s)
+	}
+	sum := 0.0
+	for c, s := range scores {
+		scores[c] = math.Exp(s - greatest)
+		sum += scores[c]
+	}
+	proba := make(map[any]float64, len(scores))
+	for c, s := range scores {
+		proba[lda.classes[c]] = s / sum
+	}
+	return proba
+}
+
+// Classes in order of appearance
+func (lda *LDA) Classes() []any {
+	return lda.classes
+}
+
+// Discriminant axes by rows in decreasing order of separation
+func (lda *LDA) Scalings() [][]float64 {
+	return lda.scalings
+}
+
+// Fraction of the between class variance explained by every discriminant axis
+func (lda *LDA) ExplainedVarianceRatio() []float64 {
+	return lda.ratio
+}
@@ -0,0 +1,91 @@
+package discriminant
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linalg"
+	"github.com/stellviaproject/go-ia/metrics"
+)
+
+func TestLDA(t *testing.T) {
+	data := dataset.MakeBlobs(600, []knn.Point{{0, 0, 0}, {3, 0, 0}, {0, 3, 0}}, 1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.25, true, 1)
+	lda := NewLDA(0, 0)
+	if err := lda.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	expected := make([]any, len(test))
+	predicted := make([]any, len(test))
+	for i, dp := range test {
+		expected[i] = dp.Label()
+		predicted[i] = lda.Predict(dp.Point())
+	}
+	if acc := metrics.Accuracy(expected, predicted); acc < 0.9 {
+		t.Errorf("LDA failed. Expected accuracy greater than 0.9, but got %v", acc)
+	}
+	if len(lda.Transform(knn.Point{1, 1, 1})) != 2 {
+		t.Errorf("Transform failed. Expected 2 components")
+	}
+	ratio := lda.ExplainedVarianceRatio()
+	if math.Abs(ratio[0]+ratio[1]-1) > 1e-9 || ratio[0] < ratio[1] {
+		t.Errorf("ExplainedVarianceRatio failed. Unexpected ratios %v", ratio)
+	}
+	// discriminant axes don't use the uninformative third feature
+	for _, axis := range lda.Scalings() {
+		if math.Abs(axis[2]) > 0.2*math.Hypot(axis[0], axis[1]) {
+			t.Errorf("Scalings failed. Third feature has weight in %v", axis)
+		}
+	}
+}
+
+func TestLDAWhitening(t *testing.T) {
+	data := dataset.MakeBlobs(400, []knn.Point{{0, 0}, {4, 1}}, 2, 2)
+	lda := NewLDA(1, 0)
+	lda.Fit(data)
+	// projections have unit variance within classes
+	groups := map[any][]float64{}
+	for _, dp := range data {
+		groups[dp.Label()] = append(groups[dp.Label()], lda.Transform(dp.Point())[0])
+	}
+	sum := 0.0
+	for _, g := range groups {
+		mean := 0.0
+		for _, v := range g {
+			mean += v
+		}
+		mean /= float64(len(g))
+		for _, v := range g {
+			sum += (v - mean) * (v - mean)
+		}
+	}
+	if variance := sum / float64(len(data)-2); math.Abs(variance-1) > 1e-9 {
+		t.Errorf("Transform failed. Expected unit within class variance, but got %v", variance)
+	}
+	proba := lda.PredictProba(knn.Point{4, 1})
+	if proba[1] < 0.85 {
+		t.Errorf("PredictProba failed. Expected class 1, but got %v", proba)
+	}
+}
+
+func TestShrinkage(t *testing.T) {
+	// constant feature makes the within class scatter singular
+	data := []knn.DataPoint{
+		knn.NewDataPoint("a", knn.Point{0, 1}),
+		knn.NewDataPoint("a", knn.Point{1, 1}),
+		knn.NewDataPoint("b", knn.Point{4, 1}),
+		knn.NewDataPoint("b", knn.Point{5, 1}),
+	}
+	if err := NewLDA(0, 0).Fit(data); err != linalg.ErrNotPositiveDefinite {
+		t.Errorf("Fit failed. Expected ErrNotPositiveDefinite, but got %v", err)
+	}
+	lda := NewLDA(0, 0.1)
+	if err := lda.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := lda.Predict(knn.Point{4.2, 1}); got != "b" {
+		t.Errorf("Predict failed. Expected b, but got %v", got)
+	}
+}
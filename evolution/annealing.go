@@ -0,0 +1,81 @@
+package evolution
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Configuration of simulated annealing, zero fields take the default values
+type AnnealConfig struct {
+	Iterations  int     //10000 by default
+	Temperature float64 //initial temperature, 1 by default
+	Cooling     float64 //geometric cooling factor, it reaches 0.001 of initial temperature at the end by default
+	Step        float64 //deviation of neighbors relative to range of bounds, 0.1 by default
+	Callback    Callback
+	Seed        int64
+}
+
+func (config *AnnealConfig) defaults() {
+	if config.Iterations == 0 {
+		config.Iterations = 10000
+	}
+	if config.Temperature == 0 {
+		config.Temperature = 1
+	}
+	if config.Cooling == 0 {
+		config.Cooling = math.Pow(1e-3, 1/float64(config.Iterations))
+	}
+	if config.Step == 0 {
+		config.Step = 0.1
+	}
+	if config.Iterations < 1 || config.Temperature < 0 || config.Cooling <= 0 || config.Cooling > 1 || config.Step < 0 {
+		panic(ErrConfigNotValid)
+	}
+}
+
+// Minimize f inside bounds lo and hi with simulated annealing starting at x0, a random point is used if x0 is nil
+//
+// Neighbors are gaussian moves whose deviation shrinks with square root of temperature
+func Anneal(f func(x []float64) float64, x0, lo, hi []float64, config AnnealConfig) Solution {
+	checkBounds(lo, hi)
+	config.defaults()
+	rnd := rand.New(rand.NewSource(config.Seed))
+	x := make([]float64, len(lo))
+	if x0 == nil {
+		for i := range x {
+			x[i] = lo[i] + rnd.Float64()*(hi[i]-lo[i])
+		}
+	} else {
+		if len(x0) != len(lo) {
+			panic(ErrConfigNotValid)
+		}
+		copy(x, x0)
+		clamp(x, lo, hi)
+	}
+	value := f(x)
+	sol := Solution{X: append([]float64(nil), x...), Value: value, History: make([]float64, 0, config.Iterations)}
+	temp := config.Temperature
+	next := make([]float64, len(x))
+	for iter := 0; iter < config.Iterations; iter++ {
+		scale := config.Step * math.Sqrt(temp/config.Temperature)
+		for i := range next {
+			next[i] = x[i] + scale*(hi[i]-lo[i])*rnd.NormFloat64()
+		}
+		clamp(next, lo, hi)
+		v := f(next)
+		// accept better points always and worse ones with Boltzmann probability
+		if d := v - value; d <= 0 || temp > 0 && rnd.Float64() < math.Exp(-d/temp) {
+			x, next = next, x
+			value = v
+			if value < sol.Value {
+				sol.X, sol.Value = append(sol.X[:0], x...), value
+			}
+		}
+		sol.History = append(sol.History, sol.Value)
+		if config.Callback != nil && !config.Callback(iter, sol.X, sol.Value) {
+			break
+		}
+		temp *= config.Cooling
+	}
+	return sol
+}
@@ -0,0 +1,35 @@
+package evolution
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnneal(t *testing.T) {
+	rastrigin := func(x []float64) float64 {
+		sum := 10 * float64(len(x))
+		for _, v := range x {
+			sum += v*v - 10*math.Cos(2*math.Pi*v)
+		}
+		return sum
+	}
+	lo, hi := []float64{-5.12, -5.12}, []float64{5.12, 5.12}
+	sol := Anneal(rastrigin, []float64{4, -4}, lo, hi, AnnealConfig{Iterations: 20000, Temperature: 10, Seed: 1})
+	// local minima are near integer points, global one is at origin
+	if math.Abs(sol.X[0]) > 0.1 || math.Abs(sol.X[1]) > 0.1 || sol.Value > 0.5 {
+		t.Errorf("Anneal failed. Expected global minimum 0 at origin, but got %v at %v", sol.Value, sol.X)
+	}
+	if len(sol.History) != 20000 || sol.History[len(sol.History)-1] != sol.Value {
+		t.Errorf("Anneal failed. History doesn't match solution")
+	}
+}
+
+func TestAnnealCallback(t *testing.T) {
+	lo, hi := []float64{-1}, []float64{1}
+	sol := Anneal(func(x []float64) float64 { return x[0] * x[0] }, nil, lo, hi, AnnealConfig{Callback: func(iter int, best []float64, value float64) bool {
+		return value > 1e-6
+	}})
+	if sol.Value > 1e-6 || len(sol.History) == 10000 {
+		t.Errorf("Anneal failed. Expected stop by callback below 1e-6, but got %v after %v iterations", sol.Value, len(sol.History))
+	}
+}
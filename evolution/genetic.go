@@ -0,0 +1,183 @@
+// Package evolution implements evolutionary optimization with genetic algorithms
+package evolution
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+var ErrConfigNotValid = errors.New("genetic algorithm configuration is not valid")
+
+// Genome of type G, operators return new genomes and don't change their receivers
+type Genome[G any] interface {
+	Crossover(other G, rnd *rand.Rand) G
+	Mutate(rnd *rand.Rand) G
+}
+
+// Selection of parents by fitness, greater fitness is better
+type Selection interface {
+	Select(fitness []float64, rnd *rand.Rand) int
+}
+
+type tournament struct {
+	size int
+}
+
+// Create tournament selection, the best of size random individuals is selected
+func NewTournament(size int) Selection {
+	if size < 1 {
+		panic(ErrConfigNotValid)
+	}
+	return tournament{size: size}
+}
+
+func (t tournament) Select(fitness []float64, rnd *rand.Rand) int {
+	best := rnd.Intn(len(fitness))
+	for i := 1; i < t.size; i++ {
+		if j := rnd.Intn(len(fitness)); fitness[j] > fitness[best] {
+			best = j
+		}
+	}
+	return best
+}
+
+type roulette struct{}
+
+// Create roulette wheel selection, individuals are selected with probability proportional to their fitness
+// above the least one
+func NewRoulette() Selection {
+	return roulette{}
+}
+
+func (roulette) Select(fitness []float64, rnd *rand.Rand) int {
+	least := math.Inf(1)
+	for _, f := range fitness {
+		least = math.Min(least, f)
+	}
+	total := 0.0
+	for _, f := range fitness {
+		total += f - least
+	}
+	if total == 0 {
+		return rnd.Intn(len(fitness))
+	}
+	u := rnd.Float64() * total
+	for i, f := range fitness {
+		u -= f - least
+		if u < 0 {
+			return i
+		}
+	}
+	return len(fitness) - 1
+}
+
+// Configuration of genetic algorithm, zero fields take the default values
+type Config struct {
+	Population    int       //individuals by generation, 50 by default
+	Generations   int       //100 by default
+	Elitism       int       //best individuals copied to the next generation
+	CrossoverRate float64   //probability of crossover of parents, else the first parent is copied, 0.9 by default
+	MutationRate  float64   //probability of mutation of a child, 1 by default
+	Selection     Selection //tournament of 3 by default
+	Workers       int       //goroutines evaluating fitness, 1 by default
+	Seed          int64
+}
+
+func (config *Config) defaults() {
+	if config.Population == 0 {
+		config.Population = 50
+	}
+	if config.Generations == 0 {
+		config.Generations = 100
+	}
+	if config.CrossoverRate == 0 {
+		config.CrossoverRate = 0.9
+	}
+	if config.MutationRate == 0 {
+		config.MutationRate = 1
+	}
+	if config.Selection == nil {
+		config.Selection = NewTournament(3)
+	}
+	if config.Workers == 0 {
+		config.Workers = 1
+	}
+	if config.Population < 2 || config.Generations < 1 || config.Elitism < 0 || config.Elitism >= config.Population || config.Workers < 1 {
+		panic(ErrConfigNotValid)
+	}
+}
+
+// Result of evolution
+type Result[G any] struct {
+	Best        G
+	BestFitness float64
+	History     []float64 //best fitness of every generation
+}
+
+// evaluate fitness of population with workers goroutines
+func evaluate[G any](population []G, fitness func(G) float64, workers int) []float64 {
+	out := make([]float64, len(population))
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				out[i] = fitness(population[i])
+			}
+		}()
+	}
+	for i := range population {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return out
+}
+
+// Evolve a population created by random to maximize fitness, fitness must be safe for concurrent calls when
+// there are many workers
+func Evolve[G Genome[G]](random func(rnd *rand.Rand) G, fitness func(G) float64, config Config) Result[G] {
+	config.defaults()
+	rnd := rand.New(rand.NewSource(config.Seed))
+	population := make([]G, config.Population)
+	for i := range population {
+		population[i] = random(rnd)
+	}
+	result := Result[G]{BestFitness: math.Inf(-1), History: make([]float64, 0, config.Generations)}
+	for gen := 0; gen < config.Generations; gen++ {
+		scores := evaluate(population, fitness, config.Workers)
+		order := make([]int, len(population))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+		if scores[order[0]] > result.BestFitness {
+			result.Best, result.BestFitness = population[order[0]], scores[order[0]]
+		}
+		result.History = append(result.History, scores[order[0]])
+		if gen == config.Generations-1 {
+			break
+		}
+		next := make([]G, 0, config.Population)
+		for _, i := range order[:config.Elitism] {
+			next = append(next, population[i])
+		}
+		for len(next) < config.Population {
+			child := population[config.Selection.Select(scores, rnd)]
+			if rnd.Float64() < config.CrossoverRate {
+				child = child.Crossover(population[config.Selection.Select(scores, rnd)], rnd)
+			}
+			if rnd.Float64() < config.MutationRate {
+				child = child.Mutate(rnd)
+			}
+			next = append(next, child)
+		}
+		population = next
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package evolution
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/layers"
+)
+
+func TestOneMax(t *testing.T) {
+	fitness := func(g BitGenome) float64 {
+		count := 0.0
+		for _, b := range g.Bits {
+			if b {
+				count++
+			}
+		}
+		return count
+	}
+	for _, selection := range []Selection{NewTournament(3), NewRoulette()} {
+		result := Evolve(RandomBits(40, 1.0/40), fitness, Config{Generations: 200, Elitism: 2, Selection: selection, Seed: 1})
+		if result.BestFitness < 38 {
+			t.Errorf("Evolve failed. Expected at least 38 bits, but got %v", result.BestFitness)
+		}
+		// with elitism best fitness never decreases
+		for i := 1; i < len(result.History); i++ {
+			if result.History[i] < result.History[i-1] {
+				t.Fatalf("Evolve failed. Best fitness decreased at generation %d", i)
+			}
+		}
+	}
+}
+
+func TestRastrigin(t *testing.T) {
+	lo, hi := []float64{-5.12, -5.12}, []float64{5.12, 5.12}
+	fitness := func(g FloatGenome) float64 {
+		sum := 20.0
+		for _, x := range g.Genes {
+			sum += x*x - 10*math.Cos(2*math.Pi*x)
+		}
+		return -sum
+	}
+	result := Evolve(RandomFloat(lo, hi, 0.05, 0.5), fitness, Config{Population: 100, Generations: 150, Elitism: 2, Workers: 4, Seed: 2})
+	if result.BestFitness < -0.01 {
+		t.Errorf("Evolve failed. Expected global minimum 0, but got %v at %v", -result.BestFitness, result.Best.Genes)
+	}
+}
+
+func TestNeuroevolution(t *testing.T) {
+	net := layers.NewSequential(1, layers.NewDense(2, 4), layers.NewTanh(), layers.NewDense(4, 1))
+	size := len(net.Params())
+	lo, hi := make([]float64, size), make([]float64, size)
+	for i := range lo {
+		lo[i], hi[i] = -5, 5
+	}
+	inputs := [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	targets := []float64{0, 1, 1, 0}
+	// every worker evaluates its own copy of network
+	fitness := func(g FloatGenome) float64 {
+		local := net.Clone()
+		copy(local.Params(), g.Genes)
+		sum := 0.0
+		for i, x := range inputs {
+			dif := local.Forward(x)[0] - targets[i]
+			sum += dif * dif
+		}
+		return -sum
+	}
+	result := Evolve(RandomFloat(lo, hi, 0.05, 0.2), fitness, Config{Population: 100, Generations: 200, Elitism: 2, Workers: 4, Seed: 3})
+	if result.BestFitness < -0.05 {
+		t.Errorf("Neuroevolution failed. Expected XOR error lesser than 0.05, but got %v", -result.BestFitness)
+	}
+}
+
+func TestRoulette(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	counts := make([]int, 3)
+	for i := 0; i < 3000; i++ {
+		counts[NewRoulette().Select([]float64{1, 2, 3}, rnd)]++
+	}
+	// weights above the least fitness are 0, 1 and 2
+	if counts[0] != 0 || counts[2] < counts[1] {
+		t.Errorf("Roulette failed. Unexpected selections %v", counts)
+	}
+}
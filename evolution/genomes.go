@@ -0,0 +1,95 @@
+package evolution
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Real vector genome with bounds, crossover blends genes (BLX-alpha) and mutation adds gaussian noise
+type FloatGenome struct {
+	Genes  []float64
+	Lo, Hi []float64 //bounds of every gene
+	Sigma  float64   //deviation of mutation relative to the range of every gene
+	Rate   float64   //probability of mutation of every gene
+}
+
+// Create random generator of float genomes inside bounds, with mutation sigma and rate by gene
+func RandomFloat(lo, hi []float64, sigma, rate float64) func(rnd *rand.Rand) FloatGenome {
+	if len(lo) != len(hi) {
+		panic(ErrConfigNotValid)
+	}
+	return func(rnd *rand.Rand) FloatGenome {
+		genes := make([]float64, len(lo))
+		for i := range genes {
+			genes[i] = lo[i] + rnd.Float64()*(hi[i]-lo[i])
+		}
+		return FloatGenome{Genes: genes, Lo: lo, Hi: hi, Sigma: sigma, Rate: rate}
+	}
+}
+
+func (fg FloatGenome) with(genes []float64) FloatGenome {
+	for i := range genes {
+		genes[i] = math.Max(fg.Lo[i], math.Min(fg.Hi[i], genes[i]))
+	}
+	return FloatGenome{Genes: genes, Lo: fg.Lo, Hi: fg.Hi, Sigma: fg.Sigma, Rate: fg.Rate}
+}
+
+// Blend crossover with alpha 0.5, child genes are uniform in the parents range extended by half of it
+func (fg FloatGenome) Crossover(other FloatGenome, rnd *rand.Rand) FloatGenome {
+	genes := make([]float64, len(fg.Genes))
+	for i, a := range fg.Genes {
+		b := other.Genes[i]
+		lo, hi := math.Min(a, b), math.Max(a, b)
+		ext := 0.5 * (hi - lo)
+		genes[i] = lo - ext + rnd.Float64()*(hi-lo+2*ext)
+	}
+	return fg.with(genes)
+}
+
+// Gaussian mutation of every gene with probability Rate
+func (fg FloatGenome) Mutate(rnd *rand.Rand) FloatGenome {
+	genes := append([]float64(nil), fg.Genes...)
+	for i := range genes {
+		if rnd.Float64() < fg.Rate {
+			genes[i] += fg.Sigma * (fg.Hi[i] - fg.Lo[i]) * rnd.NormFloat64()
+		}
+	}
+	return fg.with(genes)
+}
+
+// Bit string genome, crossover cuts parents at one point and mutation flips bits
+type BitGenome struct {
+	Bits []bool
+	Rate float64 //probability of flip of every bit
+}
+
+// Create random generator of bit genomes of length bits with flip rate
+func RandomBits(length int, rate float64) func(rnd *rand.Rand) BitGenome {
+	return func(rnd *rand.Rand) BitGenome {
+		bits := make([]bool, length)
+		for i := range bits {
+			bits[i] = rnd.Intn(2) == 1
+		}
+		return BitGenome{Bits: bits, Rate: rate}
+	}
+}
+
+// One point crossover
+func (bg BitGenome) Crossover(other BitGenome, rnd *rand.Rand) BitGenome {
+	cut := rnd.Intn(len(bg.Bits) + 1)
+	bits := make([]bool, len(bg.Bits))
+	copy(bits, bg.Bits[:cut])
+	copy(bits[cut:], other.Bits[cut:])
+	return BitGenome{Bits: bits, Rate: bg.Rate}
+}
+
+// Flip every bit with probability Rate
+func (bg BitGenome) Mutate(rnd *rand.Rand) BitGenome {
+	bits := append([]bool(nil), bg.Bits...)
+	for i := range bits {
+		if rnd.Float64() < bg.Rate {
+			bits[i] = !bits[i]
+		}
+	}
+	return BitGenome{Bits: bits, Rate: bg.Rate}
+}
@@ -0,0 +1,118 @@
+package evolution
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Callback called after every iteration with best point and value found, returning false stops optimization
+type Callback func(iter int, best []float64, value float64) bool
+
+// Solution of minimization of a black-box function
+type Solution struct {
+	X       []float64
+	Value   float64
+	History []float64 //best value after every iteration
+}
+
+func checkBounds(lo, hi []float64) {
+	if len(lo) == 0 || len(lo) != len(hi) {
+		panic(ErrConfigNotValid)
+	}
+	for i := range lo {
+		if lo[i] > hi[i] {
+			panic(ErrConfigNotValid)
+		}
+	}
+}
+
+func clamp(x, lo, hi []float64) {
+	for i := range x {
+		x[i] = math.Max(lo[i], math.Min(hi[i], x[i]))
+	}
+}
+
+// Configuration of particle swarm optimization, zero fields take the default values
+type PSOConfig struct {
+	Particles  int     //30 by default
+	Iterations int     //100 by default
+	Inertia    float64 //0.7298 by default
+	Cognitive  float64 //attraction to best point of particle, 1.4962 by default
+	Social     float64 //attraction to best point of swarm, 1.4962 by default
+	Workers    int     //goroutines evaluating particles, 1 by default
+	Callback   Callback
+	Seed       int64
+}
+
+func (config *PSOConfig) defaults() {
+	if config.Particles == 0 {
+		config.Particles = 30
+	}
+	if config.Iterations == 0 {
+		config.Iterations = 100
+	}
+	if config.Inertia == 0 {
+		config.Inertia = 0.7298
+	}
+	if config.Cognitive == 0 {
+		config.Cognitive = 1.4962
+	}
+	if config.Social == 0 {
+		config.Social = 1.4962
+	}
+	if config.Workers == 0 {
+		config.Workers = 1
+	}
+	if config.Particles < 1 || config.Iterations < 1 || config.Workers < 1 {
+		panic(ErrConfigNotValid)
+	}
+}
+
+// Minimize f inside bounds lo and hi with particle swarm optimization, f must be safe for concurrent calls
+// when there are many workers
+func PSO(f func(x []float64) float64, lo, hi []float64, config PSOConfig) Solution {
+	checkBounds(lo, hi)
+	config.defaults()
+	rnd := rand.New(rand.NewSource(config.Seed))
+	dim := len(lo)
+	pos := make([][]float64, config.Particles)
+	vel := make([][]float64, config.Particles)
+	for i := range pos {
+		pos[i], vel[i] = make([]float64, dim), make([]float64, dim)
+		for j := 0; j < dim; j++ {
+			pos[i][j] = lo[j] + rnd.Float64()*(hi[j]-lo[j])
+			vel[i][j] = (rnd.Float64()*2 - 1) * (hi[j] - lo[j]) * 0.1
+		}
+	}
+	best := make([][]float64, config.Particles)
+	bestValue := make([]float64, config.Particles)
+	sol := Solution{Value: math.Inf(1), History: make([]float64, 0, config.Iterations)}
+	for iter := 0; iter < config.Iterations; iter++ {
+		values := evaluate(pos, f, config.Workers)
+		for i, v := range values {
+			if best[i] == nil || v < bestValue[i] {
+				best[i], bestValue[i] = append([]float64(nil), pos[i]...), v
+			}
+			if v < sol.Value {
+				sol.X, sol.Value = append([]float64(nil), pos[i]...), v
+			}
+		}
+		sol.History = append(sol.History, sol.Value)
+		if config.Callback != nil && !config.Callback(iter, sol.X, sol.Value) {
+			break
+		}
+		// move particles, velocity is limited to range of bounds
+		for i := range pos {
+			for j := 0; j < dim; j++ {
+				v := config.Inertia*vel[i][j] +
+					config.Cognitive*rnd.Float64()*(best[i][j]-pos[i][j]) +
+					config.Social*rnd.Float64()*(sol.X[j]-pos[i][j])
+				limit := hi[j] - lo[j]
+				vel[i][j] = math.Max(-limit, math.Min(limit, v))
+				pos[i][j] += vel[i][j]
+			}
+			clamp(pos[i], lo, hi)
+		}
+	}
+	return sol
+}
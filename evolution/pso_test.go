@@ -0,0 +1,43 @@
+package evolution
+
+import (
+	"math"
+	"testing"
+)
+
+func rosenbrock(x []float64) float64 {
+	sum := 0.0
+	for i := 0; i+1 < len(x); i++ {
+		sum += 100*(x[i+1]-x[i]*x[i])*(x[i+1]-x[i]*x[i]) + (1-x[i])*(1-x[i])
+	}
+	return sum
+}
+
+func TestPSO(t *testing.T) {
+	lo, hi := []float64{-2, -2, -2}, []float64{2, 2, 2}
+	sol := PSO(rosenbrock, lo, hi, PSOConfig{Particles: 40, Iterations: 1000, Workers: 4, Seed: 1})
+	if sol.Value > 1e-4 {
+		t.Errorf("PSO failed. Expected minimum 0 at [1 1 1], but got %v at %v", sol.Value, sol.X)
+	}
+	for i := 1; i < len(sol.History); i++ {
+		if sol.History[i] > sol.History[i-1] {
+			t.Fatalf("PSO failed. Best value increased at iteration %d", i)
+		}
+	}
+}
+
+func TestPSOBounds(t *testing.T) {
+	// minimum of x is outside bounds, it must be found at lower bound
+	lo, hi := []float64{1, -1}, []float64{3, 1}
+	calls := 0
+	sol := PSO(func(x []float64) float64 { return x[0] + x[1]*x[1] }, lo, hi, PSOConfig{Callback: func(iter int, best []float64, value float64) bool {
+		calls++
+		return iter < 9
+	}})
+	if calls != 10 || len(sol.History) != 10 {
+		t.Errorf("PSO failed. Expected 10 iterations stopped by callback, but got %v", calls)
+	}
+	if math.Abs(sol.X[0]-1) > 1e-3 || sol.X[0] < 1 {
+		t.Errorf("PSO failed. Expected x at lower bound 1, but got %v", sol.X[0])
+	}
+}
@@ -0,0 +1,135 @@
+package features
+
+import (
+	"math"
+	"sort"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/model"
+)
+
+// Importance of every feature of a fitted model, from FeatureImportances of trees or absolute values of Coef of
+// linear models and linear svm, summed over classes of multiclass models
+func Importances(m any) []float64 {
+	switch m := m.(type) {
+	case interface{ FeatureImportances() []float64 }:
+		return m.FeatureImportances()
+	case interface{ Coef() []float64 }:
+		coef := m.Coef()
+		out := make([]float64, len(coef))
+		for d, c := range coef {
+			out[d] = math.Abs(c)
+		}
+		return out
+	case interface{ Coef() [][]float64 }:
+		coef := m.Coef()
+		out := make([]float64, len(coef[0]))
+		for _, row := range coef {
+			for d, c := range row {
+				out[d] += math.Abs(c)
+			}
+		}
+		return out
+	}
+	panic(ErrNoImportances)
+}
+
+// Recursive feature elimination, a new model is fitted with the remaining features and the least important ones
+// are removed until k features remain
+type RFE struct {
+	factory func() model.PointModel
+	k, step int
+	dim     int
+	support []int
+	ranking []int
+	model   model.PointModel
+}
+
+// Create recursive feature elimination of models of factory, step features are removed by iteration, one if
+// lesser. Models must have importances, see Importances.
+func NewRFE(factory func() model.PointModel, k, step int) *RFE {
+	if k < 1 {
+		panic(ErrKNotValid)
+	}
+	if step < 1 {
+		step = 1
+	}
+	return &RFE{factory: factory, k: k, step: step}
+}
+
+// Select features of labeled data points and fit the model with the selected features
+func (rfe *RFE) Fit(data []knn.DataPoint) {
+	rfe.dim = dimension(data)
+	if rfe.k > rfe.dim {
+		panic(ErrKNotValid)
+	}
+	support := make([]int, rfe.dim)
+	for d := range support {
+		support[d] = d
+	}
+	// selected features have rank one, features removed later have lesser ranks
+	rfe.ranking = make([]int, rfe.dim)
+	rank := 1 + (rfe.dim-rfe.k+rfe.step-1)/rfe.step
+	for {
+		m := rfe.factory()
+		m.Fit(projectAll(data, support, rfe.dim))
+		if len(support) == rfe.k {
+			rfe.model = m
+			break
+		}
+		importances := Importances(m)
+		order := make([]int, len(support))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return importances[order[a]] < importances[order[b]]
+		})
+		remove := rfe.step
+		if len(support)-remove < rfe.k {
+			remove = len(support) - rfe.k
+		}
+		removed := make(map[int]bool, remove)
+		for _, i := range order[:remove] {
+			removed[i] = true
+			rfe.ranking[support[i]] = rank
+		}
+		rank--
+		next := make([]int, 0, len(support)-remove)
+		for i, d := range support {
+			if !removed[i] {
+				next = append(next, d)
+			}
+		}
+		support = next
+	}
+	for _, d := range support {
+		rfe.ranking[d] = 1
+	}
+	rfe.support = support
+}
+
+// Selected features of point
+func (rfe *RFE) Transform(point knn.Point) knn.Point {
+	return project(point, rfe.support, rfe.dim)
+}
+
+// Predict label of point with the model fitted with the selected features
+func (rfe *RFE) Predict(point knn.Point) any {
+	return rfe.model.Predict(rfe.Transform(point))
+}
+
+// Indices of selected features in increasing order
+func (rfe *RFE) Support() []int {
+	return rfe.support
+}
+
+// Rank of every feature, one for selected features and greater for features removed earlier
+func (rfe *RFE) Ranking() []int {
+	return rfe.ranking
+}
+
+// Model fitted with the selected features
+func (rfe *RFE) Model() model.PointModel {
+	return rfe.model
+}
@@ -0,0 +1,42 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/linear"
+	"github.com/stellviaproject/go-ia/model"
+	"github.com/stellviaproject/go-ia/tree"
+)
+
+func TestRFE(t *testing.T) {
+	data := informative(400, 4)
+	factories := map[string]func() model.PointModel{
+		"logistic": func() model.PointModel { return linear.NewLogisticRegression(linear.LogisticConfig{}) },
+		"tree":     func() model.PointModel { return tree.NewClassifier(tree.Config{MaxDepth: 4}) },
+	}
+	for name, factory := range factories {
+		rfe := NewRFE(factory, 2, 1)
+		rfe.Fit(data)
+		if s := rfe.Support(); s[0] != 0 || s[1] != 1 {
+			t.Errorf("RFE with %v failed. Expected features [0 1], but got %v", name, s)
+		}
+		if r := rfe.Ranking(); r[0] != 1 || r[1] != 1 || len(r) != 5 {
+			t.Errorf("RFE with %v failed. Expected rank 1 of selected features, but got %v", name, r)
+		}
+		hits := 0
+		for _, dp := range data {
+			if rfe.Predict(dp.Point()) == dp.Label() {
+				hits++
+			}
+		}
+		if acc := float64(hits) / float64(len(data)); acc < 0.9 {
+			t.Errorf("RFE with %v failed. Expected accuracy greater than 0.9, but got %v", name, acc)
+		}
+	}
+	defer func() {
+		if r := recover(); r != ErrNoImportances {
+			t.Errorf("Importances failed. Expected %v, but got %v", ErrNoImportances, r)
+		}
+	}()
+	Importances(struct{}{})
+}
@@ -0,0 +1,118 @@
+// Package features implements feature selection by scores of features and by recursive feature elimination
+package features
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var (
+	ErrEmptyData         = errors.New("there are no data points to fit")
+	ErrDimensionMismatch = errors.New("point dimension doesn't match fitted dimension")
+	ErrNotFitted         = errors.New("selector is not fitted")
+	ErrKNotValid         = errors.New("number of features to select is not valid")
+	ErrBinsNotValid      = errors.New("number of bins is lesser than two")
+	ErrNoImportances     = errors.New("model has no feature importances or coefficients")
+)
+
+// Score of every feature of labeled data points, greater is better
+type ScoreFunc func(data []knn.DataPoint) []float64
+
+func dimension(data []knn.DataPoint) int {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	dim := len(data[0].Point())
+	for _, dp := range data {
+		if len(dp.Point()) != dim {
+			panic(ErrDimensionMismatch)
+		}
+	}
+	return dim
+}
+
+// Variance of every feature, labels are ignored
+func Variance(data []knn.DataPoint) []float64 {
+	dim := dimension(data)
+	variances := make([]float64, dim)
+	for d := range variances {
+		mean := 0.0
+		for _, dp := range data {
+			mean += dp.Point()[d]
+		}
+		mean /= float64(len(data))
+		for _, dp := range data {
+			dif := dp.Point()[d] - mean
+			variances[d] += dif * dif
+		}
+		variances[d] /= float64(len(data))
+	}
+	return variances
+}
+
+// Mutual information in nats between every feature and the label, labels must be comparable classes
+//
+// Features are discretized in bins of equal frequency, equal values share a bin
+func MutualInformation(bins int) ScoreFunc {
+	if bins < 2 {
+		panic(ErrBinsNotValid)
+	}
+	return func(data []knn.DataPoint) []float64 {
+		dim := dimension(data)
+		n := float64(len(data))
+		ids := make(map[any]int)
+		labels := make([]int, len(data))
+		for i, dp := range data {
+			id, ok := ids[dp.Label()]
+			if !ok {
+				id = len(ids)
+				ids[dp.Label()] = id
+			}
+			labels[i] = id
+		}
+		classes := make([]float64, len(ids))
+		for _, c := range labels {
+			classes[c]++
+		}
+		scores := make([]float64, dim)
+		order := make([]int, len(data))
+		binOf := make([]int, len(data))
+		for d := range scores {
+			for i := range order {
+				order[i] = i
+			}
+			sort.Slice(order, func(a, b int) bool {
+				return data[order[a]].Point()[d] < data[order[b]].Point()[d]
+			})
+			bin := 0
+			for rank, i := range order {
+				if rank > 0 && data[i].Point()[d] != data[order[rank-1]].Point()[d] {
+					bin = rank * bins / len(order)
+				}
+				binOf[i] = bin
+			}
+			joint := make([][]float64, bins)
+			for b := range joint {
+				joint[b] = make([]float64, len(classes))
+			}
+			marginal := make([]float64, bins)
+			for i, b := range binOf {
+				joint[b][labels[i]]++
+				marginal[b]++
+			}
+			mi := 0.0
+			for b := range joint {
+				for c, count := range joint[b] {
+					if count > 0 {
+						mi += count / n * math.Log(count*n/(marginal[b]*classes[c]))
+					}
+				}
+			}
+			scores[d] = math.Max(mi, 0)
+		}
+		return scores
+	}
+}
@@ -0,0 +1,36 @@
+package features
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// features 0 and 1 are informative, 2 and 3 are noise and 4 is constant
+func informative(n int, seed int64) []knn.DataPoint {
+	rnd := rand.New(rand.NewSource(seed))
+	data := make([]knn.DataPoint, n)
+	for i := range data {
+		label := i % 2
+		c := float64(2*label - 1)
+		p := knn.Point{2*c + rnd.NormFloat64(), c + rnd.NormFloat64(), rnd.NormFloat64(), 3 * rnd.NormFloat64(), 1}
+		data[i] = knn.NewDataPoint(label, p)
+	}
+	return data
+}
+
+func TestVariance(t *testing.T) {
+	data := []knn.DataPoint{knn.NewDataPoint(0, knn.Point{1, 5}), knn.NewDataPoint(1, knn.Point{3, 5})}
+	v := Variance(data)
+	if v[0] != 1 || v[1] != 0 {
+		t.Errorf("Variance failed. Expected [1 0], but got %v", v)
+	}
+}
+
+func TestMutualInformation(t *testing.T) {
+	scores := MutualInformation(10)(informative(400, 1))
+	if scores[0] <= scores[1] || scores[1] <= scores[2] || scores[1] <= scores[3] || scores[4] != 0 {
+		t.Errorf("MutualInformation failed. Expected greatest scores of informative features, but got %v", scores)
+	}
+}
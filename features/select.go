@@ -0,0 +1,130 @@
+package features
+
+import (
+	"sort"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// features of selected indices of point
+func project(point knn.Point, support []int, dim int) knn.Point {
+	if support == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != dim {
+		panic(ErrDimensionMismatch)
+	}
+	out := knn.NewPoint(len(support))
+	for i, d := range support {
+		out[i] = point[d]
+	}
+	return out
+}
+
+// data points with the selected features
+func projectAll(data []knn.DataPoint, support []int, dim int) []knn.DataPoint {
+	out := make([]knn.DataPoint, len(data))
+	for i, dp := range data {
+		out[i] = knn.NewDataPoint(dp.Label(), project(dp.Point(), support, dim))
+	}
+	return out
+}
+
+// Selector of features with variance greater than a threshold, it removes constant features with threshold zero
+type VarianceThreshold struct {
+	threshold float64
+	dim       int
+	support   []int
+	variances []float64
+}
+
+// Create variance threshold selector
+func NewVarianceThreshold(threshold float64) *VarianceThreshold {
+	return &VarianceThreshold{threshold: threshold}
+}
+
+// Fit variances of features of points
+func (vt *VarianceThreshold) Fit(points []knn.Point) {
+	data := make([]knn.DataPoint, len(points))
+	for i, p := range points {
+		data[i] = knn.NewDataPoint(nil, p)
+	}
+	vt.variances = Variance(data)
+	vt.dim = len(vt.variances)
+	vt.support = make([]int, 0, vt.dim)
+	for d, v := range vt.variances {
+		if v > vt.threshold {
+			vt.support = append(vt.support, d)
+		}
+	}
+}
+
+// Selected features of point
+func (vt *VarianceThreshold) Transform(point knn.Point) knn.Point {
+	return project(point, vt.support, vt.dim)
+}
+
+// Indices of selected features in increasing order
+func (vt *VarianceThreshold) Support() []int {
+	return vt.support
+}
+
+// Variances of fitted features
+func (vt *VarianceThreshold) Variances() []float64 {
+	return vt.variances
+}
+
+// Selector of the k features of greatest score
+type SelectKBest struct {
+	score   ScoreFunc
+	k       int
+	dim     int
+	support []int
+	scores  []float64
+}
+
+// Create selector of the k features of greatest score, like MutualInformation
+func NewSelectKBest(score ScoreFunc, k int) *SelectKBest {
+	if k < 1 {
+		panic(ErrKNotValid)
+	}
+	return &SelectKBest{score: score, k: k}
+}
+
+// Fit scores of features of labeled data points
+func (sk *SelectKBest) Fit(data []knn.DataPoint) {
+	sk.scores = sk.score(data)
+	sk.dim = len(sk.scores)
+	if sk.k > sk.dim {
+		panic(ErrKNotValid)
+	}
+	order := make([]int, sk.dim)
+	for d := range order {
+		order[d] = d
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sk.scores[order[a]] > sk.scores[order[b]]
+	})
+	sk.support = append([]int(nil), order[:sk.k]...)
+	sort.Ints(sk.support)
+}
+
+// Selected features of point
+func (sk *SelectKBest) Transform(point knn.Point) knn.Point {
+	return project(point, sk.support, sk.dim)
+}
+
+// Selected features of data points
+func (sk *SelectKBest) TransformData(data []knn.DataPoint) []knn.DataPoint {
+	return projectAll(data, sk.support, sk.dim)
+}
+
+// Indices of selected features in increasing order
+func (sk *SelectKBest) Support() []int {
+	return sk.support
+}
+
+// Scores of fitted features
+func (sk *SelectKBest) Scores() []float64 {
+	return sk.scores
+}
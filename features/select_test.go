@@ -0,0 +1,35 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func TestVarianceThreshold(t *testing.T) {
+	data := informative(100, 2)
+	points := make([]knn.Point, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+	}
+	vt := NewVarianceThreshold(0)
+	vt.Fit(points)
+	if s := vt.Support(); len(s) != 4 || s[3] != 3 {
+		t.Errorf("VarianceThreshold failed. Expected features [0 1 2 3], but got %v", s)
+	}
+	if p := vt.Transform(points[0]); len(p) != 4 || p[0] != points[0][0] {
+		t.Errorf("Transform failed. Expected %v, but got %v", points[0][:4], p)
+	}
+}
+
+func TestSelectKBest(t *testing.T) {
+	data := informative(400, 3)
+	sk := NewSelectKBest(MutualInformation(10), 2)
+	sk.Fit(data)
+	if s := sk.Support(); s[0] != 0 || s[1] != 1 {
+		t.Errorf("SelectKBest failed. Expected features [0 1], but got %v", s)
+	}
+	if out := sk.TransformData(data); len(out[0].Point()) != 2 || out[0].Label() != data[0].Label() {
+		t.Errorf("TransformData failed. Expected data points with 2 features, but got %v", out[0].Point())
+	}
+}
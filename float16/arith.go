@@ -0,0 +1,86 @@
+package float16
+
+import "math"
+
+// Sum of values
+//
+// Every Float16 is exact in float64 and float64 has more than twice the precision of Float16,
+// so basic operations computed in float64 and rounded once are correctly rounded.
+func (f16 Float16) Add(other Float16) Float16 {
+	return FF64(f16.ToF64() + other.ToF64())
+}
+
+// Difference of values
+func (f16 Float16) Sub(other Float16) Float16 {
+	return FF64(f16.ToF64() - other.ToF64())
+}
+
+// Product of values
+func (f16 Float16) Mul(other Float16) Float16 {
+	return FF64(f16.ToF64() * other.ToF64())
+}
+
+// Quotient of values
+func (f16 Float16) Div(other Float16) Float16 {
+	return FF64(f16.ToF64() / other.ToF64())
+}
+
+// Value with opposite sign
+func (f16 Float16) Neg() Float16 {
+	return f16 ^ signMask
+}
+
+// Fused multiply-add x*y + z with a single rounding
+func FMA(x, y, z Float16) Float16 {
+	// product of Float16 values is exact in float64
+	p, c := x.ToF64()*y.ToF64(), z.ToF64()
+	s := p + c
+	if math.IsInf(s, 0) || math.IsNaN(s) {
+		return FF64(s)
+	}
+	// error of the sum, so the rounding of s can be made sticky (round to odd) and the last rounding is right
+	b := s - p
+	e := (p - (s - b)) + (c - b)
+	if e != 0 {
+		bits := math.Float64bits(s)
+		if bits&1 == 0 {
+			if (e > 0) == (s > 0) {
+				bits++
+			} else {
+				bits--
+			}
+			s = math.Float64frombits(bits)
+		}
+	}
+	return FF64(s)
+}
+
+// Test if values are equal, zeros of any sign are equal and NaN is not equal to any value
+func (f16 Float16) Equal(other Float16) bool {
+	if f16.IsNaN() || other.IsNaN() {
+		return false
+	}
+	return f16 == other || (f16|other)&^signMask == 0
+}
+
+// Test if value is lesser than other, it is false if any value is NaN
+func (f16 Float16) Less(other Float16) bool {
+	if f16.IsNaN() || other.IsNaN() {
+		return false
+	}
+	return f16.orderKey() < other.orderKey()
+}
+
+// Test if value is greater than other, it is false if any value is NaN
+func (f16 Float16) Greater(other Float16) bool {
+	return other.Less(f16)
+}
+
+// key with the order of values, zeros of any sign have the same key
+func (f16 Float16) orderKey() int32 {
+	mag := int32(f16 &^ signMask)
+	if f16&signMask != 0 {
+		return -mag
+	}
+	return mag
+}
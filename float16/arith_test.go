@@ -0,0 +1,62 @@
+package float16
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestArithmetic(t *testing.T) {
+	one, two, three := Float16(0x3C00), Float16(0x4000), Float16(0x4200)
+	if got := one.Add(two); got != three {
+		t.Errorf("Add failed. Expected %#x, but got %#x", three, got)
+	}
+	if got := three.Sub(two); got != one {
+		t.Errorf("Sub failed. Expected %#x, but got %#x", one, got)
+	}
+	if got := three.Mul(two); got.ToF64() != 6 {
+		t.Errorf("Mul failed. Expected 6, but got %v", got.ToF64())
+	}
+	if got := one.Div(three); got != 0x3555 {
+		t.Errorf("Div failed. Expected 0x3555, but got %#x", got)
+	}
+	if got := one.Neg(); got.ToF64() != -1 {
+		t.Errorf("Neg failed. Expected -1, but got %v", got.ToF64())
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		a, b := Float16(rnd.Intn(0x7C00)), Float16(rnd.Intn(0x7C00))
+		sum := a.Add(b)
+		// correctly rounded sum is not farther than its neighbors
+		exact := a.ToF64() + b.ToF64()
+		dif := math.Abs(sum.ToF64() - exact)
+		if sum != InfPos && (dif > math.Abs((sum+1).ToF64()-exact) || sum > 0 && dif > math.Abs((sum-1).ToF64()-exact)) {
+			t.Fatalf("Add failed. Sum of %#x and %#x is not the nearest", a, b)
+		}
+	}
+}
+
+func TestFMA(t *testing.T) {
+	x := Float16(0x3C01)   // 1 + 2^-10
+	z := Float16(0xBC02)   // -(1 + 2^-9)
+	exact := Float16(0x10) // 2^-20
+	if got := FMA(x, x, z); got != exact {
+		t.Errorf("FMA failed. Expected %#x, but got %#x", exact, got)
+	}
+	if got := x.Mul(x).Add(z); got != 0 {
+		t.Errorf("Mul and Add failed. Expected 0, but got %#x", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	negZero := Float16(0x8000)
+	if !negZero.Equal(0) || negZero.Less(0) {
+		t.Errorf("Equal failed. Expected -0 == 0")
+	}
+	if NaN.Equal(NaN) || NaN.Less(0) || Float16(0).Less(NaN) {
+		t.Errorf("Compare failed. NaN must be unordered")
+	}
+	if !InfNeg.Less(0xBC00) || !Float16(0xBC00).Less(0x3C00) || !InfPos.Greater(0x7BFF) {
+		t.Errorf("Less failed. Unexpected order")
+	}
+}
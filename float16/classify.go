@@ -0,0 +1,50 @@
+package float16
+
+const (
+	MaxValue        Float16 = 0x7BFF //greatest finite value, 65504
+	MinNormal       Float16 = 0x0400 //smallest positive normal value, 2^-14
+	SmallestNonzero Float16 = 0x0001 //smallest positive subnormal value, 2^-24
+)
+
+// Test if value is not a number
+func (f16 Float16) IsNaN() bool {
+	return f16&expMask == expMask && f16&mantissaMask != 0
+}
+
+// Test if value is an infinity, by sign if sign > 0 positive infinity, if sign < 0 negative infinity and any infinity if sign == 0
+func (f16 Float16) IsInf(sign int) bool {
+	return sign >= 0 && f16 == InfPos || sign <= 0 && f16 == InfNeg
+}
+
+// Test if sign bit is set, it is true for negative values and negative zero
+func (f16 Float16) Signbit() bool {
+	return f16&signMask != 0
+}
+
+// Absolute value
+func (f16 Float16) Abs() Float16 {
+	return f16 &^ signMask
+}
+
+// Value with magnitude of f16 and sign of sign
+func Copysign(f16, sign Float16) Float16 {
+	return f16&^signMask | sign&signMask
+}
+
+// Next representable value after x towards y
+//
+// It returns x if x equals y and NaN if any value is NaN
+func Nextafter(x, y Float16) Float16 {
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return NaN
+	case x.Equal(y):
+		return x
+	case x.Abs() == 0:
+		return Copysign(SmallestNonzero, y)
+	case y.Greater(x) == !x.Signbit():
+		// magnitude grows
+		return x + 1
+	}
+	return x - 1
+}
@@ -0,0 +1,52 @@
+package float16
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	if !NaN.IsNaN() || InfPos.IsNaN() || MaxValue.IsNaN() {
+		t.Errorf("IsNaN failed")
+	}
+	if !InfPos.IsInf(1) || InfPos.IsInf(-1) || !InfNeg.IsInf(0) || MaxValue.IsInf(0) {
+		t.Errorf("IsInf failed")
+	}
+	if !Float16(0x8000).Signbit() || Float16(0).Signbit() {
+		t.Errorf("Signbit failed")
+	}
+	if got := Float16(0xBC00).Abs(); got != 0x3C00 {
+		t.Errorf("Abs failed. Expected 0x3c00, but got %#x", got)
+	}
+	if got := Copysign(0x3C00, InfNeg); got != 0xBC00 {
+		t.Errorf("Copysign failed. Expected 0xbc00, but got %#x", got)
+	}
+	if MaxValue.ToF64() != 65504 || MinNormal.ToF64() != 0x1p-14 || SmallestNonzero.ToF64() != 0x1p-24 {
+		t.Errorf("Constants failed. Unexpected values %v, %v and %v", MaxValue.ToF64(), MinNormal.ToF64(), SmallestNonzero.ToF64())
+	}
+}
+
+func TestNextafter(t *testing.T) {
+	cases := []struct {
+		x, y, expected Float16
+	}{
+		{0x3C00, InfPos, 0x3C01},
+		{0x3C00, 0, 0x3BFF},
+		{0xBC00, InfNeg, 0xBC01},
+		{0xBC00, InfPos, 0xBBFF},
+		{0, InfNeg, 0x8001},
+		{0x8001, InfPos, 0x8000},
+		{MaxValue, InfPos, InfPos},
+		{0x3C00, 0x3C00, 0x3C00},
+	}
+	for _, c := range cases {
+		if got := Nextafter(c.x, c.y); got != c.expected {
+			t.Errorf("Nextafter failed. Expected %#x after %#x towards %#x, but got %#x", c.expected, c.x, c.y, got)
+		}
+	}
+	// agrees with float64 values
+	x := Float16(0x3555)
+	if got := Nextafter(x, InfPos).ToF64(); got <= x.ToF64() || got-x.ToF64() != math.Ldexp(1, -12) {
+		t.Errorf("Nextafter failed. Unexpected step from %v to %v", x.ToF64(), got)
+	}
+}
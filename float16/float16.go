@@ -19,62 +19,15 @@ var ErrOverflow = errors.New("float16 overflow")
 
 type Float16 uint16
 
+// Convert float32 to Float16 rounding to nearest, ties to even
 func FF32(value float32) Float16 {
-	bits := math.Float32bits(value)
-	sign := bits >> 31
-	exp := (bits >> 23) & 0xff
-	frac := bits & 0x7fffff
-	// NaN or Inf
-	if exp == 0xff {
-		if frac != 0 {
-			return NaN
-		}
-		return Float16(sign<<15) | InfPos
-	}
-	//Denormalized
-	if exp == 0 {
-		frac >>= 13
-		return Float16(sign<<15) | Float16(frac)
-	}
-	sexp := int32(exp) - 127 + 15
-	if sexp >= 0x1f { //Too large, return Inf
-		return Float16(sign<<15) | InfPos
-	}
-	if sexp <= 0 { //Too small, return 0
-		frac >>= -sexp + 1
-		return Float16(sign<<15) | Float16(frac>>13)
-	}
-	exp = uint32(sexp)
-	return Float16(sign<<15) | Float16(exp<<10) | Float16(frac>>13)
+	// every float32 is exact in float64, so there is a single rounding
+	return FromF64(float64(value), ToNearestEven)
 }
 
+// Convert float64 to Float16 rounding to nearest, ties to even
 func FF64(value float64) Float16 {
-	bits := math.Float64bits(value)
-	sign := bits >> 63
-	exp := (bits >> 52) & 0x7ff
-	frac := bits & 0xfffffffffffff
-	// NaN or Inf
-	if exp == 0x7ff {
-		if frac != 0 {
-			return NaN
-		}
-		return Float16(sign<<15) | InfPos
-	}
-	// Denormalized
-	if exp == 0 {
-		frac >>= 42
-		return Float16(sign<<15) | Float16(frac)
-	}
-	sexp := int32(exp) - 1023 + 15
-	if sexp >= 0x1f { // Too large, return Inf
-		return Float16(sign<<15) | InfPos
-	}
-	if sexp <= 0 { // Too small, return 0
-		frac >>= -sexp + 1
-		return Float16(sign<<15) | Float16(frac>>13)
-	}
-	exp = uint64(sexp)
-	return Float16(sign<<15) | Float16(exp<<10) | Float16(frac>>42)
+	return FromF64(value, ToNearestEven)
 }
 
 func (f16 Float16) ToF32() float32 {
@@ -88,8 +41,8 @@ func (f16 Float16) ToF32() float32 {
 		}
 		return math.Float32frombits(uint32(sign<<31 | 0xff<<23))
 	}
-	if frac == 0 && exp == 0 {
-		return 0.0
+	if frac == 0 && exp == 0 { //Zero keeps its sign
+		return math.Float32frombits(sign << 31)
 	}
 	if exp == 0 { //Denormalized
 		for frac&0x400 == 0 {
@@ -114,8 +67,8 @@ func (f16 Float16) ToF64() float64 {
 		}
 		return math.Float64frombits(uint64(sign<<63 | 0x7ff<<52))
 	}
-	if frac == 0 && exp == 0 {
-		return 0.0
+	if frac == 0 && exp == 0 { // Zero keeps its sign
+		return math.Float64frombits(sign << 63)
 	}
 	if exp == 0 { // Denormalized
 		for frac&0x400 == 0 {
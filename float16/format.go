@@ -0,0 +1,112 @@
+package float16
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Parse a Float16 from a decimal or hexadecimal float string, rounding to nearest, ties to even
+//
+// Out of range values return an infinity and an error like strconv.ParseFloat
+func ParseFloat16(s string) (Float16, error) {
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil && !math.IsInf(value, 0) {
+		return 0, err
+	}
+	f16 := FF64(value)
+	if f16.IsInf(0) && !math.IsInf(value, 0) && err == nil {
+		err = &strconv.NumError{Func: "ParseFloat16", Num: s, Err: strconv.ErrRange}
+	}
+	return resolveTie(s, value, f16), err
+}
+
+// the float64 of s can be a midpoint between two Float16 values although s is not, then the exact value of s decides
+func resolveTie(s string, value float64, f16 Float16) Float16 {
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return f16
+	}
+	lo := FromF64(value, ToZero)
+	hi := Nextafter(lo, Copysign(InfPos, lo))
+	hiValue := hi.ToF64()
+	if hi.IsInf(0) {
+		hiValue = math.Copysign(65536, value)
+	}
+	mid := (lo.ToF64() + hiValue) / 2
+	if value != mid {
+		return f16
+	}
+	exact, ok := new(big.Rat).SetString(strings.TrimPrefix(s, "+"))
+	if !ok {
+		return f16
+	}
+	cmp := exact.Cmp(new(big.Rat).SetFloat64(mid))
+	if value < 0 {
+		cmp = -cmp
+	}
+	switch {
+	case cmp > 0:
+		return hi
+	case cmp < 0:
+		return lo
+	}
+	return f16
+}
+
+// Shortest decimal representation that parses back to the same value
+func (f16 Float16) String() string {
+	value := f16.ToF64()
+	switch {
+	case f16.IsNaN():
+		return "NaN"
+	case f16.IsInf(1):
+		return "+Inf"
+	case f16.IsInf(-1):
+		return "-Inf"
+	}
+	for prec := 1; prec < 5; prec++ {
+		s := strconv.FormatFloat(value, 'g', prec, 64)
+		if parsed, err := ParseFloat16(s); err == nil && parsed == f16 {
+			return s
+		}
+	}
+	return strconv.FormatFloat(value, 'g', 5, 64)
+}
+
+// Format value for fmt, float verbs format the float64 value, v and s the shortest representation
+// and integer verbs the raw bits
+func (f16 Float16) Format(f fmt.State, verb rune) {
+	directive := formatDirective(f, verb)
+	switch verb {
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		fmt.Fprintf(f, directive, f16.ToF64())
+	case 'b', 'd', 'o', 'O', 'x', 'X':
+		fmt.Fprintf(f, directive, uint16(f16))
+	case 'v', 's':
+		fmt.Fprintf(f, strings.Replace(directive, string(verb), "s", 1), f16.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(float16.Float16=%s)", verb, f16.String())
+	}
+}
+
+// rebuild format directive of fmt state
+func formatDirective(f fmt.State, verb rune) string {
+	var sb strings.Builder
+	sb.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			sb.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		sb.WriteString(strconv.Itoa(width))
+	}
+	if prec, ok := f.Precision(); ok {
+		sb.WriteByte('.')
+		sb.WriteString(strconv.Itoa(prec))
+	}
+	sb.WriteRune(verb)
+	return sb.String()
+}
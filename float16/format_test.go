@@ -0,0 +1,76 @@
+package float16
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseFloat16(t *testing.T) {
+	cases := []struct {
+		s        string
+		expected Float16
+	}{
+		{"1", 0x3C00},
+		{"-2.5", 0xC100},
+		{"65504", MaxValue},
+		{"0x1p-24", SmallestNonzero},
+		{"inf", InfPos},
+		// float64 of this string is the midpoint of 0x3c00 and 0x3c01, but the string is above
+		{"1.00048828125000000000000001", 0x3C01},
+		{"1.00048828125", 0x3C00},
+	}
+	for _, c := range cases {
+		got, err := ParseFloat16(c.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.expected {
+			t.Errorf("ParseFloat16 failed. Expected %#x for %q, but got %#x", c.expected, c.s, got)
+		}
+	}
+	if got, err := ParseFloat16("1e6"); err == nil || got != InfPos {
+		t.Errorf("ParseFloat16 failed. Expected infinity and range error, but got %#x and %v", got, err)
+	}
+	if _, err := ParseFloat16("one"); err == nil {
+		t.Errorf("ParseFloat16 failed. Expected syntax error")
+	}
+}
+
+func TestString(t *testing.T) {
+	for i := 0; i < 1<<16; i++ {
+		f16 := Float16(i)
+		if f16.IsNaN() {
+			continue
+		}
+		parsed, err := ParseFloat16(f16.String())
+		if err != nil || parsed != f16 {
+			t.Fatalf("String failed. %q parses to %#x instead of %#x", f16.String(), parsed, i)
+		}
+	}
+	if s := FF64(0.1).String(); s != "0.1" {
+		t.Errorf("String failed. Expected 0.1, but got %s", s)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	x := FF64(1.5)
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{"%v", "1.5"},
+		{"%6v", "   1.5"},
+		{"%.3f", "1.500"},
+		{"%e", "1.500000e+00"},
+		{"%#x", "0x3e00"},
+		{"%d", "15872"},
+	}
+	for _, c := range cases {
+		if got := fmt.Sprintf(c.format, x); got != c.expected {
+			t.Errorf("Format failed. Expected %q for %q, but got %q", c.expected, c.format, got)
+		}
+	}
+	if got := fmt.Sprint([]Float16{x, NaN}); got != "[1.5 NaN]" {
+		t.Errorf("Format failed. Expected [1.5 NaN], but got %q", got)
+	}
+}
@@ -0,0 +1,35 @@
+package float16
+
+// Half precision format, Float16 and bfloat16.BFloat16 satisfy it so kernels can be written once for both
+//
+// FromF32 ignores its receiver, a zero value of T converts values: var zero T; zero.FromF32(v)
+type Half[T any] interface {
+	~uint16
+	ToF32() float32
+	FromF32(value float32) T
+}
+
+// Convert float32 to Float16 rounding to nearest, the receiver is ignored so it satisfies Half
+func (f16 Float16) FromF32(value float32) Float16 {
+	return FF32(value)
+}
+
+// Convert values between half precision formats, dst must be as long as src
+func Convert[D Half[D], S Half[S]](dst []D, src []S) {
+	if len(dst) != len(src) {
+		panic(ErrLengthMismatch)
+	}
+	var zero D
+	for i, v := range src {
+		dst[i] = zero.FromF32(v.ToF32())
+	}
+}
+
+// Sum of half precision values accumulated in float32
+func SumHalf[T Half[T]](x []T) float32 {
+	sum := float32(0)
+	for _, v := range x {
+		sum += v.ToF32()
+	}
+	return sum
+}
@@ -0,0 +1,98 @@
+package float16
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+)
+
+var ErrInvalidJSON = errors.New("float16 json value is not a number or a special value string")
+
+// Marshal value as a JSON number, NaN and infinities are marshaled as the strings "NaN", "+Inf" and "-Inf"
+func (f16 Float16) MarshalJSON() ([]byte, error) {
+	if f16.IsNaN() || f16.IsInf(0) {
+		return json.Marshal(f16.String())
+	}
+	return []byte(f16.String()), nil
+}
+
+// Unmarshal value from a JSON number or the strings "NaN", "+Inf", "-Inf"
+func (f16 *Float16) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		value, err := strconv.ParseFloat(s, 64)
+		if err != nil || !math.IsNaN(value) && !math.IsInf(value, 0) {
+			return ErrInvalidJSON
+		}
+		*f16 = FF64(value)
+		return nil
+	}
+	value, err := ParseFloat16(string(data))
+	if err != nil {
+		return err
+	}
+	*f16 = value
+	return nil
+}
+
+// Marshal value as its shortest decimal text
+func (f16 Float16) MarshalText() ([]byte, error) {
+	return []byte(f16.String()), nil
+}
+
+// Unmarshal value from decimal text
+func (f16 *Float16) UnmarshalText(text []byte) error {
+	value, err := ParseFloat16(string(text))
+	if err != nil {
+		return err
+	}
+	*f16 = value
+	return nil
+}
+
+// Marshal value as two little-endian bytes
+func (f16 Float16) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 2)
+	binary.LittleEndian.PutUint16(data, uint16(f16))
+	return data, nil
+}
+
+// Unmarshal value from two little-endian bytes
+func (f16 *Float16) UnmarshalBinary(data []byte) error {
+	if len(data) != 2 {
+		return io.ErrUnexpectedEOF
+	}
+	*f16 = Float16(binary.LittleEndian.Uint16(data))
+	return nil
+}
+
+// Write values as little-endian bytes
+func Write(w io.Writer, values []Float16) error {
+	data := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(v))
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// Read len(values) values from little-endian bytes
+func Read(r io.Reader, values []Float16) error {
+	data := make([]byte, 2*len(values))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	for i := range values {
+		values[i] = Float16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return nil
+}
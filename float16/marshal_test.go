@@ -0,0 +1,63 @@
+package float16
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	type config struct {
+		Scale Float16   `json:"scale"`
+		Bias  []Float16 `json:"bias"`
+	}
+	in := config{Scale: FF64(0.1), Bias: []Float16{FF64(-2), InfPos, NaN}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"scale":0.1,"bias":[-2,"+Inf","NaN"]}` {
+		t.Errorf("MarshalJSON failed. Unexpected json %s", data)
+	}
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Scale != in.Scale || out.Bias[0] != in.Bias[0] || out.Bias[1] != InfPos || !out.Bias[2].IsNaN() {
+		t.Errorf("UnmarshalJSON failed. Unexpected config %v", out)
+	}
+	if err := json.Unmarshal([]byte(`{"scale":"big"}`), &out); err == nil {
+		t.Errorf("UnmarshalJSON failed. Expected error for invalid string")
+	}
+}
+
+func TestTextAndBinary(t *testing.T) {
+	x := FF64(3.25)
+	text, _ := x.MarshalText()
+	var y Float16
+	if err := y.UnmarshalText(text); err != nil || y != x {
+		t.Errorf("UnmarshalText failed. Expected %v, but got %v", x, y)
+	}
+	data, _ := x.MarshalBinary()
+	if data[0] != byte(x) || data[1] != byte(x>>8) {
+		t.Errorf("MarshalBinary failed. Expected little-endian bytes, but got %v", data)
+	}
+	var z Float16
+	if err := z.UnmarshalBinary(data); err != nil || z != x {
+		t.Errorf("UnmarshalBinary failed. Expected %v, but got %v", x, z)
+	}
+	values := []Float16{1, 0x3C00, MaxValue}
+	var buf bytes.Buffer
+	if err := Write(&buf, values); err != nil {
+		t.Fatal(err)
+	}
+	read := make([]Float16, 3)
+	if err := Read(&buf, read); err != nil {
+		t.Fatal(err)
+	}
+	for i := range values {
+		if read[i] != values[i] {
+			t.Errorf("Read failed. Expected %#x at %d, but got %#x", values[i], i, read[i])
+		}
+	}
+}
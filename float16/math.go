@@ -0,0 +1,31 @@
+package float16
+
+import "math"
+
+// Functions are computed in float64, whose precision makes a single rounding to Float16 correct
+// except in extremely rare cases for transcendental functions.
+
+// Square root
+func Sqrt(x Float16) Float16 {
+	return FF64(math.Sqrt(x.ToF64()))
+}
+
+// Base e exponential
+func Exp(x Float16) Float16 {
+	return FF64(math.Exp(x.ToF64()))
+}
+
+// Natural logarithm
+func Log(x Float16) Float16 {
+	return FF64(math.Log(x.ToF64()))
+}
+
+// Hyperbolic tangent
+func Tanh(x Float16) Float16 {
+	return FF64(math.Tanh(x.ToF64()))
+}
+
+// Power x^y
+func Pow(x, y Float16) Float16 {
+	return FF64(math.Pow(x.ToF64(), y.ToF64()))
+}
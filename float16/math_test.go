@@ -0,0 +1,46 @@
+package float16
+
+import (
+	"math"
+	"testing"
+)
+
+// test that result is the nearest Float16 of exact value
+func nearest(result Float16, exact float64) bool {
+	if result.IsNaN() {
+		return math.IsNaN(exact)
+	}
+	if result.IsInf(0) {
+		return math.Abs(exact) >= 65520
+	}
+	dif := math.Abs(result.ToF64() - exact)
+	return dif <= math.Abs(Nextafter(result, InfPos).ToF64()-exact) && dif <= math.Abs(Nextafter(result, InfNeg).ToF64()-exact)
+}
+
+func TestMathFunctions(t *testing.T) {
+	funcs := []struct {
+		name string
+		f16  func(Float16) Float16
+		f64  func(float64) float64
+	}{
+		{"Sqrt", Sqrt, math.Sqrt},
+		{"Exp", Exp, math.Exp},
+		{"Log", Log, math.Log},
+		{"Tanh", Tanh, math.Tanh},
+	}
+	for _, fn := range funcs {
+		for i := 0; i < 1<<16; i++ {
+			x := Float16(i)
+			if !nearest(fn.f16(x), fn.f64(x.ToF64())) {
+				t.Fatalf("%s failed. %#x is not the nearest value of %v for %v", fn.name, fn.f16(x), fn.f64(x.ToF64()), x.ToF64())
+			}
+		}
+	}
+	two, three := FF64(2), FF64(3)
+	if got := Pow(two, three); got.ToF64() != 8 {
+		t.Errorf("Pow failed. Expected 8, but got %v", got.ToF64())
+	}
+	if got := Sqrt(FF64(-1)); !got.IsNaN() {
+		t.Errorf("Sqrt failed. Expected NaN, but got %v", got.ToF64())
+	}
+}
@@ -0,0 +1,88 @@
+package float16
+
+import "sort"
+
+// Compare values, it returns -1 if a < b, 0 if a == b and +1 if a > b
+//
+// NaN is lesser than any other value and equal to NaN, zeros of any sign are equal
+func Compare(a, b Float16) int {
+	aNaN, bNaN := a.IsNaN(), b.IsNaN()
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN || a.Less(b):
+		return -1
+	case bNaN || b.Less(a):
+		return 1
+	}
+	return 0
+}
+
+// Lesser value, NaN if any value is NaN and -0 is lesser than +0
+func Min(a, b Float16) Float16 {
+	switch {
+	case a.IsNaN() || b.IsNaN():
+		return NaN
+	case a.Equal(b):
+		// zeros of different sign
+		return a | b&signMask
+	case a.Less(b):
+		return a
+	}
+	return b
+}
+
+// Greater value, NaN if any value is NaN and +0 is greater than -0
+func Max(a, b Float16) Float16 {
+	switch {
+	case a.IsNaN() || b.IsNaN():
+		return NaN
+	case a.Equal(b):
+		return a & (b | ^signMask)
+	case a.Greater(b):
+		return a
+	}
+	return b
+}
+
+// key with the IEEE 754 total order of bits: -NaN < -Inf < ... < -0 < +0 < ... < +Inf < +NaN
+func (f16 Float16) totalKey() uint16 {
+	if f16&signMask != 0 {
+		return ^uint16(f16)
+	}
+	return uint16(f16 | signMask)
+}
+
+// Test if a precedes b in IEEE 754 total order, it is a strict order of every bit pattern suitable for sort.Slice
+func TotalOrder(a, b Float16) bool {
+	return a.totalKey() < b.totalKey()
+}
+
+// Sort values in total order, so negative NaNs go first and positive NaNs go last
+func Sort(values []Float16) {
+	sort.Slice(values, func(i, j int) bool {
+		return TotalOrder(values[i], values[j])
+	})
+}
+
+// Index of the greatest value ignoring NaN, first index on ties and -1 if every value is NaN
+func ArgMax(values []Float16) int {
+	best := -1
+	for i, v := range values {
+		if !v.IsNaN() && (best < 0 || v.Greater(values[best])) {
+			best = i
+		}
+	}
+	return best
+}
+
+// Index of the lesser value ignoring NaN, first index on ties and -1 if every value is NaN
+func ArgMin(values []Float16) int {
+	best := -1
+	for i, v := range values {
+		if !v.IsNaN() && (best < 0 || v.Less(values[best])) {
+			best = i
+		}
+	}
+	return best
+}
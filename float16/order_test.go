@@ -0,0 +1,45 @@
+package float16
+
+import "testing"
+
+func TestMinMaxCompare(t *testing.T) {
+	one, two := FF64(1), FF64(2)
+	negZero := Float16(0x8000)
+	if Min(one, two) != one || Max(one, two) != two {
+		t.Errorf("Min or Max failed")
+	}
+	if Min(0, negZero) != negZero || Max(negZero, 0) != 0 || Min(negZero, 0) != negZero || Max(0, negZero) != 0 {
+		t.Errorf("Min or Max failed. Unexpected sign of zero")
+	}
+	if !Min(NaN, one).IsNaN() || !Max(one, NaN).IsNaN() {
+		t.Errorf("Min or Max failed. Expected NaN")
+	}
+	if Compare(one, two) != -1 || Compare(two, one) != 1 || Compare(negZero, 0) != 0 || Compare(NaN, InfNeg) != -1 || Compare(NaN, NaN) != 0 {
+		t.Errorf("Compare failed")
+	}
+}
+
+func TestTotalOrder(t *testing.T) {
+	negNaN := NaN | signMask
+	values := []Float16{NaN, FF64(1), 0, InfNeg, negNaN, Float16(0x8000), FF64(-2), InfPos}
+	Sort(values)
+	expected := []Float16{negNaN, InfNeg, FF64(-2), 0x8000, 0, FF64(1), InfPos, NaN}
+	for i := range expected {
+		if values[i] != expected[i] {
+			t.Fatalf("Sort failed. Expected %#x at %d, but got %#x", expected[i], i, values[i])
+		}
+	}
+}
+
+func TestArgMaxMin(t *testing.T) {
+	values := []Float16{NaN, FF64(3), FF64(-1), FF64(3), NaN}
+	if i := ArgMax(values); i != 1 {
+		t.Errorf("ArgMax failed. Expected 1, but got %d", i)
+	}
+	if i := ArgMin(values); i != 2 {
+		t.Errorf("ArgMin failed. Expected 2, but got %d", i)
+	}
+	if i := ArgMax([]Float16{NaN}); i != -1 {
+		t.Errorf("ArgMax failed. Expected -1, but got %d", i)
+	}
+}
@@ -0,0 +1,49 @@
+package float16
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Uniform value in [0, 1) from the given source
+//
+// Values are multiples of 2^-11, every one of them is exact in Float16 and equally likely
+func Uniform(rnd *rand.Rand) Float16 {
+	return FF64(math.Ldexp(float64(rnd.Intn(1<<11)), -11))
+}
+
+// pair of independent standard normal values by Box–Muller transform
+func boxMuller(rnd *rand.Rand) (float64, float64) {
+	u1 := 1 - rnd.Float64() // in (0, 1], so logarithm is finite
+	u2 := rnd.Float64()
+	r := math.Sqrt(-2 * math.Log(u1))
+	return r * math.Cos(2*math.Pi*u2), r * math.Sin(2*math.Pi*u2)
+}
+
+// Normal value with the given mean and standard deviation from the given source
+func Normal(rnd *rand.Rand, mean, std float64) Float16 {
+	z, _ := boxMuller(rnd)
+	return FF64(mean + z*std)
+}
+
+// Fill values with uniform values in [lo, hi)
+func FillUniform(rnd *rand.Rand, values []Float16, lo, hi float64) {
+	for i := range values {
+		values[i] = FromF64(lo+(hi-lo)*rnd.Float64(), ToNegativeInf)
+		// rounding down can't reach hi, but it can fall under lo
+		if values[i].ToF64() < lo {
+			values[i] = Nextafter(values[i], InfPos)
+		}
+	}
+}
+
+// Fill values with normal values of the given mean and standard deviation, both values of every transform are used
+func FillNormal(rnd *rand.Rand, values []Float16, mean, std float64) {
+	for i := 0; i < len(values); i += 2 {
+		z1, z2 := boxMuller(rnd)
+		values[i] = FF64(mean + z1*std)
+		if i+1 < len(values) {
+			values[i+1] = FF64(mean + z2*std)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package float16
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestUniform(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sum := 0.0
+	for i := 0; i < 10000; i++ {
+		v := Uniform(rnd).ToF64()
+		if v < 0 || v >= 1 {
+			t.Fatalf("Uniform failed. Value %v is not in [0, 1)", v)
+		}
+		sum += v
+	}
+	if mean := sum / 10000; math.Abs(mean-0.5) > 0.02 {
+		t.Errorf("Uniform failed. Expected mean near 0.5, but got %v", mean)
+	}
+	values := make([]Float16, 1000)
+	FillUniform(rnd, values, -3, -0.1)
+	for _, v := range values {
+		if v.ToF64() < -3 || v.ToF64() >= -0.1 {
+			t.Fatalf("FillUniform failed. Value %v is not in [-3, -0.1)", v)
+		}
+	}
+}
+
+func TestNormal(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	values := make([]Float16, 20001)
+	FillNormal(rnd, values, 2, 0.5)
+	values[0] = Normal(rnd, 2, 0.5)
+	sum, sq := 0.0, 0.0
+	for _, v := range values {
+		sum += v.ToF64()
+		sq += v.ToF64() * v.ToF64()
+	}
+	mean := sum / float64(len(values))
+	std := math.Sqrt(sq/float64(len(values)) - mean*mean)
+	if math.Abs(mean-2) > 0.02 || math.Abs(std-0.5) > 0.02 {
+		t.Errorf("FillNormal failed. Expected mean 2 and std 0.5, but got %v and %v", mean, std)
+	}
+}
@@ -0,0 +1,92 @@
+package float16
+
+import "math"
+
+// Rounding mode of conversions to Float16
+type RoundingMode int
+
+const (
+	ToNearestEven RoundingMode = iota //to nearest value, ties to even significand
+	ToNearestAway                     //to nearest value, ties away from zero
+	ToZero                            //towards zero, truncation
+	AwayFromZero                      //away from zero
+	ToNegativeInf                     //towards negative infinity, floor
+	ToPositiveInf                     //towards positive infinity, ceil
+)
+
+// round magnitude of a scaled value to an integer
+func (mode RoundingMode) round(mag float64, negative bool) float64 {
+	switch mode {
+	case ToNearestAway:
+		return math.Round(mag)
+	case ToZero:
+		return math.Trunc(mag)
+	case AwayFromZero:
+		return math.Ceil(mag)
+	case ToNegativeInf:
+		if negative {
+			return math.Ceil(mag)
+		}
+		return math.Trunc(mag)
+	case ToPositiveInf:
+		if negative {
+			return math.Trunc(mag)
+		}
+		return math.Ceil(mag)
+	}
+	return math.RoundToEven(mag)
+}
+
+// overflowing values become infinity unless mode rounds their magnitude down
+func (mode RoundingMode) overflow(negative bool) Float16 {
+	down := mode == ToZero || mode == ToNegativeInf && !negative || mode == ToPositiveInf && negative
+	if down {
+		if negative {
+			return MaxValue | signMask
+		}
+		return MaxValue
+	}
+	if negative {
+		return InfNeg
+	}
+	return InfPos
+}
+
+// Convert float32 to Float16 with the given rounding mode
+func FromF32(value float32, mode RoundingMode) Float16 {
+	return FromF64(float64(value), mode)
+}
+
+// Convert float64 to Float16 with the given rounding mode
+func FromF64(value float64, mode RoundingMode) Float16 {
+	if math.IsNaN(value) {
+		return NaN
+	}
+	negative := math.Signbit(value)
+	sign := Float16(0)
+	if negative {
+		sign = signMask
+		value = -value
+	}
+	if math.IsInf(value, 0) {
+		return sign | InfPos
+	}
+	if value < 0x1p-14 {
+		// subnormal, significand counts multiples of 2^-24, it becomes the smallest normal at 1024
+		return sign | Float16(mode.round(math.Ldexp(value, 24), negative))
+	}
+	_, exp := math.Frexp(value)
+	exp-- // value = 1.f * 2^exp
+	if exp > 15 {
+		return mode.overflow(negative)
+	}
+	frac := mode.round(math.Ldexp(value, 10-exp), negative)
+	if frac == 2048 {
+		frac = 1024
+		exp++
+		if exp > 15 {
+			return mode.overflow(negative)
+		}
+	}
+	return sign | Float16(exp+15)<<10 | (Float16(frac) & mantissaMask)
+}
@@ -0,0 +1,145 @@
+package float16
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFF64(t *testing.T) {
+	cases := []struct {
+		value    float64
+		expected Float16
+	}{
+		{1, 0x3C00},
+		{1 + 0x1p-11, 0x3C00},   // tie to even
+		{1 + 3*0x1p-11, 0x3C02}, // tie to even
+		{1 + 0x1p-11 + 0x1p-30, 0x3C01},
+		{65504, 0x7BFF},
+		{65519, 0x7BFF},
+		{65520, InfPos},
+		{-0x1p-24, 0x8001},
+		{0x1p-25, 0},               // tie to even zero
+		{0x1p-14 - 0x1p-26, 0x400}, // subnormal rounds to smallest normal
+		{math.Inf(-1), InfNeg},
+	}
+	for _, c := range cases {
+		if got := FF64(c.value); got != c.expected {
+			t.Errorf("FF64 failed. Expected %#x for %v, but got %#x", c.expected, c.value, got)
+		}
+	}
+	if !FF64(math.NaN()).IsNaN() {
+		t.Errorf("FF64 failed. Expected NaN")
+	}
+}
+
+// exact value of a Float16 computed from its fields
+func exactValue(f16 Float16) float64 {
+	exp := int(f16>>10) & 0x1f
+	frac := float64(f16 & mantissaMask)
+	value := math.Ldexp(frac, -24)
+	if exp != 0 {
+		value = math.Ldexp(1024+frac, exp-25)
+	}
+	if f16&signMask != 0 {
+		return -value
+	}
+	return value
+}
+
+func TestRoundTrip(t *testing.T) {
+	for i := 0; i < 1<<16; i++ {
+		f16 := Float16(i)
+		if f16.IsNaN() {
+			if !FF32(f16.ToF32()).IsNaN() || !FF64(f16.ToF64()).IsNaN() {
+				t.Fatalf("RoundTrip failed. NaN %#x is not NaN after round trip", i)
+			}
+			continue
+		}
+		if f16.IsInf(0) {
+			if FF64(f16.ToF64()) != f16 {
+				t.Fatalf("RoundTrip failed. Infinity %#x changed after round trip", i)
+			}
+			continue
+		}
+		if v := f16.ToF64(); v != exactValue(f16) || math.Signbit(v) != f16.Signbit() {
+			t.Fatalf("ToF64 failed. Expected %v for %#x, but got %v", exactValue(f16), i, v)
+		}
+		if v := f16.ToF32(); float64(v) != exactValue(f16) {
+			t.Fatalf("ToF32 failed. Expected %v for %#x, but got %v", exactValue(f16), i, v)
+		}
+		if got := FF32(f16.ToF32()); got != f16 {
+			t.Fatalf("FF32 failed. Expected %#x after round trip, but got %#x", i, got)
+		}
+		if got := FF64(f16.ToF64()); got != f16 {
+			t.Fatalf("FF64 failed. Expected %#x after round trip, but got %#x", i, got)
+		}
+	}
+}
+
+func TestRoundingMidpoints(t *testing.T) {
+	// positive finite values and their successors, the last successor is infinity
+	for i := 0; i < int(MaxValue)+1; i++ {
+		lo, hi := Float16(i), Float16(i+1)
+		hiValue := exactValue(hi)
+		if hi == InfPos {
+			// midpoint between greatest finite value and the next power of two
+			hiValue = 65536
+		}
+		mid := (exactValue(lo) + hiValue) / 2
+		even := lo
+		if lo&1 == 1 {
+			even = hi
+		}
+		if got := FF64(mid); got != even {
+			t.Fatalf("FF64 failed. Expected %#x for midpoint %v, but got %#x", even, mid, got)
+		}
+		if got := FF32(float32(mid)); got != even {
+			t.Fatalf("FF32 failed. Expected %#x for midpoint %v, but got %#x", even, mid, got)
+		}
+		if got := FF64(-mid); got != even|signMask {
+			t.Fatalf("FF64 failed. Expected %#x for midpoint %v, but got %#x", even|signMask, -mid, got)
+		}
+		above, below := math.Nextafter(mid, math.Inf(1)), math.Nextafter(mid, 0)
+		if got := FF64(above); got != hi {
+			t.Fatalf("FF64 failed. Expected %#x above midpoint %v, but got %#x", hi, mid, got)
+		}
+		if got := FF64(below); got != lo {
+			t.Fatalf("FF64 failed. Expected %#x below midpoint %v, but got %#x", lo, mid, got)
+		}
+		if got := FromF64(mid, ToNearestAway); got != hi {
+			t.Fatalf("FromF64 failed. Expected %#x away from midpoint %v, but got %#x", hi, mid, got)
+		}
+	}
+}
+
+func TestRoundingModes(t *testing.T) {
+	value := 1 + 0x1p-12 // between 0x3c00 and 0x3c01
+	cases := []struct {
+		mode     RoundingMode
+		pos, neg Float16
+	}{
+		{ToNearestEven, 0x3C00, 0xBC00},
+		{ToNearestAway, 0x3C00, 0xBC00},
+		{ToZero, 0x3C00, 0xBC00},
+		{AwayFromZero, 0x3C01, 0xBC01},
+		{ToNegativeInf, 0x3C00, 0xBC01},
+		{ToPositiveInf, 0x3C01, 0xBC00},
+	}
+	for _, c := range cases {
+		if got := FromF64(value, c.mode); got != c.pos {
+			t.Errorf("FromF64 failed. Expected %#x with mode %d, but got %#x", c.pos, c.mode, got)
+		}
+		if got := FromF64(-value, c.mode); got != c.neg {
+			t.Errorf("FromF64 failed. Expected %#x with mode %d, but got %#x", c.neg, c.mode, got)
+		}
+	}
+	if got := FromF64(1e6, ToZero); got != MaxValue {
+		t.Errorf("FromF64 failed. Expected MaxValue for overflow towards zero, but got %#x", got)
+	}
+	if got := FromF64(65505, ToPositiveInf); got != InfPos {
+		t.Errorf("FromF64 failed. Expected infinity for overflow towards positive infinity, but got %#x", got)
+	}
+	if got := FromF32(-0x1p-30, ToNegativeInf); got != 0x8001 {
+		t.Errorf("FromF32 failed. Expected %#x for tiny negative value, but got %#x", 0x8001, got)
+	}
+}
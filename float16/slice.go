@@ -0,0 +1,80 @@
+package float16
+
+import (
+	"runtime"
+	"sync"
+)
+
+// elements converted by a goroutine at least, smaller slices are converted in the calling goroutine
+const chunkSize = 1 << 14
+
+// split n elements in chunks converted by concurrent goroutines
+func chunks(n int, fn func(lo, hi int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if limit := (n + chunkSize - 1) / chunkSize; workers > limit {
+		workers = limit
+	}
+	if workers <= 1 {
+		fn(0, n)
+		return
+	}
+	size := (n + workers - 1) / workers
+	wg := sync.WaitGroup{}
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+// Convert slice of Float16 to float32 by the lookup table of ToF32Lookup
+func F16ToF32Slice(src []Float16) []float32 {
+	dst := make([]float32, len(src))
+	f32TableOnce.Do(buildF32Table)
+	chunks(len(src), func(lo, hi int) {
+		for i, v := range src[lo:hi] {
+			dst[lo+i] = f32Table[v]
+		}
+	})
+	return dst
+}
+
+// Convert slice of Float16 to float64
+func F16ToF64Slice(src []Float16) []float64 {
+	dst := make([]float64, len(src))
+	chunks(len(src), func(lo, hi int) {
+		for i, v := range src[lo:hi] {
+			dst[lo+i] = v.ToF64()
+		}
+	})
+	return dst
+}
+
+// Convert slice of float32 to Float16 rounding to nearest, ties to even
+func F32ToF16Slice(src []float32) []Float16 {
+	dst := make([]Float16, len(src))
+	chunks(len(src), func(lo, hi int) {
+		for i, v := range src[lo:hi] {
+			dst[lo+i] = FF32(v)
+		}
+	})
+	return dst
+}
+
+// Convert slice of float64 to Float16 rounding to nearest, ties to even
+func F64ToF16Slice(src []float64) []Float16 {
+	dst := make([]Float16, len(src))
+	chunks(len(src), func(lo, hi int) {
+		for i, v := range src[lo:hi] {
+			dst[lo+i] = FF64(v)
+		}
+	})
+	return dst
+}
@@ -0,0 +1,38 @@
+package float16
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSliceConversion(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	// bigger than a chunk, so it is converted by many goroutines
+	src := make([]float64, 3*chunkSize+7)
+	for i := range src {
+		src[i] = rnd.NormFloat64() * 100
+	}
+	f16 := F64ToF16Slice(src)
+	f64 := F16ToF64Slice(f16)
+	f32 := F16ToF32Slice(f16)
+	back := F32ToF16Slice(f32)
+	for i := range src {
+		if f16[i] != FF64(src[i]) {
+			t.Fatalf("F64ToF16Slice failed. Expected %#x at %d, but got %#x", FF64(src[i]), i, f16[i])
+		}
+		if f64[i] != f16[i].ToF64() || f32[i] != f16[i].ToF32() || back[i] != f16[i] {
+			t.Fatalf("Slice conversion failed at %d", i)
+		}
+	}
+}
+
+func BenchmarkF16ToF32Slice(b *testing.B) {
+	src := make([]Float16, 1<<20)
+	for i := range src {
+		src[i] = Float16(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		F16ToF32Slice(src)
+	}
+}
@@ -0,0 +1,23 @@
+package float16
+
+import "sync"
+
+var (
+	f32Table     []float32 //float32 value of every Float16, built on first use
+	f32TableOnce sync.Once
+)
+
+func buildF32Table() {
+	f32Table = make([]float32, 1<<16)
+	for i := range f32Table {
+		f32Table[i] = Float16(i).ToF32()
+	}
+}
+
+// Convert to float32 by a lookup table
+//
+// The table of 64K entries (256KB) is built on first call, then decoding is a memory lookup
+func (f16 Float16) ToF32Lookup() float32 {
+	f32TableOnce.Do(buildF32Table)
+	return f32Table[f16]
+}
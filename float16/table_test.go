@@ -0,0 +1,32 @@
+package float16
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToF32Lookup(t *testing.T) {
+	for i := 0; i < 1<<16; i++ {
+		f16 := Float16(i)
+		got, expected := f16.ToF32Lookup(), f16.ToF32()
+		if math.Float32bits(got) != math.Float32bits(expected) {
+			t.Fatalf("ToF32Lookup failed. Expected %v for %#x, but got %v", expected, i, got)
+		}
+	}
+}
+
+func BenchmarkToF32(b *testing.B) {
+	sum := float32(0)
+	for i := 0; i < b.N; i++ {
+		sum += Float16(i & 0x7BFF).ToF32()
+	}
+	_ = sum
+}
+
+func BenchmarkToF32Lookup(b *testing.B) {
+	sum := float32(0)
+	for i := 0; i < b.N; i++ {
+		sum += Float16(i & 0x7BFF).ToF32Lookup()
+	}
+	_ = sum
+}
@@ -0,0 +1,39 @@
+package float16
+
+import "errors"
+
+var ErrLengthMismatch = errors.New("float16 vectors have different lengths")
+
+// Dot product of vectors accumulated in float32
+func Dot(x, y []Float16) float32 {
+	if len(x) != len(y) {
+		panic(ErrLengthMismatch)
+	}
+	f32TableOnce.Do(buildF32Table)
+	sum := float32(0)
+	for i, v := range x {
+		sum += f32Table[v] * f32Table[y[i]]
+	}
+	return sum
+}
+
+// Sum of vector accumulated in float32
+func Sum(x []Float16) float32 {
+	f32TableOnce.Do(buildF32Table)
+	sum := float32(0)
+	for _, v := range x {
+		sum += f32Table[v]
+	}
+	return sum
+}
+
+// Compute y = alpha*x + y, every element is computed in float32 and rounded once
+func Axpy(alpha float32, x, y []Float16) {
+	if len(x) != len(y) {
+		panic(ErrLengthMismatch)
+	}
+	f32TableOnce.Do(buildF32Table)
+	for i, v := range x {
+		y[i] = FF32(alpha*f32Table[v] + f32Table[y[i]])
+	}
+}
@@ -0,0 +1,24 @@
+package float16
+
+import "testing"
+
+func TestVectorKernels(t *testing.T) {
+	// 4096 ones, a Float16 accumulator would stop growing at 2048
+	x := make([]Float16, 4096)
+	for i := range x {
+		x[i] = 0x3C00
+	}
+	if s := Sum(x); s != 4096 {
+		t.Errorf("Sum failed. Expected 4096, but got %v", s)
+	}
+	if d := Dot(x, x); d != 4096 {
+		t.Errorf("Dot failed. Expected 4096, but got %v", d)
+	}
+	y := []Float16{FF64(1), FF64(2), FF64(-3)}
+	Axpy(2, []Float16{FF64(0.5), FF64(1), FF64(1.5)}, y)
+	for i, expected := range []float64{2, 4, 0} {
+		if y[i].ToF64() != expected {
+			t.Errorf("Axpy failed. Expected %v at %d, but got %v", expected, i, y[i])
+		}
+	}
+}
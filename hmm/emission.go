@@ -0,0 +1,179 @@
+package hmm
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// Discrete emission of symbols 0, 1, ..., symbols - 1
+type Discrete struct {
+	Probs [][]float64 //Probs[state][symbol]
+}
+
+// Create discrete emission with probabilities of symbols by state
+func NewDiscrete(probs [][]float64) *Discrete {
+	for _, row := range probs {
+		checkStochastic(row)
+	}
+	return &Discrete{Probs: probs}
+}
+
+func (d *Discrete) States() int {
+	return len(d.Probs)
+}
+
+func (d *Discrete) LogProb(state int, obs int) float64 {
+	return math.Log(d.Probs[state][obs])
+}
+
+func (d *Discrete) Update(sequences [][]int, posteriors [][][]float64) {
+	// small pseudo count keeps every symbol possible
+	for s := range d.Probs {
+		for o := range d.Probs[s] {
+			d.Probs[s][o] = 1e-10
+		}
+	}
+	for k, seq := range sequences {
+		for t, obs := range seq {
+			for s := range d.Probs {
+				d.Probs[s][obs] += posteriors[k][t][s]
+			}
+		}
+	}
+	for s := range d.Probs {
+		normalize(d.Probs[s])
+	}
+}
+
+// Create HMM of discrete observations with random parameters
+func NewDiscreteHMM(states, symbols int, seed int64) *HMM[int] {
+	if states < 1 || symbols < 1 {
+		panic(ErrStatesNotValid)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	start, trans := randomChain(states, rnd.Float64)
+	probs := make([][]float64, states)
+	for s := range probs {
+		probs[s] = make([]float64, symbols)
+		for o := range probs[s] {
+			probs[s][o] = 0.5 + rnd.Float64()
+		}
+		normalize(probs[s])
+	}
+	return NewHMM[int](start, trans, NewDiscrete(probs))
+}
+
+// Gaussian emission with diagonal covariance of every state
+type Gaussian struct {
+	Means     [][]float64
+	Variances [][]float64
+	states    int
+	rnd       *rand.Rand
+}
+
+// Create Gaussian emission with means and variances of every state, they may be nil and then they are
+// initialized from random observations when the model is fitted
+func NewGaussian(states int, means, variances [][]float64, seed int64) *Gaussian {
+	if states < 1 {
+		panic(ErrStatesNotValid)
+	}
+	if means != nil && (len(means) != states || len(variances) != states) {
+		panic(ErrEmissionMismatch)
+	}
+	return &Gaussian{Means: means, Variances: variances, states: states, rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (g *Gaussian) States() int {
+	return g.states
+}
+
+func (g *Gaussian) LogProb(state int, obs knn.Point) float64 {
+	sum := 0.0
+	for d, v := range obs {
+		dif := v - g.Means[state][d]
+		sum -= 0.5*math.Log(2*math.Pi*g.Variances[state][d]) + dif*dif/(2*g.Variances[state][d])
+	}
+	return sum
+}
+
+// means from random observations and variances of every observation, if they are not set
+func (g *Gaussian) init(sequences [][]knn.Point) {
+	if g.Means != nil {
+		return
+	}
+	var all []knn.Point
+	for _, seq := range sequences {
+		all = append(all, seq...)
+	}
+	dim := len(all[0])
+	mean := make([]float64, dim)
+	variance := make([]float64, dim)
+	for _, p := range all {
+		for d, v := range p {
+			mean[d] += v
+		}
+	}
+	for d := range mean {
+		mean[d] /= float64(len(all))
+	}
+	for _, p := range all {
+		for d, v := range p {
+			variance[d] += (v - mean[d]) * (v - mean[d])
+		}
+	}
+	g.Means = make([][]float64, g.states)
+	g.Variances = make([][]float64, g.states)
+	for s := range g.Means {
+		g.Means[s] = append([]float64(nil), all[g.rnd.Intn(len(all))]...)
+		g.Variances[s] = make([]float64, dim)
+		for d := range variance {
+			g.Variances[s][d] = variance[d]/float64(len(all)) + 1e-6
+		}
+	}
+}
+
+func (g *Gaussian) Update(sequences [][]knn.Point, posteriors [][][]float64) {
+	for s := 0; s < g.states; s++ {
+		dim := len(g.Means[s])
+		weight := 0.0
+		mean := make([]float64, dim)
+		for k, seq := range sequences {
+			for t, obs := range seq {
+				w := posteriors[k][t][s]
+				weight += w
+				for d, v := range obs {
+					mean[d] += w * v
+				}
+			}
+		}
+		if weight == 0 {
+			continue
+		}
+		for d := range mean {
+			mean[d] /= weight
+		}
+		variance := make([]float64, dim)
+		for k, seq := range sequences {
+			for t, obs := range seq {
+				w := posteriors[k][t][s]
+				for d, v := range obs {
+					variance[d] += w * (v - mean[d]) * (v - mean[d])
+				}
+			}
+		}
+		for d := range variance {
+			// floor keeps densities finite when a state has a single observation
+			variance[d] = variance[d]/weight + 1e-6
+		}
+		g.Means[s], g.Variances[s] = mean, variance
+	}
+}
+
+// Create HMM of real vector observations with random transitions, means are set from data when it is fitted
+func NewGaussianHMM(states int, seed int64) *HMM[knn.Point] {
+	rnd := rand.New(rand.NewSource(seed))
+	start, trans := randomChain(states, rnd.Float64)
+	return NewHMM[knn.Point](start, trans, NewGaussian(states, nil, nil, seed))
+}
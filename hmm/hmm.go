@@ -0,0 +1,326 @@
+// Package hmm implements hidden Markov models with likelihood evaluation, Viterbi decoding and Baum-Welch training
+package hmm
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrEmptySequence    = errors.New("sequence is empty")
+	ErrStatesNotValid   = errors.New("number of states is not greater than zero")
+	ErrNotStochastic    = errors.New("probabilities are not a stochastic vector or matrix")
+	ErrEmissionMismatch = errors.New("emission states don't match model states")
+)
+
+// Emission distribution of every state
+type Emission[O any] interface {
+	States() int
+	// log probability (or density) of observation in state
+	LogProb(state int, obs O) float64
+	// update parameters with posterior probabilities of states of every observation of every sequence
+	Update(sequences [][]O, posteriors [][][]float64)
+}
+
+// Hidden Markov model with observations of type O
+type HMM[O any] struct {
+	start    []float64   //probability of first state
+	trans    [][]float64 //trans[i][j] is probability of transition from i to j
+	emission Emission[O]
+}
+
+func checkStochastic(ps []float64) {
+	sum := 0.0
+	for _, p := range ps {
+		if p < 0 {
+			panic(ErrNotStochastic)
+		}
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-6 {
+		panic(ErrNotStochastic)
+	}
+}
+
+// Create HMM with start probabilities, transition matrix and emission of states
+func NewHMM[O any](start []float64, trans [][]float64, emission Emission[O]) *HMM[O] {
+	n := len(start)
+	if n == 0 {
+		panic(ErrStatesNotValid)
+	}
+	if len(trans) != n || emission.States() != n {
+		panic(ErrEmissionMismatch)
+	}
+	checkStochastic(start)
+	for _, row := range trans {
+		if len(row) != n {
+			panic(ErrNotStochastic)
+		}
+		checkStochastic(row)
+	}
+	return &HMM[O]{start: start, trans: trans, emission: emission}
+}
+
+// uniform start and random transitions with heavier self transitions
+func randomChain(states int, rnd func() float64) ([]float64, [][]float64) {
+	start := make([]float64, states)
+	trans := make([][]float64, states)
+	for i := range trans {
+		start[i] = 1 / float64(states)
+		trans[i] = make([]float64, states)
+		sum := 0.0
+		for j := range trans[i] {
+			trans[i][j] = 0.5 + rnd()
+			if i == j {
+				trans[i][j] += float64(states)
+			}
+			sum += trans[i][j]
+		}
+		for j := range trans[i] {
+			trans[i][j] /= sum
+		}
+	}
+	return start, trans
+}
+
+// Number of hidden states
+func (h *HMM[O]) States() int {
+	return len(h.start)
+}
+
+// Start probabilities of states
+func (h *HMM[O]) Start() []float64 {
+	return h.start
+}
+
+// Transition probabilities between states
+func (h *HMM[O]) Transitions() [][]float64 {
+	return h.trans
+}
+
+// Emission of states
+func (h *HMM[O]) Emission() Emission[O] {
+	return h.emission
+}
+
+func logSumExp(xs []float64) float64 {
+	greatest := math.Inf(-1)
+	for _, x := range xs {
+		greatest = math.Max(greatest, x)
+	}
+	if math.IsInf(greatest, -1) {
+		return greatest
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += math.Exp(x - greatest)
+	}
+	return greatest + math.Log(sum)
+}
+
+func logs(ps []float64) []float64 {
+	out := make([]float64, len(ps))
+	for i, p := range ps {
+		out[i] = math.Log(p)
+	}
+	return out
+}
+
+// log emissions of every observation in every state
+func (h *HMM[O]) logEmissions(seq []O) [][]float64 {
+	out := make([][]float64, len(seq))
+	for t, obs := range seq {
+		out[t] = make([]float64, len(h.start))
+		for s := range out[t] {
+			out[t][s] = h.emission.LogProb(s, obs)
+		}
+	}
+	return out
+}
+
+// log forward and backward variables
+func (h *HMM[O]) forwardBackward(emit [][]float64) ([][]float64, [][]float64) {
+	n, steps := len(h.start), len(emit)
+	logStart := logs(h.start)
+	logTrans := make([][]float64, n)
+	for i := range logTrans {
+		logTrans[i] = logs(h.trans[i])
+	}
+	alpha := make([][]float64, steps)
+	beta := make([][]float64, steps)
+	terms := make([]float64, n)
+	for t := range alpha {
+		alpha[t] = make([]float64, n)
+		beta[t] = make([]float64, n)
+	}
+	for s := 0; s < n; s++ {
+		alpha[0][s] = logStart[s] + emit[0][s]
+	}
+	for t := 1; t < steps; t++ {
+		for s := 0; s < n; s++ {
+			for p := 0; p < n; p++ {
+				terms[p] = alpha[t-1][p] + logTrans[p][s]
+			}
+			alpha[t][s] = logSumExp(terms) + emit[t][s]
+		}
+	}
+	for t := steps - 2; t >= 0; t-- {
+		for s := 0; s < n; s++ {
+			for q := 0; q < n; q++ {
+				terms[q] = logTrans[s][q] + emit[t+1][q] + beta[t+1][q]
+			}
+			beta[t][s] = logSumExp(terms)
+		}
+	}
+	return alpha, beta
+}
+
+// Log likelihood of sequence by the forward algorithm
+func (h *HMM[O]) LogLikelihood(seq []O) float64 {
+	if len(seq) == 0 {
+		panic(ErrEmptySequence)
+	}
+	alpha, _ := h.forwardBackward(h.logEmissions(seq))
+	return logSumExp(alpha[len(seq)-1])
+}
+
+// Posterior probabilities of states of every observation of sequence
+func (h *HMM[O]) Posterior(seq []O) [][]float64 {
+	if len(seq) == 0 {
+		panic(ErrEmptySequence)
+	}
+	gamma, _, _ := h.posterior(h.logEmissions(seq))
+	return gamma
+}
+
+// posterior of states, expected transitions and log likelihood
+func (h *HMM[O]) posterior(emit [][]float64) ([][]float64, [][]float64, float64) {
+	n, steps := len(h.start), len(emit)
+	alpha, beta := h.forwardBackward(emit)
+	ll := logSumExp(alpha[steps-1])
+	gamma := make([][]float64, steps)
+	for t := range gamma {
+		gamma[t] = make([]float64, n)
+		for s := range gamma[t] {
+			gamma[t][s] = math.Exp(alpha[t][s] + beta[t][s] - ll)
+		}
+	}
+	xi := make([][]float64, n)
+	for i := range xi {
+		xi[i] = make([]float64, n)
+	}
+	for t := 0; t < steps-1; t++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				xi[i][j] += math.Exp(alpha[t][i] + math.Log(h.trans[i][j]) + emit[t+1][j] + beta[t+1][j] - ll)
+			}
+		}
+	}
+	return gamma, xi, ll
+}
+
+// Most likely sequence of states of observations and its log probability
+func (h *HMM[O]) Viterbi(seq []O) ([]int, float64) {
+	if len(seq) == 0 {
+		panic(ErrEmptySequence)
+	}
+	n, steps := len(h.start), len(seq)
+	emit := h.logEmissions(seq)
+	delta := make([]float64, n)
+	next := make([]float64, n)
+	back := make([][]int, steps)
+	for s := range delta {
+		delta[s] = math.Log(h.start[s]) + emit[0][s]
+	}
+	for t := 1; t < steps; t++ {
+		back[t] = make([]int, n)
+		for s := 0; s < n; s++ {
+			best, arg := math.Inf(-1), 0
+			for p := 0; p < n; p++ {
+				if v := delta[p] + math.Log(h.trans[p][s]); v > best {
+					best, arg = v, p
+				}
+			}
+			next[s] = best + emit[t][s]
+			back[t][s] = arg
+		}
+		delta, next = next, delta
+	}
+	last := 0
+	for s := range delta {
+		if delta[s] > delta[last] {
+			last = s
+		}
+	}
+	path := make([]int, steps)
+	path[steps-1] = last
+	for t := steps - 1; t > 0; t-- {
+		path[t-1] = back[t][path[t]]
+	}
+	return path, delta[last]
+}
+
+// Fit parameters to sequences by Baum-Welch expectation maximization
+//
+// It stops after maxIter iterations or when the total log likelihood improves less than tol, and it returns
+// the total log likelihood before every iteration
+func (h *HMM[O]) Fit(sequences [][]O, maxIter int, tol float64) []float64 {
+	n := len(h.start)
+	if e, ok := h.emission.(interface{ init([][]O) }); ok {
+		e.init(sequences)
+	}
+	history := make([]float64, 0, maxIter)
+	for iter := 0; iter < maxIter; iter++ {
+		start := make([]float64, n)
+		trans := make([][]float64, n)
+		for i := range trans {
+			trans[i] = make([]float64, n)
+		}
+		posteriors := make([][][]float64, len(sequences))
+		total := 0.0
+		for k, seq := range sequences {
+			if len(seq) == 0 {
+				panic(ErrEmptySequence)
+			}
+			gamma, xi, ll := h.posterior(h.logEmissions(seq))
+			posteriors[k] = gamma
+			total += ll
+			for s := range start {
+				start[s] += gamma[0][s]
+			}
+			for i := range xi {
+				for j := range xi[i] {
+					trans[i][j] += xi[i][j]
+				}
+			}
+		}
+		history = append(history, total)
+		if len(history) > 1 && total-history[len(history)-2] < tol {
+			break
+		}
+		normalize(start)
+		for i := range trans {
+			if !normalize(trans[i]) {
+				copy(trans[i], h.trans[i])
+			}
+		}
+		h.start, h.trans = start, trans
+		h.emission.Update(sequences, posteriors)
+	}
+	return history
+}
+
+// normalize to sum one, it is false if every value is zero
+func normalize(ps []float64) bool {
+	sum := 0.0
+	for _, p := range ps {
+		sum += p
+	}
+	if sum == 0 {
+		return false
+	}
+	for i := range ps {
+		ps[i] /= sum
+	}
+	return true
+}
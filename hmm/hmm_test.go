@@ -0,0 +1,132 @@
+package hmm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// healthy and fever states emitting normal, cold and dizzy
+func doctor() *HMM[int] {
+	return NewHMM[int](
+		[]float64{0.6, 0.4},
+		[][]float64{{0.7, 0.3}, {0.4, 0.6}},
+		NewDiscrete([][]float64{{0.5, 0.4, 0.1}, {0.1, 0.3, 0.6}}),
+	)
+}
+
+func TestViterbi(t *testing.T) {
+	path, logProb := doctor().Viterbi([]int{0, 1, 2})
+	if path[0] != 0 || path[1] != 0 || path[2] != 1 {
+		t.Errorf("Viterbi failed. Expected [0 0 1], but got %v", path)
+	}
+	if math.Abs(math.Exp(logProb)-0.01512) > 1e-12 {
+		t.Errorf("Viterbi failed. Expected probability 0.01512, but got %v", math.Exp(logProb))
+	}
+}
+
+func TestLogLikelihood(t *testing.T) {
+	h := doctor()
+	seq := []int{0, 1, 2, 2}
+	// sum over every path of states
+	sum := 0.0
+	for mask := 0; mask < 16; mask++ {
+		p := 1.0
+		prev := -1
+		for t, obs := range seq {
+			s := mask >> t & 1
+			if prev == -1 {
+				p *= h.start[s]
+			} else {
+				p *= h.trans[prev][s]
+			}
+			p *= h.emission.(*Discrete).Probs[s][obs]
+			prev = s
+		}
+		sum += p
+	}
+	if got := math.Exp(h.LogLikelihood(seq)); math.Abs(got-sum) > 1e-12 {
+		t.Errorf("LogLikelihood failed. Expected %v, but got %v", sum, got)
+	}
+	for _, row := range h.Posterior(seq) {
+		if math.Abs(row[0]+row[1]-1) > 1e-12 {
+			t.Errorf("Posterior failed. Probabilities don't sum 1: %v", row)
+		}
+	}
+}
+
+// sample sequences of model
+func sample(h *HMM[int], sequences, length int, seed int64) [][]int {
+	rnd := rand.New(rand.NewSource(seed))
+	draw := func(ps []float64) int {
+		u := rnd.Float64()
+		for i, p := range ps {
+			if u < p {
+				return i
+			}
+			u -= p
+		}
+		return len(ps) - 1
+	}
+	out := make([][]int, sequences)
+	for k := range out {
+		s := draw(h.start)
+		for t := 0; t < length; t++ {
+			out[k] = append(out[k], draw(h.emission.(*Discrete).Probs[s]))
+			s = draw(h.trans[s])
+		}
+	}
+	return out
+}
+
+func TestBaumWelch(t *testing.T) {
+	data := sample(doctor(), 50, 100, 1)
+	h := NewDiscreteHMM(2, 3, 1)
+	history := h.Fit(data, 200, 1e-6)
+	for i := 1; i < len(history); i++ {
+		if history[i] < history[i-1]-1e-9 {
+			t.Fatalf("Fit failed. Log likelihood decreased at iteration %d", i)
+		}
+	}
+	// fitted model explains data at least as well as the true one, up to sampling noise
+	truth, fitted := 0.0, 0.0
+	for _, seq := range data {
+		truth += doctor().LogLikelihood(seq)
+		fitted += h.LogLikelihood(seq)
+	}
+	if fitted < truth-5 {
+		t.Errorf("Fit failed. Expected log likelihood near %v, but got %v", truth, fitted)
+	}
+}
+
+func TestGaussianHMM(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	states := make([]int, 300)
+	seq := make([]knn.Point, len(states))
+	s := 0
+	for i := range seq {
+		if rnd.Float64() < 0.05 {
+			s = 1 - s
+		}
+		states[i] = s
+		seq[i] = knn.Point{5*float64(s) + rnd.NormFloat64()}
+	}
+	h := NewGaussianHMM(2, 3)
+	h.Fit([][]knn.Point{seq}, 100, 1e-6)
+	path, _ := h.Viterbi(seq)
+	right := 0
+	for i := range path {
+		if path[i] == states[i] {
+			right++
+		}
+	}
+	// states are found up to their order
+	if right < len(path)-right {
+		right = len(path) - right
+	}
+	if acc := float64(right) / float64(len(path)); acc < 0.97 {
+		t.Errorf("GaussianHMM failed. Expected accuracy greater than 0.97, but got %v", acc)
+	}
+}
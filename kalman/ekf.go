@@ -0,0 +1,55 @@
+package kalman
+
+import "github.com/stellviaproject/go-ia/linalg"
+
+// Function of state and its jacobian at state
+type Model struct {
+	Eval     func(x []float64) []float64
+	Jacobian func(x []float64) *linalg.Matrix
+}
+
+// Extended Kalman filter of x' = f(x) + w and z = h(x) + v, linearized at the current estimate
+type EKF struct {
+	f, h  Model
+	q, r  *linalg.Matrix
+	state State
+}
+
+// Create extended Kalman filter with transition f, observation h, noise covariances q and r and initial state
+func NewEKF(f, h Model, q, r *linalg.Matrix, initial State) *EKF {
+	n := len(initial.Mean)
+	checkSquare(q, n)
+	checkSquare(initial.Cov, n)
+	return &EKF{f: f, h: h, q: q, r: r, state: initial.clone()}
+}
+
+// Current estimate of state
+func (ekf *EKF) State() State {
+	return ekf.state.clone()
+}
+
+// Predict next state
+func (ekf *EKF) Predict() State {
+	jac := ekf.f.Jacobian(ekf.state.Mean)
+	checkSquare(jac, len(ekf.state.Mean))
+	mean := ekf.f.Eval(ekf.state.Mean)
+	if len(mean) != len(ekf.state.Mean) {
+		panic(ErrDimensionMismatch)
+	}
+	ekf.state = State{Mean: mean, Cov: jac.Mul(ekf.state.Cov).Mul(jac.T()).Add(ekf.q)}
+	return ekf.State()
+}
+
+// Correct state with measurement z, it returns ErrSingular of linalg if the innovation covariance is singular
+func (ekf *EKF) Update(z []float64) (State, error) {
+	jac := ekf.h.Jacobian(ekf.state.Mean)
+	if jac.Cols() != len(ekf.state.Mean) {
+		panic(ErrDimensionMismatch)
+	}
+	st, err := correct(ekf.state, z, ekf.h.Eval(ekf.state.Mean), jac, ekf.r)
+	if err != nil {
+		return ekf.State(), err
+	}
+	ekf.state = st
+	return ekf.State(), nil
+}
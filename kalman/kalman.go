@@ -0,0 +1,155 @@
+// Package kalman implements state estimation with Kalman filters and smoothers
+package kalman
+
+import (
+	"errors"
+
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+var ErrDimensionMismatch = errors.New("dimension of vector or matrix doesn't match the state space")
+
+// Gaussian estimate of state
+type State struct {
+	Mean []float64
+	Cov  *linalg.Matrix
+}
+
+func (st State) clone() State {
+	return State{Mean: append([]float64(nil), st.Mean...), Cov: st.Cov.Clone()}
+}
+
+func checkSquare(m *linalg.Matrix, n int) {
+	if m.Rows() != n || m.Cols() != n {
+		panic(ErrDimensionMismatch)
+	}
+}
+
+func addVec(a, b []float64, sign float64) []float64 {
+	out := make([]float64, len(a))
+	for i := range a {
+		out[i] = a[i] + sign*b[i]
+	}
+	return out
+}
+
+// correct state with measurement z, predicted measurement zPred and observation jacobian h
+//
+// Covariance is updated in Joseph form, which keeps it symmetric positive definite
+func correct(st State, z, zPred []float64, h, r *linalg.Matrix) (State, error) {
+	if len(z) != h.Rows() {
+		panic(ErrDimensionMismatch)
+	}
+	pht := st.Cov.Mul(h.T())
+	s := h.Mul(pht).Add(r)
+	sInv, err := s.Inverse()
+	if err != nil {
+		return st, err
+	}
+	gain := pht.Mul(sInv)
+	mean := addVec(st.Mean, gain.MulVec(addVec(z, zPred, -1)), 1)
+	ikh := linalg.Identity(len(st.Mean)).Sub(gain.Mul(h))
+	cov := ikh.Mul(st.Cov).Mul(ikh.T()).Add(gain.Mul(r).Mul(gain.T()))
+	return State{Mean: mean, Cov: cov}, nil
+}
+
+// Linear Kalman filter of x' = F x + B u + w and z = H x + v with noise covariances Q of w and R of v
+type Filter struct {
+	f, h, q, r, b *linalg.Matrix
+	state         State
+}
+
+// Create Kalman filter with transition f, observation h, noise covariances q and r and initial state
+func NewFilter(f, h, q, r *linalg.Matrix, initial State) *Filter {
+	n := len(initial.Mean)
+	checkSquare(f, n)
+	checkSquare(q, n)
+	checkSquare(initial.Cov, n)
+	if h.Cols() != n {
+		panic(ErrDimensionMismatch)
+	}
+	checkSquare(r, h.Rows())
+	return &Filter{f: f, h: h, q: q, r: r, state: initial.clone()}
+}
+
+// Set control matrix B of inputs u of Predict
+func (kf *Filter) SetControl(b *linalg.Matrix) *Filter {
+	if b.Rows() != len(kf.state.Mean) {
+		panic(ErrDimensionMismatch)
+	}
+	kf.b = b
+	return kf
+}
+
+// Current estimate of state
+func (kf *Filter) State() State {
+	return kf.state.clone()
+}
+
+// Predict next state with control input u, u is ignored if it is nil
+func (kf *Filter) Predict(u []float64) State {
+	mean := kf.f.MulVec(kf.state.Mean)
+	if u != nil && kf.b != nil {
+		mean = addVec(mean, kf.b.MulVec(u), 1)
+	}
+	cov := kf.f.Mul(kf.state.Cov).Mul(kf.f.T()).Add(kf.q)
+	kf.state = State{Mean: mean, Cov: cov}
+	return kf.State()
+}
+
+// Correct state with measurement z, it returns ErrSingular of linalg if the innovation covariance is singular
+func (kf *Filter) Update(z []float64) (State, error) {
+	st, err := correct(kf.state, z, kf.h.MulVec(kf.state.Mean), kf.h, kf.r)
+	if err != nil {
+		return kf.State(), err
+	}
+	kf.state = st
+	return kf.State(), nil
+}
+
+// Filter measurements in order, the first measurement corrects the initial state and every other one follows
+// a prediction without control. A nil measurement is missing and only predicted.
+//
+// It returns the predicted and filtered states of every step
+func (kf *Filter) Run(measurements [][]float64) ([]State, []State, error) {
+	predicted := make([]State, len(measurements))
+	filtered := make([]State, len(measurements))
+	for k, z := range measurements {
+		if k > 0 {
+			kf.Predict(nil)
+		}
+		predicted[k] = kf.State()
+		if z != nil {
+			if _, err := kf.Update(z); err != nil {
+				return nil, nil, err
+			}
+		}
+		filtered[k] = kf.State()
+	}
+	return predicted, filtered, nil
+}
+
+// Smooth measurements with the Rauch-Tung-Striebel smoother, every state is estimated with every measurement
+func (kf *Filter) Smooth(measurements [][]float64) ([]State, error) {
+	predicted, filtered, err := kf.Run(measurements)
+	if err != nil {
+		return nil, err
+	}
+	smoothed := make([]State, len(filtered))
+	if len(filtered) == 0 {
+		return smoothed, nil
+	}
+	last := len(filtered) - 1
+	smoothed[last] = filtered[last]
+	for k := last - 1; k >= 0; k-- {
+		pInv, err := predicted[k+1].Cov.Inverse()
+		if err != nil {
+			return nil, err
+		}
+		c := filtered[k].Cov.Mul(kf.f.T()).Mul(pInv)
+		mean := addVec(filtered[k].Mean, c.MulVec(addVec(smoothed[k+1].Mean, predicted[k+1].Mean, -1)), 1)
+		cov := filtered[k].Cov.Add(c.Mul(smoothed[k+1].Cov.Sub(predicted[k+1].Cov)).Mul(c.T()))
+		smoothed[k] = State{Mean: mean, Cov: cov}
+	}
+	return smoothed, nil
+}
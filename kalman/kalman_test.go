@@ -0,0 +1,99 @@
+package kalman
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+func scalar(v float64) *linalg.Matrix {
+	return linalg.NewMatrix(1, 1, []float64{v})
+}
+
+func TestConstant(t *testing.T) {
+	// without process noise and with a flat prior the estimate is the running mean
+	kf := NewFilter(scalar(1), scalar(1), scalar(0), scalar(4), State{Mean: []float64{0}, Cov: scalar(1e12)})
+	zs := [][]float64{{3}, {5}, {4}, {8}}
+	_, filtered, err := kf.Run(zs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := 0.0
+	for k, z := range zs {
+		sum += z[0]
+		if mean := filtered[k].Mean[0]; math.Abs(mean-sum/float64(k+1)) > 1e-6 {
+			t.Errorf("Run failed. Expected mean %v at step %d, but got %v", sum/float64(k+1), k, mean)
+		}
+		if v := filtered[k].Cov.At(0, 0); math.Abs(v-4/float64(k+1)) > 1e-6 {
+			t.Errorf("Run failed. Expected variance %v at step %d, but got %v", 4/float64(k+1), k, v)
+		}
+	}
+}
+
+func TestTracking(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	// position and velocity with time step 1, position is measured
+	f := linalg.NewMatrix(2, 2, []float64{1, 1, 0, 1})
+	h := linalg.NewMatrix(1, 2, []float64{1, 0})
+	q := linalg.NewMatrix(2, 2, []float64{0.25, 0.5, 0.5, 1})
+	for i := range q.Data() {
+		q.Data()[i] *= 0.01
+	}
+	truth := make([]float64, 200)
+	zs := make([][]float64, len(truth))
+	x, v := 0.0, 1.0
+	for k := range truth {
+		a := 0.1 * rnd.NormFloat64()
+		x, v = x+v+a/2, v+a
+		truth[k] = x
+		zs[k] = []float64{x + 3*rnd.NormFloat64()}
+	}
+	zs[50] = nil // missing measurement
+	initial := State{Mean: []float64{0, 0}, Cov: linalg.NewMatrix(2, 2, []float64{100, 0, 0, 100})}
+	_, filtered, err := NewFilter(f, h, q, scalar(9), initial).Run(zs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	smoothed, err := NewFilter(f, h, q, scalar(9), initial).Smooth(zs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rmse := func(est func(k int) float64) float64 {
+		sum := 0.0
+		for k := 20; k < len(truth); k++ {
+			dif := est(k) - truth[k]
+			sum += dif * dif
+		}
+		return math.Sqrt(sum / float64(len(truth)-20))
+	}
+	filterErr := rmse(func(k int) float64 { return filtered[k].Mean[0] })
+	smoothErr := rmse(func(k int) float64 { return smoothed[k].Mean[0] })
+	if filterErr > 2 || smoothErr >= filterErr {
+		t.Errorf("Smooth failed. Expected errors smoothed < filtered < 2, but got %v and %v", smoothErr, filterErr)
+	}
+}
+
+func TestEKF(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	// constant value measured through its square
+	f := Model{
+		Eval:     func(x []float64) []float64 { return x },
+		Jacobian: func(x []float64) *linalg.Matrix { return scalar(1) },
+	}
+	h := Model{
+		Eval:     func(x []float64) []float64 { return []float64{x[0] * x[0]} },
+		Jacobian: func(x []float64) *linalg.Matrix { return scalar(2 * x[0]) },
+	}
+	ekf := NewEKF(f, h, scalar(1e-6), scalar(0.25), State{Mean: []float64{2}, Cov: scalar(1)})
+	for k := 0; k < 100; k++ {
+		ekf.Predict()
+		if _, err := ekf.Update([]float64{9 + 0.5*rnd.NormFloat64()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if mean := ekf.State().Mean[0]; math.Abs(mean-3) > 0.05 {
+		t.Errorf("EKF failed. Expected 3, but got %v", mean)
+	}
+}
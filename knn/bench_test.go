@@ -0,0 +1,72 @@
+package knn_test
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func benchData(n, dim int) ([]knn.DataPoint, []knn.Point) {
+	centers := make([]knn.Point, 8)
+	for i := range centers {
+		centers[i] = knn.NewPoint(dim)
+		for d := range centers[i] {
+			centers[i][d] = float64((i*7+d*3)%11) * 2
+		}
+	}
+	data := dataset.MakeBlobs(n, centers, 1, 1)
+	queries := dataset.MakeBlobs(256, centers, 1, 2)
+	points := make([]knn.Point, len(queries))
+	for i, q := range queries {
+		points[i] = q.Point()
+	}
+	return data, points
+}
+
+func benchKNeighbors(b *testing.B, n, dim int, opts ...knn.Option) {
+	data, queries := benchData(n, dim)
+	model := knn.NewKNN(10, knn.NewEuclideanDist(), knn.NewMultiClassSelector(), data, opts...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		model.KNeighbors(queries[i%len(queries)], 10)
+	}
+}
+
+func BenchmarkBruteForce10k(b *testing.B) {
+	benchKNeighbors(b, 10000, 4)
+}
+
+func BenchmarkKDTree10k(b *testing.B) {
+	benchKNeighbors(b, 10000, 4, knn.WithIndex(knn.NewKDTree()))
+}
+
+func BenchmarkLSH10k(b *testing.B) {
+	benchKNeighbors(b, 10000, 4, knn.WithIndex(knn.NewEuclideanLSH(8, 8, 4, 1)))
+}
+
+func BenchmarkBruteForce100k(b *testing.B) {
+	benchKNeighbors(b, 100000, 4)
+}
+
+func BenchmarkKDTree100k(b *testing.B) {
+	benchKNeighbors(b, 100000, 4, knn.WithIndex(knn.NewKDTree()))
+}
+
+func BenchmarkKDTreeBuild10k(b *testing.B) {
+	data, _ := benchData(10000, 4)
+	dist := knn.NewEuclideanDist()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		knn.NewKDTree().Build(dist, data)
+	}
+}
+
+func BenchmarkPredictBatch(b *testing.B) {
+	data, queries := benchData(10000, 4)
+	model := knn.NewKNN(10, knn.NewEuclideanDist(), knn.NewMultiClassSelector(), data, knn.WithParallelLv(4))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		model.PredictBatch(queries)
+	}
+}
@@ -0,0 +1,53 @@
+package knn
+
+import "context"
+
+// Predict label of a point, the search stops when context is done and returns the context error
+func (knn *KNN) PredictCtx(ctx context.Context, point Point) (any, error) {
+	kset, err := knn.kNeighborsCtx(ctx, knn.transform(point), knn.k, knn.fan.Load())
+	if err != nil {
+		return nil, err
+	}
+	return knn.selector.Label(kset), nil
+}
+
+// Get the k nearest data points of a point, the search stops when context is done and returns the context error
+func (knn *KNN) KNeighborsCtx(ctx context.Context, point Point, k int) ([]DataDist, error) {
+	if k <= 0 {
+		panic(ErrKIsNotValid)
+	}
+	return knn.kNeighborsCtx(ctx, knn.transform(point), k, knn.fan.Load())
+}
+
+// Predict labels of many points, workers stop when context is done and the context error is returned
+func (knn *KNN) PredictBatchCtx(ctx context.Context, points []Point) ([]any, error) {
+	labels := make([]any, len(points))
+	knn.fan.Load().forEach(len(points), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		kset, err := knn.kNeighborsCtx(ctx, knn.transform(points[i]), knn.k, sequential)
+		if err == nil {
+			labels[i] = knn.selector.Label(kset)
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (knn *KNN) kNeighborsCtx(ctx context.Context, point Point, k int, fo *fanout) ([]DataDist, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if knn.index != nil {
+		// index queries are not interrupted, context is checked after the query
+		kset := knn.index.KNearest(point, k)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return kset, nil
+	}
+	return knn.scanCtx(ctx, point, k, fo)
+}
@@ -0,0 +1,43 @@
+package knn
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// distance that takes time to evaluate
+type slowDist struct {
+	Distance
+}
+
+func (sl *slowDist) Eval(p1, p2 Point) float64 {
+	time.Sleep(time.Microsecond)
+	return sl.Distance.Eval(p1, p2)
+}
+
+func TestPredictCtx(t *testing.T) {
+	rnd := rand.New(rand.NewSource(11))
+	data := randomDataPoints(rnd, 100000, 2)
+	knn := NewKNN(3, &slowDist{NewEuclideanDist()}, NewMultiClassSelector(), data).SetParallelLv(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := knn.PredictCtx(ctx, WithPoint(0.5, 0.5)); err != context.DeadlineExceeded {
+		t.Errorf("PredictCtx failed. Expected %v, but got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("PredictCtx failed. Search was not stopped, it took %v", elapsed)
+	}
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := knn.PredictBatchCtx(canceled, []Point{WithPoint(0, 0)}); err != context.Canceled {
+		t.Errorf("PredictBatchCtx failed. Expected %v, but got %v", context.Canceled, err)
+	}
+	small := NewKNN(1, NewEuclideanDist(), NewMultiClassSelector(), data[:10])
+	labels, err := small.PredictBatchCtx(context.Background(), []Point{data[3].Point()})
+	if err != nil || labels[0] != data[3].Label() {
+		t.Errorf("PredictBatchCtx failed. Expected %v, but got %v %v", data[3].Label(), labels, err)
+	}
+}
@@ -0,0 +1,290 @@
+package knn
+
+import (
+	"math"
+
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+// Get a copy of point scaled to unit length, zero points are returned as is
+func (p Point) Normalize() Point {
+	norm := 0.0
+	for _, x := range p {
+		norm += x * x
+	}
+	out := make(Point, len(p))
+	if norm == 0 {
+		copy(out, p)
+		return out
+	}
+	norm = math.Sqrt(norm)
+	for i, x := range p {
+		out[i] = x / norm
+	}
+	return out
+}
+
+// Scale every stored point to unit length, so cosine and angular distances can skip norms
+func NormalizeDataPoints(data []DataPoint) []DataPoint {
+	out := make([]DataPoint, len(data))
+	for i, d := range data {
+		out[i] = NewDataPoint(d.Label(), d.Point().Normalize())
+	}
+	return out
+}
+
+// cosine similarity of two points, normalized says that points have unit length
+func cosineSimilarity(p1, p2 Point, normalized bool) float64 {
+	if p1.Dim() != p2.Dim() {
+		panic(ErrPointDimensionMismatch)
+	}
+	var dot, norm1, norm2 float64
+	for i, ln := 0, len(p1); i < ln; i++ {
+		dot += p1[i] * p2[i]
+		if !normalized {
+			norm1 += p1[i] * p1[i]
+			norm2 += p2[i] * p2[i]
+		}
+	}
+	if normalized {
+		return math.Max(-1, math.Min(1, dot))
+	}
+	if norm1 == 0 || norm2 == 0 {
+		return 0
+	}
+	return math.Max(-1, math.Min(1, dot/math.Sqrt(norm1*norm2)))
+}
+
+type cosine struct {
+	normalized bool
+}
+
+// Cosine distance 1 - cos(p1, p2)
+//
+// normalized says that every point (stored or queried) has unit length, see NormalizeDataPoints and Point.Normalize
+func NewCosineDist(normalized bool) Distance {
+	return &cosine{normalized: normalized}
+}
+
+func (co *cosine) Eval(p1, p2 Point) float64 {
+	return 1 - cosineSimilarity(p1, p2, co.normalized)
+}
+
+type angular struct {
+	normalized bool
+}
+
+// Angular distance acos(cos(p1, p2)) / π in range [0, 1], it is a metric unlike cosine distance
+//
+// normalized says that every point (stored or queried) has unit length, see NormalizeDataPoints and Point.Normalize
+func NewAngularDist(normalized bool) Distance {
+	return &angular{normalized: normalized}
+}
+
+func (an *angular) Eval(p1, p2 Point) float64 {
+	return math.Acos(cosineSimilarity(p1, p2, an.normalized)) / math.Pi
+}
+
+type mahalanobis struct {
+	inv *linalg.Matrix //inverse of covariance matrix
+}
+
+// Mahalanobis distance sqrt((p1-p2)ᵀ S⁻¹ (p1-p2)) for covariance matrix S
+//
+// It returns linalg.ErrSingular if covariance has no inverse
+func NewMahalanobisDist(cov *linalg.Matrix) (Distance, error) {
+	inv, err := cov.Inverse()
+	if err != nil {
+		return nil, err
+	}
+	return &mahalanobis{inv: inv}, nil
+}
+
+func (ma *mahalanobis) Eval(p1, p2 Point) float64 {
+	if p1.Dim() != p2.Dim() || p1.Dim() != ma.inv.Rows() {
+		panic(ErrPointDimensionMismatch)
+	}
+	dif := make([]float64, len(p1))
+	for i := range dif {
+		dif[i] = p1[i] - p2[i]
+	}
+	prod := ma.inv.MulVec(dif)
+	sum := 0.0
+	for i := range dif {
+		sum += dif[i] * prod[i]
+	}
+	// rounding may give tiny negative values for equal points
+	return math.Sqrt(math.Max(sum, 0))
+}
+
+// Estimate covariance matrix of data points (unbiased, divided by n-1)
+func Covariance(data []DataPoint) *linalg.Matrix {
+	if len(data) < 2 {
+		panic(ErrNotEnoughData)
+	}
+	dim := data[0].Point().Dim()
+	mean := make([]float64, dim)
+	for _, d := range data {
+		p := d.Point()
+		if p.Dim() != dim {
+			panic(ErrPointDimensionMismatch)
+		}
+		for i := range mean {
+			mean[i] += p[i]
+		}
+	}
+	for i := range mean {
+		mean[i] /= float64(len(data))
+	}
+	cov := linalg.NewMatrix(dim, dim, nil)
+	for _, d := range data {
+		p := d.Point()
+		for i := 0; i < dim; i++ {
+			di := p[i] - mean[i]
+			for j := i; j < dim; j++ {
+				cov.Set(i, j, cov.At(i, j)+di*(p[j]-mean[j]))
+			}
+		}
+	}
+	n := float64(len(data) - 1)
+	for i := 0; i < dim; i++ {
+		for j := i; j < dim; j++ {
+			v := cov.At(i, j) / n
+			cov.Set(i, j, v)
+			cov.Set(j, i, v)
+		}
+	}
+	return cov
+}
+
+// count members of sets given by nonzero dimensions of two points
+func setCounts(p1, p2 Point) (both, only1, only2 int) {
+	if p1.Dim() != p2.Dim() {
+		panic(ErrPointDimensionMismatch)
+	}
+	for i, ln := 0, len(p1); i < ln; i++ {
+		in1, in2 := p1[i] != 0, p2[i] != 0
+		if in1 && in2 {
+			both++
+		} else if in1 {
+			only1++
+		} else if in2 {
+			only2++
+		}
+	}
+	return
+}
+
+type jaccard struct{}
+
+// Jaccard distance 1 - |A∩B| / |A∪B| of sets given by nonzero dimensions of points
+func NewJaccardDist() Distance {
+	return &jaccard{}
+}
+
+func (ja *jaccard) Eval(p1, p2 Point) float64 {
+	both, only1, only2 := setCounts(p1, p2)
+	union := both + only1 + only2
+	if union == 0 {
+		return 0
+	}
+	return 1 - float64(both)/float64(union)
+}
+
+type dice struct{}
+
+// Dice distance 1 - 2|A∩B| / (|A|+|B|) of sets given by nonzero dimensions of points
+func NewDiceDist() Distance {
+	return &dice{}
+}
+
+func (di *dice) Eval(p1, p2 Point) float64 {
+	both, only1, only2 := setCounts(p1, p2)
+	total := 2*both + only1 + only2
+	if total == 0 {
+		return 0
+	}
+	return 1 - float64(2*both)/float64(total)
+}
+
+type canberra struct{}
+
+// Canberra distance Σ |a-b| / (|a|+|b|), robust to dimensions with large values
+func NewCanberraDist() Distance {
+	return &canberra{}
+}
+
+func (ca *canberra) Eval(p1, p2 Point) float64 {
+	if p1.Dim() != p2.Dim() {
+		panic(ErrPointDimensionMismatch)
+	}
+	sum := 0.0
+	for i, ln := 0, len(p1); i < ln; i++ {
+		den := math.Abs(p1[i]) + math.Abs(p2[i])
+		if den != 0 {
+			sum += math.Abs(p1[i]-p2[i]) / den
+		}
+	}
+	return sum
+}
+
+// Mean radius of Earth in kilometers
+const EarthRadiusKm = 6371.0088
+
+type haversine struct {
+	radius float64
+}
+
+// Great-circle distance of points (latitude, longitude) in degrees on a sphere with the given radius,
+// use EarthRadiusKm for distances in kilometers
+func NewHaversineDist(radius float64) Distance {
+	return &haversine{radius: radius}
+}
+
+func (ha *haversine) Eval(p1, p2 Point) float64 {
+	if p1.Dim() != 2 || p2.Dim() != 2 {
+		panic(ErrPointIsNotLatLon)
+	}
+	lat1, lat2 := p1[0]*math.Pi/180, p2[0]*math.Pi/180
+	dlat := lat2 - lat1
+	dlon := (p2[1] - p1[1]) * math.Pi / 180
+	a := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return 2 * ha.radius * math.Asin(math.Sqrt(math.Min(1, a)))
+}
+
+type weighted struct {
+	base    Distance
+	weights []float64
+}
+
+// Distance that scales every dimension of points by its weight before evaluating base distance
+//
+// panics if a weight is negative
+func NewWeightedDist(base Distance, weights []float64) Distance {
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) {
+			panic(ErrWeightsNotValid)
+		}
+	}
+	return &weighted{base: base, weights: append([]float64{}, weights...)}
+}
+
+func (we *weighted) scale(p Point) Point {
+	if p.Dim() != len(we.weights) {
+		panic(ErrPointDimensionMismatch)
+	}
+	out := make(Point, len(p))
+	for i := range p {
+		out[i] = p[i] * we.weights[i]
+	}
+	return out
+}
+
+func (we *weighted) Eval(p1, p2 Point) float64 {
+	return we.base.Eval(we.scale(p1), we.scale(p2))
+}
+
+// weighted distance supports trees when base distance does
+func (we *weighted) axisDist(axis int, diff float64) float64 {
+	return we.base.(axisBounded).axisDist(axis, diff*we.weights[axis])
+}
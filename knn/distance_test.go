@@ -0,0 +1,121 @@
+package knn
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineEval(t *testing.T) {
+	p1 := WithPoint(1.0, 0.0)
+	p2 := WithPoint(0.0, 2.0)
+	p3 := WithPoint(2.0, 2.0)
+	co := NewCosineDist(false)
+	if d := co.Eval(p1, p2); d != 1 {
+		t.Errorf("CosineEval failed. Expected 1, but got %v", d)
+	}
+	if d := co.Eval(p3, p3); math.Abs(d) > 1e-12 {
+		t.Errorf("CosineEval failed. Expected 0, but got %v", d)
+	}
+	normalized := NewCosineDist(true)
+	if d, expected := normalized.Eval(p1.Normalize(), p3.Normalize()), co.Eval(p1, p3); math.Abs(d-expected) > 1e-12 {
+		t.Errorf("CosineEval normalized failed. Expected %v, but got %v", expected, d)
+	}
+}
+
+func TestAngularEval(t *testing.T) {
+	an := NewAngularDist(false)
+	if d := an.Eval(WithPoint(1.0, 0.0), WithPoint(0.0, 3.0)); math.Abs(d-0.5) > 1e-12 {
+		t.Errorf("AngularEval failed. Expected 0.5, but got %v", d)
+	}
+	if d := an.Eval(WithPoint(1.0, 0.0), WithPoint(-1.0, 0.0)); d != 1 {
+		t.Errorf("AngularEval failed. Expected 1, but got %v", d)
+	}
+	data := NormalizeDataPoints([]DataPoint{NewDataPoint("a", WithPoint(3.0, 4.0))})
+	if p := data[0].Point(); p[0] != 0.6 || p[1] != 0.8 {
+		t.Errorf("NormalizeDataPoints failed. Expected [0.6 0.8], but got %v", p)
+	}
+}
+
+func TestMahalanobisEval(t *testing.T) {
+	data := []DataPoint{
+		NewDataPoint(nil, WithPoint(0.0, 0.0)),
+		NewDataPoint(nil, WithPoint(2.0, 0.0)),
+		NewDataPoint(nil, WithPoint(0.0, 4.0)),
+		NewDataPoint(nil, WithPoint(2.0, 4.0)),
+	}
+	cov := Covariance(data)
+	if cov.At(0, 0) != 4.0/3 || cov.At(1, 1) != 16.0/3 || cov.At(0, 1) != 0 {
+		t.Fatalf("Covariance failed. Unexpected matrix %v", cov)
+	}
+	ma, err := NewMahalanobisDist(cov)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// one unit in each axis is scaled by its standard deviation
+	d1 := ma.Eval(WithPoint(0.0, 0.0), WithPoint(2.0, 0.0))
+	d2 := ma.Eval(WithPoint(0.0, 0.0), WithPoint(0.0, 4.0))
+	if math.Abs(d1-d2) > 1e-12 {
+		t.Errorf("MahalanobisEval failed. Expected %v, but got %v", d1, d2)
+	}
+	if _, err := NewMahalanobisDist(Covariance(data[:2])); err == nil {
+		t.Errorf("NewMahalanobisDist failed. Expected error for singular covariance")
+	}
+}
+
+func TestSetDistancesEval(t *testing.T) {
+	p1 := WithPoint(1, 1, 0, 1)
+	p2 := WithPoint(1, 0, 1, 1)
+	if d := NewJaccardDist().Eval(p1, p2); d != 0.5 {
+		t.Errorf("JaccardEval failed. Expected 0.5, but got %v", d)
+	}
+	if d := NewDiceDist().Eval(p1, p2); math.Abs(d-1.0/3) > 1e-12 {
+		t.Errorf("DiceEval failed. Expected %v, but got %v", 1.0/3, d)
+	}
+	empty := WithPoint(0, 0, 0, 0)
+	if d := NewJaccardDist().Eval(empty, empty); d != 0 {
+		t.Errorf("JaccardEval failed. Expected 0 for empty sets, but got %v", d)
+	}
+}
+
+func TestCanberraEval(t *testing.T) {
+	d := NewCanberraDist().Eval(WithPoint(1, 0, 3), WithPoint(3, 0, 1))
+	if expected := 2.0/4 + 2.0/4; d != expected {
+		t.Errorf("CanberraEval failed. Expected %v, but got %v", expected, d)
+	}
+}
+
+func TestHaversineEval(t *testing.T) {
+	ha := NewHaversineDist(EarthRadiusKm)
+	// one degree of longitude at the equator
+	d := ha.Eval(WithPoint(0, 0), WithPoint(0, 1))
+	if expected := EarthRadiusKm * math.Pi / 180; math.Abs(d-expected) > 1e-9 {
+		t.Errorf("HaversineEval failed. Expected %v, but got %v", expected, d)
+	}
+	// Paris to London is about 344 km
+	if d := ha.Eval(WithPoint(48.8566, 2.3522), WithPoint(51.5074, -0.1278)); math.Abs(d-344) > 2 {
+		t.Errorf("HaversineEval failed. Expected about 344, but got %v", d)
+	}
+	defer func() {
+		if r := recover(); r != ErrPointIsNotLatLon {
+			t.Errorf("HaversineEval failed. Expected panic %v, but got %v", ErrPointIsNotLatLon, r)
+		}
+	}()
+	ha.Eval(WithPoint(0, 0, 0), WithPoint(0, 0, 0))
+}
+
+func TestWeightedEval(t *testing.T) {
+	we := NewWeightedDist(NewEuclideanDist(), []float64{1, 0})
+	if d := we.Eval(WithPoint(0, 0), WithPoint(3, 100)); d != 3 {
+		t.Errorf("WeightedEval failed. Expected 3, but got %v", d)
+	}
+	// weighted distance keeps working with trees
+	data := []DataPoint{
+		NewDataPoint("a", WithPoint(0, 100)),
+		NewDataPoint("b", WithPoint(5, 0)),
+	}
+	tree := NewKDTree()
+	tree.Build(we, data)
+	if kset := tree.KNearest(WithPoint(1, 0), 1); kset[0].DataPoint().Label() != "a" {
+		t.Errorf("WeightedEval with KDTree failed. Expected a, but got %v", kset[0].DataPoint().Label())
+	}
+}
@@ -0,0 +1,75 @@
+package knn
+
+import "sync"
+
+// fan-out of the work of a call among at most lv goroutines, they are started by every call and end before it
+// returns, so nothing has to be closed
+type fanout struct {
+	lv int
+}
+
+// fan-out that runs everything in the calling goroutine
+var sequential = &fanout{lv: 1}
+
+func newFanout(lv int) *fanout {
+	if lv < 1 {
+		panic(ErrParallelLevelIsNotValid)
+	}
+	return &fanout{lv: lv}
+}
+
+// run fn for every index in [0, n) using goroutines that take indices from a shared channel
+func (fo *fanout) forEach(n int, fn func(i int)) {
+	lv := fo.lv
+	if lv <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	if lv > n {
+		lv = n
+	}
+	jobs := make(chan int, lv)
+	wg := sync.WaitGroup{}
+	wg.Add(lv)
+	for w := 0; w < lv; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// split [0, n) in one contiguous chunk for every goroutine and run fn for each chunk
+func (fo *fanout) chunks(n int, fn func(lo, hi int)) {
+	lv := fo.lv
+	if lv <= 1 || n <= 1 {
+		fn(0, n)
+		return
+	}
+	if lv > n {
+		lv = n
+	}
+	size := (n + lv - 1) / lv
+	wg := sync.WaitGroup{}
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
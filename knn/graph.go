@@ -0,0 +1,66 @@
+package knn
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Weight of an edge of a k-NN graph from the distance between its points
+type EdgeWeight func(dist float64) float64
+
+// Distance itself as weight, like the graphs read by manifold.UMAP
+func DistanceWeight(dist float64) float64 {
+	return dist
+}
+
+// Weight one for every edge
+func UnitWeight(dist float64) float64 {
+	return 1
+}
+
+// Affinity 1 / (dist + eps), nearer neighbors have stronger links and duplicated points have 1 / eps
+func InverseWeight(eps float64) EdgeWeight {
+	return func(dist float64) float64 {
+		return 1 / (dist + eps)
+	}
+}
+
+// Gaussian affinity exp(-dist² / sigma²)
+func GaussianWeight(sigma float64) EdgeWeight {
+	return func(dist float64) float64 {
+		return math.Exp(-dist * dist / (sigma * sigma))
+	}
+}
+
+// Graph of k nearest neighbors of points
+//
+// Node i is named by its index and has points[i] as value, it has an edge i -> j to every one of its k nearest
+// neighbors weighted by weight of the distance between them. Community detection of package graph reads weights as
+// link strength, so it needs an affinity like InverseWeight or GaussianWeight, InverseWeight(1e-9) if weight is nil
+func BuildKNNGraph(points []Point, k int, dist Distance, weight EdgeWeight) graph.Graph {
+	if k <= 0 || k >= len(points) {
+		panic(ErrKIsNotValid)
+	}
+	if weight == nil {
+		weight = InverseWeight(1e-9)
+	}
+	g := graph.New("knn")
+	for i, p := range points {
+		g.AddNode(fmt.Sprint(i), p)
+	}
+	for i, p := range points {
+		set := newNearest(k)
+		for j, q := range points {
+			if i != j {
+				// label keeps index of neighbor
+				set.push(dist.Eval(p, q), NewDataPoint(j, q))
+			}
+		}
+		for _, dd := range set.sorted() {
+			g.AddWeightedEdge(i, dd.DataPoint().Label().(int), weight(dd.Dist()))
+		}
+	}
+	return g
+}
@@ -0,0 +1,71 @@
+package knn
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBuildKNNGraph(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	data := randomDataPoints(rnd, 60, 2)
+	points := make([]Point, len(data))
+	for i, d := range data {
+		points[i] = d.Point()
+	}
+	dist := NewEuclideanDist()
+	k := 4
+	g := BuildKNNGraph(points, k, dist, DistanceWeight)
+	if g.LenNodes() != len(points) {
+		t.Fatalf("BuildKNNGraph failed. Expected %d nodes, but got %d", len(points), g.LenNodes())
+	}
+	for i, p := range points {
+		// k-th nearest distance by brute force, point itself is the nearest
+		kset := NewKNN(k+1, dist, nil, data).KNeighbors(p, k+1)
+		bound := kset[k].Dist()
+		outs := 0
+		for j, q := range points {
+			w, ok := g.EdgeWeight(i, j)
+			if !ok {
+				continue
+			}
+			outs++
+			if w != dist.Eval(p, q) {
+				t.Fatalf("BuildKNNGraph failed. Expected weight %v for %d -> %d, but got %v", dist.Eval(p, q), i, j, w)
+			}
+			if w > bound {
+				t.Fatalf("BuildKNNGraph failed. Edge %d -> %d is not among the %d nearest", i, j, k)
+			}
+		}
+		if outs != k {
+			t.Fatalf("BuildKNNGraph failed. Expected %d neighbors of %d, but got %d", k, i, outs)
+		}
+	}
+}
+
+func TestKNNGraphCommunities(t *testing.T) {
+	// tight cluster near origin and a spread cluster far from it, every point has neighbors in both clusters
+	var points []Point
+	for i := 0; i < 6; i++ {
+		points = append(points, Point{0.1 * float64(i), 0})
+	}
+	for i := 0; i < 6; i++ {
+		points = append(points, Point{20 + float64(i), 0})
+	}
+	for name, weight := range map[string]EdgeWeight{"InverseWeight": nil, "GaussianWeight": GaussianWeight(3)} {
+		g := BuildKNNGraph(points, 7, NewEuclideanDist(), weight)
+		community := g.Louvain()
+		// no community has points of both clusters
+		for i := 0; i < 6; i++ {
+			for j := 6; j < 12; j++ {
+				if community[i] == community[j] {
+					t.Fatalf("%s failed. Expected clusters separated, but got communities %v", name, community)
+				}
+			}
+		}
+	}
+	// duplicated points keep a strong link
+	g := BuildKNNGraph([]Point{{1, 1}, {1, 1}, {5, 5}}, 1, NewEuclideanDist(), nil)
+	if w, ok := g.EdgeWeight(0, 1); !ok || w <= 1 {
+		t.Errorf("BuildKNNGraph failed. Expected strong link of duplicated points, but got %v", w)
+	}
+}
@@ -0,0 +1,99 @@
+package knn
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// neighbor with the order it was offered, ties of distance are broken by order
+type neighbor struct {
+	DataDist
+	order int
+}
+
+// less reports if n is nearer than other
+func (n neighbor) less(other neighbor) bool {
+	if n.Dist() != other.Dist() {
+		return n.Dist() < other.Dist()
+	}
+	return n.order < other.order
+}
+
+// max-heap of neighbors by distance, used to keep the k nearest neighbors
+type neighborHeap []neighbor
+
+func (h neighborHeap) Len() int           { return len(h) }
+func (h neighborHeap) Less(i, j int) bool { return h[j].less(h[i]) }
+func (h neighborHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *neighborHeap) Push(x any) {
+	*h = append(*h, x.(neighbor))
+}
+
+func (h *neighborHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// bounded set of the k nearest neighbors
+type nearest struct {
+	k       int
+	heap    neighborHeap
+	offered int
+}
+
+func newNearest(k int) *nearest {
+	return &nearest{
+		k:    k,
+		heap: make(neighborHeap, 0, k),
+	}
+}
+
+// distance a candidate must beat to enter the set
+func (ne *nearest) bound() (float64, bool) {
+	if len(ne.heap) < ne.k {
+		return 0, false
+	}
+	return ne.heap[0].Dist(), true
+}
+
+// offer a candidate to the set, candidates offered first win ties of distance
+func (ne *nearest) push(dist float64, dp DataPoint) {
+	ne.pushAt(dist, dp, ne.offered)
+}
+
+// offer a candidate with its order, like the index of data point, lesser orders win ties of distance
+func (ne *nearest) pushAt(dist float64, dp DataPoint, order int) {
+	ne.offered++
+	n := neighbor{DataDist: newDataDist(dist, dp), order: order}
+	if len(ne.heap) < ne.k {
+		heap.Push(&ne.heap, n)
+	} else if n.less(ne.heap[0]) {
+		ne.heap[0] = n
+		heap.Fix(&ne.heap, 0)
+	}
+}
+
+// merge candidates of other set keeping their orders
+func (ne *nearest) merge(other *nearest) {
+	for _, n := range other.heap {
+		ne.pushAt(n.Dist(), n.DataPoint(), n.order)
+	}
+}
+
+// neighbors sorted by distance and order
+func (ne *nearest) sorted() []DataDist {
+	sorted := make(neighborHeap, len(ne.heap))
+	copy(sorted, ne.heap)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].less(sorted[j])
+	})
+	kset := make([]DataDist, len(sorted))
+	for i, n := range sorted {
+		kset[i] = n.DataDist
+	}
+	return kset
+}
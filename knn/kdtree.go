@@ -0,0 +1,295 @@
+package knn
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+var ErrDistanceNotSupported = fmt.Errorf("distance is not supported by index")
+
+// Index of data points for neighbor queries
+type Index interface {
+	Build(dist Distance, data []DataPoint)        //build index for data points with the given distance
+	KNearest(point Point, k int) []DataDist       //k nearest data points sorted by distance
+	Range(point Point, radius float64) []DataDist //data points with distance lesser or equal to radius sorted by distance
+}
+
+// Index that supports updates without building it again
+type DynamicIndex interface {
+	Index
+	Insert(dp DataPoint)      //add a data point to index
+	Remove(dp DataPoint) bool //remove a data point from index, it returns false if data point is not found
+}
+
+// updates allowed before building an index again, relative to its size
+func needsRebuild(updates, size int) bool {
+	return updates > size/2+16
+}
+
+// distances with a lower bound given by the difference of two points in a single axis, needed for pruning in trees
+type axisBounded interface {
+	axisDist(axis int, diff float64) float64
+}
+
+func (eu *euclidean) axisDist(axis int, diff float64) float64 {
+	return math.Abs(diff)
+}
+
+func (ma *manhattan) axisDist(axis int, diff float64) float64 {
+	return math.Abs(diff)
+}
+
+func (mi *minkowski) axisDist(axis int, diff float64) float64 {
+	return math.Abs(diff)
+}
+
+func (ch *chebyshev) axisDist(axis int, diff float64) float64 {
+	return math.Abs(diff)
+}
+
+// axis bound of dist for kd-trees, Minkowski distances with p lesser than 1 aren't metrics so they are rejected
+func kdBound(dist Distance) (axisBounded, bool) {
+	bound, ok := dist.(axisBounded)
+	base := dist
+	if we, isWeighted := dist.(*weighted); isWeighted {
+		_, ok = we.base.(axisBounded)
+		base = we.base
+	}
+	if mi, isMinkowski := base.(*minkowski); isMinkowski && !(mi.ratio >= 1) {
+		return nil, false
+	}
+	return bound, ok
+}
+
+type kdNode struct {
+	point int //index of data point
+	axis  int //split axis
+	left  int //index of left node or -1
+	right int //index of right node or -1
+}
+
+// KD-tree index, it supports distances of Minkowski family with p greater or equal to 1
+//
+// Inserted points are added as leaves and removed points are marked as deleted,
+// the tree is built again when updates are greater than half of its size
+type KDTree struct {
+	dist     Distance
+	bound    axisBounded
+	data     []DataPoint
+	deleted  []bool
+	nodes    []kdNode
+	root     int
+	removed  int //deleted data points
+	inserted int //data points inserted since tree was built
+}
+
+func NewKDTree() *KDTree {
+	return &KDTree{root: -1}
+}
+
+func (kd *KDTree) Build(dist Distance, data []DataPoint) {
+	bound, ok := kdBound(dist)
+	if !ok {
+		panic(ErrDistanceNotSupported)
+	}
+	kd.dist = dist
+	kd.bound = bound
+	kd.data = append([]DataPoint{}, data...)
+	kd.deleted = make([]bool, len(data))
+	kd.removed = 0
+	kd.inserted = 0
+	kd.nodes = make([]kdNode, 0, len(data))
+	points := make([]int, len(data))
+	for i := range points {
+		points[i] = i
+	}
+	kd.root = kd.build(points)
+}
+
+// build subtree for points and return its root
+func (kd *KDTree) build(points []int) int {
+	if len(points) == 0 {
+		return -1
+	}
+	axis := kd.splitAxis(points)
+	sort.SliceStable(points, func(i, j int) bool {
+		return kd.data[points[i]].Point()[axis] < kd.data[points[j]].Point()[axis]
+	})
+	median := len(points) / 2
+	node := len(kd.nodes)
+	kd.nodes = append(kd.nodes, kdNode{point: points[median], axis: axis})
+	left := kd.build(points[:median])
+	right := kd.build(points[median+1:])
+	kd.nodes[node].left = left
+	kd.nodes[node].right = right
+	return node
+}
+
+// axis with the greatest spread of values
+func (kd *KDTree) splitAxis(points []int) int {
+	dim := kd.data[points[0]].Point().Dim()
+	axis, spread := 0, -1.0
+	for d := 0; d < dim; d++ {
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, i := range points {
+			v := kd.data[i].Point()[d]
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		if max-min > spread {
+			axis, spread = d, max-min
+		}
+	}
+	return axis
+}
+
+func (kd *KDTree) KNearest(point Point, k int) []DataDist {
+	if k <= 0 {
+		panic(ErrKIsNotValid)
+	}
+	set := newNearest(k)
+	kd.kNearest(kd.root, point, set)
+	return set.sorted()
+}
+
+func (kd *KDTree) kNearest(node int, point Point, set *nearest) {
+	if node == -1 {
+		return
+	}
+	nd := &kd.nodes[node]
+	dp := kd.data[nd.point]
+	if !kd.deleted[nd.point] {
+		set.push(kd.dist.Eval(dp.Point(), point), dp)
+	}
+	diff := point[nd.axis] - dp.Point()[nd.axis]
+	near, far := nd.left, nd.right
+	if diff > 0 {
+		near, far = far, near
+	}
+	kd.kNearest(near, point, set)
+	// visit the other side only if it may contain a nearer point
+	if bound, full := set.bound(); !full || kd.bound.axisDist(nd.axis, diff) < bound {
+		kd.kNearest(far, point, set)
+	}
+}
+
+func (kd *KDTree) Range(point Point, radius float64) []DataDist {
+	found := make([]DataDist, 0, 10)
+	kd.inRange(kd.root, point, radius, &found)
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Dist() < found[j].Dist()
+	})
+	return found
+}
+
+func (kd *KDTree) inRange(node int, point Point, radius float64, found *[]DataDist) {
+	if node == -1 {
+		return
+	}
+	nd := &kd.nodes[node]
+	dp := kd.data[nd.point]
+	if dist := kd.dist.Eval(dp.Point(), point); dist <= radius && !kd.deleted[nd.point] {
+		*found = append(*found, newDataDist(dist, dp))
+	}
+	diff := point[nd.axis] - dp.Point()[nd.axis]
+	near, far := nd.left, nd.right
+	if diff > 0 {
+		near, far = far, near
+	}
+	kd.inRange(near, point, radius, found)
+	if kd.bound.axisDist(nd.axis, diff) <= radius {
+		kd.inRange(far, point, radius, found)
+	}
+}
+
+func (kd *KDTree) Insert(dp DataPoint) {
+	if kd.dist == nil {
+		panic(ErrIndexNotBuilt)
+	}
+	point := len(kd.data)
+	kd.data = append(kd.data, dp)
+	kd.deleted = append(kd.deleted, false)
+	kd.inserted++
+	node := len(kd.nodes)
+	if kd.root == -1 {
+		kd.nodes = append(kd.nodes, kdNode{point: point, left: -1, right: -1})
+		kd.root = node
+		return
+	}
+	// descend to the leaf where point belongs
+	p := dp.Point()
+	curr := kd.root
+	for {
+		nd := &kd.nodes[curr]
+		next := &nd.right
+		if p[nd.axis] < kd.data[nd.point].Point()[nd.axis] {
+			next = &nd.left
+		}
+		if *next == -1 {
+			*next = node
+			axis := (nd.axis + 1) % p.Dim()
+			kd.nodes = append(kd.nodes, kdNode{point: point, axis: axis, left: -1, right: -1})
+			break
+		}
+		curr = *next
+	}
+	kd.rebalance()
+}
+
+func (kd *KDTree) Remove(dp DataPoint) bool {
+	if kd.dist == nil {
+		return false
+	}
+	point := kd.find(kd.root, dp)
+	if point == -1 {
+		return false
+	}
+	kd.deleted[point] = true
+	kd.removed++
+	kd.rebalance()
+	return true
+}
+
+// index of data point in subtree or -1
+func (kd *KDTree) find(node int, dp DataPoint) int {
+	if node == -1 {
+		return -1
+	}
+	nd := &kd.nodes[node]
+	if kd.data[nd.point] == dp && !kd.deleted[nd.point] {
+		return nd.point
+	}
+	diff := dp.Point()[nd.axis] - kd.data[nd.point].Point()[nd.axis]
+	if diff <= 0 {
+		if found := kd.find(nd.left, dp); found != -1 {
+			return found
+		}
+	}
+	if diff >= 0 {
+		return kd.find(nd.right, dp)
+	}
+	return -1
+}
+
+// build tree again when it has too many updates
+func (kd *KDTree) rebalance() {
+	if needsRebuild(kd.inserted+kd.removed, len(kd.data)-kd.removed) {
+		kd.compact()
+	}
+}
+
+// build tree again with data points that are not deleted
+func (kd *KDTree) compact() {
+	live := make([]DataPoint, 0, len(kd.data)-kd.removed)
+	for i, dp := range kd.data {
+		if !kd.deleted[i] {
+			live = append(live, dp)
+		}
+	}
+	kd.Build(kd.dist, live)
+}
@@ -1,35 +1,43 @@
 package knn
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 var (
 	ErrParallelLevelIsNotValid = fmt.Errorf("parallelism level is not greater or equal to 1")
 	ErrPointDimensionMismatch  = fmt.Errorf("point dimension is not the same")
 	ErrKIsNotValid             = fmt.Errorf("value of k is not greater or equal to 1")
+	ErrNotEnoughData           = fmt.Errorf("not enough data points")
+	ErrPointIsNotLatLon        = fmt.Errorf("point is not a (latitude, longitude) pair")
+	ErrWeightsNotValid         = fmt.Errorf("weights are not greater or equal to 0")
+	ErrIndexNotBuilt           = fmt.Errorf("index is not built")
 )
 
-var prll chan int //control parallelism level
-var plv int
+var plv int = 1
 var prllMtx sync.RWMutex //control access to parallelism
 
-// Set the numbers of gorutines used in every function of knn
+// Set the default numbers of gorutines used by every new KNN
+//
+// Deprecated: package state is shared by every model, use WithParallelLv option of NewKNN instead
 func SetParallelLv(lv int) error {
 	prllMtx.Lock()
 	defer prllMtx.Unlock()
 	if lv < 1 {
 		panic(ErrParallelLevelIsNotValid)
 	}
-	prll = make(chan int, lv)
 	plv = lv
 	return nil
 }
 
-// Get the numbers of gorutines used in every function of knn
+// Get the default numbers of gorutines used by every new KNN
+//
+// Deprecated: use KNN.GetParallelLv of every model instead
 func GetParallelLv() int {
 	prllMtx.RLock()
 	defer prllMtx.RUnlock()
@@ -392,56 +400,278 @@ type KNN struct {
 	k        int
 	dist     Distance
 	selector Selector
+	index    Index
+	fan      atomic.Pointer[fanout] //replaced by SetParallelLv while other goroutines predict
+	scaler   Scaler
 }
 
-func NewKNN(k int, dist Distance, selector Selector, dataPoints []DataPoint) *KNN {
+// Option of KNN constructor
+type Option func(*options)
+
+type options struct {
+	parallel int
+	index    Index
+	scaler   Scaler
+}
+
+// Set the numbers of gorutines used by the KNN
+func WithParallelLv(lv int) Option {
+	return func(o *options) {
+		o.parallel = lv
+	}
+}
+
+// Build index with data points and use it for queries
+func WithIndex(index Index) Option {
+	return func(o *options) {
+		o.index = index
+	}
+}
+
+// Fit scaler with data points and use it for stored and queried points, it is applied before index is built
+func WithScaler(scaler Scaler) Option {
+	return func(o *options) {
+		o.scaler = scaler
+	}
+}
+
+func NewKNN(k int, dist Distance, selector Selector, dataPoints []DataPoint, opts ...Option) *KNN {
 	if k <= 0 {
 		panic(ErrKIsNotValid)
 	}
-	return &KNN{
+	o := options{parallel: GetParallelLv()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	knn := &KNN{
 		k:        k,
 		dist:     dist,
 		data:     dataPoints,
 		selector: selector,
 	}
+	knn.fan.Store(newFanout(o.parallel))
+	if o.scaler != nil {
+		knn.SetScaler(o.scaler)
+	}
+	if o.index != nil {
+		knn.SetIndex(o.index)
+	}
+	return knn
+}
+
+// Set the numbers of gorutines used by this KNN
+func (knn *KNN) SetParallelLv(lv int) *KNN {
+	knn.fan.Store(newFanout(lv))
+	return knn
+}
+
+// Get the numbers of gorutines used by this KNN
+func (knn *KNN) GetParallelLv() int {
+	return knn.fan.Load().lv
 }
 
 func (knn *KNN) Append(dp DataPoint) *KNN {
+	if knn.scaler != nil {
+		dp = &scaledDataPoint{DataPoint: dp, point: knn.scaler.Transform(dp.Point())}
+	}
 	knn.data = append(knn.data, dp)
+	if dynamic, ok := knn.index.(DynamicIndex); ok {
+		dynamic.Insert(dp)
+	} else if knn.index != nil {
+		knn.index.Build(knn.dist, knn.data)
+	}
+	return knn
+}
+
+// Remove data point at position i of GetDataPoints, it returns false if i is out of range
+func (knn *KNN) RemoveAt(i int) bool {
+	if i < 0 || i >= len(knn.data) {
+		return false
+	}
+	dp := knn.data[i]
+	knn.data = append(knn.data[:i:i], knn.data[i+1:]...)
+	if dynamic, ok := knn.index.(DynamicIndex); ok {
+		dynamic.Remove(dp)
+	} else if knn.index != nil {
+		knn.index.Build(knn.dist, knn.data)
+	}
+	return true
+}
+
+// Remove every data point that satisfies predicate and return the number of removed data points
+func (knn *KNN) Remove(predicate func(dp DataPoint) bool) int {
+	kept := make([]DataPoint, 0, len(knn.data))
+	removed := make([]DataPoint, 0, 10)
+	for _, dp := range knn.data {
+		if predicate(unscaled(dp)) {
+			removed = append(removed, dp)
+		} else {
+			kept = append(kept, dp)
+		}
+	}
+	if len(removed) == 0 {
+		return 0
+	}
+	knn.data = kept
+	if dynamic, ok := knn.index.(DynamicIndex); ok && !needsRebuild(len(removed), len(kept)) {
+		for _, dp := range removed {
+			dynamic.Remove(dp)
+		}
+	} else if knn.index != nil {
+		knn.index.Build(knn.dist, knn.data)
+	}
+	return len(removed)
+}
+
+// Build index with data points and use it for queries
+func (knn *KNN) SetIndex(index Index) *KNN {
+	index.Build(knn.dist, knn.data)
+	knn.index = index
 	return knn
 }
 
 func (knn *KNN) GetDataPoints() []DataPoint {
-	return knn.data
+	if knn.scaler == nil {
+		return knn.data
+	}
+	data := make([]DataPoint, len(knn.data))
+	for i, dp := range knn.data {
+		data[i] = unscaled(dp)
+	}
+	return data
+}
+
+// Fit scaler with stored points and use it for stored and queried points
+//
+// Neighbors returned by queries have scaled points
+func (knn *KNN) SetScaler(scaler Scaler) *KNN {
+	data := knn.GetDataPoints()
+	points := make([]Point, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+	}
+	scaler.Fit(points)
+	knn.scaler = scaler
+	knn.data = make([]DataPoint, len(data))
+	for i, dp := range data {
+		knn.data[i] = &scaledDataPoint{DataPoint: dp, point: scaler.Transform(points[i])}
+	}
+	if knn.index != nil {
+		knn.index.Build(knn.dist, knn.data)
+	}
+	return knn
 }
 
+// scale a queried point
+func (knn *KNN) transform(point Point) Point {
+	if knn.scaler == nil {
+		return point
+	}
+	return knn.scaler.Transform(point)
+}
+
+// Predict label of a point
+//
+// Deprecated: Fit predicts, use Predict instead
 func (knn *KNN) Fit(testData Point) any {
+	return knn.Predict(testData)
+}
 
-	distances := make([]DataDist, len(knn.data))
-	if plv > 1 {
-		wg := sync.WaitGroup{}
-		for i, d := range knn.data {
-			go func(i int, d DataPoint) {
-				defer wg.Done()
-				prll <- 0
-				distances[i] = newDataDist(knn.dist.Eval(d.Point(), testData), d)
-				<-prll
-			}(i, d)
-		}
-		wg.Wait()
-	} else {
-		for i, d := range knn.data {
-			distances[i] = newDataDist(knn.dist.Eval(d.Point(), testData), d)
+// Predict label of a point selecting it from its k nearest data points
+func (knn *KNN) Predict(testData Point) any {
+	testData = knn.transform(testData)
+	if knn.index != nil {
+		return knn.selector.Label(knn.index.KNearest(testData, knn.k))
+	}
+
+	return knn.selector.Label(knn.scan(testData, knn.k, knn.fan.Load()))
+}
+
+// k nearest data points by scanning every data point with a bounded max-heap in O(n log k),
+// every goroutine of the fan-out scans a chunk and partial results are merged
+func (knn *KNN) scan(point Point, k int, fo *fanout) []DataDist {
+	kset, _ := knn.scanCtx(context.Background(), point, k, fo)
+	return kset
+}
+
+// distance of data point to point with the distance of model
+func (knn *KNN) eval(d DataPoint, point Point) float64 {
+	return evalData(knn.dist, d, point)
+}
+
+// data points scanned between checks of context cancellation
+const ctxCheckInterval = 1024
+
+// scan that stops and returns the context error when context is done
+func (knn *KNN) scanCtx(ctx context.Context, point Point, k int, fo *fanout) ([]DataDist, error) {
+	parts := make([]*nearest, 0, fo.lv)
+	mtx := sync.Mutex{}
+	done := ctx.Done()
+	fo.chunks(len(knn.data), func(lo, hi int) {
+		set := newNearest(k)
+		for i := lo; i < hi; i++ {
+			if done != nil && (i-lo)%ctxCheckInterval == 0 && ctx.Err() != nil {
+				return
+			}
+			d := knn.data[i]
+			set.pushAt(knn.eval(d, point), d, i)
 		}
+		mtx.Lock()
+		parts = append(parts, set)
+		mtx.Unlock()
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// data indices break ties, so results don't depend on the order workers finish
+	set := parts[0]
+	for _, part := range parts[1:] {
+		set.merge(part)
+	}
+	return set.sorted(), nil
+}
+
+// Get the k nearest data points of a point with their distances, sorted by distance
+func (knn *KNN) KNeighbors(point Point, k int) []DataDist {
+	if k <= 0 {
+		panic(ErrKIsNotValid)
 	}
+	return knn.kNearest(knn.transform(point), k)
+}
 
-	sort.Slice(distances, func(i, j int) bool {
-		return distances[i].Dist() < distances[j].Dist()
+// Get data points with distance to point lesser or equal to radius, sorted by distance
+func (knn *KNN) RadiusNeighbors(point Point, radius float64) []DataDist {
+	point = knn.transform(point)
+	if knn.index != nil {
+		return knn.index.Range(point, radius)
+	}
+	found := make([]DataDist, 0, 10)
+	for _, d := range knn.data {
+		if dist := knn.eval(d, point); dist <= radius {
+			found = append(found, newDataDist(dist, d))
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Dist() < found[j].Dist()
 	})
+	return found
+}
 
-	kset := distances[:knn.k]
+// k nearest data points of a point, evaluated in the calling goroutine
+func (knn *KNN) kNearest(point Point, k int) []DataDist {
+	if knn.index != nil {
+		return knn.index.KNearest(point, k)
+	}
+	return knn.scan(point, k, sequential)
+}
 
-	return knn.selector.Label(kset)
+// Predict labels of many points, queries are distributed among the workers of this KNN
+func (knn *KNN) PredictBatch(points []Point) []any {
+	labels := make([]any, len(points))
+	knn.fan.Load().forEach(len(points), func(i int) {
+		labels[i] = knn.selector.Label(knn.kNearest(knn.transform(points[i]), knn.k))
+	})
+	return labels
 }
 
 type dataPoint struct {
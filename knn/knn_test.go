@@ -2,6 +2,9 @@ package knn
 
 import (
 	"math"
+	"math/rand"
+	"sort"
+	"sync"
 	"testing"
 )
 
@@ -158,3 +161,267 @@ func TestKNNFit(t *testing.T) {
 		t.Errorf("KNNFit failed. Expected true, but got %v", l)
 	}
 }
+
+func randomDataPoints(rnd *rand.Rand, n, dim int) []DataPoint {
+	data := make([]DataPoint, n)
+	for i := range data {
+		p := NewPoint(dim)
+		for d := range p {
+			p[d] = rnd.Float64()
+		}
+		data[i] = NewDataPoint(i, p)
+	}
+	return data
+}
+
+func TestKDTree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	data := randomDataPoints(rnd, 500, 3)
+	dist := NewEuclideanDist()
+	tree := NewKDTree()
+	tree.Build(dist, data)
+	for q := 0; q < 20; q++ {
+		query := randomDataPoints(rnd, 1, 3)[0].Point()
+		brute := make([]float64, len(data))
+		for i, d := range data {
+			brute[i] = dist.Eval(d.Point(), query)
+		}
+		sort.Float64s(brute)
+		kset := tree.KNearest(query, 5)
+		for i := range kset {
+			if kset[i].Dist() != brute[i] {
+				t.Fatalf("KDTree KNearest failed. Expected %v, but got %v", brute[i], kset[i].Dist())
+			}
+		}
+		found := tree.Range(query, 0.2)
+		expected := sort.SearchFloat64s(brute, math.Nextafter(0.2, 1))
+		if len(found) != expected {
+			t.Fatalf("KDTree Range failed. Expected %v points, but got %v", expected, len(found))
+		}
+	}
+}
+
+func TestKDTreeMinkowski(t *testing.T) {
+	data := randomDataPoints(rand.New(rand.NewSource(2)), 10, 2)
+	NewKDTree().Build(NewMinkowskiDist(3), data)
+	for _, dist := range []Distance{NewMinkowskiDist(0.5), NewWeightedDist(NewMinkowskiDist(0), []float64{1, 1})} {
+		func() {
+			defer func() {
+				if err := recover(); err != ErrDistanceNotSupported {
+					t.Errorf("KDTree Build failed. Expected panic %v, but got %v", ErrDistanceNotSupported, err)
+				}
+			}()
+			NewKDTree().Build(dist, data)
+		}()
+	}
+}
+
+func TestKNNWithKDTree(t *testing.T) {
+	dataPoints := []DataPoint{
+		&dataPoint{WithPoint(0.0, 0.0), true},
+		&dataPoint{WithPoint(1.0, 1.0), true},
+		&dataPoint{WithPoint(2.0, 2.0), false},
+	}
+	knn := NewKNN(3, NewEuclideanDist(), NewBinarySelector(), dataPoints).SetIndex(NewKDTree())
+	knn.Append(&dataPoint{WithPoint(3.0, 3.0), false})
+	if l := knn.Fit(WithPoint(2.5, 2.5)); l != false {
+		t.Errorf("KNNFit with KDTree failed. Expected false, but got %v", l)
+	}
+}
+
+func TestLSH(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	data := randomDataPoints(rnd, 300, 8)
+	dist := NewEuclideanDist()
+	for _, index := range []Index{NewEuclideanLSH(8, 4, 0.5, 1), NewCosineLSH(8, 6, 1)} {
+		index.Build(dist, data)
+		// a stored point must be found as its own nearest neighbor
+		for i := 0; i < 20; i++ {
+			kset := index.KNearest(data[i].Point(), 3)
+			if len(kset) != 3 || kset[0].Dist() != 0 {
+				t.Fatalf("LSH KNearest failed. Expected stored point at distance 0, but got %v", kset[0].Dist())
+			}
+			if found := index.Range(data[i].Point(), 0); len(found) == 0 {
+				t.Fatalf("LSH Range failed. Expected stored point in range")
+			}
+		}
+	}
+}
+
+func TestKNNPredictBatch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	data := randomDataPoints(rnd, 200, 2)
+	for _, d := range data {
+		d.(*dataPoint).label = d.Point()[0] > 0.5
+	}
+	knn := NewKNN(5, NewEuclideanDist(), NewBinarySelector(), data)
+	queries := make([]Point, 50)
+	for i := range queries {
+		queries[i] = randomDataPoints(rnd, 1, 2)[0].Point()
+	}
+	expected := make([]any, len(queries))
+	for i, q := range queries {
+		expected[i] = knn.Fit(q)
+	}
+	labels := knn.SetParallelLv(4).PredictBatch(queries)
+	for i := range queries {
+		if labels[i] != expected[i] {
+			t.Errorf("PredictBatch failed. Expected %v, but got %v", expected[i], labels[i])
+		}
+	}
+}
+
+func TestKNNKNeighbors(t *testing.T) {
+	dataPoints := []DataPoint{
+		&dataPoint{WithPoint(0.0, 0.0), "a"},
+		&dataPoint{WithPoint(3.0, 4.0), "b"},
+		&dataPoint{WithPoint(1.0, 0.0), "c"},
+	}
+	knn := NewKNN(1, NewEuclideanDist(), NewMultiClassSelector(), dataPoints)
+	kset := knn.KNeighbors(WithPoint(0.0, 0.0), 2)
+	if len(kset) != 2 || kset[0].DataPoint().Label() != "a" || kset[1].DataPoint().Label() != "c" || kset[1].Dist() != 1 {
+		t.Errorf("KNeighbors failed. Unexpected neighbors %v", kset)
+	}
+	if l := knn.Predict(WithPoint(3.0, 3.0)); l != "b" {
+		t.Errorf("Predict failed. Expected b, but got %v", l)
+	}
+}
+
+// run with -race, parallel Predict must not deadlock nor race under concurrent load
+func TestKNNParallelPredict(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	data := randomDataPoints(rnd, 1000, 3)
+	for _, d := range data {
+		d.(*dataPoint).label = d.Point()[0] > 0.5
+	}
+	sequential := NewKNN(7, NewEuclideanDist(), NewBinarySelector(), data)
+	parallel := NewKNN(7, NewEuclideanDist(), NewBinarySelector(), data, WithParallelLv(8))
+	if parallel.GetParallelLv() != 8 || sequential.GetParallelLv() != GetParallelLv() {
+		t.Fatalf("SetParallelLv failed. Parallelism level is shared between instances")
+	}
+	queries := randomDataPoints(rnd, 64, 3)
+	wg := sync.WaitGroup{}
+	for _, q := range queries {
+		wg.Add(1)
+		go func(q Point) {
+			defer wg.Done()
+			if l, expected := parallel.Predict(q), sequential.Predict(q); l != expected {
+				t.Errorf("Parallel Predict failed. Expected %v, but got %v", expected, l)
+			}
+		}(q.Point())
+	}
+	wg.Wait()
+}
+
+func TestKNNScan(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	data := randomDataPoints(rnd, 100, 2)
+	knn := NewKNN(3, NewEuclideanDist(), NewMultiClassSelector(), data).SetParallelLv(3)
+	query := WithPoint(0.5, 0.5)
+	brute := make([]float64, len(data))
+	for i, d := range data {
+		brute[i] = knn.dist.Eval(d.Point(), query)
+	}
+	sort.Float64s(brute)
+	for _, k := range []int{1, 10, 100, 150} {
+		kset := knn.scan(query, k, knn.fan.Load())
+		if expected := int(math.Min(float64(k), 100)); len(kset) != expected {
+			t.Fatalf("scan failed. Expected %d neighbors, but got %d", expected, len(kset))
+		}
+		for i := range kset {
+			if kset[i].Dist() != brute[i] {
+				t.Fatalf("scan failed. Expected %v, but got %v", brute[i], kset[i].Dist())
+			}
+		}
+	}
+}
+
+func TestKNNScanTies(t *testing.T) {
+	// every data point is at the same distance of the query
+	data := make([]DataPoint, 40)
+	for i := range data {
+		data[i] = NewDataPoint(i, WithPoint(1, 0))
+	}
+	knn := NewKNN(5, NewEuclideanDist(), NewMultiClassSelector(), data).SetParallelLv(7)
+	for run := 0; run < 20; run++ {
+		for i, dd := range knn.KNeighbors(WithPoint(0, 0), 5) {
+			if l := dd.DataPoint().Label(); l != i {
+				t.Fatalf("scan failed. Expected ties broken by index %d, but got %v", i, l)
+			}
+		}
+	}
+}
+
+// run with -race, parallelism level may change while other goroutines predict
+func TestKNNSetParallelLvConcurrent(t *testing.T) {
+	rnd := rand.New(rand.NewSource(6))
+	knn := NewKNN(3, NewEuclideanDist(), NewMultiClassSelector(), randomDataPoints(rnd, 200, 2))
+	queries := []Point{WithPoint(0.1, 0.2), WithPoint(0.5, 0.5), WithPoint(0.9, 0.1)}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for lv := 1; lv <= 8; lv++ {
+			knn.SetParallelLv(lv)
+		}
+	}()
+	for i := 0; i < 8; i++ {
+		knn.PredictBatch(queries)
+	}
+	wg.Wait()
+	if knn.GetParallelLv() != 8 {
+		t.Errorf("SetParallelLv failed. Expected 8, but got %d", knn.GetParallelLv())
+	}
+}
+
+func TestKNNDynamicIndex(t *testing.T) {
+	rnd := rand.New(rand.NewSource(8))
+	for _, index := range []Index{NewKDTree(), NewEuclideanLSH(4, 2, 1, 1)} {
+		indexed := NewKNN(3, NewEuclideanDist(), NewMultiClassSelector(), randomDataPoints(rnd, 50, 2)).SetIndex(index)
+		for step := 0; step < 300; step++ {
+			if rnd.Intn(3) == 0 && len(indexed.data) > 10 {
+				if !indexed.RemoveAt(rnd.Intn(len(indexed.data))) {
+					t.Fatalf("RemoveAt failed. Expected true")
+				}
+			} else {
+				indexed.Append(randomDataPoints(rnd, 1, 2)[0])
+			}
+		}
+		removed := indexed.Remove(func(dp DataPoint) bool {
+			return dp.Point()[0] < 0.1
+		})
+		if removed == 0 {
+			t.Fatalf("Remove failed. Expected some removed data points")
+		}
+		brute := NewKNN(3, NewEuclideanDist(), NewMultiClassSelector(), indexed.GetDataPoints())
+		for q := 0; q < 20; q++ {
+			query := randomDataPoints(rnd, 1, 2)[0].Point()
+			expected, got := brute.KNeighbors(query, 3), indexed.KNeighbors(query, 3)
+			for i := range expected {
+				if expected[i].Dist() != got[i].Dist() {
+					t.Fatalf("KNeighbors with dynamic index failed. Expected %v, but got %v", expected[i].Dist(), got[i].Dist())
+				}
+				if got[i].DataPoint().Point()[0] < 0.1 {
+					t.Fatalf("KNeighbors with dynamic index failed. Removed data point was found")
+				}
+			}
+		}
+	}
+}
+
+func TestKNNOptions(t *testing.T) {
+	data := []DataPoint{
+		NewDataPoint("a", WithPoint(0, 1000)),
+		NewDataPoint("a", WithPoint(0.1, 1200)),
+		NewDataPoint("b", WithPoint(1, 1100)),
+		NewDataPoint("b", WithPoint(0.9, 1300)),
+	}
+	knn := NewKNN(1, NewEuclideanDist(), NewMultiClassSelector(), data,
+		WithIndex(NewKDTree()), WithScaler(NewStandardScaler()), WithParallelLv(3))
+	if knn.GetParallelLv() != 3 || knn.index == nil || knn.scaler == nil {
+		t.Fatalf("NewKNN options failed. Options were not applied")
+	}
+	if l := knn.Predict(WithPoint(0.9, 1020)); l != "b" {
+		t.Errorf("NewKNN options failed. Expected b, but got %v", l)
+	}
+}
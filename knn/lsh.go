@@ -0,0 +1,238 @@
+package knn
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+var ErrLSHParamsNotValid = fmt.Errorf("lsh tables and bits are not in range [1, 64] or width is not positive")
+
+type lshFamily int
+
+const (
+	hyperplaneFamily lshFamily = iota + 1 //random hyperplanes, for cosine similarity
+	pStableFamily                         //gaussian projections, for euclidean distance
+)
+
+// Locality-sensitive hashing approximate index
+//
+// Points are hashed in several tables, queries only evaluate distance for points in the same buckets,
+// if buckets have less than k points the query falls back to a full scan.
+//
+// Projection vectors are not stored, their values are generated from seed when they are needed, and sparse
+// data points are hashed by their nonzero dimensions, so very high-dimensional sparse data can be indexed
+type LSH struct {
+	family  lshFamily
+	tables  int
+	bits    int
+	width   float64
+	seed    int64
+	dist    Distance
+	data    []DataPoint
+	deleted []bool
+	removed int
+	dim     int                //dimension of points, zero before the first point
+	offsets [][]float64        //offsets of p-stable projections of every table
+	buckets []map[uint64][]int //data points by hash of every table
+}
+
+// LSH index with random hyperplanes for cosine similarity
+func NewCosineLSH(tables, bits int, seed int64) *LSH {
+	if tables < 1 || tables > 64 || bits < 1 || bits > 64 {
+		panic(ErrLSHParamsNotValid)
+	}
+	return &LSH{family: hyperplaneFamily, tables: tables, bits: bits, seed: seed}
+}
+
+// LSH index with p-stable gaussian projections quantized by width for euclidean distance
+func NewEuclideanLSH(tables, bits int, width float64, seed int64) *LSH {
+	if tables < 1 || tables > 64 || bits < 1 || bits > 64 || !(width > 0) || math.IsInf(width, 1) {
+		panic(ErrLSHParamsNotValid)
+	}
+	return &LSH{family: pStableFamily, tables: tables, bits: bits, width: width, seed: seed}
+}
+
+func (ls *LSH) Build(dist Distance, data []DataPoint) {
+	ls.dist = dist
+	ls.data = append([]DataPoint{}, data...)
+	ls.deleted = make([]bool, len(data))
+	ls.removed = 0
+	ls.buckets = make([]map[uint64][]int, ls.tables)
+	for t := range ls.buckets {
+		ls.buckets[t] = make(map[uint64][]int)
+	}
+	if len(data) == 0 {
+		ls.dim = 0
+		return
+	}
+	ls.dim = dataVector(data[0]).Dim()
+	rnd := rand.New(rand.NewSource(ls.seed))
+	ls.offsets = make([][]float64, ls.tables)
+	for t := range ls.offsets {
+		ls.offsets[t] = make([]float64, ls.bits)
+		for b := range ls.offsets[t] {
+			ls.offsets[t][b] = rnd.Float64() * ls.width
+		}
+	}
+	for i, dp := range data {
+		for t, key := range ls.hashes(dataVector(dp)) {
+			ls.buckets[t][key] = append(ls.buckets[t][key], i)
+		}
+	}
+}
+
+// mix bits of x (splitmix64 finalizer)
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// gaussian value of dimension d of projection vector of bit b of table t, the same for the same seed
+func (ls *LSH) project(t, b, d int) float64 {
+	h1 := mix(uint64(ls.seed) ^ mix(uint64(d)) ^ mix(uint64(t*64+b)+0x9e3779b97f4a7c15))
+	h2 := mix(h1 + 0x9e3779b97f4a7c15)
+	// Box-Muller transform of two uniform values in (0, 1]
+	u1 := (float64(h1>>11) + 1) / (1 << 53)
+	u2 := float64(h2>>11) / (1 << 53)
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+// hash of vector in every table, only nonzero dimensions are projected
+func (ls *LSH) hashes(v Vector) []uint64 {
+	if v.Dim() != ls.dim {
+		panic(ErrPointDimensionMismatch)
+	}
+	dots := make([]float64, ls.tables*ls.bits)
+	v.Range(func(d int, value float64) {
+		for k := range dots {
+			dots[k] += ls.project(k/ls.bits, k%ls.bits, d) * value
+		}
+	})
+	keys := make([]uint64, ls.tables)
+	for t := range keys {
+		var key uint64
+		for b, dot := range dots[t*ls.bits : (t+1)*ls.bits] {
+			if ls.family == hyperplaneFamily {
+				if dot >= 0 {
+					key |= 1 << uint(b)
+				}
+			} else {
+				// mix quantized projections in a single key (FNV-1a)
+				h := uint64(int64(math.Floor((dot + ls.offsets[t][b]) / ls.width)))
+				if b == 0 {
+					key = 14695981039346656037
+				}
+				key ^= h
+				key *= 1099511628211
+			}
+		}
+		keys[t] = key
+	}
+	return keys
+}
+
+// indices of data points that share a bucket with point in some table
+func (ls *LSH) candidates(point Point) []int {
+	seen := make(map[int]bool)
+	candidates := make([]int, 0, 10)
+	for t, key := range ls.hashes(point) {
+		for _, i := range ls.buckets[t][key] {
+			if !seen[i] && !ls.deleted[i] {
+				seen[i] = true
+				candidates = append(candidates, i)
+			}
+		}
+	}
+	return candidates
+}
+
+func (ls *LSH) KNearest(point Point, k int) []DataDist {
+	if k <= 0 {
+		panic(ErrKIsNotValid)
+	}
+	set := newNearest(k)
+	if len(ls.data) == 0 {
+		return set.sorted()
+	}
+	candidates := ls.candidates(point)
+	if len(candidates) < k {
+		// not enough candidates, scan every point
+		for i, dp := range ls.data {
+			if !ls.deleted[i] {
+				set.push(evalData(ls.dist, dp, point), dp)
+			}
+		}
+		return set.sorted()
+	}
+	for _, i := range candidates {
+		set.push(evalData(ls.dist, ls.data[i], point), ls.data[i])
+	}
+	return set.sorted()
+}
+
+func (ls *LSH) Range(point Point, radius float64) []DataDist {
+	found := make([]DataDist, 0, 10)
+	if len(ls.data) == 0 {
+		return found
+	}
+	for _, i := range ls.candidates(point) {
+		if dist := evalData(ls.dist, ls.data[i], point); dist <= radius {
+			found = append(found, newDataDist(dist, ls.data[i]))
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Dist() < found[j].Dist()
+	})
+	return found
+}
+
+func (ls *LSH) Insert(dp DataPoint) {
+	if ls.dist == nil {
+		panic(ErrIndexNotBuilt)
+	}
+	if ls.dim == 0 {
+		// hash functions need the dimension of points
+		ls.Build(ls.dist, []DataPoint{dp})
+		return
+	}
+	keys := ls.hashes(dataVector(dp))
+	i := len(ls.data)
+	ls.data = append(ls.data, dp)
+	ls.deleted = append(ls.deleted, false)
+	for t, key := range keys {
+		ls.buckets[t][key] = append(ls.buckets[t][key], i)
+	}
+}
+
+func (ls *LSH) Remove(dp DataPoint) bool {
+	if ls.dim == 0 {
+		return false
+	}
+	for _, i := range ls.buckets[0][ls.hashes(dataVector(dp))[0]] {
+		if ls.data[i] == dp && !ls.deleted[i] {
+			ls.deleted[i] = true
+			ls.removed++
+			if needsRebuild(ls.removed, len(ls.data)-ls.removed) {
+				ls.compact()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// hash again data points that are not deleted
+func (ls *LSH) compact() {
+	live := make([]DataPoint, 0, len(ls.data)-ls.removed)
+	for i, dp := range ls.data {
+		if !ls.deleted[i] {
+			live = append(live, dp)
+		}
+	}
+	ls.Build(ls.dist, live)
+}
@@ -0,0 +1,40 @@
+package knn
+
+import "github.com/stellviaproject/go-ia/nn/graph"
+
+// size of square tiles of the distance matrix evaluated by a worker
+const tileSize = 64
+
+// Pairwise distances of points as a Float64 tensor of shape (n, n)
+//
+// Upper triangle is split in square tiles evaluated by lv workers, then it is mirrored
+func DistanceMatrix(points []Point, dist Distance, lv int) *graph.Tensor {
+	n := len(points)
+	if n == 0 {
+		panic(ErrNotEnoughData)
+	}
+	tensor := graph.NewTensor(nil, graph.Float64, graph.NewShape(n, n))
+	data := tensor.F64Slice()
+	tiles := make([][2]int, 0, (n/tileSize+1)*(n/tileSize+2)/2)
+	for ti := 0; ti < n; ti += tileSize {
+		for tj := ti; tj < n; tj += tileSize {
+			tiles = append(tiles, [2]int{ti, tj})
+		}
+	}
+	newFanout(lv).forEach(len(tiles), func(t int) {
+		ti, tj := tiles[t][0], tiles[t][1]
+		for i := ti; i < ti+tileSize && i < n; i++ {
+			start := tj
+			if start < i+1 {
+				start = i + 1
+			}
+			for j := start; j < tj+tileSize && j < n; j++ {
+				d := dist.Eval(points[i], points[j])
+				// matrix is symmetric so index order doesn't depend on tensor layout
+				data[i*n+j] = d
+				data[j*n+i] = d
+			}
+		}
+	})
+	return tensor
+}
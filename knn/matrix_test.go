@@ -0,0 +1,24 @@
+package knn
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDistanceMatrix(t *testing.T) {
+	rnd := rand.New(rand.NewSource(10))
+	data := randomDataPoints(rnd, 150, 3)
+	points := make([]Point, len(data))
+	for i, d := range data {
+		points[i] = d.Point()
+	}
+	dist := NewManhattanDist()
+	matrix := DistanceMatrix(points, dist, 4)
+	for i := range points {
+		for j := range points {
+			if got := matrix.GetF64At([]int{i, j}); got != dist.Eval(points[i], points[j]) {
+				t.Fatalf("DistanceMatrix failed. Expected %v at (%d, %d), but got %v", dist.Eval(points[i], points[j]), i, j, got)
+			}
+		}
+	}
+}
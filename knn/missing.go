@@ -0,0 +1,118 @@
+package knn
+
+import (
+	"math"
+	"sort"
+)
+
+type nanEuclidean struct {
+	penalty float64 //distance added by every missing dimension, negative to ignore and rescale
+}
+
+// Euclidean distance that ignores dimensions with NaN in any point and scales the sum by dim / present dims
+//
+// Points without present dimensions in common are at distance +Inf
+func NewNaNEuclideanDist() Distance {
+	return &nanEuclidean{penalty: -1}
+}
+
+// Euclidean distance where every dimension with NaN in any point adds penalty² to the sum
+func NewNaNPenaltyDist(penalty float64) Distance {
+	if penalty < 0 {
+		panic(ErrWeightsNotValid)
+	}
+	return &nanEuclidean{penalty: penalty}
+}
+
+func (na *nanEuclidean) Eval(p1, p2 Point) float64 {
+	if p1.Dim() != p2.Dim() {
+		panic(ErrPointDimensionMismatch)
+	}
+	sum := 0.0
+	present := 0
+	for i, ln := 0, len(p1); i < ln; i++ {
+		if math.IsNaN(p1[i]) || math.IsNaN(p2[i]) {
+			if na.penalty >= 0 {
+				sum += na.penalty * na.penalty
+			}
+			continue
+		}
+		dif := p1[i] - p2[i]
+		sum += dif * dif
+		present++
+	}
+	if na.penalty >= 0 {
+		return math.Sqrt(sum)
+	}
+	if present == 0 {
+		return math.Inf(1)
+	}
+	return math.Sqrt(sum * float64(len(p1)) / float64(present))
+}
+
+// Imputer that fills missing values (NaN) with the mean of the k nearest points that have the value
+//
+// It implements Scaler, so it can be set to a KNN to impute stored and queried points
+type KNNImputer struct {
+	k      int
+	dist   Distance
+	points []Point
+}
+
+// Create an imputer, dist must tolerate NaN, e.g. NewNaNEuclideanDist
+func NewKNNImputer(k int, dist Distance) *KNNImputer {
+	if k <= 0 {
+		panic(ErrKIsNotValid)
+	}
+	return &KNNImputer{k: k, dist: dist}
+}
+
+func (im *KNNImputer) Fit(points []Point) {
+	checkPoints(points)
+	im.points = make([]Point, len(points))
+	for i, p := range points {
+		im.points[i] = append(Point{}, p...)
+	}
+}
+
+func (im *KNNImputer) Transform(point Point) Point {
+	out := append(Point{}, point...)
+	missing := false
+	for _, v := range point {
+		if math.IsNaN(v) {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return out
+	}
+	order := make([]int, len(im.points))
+	dists := make([]float64, len(im.points))
+	for i, p := range im.points {
+		order[i] = i
+		dists[i] = im.dist.Eval(p, point)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return dists[order[a]] < dists[order[b]]
+	})
+	for d, v := range point {
+		if !math.IsNaN(v) {
+			continue
+		}
+		sum, count := 0.0, 0
+		for _, i := range order {
+			if count == im.k {
+				break
+			}
+			if x := im.points[i][d]; !math.IsNaN(x) && !math.IsInf(dists[i], 1) {
+				sum += x
+				count++
+			}
+		}
+		if count > 0 {
+			out[d] = sum / float64(count)
+		}
+	}
+	return out
+}
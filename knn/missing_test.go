@@ -0,0 +1,33 @@
+package knn
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNaNDistances(t *testing.T) {
+	nan := math.NaN()
+	p1 := WithPoint(0, nan, 0)
+	p2 := WithPoint(3, 5, 4)
+	if d := NewNaNEuclideanDist().Eval(p1, p2); math.Abs(d-math.Sqrt(25*1.5)) > 1e-12 {
+		t.Errorf("NaNEuclideanEval failed. Expected %v, but got %v", math.Sqrt(25*1.5), d)
+	}
+	if d := NewNaNPenaltyDist(2).Eval(p1, p2); math.Abs(d-math.Sqrt(29)) > 1e-12 {
+		t.Errorf("NaNPenaltyEval failed. Expected %v, but got %v", math.Sqrt(29), d)
+	}
+}
+
+func TestKNNImputer(t *testing.T) {
+	nan := math.NaN()
+	im := NewKNNImputer(2, NewNaNEuclideanDist())
+	im.Fit([]Point{
+		WithPoint(0, 10),
+		WithPoint(1, 20),
+		WithPoint(100, 1000),
+		WithPoint(0.5, nan),
+	})
+	p := im.Transform(WithPoint(0.4, nan))
+	if p[0] != 0.4 || p[1] != 15 {
+		t.Errorf("KNNImputer failed. Expected [0.4 15], but got %v", p)
+	}
+}
@@ -0,0 +1,571 @@
+package knn
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+var (
+	ErrNotSerializable = fmt.Errorf("model component is not serializable")
+	ErrModelCorrupt    = fmt.Errorf("knn model is corrupt")
+)
+
+const modelVersion = 1
+
+// serialized distance
+type distSpec struct {
+	Kind   string
+	Params []float64
+	Base   *distSpec
+}
+
+// serialized selector
+type selectorSpec struct {
+	Kind    string
+	Params  []float64
+	Labels  []any
+	Weights []float64
+}
+
+// serialized scaler
+type scalerSpec struct {
+	Kind string
+	A    []float64
+	B    []float64
+}
+
+// serialized index
+type indexSpec struct {
+	Kind    string
+	Nodes   []int //kd-tree nodes as (point, axis, left, right)
+	Root    int
+	Family  int
+	Tables  int
+	Bits    int
+	Width   float64
+	Seed    int64
+	Planes  [][][]float64 //lsh projections of older models, their index is built again
+	Dim     int           //dimension of lsh projections
+	Offsets [][]float64
+	Buckets []map[uint64][]int
+}
+
+// serialized data point
+type dataSpec struct {
+	Point   []float64
+	Label   any
+	Dim     int   //dimension of a sparse point, zero for dense points
+	Indices []int //nonzero dimensions of a sparse point, values are in Point
+}
+
+// serialized KNN model
+type modelSpec struct {
+	Version  int
+	K        int
+	Parallel int
+	Dist     *distSpec
+	Selector *selectorSpec
+	Scaler   *scalerSpec
+	Index    *indexSpec
+	Data     []dataSpec
+}
+
+func boolParam(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func encodeDist(dist Distance) (*distSpec, error) {
+	switch d := dist.(type) {
+	case *euclidean:
+		return &distSpec{Kind: "euclidean"}, nil
+	case *manhattan:
+		return &distSpec{Kind: "manhattan"}, nil
+	case *minkowski:
+		return &distSpec{Kind: "minkowski", Params: []float64{d.ratio}}, nil
+	case *chebyshev:
+		return &distSpec{Kind: "chebyshev"}, nil
+	case *pearsonCorrelation:
+		return &distSpec{Kind: "pearson"}, nil
+	case *hamming:
+		return &distSpec{Kind: "hamming"}, nil
+	case *cosine:
+		return &distSpec{Kind: "cosine", Params: []float64{boolParam(d.normalized)}}, nil
+	case *angular:
+		return &distSpec{Kind: "angular", Params: []float64{boolParam(d.normalized)}}, nil
+	case *mahalanobis:
+		return &distSpec{Kind: "mahalanobis", Params: d.inv.Data()}, nil
+	case *jaccard:
+		return &distSpec{Kind: "jaccard"}, nil
+	case *dice:
+		return &distSpec{Kind: "dice"}, nil
+	case *canberra:
+		return &distSpec{Kind: "canberra"}, nil
+	case *haversine:
+		return &distSpec{Kind: "haversine", Params: []float64{d.radius}}, nil
+	case *nanEuclidean:
+		return &distSpec{Kind: "nan_euclidean", Params: []float64{d.penalty}}, nil
+	case *sparseEuclidean:
+		return &distSpec{Kind: "sparse_euclidean"}, nil
+	case *sparseCosine:
+		return &distSpec{Kind: "sparse_cosine"}, nil
+	case *weighted:
+		base, err := encodeDist(d.base)
+		if err != nil {
+			return nil, err
+		}
+		return &distSpec{Kind: "weighted", Params: d.weights, Base: base}, nil
+	}
+	return nil, ErrNotSerializable
+}
+
+// error of a corrupt component unless it has n params
+func checkParams(kind string, params []float64, n int) error {
+	if len(params) != n {
+		return fmt.Errorf("%w: %s has %d params instead of %d", ErrModelCorrupt, kind, len(params), n)
+	}
+	return nil
+}
+
+func decodeDist(spec *distSpec) (Distance, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("%w: distance is missing", ErrModelCorrupt)
+	}
+	params := map[string]int{"minkowski": 1, "cosine": 1, "angular": 1, "haversine": 1, "nan_euclidean": 1}
+	if n, ok := params[spec.Kind]; ok {
+		if err := checkParams(spec.Kind, spec.Params, n); err != nil {
+			return nil, err
+		}
+	}
+	switch spec.Kind {
+	case "euclidean":
+		return NewEuclideanDist(), nil
+	case "manhattan":
+		return NewManhattanDist(), nil
+	case "minkowski":
+		return NewMinkowskiDist(spec.Params[0]), nil
+	case "chebyshev":
+		return NewChebyshevDist(), nil
+	case "pearson":
+		return NewPearsonCorrelationDist(), nil
+	case "hamming":
+		return NewHammingDist(), nil
+	case "cosine":
+		return NewCosineDist(spec.Params[0] == 1), nil
+	case "angular":
+		return NewAngularDist(spec.Params[0] == 1), nil
+	case "mahalanobis":
+		dim := 0
+		for dim*dim < len(spec.Params) {
+			dim++
+		}
+		if dim == 0 || dim*dim != len(spec.Params) {
+			return nil, fmt.Errorf("%w: mahalanobis matrix has %d elements", ErrModelCorrupt, len(spec.Params))
+		}
+		return &mahalanobis{inv: linalg.NewMatrix(dim, dim, spec.Params)}, nil
+	case "jaccard":
+		return NewJaccardDist(), nil
+	case "dice":
+		return NewDiceDist(), nil
+	case "canberra":
+		return NewCanberraDist(), nil
+	case "haversine":
+		return NewHaversineDist(spec.Params[0]), nil
+	case "nan_euclidean":
+		return &nanEuclidean{penalty: spec.Params[0]}, nil
+	case "sparse_euclidean":
+		return NewSparseEuclideanDist(), nil
+	case "sparse_cosine":
+		return NewSparseCosineDist(), nil
+	case "weighted":
+		base, err := decodeDist(spec.Base)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range spec.Params {
+			if !(w >= 0) {
+				return nil, fmt.Errorf("%w: %v", ErrModelCorrupt, ErrWeightsNotValid)
+			}
+		}
+		return NewWeightedDist(base, spec.Params), nil
+	}
+	return nil, ErrNotSerializable
+}
+
+func encodeSelector(selector Selector) (*selectorSpec, error) {
+	switch s := selector.(type) {
+	case *binarySelector:
+		return &selectorSpec{Kind: "binary"}, nil
+	case *multiClassSelector:
+		return &selectorSpec{Kind: "multiclass"}, nil
+	case *regressionSelector:
+		return &selectorSpec{Kind: "regression"}, nil
+	case *weightedRegressionSelector:
+		return &selectorSpec{Kind: "weighted_regression", Params: []float64{s.power}}, nil
+	case *inverseDistanceSelector:
+		return &selectorSpec{Kind: "inverse_distance"}, nil
+	case *smoothInverseDistanceSelector:
+		return &selectorSpec{Kind: "smooth_inverse_distance", Params: []float64{s.WeightParam, s.SmoothingParam}}, nil
+	case *weightedVotingSelector:
+		spec := &selectorSpec{Kind: "weighted_voting"}
+		for label, weight := range s.weights {
+			spec.Labels = append(spec.Labels, label)
+			spec.Weights = append(spec.Weights, weight)
+		}
+		return spec, nil
+	}
+	return nil, ErrNotSerializable
+}
+
+func decodeSelector(spec *selectorSpec) (Selector, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("%w: selector is missing", ErrModelCorrupt)
+	}
+	params := map[string]int{"weighted_regression": 1, "smooth_inverse_distance": 2}
+	if n, ok := params[spec.Kind]; ok {
+		if err := checkParams(spec.Kind, spec.Params, n); err != nil {
+			return nil, err
+		}
+	}
+	switch spec.Kind {
+	case "binary":
+		return NewBinarySelector(), nil
+	case "multiclass":
+		return NewMultiClassSelector(), nil
+	case "regression":
+		return NewRegressionSelector(), nil
+	case "weighted_regression":
+		return NewWeightedRegressionSelector(spec.Params[0]), nil
+	case "inverse_distance":
+		return NewInverseDistanceSelector(), nil
+	case "smooth_inverse_distance":
+		return NewSmoothInverseDistanceSelector(spec.Params[0], spec.Params[1]), nil
+	case "weighted_voting":
+		if len(spec.Labels) != len(spec.Weights) {
+			return nil, fmt.Errorf("%w: weighted voting has %d labels and %d weights", ErrModelCorrupt, len(spec.Labels), len(spec.Weights))
+		}
+		selector := NewWeightedVotingSelector()
+		for i, label := range spec.Labels {
+			selector.Set(label, spec.Weights[i])
+		}
+		return selector, nil
+	}
+	return nil, ErrNotSerializable
+}
+
+func encodeScaler(scaler Scaler) (*scalerSpec, error) {
+	switch s := scaler.(type) {
+	case *StandardScaler:
+		return &scalerSpec{Kind: "standard", A: s.Mean, B: s.Std}, nil
+	case *MinMaxScaler:
+		return &scalerSpec{Kind: "minmax", A: s.Min, B: s.Max}, nil
+	case *RobustScaler:
+		return &scalerSpec{Kind: "robust", A: s.Median, B: s.IQR}, nil
+	}
+	return nil, ErrNotSerializable
+}
+
+func decodeScaler(spec *scalerSpec, dim int) (Scaler, error) {
+	if len(spec.A) != len(spec.B) || (dim > 0 && len(spec.A) != dim) {
+		return nil, fmt.Errorf("%w: scaler parameters don't match dimension %d", ErrModelCorrupt, dim)
+	}
+	switch spec.Kind {
+	case "standard":
+		return &StandardScaler{Mean: spec.A, Std: spec.B}, nil
+	case "minmax":
+		return &MinMaxScaler{Min: spec.A, Max: spec.B}, nil
+	case "robust":
+		return &RobustScaler{Median: spec.A, IQR: spec.B}, nil
+	}
+	return nil, ErrNotSerializable
+}
+
+func encodeIndex(index Index) (*indexSpec, error) {
+	switch ix := index.(type) {
+	case *KDTree:
+		if ix.removed > 0 {
+			// saving doesn't change the live index
+			compacted := *ix
+			compacted.compact()
+			ix = &compacted
+		}
+		spec := &indexSpec{Kind: "kdtree", Root: ix.root, Nodes: make([]int, 0, 4*len(ix.nodes))}
+		for _, nd := range ix.nodes {
+			spec.Nodes = append(spec.Nodes, nd.point, nd.axis, nd.left, nd.right)
+		}
+		return spec, nil
+	case *LSH:
+		if ix.removed > 0 {
+			compacted := *ix
+			compacted.compact()
+			ix = &compacted
+		}
+		return &indexSpec{
+			Kind:    "lsh",
+			Family:  int(ix.family),
+			Tables:  ix.tables,
+			Bits:    ix.bits,
+			Width:   ix.width,
+			Seed:    ix.seed,
+			Dim:     ix.dim,
+			Offsets: ix.offsets,
+			Buckets: ix.buckets,
+		}, nil
+	}
+	return nil, ErrNotSerializable
+}
+
+// check that parameters of distance match dimension of data points
+func checkDistDim(dist Distance, dim int) error {
+	switch d := dist.(type) {
+	case *mahalanobis:
+		if d.inv.Rows() != dim {
+			return fmt.Errorf("%w: mahalanobis matrix of %d rows for points of %d dimensions", ErrModelCorrupt, d.inv.Rows(), dim)
+		}
+	case *weighted:
+		if len(d.weights) != dim {
+			return fmt.Errorf("%w: %d weights for points of %d dimensions", ErrModelCorrupt, len(d.weights), dim)
+		}
+		return checkDistDim(d.base, dim)
+	}
+	return nil
+}
+
+// dimension of data points, zero without data
+func dim(data []DataPoint) int {
+	if len(data) == 0 {
+		return 0
+	}
+	return dataVector(data[0]).Dim()
+}
+
+// check parameters of lsh spec
+func checkLSHParams(spec *indexSpec) error {
+	corrupt := fmt.Errorf("%w: lsh parameters are not valid", ErrModelCorrupt)
+	if spec.Tables < 1 || spec.Tables > 64 || spec.Bits < 1 || spec.Bits > 64 {
+		return corrupt
+	}
+	if lshFamily(spec.Family) != hyperplaneFamily && lshFamily(spec.Family) != pStableFamily {
+		return corrupt
+	}
+	if lshFamily(spec.Family) == pStableFamily && (!(spec.Width > 0) || math.IsInf(spec.Width, 1)) {
+		return corrupt
+	}
+	return nil
+}
+
+func checkLSH(spec *indexSpec, n, dim int) error {
+	if err := checkLSHParams(spec); err != nil {
+		return err
+	}
+	corrupt := fmt.Errorf("%w: lsh tables are not valid", ErrModelCorrupt)
+	if len(spec.Buckets) != spec.Tables {
+		return corrupt
+	}
+	if n > 0 && (spec.Dim != dim || len(spec.Offsets) != spec.Tables) {
+		return corrupt
+	}
+	for t := range spec.Offsets {
+		if len(spec.Offsets[t]) != spec.Bits {
+			return corrupt
+		}
+	}
+	for _, bucket := range spec.Buckets {
+		for _, ids := range bucket {
+			for _, i := range ids {
+				if i < 0 || i >= n {
+					return corrupt
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// check that nodes reached from root form a tree with a node for every one of n data points, so queries
+// don't loop over cycles
+func checkKDTree(nodes []kdNode, root, n int) error {
+	reached := make([]bool, len(nodes))
+	points := make([]bool, n)
+	count := 0
+	stack := []int{root}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if i < 0 {
+			continue
+		}
+		if reached[i] || points[nodes[i].point] {
+			return fmt.Errorf("%w: kd-tree node %d is reached twice", ErrModelCorrupt, i)
+		}
+		reached[i], points[nodes[i].point] = true, true
+		count++
+		stack = append(stack, nodes[i].left, nodes[i].right)
+	}
+	if count != len(nodes) || count != n {
+		return fmt.Errorf("%w: kd-tree has %d nodes reached from root for %d data points", ErrModelCorrupt, count, n)
+	}
+	return nil
+}
+
+// restore index without building it again
+func decodeIndex(spec *indexSpec, dist Distance, data []DataPoint) (Index, error) {
+	switch spec.Kind {
+	case "kdtree":
+		bound, ok := kdBound(dist)
+		if !ok {
+			return nil, ErrDistanceNotSupported
+		}
+		tree := &KDTree{dist: dist, bound: bound, data: append([]DataPoint{}, data...), deleted: make([]bool, len(data)), root: spec.Root}
+		if len(spec.Nodes)%4 != 0 || spec.Root < -1 || spec.Root >= len(spec.Nodes)/4 {
+			return nil, fmt.Errorf("%w: kd-tree nodes are not valid", ErrModelCorrupt)
+		}
+		tree.nodes = make([]kdNode, len(spec.Nodes)/4)
+		for i := range tree.nodes {
+			n := spec.Nodes[4*i : 4*i+4]
+			if n[0] < 0 || n[0] >= len(data) || n[1] < 0 || n[1] >= dim(data) || n[2] < -1 || n[2] >= len(tree.nodes) ||
+				n[3] < -1 || n[3] >= len(tree.nodes) {
+				return nil, fmt.Errorf("%w: kd-tree node %d is not valid", ErrModelCorrupt, i)
+			}
+			tree.nodes[i] = kdNode{point: n[0], axis: n[1], left: n[2], right: n[3]}
+		}
+		if err := checkKDTree(tree.nodes, tree.root, len(data)); err != nil {
+			return nil, err
+		}
+		return tree, nil
+	case "lsh":
+		if len(spec.Planes) > 0 {
+			// projections of older models were random vectors, hash data again with projections of seed
+			if err := checkLSHParams(spec); err != nil {
+				return nil, err
+			}
+			ls := &LSH{family: lshFamily(spec.Family), tables: spec.Tables, bits: spec.Bits, width: spec.Width, seed: spec.Seed}
+			ls.Build(dist, data)
+			return ls, nil
+		}
+		if err := checkLSH(spec, len(data), dim(data)); err != nil {
+			return nil, err
+		}
+		return &LSH{
+			family:  lshFamily(spec.Family),
+			tables:  spec.Tables,
+			bits:    spec.Bits,
+			width:   spec.Width,
+			seed:    spec.Seed,
+			dist:    dist,
+			data:    append([]DataPoint{}, data...),
+			deleted: make([]bool, len(data)),
+			dim:     spec.Dim,
+			offsets: spec.Offsets,
+			buckets: spec.Buckets,
+		}, nil
+	}
+	return nil, ErrNotSerializable
+}
+
+// data point of spec, sparse points keep only their nonzero dimensions
+func decodeData(d dataSpec) (DataPoint, error) {
+	if d.Indices == nil && d.Dim == 0 {
+		return NewDataPoint(d.Label, d.Point), nil
+	}
+	if d.Dim < 1 || len(d.Indices) != len(d.Point) {
+		return nil, fmt.Errorf("%w: sparse point is not valid", ErrModelCorrupt)
+	}
+	for _, i := range d.Indices {
+		if i < 0 || i >= d.Dim {
+			return nil, fmt.Errorf("%w: sparse index %d is out of dimension %d", ErrModelCorrupt, i, d.Dim)
+		}
+	}
+	return NewSparseDataPoint(d.Label, NewSparsePoint(d.Dim, d.Indices, d.Point)), nil
+}
+
+// Save model with data points, scaler parameters and index structure
+//
+// Labels of data points must be types registered with gob.Register, basic types are registered by default
+func (knn *KNN) Save(w io.Writer) error {
+	model := modelSpec{Version: modelVersion, K: knn.k, Parallel: knn.fan.Load().lv}
+	var err error
+	if model.Dist, err = encodeDist(knn.dist); err != nil {
+		return err
+	}
+	if model.Selector, err = encodeSelector(knn.selector); err != nil {
+		return err
+	}
+	if knn.scaler != nil {
+		if model.Scaler, err = encodeScaler(knn.scaler); err != nil {
+			return err
+		}
+	}
+	if knn.index != nil {
+		if model.Index, err = encodeIndex(knn.index); err != nil {
+			return err
+		}
+	}
+	model.Data = make([]dataSpec, len(knn.data))
+	for i, dp := range knn.data {
+		original := unscaled(dp)
+		if sp, ok := dataVector(original).(*SparsePoint); ok {
+			model.Data[i] = dataSpec{Point: sp.values, Label: original.Label(), Dim: sp.dim, Indices: sp.indices}
+			continue
+		}
+		model.Data[i] = dataSpec{Point: original.Point(), Label: original.Label()}
+	}
+	return gob.NewEncoder(w).Encode(&model)
+}
+
+// Load a model saved with KNN.Save
+func Load(r io.Reader) (*KNN, error) {
+	var model modelSpec
+	if err := gob.NewDecoder(r).Decode(&model); err != nil {
+		return nil, err
+	}
+	if model.Version != modelVersion {
+		return nil, fmt.Errorf("knn model version %d is not supported", model.Version)
+	}
+	if model.K < 1 || model.Parallel < 1 {
+		return nil, fmt.Errorf("%w: k %d or parallelism %d is lesser than 1", ErrModelCorrupt, model.K, model.Parallel)
+	}
+	dist, err := decodeDist(model.Dist)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := decodeSelector(model.Selector)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]DataPoint, len(model.Data))
+	for i, d := range model.Data {
+		if data[i], err = decodeData(d); err != nil {
+			return nil, err
+		}
+		if dataVector(data[i]).Dim() != dataVector(data[0]).Dim() {
+			return nil, fmt.Errorf("%w: %v", ErrModelCorrupt, ErrPointDimensionMismatch)
+		}
+	}
+	if len(data) > 0 {
+		if err := checkDistDim(dist, dim(data)); err != nil {
+			return nil, err
+		}
+	}
+	knn := NewKNN(model.K, dist, selector, data).SetParallelLv(model.Parallel)
+	if model.Scaler != nil {
+		if knn.scaler, err = decodeScaler(model.Scaler, dim(data)); err != nil {
+			return nil, err
+		}
+		for i, dp := range data {
+			knn.data[i] = &scaledDataPoint{DataPoint: dp, point: knn.scaler.Transform(dp.Point())}
+		}
+	}
+	if model.Index != nil {
+		if knn.index, err = decodeIndex(model.Index, dist, knn.data); err != nil {
+			return nil, err
+		}
+	}
+	return knn, nil
+}
@@ -0,0 +1,104 @@
+package knn
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	data := randomDataPoints(rnd, 200, 3)
+	for _, d := range data {
+		d.(*dataPoint).label = d.Point()[0] > 0.5
+	}
+	dists := []Distance{NewWeightedDist(NewEuclideanDist(), []float64{1, 2, 3}), NewCosineDist(false)}
+	indexes := []Index{NewKDTree(), NewCosineLSH(4, 8, 1)}
+	for i := range dists {
+		knn := NewKNN(5, dists[i], NewBinarySelector(), data).SetScaler(NewMinMaxScaler()).SetIndex(indexes[i])
+		var buf bytes.Buffer
+		if err := knn.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for q := 0; q < 20; q++ {
+			query := randomDataPoints(rnd, 1, 3)[0].Point()
+			if l, expected := loaded.Predict(query), knn.Predict(query); l != expected {
+				t.Errorf("Load failed. Expected %v, but got %v", expected, l)
+			}
+		}
+	}
+}
+
+type customDist struct{}
+
+func (cu *customDist) Eval(p1, p2 Point) float64 {
+	return 0
+}
+
+func TestSaveNotSerializable(t *testing.T) {
+	knn := NewKNN(1, &customDist{}, NewBinarySelector(), nil)
+	var buf bytes.Buffer
+	if err := knn.Save(&buf); err != ErrNotSerializable {
+		t.Errorf("Save failed. Expected %v, but got %v", ErrNotSerializable, err)
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	data := []dataSpec{{Point: []float64{0, 1}, Label: true}, {Point: []float64{1, 0}, Label: false}}
+	models := map[string]modelSpec{
+		"minkowski without params": {Dist: &distSpec{Kind: "minkowski"}, Selector: &selectorSpec{Kind: "binary"}},
+		"weighted without base":    {Dist: &distSpec{Kind: "weighted", Params: []float64{1, 1}}, Selector: &selectorSpec{Kind: "binary"}},
+		"missing selector":         {Dist: &distSpec{Kind: "euclidean"}},
+		"smooth selector":          {Dist: &distSpec{Kind: "euclidean"}, Selector: &selectorSpec{Kind: "smooth_inverse_distance", Params: []float64{1}}},
+		"mahalanobis matrix":       {Dist: &distSpec{Kind: "mahalanobis", Params: []float64{1, 0, 0}}, Selector: &selectorSpec{Kind: "binary"}},
+		"kd-tree node": {Dist: &distSpec{Kind: "euclidean"}, Selector: &selectorSpec{Kind: "binary"},
+			Index: &indexSpec{Kind: "kdtree", Nodes: []int{5, 0, -1, -1}}},
+		"mahalanobis dimension": {Dist: &distSpec{Kind: "mahalanobis", Params: []float64{1}}, Selector: &selectorSpec{Kind: "binary"}},
+		"weights dimension": {Dist: &distSpec{Kind: "weighted", Params: []float64{1, 1, 1}, Base: &distSpec{Kind: "euclidean"}},
+			Selector: &selectorSpec{Kind: "binary"}},
+		"kd-tree cycle": {Dist: &distSpec{Kind: "euclidean"}, Selector: &selectorSpec{Kind: "binary"},
+			Index: &indexSpec{Kind: "kdtree", Nodes: []int{0, 0, 1, -1, 1, 1, 0, -1}}},
+		"kd-tree self child": {Dist: &distSpec{Kind: "euclidean"}, Selector: &selectorSpec{Kind: "binary"},
+			Index: &indexSpec{Kind: "kdtree", Nodes: []int{0, 0, 0, -1}}},
+		"kd-tree unreached node": {Dist: &distSpec{Kind: "euclidean"}, Selector: &selectorSpec{Kind: "binary"},
+			Index: &indexSpec{Kind: "kdtree", Nodes: []int{0, 0, -1, -1, 1, 0, -1, -1}}},
+		"lsh buckets": {Dist: &distSpec{Kind: "cosine", Params: []float64{0}}, Selector: &selectorSpec{Kind: "binary"},
+			Index: &indexSpec{Kind: "lsh", Tables: 2, Bits: 1}},
+		"scaler": {Dist: &distSpec{Kind: "euclidean"}, Selector: &selectorSpec{Kind: "binary"},
+			Scaler: &scalerSpec{Kind: "minmax", A: []float64{0}, B: []float64{1}}},
+	}
+	for name, model := range models {
+		model.Version, model.K, model.Parallel, model.Data = modelVersion, 1, 1, data
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(&model); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(&buf); !errors.Is(err, ErrModelCorrupt) {
+			t.Errorf("Load failed. Expected %v with %s, but got %v", ErrModelCorrupt, name, err)
+		}
+	}
+}
+
+func TestSaveKeepsIndex(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	data := randomDataPoints(rnd, 100, 2)
+	tree := NewKDTree()
+	knn := NewKNN(3, NewEuclideanDist(), NewMultiClassSelector(), data).SetIndex(tree)
+	knn.RemoveAt(0)
+	if tree.removed == 0 {
+		t.Fatalf("RemoveAt failed. Expected point removed from index without building it again")
+	}
+	nodes := len(tree.nodes)
+	if err := knn.Save(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if tree.removed != 1 || len(tree.nodes) != nodes {
+		t.Errorf("Save failed. Expected index not compacted by save")
+	}
+}
@@ -0,0 +1,70 @@
+package knn
+
+import "fmt"
+
+// small value added to distances so equal points don't get infinite weight
+const distEpsilon = 1e-9
+
+// probability of every label in a neighbor set, by vote share or by inverse distance weights
+func labelProba(kset []DataDist, weighted bool) map[any]float64 {
+	proba := make(map[any]float64)
+	total := 0.0
+	for _, d := range kset {
+		w := 1.0
+		if weighted {
+			w = 1 / (d.Dist() + distEpsilon)
+		}
+		proba[d.DataPoint().Label()] += w
+		total += w
+	}
+	for label := range proba {
+		proba[label] /= total
+	}
+	return proba
+}
+
+// Predict probability of every label for a point
+//
+// Probabilities are the vote share of the k nearest neighbors, or with weighted the share of inverse distance weights
+func (knn *KNN) PredictProba(point Point, weighted bool) map[any]float64 {
+	return labelProba(knn.KNeighbors(point, knn.k), weighted)
+}
+
+// Predict probability of every label for a point from data points inside radius, it is empty if there are no neighbors
+func (rn *RadiusNN) PredictProba(point Point, weighted bool) map[any]float64 {
+	return labelProba(rn.Neighbors(point), weighted)
+}
+
+// Label with greatest probability and its probability, it is the confidence of the prediction
+//
+// Ties are broken by the least label, numbers and strings are compared by value and other labels by their text
+func MaxProba(proba map[any]float64) (any, float64) {
+	var label any
+	max := -1.0
+	for l, p := range proba {
+		if p > max || (p == max && labelLess(l, label)) {
+			label, max = l, p
+		}
+	}
+	return label, max
+}
+
+// stable order of labels of any type, labels of different types are ordered by type name
+func labelLess(a, b any) bool {
+	if ta, tb := fmt.Sprintf("%T", a), fmt.Sprintf("%T", b); ta != tb {
+		return ta < tb
+	}
+	switch x := a.(type) {
+	case int:
+		return x < b.(int)
+	case int64:
+		return x < b.(int64)
+	case float64:
+		return x < b.(float64)
+	case string:
+		return x < b.(string)
+	case bool:
+		return !x && b.(bool)
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
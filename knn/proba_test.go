@@ -0,0 +1,39 @@
+package knn
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPredictProba(t *testing.T) {
+	data := []DataPoint{
+		NewDataPoint("a", WithPoint(0)),
+		NewDataPoint("a", WithPoint(1)),
+		NewDataPoint("b", WithPoint(3)),
+		NewDataPoint("b", WithPoint(10)),
+	}
+	knn := NewKNN(3, NewEuclideanDist(), NewMultiClassSelector(), data)
+	proba := knn.PredictProba(WithPoint(0), false)
+	if math.Abs(proba["a"]-2.0/3) > 1e-12 || math.Abs(proba["b"]-1.0/3) > 1e-12 {
+		t.Errorf("PredictProba failed. Expected a: 2/3 and b: 1/3, but got %v", proba)
+	}
+	weighted := knn.PredictProba(WithPoint(2.9), true)
+	if label, p := MaxProba(weighted); label != "b" || p <= 0.5 {
+		t.Errorf("PredictProba weighted failed. Expected b with probability > 0.5, but got %v %v", label, p)
+	}
+	rn := NewRadiusNN(0.5, NewEuclideanDist(), NewMultiClassSelector(), data, nil)
+	if proba := rn.PredictProba(WithPoint(5), false); len(proba) != 0 {
+		t.Errorf("RadiusNN PredictProba failed. Expected empty, but got %v", proba)
+	}
+}
+
+func TestMaxProbaTies(t *testing.T) {
+	for run := 0; run < 20; run++ {
+		if label, p := MaxProba(map[any]float64{"c": 0.4, "b": 0.4, "a": 0.2}); label != "b" || p != 0.4 {
+			t.Fatalf("MaxProba failed. Expected b 0.4, but got %v %v", label, p)
+		}
+		if label, _ := MaxProba(map[any]float64{3: 0.5, 1: 0.5}); label != 1 {
+			t.Fatalf("MaxProba failed. Expected 1, but got %v", label)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package knn
+
+import "context"
+
+// Neighbors model that selects labels from every data point inside a radius instead of a fixed k,
+// it works as classifier or regressor depending on the selector
+type RadiusNN struct {
+	knn     *KNN
+	radius  float64
+	outlier any
+}
+
+// Create a radius neighbors model, outlier is the label of points without neighbors inside radius
+func NewRadiusNN(radius float64, dist Distance, selector Selector, dataPoints []DataPoint, outlier any) *RadiusNN {
+	return &RadiusNN{
+		knn:     NewKNN(1, dist, selector, dataPoints),
+		radius:  radius,
+		outlier: outlier,
+	}
+}
+
+// Get radius
+func (rn *RadiusNN) Radius() float64 {
+	return rn.radius
+}
+
+// Set scaler of data points and queries, like KNN.SetScaler
+func (rn *RadiusNN) SetScaler(scaler Scaler) *RadiusNN {
+	rn.knn.SetScaler(scaler)
+	return rn
+}
+
+// Set index of data points, like KNN.SetIndex
+func (rn *RadiusNN) SetIndex(index Index) *RadiusNN {
+	rn.knn.SetIndex(index)
+	return rn
+}
+
+// Set number of workers of PredictBatch
+func (rn *RadiusNN) SetParallelLv(lv int) *RadiusNN {
+	rn.knn.SetParallelLv(lv)
+	return rn
+}
+
+// Add a data point
+func (rn *RadiusNN) Append(dp DataPoint) *RadiusNN {
+	rn.knn.Append(dp)
+	return rn
+}
+
+// Remove data point at index i, it returns false if i is out of range
+func (rn *RadiusNN) RemoveAt(i int) bool {
+	return rn.knn.RemoveAt(i)
+}
+
+// Get data points
+func (rn *RadiusNN) GetDataPoints() []DataPoint {
+	return rn.knn.GetDataPoints()
+}
+
+// Data points inside radius of a point sorted by distance
+func (rn *RadiusNN) Neighbors(point Point) []DataDist {
+	return rn.knn.RadiusNeighbors(point, rn.radius)
+}
+
+// Predict label of a point selecting it from data points inside radius
+func (rn *RadiusNN) Predict(point Point) any {
+	kset := rn.Neighbors(point)
+	if len(kset) == 0 {
+		return rn.outlier
+	}
+	return rn.knn.selector.Label(kset)
+}
+
+// Predict label of a point unless context is done, then it returns the context error
+func (rn *RadiusNN) PredictCtx(ctx context.Context, point Point) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return rn.Predict(point), nil
+}
+
+// Predict labels of many points using the workers of the model
+func (rn *RadiusNN) PredictBatch(points []Point) []any {
+	labels := make([]any, len(points))
+	rn.knn.fan.Load().forEach(len(points), func(i int) {
+		labels[i] = rn.Predict(points[i])
+	})
+	return labels
+}
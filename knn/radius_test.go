@@ -0,0 +1,60 @@
+package knn
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRadiusNeighbors(t *testing.T) {
+	data := []DataPoint{
+		NewDataPoint(1.0, WithPoint(0, 0)),
+		NewDataPoint(3.0, WithPoint(1, 0)),
+		NewDataPoint(10.0, WithPoint(5, 5)),
+	}
+	knn := NewKNN(1, NewEuclideanDist(), NewRegressionSelector(), data)
+	if found := knn.RadiusNeighbors(WithPoint(0, 0), 1); len(found) != 2 || found[1].Dist() != 1 {
+		t.Errorf("RadiusNeighbors failed. Unexpected neighbors %v", found)
+	}
+	knn.SetIndex(NewKDTree())
+	if found := knn.RadiusNeighbors(WithPoint(0, 0), 1); len(found) != 2 {
+		t.Errorf("RadiusNeighbors with KDTree failed. Unexpected neighbors %v", found)
+	}
+}
+
+func TestRadiusNNPredict(t *testing.T) {
+	data := []DataPoint{
+		NewDataPoint(1.0, WithPoint(0, 0)),
+		NewDataPoint(3.0, WithPoint(1, 0)),
+		NewDataPoint(10.0, WithPoint(5, 5)),
+	}
+	rn := NewRadiusNN(1.5, NewEuclideanDist(), NewRegressionSelector(), data, -1.0)
+	labels := rn.PredictBatch([]Point{WithPoint(0.5, 0), WithPoint(5, 4), WithPoint(20, 20)})
+	if labels[0] != 2.0 || labels[1] != 10.0 || labels[2] != -1.0 {
+		t.Errorf("RadiusNN Predict failed. Expected [2 10 -1], but got %v", labels)
+	}
+}
+
+func TestRadiusNNNeighbors(t *testing.T) {
+	data := []DataPoint{
+		NewDataPoint(1.0, WithPoint(0, 0)),
+		NewDataPoint(3.0, WithPoint(1, 0)),
+		NewDataPoint(10.0, WithPoint(5, 5)),
+	}
+	rn := NewRadiusNN(1.5, NewEuclideanDist(), NewRegressionSelector(), data, -1.0).SetIndex(NewKDTree())
+	if found := rn.Neighbors(WithPoint(0.5, 0)); len(found) != 2 {
+		t.Errorf("Neighbors failed. Expected 2 neighbors inside radius, but got %v", found)
+	}
+	// context predictions use the radius too
+	if label, err := rn.PredictCtx(context.Background(), WithPoint(20, 20)); err != nil || label != -1.0 {
+		t.Errorf("PredictCtx failed. Expected outlier label -1, but got %v, %v", label, err)
+	}
+	rn.Append(NewDataPoint(30.0, WithPoint(20, 20)))
+	if label := rn.Predict(WithPoint(20, 20)); label != 30.0 {
+		t.Errorf("Append failed. Expected 30, but got %v", label)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := rn.PredictCtx(ctx, WithPoint(0, 0)); err != context.Canceled {
+		t.Errorf("PredictCtx failed. Expected %v, but got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,62 @@
+package knn
+
+import "math/rand"
+
+// Condensed nearest neighbor (Hart) prototype selection
+//
+// It keeps a subset of data that classifies every data point right with 1-NN, points are visited in a
+// random order given by seed. Labels are compared with ==
+func CondensedNN(data []DataPoint, dist Distance, seed int64) []DataPoint {
+	if len(data) == 0 {
+		return []DataPoint{}
+	}
+	order := rand.New(rand.NewSource(seed)).Perm(len(data))
+	inStore := make([]bool, len(data))
+	store := []DataPoint{data[order[0]]}
+	inStore[order[0]] = true
+	for added := true; added; {
+		added = false
+		for _, i := range order {
+			if inStore[i] {
+				continue
+			}
+			// classify with nearest prototype
+			nearest, best := store[0], dist.Eval(store[0].Point(), data[i].Point())
+			for _, dp := range store[1:] {
+				if d := dist.Eval(dp.Point(), data[i].Point()); d < best {
+					nearest, best = dp, d
+				}
+			}
+			if nearest.Label() != data[i].Label() {
+				store = append(store, data[i])
+				inStore[i] = true
+				added = true
+			}
+		}
+	}
+	return store
+}
+
+// Edited nearest neighbor (Wilson) noise removal
+//
+// It removes every data point whose label is not the majority label of its k nearest other data points
+func EditedNN(data []DataPoint, k int, dist Distance) []DataPoint {
+	if k <= 0 {
+		panic(ErrKIsNotValid)
+	}
+	selector := NewMultiClassSelector()
+	kept := make([]DataPoint, 0, len(data))
+	for i, dp := range data {
+		set := newNearest(k)
+		for j, other := range data {
+			if i != j {
+				set.push(dist.Eval(other.Point(), dp.Point()), other)
+			}
+		}
+		kset := set.sorted()
+		if len(kset) == 0 || selector.Label(kset) == dp.Label() {
+			kept = append(kept, dp)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,45 @@
+package knn
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCondensedNN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(9))
+	data := randomDataPoints(rnd, 300, 2)
+	for _, d := range data {
+		d.(*dataPoint).label = d.Point()[0] > 0.5
+	}
+	store := CondensedNN(data, NewEuclideanDist(), 1)
+	if len(store) >= len(data)/2 {
+		t.Errorf("CondensedNN failed. Expected a small subset, but got %d of %d", len(store), len(data))
+	}
+	knn := NewKNN(1, NewEuclideanDist(), NewBinarySelector(), store)
+	for _, d := range data {
+		if l := knn.Predict(d.Point()); l != d.Label() {
+			t.Fatalf("CondensedNN failed. Data point %v is not classified right", d.Point())
+		}
+	}
+}
+
+func TestEditedNN(t *testing.T) {
+	data := []DataPoint{
+		NewDataPoint("a", WithPoint(0)),
+		NewDataPoint("a", WithPoint(1)),
+		NewDataPoint("b", WithPoint(1.5)),
+		NewDataPoint("a", WithPoint(2)),
+		NewDataPoint("b", WithPoint(10)),
+		NewDataPoint("b", WithPoint(11)),
+		NewDataPoint("b", WithPoint(12)),
+	}
+	kept := EditedNN(data, 3, NewEuclideanDist())
+	if len(kept) != 6 {
+		t.Fatalf("EditedNN failed. Expected 6 data points, but got %d", len(kept))
+	}
+	for _, dp := range kept {
+		if dp == data[2] {
+			t.Errorf("EditedNN failed. Noisy data point was kept")
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package knn
+
+import (
+	"fmt"
+	"math"
+)
+
+var ErrSelectorNotSupported = fmt.Errorf("selector has no uncertainty estimate")
+
+// Regression selector that also estimates uncertainty of its labels
+type UncertaintySelector interface {
+	Selector
+	LabelStd(kset []DataDist) (mean, std float64) //label and standard deviation of neighbor targets
+}
+
+// weighted mean and standard deviation of neighbor targets
+func weightedMeanStd(kset []DataDist, weight func(d DataDist) float64) (float64, float64) {
+	var sum, total float64
+	for _, d := range kset {
+		w := weight(d)
+		sum += w * d.DataPoint().Label().(float64)
+		total += w
+	}
+	mean := sum / total
+	variance := 0.0
+	for _, d := range kset {
+		dif := d.DataPoint().Label().(float64) - mean
+		variance += weight(d) * dif * dif
+	}
+	return mean, math.Sqrt(variance / total)
+}
+
+func uniformWeight(d DataDist) float64 {
+	return 1
+}
+
+func (re *regressionSelector) LabelStd(kset []DataDist) (float64, float64) {
+	return weightedMeanStd(kset, uniformWeight)
+}
+
+type weightedRegressionSelector struct {
+	power float64
+}
+
+// Regression selector averaging neighbor targets with weights 1 / dist^power
+func NewWeightedRegressionSelector(power float64) UncertaintySelector {
+	return &weightedRegressionSelector{power: power}
+}
+
+func (we *weightedRegressionSelector) weight(d DataDist) float64 {
+	return 1 / (math.Pow(d.Dist(), we.power) + distEpsilon)
+}
+
+func (we *weightedRegressionSelector) Label(kset []DataDist) interface{} {
+	mean, _ := weightedMeanStd(kset, we.weight)
+	return mean
+}
+
+func (we *weightedRegressionSelector) LabelStd(kset []DataDist) (float64, float64) {
+	return weightedMeanStd(kset, we.weight)
+}
+
+// Predict target of a point with the standard deviation of neighbor targets as uncertainty
+//
+// panics if selector is not an UncertaintySelector
+func (knn *KNN) PredictStd(point Point) (float64, float64) {
+	selector, ok := knn.selector.(UncertaintySelector)
+	if !ok {
+		panic(ErrSelectorNotSupported)
+	}
+	return selector.LabelStd(knn.KNeighbors(point, knn.k))
+}
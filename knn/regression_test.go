@@ -0,0 +1,33 @@
+package knn
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWeightedRegressionSelector(t *testing.T) {
+	kset := []DataDist{
+		newDataDist(1, NewDataPoint(2.0, WithPoint(1))),
+		newDataDist(3, NewDataPoint(6.0, WithPoint(3))),
+	}
+	we := NewWeightedRegressionSelector(1)
+	mean, std := we.LabelStd(kset)
+	if math.Abs(mean-3) > 1e-6 || math.Abs(std-math.Sqrt(3)) > 1e-6 {
+		t.Errorf("WeightedRegressionSelector failed. Expected 3 and %v, but got %v and %v", math.Sqrt(3), mean, std)
+	}
+	if l := we.Label(kset).(float64); math.Abs(l-mean) > 1e-12 {
+		t.Errorf("WeightedRegressionSelector Label failed. Expected %v, but got %v", mean, l)
+	}
+}
+
+func TestKNNPredictStd(t *testing.T) {
+	data := []DataPoint{
+		NewDataPoint(1.0, WithPoint(0)),
+		NewDataPoint(3.0, WithPoint(1)),
+		NewDataPoint(100.0, WithPoint(50)),
+	}
+	knn := NewKNN(2, NewEuclideanDist(), NewRegressionSelector(), data)
+	if mean, std := knn.PredictStd(WithPoint(0.5)); mean != 2 || std != 1 {
+		t.Errorf("PredictStd failed. Expected 2 and 1, but got %v and %v", mean, std)
+	}
+}
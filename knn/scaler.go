@@ -0,0 +1,166 @@
+package knn
+
+import (
+	"math"
+	"sort"
+)
+
+// Feature scaler, it learns parameters from training points and transforms points with them
+type Scaler interface {
+	Fit(points []Point)          //learn scaling parameters
+	Transform(point Point) Point //get a scaled copy of point
+}
+
+// apply (x - center) / scale to every dimension, scale zero is taken as one
+func affine(point Point, center, scale []float64) Point {
+	if point.Dim() != len(center) {
+		panic(ErrPointDimensionMismatch)
+	}
+	out := make(Point, len(point))
+	for i := range point {
+		s := scale[i]
+		if s == 0 {
+			s = 1
+		}
+		out[i] = (point[i] - center[i]) / s
+	}
+	return out
+}
+
+func checkPoints(points []Point) int {
+	if len(points) == 0 {
+		panic(ErrNotEnoughData)
+	}
+	dim := points[0].Dim()
+	for _, p := range points {
+		if p.Dim() != dim {
+			panic(ErrPointDimensionMismatch)
+		}
+	}
+	return dim
+}
+
+// Scaler to zero mean and unit variance
+type StandardScaler struct {
+	Mean []float64
+	Std  []float64
+}
+
+func NewStandardScaler() *StandardScaler {
+	return &StandardScaler{}
+}
+
+func (st *StandardScaler) Fit(points []Point) {
+	dim := checkPoints(points)
+	st.Mean = make([]float64, dim)
+	st.Std = make([]float64, dim)
+	n := float64(len(points))
+	for _, p := range points {
+		for i := range p {
+			st.Mean[i] += p[i]
+		}
+	}
+	for i := range st.Mean {
+		st.Mean[i] /= n
+	}
+	for _, p := range points {
+		for i := range p {
+			d := p[i] - st.Mean[i]
+			st.Std[i] += d * d
+		}
+	}
+	for i := range st.Std {
+		st.Std[i] = math.Sqrt(st.Std[i] / n)
+	}
+}
+
+func (st *StandardScaler) Transform(point Point) Point {
+	return affine(point, st.Mean, st.Std)
+}
+
+// Scaler to range [0, 1] by minimum and maximum of every dimension
+type MinMaxScaler struct {
+	Min []float64
+	Max []float64
+}
+
+func NewMinMaxScaler() *MinMaxScaler {
+	return &MinMaxScaler{}
+}
+
+func (mm *MinMaxScaler) Fit(points []Point) {
+	dim := checkPoints(points)
+	mm.Min = make([]float64, dim)
+	mm.Max = make([]float64, dim)
+	copy(mm.Min, points[0])
+	copy(mm.Max, points[0])
+	for _, p := range points[1:] {
+		for i := range p {
+			mm.Min[i] = math.Min(mm.Min[i], p[i])
+			mm.Max[i] = math.Max(mm.Max[i], p[i])
+		}
+	}
+}
+
+func (mm *MinMaxScaler) Transform(point Point) Point {
+	scale := make([]float64, len(mm.Min))
+	for i := range scale {
+		scale[i] = mm.Max[i] - mm.Min[i]
+	}
+	return affine(point, mm.Min, scale)
+}
+
+// Scaler by median and interquartile range of every dimension, robust to outliers
+type RobustScaler struct {
+	Median []float64
+	IQR    []float64
+}
+
+func NewRobustScaler() *RobustScaler {
+	return &RobustScaler{}
+}
+
+// quantile of sorted values with linear interpolation
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+func (ro *RobustScaler) Fit(points []Point) {
+	dim := checkPoints(points)
+	ro.Median = make([]float64, dim)
+	ro.IQR = make([]float64, dim)
+	values := make([]float64, len(points))
+	for i := 0; i < dim; i++ {
+		for j, p := range points {
+			values[j] = p[i]
+		}
+		sort.Float64s(values)
+		ro.Median[i] = quantile(values, 0.5)
+		ro.IQR[i] = quantile(values, 0.75) - quantile(values, 0.25)
+	}
+}
+
+func (ro *RobustScaler) Transform(point Point) Point {
+	return affine(point, ro.Median, ro.IQR)
+}
+
+// data point with a scaled point, it keeps the original data point
+type scaledDataPoint struct {
+	DataPoint
+	point Point
+}
+
+func (sc *scaledDataPoint) Point() Point {
+	return sc.point
+}
+
+// unwrap original data point
+func unscaled(dp DataPoint) DataPoint {
+	if sc, ok := dp.(*scaledDataPoint); ok {
+		return sc.DataPoint
+	}
+	return dp
+}
@@ -0,0 +1,55 @@
+package knn
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStandardScaler(t *testing.T) {
+	st := NewStandardScaler()
+	st.Fit([]Point{WithPoint(1, 10), WithPoint(3, 10)})
+	if p := st.Transform(WithPoint(3, 12)); p[0] != 1 || p[1] != 2 {
+		t.Errorf("StandardScaler failed. Expected [1 2], but got %v", p)
+	}
+}
+
+func TestMinMaxScaler(t *testing.T) {
+	mm := NewMinMaxScaler()
+	mm.Fit([]Point{WithPoint(0, -1), WithPoint(10, 1), WithPoint(5, 0)})
+	if p := mm.Transform(WithPoint(5, 1)); p[0] != 0.5 || p[1] != 1 {
+		t.Errorf("MinMaxScaler failed. Expected [0.5 1], but got %v", p)
+	}
+}
+
+func TestRobustScaler(t *testing.T) {
+	ro := NewRobustScaler()
+	ro.Fit([]Point{WithPoint(1), WithPoint(2), WithPoint(3), WithPoint(4), WithPoint(1000)})
+	if ro.Median[0] != 3 || ro.IQR[0] != 2 {
+		t.Errorf("RobustScaler failed. Expected median 3 and IQR 2, but got %v and %v", ro.Median[0], ro.IQR[0])
+	}
+}
+
+func TestKNNWithScaler(t *testing.T) {
+	// second feature has large values that dominate the distance without scaling
+	data := []DataPoint{
+		NewDataPoint("a", WithPoint(0, 1000)),
+		NewDataPoint("a", WithPoint(0.1, 1200)),
+		NewDataPoint("b", WithPoint(1, 1100)),
+		NewDataPoint("b", WithPoint(0.9, 1300)),
+	}
+	knn := NewKNN(1, NewEuclideanDist(), NewMultiClassSelector(), data)
+	if l := knn.Predict(WithPoint(0.9, 1020)); l != "a" {
+		t.Fatalf("Predict without scaler. Expected a, but got %v", l)
+	}
+	knn.SetScaler(NewStandardScaler()).SetIndex(NewKDTree())
+	if l := knn.Predict(WithPoint(0.9, 1020)); l != "b" {
+		t.Errorf("Predict with scaler failed. Expected b, but got %v", l)
+	}
+	knn.Append(NewDataPoint("c", WithPoint(0.5, 2000)))
+	if l := knn.Predict(WithPoint(0.5, 2000)); l != "c" {
+		t.Errorf("Predict after Append failed. Expected c, but got %v", l)
+	}
+	if p := knn.GetDataPoints()[4].Point(); math.Abs(p[1]-2000) > 0 {
+		t.Errorf("GetDataPoints failed. Expected original point, but got %v", p)
+	}
+}
@@ -0,0 +1,145 @@
+package knn
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+var (
+	ErrFoldsNotValid = fmt.Errorf("folds are not in range [2, len(data)]")
+	ErrNoLabels      = fmt.Errorf("there are no labels to score")
+)
+
+// Score of predicted labels against expected labels, greater is better
+type ScoreFunc func(expected, predicted []any) float64
+
+// Fraction of labels predicted right, it panics with ErrNoLabels if there are no labels
+func Accuracy(expected, predicted []any) float64 {
+	if len(expected) == 0 {
+		panic(ErrNoLabels)
+	}
+	right := 0
+	for i := range expected {
+		if expected[i] == predicted[i] {
+			right++
+		}
+	}
+	return float64(right) / float64(len(expected))
+}
+
+// Negative mean squared error of float64 labels, so greater is better
+func NegMeanSquaredError(expected, predicted []any) float64 {
+	if len(expected) == 0 {
+		panic(ErrNoLabels)
+	}
+	sum := 0.0
+	for i := range expected {
+		dif := expected[i].(float64) - predicted[i].(float64)
+		sum += dif * dif
+	}
+	return -sum / float64(len(expected))
+}
+
+// Configuration of a KNN model
+type Config struct {
+	K        int
+	Dist     Distance
+	Selector Selector
+}
+
+// Cross-validation result of a configuration
+type CVResult struct {
+	Config     Config
+	FoldScores []float64
+	Mean       float64
+	Std        float64
+}
+
+// Split indices of n data points in folds consecutive parts of almost the same size, indices are shuffled before
+// when shuffle is true
+func kFolds(n, folds int, shuffle bool, seed int64) [][]int {
+	if folds < 2 || folds > n {
+		panic(ErrFoldsNotValid)
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	if shuffle {
+		order = rand.New(rand.NewSource(seed)).Perm(n)
+	}
+	parts := make([][]int, folds)
+	start := 0
+	for i := range parts {
+		size := n / folds
+		if i < n%folds {
+			size++
+		}
+		parts[i] = order[start : start+size]
+		start += size
+	}
+	return parts
+}
+
+// Cross-validate a configuration with k folds of shuffled data
+func CrossValidate(data []DataPoint, config Config, folds int, score ScoreFunc, seed int64) CVResult {
+	return crossValidate(data, kFolds(len(data), folds, true, seed), config, score)
+}
+
+func crossValidate(data []DataPoint, folds [][]int, config Config, score ScoreFunc) CVResult {
+	result := CVResult{Config: config, FoldScores: make([]float64, len(folds))}
+	for f, test := range folds {
+		inTest := make(map[int]bool, len(test))
+		for _, i := range test {
+			inTest[i] = true
+		}
+		train := make([]DataPoint, 0, len(data)-len(test))
+		for i, d := range data {
+			if !inTest[i] {
+				train = append(train, d)
+			}
+		}
+		points := make([]Point, len(test))
+		expected := make([]any, len(test))
+		for j, i := range test {
+			points[j] = data[i].Point()
+			expected[j] = data[i].Label()
+		}
+		model := NewKNN(config.K, config.Dist, config.Selector, train)
+		result.FoldScores[f] = score(expected, model.PredictBatch(points))
+	}
+	for _, s := range result.FoldScores {
+		result.Mean += s
+	}
+	result.Mean /= float64(len(folds))
+	for _, s := range result.FoldScores {
+		result.Std += (s - result.Mean) * (s - result.Mean)
+	}
+	result.Std = math.Sqrt(result.Std / float64(len(folds)))
+	return result
+}
+
+// Cross-validate every combination of k values, distances and selectors with the same folds
+//
+// It returns the result with the best mean score and the results of every configuration
+func GridSearch(data []DataPoint, ks []int, dists []Distance, selectors []Selector, folds int, score ScoreFunc, seed int64) (CVResult, []CVResult) {
+	split := kFolds(len(data), folds, true, seed)
+	results := make([]CVResult, 0, len(ks)*len(dists)*len(selectors))
+	best := -1
+	for _, k := range ks {
+		for _, dist := range dists {
+			for _, selector := range selectors {
+				result := crossValidate(data, split, Config{K: k, Dist: dist, Selector: selector}, score)
+				results = append(results, result)
+				if best == -1 || result.Mean > results[best].Mean {
+					best = len(results) - 1
+				}
+			}
+		}
+	}
+	if best == -1 {
+		return CVResult{}, results
+	}
+	return results[best], results
+}
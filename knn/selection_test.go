@@ -0,0 +1,74 @@
+package knn
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGridSearch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(6))
+	// label depends only on first feature, second feature is large noise
+	data := make([]DataPoint, 120)
+	for i := range data {
+		x := rnd.Float64()
+		data[i] = NewDataPoint(x > 0.5, WithPoint(x, rnd.Float64()*0.05))
+	}
+	best, results := GridSearch(data, []int{1, 5, 9}, []Distance{NewEuclideanDist(), NewManhattanDist()},
+		[]Selector{NewBinarySelector()}, 4, Accuracy, 1)
+	if len(results) != 6 {
+		t.Fatalf("GridSearch failed. Expected 6 results, but got %d", len(results))
+	}
+	for _, r := range results {
+		if len(r.FoldScores) != 4 || r.Mean > best.Mean {
+			t.Errorf("GridSearch failed. Unexpected result %v for best %v", r, best)
+		}
+	}
+	if best.Mean < 0.9 {
+		t.Errorf("GridSearch failed. Expected accuracy greater than 0.9, but got %v", best.Mean)
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	data := make([]DataPoint, 20)
+	for i := range data {
+		data[i] = NewDataPoint(float64(i), WithPoint(float64(i)))
+	}
+	result := CrossValidate(data, Config{K: 2, Dist: NewEuclideanDist(), Selector: NewRegressionSelector()}, 5, NegMeanSquaredError, 1)
+	if result.Mean > 0 || result.Mean < -10 {
+		t.Errorf("CrossValidate failed. Unexpected mean score %v", result.Mean)
+	}
+}
+
+func TestKFolds(t *testing.T) {
+	for _, shuffle := range []bool{false, true} {
+		parts := kFolds(11, 3, shuffle, 2)
+		seen := make(map[int]bool)
+		for f, part := range parts {
+			if expected := []int{4, 4, 3}[f]; len(part) != expected {
+				t.Errorf("kFolds failed. Expected %d indices in fold %d, but got %v", expected, f, part)
+			}
+			for _, i := range part {
+				seen[i] = true
+			}
+		}
+		if len(seen) != 11 {
+			t.Errorf("kFolds failed. Expected every index in one fold, but got %v", parts)
+		}
+	}
+	if parts := kFolds(4, 2, false, 0); parts[0][0] != 0 || parts[1][0] != 2 {
+		t.Errorf("kFolds failed. Expected consecutive folds, but got %v", parts)
+	}
+}
+
+func TestScoresEmpty(t *testing.T) {
+	for name, score := range map[string]ScoreFunc{"Accuracy": Accuracy, "NegMeanSquaredError": NegMeanSquaredError} {
+		func() {
+			defer func() {
+				if r := recover(); r != ErrNoLabels {
+					t.Errorf("%s failed. Expected %v, but got %v", name, ErrNoLabels, r)
+				}
+			}()
+			score(nil, nil)
+		}()
+	}
+}
@@ -0,0 +1,235 @@
+package knn
+
+import (
+	"math"
+	"sort"
+)
+
+// Vector is the common interface of dense and sparse points
+type Vector interface {
+	Dim() int                            //number of dimensions
+	At(i int) float64                    //value of dimension i
+	Range(fn func(i int, value float64)) //visit nonzero dimensions in increasing order
+}
+
+func (p Point) At(i int) float64 {
+	return p[i]
+}
+
+func (p Point) Range(fn func(i int, value float64)) {
+	for i, v := range p {
+		if v != 0 {
+			fn(i, v)
+		}
+	}
+}
+
+// Sparse point stored as pairs of index and value of nonzero dimensions
+type SparsePoint struct {
+	dim     int
+	indices []int
+	values  []float64
+}
+
+// Create a sparse point with the given dimension and nonzero values, pairs are sorted by index
+func NewSparsePoint(dim int, indices []int, values []float64) *SparsePoint {
+	if len(indices) != len(values) {
+		panic(ErrPointDimensionMismatch)
+	}
+	sp := &SparsePoint{dim: dim, indices: make([]int, 0, len(indices)), values: make([]float64, 0, len(values))}
+	order := make([]int, len(indices))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return indices[order[a]] < indices[order[b]]
+	})
+	for _, o := range order {
+		if indices[o] < 0 || indices[o] >= dim {
+			panic(ErrPointDimensionMismatch)
+		}
+		if values[o] != 0 {
+			sp.indices = append(sp.indices, indices[o])
+			sp.values = append(sp.values, values[o])
+		}
+	}
+	return sp
+}
+
+func (sp *SparsePoint) Dim() int {
+	return sp.dim
+}
+
+func (sp *SparsePoint) At(i int) float64 {
+	k := sort.SearchInts(sp.indices, i)
+	if k < len(sp.indices) && sp.indices[k] == i {
+		return sp.values[k]
+	}
+	return 0
+}
+
+func (sp *SparsePoint) Range(fn func(i int, value float64)) {
+	for k, i := range sp.indices {
+		fn(i, sp.values[k])
+	}
+}
+
+// Number of nonzero dimensions
+func (sp *SparsePoint) NonZero() int {
+	return len(sp.indices)
+}
+
+// Dense copy of point
+func (sp *SparsePoint) Dense() Point {
+	p := NewPoint(sp.dim)
+	for k, i := range sp.indices {
+		p[i] = sp.values[k]
+	}
+	return p
+}
+
+// Distance between vectors, dense or sparse
+type VectorDistance interface {
+	Distance
+	EvalVector(v1, v2 Vector) float64
+}
+
+// visit dimensions nonzero in any vector with both values, in increasing order of dimension
+func mergeVectors(v1, v2 Vector, fn func(a, b float64)) {
+	s1, ok1 := v1.(*SparsePoint)
+	s2, ok2 := v2.(*SparsePoint)
+	if ok1 && ok2 {
+		i, j := 0, 0
+		for i < len(s1.indices) || j < len(s2.indices) {
+			switch {
+			case j == len(s2.indices) || (i < len(s1.indices) && s1.indices[i] < s2.indices[j]):
+				fn(s1.values[i], 0)
+				i++
+			case i == len(s1.indices) || s2.indices[j] < s1.indices[i]:
+				fn(0, s2.values[j])
+				j++
+			default:
+				fn(s1.values[i], s2.values[j])
+				i++
+				j++
+			}
+		}
+		return
+	}
+	// one vector is dense, visit every dimension
+	for i, ln := 0, v1.Dim(); i < ln; i++ {
+		fn(v1.At(i), v2.At(i))
+	}
+}
+
+type sparseEuclidean struct{}
+
+// Euclidean distance that only visits nonzero dimensions of sparse points
+func NewSparseEuclideanDist() VectorDistance {
+	return &sparseEuclidean{}
+}
+
+func (se *sparseEuclidean) Eval(p1, p2 Point) float64 {
+	return se.EvalVector(p1, p2)
+}
+
+func (se *sparseEuclidean) EvalVector(v1, v2 Vector) float64 {
+	if v1.Dim() != v2.Dim() {
+		panic(ErrPointDimensionMismatch)
+	}
+	sum := 0.0
+	mergeVectors(v1, v2, func(a, b float64) {
+		sum += (a - b) * (a - b)
+	})
+	return math.Sqrt(sum)
+}
+
+type sparseCosine struct{}
+
+// Cosine distance that only visits nonzero dimensions of sparse points
+func NewSparseCosineDist() VectorDistance {
+	return &sparseCosine{}
+}
+
+func (sc *sparseCosine) Eval(p1, p2 Point) float64 {
+	return sc.EvalVector(p1, p2)
+}
+
+func (sc *sparseCosine) EvalVector(v1, v2 Vector) float64 {
+	if v1.Dim() != v2.Dim() {
+		panic(ErrPointDimensionMismatch)
+	}
+	var dot, norm1, norm2 float64
+	mergeVectors(v1, v2, func(a, b float64) {
+		dot += a * b
+		norm1 += a * a
+		norm2 += b * b
+	})
+	if norm1 == 0 || norm2 == 0 {
+		return 1
+	}
+	return 1 - math.Max(-1, math.Min(1, dot/math.Sqrt(norm1*norm2)))
+}
+
+type sparseDataPoint struct {
+	vector *SparsePoint
+	label  any
+}
+
+// Create a data point with a sparse point
+//
+// Point() allocates a dense copy on every call, KNN scans with a VectorDistance use the sparse point instead
+func NewSparseDataPoint(label any, vector *SparsePoint) DataPoint {
+	return &sparseDataPoint{vector: vector, label: label}
+}
+
+func (sd *sparseDataPoint) Label() any {
+	return sd.label
+}
+
+func (sd *sparseDataPoint) Point() Point {
+	return sd.vector.Dense()
+}
+
+func (sd *sparseDataPoint) Vector() Vector {
+	return sd.vector
+}
+
+// vector of a data point, sparse if it has one
+func dataVector(dp DataPoint) Vector {
+	if sd, ok := dp.(interface{ Vector() Vector }); ok {
+		return sd.Vector()
+	}
+	return dp.Point()
+}
+
+// distance of data point to point, sparse data points are not densified by vector distances
+func evalData(dist Distance, d DataPoint, point Point) float64 {
+	if dist, ok := dist.(VectorDistance); ok {
+		return dist.EvalVector(dataVector(d), point)
+	}
+	return dist.Eval(d.Point(), point)
+}
+
+// Get the k nearest data points of a dense or sparse vector by scanning every data point
+//
+// panics if distance of KNN is not a VectorDistance, scaler and index are not used
+func (knn *KNN) KNeighborsVector(vector Vector, k int) []DataDist {
+	if k <= 0 {
+		panic(ErrKIsNotValid)
+	}
+	dist, ok := knn.dist.(VectorDistance)
+	if !ok {
+		panic(ErrDistanceNotSupported)
+	}
+	set := newNearest(k)
+	for _, dp := range knn.data {
+		set.push(dist.EvalVector(dataVector(unscaled(dp)), vector), unscaled(dp))
+	}
+	return set.sorted()
+}
+
+// Predict label of a dense or sparse vector, see KNeighborsVector
+func (knn *KNN) PredictVector(vector Vector) any {
+	return knn.selector.Label(knn.KNeighborsVector(vector, knn.k))
+}
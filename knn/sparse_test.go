@@ -0,0 +1,110 @@
+package knn
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestSparsePoint(t *testing.T) {
+	sp := NewSparsePoint(1000000, []int{999999, 3, 10}, []float64{1, 2, 0})
+	if sp.NonZero() != 2 || sp.At(3) != 2 || sp.At(999999) != 1 || sp.At(4) != 0 {
+		t.Errorf("SparsePoint failed. Unexpected values %v", sp)
+	}
+}
+
+func TestSparseDistances(t *testing.T) {
+	s1 := NewSparsePoint(5, []int{0, 3}, []float64{3, 1})
+	s2 := NewSparsePoint(5, []int{3, 4}, []float64{1, 4})
+	eu, co := NewSparseEuclideanDist(), NewSparseCosineDist()
+	if d := eu.EvalVector(s1, s2); d != 5 {
+		t.Errorf("SparseEuclideanEval failed. Expected 5, but got %v", d)
+	}
+	if d, expected := eu.EvalVector(s1, s2.Dense()), NewEuclideanDist().Eval(s1.Dense(), s2.Dense()); d != expected {
+		t.Errorf("SparseEuclideanEval dense failed. Expected %v, but got %v", expected, d)
+	}
+	if d, expected := co.EvalVector(s1, s2), NewCosineDist(false).Eval(s1.Dense(), s2.Dense()); math.Abs(d-expected) > 1e-12 {
+		t.Errorf("SparseCosineEval failed. Expected %v, but got %v", expected, d)
+	}
+}
+
+func TestKNNPredictVector(t *testing.T) {
+	dim := 100000
+	data := []DataPoint{
+		NewSparseDataPoint("sports", NewSparsePoint(dim, []int{1, 2}, []float64{3, 1})),
+		NewSparseDataPoint("politics", NewSparsePoint(dim, []int{50000, 60000}, []float64{2, 2})),
+	}
+	knn := NewKNN(1, NewSparseCosineDist(), NewMultiClassSelector(), data)
+	if l := knn.PredictVector(NewSparsePoint(dim, []int{60000}, []float64{1})); l != "politics" {
+		t.Errorf("PredictVector failed. Expected politics, but got %v", l)
+	}
+}
+
+func TestSparseSaveLoad(t *testing.T) {
+	dim := 100000
+	data := []DataPoint{
+		NewSparseDataPoint("sports", NewSparsePoint(dim, []int{1, 2}, []float64{3, 1})),
+		NewSparseDataPoint("politics", NewSparsePoint(dim, []int{50000, 60000}, []float64{2, 2})),
+	}
+	model := NewKNN(1, NewSparseEuclideanDist(), NewMultiClassSelector(), data)
+	buf := &bytes.Buffer{}
+	if err := model.Save(buf); err != nil {
+		t.Fatalf("Save failed. Expected nil, but got %v", err)
+	}
+	if buf.Len() > 1024 {
+		t.Errorf("Save failed. Expected sparse points, but got %d bytes", buf.Len())
+	}
+	loaded, err := Load(buf)
+	if err != nil {
+		t.Fatalf("Load failed. Expected nil, but got %v", err)
+	}
+	if l := loaded.Predict(NewSparsePoint(dim, []int{60000}, []float64{1}).Dense()); l != "politics" {
+		t.Errorf("Load failed. Expected politics, but got %v", l)
+	}
+	if _, ok := dataVector(loaded.data[0]).(*SparsePoint); !ok {
+		t.Errorf("Load failed. Expected sparse data points, but got %T", loaded.data[0])
+	}
+}
+
+// sparse data point that must not be densified
+type vectorOnly struct {
+	DataPoint
+}
+
+func (vo vectorOnly) Vector() Vector {
+	return vo.DataPoint.(interface{ Vector() Vector }).Vector()
+}
+
+func (vo vectorOnly) Point() Point {
+	panic("sparse data point is densified")
+}
+
+func TestLSHSparse(t *testing.T) {
+	const dim = 1000000
+	data := make([]DataPoint, 50)
+	for i := range data {
+		sp := NewSparsePoint(dim, []int{i, 1000 * i, dim - 1 - i}, []float64{1, 2, float64(i + 1)})
+		data[i] = vectorOnly{NewSparseDataPoint(i, sp)}
+	}
+	for _, index := range []DynamicIndex{NewCosineLSH(4, 8, 1), NewEuclideanLSH(4, 4, 2, 1)} {
+		index.Build(NewSparseCosineDist(), data)
+		query := NewSparsePoint(dim, []int{7, 7000, dim - 8}, []float64{1, 2, 8}).Dense()
+		if kset := index.KNearest(query, 1); len(kset) != 1 || kset[0].DataPoint().Label() != 7 {
+			t.Errorf("LSH KNearest failed. Expected sparse point 7, but got %v", kset)
+		}
+		index.Insert(vectorOnly{NewSparseDataPoint(50, NewSparsePoint(dim, []int{3}, []float64{1}))})
+		if !index.Remove(data[3]) {
+			t.Errorf("LSH Remove failed. Expected sparse point to be removed")
+		}
+	}
+	for _, width := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		func() {
+			defer func() {
+				if r := recover(); r != ErrLSHParamsNotValid {
+					t.Errorf("NewEuclideanLSH failed. Expected %v for width %v, but got %v", ErrLSHParamsNotValid, width, r)
+				}
+			}()
+			NewEuclideanLSH(4, 4, width, 1)
+		}()
+	}
+}
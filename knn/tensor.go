@@ -0,0 +1,92 @@
+package knn
+
+import (
+	"fmt"
+
+	"github.com/stellviaproject/go-ia/float16"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrTensorNotMatrix = fmt.Errorf("tensor is not a matrix of shape (samples, features)")
+	ErrLabelNotNumeric = fmt.Errorf("label is not numeric")
+	ErrLabelsMismatch  = fmt.Errorf("number of labels doesn't match number of samples")
+)
+
+// convert a tensor element to float64
+func tensorFloat(v any) float64 {
+	switch x := v.(type) {
+	case float16.Float16:
+		return x.ToF64()
+	case float32:
+		return float64(x)
+	case float64:
+		return x
+	}
+	panic(graph.ErrInvalidData)
+}
+
+// Get points from rows of a tensor of shape (samples, features), element (i, j) is feature j of point i
+func PointsFromTensor(features *graph.Tensor) []Point {
+	shape := features.Shape()
+	if shape.Dim() != 2 {
+		panic(ErrTensorNotMatrix)
+	}
+	points := make([]Point, shape[0])
+	index := make([]int, 2)
+	for i := range points {
+		p := NewPoint(shape[1])
+		index[0] = i
+		for j := range p {
+			index[1] = j
+			p[j] = tensorFloat(features.Get(index))
+		}
+		points[i] = p
+	}
+	return points
+}
+
+// Create KNN from a tensor of shape (samples, features) and the label of every sample
+func NewKNNFromTensor(k int, dist Distance, selector Selector, features *graph.Tensor, labels []any) *KNN {
+	points := PointsFromTensor(features)
+	if len(points) != len(labels) {
+		panic(ErrLabelsMismatch)
+	}
+	data := make([]DataPoint, len(points))
+	for i, p := range points {
+		data[i] = NewDataPoint(labels[i], p)
+	}
+	return NewKNN(k, dist, selector, data)
+}
+
+// convert a numeric label to float64
+func labelFloat(label any) float64 {
+	switch x := label.(type) {
+	case float64:
+		return x
+	case float32:
+		return float64(x)
+	case int:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case bool:
+		if x {
+			return 1
+		}
+		return 0
+	}
+	panic(ErrLabelNotNumeric)
+}
+
+// Predict labels of every row of a tensor of shape (samples, features)
+//
+// It returns a Float64 tensor of shape (samples), panics if labels are not numeric (float, int or bool)
+func (knn *KNN) PredictTensor(features *graph.Tensor) *graph.Tensor {
+	labels := knn.PredictBatch(PointsFromTensor(features))
+	out := make([]float64, len(labels))
+	for i, l := range labels {
+		out[i] = labelFloat(l)
+	}
+	return graph.NewTensor(out, graph.Float64, graph.NewShape(len(out)))
+}
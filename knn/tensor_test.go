@@ -0,0 +1,31 @@
+package knn
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+func TestKNNFromTensor(t *testing.T) {
+	// shape (4, 2), element (i, j) is at offset i + 4*j
+	features := graph.NewTensor([]float32{
+		0, 1, 10, 11,
+		0, 1, 10, 11,
+	}, graph.Float32, graph.NewShape(4, 2))
+	points := PointsFromTensor(features)
+	if points[2][0] != 10 || points[2][1] != 10 || points[1][1] != 1 {
+		t.Fatalf("PointsFromTensor failed. Unexpected points %v", points)
+	}
+	knn := NewKNNFromTensor(1, NewEuclideanDist(), NewRegressionSelector(), features, []any{0.0, 1.0, 2.0, 3.0})
+	queries := graph.NewTensor([]float64{0.9, 10.2, 0.9, 10.2}, graph.Float64, graph.NewShape(2, 2))
+	predicted := knn.PredictTensor(queries)
+	if predicted.GetF64At([]int{0}) != 1 || predicted.GetF64At([]int{1}) != 2 {
+		t.Errorf("PredictTensor failed. Expected [1, 2], but got %v", predicted)
+	}
+	defer func() {
+		if r := recover(); r != ErrLabelsMismatch {
+			t.Errorf("NewKNNFromTensor failed. Expected %v, but got %v", ErrLabelsMismatch, r)
+		}
+	}()
+	NewKNNFromTensor(1, NewEuclideanDist(), NewRegressionSelector(), features, []any{0.0})
+}
@@ -0,0 +1,76 @@
+package linalg
+
+import (
+	"math"
+	"sort"
+)
+
+// Eigenvalues and eigenvectors of a symmetric matrix by cyclic Jacobi rotations
+//
+// Eigenvalues are in decreasing order and column k of vectors is the unit eigenvector of eigenvalue k.
+// Only the symmetric part of m is used.
+func (m *Matrix) SymEigen() ([]float64, *Matrix) {
+	if m.rows != m.cols {
+		panic(ErrNotSquare)
+	}
+	n := m.rows
+	a := NewMatrix(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, (m.At(i, j)+m.At(j, i))/2)
+		}
+	}
+	v := Identity(n)
+	for sweep := 0; sweep < 100; sweep++ {
+		off, diag := 0.0, 0.0
+		for i := 0; i < n; i++ {
+			diag += a.At(i, i) * a.At(i, i)
+			for j := i + 1; j < n; j++ {
+				off += a.At(i, j) * a.At(i, j)
+			}
+		}
+		if off <= 1e-30*diag || off == 0 {
+			break
+		}
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := a.At(p, q)
+				if apq == 0 {
+					continue
+				}
+				// rotation that zeroes a[p][q]
+				theta := (a.At(q, q) - a.At(p, p)) / (2 * apq)
+				t := 1 / (math.Abs(theta) + math.Sqrt(1+theta*theta))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				rotate(a, p, q, c, s)
+				// rotate rows p and q
+				for j := 0; j < n; j++ {
+					ap, aq := a.At(p, j), a.At(q, j)
+					a.Set(p, j, c*ap-s*aq)
+					a.Set(q, j, s*ap+c*aq)
+				}
+				rotate(v, p, q, c, s)
+			}
+		}
+	}
+	values := make([]float64, n)
+	order := make([]int, n)
+	for i := range values {
+		values[i] = a.At(i, i)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] > values[order[j]] })
+	sorted := make([]float64, n)
+	vectors := NewMatrix(n, n, nil)
+	for k, i := range order {
+		sorted[k] = values[i]
+		for r := 0; r < n; r++ {
+			vectors.Set(r, k, v.At(r, i))
+		}
+	}
+	return sorted, vectors
+}
@@ -0,0 +1,40 @@
+package linalg
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSymEigen(t *testing.T) {
+	values, vectors := NewMatrix(2, 2, []float64{2, 1, 1, 2}).SymEigen()
+	if math.Abs(values[0]-3) > 1e-12 || math.Abs(values[1]-1) > 1e-12 {
+		t.Errorf("SymEigen failed. Expected [3 1], but got %v", values)
+	}
+	if math.Abs(math.Abs(vectors.At(0, 0))-math.Sqrt2/2) > 1e-12 {
+		t.Errorf("SymEigen failed. Unexpected eigenvectors %v", vectors)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	data := make([]float64, 25)
+	for i := range data {
+		data[i] = rnd.NormFloat64()
+	}
+	b := NewMatrix(5, 5, data)
+	a := b.Mul(b.T())
+	values, vectors = a.SymEigen()
+	for k := range values {
+		col := make([]float64, 5)
+		for i := range col {
+			col[i] = vectors.At(i, k)
+		}
+		av := a.MulVec(col)
+		for i := range av {
+			if math.Abs(av[i]-values[k]*col[i]) > 1e-9 {
+				t.Fatalf("SymEigen failed. Column %d is not an eigenvector", k)
+			}
+		}
+	}
+	if !near(vectors.T().Mul(vectors), Identity(5), 1e-10) {
+		t.Errorf("SymEigen failed. Eigenvectors are not orthonormal")
+	}
+}
@@ -0,0 +1,231 @@
+// Package linalg implements dense matrices and linear algebra routines
+package linalg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrDimMismatch = errors.New("matrix dimension mismatch")
+	ErrNotSquare   = errors.New("matrix is not square")
+	ErrSingular    = errors.New("matrix is singular")
+)
+
+// Dense matrix stored by rows
+type Matrix struct {
+	rows int
+	cols int
+	data []float64
+}
+
+// Create a matrix with given data stored by rows
+//
+// data may be nil, then a zero matrix is created, panics if len(data) is not rows*cols
+func NewMatrix(rows, cols int, data []float64) *Matrix {
+	if rows <= 0 || cols <= 0 {
+		panic(ErrDimMismatch)
+	}
+	if data == nil {
+		data = make([]float64, rows*cols)
+	}
+	if len(data) != rows*cols {
+		panic(ErrDimMismatch)
+	}
+	return &Matrix{rows: rows, cols: cols, data: data}
+}
+
+// Identity matrix of size n
+func Identity(n int) *Matrix {
+	m := NewMatrix(n, n, nil)
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+// Number of rows
+func (m *Matrix) Rows() int {
+	return m.rows
+}
+
+// Number of columns
+func (m *Matrix) Cols() int {
+	return m.cols
+}
+
+// Slice of elements stored by rows
+func (m *Matrix) Data() []float64 {
+	return m.data
+}
+
+// Get element at row i and column j
+func (m *Matrix) At(i, j int) float64 {
+	return m.data[i*m.cols+j]
+}
+
+// Set element at row i and column j
+func (m *Matrix) Set(i, j int, v float64) {
+	m.data[i*m.cols+j] = v
+}
+
+// Get a copy of row i
+func (m *Matrix) Row(i int) []float64 {
+	row := make([]float64, m.cols)
+	copy(row, m.data[i*m.cols:(i+1)*m.cols])
+	return row
+}
+
+// Copy of matrix
+func (m *Matrix) Clone() *Matrix {
+	data := make([]float64, len(m.data))
+	copy(data, m.data)
+	return &Matrix{rows: m.rows, cols: m.cols, data: data}
+}
+
+// Transpose of matrix
+func (m *Matrix) T() *Matrix {
+	t := NewMatrix(m.cols, m.rows, nil)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			t.data[j*m.rows+i] = m.data[i*m.cols+j]
+		}
+	}
+	return t
+}
+
+// Sum of matrices m + other
+func (m *Matrix) Add(other *Matrix) *Matrix {
+	if m.rows != other.rows || m.cols != other.cols {
+		panic(ErrDimMismatch)
+	}
+	out := m.Clone()
+	for i, v := range other.data {
+		out.data[i] += v
+	}
+	return out
+}
+
+// Difference of matrices m - other
+func (m *Matrix) Sub(other *Matrix) *Matrix {
+	if m.rows != other.rows || m.cols != other.cols {
+		panic(ErrDimMismatch)
+	}
+	out := m.Clone()
+	for i, v := range other.data {
+		out.data[i] -= v
+	}
+	return out
+}
+
+// Product of matrices m * other
+func (m *Matrix) Mul(other *Matrix) *Matrix {
+	if m.cols != other.rows {
+		panic(ErrDimMismatch)
+	}
+	out := NewMatrix(m.rows, other.cols, nil)
+	for i := 0; i < m.rows; i++ {
+		for k := 0; k < m.cols; k++ {
+			a := m.data[i*m.cols+k]
+			if a == 0 {
+				continue
+			}
+			for j := 0; j < other.cols; j++ {
+				out.data[i*other.cols+j] += a * other.data[k*other.cols+j]
+			}
+		}
+	}
+	return out
+}
+
+// Product of matrix by vector
+func (m *Matrix) MulVec(v []float64) []float64 {
+	if m.cols != len(v) {
+		panic(ErrDimMismatch)
+	}
+	out := make([]float64, m.rows)
+	for i := 0; i < m.rows; i++ {
+		sum := 0.0
+		for j, row := 0, m.data[i*m.cols:(i+1)*m.cols]; j < m.cols; j++ {
+			sum += row[j] * v[j]
+		}
+		out[i] = sum
+	}
+	return out
+}
+
+// Inverse of a square matrix by Gauss-Jordan elimination with partial pivoting
+//
+// It returns ErrSingular if matrix has no inverse
+func (m *Matrix) Inverse() (*Matrix, error) {
+	if m.rows != m.cols {
+		panic(ErrNotSquare)
+	}
+	n := m.rows
+	a := m.Clone()
+	inv := Identity(n)
+	// scale used to decide when a pivot is zero
+	scale := 0.0
+	for _, v := range a.data {
+		scale = math.Max(scale, math.Abs(v))
+	}
+	eps := scale * float64(n) * 1e-14
+	for col := 0; col < n; col++ {
+		// select pivot with the greatest absolute value
+		pivot := col
+		for i := col + 1; i < n; i++ {
+			if math.Abs(a.At(i, col)) > math.Abs(a.At(pivot, col)) {
+				pivot = i
+			}
+		}
+		if math.Abs(a.At(pivot, col)) <= eps {
+			return nil, ErrSingular
+		}
+		a.swapRows(col, pivot)
+		inv.swapRows(col, pivot)
+		// normalize pivot row
+		p := a.At(col, col)
+		for j := 0; j < n; j++ {
+			a.data[col*n+j] /= p
+			inv.data[col*n+j] /= p
+		}
+		// eliminate column in other rows
+		for i := 0; i < n; i++ {
+			if i == col {
+				continue
+			}
+			f := a.At(i, col)
+			if f == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				a.data[i*n+j] -= f * a.data[col*n+j]
+				inv.data[i*n+j] -= f * inv.data[col*n+j]
+			}
+		}
+	}
+	return inv, nil
+}
+
+func (m *Matrix) swapRows(i, j int) {
+	if i == j {
+		return
+	}
+	ri := m.data[i*m.cols : (i+1)*m.cols]
+	rj := m.data[j*m.cols : (j+1)*m.cols]
+	for k := range ri {
+		ri[k], rj[k] = rj[k], ri[k]
+	}
+}
+
+func (m *Matrix) String() string {
+	s := "["
+	for i := 0; i < m.rows; i++ {
+		if i > 0 {
+			s += "\n "
+		}
+		s += fmt.Sprint(m.data[i*m.cols : (i+1)*m.cols])
+	}
+	return s + "]"
+}
@@ -0,0 +1,56 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b *Matrix, eps float64) bool {
+	if a.rows != b.rows || a.cols != b.cols {
+		return false
+	}
+	for i := range a.data {
+		if math.Abs(a.data[i]-b.data[i]) > eps {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMul(t *testing.T) {
+	a := NewMatrix(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	b := a.T()
+	c := a.Mul(b)
+	if expected := NewMatrix(2, 2, []float64{14, 32, 32, 77}); !near(c, expected, 0) {
+		t.Errorf("Mul failed. Expected %v, but got %v", expected, c)
+	}
+	if v := a.MulVec([]float64{1, 1, 1}); v[0] != 6 || v[1] != 15 {
+		t.Errorf("MulVec failed. Expected [6 15], but got %v", v)
+	}
+}
+
+func TestAddSub(t *testing.T) {
+	a := NewMatrix(2, 2, []float64{1, 2, 3, 4})
+	b := Identity(2)
+	if expected := NewMatrix(2, 2, []float64{2, 2, 3, 5}); !near(a.Add(b), expected, 0) {
+		t.Errorf("Add failed. Expected %v, but got %v", expected, a.Add(b))
+	}
+	if !near(a.Add(b).Sub(b), a, 0) {
+		t.Errorf("Sub failed. Expected %v, but got %v", a, a.Add(b).Sub(b))
+	}
+}
+
+func TestInverse(t *testing.T) {
+	a := NewMatrix(3, 3, []float64{0, 2, 1, 1, 1, 0, 3, 0, 4})
+	inv, err := a.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(a.Mul(inv), Identity(3), 1e-12) {
+		t.Errorf("Inverse failed. Product is not identity %v", a.Mul(inv))
+	}
+	singular := NewMatrix(2, 2, []float64{1, 2, 2, 4})
+	if _, err := singular.Inverse(); err != ErrSingular {
+		t.Errorf("Inverse failed. Expected %v, but got %v", ErrSingular, err)
+	}
+}
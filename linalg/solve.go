@@ -0,0 +1,120 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrNotPositiveDefinite = errors.New("matrix is not symmetric positive definite")
+
+// Cholesky factor L of a symmetric positive definite matrix, so m = L * L^T
+//
+// Only the lower triangle of m is read, it returns ErrNotPositiveDefinite if a pivot is not positive
+func (m *Matrix) Cholesky() (*Matrix, error) {
+	if m.rows != m.cols {
+		panic(ErrNotSquare)
+	}
+	n := m.rows
+	l := NewMatrix(n, n, nil)
+	for j := 0; j < n; j++ {
+		sum := m.At(j, j)
+		for k := 0; k < j; k++ {
+			sum -= l.At(j, k) * l.At(j, k)
+		}
+		if sum <= 0 || math.IsNaN(sum) {
+			return nil, ErrNotPositiveDefinite
+		}
+		d := math.Sqrt(sum)
+		l.Set(j, j, d)
+		for i := j + 1; i < n; i++ {
+			sum := m.At(i, j)
+			for k := 0; k < j; k++ {
+				sum -= l.At(i, k) * l.At(j, k)
+			}
+			l.Set(i, j, sum/d)
+		}
+	}
+	return l, nil
+}
+
+// Solve L * L^T * x = b with the Cholesky factor L
+func CholeskySolve(l *Matrix, b []float64) []float64 {
+	if l.rows != l.cols {
+		panic(ErrNotSquare)
+	}
+	if len(b) != l.rows {
+		panic(ErrDimMismatch)
+	}
+	n := l.rows
+	// forward substitution L * y = b
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= l.At(i, k) * y[k]
+		}
+		y[i] = sum / l.At(i, i)
+	}
+	// back substitution L^T * x = y
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := i + 1; k < n; k++ {
+			sum -= l.At(k, i) * x[k]
+		}
+		x[i] = sum / l.At(i, i)
+	}
+	return x
+}
+
+// Solve m * x = b by Gaussian elimination with partial pivoting
+//
+// It returns ErrSingular if matrix has no inverse
+func (m *Matrix) Solve(b []float64) ([]float64, error) {
+	if m.rows != m.cols {
+		panic(ErrNotSquare)
+	}
+	if len(b) != m.rows {
+		panic(ErrDimMismatch)
+	}
+	n := m.rows
+	a := m.Clone()
+	x := make([]float64, n)
+	copy(x, b)
+	scale := 0.0
+	for _, v := range a.data {
+		scale = math.Max(scale, math.Abs(v))
+	}
+	eps := scale * float64(n) * 1e-14
+	for col := 0; col < n; col++ {
+		pivot := col
+		for i := col + 1; i < n; i++ {
+			if math.Abs(a.At(i, col)) > math.Abs(a.At(pivot, col)) {
+				pivot = i
+			}
+		}
+		if math.Abs(a.At(pivot, col)) <= eps {
+			return nil, ErrSingular
+		}
+		a.swapRows(col, pivot)
+		x[col], x[pivot] = x[pivot], x[col]
+		for i := col + 1; i < n; i++ {
+			f := a.At(i, col) / a.At(col, col)
+			if f == 0 {
+				continue
+			}
+			for j := col; j < n; j++ {
+				a.data[i*n+j] -= f * a.data[col*n+j]
+			}
+			x[i] -= f * x[col]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := x[i]
+		for j := i + 1; j < n; j++ {
+			sum -= a.At(i, j) * x[j]
+		}
+		x[i] = sum / a.At(i, i)
+	}
+	return x, nil
+}
@@ -0,0 +1,43 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCholesky(t *testing.T) {
+	a := NewMatrix(3, 3, []float64{4, 12, -16, 12, 37, -43, -16, -43, 98})
+	l, err := a.Cholesky()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := NewMatrix(3, 3, []float64{2, 0, 0, 6, 1, 0, -8, 5, 3}); !near(l, expected, 1e-12) {
+		t.Errorf("Cholesky failed. Expected %v, but got %v", expected, l)
+	}
+	x := CholeskySolve(l, []float64{1, 2, 3})
+	b := a.MulVec(x)
+	for i, v := range []float64{1, 2, 3} {
+		if math.Abs(b[i]-v) > 1e-9 {
+			t.Errorf("CholeskySolve failed. Expected %v, but got %v", v, b[i])
+		}
+	}
+	if _, err := NewMatrix(2, 2, []float64{1, 2, 2, 1}).Cholesky(); err != ErrNotPositiveDefinite {
+		t.Errorf("Cholesky failed. Expected ErrNotPositiveDefinite, but got %v", err)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	a := NewMatrix(3, 3, []float64{0, 2, 1, 1, 1, 0, 3, 0, 4})
+	x, err := a.Solve([]float64{5, 3, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range []float64{1, 2, 1} {
+		if math.Abs(x[i]-v) > 1e-12 {
+			t.Errorf("Solve failed. Expected %v, but got %v", v, x[i])
+		}
+	}
+	if _, err := NewMatrix(2, 2, []float64{1, 2, 2, 4}).Solve([]float64{1, 1}); err != ErrSingular {
+		t.Errorf("Solve failed. Expected ErrSingular, but got %v", err)
+	}
+}
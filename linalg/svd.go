@@ -0,0 +1,88 @@
+package linalg
+
+import (
+	"math"
+	"sort"
+)
+
+// Thin singular value decomposition m = U * diag(S) * V^T by one-sided Jacobi rotations
+//
+// For a rows x cols matrix and k = min(rows, cols), U is rows x k, V is cols x k and S has k singular values
+// in decreasing order. Columns of U of zero singular values are zero.
+func (m *Matrix) SVD() (*Matrix, []float64, *Matrix) {
+	if m.rows < m.cols {
+		v, s, u := m.T().SVD()
+		return u, s, v
+	}
+	rows, cols := m.rows, m.cols
+	a := m.Clone()
+	v := Identity(cols)
+	for sweep := 0; sweep < 60; sweep++ {
+		rotated := false
+		for p := 0; p < cols-1; p++ {
+			for q := p + 1; q < cols; q++ {
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for i := 0; i < rows; i++ {
+					ap, aq := a.data[i*cols+p], a.data[i*cols+q]
+					alpha += ap * ap
+					beta += aq * aq
+					gamma += ap * aq
+				}
+				if gamma == 0 || math.Abs(gamma) <= 1e-15*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				// rotation that makes columns p and q orthogonal
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				rotate(a, p, q, c, s)
+				rotate(v, p, q, c, s)
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+	sv := make([]float64, cols)
+	for j := range sv {
+		sum := 0.0
+		for i := 0; i < rows; i++ {
+			sum += a.data[i*cols+j] * a.data[i*cols+j]
+		}
+		sv[j] = math.Sqrt(sum)
+	}
+	order := make([]int, cols)
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(i, j int) bool { return sv[order[i]] > sv[order[j]] })
+	u := NewMatrix(rows, cols, nil)
+	vs := NewMatrix(cols, cols, nil)
+	s := make([]float64, cols)
+	for k, j := range order {
+		s[k] = sv[j]
+		for i := 0; i < rows; i++ {
+			if sv[j] > 0 {
+				u.data[i*cols+k] = a.data[i*cols+j] / sv[j]
+			}
+		}
+		for i := 0; i < cols; i++ {
+			vs.data[i*cols+k] = v.data[i*cols+j]
+		}
+	}
+	return u, s, vs
+}
+
+// rotate columns p and q of matrix by cosine c and sine s
+func rotate(m *Matrix, p, q int, c, s float64) {
+	for i := 0; i < m.rows; i++ {
+		ap, aq := m.data[i*m.cols+p], m.data[i*m.cols+q]
+		m.data[i*m.cols+p] = c*ap - s*aq
+		m.data[i*m.cols+q] = s*ap + c*aq
+	}
+}
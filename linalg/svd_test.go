@@ -0,0 +1,44 @@
+package linalg
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func reconstruct(u *Matrix, s []float64, v *Matrix) *Matrix {
+	us := u.Clone()
+	for i := 0; i < us.rows; i++ {
+		for j := 0; j < us.cols; j++ {
+			us.Set(i, j, us.At(i, j)*s[j])
+		}
+	}
+	return us.Mul(v.T())
+}
+
+func TestSVD(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, shape := range [][2]int{{6, 4}, {3, 5}, {4, 4}} {
+		data := make([]float64, shape[0]*shape[1])
+		for i := range data {
+			data[i] = rnd.NormFloat64()
+		}
+		a := NewMatrix(shape[0], shape[1], data)
+		u, s, v := a.SVD()
+		if !near(reconstruct(u, s, v), a, 1e-10) {
+			t.Errorf("SVD failed. Product of %v x %v is not the matrix", shape[0], shape[1])
+		}
+		for i := 1; i < len(s); i++ {
+			if s[i] > s[i-1] {
+				t.Errorf("SVD failed. Singular values are not decreasing %v", s)
+			}
+		}
+		if !near(v.T().Mul(v), Identity(len(s)), 1e-10) || !near(u.T().Mul(u), Identity(len(s)), 1e-10) {
+			t.Errorf("SVD failed. Singular vectors are not orthonormal")
+		}
+	}
+	// rank one matrix
+	u, s, v := NewMatrix(2, 2, []float64{1, 2, 2, 4}).SVD()
+	if s[1] > 1e-12 || !near(reconstruct(u, s, v), NewMatrix(2, 2, []float64{1, 2, 2, 4}), 1e-12) {
+		t.Errorf("SVD failed. Expected rank one, but got %v", s)
+	}
+}
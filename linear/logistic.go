@@ -0,0 +1,343 @@
+// Package linear implements linear models for classification and regression
+package linear
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+var (
+	ErrNotFitted         = errors.New("model is not fitted")
+	ErrEmptyData         = errors.New("there are no data points to fit")
+	ErrDimensionMismatch = errors.New("point dimension doesn't match fitted dimension")
+	ErrTooFewClasses     = errors.New("there are less than two classes")
+	ErrPenaltyNotValid   = errors.New("penalty is not valid")
+)
+
+// Regularization penalty of weights, the intercept is never penalized
+type Penalty int
+
+const (
+	NoPenalty Penalty = iota
+	L1
+	L2
+)
+
+// Configuration of logistic regression
+type LogisticConfig struct {
+	Penalty     Penalty
+	Lambda      float64         //strength of penalty
+	Epochs      int             //greatest number of full batch gradient steps, 1000 if zero
+	Tol         float64         //stop when every gradient component is lesser than it, 1e-6 if zero
+	ClassWeight map[any]float64 //weight of every class, 1 for missing classes
+	Balanced    bool            //weight classes inversely to their frequency, it overrides ClassWeight
+	Optimizer   optim.Optimizer //Adam with rate 0.05 if nil, L1 penalty uses its subgradient if it isn't an optim.StepSizer
+}
+
+// Logistic regression, binary with sigmoid and multiclass with softmax
+type LogisticRegression struct {
+	config  LogisticConfig
+	classes []any
+	dim     int
+	params  []float64 //rows of weights with intercept at the end, one row if binary
+	steps   int       //gradient steps of the last Fit
+}
+
+// Create logistic regression
+func NewLogisticRegression(config LogisticConfig) *LogisticRegression {
+	if config.Penalty < NoPenalty || config.Penalty > L2 {
+		panic(ErrPenaltyNotValid)
+	}
+	if config.Epochs <= 0 {
+		config.Epochs = 1000
+	}
+	if config.Tol <= 0 {
+		config.Tol = 1e-6
+	}
+	if config.Optimizer == nil {
+		config.Optimizer = optim.NewAdam(0.05)
+	}
+	return &LogisticRegression{config: config}
+}
+
+// number of weight rows
+func (lr *LogisticRegression) outputs() int {
+	if len(lr.classes) == 2 {
+		return 1
+	}
+	return len(lr.classes)
+}
+
+// Fit weights by gradient descent of the weighted cross entropy, L1 penalty uses proximal steps of optim.ProximalL1
+// with optimizers that report their step size, so weights reach exact zeros
+func (lr *LogisticRegression) Fit(data []knn.DataPoint) {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	lr.dim = len(data[0].Point())
+	ids := make(map[any]int)
+	lr.classes = nil
+	targets := make([]int, len(data))
+	counts := make([]int, 0, 10)
+	for i, dp := range data {
+		if len(dp.Point()) != lr.dim {
+			panic(ErrDimensionMismatch)
+		}
+		id, ok := ids[dp.Label()]
+		if !ok {
+			id = len(lr.classes)
+			ids[dp.Label()] = id
+			lr.classes = append(lr.classes, dp.Label())
+			counts = append(counts, 0)
+		}
+		targets[i] = id
+		counts[id]++
+	}
+	if len(lr.classes) < 2 {
+		panic(ErrTooFewClasses)
+	}
+	weights := make([]float64, len(lr.classes))
+	for c, label := range lr.classes {
+		switch {
+		case lr.config.Balanced:
+			weights[c] = float64(len(data)) / float64(len(lr.classes)*counts[c])
+		case lr.config.ClassWeight != nil:
+			if w, ok := lr.config.ClassWeight[label]; ok {
+				weights[c] = w
+			} else {
+				weights[c] = 1
+			}
+		default:
+			weights[c] = 1
+		}
+	}
+	total := 0.0
+	for _, t := range targets {
+		total += weights[t]
+	}
+	outs, cols := lr.outputs(), lr.dim+1
+	lr.params = make([]float64, outs*cols)
+	grads := make([]float64, len(lr.params))
+	proba := make([]float64, len(lr.classes))
+	// intercepts are not penalized
+	penalized := make([]bool, len(lr.params))
+	for o := 0; o < outs; o++ {
+		for d := 0; d < lr.dim; d++ {
+			penalized[o*cols+d] = true
+		}
+	}
+	opt := lr.config.Optimizer
+	_, proximal := opt.(optim.StepSizer)
+	proximal = proximal && lr.config.Penalty == L1
+	if proximal {
+		opt = optim.NewProximalL1(opt, lr.config.Lambda, penalized)
+	}
+	opt.Reset()
+	lr.steps = 0
+	for epoch := 0; epoch < lr.config.Epochs; epoch++ {
+		for i := range grads {
+			grads[i] = 0
+		}
+		for i, dp := range data {
+			lr.probabilities(dp.Point(), proba)
+			w := weights[targets[i]] / total
+			for o := 0; o < outs; o++ {
+				// derivative of cross entropy by the score of output o
+				var dif float64
+				if outs == 1 {
+					dif = proba[1]
+					if targets[i] == 1 {
+						dif--
+					}
+				} else {
+					dif = proba[o]
+					if targets[i] == o {
+						dif--
+					}
+				}
+				row := grads[o*cols : (o+1)*cols]
+				for d, v := range dp.Point() {
+					row[d] += w * dif * v
+				}
+				row[lr.dim] += w * dif
+			}
+		}
+		if lr.config.Penalty == L2 {
+			for i, p := range lr.params {
+				if penalized[i] {
+					grads[i] += lr.config.Lambda * p
+				}
+			}
+		}
+		// the L1 penalty isn't differentiable at zero, its optimality is measured by the proximal residual
+		lambda := 0.0
+		if lr.config.Penalty == L1 {
+			lambda = lr.config.Lambda
+		}
+		if optim.ProximalResidual(lr.params, grads, lambda, penalized) < lr.config.Tol {
+			break
+		}
+		if lr.config.Penalty == L1 && !proximal {
+			for i, p := range lr.params {
+				if penalized[i] && p != 0 {
+					grads[i] += math.Copysign(lr.config.Lambda, p)
+				}
+			}
+		}
+		opt.Step(lr.params, grads)
+		lr.steps++
+	}
+}
+
+// Gradient steps of the last Fit, lesser than Epochs if it converged
+func (lr *LogisticRegression) Steps() int {
+	return lr.steps
+}
+
+func (lr *LogisticRegression) check(point knn.Point) {
+	if lr.params == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != lr.dim {
+		panic(ErrDimensionMismatch)
+	}
+}
+
+// Scores of point, one score if binary and one score by class if multiclass
+func (lr *LogisticRegression) DecisionFunction(point knn.Point) []float64 {
+	lr.check(point)
+	return lr.scores(point)
+}
+
+func (lr *LogisticRegression) scores(point knn.Point) []float64 {
+	cols := lr.dim + 1
+	scores := make([]float64, lr.outputs())
+	for o := range scores {
+		row := lr.params[o*cols : (o+1)*cols]
+		s := row[lr.dim]
+		for d, v := range point {
+			s += row[d] * v
+		}
+		scores[o] = s
+	}
+	return scores
+}
+
+// probabilities of classes in the order of classes
+func (lr *LogisticRegression) probabilities(point knn.Point, proba []float64) {
+	scores := lr.scores(point)
+	if len(scores) == 1 {
+		proba[1] = 1 / (1 + math.Exp(-scores[0]))
+		proba[0] = 1 - proba[1]
+		return
+	}
+	maxScore := math.Inf(-1)
+	for _, s := range scores {
+		maxScore = math.Max(maxScore, s)
+	}
+	sum := 0.0
+	for c, s := range scores {
+		proba[c] = math.Exp(s - maxScore)
+		sum += proba[c]
+	}
+	for c := range proba {
+		proba[c] /= sum
+	}
+}
+
+// Predict class probabilities of point
+func (lr *LogisticRegression) PredictProba(point knn.Point) map[any]float64 {
+	lr.check(point)
+	proba := make([]float64, len(lr.classes))
+	lr.probabilities(point, proba)
+	out := make(map[any]float64, len(proba))
+	for c, p := range proba {
+		out[lr.classes[c]] = p
+	}
+	return out
+}
+
+// Predict label of point
+func (lr *LogisticRegression) Predict(point knn.Point) any {
+	lr.check(point)
+	proba := make([]float64, len(lr.classes))
+	lr.probabilities(point, proba)
+	best := 0
+	for c := range proba {
+		if proba[c] > proba[best] {
+			best = c
+		}
+	}
+	return lr.classes[best]
+}
+
+// Classes in the order of weight rows, the second class is the positive one if binary
+func (lr *LogisticRegression) Classes() []any {
+	return lr.classes
+}
+
+// Weights of features, one row if binary and one row by class if multiclass
+func (lr *LogisticRegression) Coef() [][]float64 {
+	if lr.params == nil {
+		panic(ErrNotFitted)
+	}
+	cols := lr.dim + 1
+	coef := make([][]float64, lr.outputs())
+	for o := range coef {
+		coef[o] = append([]float64(nil), lr.params[o*cols:o*cols+lr.dim]...)
+	}
+	return coef
+}
+
+// Intercepts, one if binary and one by class if multiclass
+func (lr *LogisticRegression) Intercept() []float64 {
+	if lr.params == nil {
+		panic(ErrNotFitted)
+	}
+	cols := lr.dim + 1
+	intercept := make([]float64, lr.outputs())
+	for o := range intercept {
+		intercept[o] = lr.params[o*cols+lr.dim]
+	}
+	return intercept
+}
+
+type logisticSpec struct {
+	Penalty     Penalty
+	Lambda      float64
+	Epochs      int
+	Tol         float64
+	ClassWeight map[any]float64
+	Balanced    bool
+	Classes     []any
+	Dim         int
+	Params      []float64
+}
+
+// Encode configuration and weights, the optimizer is not encoded and decoded models use the default one
+func (lr *LogisticRegression) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&logisticSpec{
+		Penalty: lr.config.Penalty, Lambda: lr.config.Lambda, Epochs: lr.config.Epochs, Tol: lr.config.Tol,
+		ClassWeight: lr.config.ClassWeight, Balanced: lr.config.Balanced,
+		Classes: lr.classes, Dim: lr.dim, Params: lr.params,
+	})
+	return buf.Bytes(), err
+}
+
+// Decode model encoded by GobEncode
+func (lr *LogisticRegression) GobDecode(b []byte) error {
+	var spec logisticSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return err
+	}
+	*lr = *NewLogisticRegression(LogisticConfig{Penalty: spec.Penalty, Lambda: spec.Lambda, Epochs: spec.Epochs,
+		Tol: spec.Tol, ClassWeight: spec.ClassWeight, Balanced: spec.Balanced})
+	lr.classes, lr.dim, lr.params = spec.Classes, spec.Dim, spec.Params
+	return nil
+}
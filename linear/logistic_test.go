@@ -0,0 +1,100 @@
+package linear
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/metrics"
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+func accuracy(model interface{ Predict(knn.Point) any }, data []knn.DataPoint) float64 {
+	expected := make([]any, len(data))
+	predicted := make([]any, len(data))
+	for i, dp := range data {
+		expected[i] = dp.Label()
+		predicted[i] = model.Predict(dp.Point())
+	}
+	return metrics.Accuracy(expected, predicted)
+}
+
+func TestBinaryLogistic(t *testing.T) {
+	data := dataset.MakeBlobs(400, []knn.Point{{-2, -2}, {2, 2}}, 1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.25, true, 1)
+	lr := NewLogisticRegression(LogisticConfig{Penalty: L2, Lambda: 0.01})
+	lr.Fit(train)
+	if acc := accuracy(lr, test); acc < 0.95 {
+		t.Errorf("LogisticRegression failed. Expected accuracy greater than 0.95, but got %v", acc)
+	}
+	if coef := lr.Coef(); len(coef) != 1 || len(coef[0]) != 2 {
+		t.Errorf("Coef failed. Expected one row of two weights, but got %v", coef)
+	}
+	proba := lr.PredictProba(knn.Point{0, 0})
+	if math.Abs(proba[0]+proba[1]-1) > 1e-12 {
+		t.Errorf("PredictProba failed. Probabilities don't sum 1: %v", proba)
+	}
+}
+
+func TestSoftmax(t *testing.T) {
+	data := dataset.MakeBlobs(600, []knn.Point{{0, 4}, {4, -2}, {-4, -2}}, 1, 2)
+	train, test := dataset.SplitTrainTest(data, 0.25, true, 2)
+	lr := NewLogisticRegression(LogisticConfig{})
+	lr.Fit(train)
+	if acc := accuracy(lr, test); acc < 0.95 {
+		t.Errorf("Softmax failed. Expected accuracy greater than 0.95, but got %v", acc)
+	}
+	if len(lr.Intercept()) != 3 {
+		t.Errorf("Intercept failed. Expected 3 intercepts, but got %v", lr.Intercept())
+	}
+}
+
+func TestL1Sparsity(t *testing.T) {
+	// only the first feature is informative
+	data := dataset.MakeBlobs(400, []knn.Point{{-2, 0, 0}, {2, 0, 0}}, 1, 3)
+	lr := NewLogisticRegression(LogisticConfig{Penalty: L1, Lambda: 0.05, Epochs: 3000})
+	lr.Fit(data)
+	coef := lr.Coef()[0]
+	if math.Abs(coef[0]) < 0.5 || math.Abs(coef[1]) > 0.05 || math.Abs(coef[2]) > 0.05 {
+		t.Errorf("L1 failed. Expected only first weight, but got %v", coef)
+	}
+	// proximal steps reach exact zeros and converge
+	lr = NewLogisticRegression(LogisticConfig{Penalty: L1, Lambda: 0.2, Epochs: 100000, Tol: 1e-4, Optimizer: optim.NewSGD(0.5, 0)})
+	lr.Fit(data)
+	if coef := lr.Coef()[0]; coef[0] == 0 || coef[1] != 0 || coef[2] != 0 {
+		t.Errorf("L1 failed. Expected exact zeros of noise weights, but got %v", coef)
+	}
+	if lr.Steps() == 100000 {
+		t.Errorf("L1 failed. Expected convergence before %d steps", lr.Steps())
+	}
+	// optimizers without step sizes use the subgradient of penalty
+	lr = NewLogisticRegression(LogisticConfig{Penalty: L1, Lambda: 0.05, Epochs: 3000, Optimizer: plainOptimizer{optim.NewAdam(0.05)}})
+	lr.Fit(data)
+	if coef := lr.Coef()[0]; math.Abs(coef[0]) < 0.5 || math.Abs(coef[1]) > 0.05 || math.Abs(coef[2]) > 0.05 {
+		t.Errorf("L1 failed. Expected only first weight with subgradient, but got %v", coef)
+	}
+}
+
+// optimizer that doesn't report its step size, like wrappers of other packages
+type plainOptimizer struct {
+	optim.Optimizer
+}
+
+func TestClassWeight(t *testing.T) {
+	// imbalanced overlapping classes, balanced weights move the boundary to the majority class
+	data := dataset.MakeBlobs(500, []knn.Point{{-1}, {1}, {-1}, {-1}, {-1}}, 1.5, 4)
+	for i, dp := range data {
+		if dp.Label() != 1 {
+			data[i] = knn.NewDataPoint(0, dp.Point())
+		}
+	}
+	plain := NewLogisticRegression(LogisticConfig{})
+	plain.Fit(data)
+	balanced := NewLogisticRegression(LogisticConfig{Balanced: true})
+	balanced.Fit(data)
+	p, b := plain.PredictProba(knn.Point{0})[1], balanced.PredictProba(knn.Point{0})[1]
+	if b <= p {
+		t.Errorf("Balanced failed. Expected greater probability of minority class than %v, but got %v", p, b)
+	}
+}
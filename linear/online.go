@@ -0,0 +1,187 @@
+package linear
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// weights of online classifiers, one row by class with bias at the end, new classes get zero rows
+type online struct {
+	classes []any
+	ids     map[any]int
+	weights [][]float64
+	dim     int
+}
+
+// id of class of label, adding it if it is new
+func (ol *online) class(label any, dim int) int {
+	if ol.ids == nil {
+		ol.ids = make(map[any]int)
+		ol.dim = dim
+	}
+	if dim != ol.dim {
+		panic(ErrDimensionMismatch)
+	}
+	id, ok := ol.ids[label]
+	if !ok {
+		id = len(ol.classes)
+		ol.ids[label] = id
+		ol.classes = append(ol.classes, label)
+		ol.weights = append(ol.weights, make([]float64, dim+1))
+	}
+	return id
+}
+
+func (ol *online) score(c int, point knn.Point) float64 {
+	row := ol.weights[c]
+	s := row[ol.dim]
+	for d, v := range point {
+		s += row[d] * v
+	}
+	return s
+}
+
+// best class of point, and best class other than exclude if exclude is not -1
+func (ol *online) best(point knn.Point, exclude int) int {
+	best := -1
+	for c := range ol.weights {
+		if c != exclude && (best == -1 || ol.score(c, point) > ol.score(best, point)) {
+			best = c
+		}
+	}
+	return best
+}
+
+// add step * (point, 1) to the weights of class
+func (ol *online) update(c int, point knn.Point, step float64) {
+	row := ol.weights[c]
+	for d, v := range point {
+		row[d] += step * v
+	}
+	row[ol.dim] += step
+}
+
+func (ol *online) check(point knn.Point) {
+	if ol.classes == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != ol.dim {
+		panic(ErrDimensionMismatch)
+	}
+}
+
+// Scores of point by class in the order of Classes
+func (ol *online) DecisionFunction(point knn.Point) []float64 {
+	ol.check(point)
+	scores := make([]float64, len(ol.classes))
+	for c := range scores {
+		scores[c] = ol.score(c, point)
+	}
+	return scores
+}
+
+// Predict label of point
+func (ol *online) Predict(point knn.Point) any {
+	ol.check(point)
+	return ol.classes[ol.best(point, -1)]
+}
+
+// Classes seen so far in order of appearance
+func (ol *online) Classes() []any {
+	return ol.classes
+}
+
+// fit epochs over data in shuffled order with partialFit
+func fitOnline(data []knn.DataPoint, epochs int, seed int64, partialFit func([]knn.DataPoint)) {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	shuffled := make([]knn.DataPoint, len(data))
+	for epoch := 0; epoch < epochs; epoch++ {
+		for i, p := range rnd.Perm(len(data)) {
+			shuffled[i] = data[p]
+		}
+		partialFit(shuffled)
+	}
+}
+
+// Multiclass perceptron, updated only by mistakes
+type Perceptron struct {
+	online
+	rate float64
+}
+
+// Create perceptron with learning rate, 1 if zero
+func NewPerceptron(rate float64) *Perceptron {
+	if rate <= 0 {
+		rate = 1
+	}
+	return &Perceptron{rate: rate}
+}
+
+// Update weights with data points in order, classes are learned as they appear
+func (pc *Perceptron) PartialFit(data []knn.DataPoint) {
+	for _, dp := range data {
+		y := pc.class(dp.Label(), len(dp.Point()))
+		if predicted := pc.best(dp.Point(), -1); predicted != y {
+			pc.update(y, dp.Point(), pc.rate)
+			pc.update(predicted, dp.Point(), -pc.rate)
+		}
+	}
+}
+
+// Fit epochs over data in shuffled order
+func (pc *Perceptron) Fit(data []knn.DataPoint, epochs int, seed int64) {
+	pc.online = online{}
+	fitOnline(data, epochs, seed, pc.PartialFit)
+}
+
+// Multiclass passive-aggressive classifier (PA-I)
+//
+// Every data point with margin lesser than 1 against the best wrong class makes the smallest update that fixes it,
+// with step bounded by c
+type PassiveAggressive struct {
+	online
+	c float64
+}
+
+// Create passive-aggressive classifier with aggressiveness c, unbounded steps if c is zero
+func NewPassiveAggressive(c float64) *PassiveAggressive {
+	if c <= 0 {
+		c = math.Inf(1)
+	}
+	return &PassiveAggressive{c: c}
+}
+
+// Update weights with data points in order, classes are learned as they appear
+func (pa *PassiveAggressive) PartialFit(data []knn.DataPoint) {
+	for _, dp := range data {
+		point := dp.Point()
+		y := pa.class(dp.Label(), len(point))
+		if len(pa.classes) < 2 {
+			continue
+		}
+		r := pa.best(point, y)
+		loss := 1 - (pa.score(y, point) - pa.score(r, point))
+		if loss <= 0 {
+			continue
+		}
+		// both rows change, so the squared norm of the update is doubled
+		norm := 1.0
+		for _, v := range point {
+			norm += v * v
+		}
+		tau := math.Min(pa.c, loss/(2*norm))
+		pa.update(y, point, tau)
+		pa.update(r, point, -tau)
+	}
+}
+
+// Fit epochs over data in shuffled order
+func (pa *PassiveAggressive) Fit(data []knn.DataPoint, epochs int, seed int64) {
+	pa.online = online{}
+	fitOnline(data, epochs, seed, pa.PartialFit)
+}
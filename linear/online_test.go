@@ -0,0 +1,48 @@
+package linear
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func TestPerceptron(t *testing.T) {
+	data := dataset.MakeBlobs(600, []knn.Point{{0, 5}, {5, -3}, {-5, -3}}, 1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.25, true, 1)
+	pc := NewPerceptron(0)
+	pc.Fit(train, 5, 1)
+	if acc := accuracy(pc, test); acc < 0.95 {
+		t.Errorf("Perceptron failed. Expected accuracy greater than 0.95, but got %v", acc)
+	}
+	if len(pc.Classes()) != 3 || len(pc.DecisionFunction(knn.Point{0, 0})) != 3 {
+		t.Errorf("Perceptron failed. Expected 3 classes, but got %v", pc.Classes())
+	}
+}
+
+func TestPassiveAggressive(t *testing.T) {
+	data := dataset.MakeBlobs(600, []knn.Point{{0, 5}, {5, -3}, {-5, -3}}, 1, 2)
+	train, test := dataset.SplitTrainTest(data, 0.25, true, 2)
+	pa := NewPassiveAggressive(0.1)
+	// stream of batches
+	for i := 0; i < len(train); i += 50 {
+		end := i + 50
+		if end > len(train) {
+			end = len(train)
+		}
+		pa.PartialFit(train[i:end])
+	}
+	if acc := accuracy(pa, test); acc < 0.95 {
+		t.Errorf("PassiveAggressive failed. Expected accuracy greater than 0.95, but got %v", acc)
+	}
+}
+
+func TestPassiveAggressiveStep(t *testing.T) {
+	// an unbounded step fixes the margin of the data point exactly
+	pa := NewPassiveAggressive(0)
+	pa.PartialFit([]knn.DataPoint{knn.NewDataPoint("a", knn.Point{1}), knn.NewDataPoint("b", knn.Point{2})})
+	scores := pa.DecisionFunction(knn.Point{2})
+	if dif := scores[1] - scores[0]; dif < 1-1e-12 || dif > 1+1e-12 {
+		t.Errorf("PartialFit failed. Expected margin 1, but got %v", dif)
+	}
+}
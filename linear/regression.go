@@ -0,0 +1,309 @@
+package linear
+
+import (
+	"errors"
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+var (
+	ErrLabelNotNumeric = errors.New("label of data point is not float64")
+	ErrAlphaNotValid   = errors.New("regularization alpha is lesser than zero")
+)
+
+type method int
+
+const (
+	ols method = iota
+	ridge
+	lasso
+)
+
+// Linear regression of float64 labels
+type LinearRegression struct {
+	method    method
+	alpha     float64
+	maxIter   int
+	tol       float64
+	coef      []float64
+	intercept float64
+	r2        float64
+	stdErr    []float64
+}
+
+// Create ordinary least squares regression
+func NewOLS() *LinearRegression {
+	return &LinearRegression{method: ols}
+}
+
+// Create Ridge regression minimizing ||y - Xw||^2 + alpha * ||w||^2
+func NewRidge(alpha float64) *LinearRegression {
+	if alpha < 0 {
+		panic(ErrAlphaNotValid)
+	}
+	return &LinearRegression{method: ridge, alpha: alpha}
+}
+
+// Create Lasso regression minimizing ||y - Xw||^2 / (2n) + alpha * |w| by coordinate descent
+//
+// maxIter is 1000 and tol is 1e-6 if they are zero
+func NewLasso(alpha float64, maxIter int, tol float64) *LinearRegression {
+	if alpha < 0 {
+		panic(ErrAlphaNotValid)
+	}
+	if maxIter <= 0 {
+		maxIter = 1000
+	}
+	if tol <= 0 {
+		tol = 1e-6
+	}
+	return &LinearRegression{method: lasso, alpha: alpha, maxIter: maxIter, tol: tol}
+}
+
+// Fit coefficients and intercept
+//
+// It returns ErrSingular of linalg if features are collinear in ordinary least squares
+func (lr *LinearRegression) Fit(data []knn.DataPoint) error {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	dim := len(data[0].Point())
+	y := make([]float64, len(data))
+	for i, dp := range data {
+		if len(dp.Point()) != dim {
+			panic(ErrDimensionMismatch)
+		}
+		v, ok := dp.Label().(float64)
+		if !ok {
+			panic(ErrLabelNotNumeric)
+		}
+		y[i] = v
+	}
+	lr.stdErr = nil
+	var err error
+	if lr.method == lasso {
+		lr.fitLasso(data, y, dim)
+	} else {
+		err = lr.fitClosed(data, y, dim)
+	}
+	if err != nil {
+		lr.coef = nil
+		return err
+	}
+	// coefficient of determination on training data
+	mean := 0.0
+	for _, v := range y {
+		mean += v
+	}
+	mean /= float64(len(y))
+	rss, tss := 0.0, 0.0
+	for i, dp := range data {
+		dif := y[i] - lr.predict(dp.Point())
+		rss += dif * dif
+		tss += (y[i] - mean) * (y[i] - mean)
+	}
+	if tss == 0 {
+		lr.r2 = 1
+	} else {
+		lr.r2 = 1 - rss/tss
+	}
+	if lr.method != lasso && len(data) > dim+1 {
+		lr.standardErrors(data, rss/float64(len(data)-dim-1))
+	}
+	return nil
+}
+
+// normal matrix A^T A + alpha * I with A = [X 1], the intercept is not penalized
+func (lr *LinearRegression) normal(data []knn.DataPoint, dim int, alpha float64) *linalg.Matrix {
+	cols := dim + 1
+	m := linalg.NewMatrix(cols, cols, nil)
+	row := make([]float64, cols)
+	for _, dp := range data {
+		copy(row, dp.Point())
+		row[dim] = 1
+		for i := 0; i < cols; i++ {
+			for j := 0; j <= i; j++ {
+				m.Set(i, j, m.At(i, j)+row[i]*row[j])
+			}
+		}
+	}
+	for i := 0; i < cols; i++ {
+		for j := 0; j < i; j++ {
+			m.Set(j, i, m.At(i, j))
+		}
+	}
+	for i := 0; i < dim; i++ {
+		m.Set(i, i, m.At(i, i)+alpha)
+	}
+	return m
+}
+
+func (lr *LinearRegression) fitClosed(data []knn.DataPoint, y []float64, dim int) error {
+	alpha := 0.0
+	if lr.method == ridge {
+		alpha = lr.alpha
+	}
+	m := lr.normal(data, dim, alpha)
+	b := make([]float64, dim+1)
+	for i, dp := range data {
+		for d, v := range dp.Point() {
+			b[d] += v * y[i]
+		}
+		b[dim] += y[i]
+	}
+	var beta []float64
+	if l, err := m.Cholesky(); err == nil {
+		beta = linalg.CholeskySolve(l, b)
+	} else if beta, err = m.Solve(b); err != nil {
+		return err
+	}
+	lr.coef, lr.intercept = beta[:dim], beta[dim]
+	return nil
+}
+
+// standard errors from covariance sigma2 * M^-1 * A^T A * M^-1, it is sigma2 * M^-1 for least squares
+func (lr *LinearRegression) standardErrors(data []knn.DataPoint, sigma2 float64) {
+	dim := len(lr.coef)
+	alpha := 0.0
+	if lr.method == ridge {
+		alpha = lr.alpha
+	}
+	inv, err := lr.normal(data, dim, alpha).Inverse()
+	if err != nil {
+		return
+	}
+	cov := inv
+	if alpha != 0 {
+		cov = inv.Mul(lr.normal(data, dim, 0)).Mul(inv)
+	}
+	lr.stdErr = make([]float64, dim+1)
+	for i := range lr.stdErr {
+		lr.stdErr[i] = math.Sqrt(sigma2 * cov.At(i, i))
+	}
+}
+
+func softThreshold(x, t float64) float64 {
+	switch {
+	case x > t:
+		return x - t
+	case x < -t:
+		return x + t
+	}
+	return 0
+}
+
+func (lr *LinearRegression) fitLasso(data []knn.DataPoint, y []float64, dim int) {
+	n := float64(len(data))
+	// center features and labels so the intercept is not penalized
+	means := make([]float64, dim)
+	yMean := 0.0
+	for i, dp := range data {
+		for d, v := range dp.Point() {
+			means[d] += v
+		}
+		yMean += y[i]
+	}
+	for d := range means {
+		means[d] /= n
+	}
+	yMean /= n
+	// features by column
+	x := make([][]float64, dim)
+	norms := make([]float64, dim)
+	for d := range x {
+		x[d] = make([]float64, len(data))
+		for i, dp := range data {
+			x[d][i] = dp.Point()[d] - means[d]
+			norms[d] += x[d][i] * x[d][i]
+		}
+	}
+	residual := make([]float64, len(data))
+	for i := range residual {
+		residual[i] = y[i] - yMean
+	}
+	coef := make([]float64, dim)
+	for iter := 0; iter < lr.maxIter; iter++ {
+		greatest := 0.0
+		for d := range coef {
+			if norms[d] == 0 {
+				continue
+			}
+			// correlation of feature with partial residual
+			rho := 0.0
+			for i, v := range x[d] {
+				rho += v * (residual[i] + v*coef[d])
+			}
+			w := softThreshold(rho/n, lr.alpha) / (norms[d] / n)
+			if delta := w - coef[d]; delta != 0 {
+				for i, v := range x[d] {
+					residual[i] -= v * delta
+				}
+				greatest = math.Max(greatest, math.Abs(delta))
+				coef[d] = w
+			}
+		}
+		if greatest < lr.tol {
+			break
+		}
+	}
+	lr.coef = coef
+	lr.intercept = yMean
+	for d, w := range coef {
+		lr.intercept -= w * means[d]
+	}
+}
+
+func (lr *LinearRegression) predict(point knn.Point) float64 {
+	sum := lr.intercept
+	for d, v := range point {
+		sum += lr.coef[d] * v
+	}
+	return sum
+}
+
+// Predict label of point
+func (lr *LinearRegression) Predict(point knn.Point) float64 {
+	if lr.coef == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != len(lr.coef) {
+		panic(ErrDimensionMismatch)
+	}
+	return lr.predict(point)
+}
+
+// Coefficients of features
+func (lr *LinearRegression) Coef() []float64 {
+	if lr.coef == nil {
+		panic(ErrNotFitted)
+	}
+	return append([]float64(nil), lr.coef...)
+}
+
+// Intercept of regression
+func (lr *LinearRegression) Intercept() float64 {
+	if lr.coef == nil {
+		panic(ErrNotFitted)
+	}
+	return lr.intercept
+}
+
+// Coefficient of determination on training data
+func (lr *LinearRegression) R2() float64 {
+	if lr.coef == nil {
+		panic(ErrNotFitted)
+	}
+	return lr.r2
+}
+
+// Standard errors of coefficients followed by the intercept one
+//
+// It is nil for Lasso and when there are not more data points than parameters
+func (lr *LinearRegression) StdErr() []float64 {
+	if lr.coef == nil {
+		panic(ErrNotFitted)
+	}
+	return lr.stdErr
+}
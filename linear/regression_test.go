@@ -0,0 +1,110 @@
+package linear
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+// y = 3 x0 - 2 x1 + 0 x2 + 5 with gaussian noise
+func linearData(n int, noise float64, seed int64) []knn.DataPoint {
+	rnd := rand.New(rand.NewSource(seed))
+	data := make([]knn.DataPoint, n)
+	for i := range data {
+		x := knn.Point{rnd.NormFloat64(), rnd.NormFloat64(), rnd.NormFloat64()}
+		data[i] = knn.NewDataPoint(3*x[0]-2*x[1]+5+noise*rnd.NormFloat64(), x)
+	}
+	return data
+}
+
+func TestOLS(t *testing.T) {
+	data := []knn.DataPoint{
+		knn.NewDataPoint(1.0, knn.Point{0}),
+		knn.NewDataPoint(3.0, knn.Point{1}),
+		knn.NewDataPoint(2.0, knn.Point{2}),
+		knn.NewDataPoint(4.0, knn.Point{3}),
+	}
+	ols := NewOLS()
+	if err := ols.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	// slope 0.8, intercept 1.3, residuals -0.3 0.9 -0.9 0.3, sigma2 = 1.8/2
+	if math.Abs(ols.Coef()[0]-0.8) > 1e-12 || math.Abs(ols.Intercept()-1.3) > 1e-12 {
+		t.Errorf("OLS failed. Expected 0.8x + 1.3, but got %vx + %v", ols.Coef()[0], ols.Intercept())
+	}
+	if math.Abs(ols.R2()-0.64) > 1e-12 {
+		t.Errorf("R2 failed. Expected 0.64, but got %v", ols.R2())
+	}
+	se := ols.StdErr()
+	if math.Abs(se[0]-math.Sqrt(0.9/5)) > 1e-12 || math.Abs(se[1]-math.Sqrt(0.9*14/20)) > 1e-12 {
+		t.Errorf("StdErr failed. Unexpected standard errors %v", se)
+	}
+	if got := ols.Predict(knn.Point{10}); math.Abs(got-9.3) > 1e-12 {
+		t.Errorf("Predict failed. Expected 9.3, but got %v", got)
+	}
+}
+
+func TestCollinear(t *testing.T) {
+	data := []knn.DataPoint{
+		knn.NewDataPoint(1.0, knn.Point{1, 2}),
+		knn.NewDataPoint(2.0, knn.Point{2, 4}),
+		knn.NewDataPoint(3.0, knn.Point{3, 6}),
+	}
+	if err := NewOLS().Fit(data); err != linalg.ErrSingular {
+		t.Errorf("OLS failed. Expected ErrSingular, but got %v", err)
+	}
+	// ridge penalty makes the problem well posed
+	if err := NewRidge(0.1).Fit(data); err != nil {
+		t.Errorf("Ridge failed. Unexpected error %v", err)
+	}
+}
+
+func TestRidge(t *testing.T) {
+	data := linearData(200, 0.1, 1)
+	plain, ridged := NewRidge(0), NewRidge(100)
+	plain.Fit(data)
+	ridged.Fit(data)
+	norm := func(w []float64) float64 {
+		sum := 0.0
+		for _, v := range w {
+			sum += v * v
+		}
+		return sum
+	}
+	if norm(ridged.Coef()) >= norm(plain.Coef()) {
+		t.Errorf("Ridge failed. Expected shrunk coefficients, but got %v", ridged.Coef())
+	}
+	if math.Abs(plain.Coef()[0]-3) > 0.05 || plain.R2() < 0.99 {
+		t.Errorf("Ridge failed. Unexpected fit %v with R2 %v", plain.Coef(), plain.R2())
+	}
+}
+
+func TestLasso(t *testing.T) {
+	data := linearData(200, 0.1, 2)
+	lasso := NewLasso(0.1, 0, 0)
+	if err := lasso.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	coef := lasso.Coef()
+	if coef[2] != 0 || math.Abs(coef[0]-2.9) > 0.1 || math.Abs(coef[1]+1.9) > 0.1 {
+		t.Errorf("Lasso failed. Expected about [2.9 -1.9 0], but got %v", coef)
+	}
+	if math.Abs(lasso.Intercept()-5) > 0.1 {
+		t.Errorf("Lasso failed. Expected intercept 5, but got %v", lasso.Intercept())
+	}
+	if lasso.StdErr() != nil {
+		t.Errorf("StdErr failed. Expected nil for Lasso")
+	}
+	// with zero alpha Lasso is least squares
+	ls, ols := NewLasso(0, 10000, 1e-12), NewOLS()
+	ls.Fit(data)
+	ols.Fit(data)
+	for d := range coef {
+		if math.Abs(ls.Coef()[d]-ols.Coef()[d]) > 1e-6 {
+			t.Errorf("Lasso failed. Expected OLS coefficients %v, but got %v", ols.Coef(), ls.Coef())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package manifold
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// blobs in 10 dimensions
+func blobs(n int, seed int64) ([]knn.Point, []any) {
+	centers := make([]knn.Point, 3)
+	for c := range centers {
+		centers[c] = make(knn.Point, 10)
+		centers[c][3*c] = 10
+	}
+	data := dataset.MakeBlobs(n, centers, 1, seed)
+	points := make([]knn.Point, len(data))
+	labels := make([]any, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+		labels[i] = dp.Label()
+	}
+	return points, labels
+}
+
+// fraction of embedded points whose nearest neighbor has the same label
+func neighborAgreement(embedding []knn.Point, labels []any) float64 {
+	dist := knn.NewEuclideanDist()
+	right := 0
+	for i, p := range embedding {
+		best, nearest := math.Inf(1), -1
+		for j, q := range embedding {
+			if i != j {
+				if d := dist.Eval(p, q); d < best {
+					best, nearest = d, j
+				}
+			}
+		}
+		if labels[nearest] == labels[i] {
+			right++
+		}
+	}
+	return float64(right) / float64(len(embedding))
+}
+
+func TestTSNE(t *testing.T) {
+	points, labels := blobs(120, 1)
+	embedding := TSNEPoints(points, knn.NewEuclideanDist(), TSNEConfig{Iterations: 400, Seed: 1})
+	if len(embedding) != len(points) || len(embedding[0]) != 2 {
+		t.Fatalf("TSNE failed. Unexpected embedding shape")
+	}
+	if agree := neighborAgreement(embedding, labels); agree < 0.95 {
+		t.Errorf("TSNE failed. Expected neighbor agreement greater than 0.95, but got %v", agree)
+	}
+}
+
+func TestUMAP(t *testing.T) {
+	points, labels := blobs(150, 2)
+	embedding := UMAPPoints(points, knn.NewEuclideanDist(), UMAPConfig{Dims: 3, Seed: 2})
+	if len(embedding[0]) != 3 {
+		t.Fatalf("UMAP failed. Expected 3 dimensions, but got %d", len(embedding[0]))
+	}
+	if agree := neighborAgreement(embedding, labels); agree < 0.95 {
+		t.Errorf("UMAP failed. Expected neighbor agreement greater than 0.95, but got %v", agree)
+	}
+}
+
+func TestCurve(t *testing.T) {
+	// reference values of the UMAP curve for min_dist 0.1
+	a, b := curve(0.1)
+	if math.Abs(a-1.577) > 0.05 || math.Abs(b-0.895) > 0.02 {
+		t.Errorf("curve failed. Expected (1.577, 0.895), but got (%v, %v)", a, b)
+	}
+}
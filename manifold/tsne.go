@@ -0,0 +1,209 @@
+// Package manifold implements nonlinear embeddings of datasets in few dimensions for visualization
+package manifold
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrMatrixNotSquare = errors.New("distance matrix is not square")
+	ErrTooFewPoints    = errors.New("there are too few points to embed")
+	ErrParamNotValid   = errors.New("embedding parameter is not valid")
+)
+
+// Configuration of t-SNE, zero fields take the default values
+type TSNEConfig struct {
+	Dims              int     //dimension of embedding, 2 by default
+	Perplexity        float64 //effective number of neighbors, 30 by default
+	Iterations        int     //1000 by default
+	LearningRate      float64 //200 by default
+	EarlyExaggeration float64 //multiplier of affinities in the first 250 iterations, 12 by default
+	Seed              int64
+}
+
+func (config *TSNEConfig) defaults(n int) {
+	if config.Dims == 0 {
+		config.Dims = 2
+	}
+	if config.Perplexity == 0 {
+		config.Perplexity = 30
+	}
+	// perplexity can't be greater than the number of neighbors
+	if config.Perplexity > float64(n-1)/3 {
+		config.Perplexity = math.Max(float64(n-1)/3, 1)
+	}
+	if config.Iterations == 0 {
+		config.Iterations = 1000
+	}
+	if config.LearningRate == 0 {
+		config.LearningRate = 200
+	}
+	if config.EarlyExaggeration == 0 {
+		config.EarlyExaggeration = 12
+	}
+	if config.Dims < 1 || config.Perplexity < 1 || config.Iterations < 1 || config.LearningRate < 0 {
+		panic(ErrParamNotValid)
+	}
+}
+
+// read a distance matrix of shape (n, n)
+func squareMatrix(distances *graph.Tensor) [][]float64 {
+	shape := distances.Shape()
+	if len(shape) != 2 || shape[0] != shape[1] {
+		panic(ErrMatrixNotSquare)
+	}
+	n := shape[0]
+	out := make([][]float64, n)
+	for i := range out {
+		out[i] = make([]float64, n)
+		for j := range out[i] {
+			out[i][j] = distances.GetF64At([]int{i, j})
+		}
+	}
+	return out
+}
+
+// joint affinities of points with the precision of every row searched to match perplexity
+func affinities(dist [][]float64, perplexity float64) [][]float64 {
+	n := len(dist)
+	target := math.Log(perplexity)
+	p := make([][]float64, n)
+	for i := range p {
+		p[i] = make([]float64, n)
+		lo, hi, beta := 0.0, math.Inf(1), 1.0
+		for step := 0; step < 100; step++ {
+			// entropy of conditional distribution of row i
+			sum, weighted := 0.0, 0.0
+			for j, d := range dist[i] {
+				if j == i {
+					p[i][j] = 0
+					continue
+				}
+				p[i][j] = math.Exp(-beta * d * d)
+				sum += p[i][j]
+				weighted += d * d * p[i][j]
+			}
+			if sum == 0 {
+				// precision is too high for every neighbor
+				hi = beta
+				beta = (lo + hi) / 2
+				continue
+			}
+			entropy := math.Log(sum) + beta*weighted/sum
+			for j := range p[i] {
+				p[i][j] /= sum
+			}
+			if math.Abs(entropy-target) < 1e-5 {
+				break
+			}
+			if entropy > target {
+				lo = beta
+				if math.IsInf(hi, 1) {
+					beta *= 2
+				} else {
+					beta = (lo + hi) / 2
+				}
+			} else {
+				hi = beta
+				beta = (lo + hi) / 2
+			}
+		}
+	}
+	// symmetric joint probabilities
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			v := math.Max((p[i][j]+p[j][i])/float64(2*n), 1e-12)
+			p[i][j], p[j][i] = v, v
+		}
+	}
+	return p
+}
+
+// Embed a distance matrix of shape (n, n) with exact t-SNE, it takes O(n^2) time by iteration
+func TSNE(distances *graph.Tensor, config TSNEConfig) []knn.Point {
+	dist := squareMatrix(distances)
+	n := len(dist)
+	if n < 2 {
+		panic(ErrTooFewPoints)
+	}
+	config.defaults(n)
+	p := affinities(dist, config.Perplexity)
+	rnd := rand.New(rand.NewSource(config.Seed))
+	dims := config.Dims
+	y := make([]knn.Point, n)
+	update := make([][]float64, n)
+	gains := make([][]float64, n)
+	grad := make([][]float64, n)
+	for i := range y {
+		y[i] = make(knn.Point, dims)
+		update[i] = make([]float64, dims)
+		gains[i] = make([]float64, dims)
+		grad[i] = make([]float64, dims)
+		for d := range y[i] {
+			y[i][d] = 1e-4 * rnd.NormFloat64()
+			gains[i][d] = 1
+		}
+	}
+	num := make([][]float64, n)
+	for i := range num {
+		num[i] = make([]float64, n)
+	}
+	const exaggerationIters = 250
+	for iter := 0; iter < config.Iterations; iter++ {
+		exaggeration, momentum := 1.0, 0.8
+		if iter < exaggerationIters {
+			exaggeration, momentum = config.EarlyExaggeration, 0.5
+		}
+		// student-t kernel of embedding
+		sum := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				d := 0.0
+				for k := 0; k < dims; k++ {
+					dif := y[i][k] - y[j][k]
+					d += dif * dif
+				}
+				v := 1 / (1 + d)
+				num[i][j], num[j][i] = v, v
+				sum += 2 * v
+			}
+		}
+		for i := 0; i < n; i++ {
+			for k := range grad[i] {
+				grad[i][k] = 0
+			}
+			for j := 0; j < n; j++ {
+				if i == j {
+					continue
+				}
+				mult := 4 * (exaggeration*p[i][j] - num[i][j]/sum) * num[i][j]
+				for k := 0; k < dims; k++ {
+					grad[i][k] += mult * (y[i][k] - y[j][k])
+				}
+			}
+		}
+		for i := 0; i < n; i++ {
+			for k := 0; k < dims; k++ {
+				// gains grow when the direction of descent is kept
+				if (grad[i][k] > 0) != (update[i][k] > 0) {
+					gains[i][k] += 0.2
+				} else {
+					gains[i][k] = math.Max(gains[i][k]*0.8, 0.01)
+				}
+				update[i][k] = momentum*update[i][k] - config.LearningRate*gains[i][k]*grad[i][k]
+				y[i][k] += update[i][k]
+			}
+		}
+	}
+	return y
+}
+
+// Embed points with exact t-SNE of the distances between them
+func TSNEPoints(points []knn.Point, dist knn.Distance, config TSNEConfig) []knn.Point {
+	return TSNE(knn.DistanceMatrix(points, dist, 1), config)
+}
@@ -0,0 +1,232 @@
+package manifold
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Configuration of UMAP, zero fields take the default values
+type UMAPConfig struct {
+	Dims            int     //dimension of embedding, 2 by default
+	Neighbors       int     //neighbors of the k-NN graph built by UMAPPoints, 15 by default
+	MinDist         float64 //least distance between embedded points, 0.1 by default
+	Epochs          int     //200 by default
+	NegativeSamples int     //repulsive samples by attractive step, 5 by default
+	Seed            int64
+}
+
+func (config *UMAPConfig) defaults() {
+	if config.Dims == 0 {
+		config.Dims = 2
+	}
+	if config.Neighbors == 0 {
+		config.Neighbors = 15
+	}
+	if config.MinDist == 0 {
+		config.MinDist = 0.1
+	}
+	if config.Epochs == 0 {
+		config.Epochs = 200
+	}
+	if config.NegativeSamples == 0 {
+		config.NegativeSamples = 5
+	}
+	if config.Dims < 1 || config.Neighbors < 1 || config.MinDist < 0 || config.Epochs < 1 || config.NegativeSamples < 0 {
+		panic(ErrParamNotValid)
+	}
+}
+
+// parameters a and b of the curve 1 / (1 + a * x^(2b)) nearest to 1 below minDist and exp(minDist - x) above it
+func curve(minDist float64) (float64, float64) {
+	xs := make([]float64, 300)
+	ys := make([]float64, len(xs))
+	for i := range xs {
+		xs[i] = 3 * float64(i+1) / float64(len(xs))
+		if xs[i] < minDist {
+			ys[i] = 1
+		} else {
+			ys[i] = math.Exp(minDist - xs[i])
+		}
+	}
+	loss := func(a, b float64) float64 {
+		sum := 0.0
+		for i, x := range xs {
+			dif := 1/(1+a*math.Pow(x, 2*b)) - ys[i]
+			sum += dif * dif
+		}
+		return sum
+	}
+	// grid search refined around the best cell
+	bestA, bestB := 1.0, 1.0
+	stepA, stepB := 0.5, 0.1
+	for round := 0; round < 4; round++ {
+		ca, cb := bestA, bestB
+		best := loss(ca, cb)
+		for i := -10; i <= 10; i++ {
+			for j := -10; j <= 10; j++ {
+				a, b := ca+float64(i)*stepA, cb+float64(j)*stepB
+				if a <= 0 || b <= 0 {
+					continue
+				}
+				if l := loss(a, b); l < best {
+					best, bestA, bestB = l, a, b
+				}
+			}
+		}
+		stepA /= 10
+		stepB /= 10
+	}
+	return bestA, bestB
+}
+
+type edge struct {
+	src, dst int
+	weight   float64
+}
+
+// fuzzy union of the local fuzzy sets of neighbors of every node
+func fuzzyEdges(g *graph.Graph) []edge {
+	n := g.LenNodes()
+	weights := make([]map[int]float64, n)
+	for i := 0; i < n; i++ {
+		weights[i] = make(map[int]float64)
+		neighbors := g.OutEdges(i)
+		if len(neighbors) == 0 {
+			continue
+		}
+		dists := make([]float64, len(neighbors))
+		rho := math.Inf(1)
+		for k, j := range neighbors {
+			dists[k], _ = g.EdgeWeight(i, j)
+			if dists[k] > 0 {
+				rho = math.Min(rho, dists[k])
+			}
+		}
+		if math.IsInf(rho, 1) {
+			rho = 0
+		}
+		// bandwidth with sum of memberships log2(k)
+		target := math.Log2(float64(len(neighbors)))
+		lo, hi, sigma := 0.0, math.Inf(1), 1.0
+		for step := 0; step < 64; step++ {
+			sum := 0.0
+			for _, d := range dists {
+				sum += math.Exp(-math.Max(d-rho, 0) / sigma)
+			}
+			if math.Abs(sum-target) < 1e-5 {
+				break
+			}
+			if sum > target {
+				hi = sigma
+				sigma = (lo + hi) / 2
+			} else {
+				lo = sigma
+				if math.IsInf(hi, 1) {
+					sigma *= 2
+				} else {
+					sigma = (lo + hi) / 2
+				}
+			}
+		}
+		for k, j := range neighbors {
+			weights[i][j] = math.Exp(-math.Max(dists[k]-rho, 0) / sigma)
+		}
+	}
+	edges := make([]edge, 0, n)
+	for i := 0; i < n; i++ {
+		for j, a := range weights[i] {
+			b, ok := weights[j][i]
+			if ok && j < i {
+				continue //added from j
+			}
+			edges = append(edges, edge{src: i, dst: j, weight: a + b - a*b})
+		}
+	}
+	return edges
+}
+
+func clip(v float64) float64 {
+	return math.Max(-4, math.Min(4, v))
+}
+
+// Embed the nodes of a k-NN graph, like the one of knn.BuildKNNGraph with knn.DistanceWeight, with UMAP-style stochastic layout
+//
+// Edge weights of the graph are the distances to neighbors, point i of the embedding is node i
+func UMAP(g *graph.Graph, config UMAPConfig) []knn.Point {
+	n := g.LenNodes()
+	if n < 2 {
+		panic(ErrTooFewPoints)
+	}
+	config.defaults()
+	a, b := curve(config.MinDist)
+	edges := fuzzyEdges(g)
+	greatest := 0.0
+	for _, e := range edges {
+		greatest = math.Max(greatest, e.weight)
+	}
+	rnd := rand.New(rand.NewSource(config.Seed))
+	dims := config.Dims
+	y := make([]knn.Point, n)
+	for i := range y {
+		y[i] = make(knn.Point, dims)
+		for d := range y[i] {
+			y[i][d] = 20*rnd.Float64() - 10
+		}
+	}
+	sqDist := func(i, j int) float64 {
+		sum := 0.0
+		for d := 0; d < dims; d++ {
+			dif := y[i][d] - y[j][d]
+			sum += dif * dif
+		}
+		return sum
+	}
+	for epoch := 0; epoch < config.Epochs; epoch++ {
+		rate := 1 - float64(epoch)/float64(config.Epochs)
+		for _, e := range edges {
+			// edges are sampled with probability proportional to their weight
+			if rnd.Float64()*greatest > e.weight {
+				continue
+			}
+			i, j := e.src, e.dst
+			if d := sqDist(i, j); d > 0 {
+				coef := -2 * a * b * math.Pow(d, b-1) / (1 + a*math.Pow(d, b))
+				for k := 0; k < dims; k++ {
+					step := rate * clip(coef*(y[i][k]-y[j][k]))
+					y[i][k] += step
+					y[j][k] -= step
+				}
+			}
+			for s := 0; s < config.NegativeSamples; s++ {
+				other := rnd.Intn(n)
+				if other == i {
+					continue
+				}
+				d := sqDist(i, other)
+				coef := 2 * b / ((0.001 + d) * (1 + a*math.Pow(d, b)))
+				for k := 0; k < dims; k++ {
+					if d > 0 {
+						y[i][k] += rate * clip(coef*(y[i][k]-y[other][k]))
+					} else {
+						y[i][k] += rate * 4
+					}
+				}
+			}
+		}
+	}
+	return y
+}
+
+// Embed points with UMAP-style layout of the graph of their nearest neighbors
+func UMAPPoints(points []knn.Point, dist knn.Distance, config UMAPConfig) []knn.Point {
+	config.defaults()
+	k := config.Neighbors
+	if k >= len(points) {
+		k = len(points) - 1
+	}
+	g := knn.BuildKNNGraph(points, k, dist, knn.DistanceWeight)
+	return UMAP(&g, config)
+}
@@ -0,0 +1,170 @@
+// Package markov implements n-order Markov chains over sequences of tokens or states
+package markov
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+var (
+	ErrOrderNotValid = errors.New("order is not greater than zero")
+	ErrAlphaNotValid = errors.New("smoothing alpha is lesser than zero")
+	ErrNotFitted     = errors.New("chain has no sequences")
+)
+
+// token of a sequence or boundary, the start padding before a sequence or the end after it
+type token[T comparable] struct {
+	value    T
+	boundary bool
+}
+
+// counts of tokens following a context
+type node[T comparable] struct {
+	children map[token[T]]*node[T]
+	counts   map[token[T]]float64
+	total    float64
+}
+
+func newNode[T comparable]() *node[T] {
+	return &node[T]{children: make(map[token[T]]*node[T]), counts: make(map[token[T]]float64)}
+}
+
+// N-order Markov chain, every token depends on the order tokens before it
+type Chain[T comparable] struct {
+	order int
+	alpha float64
+	root  *node[T]
+	vocab []T
+	seen  map[T]bool
+}
+
+// Create chain of given order with additive smoothing alpha, 1 is Laplace smoothing
+func NewChain[T comparable](order int, alpha float64) *Chain[T] {
+	if order < 1 {
+		panic(ErrOrderNotValid)
+	}
+	if alpha < 0 {
+		panic(ErrAlphaNotValid)
+	}
+	return &Chain[T]{order: order, alpha: alpha, root: newNode[T](), seen: make(map[T]bool)}
+}
+
+// context of order tokens before position i of a padded sequence
+func (ch *Chain[T]) context(seq []T, i int) []token[T] {
+	ctx := make([]token[T], ch.order)
+	for k := range ctx {
+		j := i - ch.order + k
+		if j >= 0 {
+			ctx[k] = token[T]{value: seq[j]}
+		} else {
+			ctx[k] = token[T]{boundary: true}
+		}
+	}
+	return ctx
+}
+
+// Count transitions of sequences, it can be called again to add more sequences
+func (ch *Chain[T]) Fit(sequences [][]T) {
+	for _, seq := range sequences {
+		for i := 0; i <= len(seq); i++ {
+			n := ch.root
+			for _, t := range ch.context(seq, i) {
+				child, ok := n.children[t]
+				if !ok {
+					child = newNode[T]()
+					n.children[t] = child
+				}
+				n = child
+			}
+			next := token[T]{boundary: true}
+			if i < len(seq) {
+				next = token[T]{value: seq[i]}
+				if !ch.seen[seq[i]] {
+					ch.seen[seq[i]] = true
+					ch.vocab = append(ch.vocab, seq[i])
+				}
+			}
+			n.counts[next]++
+			n.total++
+		}
+	}
+}
+
+// Tokens seen by the chain in order of appearance
+func (ch *Chain[T]) Vocabulary() []T {
+	return ch.vocab
+}
+
+func (ch *Chain[T]) find(ctx []token[T]) *node[T] {
+	n := ch.root
+	for _, t := range ctx {
+		if n = n.children[t]; n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// smoothed probability of next after context, outcomes are the vocabulary and the end of sequence
+func (ch *Chain[T]) prob(ctx []token[T], next token[T]) float64 {
+	outcomes := float64(len(ch.vocab) + 1)
+	count, total := 0.0, 0.0
+	if n := ch.find(ctx); n != nil {
+		count, total = n.counts[next], n.total
+	}
+	if total+ch.alpha*outcomes == 0 {
+		return 0
+	}
+	return (count + ch.alpha) / (total + ch.alpha*outcomes)
+}
+
+// Probability of next token after history, only the last order tokens of history are used and
+// shorter histories are at the start of a sequence
+func (ch *Chain[T]) Prob(history []T, next T) float64 {
+	return ch.prob(ch.context(history, len(history)), token[T]{value: next})
+}
+
+// Probability that sequence ends after history
+func (ch *Chain[T]) EndProb(history []T) float64 {
+	return ch.prob(ch.context(history, len(history)), token[T]{boundary: true})
+}
+
+// Log probability of a whole sequence, including its end
+func (ch *Chain[T]) LogProb(seq []T) float64 {
+	sum := 0.0
+	for i := 0; i <= len(seq); i++ {
+		next := token[T]{boundary: true}
+		if i < len(seq) {
+			next = token[T]{value: seq[i]}
+		}
+		sum += math.Log(ch.prob(ch.context(seq, i), next))
+	}
+	return sum
+}
+
+// Sample a sequence until its end or maxLen tokens
+func (ch *Chain[T]) Sample(rnd *rand.Rand, maxLen int) []T {
+	if len(ch.vocab) == 0 {
+		panic(ErrNotFitted)
+	}
+	out := make([]T, 0, 16)
+	for len(out) < maxLen {
+		ctx := ch.context(out, len(out))
+		u := rnd.Float64()
+		chosen := -1
+		for i, v := range ch.vocab {
+			u -= ch.prob(ctx, token[T]{value: v})
+			if u < 0 {
+				chosen = i
+				break
+			}
+		}
+		if chosen == -1 {
+			// the rest of probability is the end of sequence
+			break
+		}
+		out = append(out, ch.vocab[chosen])
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package markov
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestProb(t *testing.T) {
+	ch := NewChain[string](1, 0)
+	ch.Fit([][]string{strings.Fields("a b a c"), strings.Fields("a b")})
+	// a is followed by b twice and c once
+	if got := ch.Prob([]string{"a"}, "b"); math.Abs(got-2.0/3) > 1e-12 {
+		t.Errorf("Prob failed. Expected 2/3, but got %v", got)
+	}
+	// every sequence starts with a
+	if got := ch.Prob(nil, "a"); got != 1 {
+		t.Errorf("Prob failed. Expected 1, but got %v", got)
+	}
+	if got := ch.EndProb([]string{"x", "b"}); got != 0.5 {
+		t.Errorf("EndProb failed. Expected 0.5, but got %v", got)
+	}
+	if got := ch.LogProb(strings.Fields("a b")); math.Abs(got-math.Log(2.0/3*0.5)) > 1e-12 {
+		t.Errorf("LogProb failed. Expected %v, but got %v", math.Log(2.0/3*0.5), got)
+	}
+}
+
+func TestLaplace(t *testing.T) {
+	ch := NewChain[rune](2, 1)
+	ch.Fit([][]rune{[]rune("abab")})
+	// vocabulary a and b and the end, context (a, b) was followed by a once
+	if got := ch.Prob([]rune("ab"), 'a'); math.Abs(got-2.0/5) > 1e-12 {
+		t.Errorf("Prob failed. Expected 2/5, but got %v", got)
+	}
+	// unseen context is uniform
+	if got := ch.Prob([]rune("bb"), 'a'); math.Abs(got-1.0/3) > 1e-12 {
+		t.Errorf("Prob failed. Expected 1/3, but got %v", got)
+	}
+	sum := ch.EndProb([]rune("ab"))
+	for _, v := range ch.Vocabulary() {
+		sum += ch.Prob([]rune("ab"), v)
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("Prob failed. Expected sum 1, but got %v", sum)
+	}
+}
+
+func TestSample(t *testing.T) {
+	ch := NewChain[string](1, 0)
+	ch.Fit([][]string{strings.Fields("the cat sat"), strings.Fields("the dog sat")})
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		seq := ch.Sample(rnd, 10)
+		if len(seq) != 3 || seq[0] != "the" || seq[2] != "sat" {
+			t.Fatalf("Sample failed. Unexpected sequence %v", seq)
+		}
+	}
+}
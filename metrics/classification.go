@@ -0,0 +1,246 @@
+// Package metrics scores predictions of classifiers and regressors
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrLengthMismatch = errors.New("expected and predicted values have different lengths")
+	ErrEmpty          = errors.New("there are no values to score")
+	ErrLabelNotFound  = errors.New("label is not in confusion matrix")
+)
+
+// Averaging of per label scores
+type Average int
+
+const (
+	Macro    Average = iota //unweighted mean of per label scores
+	Micro                   //score of global true positives, false positives and false negatives
+	Weighted                //mean of per label scores weighted by label support
+)
+
+func checkLabels(expected, predicted []any) {
+	if len(expected) != len(predicted) {
+		panic(ErrLengthMismatch)
+	}
+	if len(expected) == 0 {
+		panic(ErrEmpty)
+	}
+}
+
+// Confusion matrix of a classifier, Counts[i][j] is the number of samples with label Labels[i] predicted as Labels[j]
+type ConfusionMatrix struct {
+	Labels []any
+	Counts [][]int
+	index  map[any]int
+}
+
+// Create confusion matrix of expected and predicted labels, labels are ordered by first appearance
+func NewConfusionMatrix(expected, predicted []any) *ConfusionMatrix {
+	checkLabels(expected, predicted)
+	cm := &ConfusionMatrix{index: make(map[any]int)}
+	for _, ls := range [][]any{expected, predicted} {
+		for _, l := range ls {
+			if _, ok := cm.index[l]; !ok {
+				cm.index[l] = len(cm.Labels)
+				cm.Labels = append(cm.Labels, l)
+			}
+		}
+	}
+	cm.Counts = make([][]int, len(cm.Labels))
+	for i := range cm.Counts {
+		cm.Counts[i] = make([]int, len(cm.Labels))
+	}
+	for i := range expected {
+		cm.Counts[cm.index[expected[i]]][cm.index[predicted[i]]]++
+	}
+	return cm
+}
+
+func (cm *ConfusionMatrix) labelIndex(label any) int {
+	i, ok := cm.index[label]
+	if !ok {
+		panic(ErrLabelNotFound)
+	}
+	return i
+}
+
+// Total number of samples
+func (cm *ConfusionMatrix) Total() int {
+	total := 0
+	for _, row := range cm.Counts {
+		for _, c := range row {
+			total += c
+		}
+	}
+	return total
+}
+
+// true positives, false positives and false negatives of label at index i
+func (cm *ConfusionMatrix) counts(i int) (tp, fp, fn int) {
+	tp = cm.Counts[i][i]
+	for j := range cm.Labels {
+		if j != i {
+			fp += cm.Counts[j][i]
+			fn += cm.Counts[i][j]
+		}
+	}
+	return tp, fp, fn
+}
+
+// Number of samples with label
+func (cm *ConfusionMatrix) Support(label any) int {
+	support := 0
+	for _, c := range cm.Counts[cm.labelIndex(label)] {
+		support += c
+	}
+	return support
+}
+
+// Fraction of samples predicted right
+func (cm *ConfusionMatrix) Accuracy() float64 {
+	right := 0
+	for i := range cm.Labels {
+		right += cm.Counts[i][i]
+	}
+	return float64(right) / float64(cm.Total())
+}
+
+func ratio(num, den int) float64 {
+	if den == 0 {
+		return 0
+	}
+	return float64(num) / float64(den)
+}
+
+func f1(precision, recall float64) float64 {
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
+
+// Precision of label, zero when label is never predicted
+func (cm *ConfusionMatrix) Precision(label any) float64 {
+	tp, fp, _ := cm.counts(cm.labelIndex(label))
+	return ratio(tp, tp+fp)
+}
+
+// Recall of label, zero when label is never expected
+func (cm *ConfusionMatrix) Recall(label any) float64 {
+	tp, _, fn := cm.counts(cm.labelIndex(label))
+	return ratio(tp, tp+fn)
+}
+
+// F1 score of label
+func (cm *ConfusionMatrix) F1(label any) float64 {
+	return f1(cm.Precision(label), cm.Recall(label))
+}
+
+// average per label score or compute score from global counts
+func (cm *ConfusionMatrix) average(avg Average, score func(label any) float64, global func(tp, fp, fn int) float64) float64 {
+	switch avg {
+	case Micro:
+		tp, fp, fn := 0, 0, 0
+		for i := range cm.Labels {
+			t, p, n := cm.counts(i)
+			tp, fp, fn = tp+t, fp+p, fn+n
+		}
+		return global(tp, fp, fn)
+	case Weighted:
+		sum := 0.0
+		for _, l := range cm.Labels {
+			sum += score(l) * float64(cm.Support(l))
+		}
+		return sum / float64(cm.Total())
+	}
+	sum := 0.0
+	for _, l := range cm.Labels {
+		sum += score(l)
+	}
+	return sum / float64(len(cm.Labels))
+}
+
+// Averaged precision of every label
+func (cm *ConfusionMatrix) AvgPrecision(avg Average) float64 {
+	return cm.average(avg, cm.Precision, func(tp, fp, fn int) float64 {
+		return ratio(tp, tp+fp)
+	})
+}
+
+// Averaged recall of every label
+func (cm *ConfusionMatrix) AvgRecall(avg Average) float64 {
+	return cm.average(avg, cm.Recall, func(tp, fp, fn int) float64 {
+		return ratio(tp, tp+fn)
+	})
+}
+
+// Averaged F1 score of every label
+func (cm *ConfusionMatrix) AvgF1(avg Average) float64 {
+	return cm.average(avg, cm.F1, func(tp, fp, fn int) float64 {
+		return f1(ratio(tp, tp+fp), ratio(tp, tp+fn))
+	})
+}
+
+// Table of counts with expected labels in rows and predicted labels in columns
+func (cm *ConfusionMatrix) String() string {
+	cells := make([][]string, len(cm.Labels)+1)
+	cells[0] = append(cells[0], "")
+	for _, l := range cm.Labels {
+		cells[0] = append(cells[0], fmt.Sprint(l))
+	}
+	for i, l := range cm.Labels {
+		cells[i+1] = append(cells[i+1], fmt.Sprint(l))
+		for _, c := range cm.Counts[i] {
+			cells[i+1] = append(cells[i+1], fmt.Sprint(c))
+		}
+	}
+	width := 0
+	for _, row := range cells {
+		for _, c := range row {
+			if len(c) > width {
+				width = len(c)
+			}
+		}
+	}
+	var sb strings.Builder
+	for _, row := range cells {
+		for j, c := range row {
+			if j > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(strings.Repeat(" ", width-len(c)))
+			sb.WriteString(c)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Fraction of labels predicted right
+func Accuracy(expected, predicted []any) float64 {
+	return NewConfusionMatrix(expected, predicted).Accuracy()
+}
+
+// Averaged precision of predicted labels
+func Precision(expected, predicted []any, avg Average) float64 {
+	return NewConfusionMatrix(expected, predicted).AvgPrecision(avg)
+}
+
+// Averaged recall of predicted labels
+func Recall(expected, predicted []any, avg Average) float64 {
+	return NewConfusionMatrix(expected, predicted).AvgRecall(avg)
+}
+
+// Averaged F1 score of predicted labels
+func F1(expected, predicted []any, avg Average) float64 {
+	return NewConfusionMatrix(expected, predicted).AvgF1(avg)
+}
+
+// F1 score with macro averaging, it can be used as a score function of model selection
+func MacroF1(expected, predicted []any) float64 {
+	return F1(expected, predicted, Macro)
+}
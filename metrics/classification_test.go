@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+var (
+	expected  = []any{"cat", "cat", "cat", "dog", "dog", "bird"}
+	predicted = []any{"cat", "cat", "dog", "dog", "cat", "bird"}
+)
+
+func TestConfusionMatrix(t *testing.T) {
+	cm := NewConfusionMatrix(expected, predicted)
+	if cm.Counts[0][0] != 2 || cm.Counts[0][1] != 1 || cm.Counts[1][0] != 1 || cm.Counts[2][2] != 1 {
+		t.Errorf("NewConfusionMatrix failed. Unexpected counts %v", cm.Counts)
+	}
+	if p := cm.Precision("cat"); !near(p, 2.0/3) {
+		t.Errorf("Precision failed. Expected %v, but got %v", 2.0/3, p)
+	}
+	if r := cm.Recall("dog"); !near(r, 0.5) {
+		t.Errorf("Recall failed. Expected 0.5, but got %v", r)
+	}
+	s := cm.String()
+	if s != "      cat  dog bird\n cat    2    1    0\n dog    1    1    0\nbird    0    0    1\n" {
+		t.Errorf("String failed. Unexpected table\n%s", s)
+	}
+}
+
+func TestAverages(t *testing.T) {
+	if a := Accuracy(expected, predicted); !near(a, 4.0/6) {
+		t.Errorf("Accuracy failed. Expected %v, but got %v", 4.0/6, a)
+	}
+	// micro averaged scores of single label classification are the accuracy
+	if f := F1(expected, predicted, Micro); !near(f, 4.0/6) {
+		t.Errorf("F1 failed. Expected micro %v, but got %v", 4.0/6, f)
+	}
+	macro := (2.0/3 + 0.5 + 1) / 3
+	if p := Precision(expected, predicted, Macro); !near(p, macro) {
+		t.Errorf("Precision failed. Expected macro %v, but got %v", macro, p)
+	}
+	weighted := (2.0/3*3 + 0.5*2 + 1) / 6
+	if r := Recall(expected, predicted, Weighted); !near(r, weighted) {
+		t.Errorf("Recall failed. Expected weighted %v, but got %v", weighted, r)
+	}
+	macroF1 := (2.0/3 + 0.5 + 1) / 3
+	if f := MacroF1(expected, predicted); !near(f, macroF1) {
+		t.Errorf("MacroF1 failed. Expected %v, but got %v", macroF1, f)
+	}
+}
@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var ErrTooFewClusters = errors.New("score needs at least two clusters")
+
+// labels renumbered from zero in order of appearance and number of clusters, negative labels are noise,
+// they become -1 and they are not counted
+func remapClusters(labels []int) ([]int, int) {
+	ids := make(map[int]int)
+	out := make([]int, len(labels))
+	for i, l := range labels {
+		if l < 0 {
+			out[i] = -1
+			continue
+		}
+		id, ok := ids[l]
+		if !ok {
+			id = len(ids)
+			ids[l] = id
+		}
+		out[i] = id
+	}
+	return out, len(ids)
+}
+
+// Silhouette coefficient of every point, points with negative labels are noise and they get zero.
+// Labels need not be contiguous, it panics with ErrTooFewClusters if there are not two clusters besides noise
+//
+// It is (b - a) / max(a, b) where a is the mean distance to points of its cluster and b the lesser mean distance
+// to points of another cluster, points are scored by lv goroutines
+func SilhouetteSamples(points []knn.Point, labels []int, dist knn.Distance, lv int) []float64 {
+	if len(points) != len(labels) {
+		panic(ErrLengthMismatch)
+	}
+	labels, k := remapClusters(labels)
+	if k < 2 {
+		panic(ErrTooFewClusters)
+	}
+	sizes := make([]int, k)
+	for _, l := range labels {
+		if l >= 0 {
+			sizes[l]++
+		}
+	}
+	scores := make([]float64, len(points))
+	if lv < 1 {
+		lv = 1
+	}
+	wg := sync.WaitGroup{}
+	for w := 0; w < lv; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			sums := make([]float64, k)
+			for i := w; i < len(points); i += lv {
+				li := labels[i]
+				if li < 0 || sizes[li] < 2 {
+					// noise and single point clusters score zero
+					continue
+				}
+				for c := range sums {
+					sums[c] = 0
+				}
+				for j, p := range points {
+					if j != i && labels[j] >= 0 {
+						sums[labels[j]] += dist.Eval(points[i], p)
+					}
+				}
+				a := sums[li] / float64(sizes[li]-1)
+				b := math.Inf(1)
+				for c, s := range sums {
+					if c != li {
+						b = math.Min(b, s/float64(sizes[c]))
+					}
+				}
+				if m := math.Max(a, b); m > 0 {
+					scores[i] = (b - a) / m
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	return scores
+}
+
+// Mean silhouette coefficient of points that are not noise, from -1 to 1 and greater is better
+func Silhouette(points []knn.Point, labels []int, dist knn.Distance, lv int) float64 {
+	scores := SilhouetteSamples(points, labels, dist, lv)
+	sum, count := 0.0, 0
+	for i, s := range scores {
+		if labels[i] >= 0 {
+			sum += s
+			count++
+		}
+	}
+	return sum / float64(count)
+}
+
+// Davies-Bouldin index with euclidean distance, lesser is better and noise is ignored.
+// Labels need not be contiguous, it panics with ErrTooFewClusters if there are not two clusters besides noise
+//
+// It is the mean over clusters of the greatest ratio of scatter sum to centroid distance with another cluster,
+// clusters with the same centroid are not compared
+func DaviesBouldin(points []knn.Point, labels []int) float64 {
+	if len(points) != len(labels) {
+		panic(ErrLengthMismatch)
+	}
+	labels, k := remapClusters(labels)
+	if k < 2 {
+		panic(ErrTooFewClusters)
+	}
+	dist := knn.NewEuclideanDist()
+	centroids := make([]knn.Point, k)
+	sizes := make([]int, k)
+	for i, p := range points {
+		l := labels[i]
+		if l < 0 {
+			continue
+		}
+		if centroids[l] == nil {
+			centroids[l] = knn.NewPoint(len(p))
+		}
+		for d, v := range p {
+			centroids[l][d] += v
+		}
+		sizes[l]++
+	}
+	for c := range centroids {
+		for d := range centroids[c] {
+			centroids[c][d] /= float64(sizes[c])
+		}
+	}
+	scatter := make([]float64, k)
+	for i, p := range points {
+		if l := labels[i]; l >= 0 {
+			scatter[l] += dist.Eval(p, centroids[l]) / float64(sizes[l])
+		}
+	}
+	sum := 0.0
+	for i := range centroids {
+		worst := 0.0
+		for j := range centroids {
+			if d := dist.Eval(centroids[i], centroids[j]); j != i && d > 0 {
+				worst = math.Max(worst, (scatter[i]+scatter[j])/d)
+			}
+		}
+		sum += worst
+	}
+	return sum / float64(k)
+}
+
+// Adjusted Rand index of two partitions of the same points, one for equal partitions and near zero for random ones
+//
+// Labels are compared as values, so noise is a cluster
+func AdjustedRandIndex(a, b []int) float64 {
+	if len(a) != len(b) {
+		panic(ErrLengthMismatch)
+	}
+	if len(a) == 0 {
+		panic(ErrEmpty)
+	}
+	type pair struct{ a, b int }
+	table := make(map[pair]int)
+	rows := make(map[int]int)
+	cols := make(map[int]int)
+	for i := range a {
+		table[pair{a[i], b[i]}]++
+		rows[a[i]]++
+		cols[b[i]]++
+	}
+	comb := func(n int) float64 {
+		return float64(n) * float64(n-1) / 2
+	}
+	index, sumRows, sumCols := 0.0, 0.0, 0.0
+	for _, n := range table {
+		index += comb(n)
+	}
+	for _, n := range rows {
+		sumRows += comb(n)
+	}
+	for _, n := range cols {
+		sumCols += comb(n)
+	}
+	expected := sumRows * sumCols / comb(len(a))
+	maxIndex := (sumRows + sumCols) / 2
+	if maxIndex == expected {
+		// both partitions are a single cluster or every point is alone
+		return 1
+	}
+	return (index - expected) / (maxIndex - expected)
+}
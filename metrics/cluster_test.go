@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func clusterPoints() ([]knn.Point, []int) {
+	points := []knn.Point{{0, 0}, {0, 1}, {1, 0}, {10, 10}, {10, 11}, {11, 10}}
+	return points, []int{0, 0, 0, 1, 1, 1}
+}
+
+func TestSilhouette(t *testing.T) {
+	points, labels := clusterPoints()
+	good := Silhouette(points, labels, knn.NewEuclideanDist(), 3)
+	if good < 0.9 {
+		t.Errorf("Silhouette failed. Expected score near one, but got %v", good)
+	}
+	bad := Silhouette(points, []int{0, 1, 0, 1, 0, 1}, knn.NewEuclideanDist(), 1)
+	if bad >= 0 {
+		t.Errorf("Silhouette failed. Expected negative score, but got %v", bad)
+	}
+	noisy := Silhouette(points, []int{0, 0, -1, 1, 1, 1}, knn.NewEuclideanDist(), 2)
+	if noisy < 0.9 {
+		t.Errorf("Silhouette failed. Expected noise to be ignored, but got %v", noisy)
+	}
+}
+
+func TestDaviesBouldin(t *testing.T) {
+	points, labels := clusterPoints()
+	good := DaviesBouldin(points, labels)
+	bad := DaviesBouldin(points, []int{0, 1, 0, 1, 0, 1})
+	if good >= bad || good > 0.2 {
+		t.Errorf("DaviesBouldin failed. Expected good %v lesser than bad %v", good, bad)
+	}
+}
+
+func TestClusterLabels(t *testing.T) {
+	points, labels := clusterPoints()
+	sparse := []int{3, 3, 3, 7, 7, 7}
+	if a, b := Silhouette(points, labels, knn.NewEuclideanDist(), 1), Silhouette(points, sparse, knn.NewEuclideanDist(), 1); a != b {
+		t.Errorf("Silhouette failed. Expected %v for non contiguous labels, but got %v", a, b)
+	}
+	if a, b := DaviesBouldin(points, labels), DaviesBouldin(points, sparse); a != b {
+		t.Errorf("DaviesBouldin failed. Expected %v for non contiguous labels, but got %v", a, b)
+	}
+	// same centroid of both clusters
+	if db := DaviesBouldin([]knn.Point{{0}, {2}, {1}, {1}}, []int{0, 0, 1, 1}); math.IsNaN(db) || math.IsInf(db, 0) {
+		t.Errorf("DaviesBouldin failed. Expected finite index, but got %v", db)
+	}
+	for _, degenerate := range [][]int{{-1, -1, -1, -1, -1, -1}, {5, 5, 5, -1, -1, -1}} {
+		for name, score := range map[string]func(){
+			"Silhouette":    func() { Silhouette(points, degenerate, knn.NewEuclideanDist(), 1) },
+			"DaviesBouldin": func() { DaviesBouldin(points, degenerate) },
+		} {
+			func() {
+				defer func() {
+					if r := recover(); r != ErrTooFewClusters {
+						t.Errorf("%s failed. Expected %v for labels %v, but got %v", name, ErrTooFewClusters, degenerate, r)
+					}
+				}()
+				score()
+			}()
+		}
+	}
+}
+
+func TestAdjustedRandIndex(t *testing.T) {
+	a := []int{0, 0, 0, 1, 1, 1}
+	if ari := AdjustedRandIndex(a, []int{5, 5, 5, 2, 2, 2}); !near(ari, 1) {
+		t.Errorf("AdjustedRandIndex failed. Expected 1 for renamed clusters, but got %v", ari)
+	}
+	// known value of sklearn
+	if ari := AdjustedRandIndex([]int{0, 0, 1, 1}, []int{0, 0, 1, 2}); !near(ari, 0.5714285714285715) {
+		t.Errorf("AdjustedRandIndex failed. Expected 0.5714, but got %v", ari)
+	}
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+var ErrSingleClass = errors.New("expected values have a single class")
+
+// Point of a threshold sweep curve, X and Y are the curve axes at the given threshold
+type CurvePoint struct {
+	X         float64
+	Y         float64
+	Threshold float64
+}
+
+// Curve of points sorted by threshold sweep
+type Curve []CurvePoint
+
+// Area under the curve by trapezoidal integration
+func (c Curve) AUC() float64 {
+	return Trapezoid(c)
+}
+
+// Write curve as delimited x,y,threshold records with header, so it can be plotted
+func (c Curve) WriteCSV(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "x,y,threshold"); err != nil {
+		return err
+	}
+	for _, p := range c {
+		if _, err := fmt.Fprintf(w, "%g,%g,%g\n", p.X, p.Y, p.Threshold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Area under points by trapezoidal integration over X, points must be sorted by X
+func Trapezoid(points []CurvePoint) float64 {
+	area := 0.0
+	for i := 1; i < len(points); i++ {
+		area += (points[i].X - points[i-1].X) * (points[i].Y + points[i-1].Y) / 2
+	}
+	return area
+}
+
+// counts of a threshold sweep, tps[i] and fps[i] are true and false positives of scores greater or equal to thresholds[i]
+func sweep(expected []bool, scores []float64) (tps, fps []int, thresholds []float64) {
+	if len(expected) != len(scores) {
+		panic(ErrLengthMismatch)
+	}
+	if len(expected) == 0 {
+		panic(ErrEmpty)
+	}
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	tp, fp := 0, 0
+	for k, i := range order {
+		if expected[i] {
+			tp++
+		} else {
+			fp++
+		}
+		// tied scores share a threshold
+		if k+1 < len(order) && scores[order[k+1]] == scores[i] {
+			continue
+		}
+		tps = append(tps, tp)
+		fps = append(fps, fp)
+		thresholds = append(thresholds, scores[i])
+	}
+	return tps, fps, thresholds
+}
+
+// ROC curve of binary scores, X is false positive rate and Y is true positive rate
+//
+// A sample is positive when its score is greater or equal to the threshold, the curve starts at (0, 0)
+func ROC(expected []bool, scores []float64) Curve {
+	tps, fps, thresholds := sweep(expected, scores)
+	pos, neg := tps[len(tps)-1], fps[len(fps)-1]
+	if pos == 0 || neg == 0 {
+		panic(ErrSingleClass)
+	}
+	curve := make(Curve, 0, len(tps)+1)
+	curve = append(curve, CurvePoint{X: 0, Y: 0, Threshold: thresholds[0] + 1})
+	for i := range tps {
+		curve = append(curve, CurvePoint{
+			X:         float64(fps[i]) / float64(neg),
+			Y:         float64(tps[i]) / float64(pos),
+			Threshold: thresholds[i],
+		})
+	}
+	return curve
+}
+
+// Area under ROC curve of binary scores
+func ROCAUC(expected []bool, scores []float64) float64 {
+	return ROC(expected, scores).AUC()
+}
+
+// Precision-recall curve of binary scores, X is recall and Y is precision
+//
+// A sample is positive when its score is greater or equal to the threshold, the curve starts at recall zero
+// with precision one
+func PrecisionRecall(expected []bool, scores []float64) Curve {
+	tps, fps, thresholds := sweep(expected, scores)
+	pos := tps[len(tps)-1]
+	if pos == 0 {
+		panic(ErrSingleClass)
+	}
+	curve := make(Curve, 0, len(tps)+1)
+	curve = append(curve, CurvePoint{X: 0, Y: 1, Threshold: thresholds[0] + 1})
+	for i := range tps {
+		curve = append(curve, CurvePoint{
+			X:         float64(tps[i]) / float64(pos),
+			Y:         float64(tps[i]) / float64(tps[i]+fps[i]),
+			Threshold: thresholds[i],
+		})
+	}
+	return curve
+}
+
+// Average precision, the sum of precisions weighted by the increase of recall at every threshold
+func AveragePrecision(expected []bool, scores []float64) float64 {
+	curve := PrecisionRecall(expected, scores)
+	ap := 0.0
+	for i := 1; i < len(curve); i++ {
+		ap += (curve[i].X - curve[i-1].X) * curve[i].Y
+	}
+	return ap
+}
+
+// Binary expected values and scores of a label, from labels and class probabilities like the ones of PredictProba
+func BinaryScores(expected []any, proba []map[any]float64, positive any) ([]bool, []float64) {
+	if len(expected) != len(proba) {
+		panic(ErrLengthMismatch)
+	}
+	binary := make([]bool, len(expected))
+	scores := make([]float64, len(expected))
+	for i := range expected {
+		binary[i] = expected[i] == positive
+		scores[i] = proba[i][positive]
+	}
+	return binary, scores
+}
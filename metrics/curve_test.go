@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestROC(t *testing.T) {
+	expected := []bool{false, false, true, true}
+	scores := []float64{0.1, 0.4, 0.35, 0.8}
+	curve := ROC(expected, scores)
+	if len(curve) != 5 || curve[0].X != 0 || curve[0].Y != 0 || curve[4].X != 1 || curve[4].Y != 1 {
+		t.Fatalf("ROC failed. Unexpected curve %v", curve)
+	}
+	if auc := ROCAUC(expected, scores); !near(auc, 0.75) {
+		t.Errorf("ROCAUC failed. Expected 0.75, but got %v", auc)
+	}
+	// tied scores are a single point, so the diagonal is integrated
+	if auc := ROCAUC(expected, []float64{0.5, 0.5, 0.5, 0.5}); !near(auc, 0.5) {
+		t.Errorf("ROCAUC failed. Expected 0.5 for tied scores, but got %v", auc)
+	}
+	var sb strings.Builder
+	if err := curve.WriteCSV(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sb.String(), "x,y,threshold\n0,0,1.8\n0,0.5,0.8\n") {
+		t.Errorf("WriteCSV failed. Unexpected records %q", sb.String())
+	}
+}
+
+func TestPrecisionRecall(t *testing.T) {
+	expected := []bool{false, false, true, true}
+	scores := []float64{0.1, 0.4, 0.35, 0.8}
+	curve := PrecisionRecall(expected, scores)
+	last := curve[len(curve)-1]
+	if last.X != 1 || last.Y != 0.5 {
+		t.Errorf("PrecisionRecall failed. Expected last point (1, 0.5), but got %v", last)
+	}
+	ap := 0.5*1 + 0.5*2.0/3
+	if got := AveragePrecision(expected, scores); !near(got, ap) {
+		t.Errorf("AveragePrecision failed. Expected %v, but got %v", ap, got)
+	}
+}
+
+func TestBinaryScores(t *testing.T) {
+	proba := []map[any]float64{{"a": 0.9, "b": 0.1}, {"b": 1}, {"a": 0.4, "b": 0.6}}
+	binary, scores := BinaryScores([]any{"a", "b", "a"}, proba, "a")
+	if !binary[0] || binary[1] || !binary[2] || scores[1] != 0 || scores[2] != 0.4 {
+		t.Errorf("BinaryScores failed. Unexpected %v and %v", binary, scores)
+	}
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var ErrZeroTarget = errors.New("expected value is zero")
+
+func checkValues(expected, predicted []float64) {
+	if len(expected) != len(predicted) {
+		panic(ErrLengthMismatch)
+	}
+	if len(expected) == 0 {
+		panic(ErrEmpty)
+	}
+}
+
+// Values of a tensor of any element type as float64, in the order of its memory layout
+func Floats(tensor *graph.Tensor) []float64 {
+	switch tensor.Type() {
+	case graph.Float16:
+		data := tensor.F16Slice()
+		out := make([]float64, len(data))
+		for i, v := range data {
+			out[i] = v.ToF64()
+		}
+		return out
+	case graph.Float32:
+		data := tensor.F32Slice()
+		out := make([]float64, len(data))
+		for i, v := range data {
+			out[i] = float64(v)
+		}
+		return out
+	}
+	return append([]float64{}, tensor.F64Slice()...)
+}
+
+// values of two tensors with equal shape
+func tensorValues(expected, predicted *graph.Tensor) ([]float64, []float64) {
+	if !expected.Shape().Equal(predicted.Shape()) {
+		panic(ErrLengthMismatch)
+	}
+	return Floats(expected), Floats(predicted)
+}
+
+// Mean squared error
+func MSE(expected, predicted []float64) float64 {
+	checkValues(expected, predicted)
+	sum := 0.0
+	for i := range expected {
+		dif := expected[i] - predicted[i]
+		sum += dif * dif
+	}
+	return sum / float64(len(expected))
+}
+
+// Root mean squared error
+func RMSE(expected, predicted []float64) float64 {
+	return math.Sqrt(MSE(expected, predicted))
+}
+
+// Mean absolute error
+func MAE(expected, predicted []float64) float64 {
+	checkValues(expected, predicted)
+	sum := 0.0
+	for i := range expected {
+		sum += math.Abs(expected[i] - predicted[i])
+	}
+	return sum / float64(len(expected))
+}
+
+// Coefficient of determination, one is a perfect fit and zero is the fit of the mean
+//
+// When expected values are constant it is one for a perfect fit and zero otherwise
+func R2(expected, predicted []float64) float64 {
+	checkValues(expected, predicted)
+	mean := 0.0
+	for _, v := range expected {
+		mean += v
+	}
+	mean /= float64(len(expected))
+	res, tot := 0.0, 0.0
+	for i := range expected {
+		res += (expected[i] - predicted[i]) * (expected[i] - predicted[i])
+		tot += (expected[i] - mean) * (expected[i] - mean)
+	}
+	if tot == 0 {
+		if res == 0 {
+			return 1
+		}
+		return 0
+	}
+	return 1 - res/tot
+}
+
+// Mean absolute percentage error as a fraction, it panics when an expected value is zero
+func MAPE(expected, predicted []float64) float64 {
+	checkValues(expected, predicted)
+	sum := 0.0
+	for i := range expected {
+		if expected[i] == 0 {
+			panic(ErrZeroTarget)
+		}
+		sum += math.Abs((expected[i] - predicted[i]) / expected[i])
+	}
+	return sum / float64(len(expected))
+}
+
+// Root mean squared error of tensors with equal shape
+func RMSETensor(expected, predicted *graph.Tensor) float64 {
+	return RMSE(tensorValues(expected, predicted))
+}
+
+// Mean absolute error of tensors with equal shape
+func MAETensor(expected, predicted *graph.Tensor) float64 {
+	return MAE(tensorValues(expected, predicted))
+}
+
+// Coefficient of determination of tensors with equal shape
+func R2Tensor(expected, predicted *graph.Tensor) float64 {
+	return R2(tensorValues(expected, predicted))
+}
+
+// Mean absolute percentage error of tensors with equal shape
+func MAPETensor(expected, predicted *graph.Tensor) float64 {
+	return MAPE(tensorValues(expected, predicted))
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+func TestRegressionMetrics(t *testing.T) {
+	expected := []float64{1, 2, 4, 5}
+	predicted := []float64{1, 3, 3, 5}
+	if v := MSE(expected, predicted); !near(v, 0.5) {
+		t.Errorf("MSE failed. Expected 0.5, but got %v", v)
+	}
+	if v := RMSE(expected, predicted); !near(v*v, 0.5) {
+		t.Errorf("RMSE failed. Expected sqrt(0.5), but got %v", v)
+	}
+	if v := MAE(expected, predicted); !near(v, 0.5) {
+		t.Errorf("MAE failed. Expected 0.5, but got %v", v)
+	}
+	// mean is 3 so total sum of squares is 10
+	if v := R2(expected, predicted); !near(v, 0.8) {
+		t.Errorf("R2 failed. Expected 0.8, but got %v", v)
+	}
+	if v := MAPE(expected, predicted); !near(v, (0.5+0.25)/4) {
+		t.Errorf("MAPE failed. Expected %v, but got %v", (0.5+0.25)/4, v)
+	}
+}
+
+func TestRegressionTensor(t *testing.T) {
+	expected := graph.NewTensor([]float32{1, 2, 4, 5}, graph.Float32, graph.NewShape(2, 2))
+	predicted := graph.NewTensor([]float64{1, 3, 3, 5}, graph.Float64, graph.NewShape(2, 2))
+	if v := MAETensor(expected, predicted); !near(v, 0.5) {
+		t.Errorf("MAETensor failed. Expected 0.5, but got %v", v)
+	}
+	if v := R2Tensor(expected, predicted); !near(v, 0.8) {
+		t.Errorf("R2Tensor failed. Expected 0.8, but got %v", v)
+	}
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"github.com/stellviaproject/go-ia/discriminant"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linear"
+	"github.com/stellviaproject/go-ia/nn/graph"
+	"github.com/stellviaproject/go-ia/tree"
+)
+
+// Model fitted to data points and predicting labels of points, like classifiers of bayes, linear, svm, tree
+// and pipelines
+type PointModel interface {
+	Fit(data []knn.DataPoint)
+	Predict(point knn.Point) any
+}
+
+// Model of point transformations, like scalers of knn and PCA
+type PointTransformer interface {
+	Fit(points []knn.Point)
+	Transform(point knn.Point) knn.Point
+}
+
+// estimator of fit and predict functions of data points
+type estimator struct {
+	fit     func(data []knn.DataPoint) error
+	predict func(point knn.Point) any
+	fitted  bool
+}
+
+// Create estimator of fit and predict functions of data points
+func NewFuncEstimator(fit func(data []knn.DataPoint) error, predict func(point knn.Point) any) Estimator {
+	return &estimator{fit: fit, predict: predict}
+}
+
+func (e *estimator) Fit(x *graph.Tensor, y []any) error {
+	if err := e.fit(DataPoints(x, y)); err != nil {
+		return err
+	}
+	e.fitted = true
+	return nil
+}
+
+func (e *estimator) Predict(x *graph.Tensor) []any {
+	if !e.fitted {
+		panic(ErrNotFitted)
+	}
+	points := Points(x)
+	out := make([]any, len(points))
+	for i, p := range points {
+		out[i] = e.predict(p)
+	}
+	return out
+}
+
+func (e *estimator) Score(x *graph.Tensor, y []any) float64 {
+	return Score(y, e.Predict(x))
+}
+
+// Create estimator of model of data points
+func NewEstimator(m PointModel) Estimator {
+	return NewFuncEstimator(func(data []knn.DataPoint) error {
+		m.Fit(data)
+		return nil
+	}, m.Predict)
+}
+
+// Create estimator of KNN built with training data on Fit
+func NewKNN(k int, dist knn.Distance, selector knn.Selector, opts ...knn.Option) Estimator {
+	var model *knn.KNN
+	return NewFuncEstimator(func(data []knn.DataPoint) error {
+		model = knn.NewKNN(k, dist, selector, data, opts...)
+		return nil
+	}, func(point knn.Point) any {
+		return model.Predict(point)
+	})
+}
+
+// Create estimator of linear regression of float64 labels
+func NewLinearRegression(lr *linear.LinearRegression) Estimator {
+	return NewFuncEstimator(lr.Fit, func(point knn.Point) any {
+		return lr.Predict(point)
+	})
+}
+
+// Create estimator of linear discriminant analysis
+func NewLDA(lda *discriminant.LDA) Estimator {
+	return NewFuncEstimator(lda.Fit, lda.Predict)
+}
+
+// Create estimator of gradient boosting fitted without validation data
+func NewBoosting(gb *tree.GradientBoosting) Estimator {
+	return NewFuncEstimator(func(data []knn.DataPoint) error {
+		gb.Fit(data, nil)
+		return nil
+	}, gb.Predict)
+}
+
+type transformer struct {
+	t PointTransformer
+}
+
+// Create transformer of tensors of point transformer
+func NewTransformer(t PointTransformer) Transformer {
+	return &transformer{t: t}
+}
+
+func (tr *transformer) Fit(x *graph.Tensor) error {
+	tr.t.Fit(Points(x))
+	return nil
+}
+
+func (tr *transformer) Transform(x *graph.Tensor) *graph.Tensor {
+	points := Points(x)
+	for i, p := range points {
+		points[i] = tr.t.Transform(p)
+	}
+	return Tensor(points)
+}
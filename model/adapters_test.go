@@ -0,0 +1,77 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/bayes"
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/discriminant"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linear"
+	"github.com/stellviaproject/go-ia/nn/graph"
+	"github.com/stellviaproject/go-ia/svm"
+	"github.com/stellviaproject/go-ia/tree"
+)
+
+func split(data []knn.DataPoint) (*graph.Tensor, []any) {
+	points := make([]knn.Point, len(data))
+	labels := make([]any, len(data))
+	for i, dp := range data {
+		points[i], labels[i] = dp.Point(), dp.Label()
+	}
+	return Tensor(points), labels
+}
+
+func TestClassifiers(t *testing.T) {
+	data := dataset.MakeBlobs(200, []knn.Point{{0, 0}, {4, 4}}, 1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.7, true, 1)
+	x, y := split(train)
+	xt, yt := split(test)
+	estimators := map[string]Estimator{
+		"knn":      NewKNN(5, knn.NewEuclideanDist(), knn.NewMultiClassSelector()),
+		"logistic": NewEstimator(linear.NewLogisticRegression(linear.LogisticConfig{})),
+		"bayes":    NewEstimator(bayes.NewGaussianNB(1e-9)),
+		"tree":     NewEstimator(tree.NewClassifier(tree.Config{MaxDepth: 3})),
+		"svm":      NewEstimator(svm.NewLinearSVM(0.01, 20, 1)),
+		"lda":      NewLDA(discriminant.NewLDA(1, 0)),
+		"boosting": NewBoosting(tree.NewGBClassifier(tree.BoostConfig{Trees: 20})),
+	}
+	for name, e := range estimators {
+		if err := e.Fit(x, y); err != nil {
+			t.Fatalf("Fit of %v failed. %v", name, err)
+		}
+		if s := e.Score(xt, yt); s < 0.95 {
+			t.Errorf("Score of %v failed. Expected accuracy greater than 0.95, but got %v", name, s)
+		}
+	}
+}
+
+func TestRegressor(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := make([]knn.Point, 50)
+	y := make([]any, 50)
+	for i := range points {
+		points[i] = knn.Point{rnd.Float64(), rnd.Float64()}
+		y[i] = 3*points[i][0] - 2*points[i][1] + 1 + 0.01*rnd.NormFloat64()
+	}
+	e := NewLinearRegression(linear.NewOLS())
+	if err := e.Fit(Tensor(points), y); err != nil {
+		t.Fatal(err)
+	}
+	if s := e.Score(Tensor(points), y); s < 0.99 {
+		t.Errorf("Score failed. Expected R2 greater than 0.99, but got %v", s)
+	}
+}
+
+func TestTransformer(t *testing.T) {
+	tr := NewTransformer(knn.NewStandardScaler())
+	x := Tensor([]knn.Point{{1, 10}, {3, 30}})
+	if err := tr.Fit(x); err != nil {
+		t.Fatal(err)
+	}
+	out := Points(tr.Transform(x))
+	if out[0][0] != -1 || out[1][1] != 1 {
+		t.Errorf("Transform failed. Expected standardized points, but got %v", out)
+	}
+}
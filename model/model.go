@@ -0,0 +1,85 @@
+// Package model defines common interfaces of estimators and transformers over tensors, with adapters of the
+// models of every package, so model selection and pipelines work with any of them
+package model
+
+import (
+	"errors"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/metrics"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrLabelsMismatch = errors.New("number of labels doesn't match number of samples")
+	ErrNotFitted      = errors.New("estimator is not fitted")
+)
+
+// Estimator of labels of samples, x is a tensor of shape (samples, features)
+type Estimator interface {
+	Fit(x *graph.Tensor, y []any) error
+	Predict(x *graph.Tensor) []any
+	Score(x *graph.Tensor, y []any) float64 //greater is better
+}
+
+// Transformer of samples, x is a tensor of shape (samples, features)
+type Transformer interface {
+	Fit(x *graph.Tensor) error
+	Transform(x *graph.Tensor) *graph.Tensor
+}
+
+// Factory of unfitted estimators, used to fit a new estimator for every fold or configuration
+type Factory func() Estimator
+
+// Points of rows of x
+func Points(x *graph.Tensor) []knn.Point {
+	return knn.PointsFromTensor(x)
+}
+
+// Data points of rows of x labeled by y
+func DataPoints(x *graph.Tensor, y []any) []knn.DataPoint {
+	points := Points(x)
+	if len(points) != len(y) {
+		panic(ErrLabelsMismatch)
+	}
+	data := make([]knn.DataPoint, len(points))
+	for i, p := range points {
+		data[i] = knn.NewDataPoint(y[i], p)
+	}
+	return data
+}
+
+// Float64 tensor of shape (samples, features) of points
+func Tensor(points []knn.Point) *graph.Tensor {
+	if len(points) == 0 {
+		panic(knn.ErrNotEnoughData)
+	}
+	t := graph.NewTensor(nil, graph.Float64, graph.NewShape(len(points), len(points[0])))
+	index := make([]int, 2)
+	for i, p := range points {
+		index[0] = i
+		for j, v := range p {
+			index[1] = j
+			t.SetF64(index, v)
+		}
+	}
+	return t
+}
+
+// Default score of predictions, R2 if every label is float64 and accuracy otherwise
+func Score(y, predicted []any) float64 {
+	if len(y) != len(predicted) {
+		panic(ErrLabelsMismatch)
+	}
+	expected := make([]float64, len(y))
+	values := make([]float64, len(y))
+	for i := range y {
+		e, ok1 := y[i].(float64)
+		v, ok2 := predicted[i].(float64)
+		if !ok1 || !ok2 {
+			return metrics.Accuracy(y, predicted)
+		}
+		expected[i], values[i] = e, v
+	}
+	return metrics.R2(expected, values)
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func TestScore(t *testing.T) {
+	if s := Score([]any{"a", "b", "a", "b"}, []any{"a", "b", "b", "b"}); s != 0.75 {
+		t.Errorf("Score failed. Expected accuracy 0.75, but got %v", s)
+	}
+	if s := Score([]any{1.0, 2.0, 3.0}, []any{1.0, 2.0, 3.0}); math.Abs(s-1) > 1e-12 {
+		t.Errorf("Score failed. Expected R2 1, but got %v", s)
+	}
+}
+
+func TestTensor(t *testing.T) {
+	points := []knn.Point{{1, 2}, {3, 4}, {5, 6}}
+	back := Points(Tensor(points))
+	for i := range points {
+		for j := range points[i] {
+			if back[i][j] != points[i][j] {
+				t.Fatalf("Tensor failed. Expected %v, but got %v", points, back)
+			}
+		}
+	}
+}
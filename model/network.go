@@ -0,0 +1,56 @@
+package model
+
+import (
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+// Configuration of training of network estimators
+type NetworkConfig struct {
+	Epochs    int //10 by default
+	BatchSize int //32 by default
+	Seed      int64
+}
+
+// Create estimator of network classifier, outputs of network are logits of classes in order of appearance of
+// labels and it is trained with cross entropy
+func NewNetworkClassifier(net *layers.Sequential, opt optim.Optimizer, config NetworkConfig) Estimator {
+	if config.Epochs <= 0 {
+		config.Epochs = 10
+	}
+	var classes []any
+	return NewFuncEstimator(func(points []knn.DataPoint) error {
+		classes = classes[:0]
+		index := make(map[any]int)
+		inputs := make([][]float64, len(points))
+		targets := make([][]float64, len(points))
+		for i, dp := range points {
+			c, ok := index[dp.Label()]
+			if !ok {
+				c = len(classes)
+				index[dp.Label()] = c
+				classes = append(classes, dp.Label())
+			}
+			inputs[i] = dp.Point()
+			targets[i] = []float64{float64(c)}
+		}
+		opt.Reset()
+		loader := data.NewDataLoader(data.NewSliceDataset(inputs, targets), data.LoaderConfig{
+			BatchSize: config.BatchSize, Shuffle: true, Seed: config.Seed,
+		})
+		_, err := train.NewTrainer(net, opt, train.CrossEntropy).Fit(loader, config.Epochs)
+		return err
+	}, func(point knn.Point) any {
+		logits := net.Forward(point)
+		best := 0
+		for c := range logits {
+			if c < len(classes) && logits[c] > logits[best] {
+				best = c
+			}
+		}
+		return classes[best]
+	})
+}
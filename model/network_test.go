@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+func TestNetworkClassifier(t *testing.T) {
+	data := dataset.MakeBlobs(300, []knn.Point{{0, 0}, {4, 0}, {2, 4}}, 0.8, 2)
+	train, test := dataset.SplitTrainTest(data, 0.7, true, 2)
+	x, y := split(train)
+	net := layers.NewSequential(1, layers.NewDense(2, 16), layers.NewReLU(), layers.NewDense(16, 3))
+	e := NewNetworkClassifier(net, optim.NewAdam(0.02), NetworkConfig{Epochs: 30, BatchSize: 16, Seed: 1})
+	if err := e.Fit(x, y); err != nil {
+		t.Fatal(err)
+	}
+	xt, yt := split(test)
+	if s := e.Score(xt, yt); s < 0.95 {
+		t.Errorf("Score failed. Expected accuracy greater than 0.95, but got %v", s)
+	}
+}
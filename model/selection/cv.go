@@ -0,0 +1,137 @@
+package selection
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/model"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Metric of predictions of a fold, greater is better
+type Metric func(expected, predicted []any) float64
+
+// Name of the default metric, the Score of the estimator
+const DefaultMetric = "score"
+
+// Configuration of cross validation
+type CVConfig struct {
+	Splitter Splitter          //5-fold without shuffle if nil
+	Metrics  map[string]Metric //Score of the estimator named DefaultMetric if empty
+	Workers  int               //folds fitted at the same time, 1 if zero
+}
+
+// Scores of cross validation by metric
+type CVResult struct {
+	Scores map[string][]float64 //score of every fold by metric
+	Mean   map[string]float64
+	Std    map[string]float64
+}
+
+// Metric names in alphabetical order
+func (r *CVResult) Names() []string {
+	names := make([]string, 0, len(r.Scores))
+	for name := range r.Scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// samples of x and y at indices
+func take(points []knn.Point, y []any, indices []int) (*graph.Tensor, []any) {
+	sub := make([]knn.Point, len(indices))
+	labels := make([]any, len(indices))
+	for i, j := range indices {
+		sub[i], labels[i] = points[j], y[j]
+	}
+	return model.Tensor(sub), labels
+}
+
+// Fit a new estimator of factory for every fold and score its predictions of the test samples of the fold
+//
+// Folds are fitted in parallel, so the estimators of factory must not share state
+func CrossValidate(factory model.Factory, x *graph.Tensor, y []any, config CVConfig) (*CVResult, error) {
+	points := model.Points(x)
+	if len(points) != len(y) {
+		return nil, ErrLabelsMismatch
+	}
+	if config.Splitter == nil {
+		config.Splitter = NewKFold(5, false, 0)
+	}
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	folds, err := config.Splitter.Split(len(points), y)
+	if err != nil {
+		return nil, err
+	}
+	result := &CVResult{Scores: make(map[string][]float64), Mean: make(map[string]float64), Std: make(map[string]float64)}
+	if len(config.Metrics) == 0 {
+		result.Scores[DefaultMetric] = make([]float64, len(folds))
+	}
+	for name := range config.Metrics {
+		result.Scores[name] = make([]float64, len(folds))
+	}
+	errs := make([]error, len(folds))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	indices := make(chan int)
+	for w := 0; w < config.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range indices {
+				trainX, trainY := take(points, y, folds[f].Train)
+				testX, testY := take(points, y, folds[f].Test)
+				estimator := factory()
+				if errs[f] = estimator.Fit(trainX, trainY); errs[f] != nil {
+					continue
+				}
+				scores := make(map[string]float64, len(result.Scores))
+				if len(config.Metrics) == 0 {
+					scores[DefaultMetric] = estimator.Score(testX, testY)
+				} else {
+					predicted := estimator.Predict(testX)
+					for name, metric := range config.Metrics {
+						scores[name] = metric(testY, predicted)
+					}
+				}
+				mu.Lock()
+				for name, s := range scores {
+					result.Scores[name][f] = s
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for f := range folds {
+		indices <- f
+	}
+	close(indices)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	for name, scores := range result.Scores {
+		result.Mean[name], result.Std[name] = meanStd(scores)
+	}
+	return result, nil
+}
+
+func meanStd(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	sq := 0.0
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)))
+}
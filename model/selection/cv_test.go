@@ -0,0 +1,56 @@
+package selection
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/metrics"
+	"github.com/stellviaproject/go-ia/model"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+func blobs() (*graph.Tensor, []any) {
+	data := dataset.MakeBlobs(150, []knn.Point{{0, 0}, {5, 5}, {0, 5}}, 1, 1)
+	points := make([]knn.Point, len(data))
+	y := make([]any, len(data))
+	for i, dp := range data {
+		points[i], y[i] = dp.Point(), dp.Label()
+	}
+	return model.Tensor(points), y
+}
+
+func TestCrossValidate(t *testing.T) {
+	x, y := blobs()
+	factory := func() model.Estimator {
+		return model.NewKNN(5, knn.NewEuclideanDist(), knn.NewMultiClassSelector())
+	}
+	result, err := CrossValidate(factory, x, y, CVConfig{
+		Splitter: NewStratifiedKFold(5, true, 1),
+		Metrics: map[string]Metric{
+			"accuracy": metrics.Accuracy,
+			"f1":       metrics.MacroF1,
+		},
+		Workers: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := result.Names(); len(names) != 2 || names[0] != "accuracy" {
+		t.Errorf("CrossValidate failed. Expected metrics accuracy and f1, but got %v", names)
+	}
+	if len(result.Scores["accuracy"]) != 5 {
+		t.Errorf("CrossValidate failed. Expected 5 scores, but got %v", result.Scores["accuracy"])
+	}
+	if result.Mean["accuracy"] < 0.95 || result.Std["accuracy"] > 0.05 {
+		t.Errorf("CrossValidate failed. Expected mean accuracy greater than 0.95, but got %v ± %v",
+			result.Mean["accuracy"], result.Std["accuracy"])
+	}
+	result, err = CrossValidate(factory, x, y, CVConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result.Mean[DefaultMetric]; !ok {
+		t.Errorf("CrossValidate failed. Expected default metric, but got %v", result.Names())
+	}
+}
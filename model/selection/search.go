@@ -0,0 +1,247 @@
+package selection
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/stellviaproject/go-ia/model"
+	"github.com/stellviaproject/go-ia/nn/graph"
+	"github.com/stellviaproject/go-ia/progress"
+)
+
+var (
+	ErrGridNotValid   = errors.New("parameter grid is empty or has a parameter without values")
+	ErrMetricNotFound = errors.New("metric to rank configurations is not a metric of cross validation")
+)
+
+// Hyperparameters of an estimator by name
+type Params map[string]any
+
+// Float parameter, panics if it is missing or it isn't float64
+func (p Params) Float(name string) float64 {
+	return p[name].(float64)
+}
+
+// Int parameter, panics if it is missing or it isn't int
+func (p Params) Int(name string) int {
+	return p[name].(int)
+}
+
+// Factory of unfitted estimators configured by params
+type ParamFactory func(params Params) model.Estimator
+
+// Values of every parameter, every combination is evaluated
+type Grid map[string][]any
+
+// Distribution of values of a parameter in random search
+type Distribution interface {
+	Sample(rnd *rand.Rand) any
+}
+
+// DistributionFunc is a function used as a Distribution
+type DistributionFunc func(rnd *rand.Rand) any
+
+func (fn DistributionFunc) Sample(rnd *rand.Rand) any {
+	return fn(rnd)
+}
+
+// Choose one of values with the same probability
+func Choice(values ...any) Distribution {
+	if len(values) == 0 {
+		panic(ErrGridNotValid)
+	}
+	return DistributionFunc(func(rnd *rand.Rand) any {
+		return values[rnd.Intn(len(values))]
+	})
+}
+
+// Uniform float64 in [lo, hi)
+func Uniform(lo, hi float64) Distribution {
+	return DistributionFunc(func(rnd *rand.Rand) any {
+		return lo + rnd.Float64()*(hi-lo)
+	})
+}
+
+// Float64 uniform in logarithmic scale in [lo, hi), like learning rates or regularization, lo must be positive
+func LogUniform(lo, hi float64) Distribution {
+	if lo <= 0 {
+		panic(ErrGridNotValid)
+	}
+	return DistributionFunc(func(rnd *rand.Rand) any {
+		return math.Exp(math.Log(lo) + rnd.Float64()*(math.Log(hi)-math.Log(lo)))
+	})
+}
+
+// Uniform int in [lo, hi]
+func IntUniform(lo, hi int) Distribution {
+	return DistributionFunc(func(rnd *rand.Rand) any {
+		return lo + rnd.Intn(hi-lo+1)
+	})
+}
+
+// Configuration of search
+type SearchConfig struct {
+	CV         CVConfig      //cross validation of every configuration
+	Metric     string        //metric to rank configurations, the only metric of CV if empty
+	Workers    int           //configurations evaluated at the same time, 1 if zero
+	Iterations int           //configurations sampled by random search, 10 if zero
+	Seed       int64         //seed of random search
+	Progress   progress.Func //receives progress after every configuration, it may be nil
+}
+
+// Configuration evaluated by search
+type Candidate struct {
+	Params Params
+	Result *CVResult
+}
+
+// Result of search
+type SearchResult struct {
+	Best       Params
+	BestScore  float64         //mean score of metric of best configuration
+	Estimator  model.Estimator //estimator of best configuration fitted with every sample
+	Candidates []Candidate     //in order of evaluation
+}
+
+// Evaluate every combination of values of grid by cross validation and refit the best one with every sample
+func GridSearchCV(factory ParamFactory, grid Grid, x *graph.Tensor, y []any, config SearchConfig) (*SearchResult, error) {
+	return GridSearchCVContext(context.Background(), factory, grid, x, y, config)
+}
+
+// Grid search until ctx is done, then it returns the error of ctx
+func GridSearchCVContext(ctx context.Context, factory ParamFactory, grid Grid, x *graph.Tensor, y []any, config SearchConfig) (*SearchResult, error) {
+	if len(grid) == 0 {
+		return nil, ErrGridNotValid
+	}
+	names := make([]string, 0, len(grid))
+	for name, values := range grid {
+		if len(values) == 0 {
+			return nil, ErrGridNotValid
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// combinations in lexicographic order of names, the last name changes fastest
+	combinations := []Params{{}}
+	for _, name := range names {
+		next := make([]Params, 0, len(combinations)*len(grid[name]))
+		for _, params := range combinations {
+			for _, v := range grid[name] {
+				p := make(Params, len(params)+1)
+				for k, pv := range params {
+					p[k] = pv
+				}
+				p[name] = v
+				next = append(next, p)
+			}
+		}
+		combinations = next
+	}
+	return search(ctx, factory, combinations, x, y, config)
+}
+
+// Evaluate configurations sampled from distributions by cross validation and refit the best one with every sample
+func RandomSearchCV(factory ParamFactory, distributions map[string]Distribution, x *graph.Tensor, y []any, config SearchConfig) (*SearchResult, error) {
+	return RandomSearchCVContext(context.Background(), factory, distributions, x, y, config)
+}
+
+// Random search until ctx is done, then it returns the error of ctx
+func RandomSearchCVContext(ctx context.Context, factory ParamFactory, distributions map[string]Distribution, x *graph.Tensor, y []any, config SearchConfig) (*SearchResult, error) {
+	if len(distributions) == 0 {
+		return nil, ErrGridNotValid
+	}
+	if config.Iterations <= 0 {
+		config.Iterations = 10
+	}
+	names := make([]string, 0, len(distributions))
+	for name := range distributions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rnd := rand.New(rand.NewSource(config.Seed))
+	configurations := make([]Params, config.Iterations)
+	for i := range configurations {
+		configurations[i] = make(Params, len(names))
+		for _, name := range names {
+			configurations[i][name] = distributions[name].Sample(rnd)
+		}
+	}
+	return search(ctx, factory, configurations, x, y, config)
+}
+
+func search(ctx context.Context, factory ParamFactory, configurations []Params, x *graph.Tensor, y []any, config SearchConfig) (*SearchResult, error) {
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	metric := config.Metric
+	if metric == "" {
+		switch len(config.CV.Metrics) {
+		case 0:
+			metric = DefaultMetric
+		case 1:
+			for name := range config.CV.Metrics {
+				metric = name
+			}
+		default:
+			return nil, ErrMetricNotFound
+		}
+	}
+	if _, ok := config.CV.Metrics[metric]; !ok && !(len(config.CV.Metrics) == 0 && metric == DefaultMetric) {
+		return nil, ErrMetricNotFound
+	}
+	candidates := make([]Candidate, len(configurations))
+	errs := make([]error, len(configurations))
+	tracker := progress.NewTracker(len(configurations), config.Progress)
+	var wg sync.WaitGroup
+	indices := make(chan int)
+	for w := 0; w < config.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range indices {
+				params := configurations[c]
+				candidates[c].Params = params
+				candidates[c].Result, errs[c] = CrossValidate(func() model.Estimator {
+					return factory(params)
+				}, x, y, config.CV)
+				tracker.Step(1)
+			}
+		}()
+	}
+feed:
+	for c := range configurations {
+		select {
+		case indices <- c:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	best := -1
+	for c, candidate := range candidates {
+		if errs[c] != nil {
+			return nil, errs[c]
+		}
+		if best < 0 || candidate.Result.Mean[metric] > candidates[best].Result.Mean[metric] {
+			best = c
+		}
+	}
+	estimator := factory(candidates[best].Params)
+	if err := estimator.Fit(x, y); err != nil {
+		return nil, err
+	}
+	return &SearchResult{
+		Best:       candidates[best].Params,
+		BestScore:  candidates[best].Result.Mean[metric],
+		Estimator:  estimator,
+		Candidates: candidates,
+	}, nil
+}
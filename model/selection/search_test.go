@@ -0,0 +1,103 @@
+package selection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/metrics"
+	"github.com/stellviaproject/go-ia/model"
+	"github.com/stellviaproject/go-ia/progress"
+	"github.com/stellviaproject/go-ia/tree"
+)
+
+func treeFactory(params Params) model.Estimator {
+	return model.NewEstimator(tree.NewClassifier(tree.Config{MaxDepth: params.Int("depth"), MinSamplesLeaf: params.Int("leaf")}))
+}
+
+func TestGridSearchCV(t *testing.T) {
+	x, y := blobs()
+	result, err := GridSearchCV(treeFactory, Grid{"depth": {1, 2, 4}, "leaf": {1, 5}}, x, y, SearchConfig{
+		CV:      CVConfig{Splitter: NewStratifiedKFold(3, true, 1), Metrics: map[string]Metric{"accuracy": metrics.Accuracy}},
+		Workers: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Candidates) != 6 {
+		t.Fatalf("GridSearchCV failed. Expected 6 candidates, but got %v", len(result.Candidates))
+	}
+	if c := result.Candidates[1].Params; c.Int("depth") != 1 || c.Int("leaf") != 5 {
+		t.Errorf("GridSearchCV failed. Expected candidates in order of grid, but got %v", c)
+	}
+	// one split can't separate three blobs
+	if result.Best.Int("depth") == 1 || result.BestScore < 0.95 {
+		t.Errorf("GridSearchCV failed. Expected depth greater than 1 and accuracy greater than 0.95, but got %v with %v",
+			result.Best, result.BestScore)
+	}
+	if s := result.Estimator.Score(x, y); s < 0.95 {
+		t.Errorf("GridSearchCV failed. Expected refitted estimator with accuracy greater than 0.95, but got %v", s)
+	}
+	if _, err := GridSearchCV(treeFactory, Grid{"depth": {}}, x, y, SearchConfig{}); err != ErrGridNotValid {
+		t.Errorf("GridSearchCV failed. Expected %v, but got %v", ErrGridNotValid, err)
+	}
+	if _, err := GridSearchCV(treeFactory, Grid{"depth": {1}, "leaf": {1}}, x, y, SearchConfig{Metric: "f1"}); err != ErrMetricNotFound {
+		t.Errorf("GridSearchCV failed. Expected %v, but got %v", ErrMetricNotFound, err)
+	}
+}
+
+func TestRandomSearchCV(t *testing.T) {
+	x, y := blobs()
+	result, err := RandomSearchCV(treeFactory, map[string]Distribution{
+		"depth": IntUniform(1, 5),
+		"leaf":  Choice(1, 2, 5),
+	}, x, y, SearchConfig{Iterations: 8, Workers: 4, Seed: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Candidates) != 8 {
+		t.Fatalf("RandomSearchCV failed. Expected 8 candidates, but got %v", len(result.Candidates))
+	}
+	for _, c := range result.Candidates {
+		if d := c.Params.Int("depth"); d < 1 || d > 5 {
+			t.Errorf("RandomSearchCV failed. Expected depth in [1, 5], but got %v", d)
+		}
+	}
+	if result.BestScore < 0.9 {
+		t.Errorf("RandomSearchCV failed. Expected score greater than 0.9, but got %v", result.BestScore)
+	}
+}
+
+func TestSearchContext(t *testing.T) {
+	x, y := blobs()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var reports []progress.Report
+	_, err := GridSearchCVContext(ctx, treeFactory, Grid{"depth": {1, 2, 3, 4}, "leaf": {1, 5}}, x, y, SearchConfig{
+		Progress: func(r progress.Report) {
+			reports = append(reports, r)
+			if r.Done == 2 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GridSearchCVContext failed. Expected %v, but got %v", context.Canceled, err)
+	}
+	if len(reports) < 2 || len(reports) > 3 || reports[0].Total != 8 {
+		t.Errorf("Progress failed. Expected search stopped after 2 of 8 configurations, but got %v", reports)
+	}
+	reports = nil
+	if _, err := RandomSearchCVContext(context.Background(), treeFactory, map[string]Distribution{"depth": IntUniform(1, 3), "leaf": Choice(1)}, x, y, SearchConfig{
+		Iterations: 3,
+		Workers:    2,
+		Progress: func(r progress.Report) {
+			reports = append(reports, r)
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 3 || reports[2].Percent != 100 {
+		t.Errorf("Progress failed. Expected 3 reports, but got %v", reports)
+	}
+}
@@ -0,0 +1,169 @@
+// Package selection implements model selection: cross validation over folds of samples and search of
+// hyperparameters of estimators
+package selection
+
+import (
+	"errors"
+	"math/rand"
+)
+
+var (
+	ErrFoldsNotValid  = errors.New("number of folds is lesser than two")
+	ErrNotEnoughData  = errors.New("there are less samples than folds")
+	ErrLabelsMismatch = errors.New("number of labels doesn't match number of samples")
+)
+
+// Indices of train and test samples of a fold
+type Fold struct {
+	Train []int
+	Test  []int
+}
+
+// Generator of folds of n samples labeled by y, y is only used by splitters that need labels
+type Splitter interface {
+	Split(n int, y []any) ([]Fold, error)
+}
+
+func checkFolds(k int) {
+	if k < 2 {
+		panic(ErrFoldsNotValid)
+	}
+}
+
+func permutation(n int, shuffle bool, seed int64) []int {
+	if shuffle {
+		return rand.New(rand.NewSource(seed)).Perm(n)
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// folds where parts[i] is the test set of fold i and the remaining parts are the train set
+func complement(parts [][]int) []Fold {
+	folds := make([]Fold, len(parts))
+	for i, test := range parts {
+		train := make([]int, 0)
+		for j, part := range parts {
+			if j != i {
+				train = append(train, part...)
+			}
+		}
+		folds[i] = Fold{Train: train, Test: test}
+	}
+	return folds
+}
+
+type kFold struct {
+	k       int
+	shuffle bool
+	seed    int64
+}
+
+// Create k-fold splitter, samples are split in k consecutive parts of almost the same size, shuffled before when
+// shuffle is true
+func NewKFold(k int, shuffle bool, seed int64) Splitter {
+	checkFolds(k)
+	return &kFold{k: k, shuffle: shuffle, seed: seed}
+}
+
+func (kf *kFold) Split(n int, y []any) ([]Fold, error) {
+	if n < kf.k {
+		return nil, ErrNotEnoughData
+	}
+	order := permutation(n, kf.shuffle, kf.seed)
+	parts := make([][]int, kf.k)
+	start := 0
+	for i := range parts {
+		size := n / kf.k
+		if i < n%kf.k {
+			size++
+		}
+		parts[i] = order[start : start+size]
+		start += size
+	}
+	return complement(parts), nil
+}
+
+type stratifiedKFold struct {
+	k       int
+	shuffle bool
+	seed    int64
+}
+
+// Create stratified k-fold splitter, every label keeps its proportion in every fold, labels must be comparable
+func NewStratifiedKFold(k int, shuffle bool, seed int64) Splitter {
+	checkFolds(k)
+	return &stratifiedKFold{k: k, shuffle: shuffle, seed: seed}
+}
+
+func (sk *stratifiedKFold) Split(n int, y []any) ([]Fold, error) {
+	if len(y) != n {
+		return nil, ErrLabelsMismatch
+	}
+	if n < sk.k {
+		return nil, ErrNotEnoughData
+	}
+	// samples of every label are dealt to parts in turn, continuing where the previous label stopped
+	parts := make([][]int, sk.k)
+	ids := make(map[any]int)
+	groups := make([][]int, 0, 10)
+	for _, i := range permutation(n, sk.shuffle, sk.seed) {
+		id, ok := ids[y[i]]
+		if !ok {
+			id = len(groups)
+			ids[y[i]] = id
+			groups = append(groups, nil)
+		}
+		groups[id] = append(groups[id], i)
+	}
+	next := 0
+	for _, group := range groups {
+		for _, i := range group {
+			parts[next] = append(parts[next], i)
+			next = (next + 1) % sk.k
+		}
+	}
+	return complement(parts), nil
+}
+
+type timeSeriesSplit struct {
+	k        int
+	maxTrain int
+	gap      int
+}
+
+// Create time series splitter, samples must be in time order and every fold trains with samples before its test
+// samples, so the train set grows with every fold. Train sets are limited to the last maxTrain samples if maxTrain
+// is greater than zero, and gap samples are skipped between train and test samples.
+func NewTimeSeriesSplit(k, maxTrain, gap int) Splitter {
+	checkFolds(k)
+	if gap < 0 {
+		gap = 0
+	}
+	return &timeSeriesSplit{k: k, maxTrain: maxTrain, gap: gap}
+}
+
+func (ts *timeSeriesSplit) Split(n int, y []any) ([]Fold, error) {
+	// n samples are split in k+1 parts, the first one is only used for training
+	size := (n - ts.gap) / (ts.k + 1)
+	if size == 0 {
+		return nil, ErrNotEnoughData
+	}
+	folds := make([]Fold, ts.k)
+	for i := range folds {
+		testStart := n - (ts.k-i)*size
+		trainEnd := testStart - ts.gap
+		trainStart := 0
+		if ts.maxTrain > 0 && trainEnd > ts.maxTrain {
+			trainStart = trainEnd - ts.maxTrain
+		}
+		folds[i] = Fold{Train: permutation(trainEnd, false, 0)[trainStart:], Test: make([]int, size)}
+		for j := range folds[i].Test {
+			folds[i].Test[j] = testStart + j
+		}
+	}
+	return folds, nil
+}
@@ -0,0 +1,84 @@
+package selection
+
+import (
+	"sort"
+	"testing"
+)
+
+func checkPartition(t *testing.T, folds []Fold, n int) {
+	seen := make([]int, n)
+	for _, f := range folds {
+		if len(f.Train)+len(f.Test) != n {
+			t.Errorf("Split failed. Expected %v samples by fold, but got %v", n, len(f.Train)+len(f.Test))
+		}
+		for _, i := range f.Test {
+			seen[i]++
+		}
+	}
+	for i, s := range seen {
+		if s != 1 {
+			t.Errorf("Split failed. Expected sample %v in one test set, but it is in %v", i, s)
+		}
+	}
+}
+
+func TestKFold(t *testing.T) {
+	folds, err := NewKFold(3, true, 1).Split(10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folds) != 3 {
+		t.Fatalf("KFold failed. Expected 3 folds, but got %v", len(folds))
+	}
+	checkPartition(t, folds, 10)
+	if _, err := NewKFold(5, false, 0).Split(3, nil); err != ErrNotEnoughData {
+		t.Errorf("KFold failed. Expected %v, but got %v", ErrNotEnoughData, err)
+	}
+}
+
+func TestStratifiedKFold(t *testing.T) {
+	y := make([]any, 40)
+	for i := range y {
+		y[i] = "a"
+		if i%4 == 0 {
+			y[i] = "b"
+		}
+	}
+	folds, err := NewStratifiedKFold(5, true, 1).Split(len(y), y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPartition(t, folds, len(y))
+	for _, f := range folds {
+		b := 0
+		for _, i := range f.Test {
+			if y[i] == "b" {
+				b++
+			}
+		}
+		if b != 2 {
+			t.Errorf("StratifiedKFold failed. Expected 2 samples of label b by fold, but got %v", b)
+		}
+	}
+}
+
+func TestTimeSeriesSplit(t *testing.T) {
+	folds, err := NewTimeSeriesSplit(3, 4, 1).Split(13, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range folds {
+		if !sort.IntsAreSorted(f.Train) || !sort.IntsAreSorted(f.Test) {
+			t.Errorf("TimeSeriesSplit failed. Expected samples in time order, but got %v", f)
+		}
+		if f.Train[len(f.Train)-1]+1 >= f.Test[0] {
+			t.Errorf("TimeSeriesSplit failed. Expected a gap before test samples, but got %v", f)
+		}
+		if len(f.Train) > 4 {
+			t.Errorf("TimeSeriesSplit failed. Expected at most 4 train samples, but got %v", len(f.Train))
+		}
+	}
+	if last := folds[2].Test; last[len(last)-1] != 12 {
+		t.Errorf("TimeSeriesSplit failed. Expected last test sample 12, but got %v", last)
+	}
+}
@@ -0,0 +1,104 @@
+// Package compress makes smaller versions of trained networks by int8 quantization and magnitude pruning of weights
+package compress
+
+import (
+	"errors"
+
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+)
+
+var (
+	ErrSparsityNotValid  = errors.New("sparsity is not in range [0, 1]")
+	ErrMaskMismatch      = errors.New("mask length doesn't match parameters")
+	ErrLayerNotSupported = errors.New("layer with parameters is not a dense layer")
+	ErrNoTargets         = errors.New("validation batch has no targets")
+	ErrNoSamples         = errors.New("validation loader has no samples")
+)
+
+// Network computing outputs of inputs, like layers.Sequential and Quantized
+type Network interface {
+	Forward(x []float64) []float64
+}
+
+// Accuracy of the class of the greatest output, targets of loader have the class as the only element.
+// It returns ErrNoSamples if loader is empty
+func Accuracy(net Network, loader *data.DataLoader) (float64, error) {
+	hits, count := 0, 0
+	it := loader.Iter()
+	defer it.Close()
+	for it.Next() {
+		b := it.Batch()
+		if b.Targets == nil {
+			return 0, ErrNoTargets
+		}
+		targets := data.Rows(b.Targets)
+		for i, x := range data.Rows(b.Inputs) {
+			if argmax(net.Forward(x)) == int(targets[i][0]) {
+				hits++
+			}
+			count++
+		}
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, ErrNoSamples
+	}
+	return float64(hits) / float64(count), nil
+}
+
+func argmax(v []float64) int {
+	best := 0
+	for i, x := range v {
+		if x > v[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// Comparison of a compressed network with its original network
+type Report struct {
+	Accuracy           float64 //accuracy of original network
+	CompressedAccuracy float64 //accuracy of compressed network
+	Delta              float64 //accuracy of compressed network minus accuracy of original network
+	Bytes              int     //bytes of parameters of original network
+	CompressedBytes    int     //bytes of parameters of compressed network
+	Sparsity           float64 //fraction of zero parameters of compressed network
+}
+
+// Compare accuracy and size of compressed network with original network on validation loader
+func Compare(original *layers.Sequential, compressed Network, validation *data.DataLoader) (*Report, error) {
+	acc, err := Accuracy(original, validation)
+	if err != nil {
+		return nil, err
+	}
+	cacc, err := Accuracy(compressed, validation)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{Accuracy: acc, CompressedAccuracy: cacc, Delta: cacc - acc, Bytes: 8 * len(original.Params())}
+	switch c := compressed.(type) {
+	case *layers.Sequential:
+		r.CompressedBytes, r.Sparsity = 8*len(c.Params()), zeros(c.Params())
+	case *Quantized:
+		r.CompressedBytes, r.Sparsity = c.Bytes(), c.Sparsity()
+	}
+	return r, nil
+}
+
+// fraction of zero values
+func zeros(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	n := 0
+	for _, v := range values {
+		if v == 0 {
+			n++
+		}
+	}
+	return float64(n) / float64(len(values))
+}
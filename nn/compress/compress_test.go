@@ -0,0 +1,66 @@
+package compress
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+// two gaussian blobs with class targets
+func blobs(n int, seed int64) *data.DataLoader {
+	ds := dataset.ClassDataset(dataset.MakeBlobs(n, []knn.Point{{-1, 1}, {1, -1}}, 0.5, seed))
+	return data.NewDataLoader(ds, data.LoaderConfig{BatchSize: 16})
+}
+
+// trained network of blobs
+func trained(t *testing.T) *layers.Sequential {
+	net := layers.NewSequential(1, layers.NewDense(2, 16), layers.NewReLU(), layers.NewDense(16, 2))
+	if _, err := train.NewTrainer(net, optim.NewAdam(0.05), train.CrossEntropy).Fit(blobs(128, 1), 20); err != nil {
+		t.Fatal(err)
+	}
+	return net
+}
+
+func TestCompare(t *testing.T) {
+	net := trained(t)
+	validation := blobs(200, 2)
+	acc, err := Accuracy(net, validation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc < 0.9 {
+		t.Fatalf("Accuracy failed. Expected accuracy greater than 0.9, but got %v", acc)
+	}
+	pruned, _ := Prune(net, 0.5, false)
+	report, err := Compare(net, pruned, validation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Accuracy != acc || report.Delta != report.CompressedAccuracy-acc || report.Bytes != 8*len(net.Params()) {
+		t.Errorf("Compare failed. Unexpected report %+v", report)
+	}
+	// half of 64 weights are pruned, biases are kept if they aren't zero
+	if report.Sparsity < 32.0/float64(len(net.Params())) || report.CompressedBytes != report.Bytes {
+		t.Errorf("Compare failed. Expected sparsity of pruned weights, but got %+v", report)
+	}
+	report, err = Compare(net, Quantize(net), validation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.CompressedBytes >= report.Bytes || report.Delta < -0.02 {
+		t.Errorf("Compare failed. Expected smaller quantized network with the same accuracy, but got %+v", report)
+	}
+	unlabeled := data.NewDataLoader(data.NewSliceDataset([][]float64{{0, 0}}, nil), data.LoaderConfig{})
+	if _, err := Compare(net, pruned, unlabeled); err != ErrNoTargets {
+		t.Errorf("Compare failed. Expected %v, but got %v", ErrNoTargets, err)
+	}
+	empty := data.NewDataLoader(data.NewSliceDataset([][]float64{}, [][]float64{}), data.LoaderConfig{})
+	if _, err := Compare(net, pruned, empty); err != ErrNoSamples {
+		t.Errorf("Compare failed. Expected %v, but got %v", ErrNoSamples, err)
+	}
+}
@@ -0,0 +1,126 @@
+package compress
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+// Mask of parameters of a network, false parameters are pruned
+type Mask []bool
+
+// Set pruned parameters to zero
+func (m Mask) Apply(params []float64) {
+	if len(m) != len(params) {
+		panic(ErrMaskMismatch)
+	}
+	for i, keep := range m {
+		if !keep {
+			params[i] = 0
+		}
+	}
+}
+
+// Fraction of pruned parameters
+func (m Mask) Sparsity() float64 {
+	if len(m) == 0 {
+		return 0
+	}
+	n := 0
+	for _, keep := range m {
+		if !keep {
+			n++
+		}
+	}
+	return float64(n) / float64(len(m))
+}
+
+// Prune weights of dense layers with the least magnitude and return a pruned copy of network and its mask.
+// Sparsity is the fraction of pruned weights of every layer if perLayer or of the weights of every layer together
+// otherwise, biases are not pruned
+func Prune(net *layers.Sequential, sparsity float64, perLayer bool) (*layers.Sequential, Mask) {
+	if sparsity < 0 || sparsity > 1 {
+		panic(ErrSparsityNotValid)
+	}
+	pruned := net.Clone()
+	params := pruned.Params()
+	mask := make(Mask, len(params))
+	for i := range mask {
+		mask[i] = true
+	}
+	// indices of weights of every dense layer in params
+	var groups [][]int
+	offset := 0
+	for _, l := range pruned.Layers() {
+		if d, ok := l.(*layers.Dense); ok {
+			weights := make([]int, len(d.Weights()))
+			for i := range weights {
+				weights[i] = offset + i
+			}
+			groups = append(groups, weights)
+		}
+		offset += l.Size()
+	}
+	if !perLayer {
+		var all []int
+		for _, g := range groups {
+			all = append(all, g...)
+		}
+		groups = [][]int{all}
+	}
+	for _, g := range groups {
+		sort.SliceStable(g, func(a, b int) bool {
+			return math.Abs(params[g[a]]) < math.Abs(params[g[b]])
+		})
+		for _, i := range g[:int(sparsity*float64(len(g)))] {
+			mask[i] = false
+		}
+	}
+	mask.Apply(params)
+	return pruned, mask
+}
+
+// Optimizer that keeps pruned parameters zero, like in fine-tuning of pruned networks
+type MaskedOptimizer struct {
+	opt  optim.Optimizer
+	mask Mask
+}
+
+// Create optimizer applying mask after every step of opt
+func NewMaskedOptimizer(opt optim.Optimizer, mask Mask) *MaskedOptimizer {
+	return &MaskedOptimizer{opt: opt, mask: mask}
+}
+
+// Update parameters with gradients and prune them again
+func (mo *MaskedOptimizer) Step(params, grads []float64) {
+	mo.opt.Step(params, grads)
+	mo.mask.Apply(params)
+}
+
+// Reset state of optimizer
+func (mo *MaskedOptimizer) Reset() {
+	mo.opt.Reset()
+}
+
+// Encode state of the wrapped optimizer for checkpoints, the mask isn't encoded so it must be the same on resume
+func (mo *MaskedOptimizer) GobEncode() ([]byte, error) {
+	enc, ok := mo.opt.(gob.GobEncoder)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", train.ErrOptimizerNotSaved, mo.opt)
+	}
+	return enc.GobEncode()
+}
+
+// Decode state of the wrapped optimizer encoded by GobEncode
+func (mo *MaskedOptimizer) GobDecode(b []byte) error {
+	dec, ok := mo.opt.(gob.GobDecoder)
+	if !ok {
+		return fmt.Errorf("%w: %T", train.ErrOptimizerNotSaved, mo.opt)
+	}
+	return dec.GobDecode(b)
+}
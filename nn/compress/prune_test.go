@@ -0,0 +1,82 @@
+package compress
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+// pruned weights of every dense layer of network
+func prunedWeights(net *layers.Sequential) []int {
+	var counts []int
+	for _, l := range net.Layers() {
+		if d, ok := l.(*layers.Dense); ok {
+			n := 0
+			for _, w := range d.Weights() {
+				if w == 0 {
+					n++
+				}
+			}
+			counts = append(counts, n)
+		}
+	}
+	return counts
+}
+
+func TestPrune(t *testing.T) {
+	net := trained(t)
+	original := append([]float64(nil), net.Params()...)
+	pruned, mask := Prune(net, 0.25, true)
+	if counts := prunedWeights(pruned); counts[0] != 8 || counts[1] != 8 {
+		t.Errorf("Prune failed. Expected 8 pruned weights by layer, but got %v", counts)
+	}
+	if math.Abs(mask.Sparsity()-16.0/float64(len(mask))) > 1e-12 {
+		t.Errorf("Sparsity failed. Expected %v, but got %v", 16.0/float64(len(mask)), mask.Sparsity())
+	}
+	for i, p := range net.Params() {
+		if p != original[i] {
+			t.Fatalf("Prune failed. Original network was changed")
+		}
+	}
+	// pruned weights have the least magnitude of their layer
+	dense := pruned.Layers()[0].(*layers.Dense)
+	least := math.Inf(1)
+	for i, keep := range mask[:len(dense.Weights())] {
+		if keep {
+			least = math.Min(least, math.Abs(original[i]))
+		}
+	}
+	for i, keep := range mask[:len(dense.Weights())] {
+		if !keep && math.Abs(original[i]) > least {
+			t.Errorf("Prune failed. Weight %v was pruned before a weight of magnitude %v", original[i], least)
+		}
+	}
+	if _, mask := Prune(net, 0.5, false); mask.Sparsity() != 32.0/float64(len(mask)) {
+		t.Errorf("Prune failed. Expected 32 pruned weights, but got sparsity %v", mask.Sparsity())
+	}
+	// fine-tuning keeps pruned weights and it can be checkpointed
+	trainer := train.NewTrainer(pruned, NewMaskedOptimizer(optim.NewAdam(0.01), mask), train.CrossEntropy)
+	path := filepath.Join(t.TempDir(), "prune.ckpt")
+	trainer.Checkpoint(path, 1)
+	if _, err := trainer.Fit(blobs(64, 3), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := train.NewTrainer(pruned.Clone(), NewMaskedOptimizer(optim.NewAdam(0.01), mask), train.CrossEntropy).Resume(path); err != nil {
+		t.Errorf("MaskedOptimizer failed. Expected resumed checkpoint, but got %v", err)
+	}
+	for i, keep := range mask {
+		if !keep && pruned.Params()[i] != 0 {
+			t.Fatalf("MaskedOptimizer failed. Pruned parameter %d is %v", i, pruned.Params()[i])
+		}
+	}
+	defer func() {
+		if recover() != ErrSparsityNotValid {
+			t.Errorf("Prune failed. Expected panic %v", ErrSparsityNotValid)
+		}
+	}()
+	Prune(net, 1.5, false)
+}
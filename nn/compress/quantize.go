@@ -0,0 +1,132 @@
+package compress
+
+import (
+	"math"
+
+	"github.com/stellviaproject/go-ia/nn/layers"
+)
+
+// Network whose dense layers have int8 weights, every output of a layer has its own scale so a weight is its
+// scale times its int8 value. Biases are float64 and inputs of layers are not quantized.
+// Forward keeps state like layers.Sequential, so concurrent calls need their own quantized network
+type Quantized struct {
+	net   *layers.Sequential //layers without parameters are applied by this network
+	dense []*quantizedDense  //nil for layers without parameters
+}
+
+type quantizedDense struct {
+	in, out int
+	w       []int8 //weights by rows of outputs
+	scale   []float64
+	b       []float64
+}
+
+// Quantize weights of dense layers of trained network to int8 symmetrically around zero, network must have only
+// dense layers and layers without parameters
+func Quantize(net *layers.Sequential) *Quantized {
+	q := &Quantized{net: net.Clone(), dense: make([]*quantizedDense, len(net.Layers()))}
+	for k, l := range net.Layers() {
+		d, ok := l.(*layers.Dense)
+		if !ok {
+			if l.Size() != 0 {
+				panic(ErrLayerNotSupported)
+			}
+			continue
+		}
+		qd := &quantizedDense{in: d.In, out: d.Out, w: make([]int8, len(d.Weights())), scale: make([]float64, d.Out),
+			b: append([]float64(nil), d.Bias()...)}
+		for o := 0; o < d.Out; o++ {
+			row := d.Weights()[o*d.In : (o+1)*d.In]
+			top := 0.0
+			for _, w := range row {
+				top = math.Max(top, math.Abs(w))
+			}
+			if top == 0 {
+				continue
+			}
+			qd.scale[o] = top / 127
+			for i, w := range row {
+				qd.w[o*d.In+i] = int8(math.Round(w / qd.scale[o]))
+			}
+		}
+		q.dense[k] = qd
+	}
+	return q
+}
+
+// Output of network for input x
+func (q *Quantized) Forward(x []float64) []float64 {
+	for k, l := range q.net.Layers() {
+		d := q.dense[k]
+		if d == nil {
+			x = l.Forward(x)
+			continue
+		}
+		if len(x) != d.in {
+			panic(layers.ErrInputMismatch)
+		}
+		y := make([]float64, d.out)
+		for o := range y {
+			sum := 0.0
+			for i, v := range x {
+				sum += float64(d.w[o*d.in+i]) * v
+			}
+			y[o] = d.b[o] + d.scale[o]*sum
+		}
+		x = y
+	}
+	return x
+}
+
+// Bytes of parameters, one by weight and eight by scale and bias
+func (q *Quantized) Bytes() int {
+	n := 0
+	for _, d := range q.dense {
+		if d != nil {
+			n += len(d.w) + 8*len(d.scale) + 8*len(d.b)
+		}
+	}
+	return n
+}
+
+// Fraction of zero weights and biases
+func (q *Quantized) Sparsity() float64 {
+	zero, total := 0, 0
+	for _, d := range q.dense {
+		if d == nil {
+			continue
+		}
+		for _, w := range d.w {
+			if w == 0 {
+				zero++
+			}
+		}
+		for _, b := range d.b {
+			if b == 0 {
+				zero++
+			}
+		}
+		total += len(d.w) + len(d.b)
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(zero) / float64(total)
+}
+
+// Network of float64 weights equal to quantized weights, like to fine-tune or serve it with float64 networks
+func (q *Quantized) Dequantize() *layers.Sequential {
+	net := q.net.Clone()
+	for k, l := range net.Layers() {
+		d := q.dense[k]
+		if d == nil {
+			continue
+		}
+		dense := l.(*layers.Dense)
+		for i, w := range d.w {
+			dense.Weights()[i] = d.scale[i/d.in] * float64(w)
+		}
+		copy(dense.Bias(), d.b)
+	}
+	return net
+}
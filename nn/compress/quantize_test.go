@@ -0,0 +1,41 @@
+package compress
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/layers"
+)
+
+func TestQuantize(t *testing.T) {
+	net := trained(t)
+	q := Quantize(net)
+	if b := q.Bytes(); b != 64+8*18+8*18 {
+		t.Errorf("Bytes failed. Expected %v, but got %v", 64+8*18+8*18, b)
+	}
+	// weights differ at most half a step of their scale
+	deq := q.Dequantize()
+	for k, l := range net.Layers() {
+		d, ok := l.(*layers.Dense)
+		if !ok {
+			continue
+		}
+		qd := q.dense[k]
+		dd := deq.Layers()[k].(*layers.Dense)
+		for i, w := range d.Weights() {
+			if math.Abs(dd.Weights()[i]-w) > qd.scale[i/d.In]/2+1e-12 {
+				t.Fatalf("Dequantize failed. Weight %v was quantized to %v with scale %v", w, dd.Weights()[i], qd.scale[i/d.In])
+			}
+		}
+	}
+	x := []float64{0.3, -0.7}
+	expected, got := deq.Forward(x), q.Forward(x)
+	for i := range expected {
+		if math.Abs(expected[i]-got[i]) > 1e-9 {
+			t.Errorf("Forward failed. Expected %v, but got %v", expected, got)
+		}
+	}
+	if q.Sparsity() > 0.5 {
+		t.Errorf("Sparsity failed. Expected few zero weights, but got %v", q.Sparsity())
+	}
+}
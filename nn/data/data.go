@@ -0,0 +1,173 @@
+// Package data implements datasets of tensors and loaders of batches for training
+package data
+
+import (
+	"errors"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrIndexOutOfRange = errors.New("item index is out of range")
+	ErrLengthMismatch  = errors.New("inputs and targets have different lengths")
+	ErrEmptyBatch      = errors.New("batch has no tensors")
+	ErrClosed          = errors.New("stream iterator is closed")
+)
+
+// Item of dataset, target is nil for unlabeled data
+type Item struct {
+	Input  *graph.Tensor
+	Target *graph.Tensor
+}
+
+// Dataset of items with random access
+type Dataset interface {
+	Len() int
+	GetItem(i int) (Item, error)
+}
+
+type tensorDataset struct {
+	inputs, targets *graph.Tensor
+}
+
+// Create dataset whose items are slices of the first dimension of inputs and targets, targets may be nil
+func NewTensorDataset(inputs, targets *graph.Tensor) Dataset {
+	if targets != nil && targets.Shape()[0] != inputs.Shape()[0] {
+		panic(ErrLengthMismatch)
+	}
+	return &tensorDataset{inputs: inputs, targets: targets}
+}
+
+func (td *tensorDataset) Len() int {
+	return td.inputs.Shape()[0]
+}
+
+func (td *tensorDataset) GetItem(i int) (Item, error) {
+	if i < 0 || i >= td.Len() {
+		return Item{}, ErrIndexOutOfRange
+	}
+	item := Item{Input: Slice(td.inputs, i)}
+	if td.targets != nil {
+		item.Target = Slice(td.targets, i)
+	}
+	return item, nil
+}
+
+type sliceDataset struct {
+	inputs, targets [][]float64
+}
+
+// Create dataset of float64 vectors, targets may be nil
+func NewSliceDataset(inputs, targets [][]float64) Dataset {
+	if targets != nil && len(targets) != len(inputs) {
+		panic(ErrLengthMismatch)
+	}
+	return &sliceDataset{inputs: inputs, targets: targets}
+}
+
+func (sd *sliceDataset) Len() int {
+	return len(sd.inputs)
+}
+
+func (sd *sliceDataset) GetItem(i int) (Item, error) {
+	if i < 0 || i >= len(sd.inputs) {
+		return Item{}, ErrIndexOutOfRange
+	}
+	item := Item{Input: Vector(sd.inputs[i])}
+	if sd.targets != nil {
+		item.Target = Vector(sd.targets[i])
+	}
+	return item, nil
+}
+
+// Float64 tensor of shape (len(v)) with a copy of v
+func Vector(v []float64) *graph.Tensor {
+	return graph.NewTensor(append([]float64(nil), v...), graph.Float64, graph.NewShape(len(v)))
+}
+
+// visit every index of shape in row major order
+func eachIndex(shape graph.Shape, fn func(index []int)) {
+	index := make([]int, len(shape))
+	for {
+		fn(index)
+		k := len(index) - 1
+		for ; k >= 0; k-- {
+			if index[k]++; index[k] < shape[k] {
+				break
+			}
+			index[k] = 0
+		}
+		if k < 0 {
+			return
+		}
+	}
+}
+
+// Tensor of element i of the first dimension of t, a rank 1 tensor gives a tensor of shape (1)
+func Slice(t *graph.Tensor, i int) *graph.Tensor {
+	shape := t.Shape()
+	if i < 0 || i >= shape[0] {
+		panic(ErrIndexOutOfRange)
+	}
+	sub := shape[1:]
+	if len(sub) == 0 {
+		sub = graph.NewShape(1)
+	}
+	out := graph.NewTensor(nil, t.Type(), sub)
+	src := make([]int, len(shape))
+	src[0] = i
+	eachIndex(shape[1:], func(index []int) {
+		copy(src[1:], index)
+		dst := index
+		if len(dst) == 0 {
+			dst = []int{0}
+		}
+		out.Set(dst, t.Get(src))
+	})
+	return out
+}
+
+// Stack tensors of equal shape and type in a tensor with a new first dimension
+func Stack(ts []*graph.Tensor) *graph.Tensor {
+	if len(ts) == 0 {
+		panic(ErrEmptyBatch)
+	}
+	shape := ts[0].Shape()
+	out := graph.NewTensor(nil, ts[0].Type(), append(graph.NewShape(len(ts)), shape...))
+	dst := make([]int, len(shape)+1)
+	for n, t := range ts {
+		if !t.Shape().Equal(shape) || t.Type() != ts[0].Type() {
+			panic(graph.ErrDimMismatch)
+		}
+		dst[0] = n
+		eachIndex(shape, func(index []int) {
+			copy(dst[1:], index)
+			out.Set(dst, t.Get(index))
+		})
+	}
+	return out
+}
+
+// Elements of every item of the first dimension of t as float64 vectors in row major order
+func Rows(t *graph.Tensor) [][]float64 {
+	shape := t.Shape()
+	rows := make([][]float64, shape[0])
+	src := make([]int, len(shape))
+	for i := range rows {
+		src[0] = i
+		row := make([]float64, 0, shape.Len()/shape[0])
+		eachIndex(shape[1:], func(index []int) {
+			copy(src[1:], index)
+			switch v := t.Get(src).(type) {
+			case float32:
+				row = append(row, float64(v))
+			case float64:
+				row = append(row, v)
+			default:
+				row = append(row, t.GetF16At(src).ToF64())
+			}
+		})
+		rows[i] = row
+	}
+	return rows
+}
@@ -0,0 +1,39 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+func TestTensorDataset(t *testing.T) {
+	inputs := graph.NewTensor(nil, graph.Float32, graph.NewShape(3, 2))
+	for i := 0; i < 3; i++ {
+		inputs.SetF32([]int{i, 0}, float32(i))
+		inputs.SetF32([]int{i, 1}, float32(10*i))
+	}
+	targets := graph.NewTensor([]float64{7, 8, 9}, graph.Float64, graph.NewShape(3))
+	ds := NewTensorDataset(inputs, targets)
+	item, err := ds.GetItem(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := item.Input.GetF32At([]int{1}); v != 20 || item.Target.GetF64At([]int{0}) != 9 {
+		t.Errorf("GetItem failed. Unexpected item %v %v", item.Input, item.Target)
+	}
+	if _, err := ds.GetItem(3); err != ErrIndexOutOfRange {
+		t.Errorf("GetItem failed. Expected %v, but got %v", ErrIndexOutOfRange, err)
+	}
+}
+
+func TestStackRows(t *testing.T) {
+	a, b := Vector([]float64{1, 2}), Vector([]float64{3, 4})
+	stacked := Stack([]*graph.Tensor{a, b})
+	if rows := Rows(stacked); !reflect.DeepEqual(rows, [][]float64{{1, 2}, {3, 4}}) {
+		t.Errorf("Stack failed. Expected [[1 2] [3 4]], but got %v", rows)
+	}
+	if s := Slice(stacked, 1); !s.Equal(b) {
+		t.Errorf("Slice failed. Expected %v, but got %v", b, s)
+	}
+}
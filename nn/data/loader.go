@@ -0,0 +1,254 @@
+package data
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Batch of stacked items
+type Batch struct {
+	Inputs  *graph.Tensor //shape (size, input shape...)
+	Targets *graph.Tensor //shape (size, target shape...) or nil for unlabeled data
+	Indices []int         //indices of items in dataset
+}
+
+// Size of batch
+func (b Batch) Len() int {
+	return len(b.Indices)
+}
+
+// Configuration of data loader
+type LoaderConfig struct {
+	BatchSize int  //items by batch, 32 by default
+	Shuffle   bool //shuffle items every epoch
+	DropLast  bool //drop the last batch when it is smaller than BatchSize
+	Workers   int  //goroutines building batches, batches are built on Next if zero
+	Prefetch  int  //batches built ahead by workers, 2 by worker by default
+	Seed      int64
+}
+
+// DataLoader yields batches of a dataset
+type DataLoader struct {
+	ds     Dataset
+	config LoaderConfig
+	rnd    *rand.Rand
+	epochs int
+	mu     sync.Mutex
+}
+
+// Create data loader of dataset
+func NewDataLoader(ds Dataset, config LoaderConfig) *DataLoader {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 32
+	}
+	if config.Workers < 0 {
+		config.Workers = 0
+	}
+	if config.Prefetch <= 0 {
+		config.Prefetch = 2 * config.Workers
+	}
+	return &DataLoader{ds: ds, config: config, rnd: rand.New(rand.NewSource(config.Seed))}
+}
+
+// Dataset of loader
+func (dl *DataLoader) Dataset() Dataset {
+	return dl.ds
+}
+
+// Number of batches by epoch
+func (dl *DataLoader) Len() int {
+	n := dl.ds.Len()
+	if dl.config.DropLast {
+		return n / dl.config.BatchSize
+	}
+	return (n + dl.config.BatchSize - 1) / dl.config.BatchSize
+}
+
+// Number of epochs started by Iter, it is the state of shuffling saved in checkpoints
+func (dl *DataLoader) Epochs() int {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+	return dl.epochs
+}
+
+// Restore state of shuffling after epochs, the next epoch yields the same batches as after starting epochs with Iter
+func (dl *DataLoader) Seek(epochs int) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+	dl.rnd = rand.New(rand.NewSource(dl.config.Seed))
+	if dl.config.Shuffle {
+		n := dl.ds.Len()
+		for e := 0; e < epochs; e++ {
+			dl.rnd.Shuffle(n, func(i, j int) {})
+		}
+	}
+	dl.epochs = epochs
+}
+
+// indices of items of every batch of an epoch
+func (dl *DataLoader) epoch() [][]int {
+	n := dl.ds.Len()
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	dl.mu.Lock()
+	if dl.config.Shuffle {
+		dl.rnd.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	dl.epochs++
+	dl.mu.Unlock()
+	batches := make([][]int, 0, dl.Len())
+	for start := 0; start < n; start += dl.config.BatchSize {
+		end := start + dl.config.BatchSize
+		if end > n {
+			if dl.config.DropLast {
+				break
+			}
+			end = n
+		}
+		batches = append(batches, order[start:end])
+	}
+	return batches
+}
+
+// build batch of items
+func (dl *DataLoader) build(indices []int) (Batch, error) {
+	items := make([]Item, len(indices))
+	for k, i := range indices {
+		item, err := dl.ds.GetItem(i)
+		if err != nil {
+			return Batch{}, err
+		}
+		items[k] = item
+	}
+	return collate(items, indices)
+}
+
+// stack inputs and targets of items, every item or none of them must have target
+func collate(items []Item, indices []int) (Batch, error) {
+	inputs := make([]*graph.Tensor, len(items))
+	targets := make([]*graph.Tensor, 0, len(items))
+	for k, item := range items {
+		inputs[k] = item.Input
+		if item.Target != nil {
+			targets = append(targets, item.Target)
+		}
+	}
+	if len(targets) != 0 && len(targets) != len(items) {
+		return Batch{}, ErrLengthMismatch
+	}
+	batch := Batch{Inputs: Stack(inputs), Indices: indices}
+	if len(targets) != 0 {
+		batch.Targets = Stack(targets)
+	}
+	return batch, nil
+}
+
+// Iterator of batches, implemented by iterators of data loaders and streams
+type Batches interface {
+	Next() bool
+	Batch() Batch
+	Err() error
+	Close()
+}
+
+type result struct {
+	batch Batch
+	err   error
+}
+
+// Iterator over batches of an epoch
+type Iterator struct {
+	dl      *DataLoader
+	batches [][]int
+	pos     int
+	pending chan chan result
+	done    chan struct{}
+	once    sync.Once
+	batch   Batch
+	err     error
+}
+
+// Start an epoch, items are shuffled again if the loader shuffles
+func (dl *DataLoader) Iter() *Iterator {
+	it := &Iterator{dl: dl, batches: dl.epoch(), done: make(chan struct{})}
+	if dl.config.Workers == 0 {
+		return it
+	}
+	// batches are requested in order and built by workers, prefetch bounds batches in flight
+	type job struct {
+		indices []int
+		out     chan result
+	}
+	jobs := make(chan job)
+	it.pending = make(chan chan result, dl.config.Prefetch)
+	for w := 0; w < dl.config.Workers; w++ {
+		go func() {
+			for j := range jobs {
+				b, err := dl.build(j.indices)
+				j.out <- result{batch: b, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(it.pending)
+		defer close(jobs)
+		for _, indices := range it.batches {
+			out := make(chan result, 1)
+			select {
+			case it.pending <- out:
+			case <-it.done:
+				return
+			}
+			select {
+			case jobs <- job{indices: indices, out: out}:
+			case <-it.done:
+				return
+			}
+		}
+	}()
+	return it
+}
+
+// Advance to next batch, it returns false at the end of epoch or after an error
+func (it *Iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.pending == nil {
+		if it.pos >= len(it.batches) {
+			return false
+		}
+		it.batch, it.err = it.dl.build(it.batches[it.pos])
+		it.pos++
+		return it.err == nil
+	}
+	out, ok := <-it.pending
+	if !ok {
+		return false
+	}
+	r := <-out
+	it.batch, it.err = r.batch, r.err
+	if it.err != nil {
+		it.Close()
+	}
+	return it.err == nil
+}
+
+// Current batch
+func (it *Iterator) Batch() Batch {
+	return it.batch
+}
+
+// Error of dataset that stopped iteration
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Stop workers of an unfinished epoch
+func (it *Iterator) Close() {
+	it.once.Do(func() { close(it.done) })
+}
@@ -0,0 +1,126 @@
+package data
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func rangeDataset(n int) Dataset {
+	inputs := make([][]float64, n)
+	targets := make([][]float64, n)
+	for i := range inputs {
+		inputs[i] = []float64{float64(i), float64(-i)}
+		targets[i] = []float64{float64(i % 2)}
+	}
+	return NewSliceDataset(inputs, targets)
+}
+
+func TestDataLoader(t *testing.T) {
+	for _, workers := range []int{0, 3} {
+		dl := NewDataLoader(rangeDataset(10), LoaderConfig{BatchSize: 4, Shuffle: true, Workers: workers, Seed: 1})
+		if dl.Len() != 3 {
+			t.Fatalf("Len failed. Expected 3 batches, but got %v", dl.Len())
+		}
+		seen := make([]int, 0, 10)
+		sizes := make([]int, 0, 3)
+		it := dl.Iter()
+		for it.Next() {
+			b := it.Batch()
+			sizes = append(sizes, b.Len())
+			for k, row := range Rows(b.Inputs) {
+				if row[0] != float64(b.Indices[k]) {
+					t.Fatalf("Iter failed. Batch input %v doesn't match index %v", row, b.Indices[k])
+				}
+				seen = append(seen, int(row[0]))
+			}
+		}
+		if it.Err() != nil {
+			t.Fatal(it.Err())
+		}
+		if sizes[0] != 4 || sizes[2] != 2 || len(seen) != 10 {
+			t.Errorf("Iter failed. Unexpected batch sizes %v with %d workers", sizes, workers)
+		}
+		ordered := append([]int(nil), seen...)
+		sort.Ints(ordered)
+		for i, v := range ordered {
+			if v != i {
+				t.Fatalf("Iter failed. Items of epoch are not a permutation %v", seen)
+			}
+		}
+		if sort.IntsAreSorted(seen) {
+			t.Errorf("Iter failed. Expected shuffled items")
+		}
+	}
+}
+
+func TestDropLast(t *testing.T) {
+	dl := NewDataLoader(rangeDataset(10), LoaderConfig{BatchSize: 4, DropLast: true, Workers: 2})
+	count := 0
+	it := dl.Iter()
+	for it.Next() {
+		if it.Batch().Len() != 4 {
+			t.Errorf("Iter failed. Expected only full batches")
+		}
+		count++
+	}
+	if count != 2 || dl.Len() != 2 {
+		t.Errorf("Iter failed. Expected 2 batches, but got %v", count)
+	}
+	// closing an unfinished epoch stops workers
+	it = dl.Iter()
+	it.Next()
+	it.Close()
+}
+
+type failing struct {
+	Dataset
+}
+
+var errBroken = errors.New("broken item")
+
+func (f failing) GetItem(i int) (Item, error) {
+	if i == 5 {
+		return Item{}, errBroken
+	}
+	return f.Dataset.GetItem(i)
+}
+
+func TestLoaderError(t *testing.T) {
+	for _, workers := range []int{0, 2} {
+		it := NewDataLoader(failing{rangeDataset(10)}, LoaderConfig{BatchSize: 2, Workers: workers}).Iter()
+		batches := 0
+		for it.Next() {
+			batches++
+		}
+		if it.Err() != errBroken || batches != 2 {
+			t.Errorf("Iter failed. Expected %v after 2 batches, but got %v after %v", errBroken, it.Err(), batches)
+		}
+	}
+}
+
+func TestSeek(t *testing.T) {
+	indices := func(dl *DataLoader) []int {
+		var out []int
+		it := dl.Iter()
+		for it.Next() {
+			out = append(out, it.Batch().Indices...)
+		}
+		return out
+	}
+	dl := NewDataLoader(rangeDataset(10), LoaderConfig{BatchSize: 4, Shuffle: true, Seed: 2})
+	indices(dl)
+	indices(dl)
+	expected := indices(dl)
+	if dl.Epochs() != 3 {
+		t.Errorf("Epochs failed. Expected 3, but got %v", dl.Epochs())
+	}
+	restored := NewDataLoader(rangeDataset(10), LoaderConfig{BatchSize: 4, Shuffle: true, Seed: 2})
+	restored.Seek(2)
+	got := indices(restored)
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("Seek failed. Expected epoch %v, but got %v", expected, got)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package data
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Dataset of items arriving as a stream, Next returns io.EOF at the end of stream
+type IterableDataset interface {
+	Next(ctx context.Context) (Item, error)
+}
+
+type channelDataset struct {
+	ch <-chan Item
+}
+
+// Create stream of items received from channel until it is closed
+func NewChannelDataset(ch <-chan Item) IterableDataset {
+	return &channelDataset{ch: ch}
+}
+
+func (cd *channelDataset) Next(ctx context.Context) (Item, error) {
+	select {
+	case item, ok := <-cd.ch:
+		if !ok {
+			return Item{}, io.EOF
+		}
+		return item, nil
+	case <-ctx.Done():
+		return Item{}, ctx.Err()
+	}
+}
+
+type readerDataset struct {
+	scanner *bufio.Scanner
+	parse   func(line []byte) (Item, error)
+}
+
+// Create stream of items parsed from lines of reader, like files too large to index or standard input
+func NewReaderDataset(r io.Reader, parse func(line []byte) (Item, error)) IterableDataset {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &readerDataset{scanner: scanner, parse: parse}
+}
+
+func (rd *readerDataset) Next(ctx context.Context) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+	if !rd.scanner.Scan() {
+		if err := rd.scanner.Err(); err != nil {
+			return Item{}, err
+		}
+		return Item{}, io.EOF
+	}
+	return rd.parse(rd.scanner.Bytes())
+}
+
+// Configuration of stream loader
+type StreamConfig struct {
+	BatchSize int  //items by batch, 32 by default
+	Buffer    int  //items read ahead of batches, BatchSize by default
+	DropLast  bool //drop the last batch when it is smaller than BatchSize
+}
+
+// StreamIterator yields batches of an iterable dataset read by a goroutine into a bounded buffer
+type StreamIterator struct {
+	config  StreamConfig
+	items   chan Item
+	cancel  context.CancelFunc
+	ctx     context.Context
+	done    chan struct{} //closed by Close
+	stopped chan struct{} //closed when reading goroutine returns
+	once    sync.Once
+	mu      sync.Mutex
+	readErr error
+	count   int
+	batch   Batch
+	err     error
+}
+
+// Start reading stream, reading stops at the end of stream, on errors, when ctx is canceled or on Close
+func NewStreamIterator(ctx context.Context, ds IterableDataset, config StreamConfig) *StreamIterator {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 32
+	}
+	if config.Buffer <= 0 {
+		config.Buffer = config.BatchSize
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	it := &StreamIterator{config: config, items: make(chan Item, config.Buffer), cancel: cancel, ctx: ctx, done: make(chan struct{}), stopped: make(chan struct{})}
+	go func() {
+		defer close(it.stopped)
+		defer close(it.items)
+		for {
+			select {
+			case <-it.done:
+				return
+			default:
+			}
+			item, err := ds.Next(ctx)
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					it.mu.Lock()
+					it.readErr = err
+					it.mu.Unlock()
+				}
+				return
+			}
+			select {
+			case it.items <- item:
+			case <-it.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return it
+}
+
+// Advance to next batch, it returns false at the end of stream, after an error or after cancellation
+func (it *StreamIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	select {
+	case <-it.done:
+		it.err = ErrClosed
+		return false
+	default:
+	}
+	inputs := make([]Item, 0, it.config.BatchSize)
+	for len(inputs) < it.config.BatchSize {
+		select {
+		case item, ok := <-it.items:
+			if !ok {
+				it.mu.Lock()
+				it.err = it.readErr
+				it.mu.Unlock()
+				if it.err == nil && it.ctx.Err() != nil {
+					it.err = it.ctx.Err()
+					select {
+					case <-it.done:
+						it.err = ErrClosed
+					default:
+					}
+				}
+				if it.err != nil || len(inputs) == 0 || it.config.DropLast {
+					return false
+				}
+				return it.assemble(inputs)
+			}
+			inputs = append(inputs, item)
+		case <-it.done:
+			it.err = ErrClosed
+			return false
+		case <-it.ctx.Done():
+			it.err = it.ctx.Err()
+			return false
+		}
+	}
+	return it.assemble(inputs)
+}
+
+func (it *StreamIterator) assemble(items []Item) bool {
+	indices := make([]int, len(items))
+	for k := range indices {
+		indices[k] = it.count + k
+	}
+	it.batch, it.err = collate(items, indices)
+	it.count += len(items)
+	return it.err == nil
+}
+
+// Current batch, indices count items from the start of stream
+func (it *StreamIterator) Batch() Batch {
+	return it.batch
+}
+
+// Error of stream or of context that stopped iteration, ErrClosed after Close
+func (it *StreamIterator) Err() error {
+	return it.err
+}
+
+// Stop reading stream, the reading goroutine returns even if it is blocked sending to a full buffer
+func (it *StreamIterator) Close() {
+	it.once.Do(func() {
+		close(it.done)
+		it.cancel()
+	})
+}
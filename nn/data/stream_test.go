@@ -0,0 +1,93 @@
+package data
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChannelDataset(t *testing.T) {
+	ch := make(chan Item)
+	go func() {
+		for i := 0; i < 7; i++ {
+			ch <- Item{Input: Vector([]float64{float64(i)}), Target: Vector([]float64{1})}
+		}
+		close(ch)
+	}()
+	it := NewStreamIterator(context.Background(), NewChannelDataset(ch), StreamConfig{BatchSize: 3, Buffer: 2})
+	sizes := make([]int, 0)
+	for it.Next() {
+		b := it.Batch()
+		sizes = append(sizes, b.Len())
+		if first := Rows(b.Inputs)[0][0]; first != float64(b.Indices[0]) {
+			t.Errorf("Next failed. Expected item %v first, but got %v", b.Indices[0], first)
+		}
+	}
+	if it.Err() != nil || len(sizes) != 3 || sizes[2] != 1 {
+		t.Errorf("Next failed. Expected batches of 3, 3 and 1, but got %v (%v)", sizes, it.Err())
+	}
+}
+
+func TestReaderDataset(t *testing.T) {
+	lines := "1,2,0\n3,4,1\nbad\n"
+	parse := func(line []byte) (Item, error) {
+		fields := strings.Split(string(line), ",")
+		values := make([]float64, len(fields))
+		for i, f := range fields {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return Item{}, err
+			}
+			values[i] = v
+		}
+		return Item{Input: Vector(values[:2]), Target: Vector(values[2:])}, nil
+	}
+	it := NewStreamIterator(context.Background(), NewReaderDataset(strings.NewReader(lines), parse), StreamConfig{BatchSize: 2})
+	if !it.Next() || Rows(it.Batch().Inputs)[1][1] != 4 {
+		t.Fatalf("Next failed. Expected first batch of two lines")
+	}
+	if it.Next() || it.Err() == nil {
+		t.Errorf("Next failed. Expected parse error of last line")
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	ch := make(chan Item)
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewStreamIterator(ctx, NewChannelDataset(ch), StreamConfig{BatchSize: 2})
+	go func() {
+		ch <- Item{Input: Vector([]float64{1})}
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if it.Next() || it.Err() != context.Canceled {
+		t.Errorf("Next failed. Expected %v, but got %v", context.Canceled, it.Err())
+	}
+}
+
+// endless stream ignoring its context
+type endlessDataset struct{}
+
+func (endlessDataset) Next(ctx context.Context) (Item, error) {
+	return Item{Input: Vector([]float64{1})}, nil
+}
+
+func TestStreamClose(t *testing.T) {
+	it := NewStreamIterator(context.Background(), endlessDataset{}, StreamConfig{BatchSize: 2, Buffer: 1})
+	if !it.Next() {
+		t.Fatalf("Next failed. Expected a batch, but got %v", it.Err())
+	}
+	time.Sleep(10 * time.Millisecond) //reader is blocked sending to full buffer
+	it.Close()
+	it.Close()
+	select {
+	case <-it.stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Close failed. Expected reading goroutine to return")
+	}
+	if it.Next() || it.Err() != ErrClosed {
+		t.Errorf("Next failed. Expected %v, but got %v", ErrClosed, it.Err())
+	}
+}
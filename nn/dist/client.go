@@ -0,0 +1,166 @@
+package dist
+
+import (
+	"encoding/gob"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Worker of a parameter server, it is an optimizer of trainers that pushes gradients and pulls the updated
+// parameters, so a train.Trainer of every process trains the same network
+type Worker struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	enc     *gob.Encoder
+	dec     *gob.Decoder
+	params  []float64
+	version int
+	err     error
+	config  Config
+	stop    chan struct{} //closed by Close to stop heartbeats
+}
+
+// Join parameter server at address, the parameters of worker are those of server. Worker sends heartbeats while
+// the trainer computes gradients and calls fail if server is silent longer than config.Timeout
+func Dial(address string, config Config) (*Worker, error) {
+	config = config.withDefaults()
+	conn, err := net.DialTimeout("tcp", address, config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	w := &Worker{conn: conn, enc: gob.NewEncoder(conn), dec: gob.NewDecoder(conn), config: config,
+		stop: make(chan struct{})}
+	if _, err := w.call(request{Kind: msgJoin}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go w.heartbeat()
+	return w, nil
+}
+
+// send heartbeats until Close or an error, pushes keep the connection alive while they run
+func (w *Worker) heartbeat() {
+	ticker := time.NewTicker(w.config.Heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+		w.mu.Lock()
+		if w.err == nil {
+			if _, err := w.call(request{Kind: msgHeartbeat}); err != nil {
+				w.err = err
+			}
+		}
+		failed := w.err != nil
+		w.mu.Unlock()
+		if failed {
+			return
+		}
+	}
+}
+
+func (w *Worker) call(req request) (response, error) {
+	var resp response
+	w.conn.SetWriteDeadline(time.Now().Add(w.config.Timeout))
+	if err := w.enc.Encode(&req); err != nil {
+		return resp, err
+	}
+	// server sends heartbeats while a push waits the round of the other workers
+	for {
+		resp = response{}
+		w.conn.SetReadDeadline(time.Now().Add(w.config.Timeout))
+		if err := w.dec.Decode(&resp); err != nil {
+			return resp, err
+		}
+		if !resp.Heartbeat {
+			break
+		}
+	}
+	if resp.Err != "" {
+		return resp, fmt.Errorf("%w: %s", ErrRemote, resp.Err)
+	}
+	if resp.Params != nil {
+		w.params, w.version = resp.Params, resp.Version
+	}
+	return resp, nil
+}
+
+// Parameters pulled in the last call and the number of rounds applied by server
+func (w *Worker) Params() ([]float64, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.params, w.version
+}
+
+// Push gradients and return parameters updated by the round of every worker
+func (w *Worker) Push(grads []float64) ([]float64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return nil, w.err
+	}
+	if _, err := w.call(request{Kind: msgPush, Grads: grads}); err != nil {
+		w.err = err
+		return nil, err
+	}
+	return w.params, nil
+}
+
+// Push gradients and copy updated parameters to params, errors stop updates and they are returned by Err
+func (w *Worker) Step(params, grads []float64) {
+	updated, err := w.Push(grads)
+	if err != nil {
+		return
+	}
+	if len(updated) != len(params) {
+		w.mu.Lock()
+		w.err = ErrLengthMismatch
+		w.mu.Unlock()
+		return
+	}
+	copy(params, updated)
+}
+
+// State of optimizer is kept by server
+func (w *Worker) Reset() {}
+
+// Encode nothing for checkpoints, state of optimizer is kept by server and parameters are pulled by every push
+func (w *Worker) GobEncode() ([]byte, error) {
+	return []byte{}, nil
+}
+
+// Decode state encoded by GobEncode, it does nothing
+func (w *Worker) GobDecode(b []byte) error {
+	return nil
+}
+
+// Error that stopped updates
+func (w *Worker) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Leave parameter server, rounds of the other workers don't wait for this worker
+func (w *Worker) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == ErrClosed {
+		return nil
+	}
+	close(w.stop)
+	var err error
+	if w.err == nil {
+		_, err = w.call(request{Kind: msgLeave})
+	}
+	w.err = ErrClosed
+	if cerr := w.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
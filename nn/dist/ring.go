@@ -0,0 +1,233 @@
+package dist
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+// Peer of a ring all-reduce, it sends chunks of vectors to the next peer and receives them from the previous one.
+// Peers of a ring are fixed, a peer that leaves fails the ring
+type Ring struct {
+	mu         sync.Mutex
+	rank, size int
+	prev, next net.Conn
+	r          *bufio.Reader
+	w          *bufio.Writer
+	err        error
+	timeout    time.Duration
+}
+
+// Join ring of size peers with rank, it accepts the previous peer on listener and dials the next peer at address
+// until ctx is done. Listener is closed after the previous peer connects.
+//
+// An all-reduce fails when a peer is silent longer than config.Timeout, so steps of peers must take less time,
+// heartbeats aren't needed because peers only wait each other inside AllReduce
+func JoinRing(ctx context.Context, l net.Listener, rank, size int, next string, config Config) (*Ring, error) {
+	if rank < 0 || rank >= size {
+		return nil, ErrRankNotValid
+	}
+	ring := &Ring{rank: rank, size: size, timeout: config.withDefaults().Timeout}
+	if size == 1 {
+		l.Close()
+		return ring, nil
+	}
+	type accepted struct {
+		conn net.Conn
+		err  error
+	}
+	prev := make(chan accepted, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err == nil {
+			// previous peer sends its rank
+			var r uint32
+			if err = binary.Read(conn, binary.LittleEndian, &r); err == nil && int(r) != (rank+size-1)%size {
+				err = fmt.Errorf("%w: peer with rank %d connected instead of the previous peer", ErrRemote, r)
+			}
+			if err != nil {
+				conn.Close()
+			}
+		}
+		prev <- accepted{conn, err}
+	}()
+	var dialer net.Dialer
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", next)
+		if err == nil {
+			ring.next = conn
+			break
+		}
+		// next peer may not listen yet
+		select {
+		case <-ctx.Done():
+			l.Close()
+			return nil, ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	if err := binary.Write(ring.next, binary.LittleEndian, uint32(rank)); err != nil {
+		ring.next.Close()
+		l.Close()
+		return nil, err
+	}
+	select {
+	case a := <-prev:
+		if a.err != nil {
+			ring.next.Close()
+			return nil, a.err
+		}
+		ring.prev = a.conn
+	case <-ctx.Done():
+		l.Close()
+		ring.next.Close()
+		return nil, ctx.Err()
+	}
+	ring.r, ring.w = bufio.NewReader(ring.prev), bufio.NewWriter(ring.next)
+	return ring, nil
+}
+
+// Rank of peer in ring
+func (ring *Ring) Rank() int {
+	return ring.rank
+}
+
+// Number of peers of ring
+func (ring *Ring) Size() int {
+	return ring.size
+}
+
+// Replace vector by the mean of vectors of every peer, every peer must call it with vectors of the same length.
+// It sends 2(size-1)/size times the vector by peer, whatever the size of ring
+func (ring *Ring) AllReduce(vec []float64) error {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+	if ring.err != nil {
+		return ring.err
+	}
+	if ring.size == 1 {
+		return nil
+	}
+	chunk := func(c int) []float64 {
+		c = (c%ring.size + ring.size) % ring.size
+		return vec[c*len(vec)/ring.size : (c+1)*len(vec)/ring.size]
+	}
+	buf := make([]float64, len(vec)/ring.size+1)
+	// reduce-scatter, after it every peer has the sum of chunk rank+1
+	for s := 0; s < ring.size-1; s++ {
+		in := chunk(ring.rank - s - 1)
+		if err := ring.exchange(chunk(ring.rank-s), buf[:len(in)]); err != nil {
+			return err
+		}
+		for i, v := range buf[:len(in)] {
+			in[i] += v
+		}
+	}
+	// all-gather of summed chunks
+	for s := 0; s < ring.size-1; s++ {
+		if err := ring.exchange(chunk(ring.rank-s+1), chunk(ring.rank-s)); err != nil {
+			return err
+		}
+	}
+	scale := 1 / float64(ring.size)
+	for i := range vec {
+		vec[i] *= scale
+	}
+	return nil
+}
+
+// send out to next peer while in is received from previous peer
+func (ring *Ring) exchange(out, in []float64) error {
+	deadline := time.Now().Add(ring.timeout)
+	ring.next.SetWriteDeadline(deadline)
+	ring.prev.SetReadDeadline(deadline)
+	sent := make(chan error, 1)
+	go func() {
+		err := binary.Write(ring.w, binary.LittleEndian, out)
+		if err == nil {
+			err = ring.w.Flush()
+		}
+		sent <- err
+	}()
+	err := binary.Read(ring.r, binary.LittleEndian, in)
+	if serr := <-sent; err == nil {
+		err = serr
+	}
+	if err != nil {
+		ring.err = err
+	}
+	return err
+}
+
+// Disconnect peer, the other peers fail their next all-reduce
+func (ring *Ring) Close() error {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+	if ring.size == 1 || ring.err == ErrClosed {
+		return nil
+	}
+	ring.err = ErrClosed
+	ring.prev.Close()
+	return ring.next.Close()
+}
+
+// Optimizer averaging gradients of every peer of a ring before the step of another optimizer. Peers must start with
+// the same parameters, like networks created with the same seed or averaged by AllReduce
+type AllReduceOptimizer struct {
+	ring *Ring
+	opt  optim.Optimizer
+	err  error
+}
+
+// Create optimizer averaging gradients by ring before steps of opt
+func NewAllReduceOptimizer(ring *Ring, opt optim.Optimizer) *AllReduceOptimizer {
+	return &AllReduceOptimizer{ring: ring, opt: opt}
+}
+
+// Average gradients with peers and update parameters, errors stop updates and they are returned by Err
+func (ar *AllReduceOptimizer) Step(params, grads []float64) {
+	if ar.err != nil {
+		return
+	}
+	if ar.err = ar.ring.AllReduce(grads); ar.err != nil {
+		return
+	}
+	ar.opt.Step(params, grads)
+}
+
+// Reset state of optimizer
+func (ar *AllReduceOptimizer) Reset() {
+	ar.opt.Reset()
+}
+
+// Error that stopped updates
+func (ar *AllReduceOptimizer) Err() error {
+	return ar.err
+}
+
+// Encode state of the wrapped optimizer for checkpoints
+func (ar *AllReduceOptimizer) GobEncode() ([]byte, error) {
+	enc, ok := ar.opt.(gob.GobEncoder)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", train.ErrOptimizerNotSaved, ar.opt)
+	}
+	return enc.GobEncode()
+}
+
+// Decode state of the wrapped optimizer encoded by GobEncode
+func (ar *AllReduceOptimizer) GobDecode(b []byte) error {
+	dec, ok := ar.opt.(gob.GobDecoder)
+	if !ok {
+		return fmt.Errorf("%w: %T", train.ErrOptimizerNotSaved, ar.opt)
+	}
+	return dec.GobDecode(b)
+}
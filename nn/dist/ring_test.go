@@ -0,0 +1,135 @@
+package dist
+
+import (
+	"context"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+// two gaussian blobs with class targets
+func blobs(n int, seed int64) data.Dataset {
+	return dataset.ClassDataset(dataset.MakeBlobs(n, []knn.Point{{-1, 1}, {1, -1}}, 0.5, seed))
+}
+
+// join ring of size peers on local addresses
+func ring(t *testing.T, size int, config Config) []*Ring {
+	listeners := make([]net.Listener, size)
+	for i := range listeners {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		listeners[i] = l
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rings := make([]*Ring, size)
+	errs := make([]error, size)
+	var wg sync.WaitGroup
+	for i := range rings {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rings[i], errs[i] = JoinRing(ctx, listeners[i], i, size, listeners[(i+1)%size].Addr().String(), config)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		for _, r := range rings {
+			r.Close()
+		}
+	})
+	return rings
+}
+
+func TestAllReduce(t *testing.T) {
+	for _, size := range []int{1, 2, 3} {
+		rings := ring(t, size, Config{})
+		vectors := make([][]float64, size)
+		expected := make([]float64, 7)
+		for i := range vectors {
+			vectors[i] = make([]float64, 7)
+			for k := range vectors[i] {
+				vectors[i][k] = float64(i*10 + k)
+				expected[k] += vectors[i][k] / float64(size)
+			}
+		}
+		errs := make([]error, size)
+		var wg sync.WaitGroup
+		for i, r := range rings {
+			wg.Add(1)
+			go func(i int, r *Ring) {
+				defer wg.Done()
+				errs[i] = r.AllReduce(vectors[i])
+			}(i, r)
+		}
+		wg.Wait()
+		for i := range vectors {
+			if errs[i] != nil {
+				t.Fatal(errs[i])
+			}
+			for k := range expected {
+				if math.Abs(vectors[i][k]-expected[k]) > 1e-12 {
+					t.Fatalf("AllReduce failed. Expected %v with %d peers, but got %v", expected, size, vectors[i])
+				}
+			}
+		}
+	}
+	if _, err := JoinRing(context.Background(), nil, 3, 3, "", Config{}); err != ErrRankNotValid {
+		t.Errorf("JoinRing failed. Expected %v, but got %v", ErrRankNotValid, err)
+	}
+}
+
+func TestAllReduceOptimizer(t *testing.T) {
+	rings := ring(t, 3, Config{})
+	nets := make([]*layers.Sequential, 3)
+	errs := make([]error, 3)
+	var wg sync.WaitGroup
+	for i, r := range rings {
+		nets[i] = layers.NewSequential(1, layers.NewDense(2, 4), layers.NewTanh(), layers.NewDense(4, 2))
+		trainer := train.NewTrainer(nets[i], NewAllReduceOptimizer(r, optim.NewAdam(0.05)), train.CrossEntropy)
+		loader := data.NewDataLoader(blobs(24, int64(i)), data.LoaderConfig{BatchSize: 8})
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = trainer.Fit(loader, 5)
+		}(i)
+	}
+	wg.Wait()
+	for i := range nets {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if !equal(nets[i].Params(), nets[0].Params()) {
+			t.Errorf("AllReduceOptimizer failed. Parameters of peer %d differ from peer 0", i)
+		}
+	}
+	// peers fail after a peer leaves
+	rings[2].Close()
+	if err := rings[0].AllReduce([]float64{1, 2, 3}); err == nil {
+		t.Errorf("AllReduce failed. Expected error after peer left")
+	}
+}
+
+func TestAllReduceTimeout(t *testing.T) {
+	rings := ring(t, 2, Config{Timeout: 50 * time.Millisecond})
+	// peer 1 never joins the all-reduce
+	if err := rings[0].AllReduce([]float64{1, 2}); err == nil {
+		t.Errorf("AllReduce failed. Expected timeout of silent peer")
+	}
+}
@@ -0,0 +1,292 @@
+// Package dist trains one network with many processes or machines over TCP.
+//
+// A ParameterServer keeps the parameters and the optimizer, workers push the gradients of their batches and pull
+// the parameters updated with the mean gradient of every worker, workers may join and leave while training and
+// workers silent longer than Config.Timeout are evicted. A Ring averages gradients of a fixed group of peers by
+// ring all-reduce without a central process.
+package dist
+
+import (
+	"encoding/gob"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+var (
+	ErrClosed         = errors.New("distributed training is closed")
+	ErrLengthMismatch = errors.New("length of gradients doesn't match parameters")
+	ErrRemote         = errors.New("remote peer failed")
+	ErrRankNotValid   = errors.New("rank is not in range [0, size)")
+)
+
+// kinds of messages between workers and parameter server
+const (
+	msgJoin = iota
+	msgPush
+	msgLeave
+	msgHeartbeat
+)
+
+type request struct {
+	Kind  int
+	Grads []float64
+}
+
+type response struct {
+	Params    []float64
+	Version   int
+	Err       string
+	Heartbeat bool //sent by server while a worker waits a round, it isn't the response of a request
+}
+
+// Timing of heartbeats of workers and parameter server, both sides send them so a connection is never silent
+// longer than Heartbeat while it works
+type Config struct {
+	Heartbeat time.Duration //interval of heartbeats, 1s if zero
+	Timeout   time.Duration //silence after which the peer is dead and the connection is closed, 10s if zero
+}
+
+func (c Config) withDefaults() Config {
+	if c.Heartbeat <= 0 {
+		c.Heartbeat = time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return c
+}
+
+// Parameter server of synchronous training, every round waits the gradients of every worker and applies their mean
+type ParameterServer struct {
+	mu      sync.Mutex
+	params  []float64
+	opt     optim.Optimizer
+	version int
+	members map[*member]bool
+	pushed  int           //gradients pushed in the current round
+	sum     []float64     //sum of gradients of the current round
+	done    chan struct{} //closed when the current round is applied
+	closed  bool
+	ln      []net.Listener
+	conns   map[net.Conn]bool
+	wg      sync.WaitGroup
+	config  Config
+}
+
+type member struct {
+	pushed bool
+}
+
+// Create parameter server of params updated by opt, params are owned by server. Workers silent longer than
+// config.Timeout are evicted, so a dead worker doesn't block rounds
+func NewParameterServer(params []float64, opt optim.Optimizer, config Config) *ParameterServer {
+	return &ParameterServer{
+		params:  params,
+		opt:     opt,
+		members: make(map[*member]bool),
+		sum:     make([]float64, len(params)),
+		done:    make(chan struct{}),
+		conns:   make(map[net.Conn]bool),
+		config:  config.withDefaults(),
+	}
+}
+
+// Copy of parameters and number of applied rounds
+func (ps *ParameterServer) Params() ([]float64, int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return append([]float64(nil), ps.params...), ps.version
+}
+
+// Number of joined workers
+func (ps *ParameterServer) Workers() int {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return len(ps.members)
+}
+
+// Accept workers of listener until Close, it always returns a non-nil error
+func (ps *ParameterServer) Serve(l net.Listener) error {
+	ps.mu.Lock()
+	if ps.closed {
+		ps.mu.Unlock()
+		return ErrClosed
+	}
+	ps.ln = append(ps.ln, l)
+	ps.mu.Unlock()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			ps.mu.Lock()
+			defer ps.mu.Unlock()
+			if ps.closed {
+				return ErrClosed
+			}
+			return err
+		}
+		ps.mu.Lock()
+		if ps.closed {
+			ps.mu.Unlock()
+			conn.Close()
+			return ErrClosed
+		}
+		ps.conns[conn] = true
+		ps.wg.Add(1)
+		ps.mu.Unlock()
+		go ps.handle(conn)
+	}
+}
+
+// Stop listeners and disconnect workers
+func (ps *ParameterServer) Close() error {
+	ps.mu.Lock()
+	ps.closed = true
+	for _, l := range ps.ln {
+		l.Close()
+	}
+	for conn := range ps.conns {
+		conn.Close()
+	}
+	// release workers waiting a round
+	close(ps.done)
+	ps.done = make(chan struct{})
+	ps.mu.Unlock()
+	ps.wg.Wait()
+	return nil
+}
+
+func (ps *ParameterServer) handle(conn net.Conn) {
+	defer ps.wg.Done()
+	defer conn.Close()
+	var m *member
+	defer func() {
+		ps.mu.Lock()
+		defer ps.mu.Unlock()
+		delete(ps.conns, conn)
+		if m != nil {
+			ps.leave(m)
+		}
+	}()
+	dec, enc := gob.NewDecoder(conn), gob.NewEncoder(conn)
+	send := func(resp *response) error {
+		conn.SetWriteDeadline(time.Now().Add(ps.config.Timeout))
+		return enc.Encode(resp)
+	}
+	for {
+		var req request
+		// a worker that sends neither requests nor heartbeats is evicted
+		conn.SetReadDeadline(time.Now().Add(ps.config.Timeout))
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		var resp response
+		switch req.Kind {
+		case msgJoin:
+			ps.mu.Lock()
+			if m == nil {
+				m = &member{}
+				ps.members[m] = true
+			}
+			resp.Params, resp.Version = append([]float64(nil), ps.params...), ps.version
+			ps.mu.Unlock()
+		case msgPush:
+			if m == nil {
+				resp.Err = "worker pushed gradients before joining"
+				break
+			}
+			var err error
+			if resp, err = ps.push(m, req.Grads, send); err != nil {
+				return
+			}
+		case msgLeave:
+			ps.mu.Lock()
+			if m != nil {
+				ps.leave(m)
+				m = nil
+			}
+			ps.mu.Unlock()
+		case msgHeartbeat:
+			// empty response, reading the request renewed the deadline
+		}
+		if err := send(&resp); err != nil || req.Kind == msgLeave {
+			return
+		}
+	}
+}
+
+// add gradients of member to round and wait until the round is applied, heartbeats are sent while it waits and
+// their errors are returned
+func (ps *ParameterServer) push(m *member, grads []float64, send func(resp *response) error) (response, error) {
+	ps.mu.Lock()
+	if len(grads) != len(ps.params) {
+		ps.mu.Unlock()
+		return response{Err: ErrLengthMismatch.Error()}, nil
+	}
+	if m.pushed {
+		ps.mu.Unlock()
+		return response{Err: "worker pushed gradients twice in a round"}, nil
+	}
+	for i, g := range grads {
+		ps.sum[i] += g
+	}
+	m.pushed = true
+	ps.pushed++
+	done := ps.done
+	ps.tryApply()
+	ps.mu.Unlock()
+	ticker := time.NewTicker(ps.config.Heartbeat)
+	defer ticker.Stop()
+	for waiting := true; waiting; {
+		select {
+		case <-done:
+			waiting = false
+		case <-ticker.C:
+			if err := send(&response{Heartbeat: true}); err != nil {
+				return response{}, err
+			}
+		}
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.closed {
+		return response{Err: ErrClosed.Error()}, nil
+	}
+	return response{Params: append([]float64(nil), ps.params...), Version: ps.version}, nil
+}
+
+// remove member, gradients it pushed are kept and the round may be complete without it
+func (ps *ParameterServer) leave(m *member) {
+	delete(ps.members, m)
+	ps.tryApply()
+}
+
+// apply mean gradient of round when every member pushed its gradients
+func (ps *ParameterServer) tryApply() {
+	if ps.pushed == 0 {
+		return
+	}
+	for m := range ps.members {
+		if !m.pushed {
+			return
+		}
+	}
+	scale := 1 / float64(ps.pushed)
+	for i := range ps.sum {
+		ps.sum[i] *= scale
+	}
+	ps.opt.Step(ps.params, ps.sum)
+	ps.version++
+	for i := range ps.sum {
+		ps.sum[i] = 0
+	}
+	for m := range ps.members {
+		m.pushed = false
+	}
+	ps.pushed = 0
+	close(ps.done)
+	ps.done = make(chan struct{})
+}
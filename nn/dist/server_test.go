@@ -0,0 +1,207 @@
+package dist
+
+import (
+	"encoding/gob"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+func serve(t *testing.T, ps *ParameterServer) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ps.Serve(l)
+	t.Cleanup(func() { ps.Close() })
+	return l.Addr().String()
+}
+
+func equal(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParameterServer(t *testing.T) {
+	ps := NewParameterServer([]float64{0, 0}, optim.NewSGD(1, 0), Config{})
+	addr := serve(t, ps)
+	w1, err := Dial(addr, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w1.Close()
+	w2, err := Dial(addr, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// round waits gradients of both workers
+	var wg sync.WaitGroup
+	results := make([][]float64, 2)
+	for i, w := range []*Worker{w1, w2} {
+		wg.Add(1)
+		go func(i int, w *Worker, grads []float64) {
+			defer wg.Done()
+			results[i], _ = w.Push(grads)
+		}(i, w, []float64{float64(2*i + 1), float64(2*i + 2)})
+	}
+	wg.Wait()
+	expected := []float64{-2, -3}
+	if !equal(results[0], expected) || !equal(results[1], expected) {
+		t.Errorf("Push failed. Expected %v, but got %v", expected, results)
+	}
+	// rounds don't wait workers that left
+	w2.Close()
+	if p, err := w1.Push([]float64{1, 1}); err != nil || !equal(p, []float64{-3, -4}) {
+		t.Errorf("Push failed. Expected [-3 -4] after worker left, but got %v, %v", p, err)
+	}
+	// workers join with the current parameters
+	w3, err := Dial(addr, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w3.Close()
+	if p, v := w3.Params(); !equal(p, []float64{-3, -4}) || v != 2 {
+		t.Errorf("Dial failed. Expected parameters [-3 -4] of round 2, but got %v of round %v", p, v)
+	}
+	if ps.Workers() != 2 {
+		t.Errorf("Workers failed. Expected 2, but got %v", ps.Workers())
+	}
+	if _, err := w3.Push([]float64{1}); !errors.Is(err, ErrRemote) {
+		t.Errorf("Push failed. Expected %v, but got %v", ErrRemote, err)
+	}
+}
+
+func TestDistributedTraining(t *testing.T) {
+	newNet := func() *layers.Sequential {
+		return layers.NewSequential(1, layers.NewDense(2, 4), layers.NewTanh(), layers.NewDense(4, 2))
+	}
+	ps := NewParameterServer(newNet().Params(), optim.NewAdam(0.05), Config{})
+	addr := serve(t, ps)
+	nets := make([]*layers.Sequential, 3)
+	trainers := make([]*train.Trainer, 3)
+	workers := make([]*Worker, 3)
+	for i := range nets {
+		w, err := Dial(addr, Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// workers have other initial weights until they copy those of server
+		nets[i] = layers.NewSequential(int64(i+2), layers.NewDense(2, 4), layers.NewTanh(), layers.NewDense(4, 2))
+		params, _ := w.Params()
+		copy(nets[i].Params(), params)
+		trainers[i], workers[i] = train.NewTrainer(nets[i], w, train.CrossEntropy), w
+	}
+	// state of workers is kept by server, checkpoints only save parameters
+	trainers[0].Checkpoint(filepath.Join(t.TempDir(), "worker.ckpt"), 5)
+	histories := make([][]float64, 3)
+	errs := make([]error, 3)
+	var wg sync.WaitGroup
+	for i := range trainers {
+		loader := data.NewDataLoader(blobs(32, int64(i)), data.LoaderConfig{BatchSize: 8, Shuffle: true})
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer workers[i].Close()
+			histories[i], errs[i] = trainers[i].Fit(loader, 10)
+		}(i)
+	}
+	wg.Wait()
+	params, version := ps.Params()
+	if version != 40 {
+		t.Errorf("Fit failed. Expected 40 rounds, but got %v", version)
+	}
+	for i, net := range nets {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if !equal(net.Params(), params) {
+			t.Errorf("Fit failed. Worker %d doesn't have parameters of server", i)
+		}
+		if h := histories[i]; h[len(h)-1] >= h[0] {
+			t.Errorf("Fit failed. Expected decreasing loss, but got %v", h)
+		}
+	}
+	// training stops with errors of server
+	w, err := Dial(addr, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.Close()
+	trainer := train.NewTrainer(newNet(), w, train.CrossEntropy)
+	if _, err := trainer.Fit(data.NewDataLoader(blobs(8, 1), data.LoaderConfig{BatchSize: 8}), 1); err == nil {
+		t.Errorf("Fit failed. Expected error after server closed")
+	}
+}
+
+func TestHeartbeats(t *testing.T) {
+	config := Config{Heartbeat: 10 * time.Millisecond, Timeout: 150 * time.Millisecond}
+	ps := NewParameterServer([]float64{0}, optim.NewSGD(1, 0), config)
+	addr := serve(t, ps)
+	w, err := Dial(addr, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	// a worker that joins and then stops responding
+	dead, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dead.Close()
+	gob.NewEncoder(dead).Encode(&request{Kind: msgJoin})
+	for ps.Workers() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	// the round doesn't wait the dead worker after it is evicted
+	if p, err := w.Push([]float64{1}); err != nil || !equal(p, []float64{-1}) {
+		t.Errorf("Push failed. Expected [-1] after dead worker was evicted, but got %v, %v", p, err)
+	}
+	if ps.Workers() != 1 {
+		t.Errorf("Workers failed. Expected 1, but got %v", ps.Workers())
+	}
+	// heartbeats keep an idle worker joined
+	time.Sleep(3 * config.Timeout)
+	if p, err := w.Push([]float64{1}); err != nil || !equal(p, []float64{-2}) {
+		t.Errorf("Push failed. Expected [-2] after idle worker, but got %v, %v", p, err)
+	}
+	// a silent server fails calls of workers
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var req request
+		gob.NewDecoder(conn).Decode(&req)
+		gob.NewEncoder(conn).Encode(&response{Params: []float64{0}})
+		time.Sleep(5 * config.Timeout)
+	}()
+	silent, err := Dial(l.Addr().String(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+	if _, err := silent.Push([]float64{1}); err == nil {
+		t.Errorf("Push failed. Expected timeout of silent server")
+	}
+}
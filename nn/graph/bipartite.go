@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrNotBipartite error = errors.New("graph is not bipartite")
+
+// Test if graph is bipartite taking edges as undirected links
+//
+// It returns the side (0 or 1) of every node in a valid two-coloring, or nil if graph is not bipartite
+func (graph *Graph) IsBipartite() (bool, []int) {
+	adj := graph.adjacency()
+	color := make([]int, len(adj.neighbors))
+	for i := range color {
+		color[i] = -1
+	}
+	queue := make([]int, 0, 10)
+	for start := range color {
+		if color[start] != -1 {
+			continue
+		}
+		color[start] = 0
+		queue = append(queue[:0], start)
+		for len(queue) != 0 {
+			curr := queue[0]
+			queue = queue[1:]
+			for _, next := range adj.neighbors[curr] {
+				if color[next] == -1 {
+					color[next] = 1 - color[curr]
+					queue = append(queue, next)
+				} else if color[next] == color[curr] {
+					return false, nil
+				}
+			}
+		}
+	}
+	return true, color
+}
+
+// Maximum matching of a bipartite graph by Hopcroft–Karp algorithm
+//
+// It returns the node matched to every node or -1 if node is unmatched, and ErrNotBipartite if graph is not bipartite
+func (graph *Graph) MaxBipartiteMatching() ([]int, error) {
+	ok, color := graph.IsBipartite()
+	if !ok {
+		return nil, ErrNotBipartite
+	}
+	adj := graph.adjacency()
+	n := len(adj.neighbors)
+	mate := make([]int, n)
+	for i := range mate {
+		mate[i] = -1
+	}
+	dist := make([]int, n)
+	left := make([]int, 0, n)
+	for i := range color {
+		if color[i] == 0 {
+			left = append(left, i)
+		}
+	}
+	inf := math.MaxInt
+	// build layers of alternating paths from free left nodes, returns if some augmenting path exists
+	bfs := func() bool {
+		queue := make([]int, 0, len(left))
+		for _, u := range left {
+			if mate[u] == -1 {
+				dist[u] = 0
+				queue = append(queue, u)
+			} else {
+				dist[u] = inf
+			}
+		}
+		found := false
+		for len(queue) != 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, v := range adj.neighbors[u] {
+				w := mate[v]
+				if w == -1 {
+					found = true
+				} else if dist[w] == inf {
+					dist[w] = dist[u] + 1
+					queue = append(queue, w)
+				}
+			}
+		}
+		return found
+	}
+	// augment along shortest alternating paths
+	var dfs func(u int) bool
+	dfs = func(u int) bool {
+		for _, v := range adj.neighbors[u] {
+			w := mate[v]
+			if w == -1 || (dist[w] == dist[u]+1 && dfs(w)) {
+				mate[u] = v
+				mate[v] = u
+				return true
+			}
+		}
+		dist[u] = inf
+		return false
+	}
+	for bfs() {
+		for _, u := range left {
+			if mate[u] == -1 {
+				dfs(u)
+			}
+		}
+	}
+	return mate, nil
+}
@@ -0,0 +1,65 @@
+package graph
+
+import "testing"
+
+func TestIsBipartite(t *testing.T) {
+	g := New("square")
+	for i := 0; i < 4; i++ {
+		g.AddNode("", nil)
+	}
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 0)
+	ok, color := g.IsBipartite()
+	if !ok {
+		t.Fatalf("IsBipartite failed. Expected true for square")
+	}
+	for dst := 0; dst < 4; dst++ {
+		for _, src := range g.InEdges(dst) {
+			if color[src] == color[dst] {
+				t.Errorf("IsBipartite failed. Nodes %d and %d have the same color", src, dst)
+			}
+		}
+	}
+	g.AddEdge(0, 2)
+	if ok, _ := g.IsBipartite(); ok {
+		t.Errorf("IsBipartite failed. Expected false for triangle")
+	}
+	if _, err := g.MaxBipartiteMatching(); err != ErrNotBipartite {
+		t.Errorf("MaxBipartiteMatching failed. Expected %v, but got %v", ErrNotBipartite, err)
+	}
+}
+
+func TestMaxBipartiteMatching(t *testing.T) {
+	// workers 0..2 and jobs 3..5
+	g := New("jobs")
+	for i := 0; i < 6; i++ {
+		g.AddNode("", nil)
+	}
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	mate, err := g.MaxBipartiteMatching()
+	if err != nil {
+		t.Fatal(err)
+	}
+	matched := 0
+	for u, v := range mate {
+		if v == -1 {
+			continue
+		}
+		if mate[v] != u {
+			t.Errorf("MaxBipartiteMatching failed. Matching is not symmetric at %d", u)
+		}
+		if !g.HasEdge(u, v) && !g.HasEdge(v, u) {
+			t.Errorf("MaxBipartiteMatching failed. Nodes %d and %d are not linked", u, v)
+		}
+		matched++
+	}
+	if matched != 6 {
+		t.Errorf("MaxBipartiteMatching failed. Expected 3 pairs, but got %d", matched/2)
+	}
+}
@@ -0,0 +1,273 @@
+package graph
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// undirected weighted adjacency used by community detection
+type adjacency struct {
+	neighbors [][]int     //sorted neighbors of every node
+	weights   [][]float64 //weights of links to neighbors
+	degrees   []float64   //weighted degree of every node
+	total     float64     //sum of every degree (2m)
+}
+
+// build undirected view of graph, every edge src -> dst becomes a link between src and dst with the edge weight
+func (graph *Graph) adjacency() *adjacency {
+	links := make([]map[int]float64, len(graph.vertices))
+	for i := range links {
+		links[i] = make(map[int]float64)
+	}
+	for dst, srcLs := range graph.edges {
+		for i, src := range srcLs {
+			w := graph.weights[dst][i]
+			if src == dst {
+				// self loop counts twice in the degree of the node
+				links[src][src] += 2 * w
+				continue
+			}
+			links[src][dst] += w
+			links[dst][src] += w
+		}
+	}
+	return newAdjacency(links)
+}
+
+func newAdjacency(links []map[int]float64) *adjacency {
+	adj := &adjacency{
+		neighbors: make([][]int, len(links)),
+		weights:   make([][]float64, len(links)),
+		degrees:   make([]float64, len(links)),
+	}
+	for i, ls := range links {
+		neighbors := make([]int, 0, len(ls))
+		for j := range ls {
+			neighbors = append(neighbors, j)
+		}
+		sort.Ints(neighbors)
+		weights := make([]float64, len(neighbors))
+		for k, j := range neighbors {
+			weights[k] = ls[j]
+			adj.degrees[i] += ls[j]
+		}
+		adj.neighbors[i] = neighbors
+		adj.weights[i] = weights
+		adj.total += adj.degrees[i]
+	}
+	return adj
+}
+
+// renumber communities to 0..c-1 in order of first appearance and return the number of communities
+func renumber(community []int) int {
+	ids := make(map[int]int)
+	for i, c := range community {
+		id, ok := ids[c]
+		if !ok {
+			id = len(ids)
+			ids[c] = id
+		}
+		community[i] = id
+	}
+	return len(ids)
+}
+
+// Modularity of a partition of graph nodes in communities
+//
+// community[i] is the community of node i, edges are taken as undirected links
+func (graph *Graph) Modularity(community []int) float64 {
+	if len(community) != len(graph.vertices) {
+		panic(ErrNodeNoExist)
+	}
+	return graph.adjacency().modularity(community)
+}
+
+func (adj *adjacency) modularity(community []int) float64 {
+	if adj.total == 0 {
+		return 0
+	}
+	internal := make(map[int]float64)
+	tot := make(map[int]float64)
+	for i := range adj.neighbors {
+		c := community[i]
+		tot[c] += adj.degrees[i]
+		for k, j := range adj.neighbors[i] {
+			if community[j] == c {
+				internal[c] += adj.weights[i][k]
+			}
+		}
+	}
+	q := 0.0
+	for c, t := range tot {
+		q += internal[c]/adj.total - (t/adj.total)*(t/adj.total)
+	}
+	return q
+}
+
+// Community detection by label propagation
+//
+// Edge weights are taken as link strength. Every node takes the most frequent label of its neighbors until labels are stable or maxIter is reached,
+// ties are broken at random with the given seed. It returns the community of every node numbered from zero.
+func (graph *Graph) LabelPropagation(maxIter int, seed int64) []int {
+	adj := graph.adjacency()
+	rnd := rand.New(rand.NewSource(seed))
+	n := len(adj.neighbors)
+	labels := make([]int, n)
+	order := make([]int, n)
+	for i := range labels {
+		labels[i] = i
+		order[i] = i
+	}
+	counts := make(map[int]float64)
+	best := make([]int, 0, 10)
+	for iter := 0; iter < maxIter; iter++ {
+		rnd.Shuffle(n, func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+		changed := false
+		for _, i := range order {
+			if len(adj.neighbors[i]) == 0 {
+				continue
+			}
+			for k := range counts {
+				delete(counts, k)
+			}
+			for k, j := range adj.neighbors[i] {
+				if j != i {
+					counts[labels[j]] += adj.weights[i][k]
+				}
+			}
+			maxCount := 0.0
+			best = best[:0]
+			for label, count := range counts {
+				if count > maxCount {
+					maxCount = count
+					best = append(best[:0], label)
+				} else if count == maxCount {
+					best = append(best, label)
+				}
+			}
+			if len(best) == 0 {
+				continue
+			}
+			// keep current label when it is one of the most frequent
+			keep := false
+			for _, label := range best {
+				if label == labels[i] {
+					keep = true
+					break
+				}
+			}
+			if keep {
+				continue
+			}
+			sort.Ints(best)
+			labels[i] = best[rnd.Intn(len(best))]
+			changed = true
+		}
+		if !changed {
+			break
+		}
+	}
+	renumber(labels)
+	return labels
+}
+
+// Community detection by Louvain method
+//
+// Edge weights are taken as link strength. It greedily moves nodes between communities while modularity grows and then aggregates every community
+// in a single node, repeating until no move improves modularity. It returns the community of every node numbered from zero.
+func (graph *Graph) Louvain() []int {
+	adj := graph.adjacency()
+	community := make([]int, len(adj.neighbors))
+	for i := range community {
+		community[i] = i
+	}
+	for {
+		local, moved := adj.moveNodes()
+		if !moved {
+			break
+		}
+		count := renumber(local)
+		// map nodes of original graph to the new communities
+		for i := range community {
+			community[i] = local[community[i]]
+		}
+		adj = adj.aggregate(local, count)
+	}
+	renumber(community)
+	return community
+}
+
+// first phase of Louvain method, it returns the community of every node and if some node was moved
+func (adj *adjacency) moveNodes() ([]int, bool) {
+	n := len(adj.neighbors)
+	community := make([]int, n)
+	tot := make([]float64, n)
+	for i := range community {
+		community[i] = i
+		tot[i] = adj.degrees[i]
+	}
+	if adj.total == 0 {
+		return community, false
+	}
+	linkTo := make([]float64, n)
+	visited := make([]int, 0, 10)
+	moved := false
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n; i++ {
+			ci := community[i]
+			ki := adj.degrees[i]
+			// weight of links from i to every neighbor community
+			visited = visited[:0]
+			for k, j := range adj.neighbors[i] {
+				if j == i {
+					continue
+				}
+				c := community[j]
+				if linkTo[c] == 0 {
+					visited = append(visited, c)
+				}
+				linkTo[c] += adj.weights[i][k]
+			}
+			// remove i from its community
+			tot[ci] -= ki
+			best := ci
+			bestGain := linkTo[ci] - tot[ci]*ki/adj.total
+			for _, c := range visited {
+				gain := linkTo[c] - tot[c]*ki/adj.total
+				if gain > bestGain {
+					best = c
+					bestGain = gain
+				}
+			}
+			// insert i in the best community
+			tot[best] += ki
+			community[i] = best
+			if best != ci {
+				improved = true
+				moved = true
+			}
+			for _, c := range visited {
+				linkTo[c] = 0
+			}
+			linkTo[ci] = 0
+		}
+	}
+	return community, moved
+}
+
+// second phase of Louvain method, every community becomes a node
+func (adj *adjacency) aggregate(community []int, count int) *adjacency {
+	links := make([]map[int]float64, count)
+	for i := range links {
+		links[i] = make(map[int]float64)
+	}
+	for i, neighbors := range adj.neighbors {
+		for k, j := range neighbors {
+			links[community[i]][community[j]] += adj.weights[i][k]
+		}
+	}
+	return newAdjacency(links)
+}
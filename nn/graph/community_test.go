@@ -0,0 +1,54 @@
+package graph
+
+import "testing"
+
+// two cliques of four nodes joined by one edge
+func twoCliques() Graph {
+	g := New("cliques")
+	for i := 0; i < 8; i++ {
+		g.AddNode("", i)
+	}
+	for _, offset := range []int{0, 4} {
+		for i := 0; i < 4; i++ {
+			for j := i + 1; j < 4; j++ {
+				g.AddEdge(offset+i, offset+j)
+			}
+		}
+	}
+	g.AddEdge(3, 4)
+	return g
+}
+
+func sameCommunities(t *testing.T, community []int) {
+	for i := 1; i < 4; i++ {
+		if community[i] != community[0] || community[4+i] != community[4] {
+			t.Fatalf("clique split in communities %v", community)
+		}
+	}
+	if community[0] == community[4] {
+		t.Fatalf("cliques joined in communities %v", community)
+	}
+}
+
+func TestLouvain(t *testing.T) {
+	g := twoCliques()
+	community := g.Louvain()
+	sameCommunities(t, community)
+	if q := g.Modularity(community); q <= 0.3 {
+		t.Errorf("Louvain failed. Expected modularity greater than 0.3, but got %v", q)
+	}
+}
+
+func TestLabelPropagation(t *testing.T) {
+	g := twoCliques()
+	community := g.LabelPropagation(100, 1)
+	sameCommunities(t, community)
+}
+
+func TestModularity(t *testing.T) {
+	g := twoCliques()
+	single := make([]int, g.LenNodes())
+	if q := g.Modularity(single); q != 0 {
+		t.Errorf("Modularity failed. Expected 0, but got %v", q)
+	}
+}
@@ -0,0 +1,190 @@
+package graph
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Edge identified by the names of its nodes
+type EdgeName struct {
+	Src string
+	Dst string
+}
+
+// Differences between two graphs with nodes matched by name
+type GraphDiff struct {
+	AddedNodes   []string   //nodes in other graph that are not in this graph
+	RemovedNodes []string   //nodes in this graph that are not in other graph
+	AddedEdges   []EdgeName //edges in other graph that are not in this graph
+	RemovedEdges []EdgeName //edges in this graph that are not in other graph
+}
+
+// Test if graphs have no differences
+func (diff *GraphDiff) Empty() bool {
+	return len(diff.AddedNodes) == 0 && len(diff.RemovedNodes) == 0 &&
+		len(diff.AddedEdges) == 0 && len(diff.RemovedEdges) == 0
+}
+
+// count node names
+func (graph *Graph) nodeNames() map[string]int {
+	names := make(map[string]int, len(graph.vertices))
+	for _, v := range graph.vertices {
+		names[v.name]++
+	}
+	return names
+}
+
+// count edges by node names
+func (graph *Graph) edgeNames() map[EdgeName]int {
+	names := make(map[EdgeName]int)
+	for dst, srcLs := range graph.edges {
+		for _, src := range srcLs {
+			names[EdgeName{Src: graph.vertices[src].name, Dst: graph.vertices[dst].name}]++
+		}
+	}
+	return names
+}
+
+// Get differences needed to transform this graph into other graph
+//
+// Nodes are matched by name, repeated names and repeated edges are compared by count
+func (graph *Graph) Diff(other *Graph) GraphDiff {
+	diff := GraphDiff{
+		AddedNodes:   []string{},
+		RemovedNodes: []string{},
+		AddedEdges:   []EdgeName{},
+		RemovedEdges: []EdgeName{},
+	}
+	nodes, otherNodes := graph.nodeNames(), other.nodeNames()
+	for name, count := range otherNodes {
+		for i := nodes[name]; i < count; i++ {
+			diff.AddedNodes = append(diff.AddedNodes, name)
+		}
+	}
+	for name, count := range nodes {
+		for i := otherNodes[name]; i < count; i++ {
+			diff.RemovedNodes = append(diff.RemovedNodes, name)
+		}
+	}
+	edges, otherEdges := graph.edgeNames(), other.edgeNames()
+	for edge, count := range otherEdges {
+		for i := edges[edge]; i < count; i++ {
+			diff.AddedEdges = append(diff.AddedEdges, edge)
+		}
+	}
+	for edge, count := range edges {
+		for i := otherEdges[edge]; i < count; i++ {
+			diff.RemovedEdges = append(diff.RemovedEdges, edge)
+		}
+	}
+	sort.Strings(diff.AddedNodes)
+	sort.Strings(diff.RemovedNodes)
+	sortEdgeNames(diff.AddedEdges)
+	sortEdgeNames(diff.RemovedEdges)
+	return diff
+}
+
+func sortEdgeNames(edges []EdgeName) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Src != edges[j].Src {
+			return edges[i].Src < edges[j].Src
+		}
+		return edges[i].Dst < edges[j].Dst
+	})
+}
+
+// Weisfeiler-Lehman colors of graph nodes after the given number of refinements
+func (graph *Graph) nodeColors(rounds int) []string {
+	n := len(graph.vertices)
+	outs := make([][]int, n)
+	for dst, srcLs := range graph.edges {
+		for _, src := range srcLs {
+			outs[src] = append(outs[src], dst)
+		}
+	}
+	// initial color is in and out degree
+	colors := make([]string, n)
+	for i := range colors {
+		colors[i] = strconv.Itoa(len(graph.edges[i])) + "/" + strconv.Itoa(len(outs[i]))
+	}
+	next := make([]string, n)
+	for r := 0; r < rounds; r++ {
+		for i := range colors {
+			ins := make([]string, 0, len(graph.edges[i]))
+			for _, src := range graph.edges[i] {
+				ins = append(ins, colors[src])
+			}
+			out := make([]string, 0, len(outs[i]))
+			for _, dst := range outs[i] {
+				out = append(out, colors[dst])
+			}
+			sort.Strings(ins)
+			sort.Strings(out)
+			next[i] = colors[i] + "(" + strings.Join(ins, ",") + "|" + strings.Join(out, ",") + ")"
+		}
+		// compress colors to keep them short
+		keys := append([]string{}, next...)
+		sort.Strings(keys)
+		ids := make(map[string]string)
+		for _, k := range keys {
+			if _, ok := ids[k]; !ok {
+				ids[k] = strconv.Itoa(len(ids))
+			}
+		}
+		for i := range colors {
+			colors[i] = ids[next[i]]
+		}
+	}
+	return colors
+}
+
+// Test if graphs may be isomorphic ignoring node names
+//
+// It compares node and edge counts and Weisfeiler-Lehman color refinement of both graphs,
+// false means graphs are not isomorphic and true means they could be
+func (graph *Graph) MaybeIsomorphic(other *Graph) bool {
+	if len(graph.vertices) != len(other.vertices) {
+		return false
+	}
+	edges, otherEdges := 0, 0
+	for i := range graph.edges {
+		edges += len(graph.edges[i])
+		otherEdges += len(other.edges[i])
+	}
+	if edges != otherEdges {
+		return false
+	}
+	rounds := len(graph.vertices)
+	if rounds > 8 {
+		rounds = 8
+	}
+	// colors are compared together because compression depends on the colors of each graph
+	joint := New("")
+	joint.vertices = append(append([]*Node{}, graph.vertices...), other.vertices...)
+	joint.edges = make([][]int, 0, len(joint.vertices))
+	joint.edges = append(joint.edges, graph.edges...)
+	offset := len(graph.vertices)
+	for _, srcLs := range other.edges {
+		shifted := make([]int, len(srcLs))
+		for i, src := range srcLs {
+			shifted[i] = src + offset
+		}
+		joint.edges = append(joint.edges, shifted)
+	}
+	colors := joint.nodeColors(rounds)
+	histogram := make(map[string]int)
+	for i, c := range colors {
+		if i < offset {
+			histogram[c]++
+		} else {
+			histogram[c]--
+		}
+	}
+	for _, count := range histogram {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
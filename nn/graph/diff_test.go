@@ -0,0 +1,63 @@
+package graph
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	g1 := New("g1")
+	a := g1.AddNode("a", nil)
+	b := g1.AddNode("b", nil)
+	c := g1.AddNode("c", nil)
+	g1.AddEdge(a, b)
+	g1.AddEdge(b, c)
+	g2 := New("g2")
+	a = g2.AddNode("a", nil)
+	b = g2.AddNode("b", nil)
+	d := g2.AddNode("d", nil)
+	g2.AddEdge(a, b)
+	g2.AddEdge(b, d)
+	diff := g1.Diff(&g2)
+	if len(diff.AddedNodes) != 1 || diff.AddedNodes[0] != "d" {
+		t.Errorf("Diff failed. Expected added node d, but got %v", diff.AddedNodes)
+	}
+	if len(diff.RemovedNodes) != 1 || diff.RemovedNodes[0] != "c" {
+		t.Errorf("Diff failed. Expected removed node c, but got %v", diff.RemovedNodes)
+	}
+	if len(diff.AddedEdges) != 1 || diff.AddedEdges[0] != (EdgeName{"b", "d"}) {
+		t.Errorf("Diff failed. Expected added edge b -> d, but got %v", diff.AddedEdges)
+	}
+	if len(diff.RemovedEdges) != 1 || diff.RemovedEdges[0] != (EdgeName{"b", "c"}) {
+		t.Errorf("Diff failed. Expected removed edge b -> c, but got %v", diff.RemovedEdges)
+	}
+	if self := g1.Diff(&g1); !self.Empty() {
+		t.Errorf("Diff failed. Expected empty diff, but got %v", self)
+	}
+}
+
+func TestMaybeIsomorphic(t *testing.T) {
+	// path a -> b -> c and the same path with other names and order
+	g1 := New("g1")
+	g1.AddNode("a", nil)
+	g1.AddNode("b", nil)
+	g1.AddNode("c", nil)
+	g1.AddEdge(0, 1)
+	g1.AddEdge(1, 2)
+	g2 := New("g2")
+	g2.AddNode("z", nil)
+	g2.AddNode("y", nil)
+	g2.AddNode("x", nil)
+	g2.AddEdge(2, 0)
+	g2.AddEdge(0, 1)
+	if !g1.MaybeIsomorphic(&g2) {
+		t.Errorf("MaybeIsomorphic failed. Expected true for paths")
+	}
+	// star with the same node and edge counts
+	g3 := New("g3")
+	g3.AddNode("a", nil)
+	g3.AddNode("b", nil)
+	g3.AddNode("c", nil)
+	g3.AddEdge(0, 1)
+	g3.AddEdge(0, 2)
+	if g1.MaybeIsomorphic(&g3) {
+		t.Errorf("MaybeIsomorphic failed. Expected false for path and star")
+	}
+}
@@ -0,0 +1,99 @@
+// Package gen generates random and data driven graphs
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrInvalidSize        = errors.New("graph size is not greater or equal to zero")
+	ErrInvalidProbability = errors.New("probability is not in range [0, 1]")
+	ErrInvalidDegree      = errors.New("degree is not in range [1, n)")
+)
+
+// add n nodes named by its index
+func addNodes(g *graph.Graph, n int) {
+	for i := 0; i < n; i++ {
+		g.AddNode(fmt.Sprint(i), i)
+	}
+}
+
+// Erdős–Rényi random graph G(n, p)
+//
+// Every pair of nodes i < j is linked by an edge i -> j with probability p
+func ErdosRenyi(n int, p float64, seed int64) graph.Graph {
+	if n < 0 {
+		panic(ErrInvalidSize)
+	}
+	if p < 0 || p > 1 {
+		panic(ErrInvalidProbability)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	g := graph.New("erdos_renyi")
+	addNodes(&g, n)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if rnd.Float64() < p {
+				g.AddEdge(i, j)
+			}
+		}
+	}
+	return g
+}
+
+// Barabási–Albert preferential attachment graph
+//
+// It starts with m nodes and every new node adds m edges new -> old to distinct nodes chosen with probability
+// proportional to their degree
+func BarabasiAlbert(n, m int, seed int64) graph.Graph {
+	if n < 0 {
+		panic(ErrInvalidSize)
+	}
+	if m < 1 || m >= n {
+		panic(ErrInvalidDegree)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	g := graph.New("barabasi_albert")
+	addNodes(&g, n)
+	// every node appears in repeated once for each edge it touches
+	repeated := make([]int, 0, 2*n*m)
+	targets := make([]int, m)
+	for i := range targets {
+		targets[i] = i
+	}
+	chosen := make(map[int]bool, m)
+	for src := m; src < n; src++ {
+		for _, dst := range targets {
+			g.AddEdge(src, dst)
+			repeated = append(repeated, src, dst)
+		}
+		// choose targets of next node
+		for k := range chosen {
+			delete(chosen, k)
+		}
+		targets = targets[:0]
+		for len(targets) < m {
+			dst := repeated[rnd.Intn(len(repeated))]
+			if !chosen[dst] {
+				chosen[dst] = true
+				targets = append(targets, dst)
+			}
+		}
+	}
+	return g
+}
+
+// k-nearest neighbor graph
+//
+// Every point is a node with the point as value and it has an edge of weight one to each of its k nearest points by the given distance
+func KNN(points []knn.Point, k int, dist knn.Distance) graph.Graph {
+	if k < 1 || k >= len(points) {
+		panic(ErrInvalidDegree)
+	}
+	return knn.BuildKNNGraph(points, k, dist, knn.UnitWeight)
+}
@@ -0,0 +1,53 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func TestErdosRenyi(t *testing.T) {
+	g := ErdosRenyi(10, 1, 1)
+	for i := 0; i < 10; i++ {
+		for j := i + 1; j < 10; j++ {
+			if !g.HasEdge(i, j) {
+				t.Fatalf("ErdosRenyi failed. Expected edge %d -> %d", i, j)
+			}
+		}
+	}
+	empty := ErdosRenyi(10, 0, 1)
+	for i := 0; i < 10; i++ {
+		if len(empty.OutEdges(i)) != 0 {
+			t.Fatalf("ErdosRenyi failed. Expected no edges from %d", i)
+		}
+	}
+}
+
+func TestBarabasiAlbert(t *testing.T) {
+	n, m := 50, 3
+	g := BarabasiAlbert(n, m, 1)
+	if g.LenNodes() != n {
+		t.Fatalf("BarabasiAlbert failed. Expected %d nodes, but got %d", n, g.LenNodes())
+	}
+	for i := m; i < n; i++ {
+		if out := g.OutEdges(i); len(out) != m {
+			t.Errorf("BarabasiAlbert failed. Expected %d edges from %d, but got %v", m, i, out)
+		}
+	}
+}
+
+func TestKNN(t *testing.T) {
+	points := []knn.Point{
+		knn.WithPoint(0, 0),
+		knn.WithPoint(0, 1),
+		knn.WithPoint(10, 10),
+		knn.WithPoint(10, 11),
+	}
+	g := KNN(points, 1, knn.NewEuclideanDist())
+	if !g.HasEdge(0, 1) || !g.HasEdge(1, 0) || !g.HasEdge(2, 3) || !g.HasEdge(3, 2) {
+		t.Errorf("KNN failed. Unexpected graph %s", g.String())
+	}
+	if g.HasEdge(1, 2) {
+		t.Errorf("KNN failed. Unexpected edge 1 -> 2")
+	}
+}
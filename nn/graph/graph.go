@@ -1,8 +1,12 @@
 package graph
 
 import (
+	"bufio"
 	"errors"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 	"unicode"
 )
 
@@ -48,9 +52,10 @@ func (n *Node) String() string {
 
 // Graph (digraph)
 type Graph struct {
-	name     string  //graph name
-	vertices []*Node //graph vertices
-	edges    [][]int //gragh edges
+	name     string      //graph name
+	vertices []*Node     //graph vertices
+	edges    [][]int     //gragh edges
+	weights  [][]float64 //weights of graph edges
 }
 
 // Create a graph
@@ -59,6 +64,7 @@ func New(name string) Graph {
 		name:     name,
 		vertices: make([]*Node, 0, 100),
 		edges:    make([][]int, 0, 100),
+		weights:  make([][]float64, 0, 100),
 	}
 }
 
@@ -72,18 +78,38 @@ func (graph *Graph) AddNode(name string, value any) int {
 	vid := len(graph.vertices)
 	graph.vertices = append(graph.vertices, &Node{name: name, value: value})
 	graph.edges = append(graph.edges, []int{})
+	graph.weights = append(graph.weights, []float64{})
 	return vid
 }
 
-// Add edge to graph
+// Add edge to graph with weight one
 func (graph *Graph) AddEdge(src, dst int) error {
+	return graph.AddWeightedEdge(src, dst, 1)
+}
+
+// Add edge with weight to graph
+func (graph *Graph) AddWeightedEdge(src, dst int, weight float64) error {
 	if src < 0 || dst < 0 || src >= len(graph.vertices) || dst >= len(graph.vertices) {
 		return ErrNodeNoExist
 	}
 	graph.edges[dst] = append(graph.edges[dst], src)
+	graph.weights[dst] = append(graph.weights[dst], weight)
 	return nil
 }
 
+// Get weight of edge, it returns false if edge doesn't exist
+func (graph *Graph) EdgeWeight(src, dst int) (float64, bool) {
+	if src < 0 || src >= len(graph.vertices) || dst < 0 || dst >= len(graph.vertices) {
+		return 0, false
+	}
+	for i, s := range graph.edges[dst] {
+		if s == src {
+			return graph.weights[dst][i], true
+		}
+	}
+	return 0, false
+}
+
 // Remove edge from graph
 func (graph *Graph) RemoveEdge(src, dst int) bool {
 	if src < 0 || src > len(graph.vertices) || dst < 0 || dst > len(graph.vertices) {
@@ -94,6 +120,7 @@ func (graph *Graph) RemoveEdge(src, dst int) bool {
 		if srcLs[i] == src {
 			srcLs = append(srcLs[:i], srcLs[i+1:]...)
 			graph.edges[dst] = srcLs
+			graph.weights[dst] = append(graph.weights[dst][:i], graph.weights[dst][i+1:]...)
 			return true
 		}
 	}
@@ -153,12 +180,14 @@ func (graph *Graph) RemoveNodeAt(index int) bool {
 		return false
 	}
 	graph.edges = append(graph.edges[:index], graph.edges[index+1:]...)
+	graph.weights = append(graph.weights[:index], graph.weights[index+1:]...)
 	graph.vertices = append(graph.vertices[:index], graph.vertices[index+1:]...)
 	for i := range graph.edges {
 		srcLs := graph.edges[i]
 		for j := 0; j < len(srcLs); {
 			if srcLs[j] == index {
 				srcLs = append(srcLs[:j], srcLs[j+1:]...)
+				graph.weights[i] = append(graph.weights[i][:j], graph.weights[i][j+1:]...)
 			} else {
 				if srcLs[j] > index {
 					srcLs[j]--
@@ -181,28 +210,61 @@ func (graph *Graph) LenEdges() int {
 	return len(graph.edges)
 }
 
+// Options of DOT rendering
+type DotOptions struct {
+	MaxNodes int //nodes with index greater or equal to MaxNodes are elided with its edges, zero renders every node
+}
+
+// Write dot representation to file
 func (graph *Graph) ToDot(fileName string) error {
 	file, err := os.Create(fileName)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	file.Write([]byte(graph.String()))
-	return nil
+	if err := graph.WriteDot(file, DotOptions{}); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
 }
 
-// Dot representation
-func (graph *Graph) String() string {
-	digraph := "digraph " + prepareName(graph.name) + "{\n"
-	for i := range graph.edges {
+// Write dot representation to a writer using a buffer, so big graphs are not built in memory
+func (graph *Graph) WriteDot(w io.Writer, opts DotOptions) error {
+	limit := len(graph.vertices)
+	if opts.MaxNodes > 0 && opts.MaxNodes < limit {
+		limit = opts.MaxNodes
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph ")
+	bw.WriteString(prepareName(graph.name))
+	bw.WriteString("{\n")
+	for i := 0; i < limit; i++ {
 		srcLs := graph.edges[i]
-		dst := graph.vertices[i]
-		for j := range srcLs {
-			src := graph.vertices[srcLs[j]]
-			digraph += src.String() + " -> " + dst.String() + "\n"
+		dst := graph.vertices[i].String()
+		for _, src := range srcLs {
+			if src >= limit {
+				continue
+			}
+			bw.WriteString(graph.vertices[src].String())
+			bw.WriteString(" -> ")
+			bw.WriteString(dst)
+			bw.WriteString("\n")
 		}
 	}
-	return digraph + "}"
+	if elided := len(graph.vertices) - limit; elided > 0 {
+		bw.WriteString("// ")
+		bw.WriteString(strconv.Itoa(elided))
+		bw.WriteString(" nodes elided\n")
+	}
+	bw.WriteString("}")
+	return bw.Flush()
+}
+
+// Dot representation
+func (graph *Graph) String() string {
+	var sb strings.Builder
+	graph.WriteDot(&sb, DotOptions{})
+	return sb.String()
 }
 
 // DFS (Depth-First Search)
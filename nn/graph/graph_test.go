@@ -2,6 +2,7 @@ package graph
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -156,3 +157,46 @@ func TestHasCycle(t *testing.T) {
 		t.Errorf("g2 no debería tener ciclo")
 	}
 }
+
+func TestWriteDot(t *testing.T) {
+	g := New("G")
+	a := g.AddNode("a", 0)
+	b := g.AddNode("b", 0)
+	c := g.AddNode("c", 0)
+	g.AddEdge(a, b)
+	g.AddEdge(b, c)
+	if s := g.String(); s != "digraph G{\na -> b\nb -> c\n}" {
+		t.Errorf("String failed. Unexpected dot %q", s)
+	}
+	var sb strings.Builder
+	if err := g.WriteDot(&sb, DotOptions{MaxNodes: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if s := sb.String(); s != "digraph G{\na -> b\n// 1 nodes elided\n}" {
+		t.Errorf("WriteDot failed. Unexpected dot %q", s)
+	}
+}
+
+func TestEdgeWeight(t *testing.T) {
+	g := New("G")
+	a := g.AddNode("a", 0)
+	b := g.AddNode("b", 0)
+	c := g.AddNode("c", 0)
+	g.AddEdge(a, b)
+	g.AddWeightedEdge(b, c, 2.5)
+	g.AddWeightedEdge(a, c, 0.5)
+	if w, ok := g.EdgeWeight(a, b); !ok || w != 1 {
+		t.Errorf("EdgeWeight failed. Expected 1, but got %v", w)
+	}
+	g.RemoveEdge(b, c)
+	if w, ok := g.EdgeWeight(a, c); !ok || w != 0.5 {
+		t.Errorf("EdgeWeight failed. Expected 0.5, but got %v", w)
+	}
+	g.RemoveNodeAt(b)
+	if w, ok := g.EdgeWeight(0, 1); !ok || w != 0.5 {
+		t.Errorf("EdgeWeight failed. Expected 0.5 after node removal, but got %v", w)
+	}
+	if _, ok := g.EdgeWeight(1, 0); ok {
+		t.Errorf("EdgeWeight failed. Unexpected edge 1 -> 0")
+	}
+}
@@ -129,18 +129,10 @@ func NewTensor(data any, typ Type, shape Shape) *Tensor {
 		}
 		if typ == Float32 {
 			// convert float16 to float32
-			aux := make([]float32, len(v))
-			for i, c := 0, len(aux); i < c; i++ {
-				aux[i] = v[i].ToF32()
-			}
-			data = aux
+			data = float16.F16ToF32Slice(v)
 		} else if typ == Float64 {
 			// convert float16 to float64
-			aux := make([]float64, len(v))
-			for i, c := 0, len(aux); i < c; i++ {
-				aux[i] = v[i].ToF64()
-			}
-			data = aux
+			data = float16.F16ToF64Slice(v)
 		}
 	case []float64:
 		// validate slice len with shape len
@@ -149,11 +141,7 @@ func NewTensor(data any, typ Type, shape Shape) *Tensor {
 		}
 		if typ == Float16 {
 			// convert float64 to float16
-			aux := make([]float16.Float16, len(v))
-			for i, c := 0, len(aux); i < c; i++ {
-				aux[i] = float16.FF64(v[i])
-			}
-			data = aux
+			data = float16.F64ToF16Slice(v)
 		} else if typ == Float32 {
 			// convert float64 to float32
 			aux := make([]float32, len(v))
@@ -169,11 +157,7 @@ func NewTensor(data any, typ Type, shape Shape) *Tensor {
 		}
 		if typ == Float16 {
 			// convert float32 to float16
-			aux := make([]float16.Float16, len(v))
-			for i, c := 0, len(aux); i < c; i++ {
-				aux[i] = float16.FF32(v[i])
-			}
-			data = aux
+			data = float16.F32ToF16Slice(v)
 		} else if typ == Float64 {
 			// convert float32 to float64
 			aux := make([]float64, len(v))
@@ -201,6 +185,11 @@ func (ts *Tensor) Rank() int {
 	return ts.rank
 }
 
+// Element type of tensor
+func (ts *Tensor) Type() Type {
+	return ts.typ
+}
+
 // Get tensor float16 slice
 //
 // panics if type is not Float16
@@ -0,0 +1,185 @@
+// Package layers implements feed-forward neural networks trained by backpropagation
+//
+// Parameters and gradients of every layer of a network are views of two flat vectors, so any optimizer of
+// package optim updates the whole network with one step.
+package layers
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+var (
+	ErrInputMismatch   = errors.New("input length doesn't match layer input")
+	ErrNetworkMismatch = errors.New("networks don't have the same architecture")
+	ErrNoForward       = errors.New("backward is called before forward")
+	ErrLayerNotValid   = errors.New("layer is not valid")
+)
+
+// Layer of a network, it keeps the input of the last forward for backward
+type Layer interface {
+	// number of parameters
+	Size() int
+	// bind parameters and gradients to views of network vectors
+	bind(params, grads []float64)
+	// initialize parameters
+	init(rnd *rand.Rand)
+	Forward(x []float64) []float64
+	// accumulate gradients of parameters and return gradient of input
+	Backward(grad []float64) []float64
+	clone() Layer
+}
+
+// Fully connected layer y = W x + b
+type Dense struct {
+	In, Out int
+	w, b    []float64
+	gw, gb  []float64
+	x       []float64
+}
+
+// Create fully connected layer
+func NewDense(in, out int) *Dense {
+	return &Dense{In: in, Out: out}
+}
+
+func (d *Dense) Size() int {
+	return d.In*d.Out + d.Out
+}
+
+func (d *Dense) bind(params, grads []float64) {
+	d.w, d.b = params[:d.In*d.Out], params[d.In*d.Out:]
+	d.gw, d.gb = grads[:d.In*d.Out], grads[d.In*d.Out:]
+}
+
+// He initialization, bias is zero
+func (d *Dense) init(rnd *rand.Rand) {
+	std := math.Sqrt(2 / float64(d.In))
+	for i := range d.w {
+		d.w[i] = std * rnd.NormFloat64()
+	}
+	for i := range d.b {
+		d.b[i] = 0
+	}
+}
+
+func (d *Dense) Forward(x []float64) []float64 {
+	if len(x) != d.In {
+		panic(ErrInputMismatch)
+	}
+	d.x = x
+	y := make([]float64, d.Out)
+	for o := range y {
+		sum := d.b[o]
+		row := d.w[o*d.In : (o+1)*d.In]
+		for i, v := range x {
+			sum += row[i] * v
+		}
+		y[o] = sum
+	}
+	return y
+}
+
+func (d *Dense) Backward(grad []float64) []float64 {
+	if d.x == nil {
+		panic(ErrNoForward)
+	}
+	gx := make([]float64, d.In)
+	for o, g := range grad {
+		d.gb[o] += g
+		row := d.w[o*d.In : (o+1)*d.In]
+		grow := d.gw[o*d.In : (o+1)*d.In]
+		for i, v := range d.x {
+			grow[i] += g * v
+			gx[i] += g * row[i]
+		}
+	}
+	return gx
+}
+
+// Weights by rows of outputs
+func (d *Dense) Weights() []float64 {
+	return d.w
+}
+
+// Bias of outputs
+func (d *Dense) Bias() []float64 {
+	return d.b
+}
+
+func (d *Dense) clone() Layer {
+	return NewDense(d.In, d.Out)
+}
+
+// activation layer of an elementwise function and its derivative by input and output
+type activation struct {
+	name  string
+	fn    func(x float64) float64
+	deriv func(x, y float64) float64
+	x, y  []float64
+}
+
+func (a *activation) Size() int {
+	return 0
+}
+
+func (a *activation) bind(params, grads []float64) {}
+
+func (a *activation) init(rnd *rand.Rand) {}
+
+func (a *activation) Forward(x []float64) []float64 {
+	a.x = x
+	a.y = make([]float64, len(x))
+	for i, v := range x {
+		a.y[i] = a.fn(v)
+	}
+	return a.y
+}
+
+func (a *activation) Backward(grad []float64) []float64 {
+	if a.x == nil {
+		panic(ErrNoForward)
+	}
+	gx := make([]float64, len(grad))
+	for i, g := range grad {
+		gx[i] = g * a.deriv(a.x[i], a.y[i])
+	}
+	return gx
+}
+
+func (a *activation) clone() Layer {
+	return &activation{name: a.name, fn: a.fn, deriv: a.deriv}
+}
+
+// Create rectified linear activation
+func NewReLU() Layer {
+	return &activation{
+		name: "relu",
+		fn:   func(x float64) float64 { return math.Max(x, 0) },
+		deriv: func(x, y float64) float64 {
+			if x > 0 {
+				return 1
+			}
+			return 0
+		},
+	}
+}
+
+// Create hyperbolic tangent activation
+func NewTanh() Layer {
+	return &activation{
+		name:  "tanh",
+		fn:    math.Tanh,
+		deriv: func(x, y float64) float64 { return 1 - y*y },
+	}
+}
+
+// Create logistic sigmoid activation
+func NewSigmoid() Layer {
+	return &activation{
+		name:  "sigmoid",
+		fn:    func(x float64) float64 { return 1 / (1 + math.Exp(-x)) },
+		deriv: func(x, y float64) float64 { return y * (1 - y) },
+	}
+}
@@ -0,0 +1,104 @@
+package layers
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+func TestGradient(t *testing.T) {
+	net := NewSequential(1, NewDense(3, 4), NewTanh(), NewDense(4, 3), NewSigmoid(), NewDense(3, 2))
+	x := []float64{0.5, -1, 2}
+	target := []float64{1, -1}
+	loss := func() float64 {
+		l, _ := MSELoss(net.Forward(x), target)
+		return l
+	}
+	net.ZeroGrad()
+	_, grad := MSELoss(net.Forward(x), target)
+	net.Backward(grad)
+	// gradients match central differences
+	for i := range net.Params() {
+		old := net.Params()[i]
+		net.Params()[i] = old + 1e-6
+		up := loss()
+		net.Params()[i] = old - 1e-6
+		down := loss()
+		net.Params()[i] = old
+		if numeric := (up - down) / 2e-6; math.Abs(numeric-net.Grads()[i]) > 1e-6 {
+			t.Fatalf("Backward failed. Expected gradient %v of parameter %d, but got %v", numeric, i, net.Grads()[i])
+		}
+	}
+}
+
+func TestXOR(t *testing.T) {
+	inputs := [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	classes := []int{0, 1, 1, 0}
+	net := NewSequential(2, NewDense(2, 8), NewReLU(), NewDense(8, 2))
+	opt := optim.NewAdam(0.05)
+	for epoch := 0; epoch < 500; epoch++ {
+		net.ZeroGrad()
+		for i, x := range inputs {
+			_, grad := CrossEntropyLoss(net.Forward(x), classes[i])
+			net.Backward(grad)
+		}
+		opt.Step(net.Params(), net.Grads())
+	}
+	for i, x := range inputs {
+		if p := Softmax(net.Forward(x)); p[classes[i]] < 0.9 {
+			t.Errorf("XOR failed. Expected class %d for %v, but got %v", classes[i], x, p)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	net := NewSequential(3, NewDense(2, 2), NewReLU(), NewDense(2, 1))
+	clone := net.Clone()
+	x := []float64{1, 2}
+	if net.Forward(x)[0] != clone.Forward(x)[0] {
+		t.Errorf("Clone failed. Outputs are not the same")
+	}
+	clone.Params()[0] += 1
+	if net.Params()[0] == clone.Params()[0] {
+		t.Errorf("Clone failed. Parameters are shared")
+	}
+	net.CopyFrom(clone)
+	if net.Forward(x)[0] != clone.Forward(x)[0] {
+		t.Errorf("CopyFrom failed. Outputs are not the same")
+	}
+}
+
+func TestHuberLoss(t *testing.T) {
+	loss, grad := HuberLoss([]float64{0, 3}, []float64{0.5, 0}, 1)
+	if math.Abs(loss-(0.125+2.5)/2) > 1e-12 || grad[0] != -0.25 || grad[1] != 0.5 {
+		t.Errorf("HuberLoss failed. Unexpected loss %v and gradient %v", loss, grad)
+	}
+}
+
+func TestSequentialGob(t *testing.T) {
+	net := NewSequential(3, NewDense(3, 4), NewReLU(), NewDense(4, 2), NewTanh())
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(net); err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Sequential{}
+	if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+		t.Fatal(err)
+	}
+	x := []float64{1, -2, 0.5}
+	if a, b := net.Forward(x), decoded.Forward(x); a[0] != b[0] || a[1] != b[1] {
+		t.Errorf("GobDecode failed. Expected output %v, but got %v", a, b)
+	}
+	b, _ := net.GobEncode()
+	net.params = net.params[:len(net.params)-1]
+	short, _ := net.GobEncode()
+	if err := decoded.GobDecode(short); err != ErrNetworkMismatch {
+		t.Errorf("GobDecode failed. Expected %v, but got %v", ErrNetworkMismatch, err)
+	}
+	if err := decoded.GobDecode(b); err != nil {
+		t.Errorf("GobDecode failed. Expected no error, but got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package layers
+
+import "math"
+
+// Mean squared error of prediction and its gradient
+func MSELoss(pred, target []float64) (float64, []float64) {
+	if len(pred) != len(target) {
+		panic(ErrInputMismatch)
+	}
+	loss := 0.0
+	grad := make([]float64, len(pred))
+	n := float64(len(pred))
+	for i := range pred {
+		dif := pred[i] - target[i]
+		loss += dif * dif / n
+		grad[i] = 2 * dif / n
+	}
+	return loss, grad
+}
+
+// Huber loss of prediction with threshold delta and its gradient, it is quadratic near target and linear far
+func HuberLoss(pred, target []float64, delta float64) (float64, []float64) {
+	if len(pred) != len(target) {
+		panic(ErrInputMismatch)
+	}
+	loss := 0.0
+	grad := make([]float64, len(pred))
+	n := float64(len(pred))
+	for i := range pred {
+		dif := pred[i] - target[i]
+		if math.Abs(dif) <= delta {
+			loss += dif * dif / 2 / n
+			grad[i] = dif / n
+		} else {
+			loss += delta * (math.Abs(dif) - delta/2) / n
+			grad[i] = math.Copysign(delta, dif) / n
+		}
+	}
+	return loss, grad
+}
+
+// Softmax probabilities of logits
+func Softmax(logits []float64) []float64 {
+	greatest := math.Inf(-1)
+	for _, v := range logits {
+		greatest = math.Max(greatest, v)
+	}
+	out := make([]float64, len(logits))
+	sum := 0.0
+	for i, v := range logits {
+		out[i] = math.Exp(v - greatest)
+		sum += out[i]
+	}
+	for i := range out {
+		out[i] /= sum
+	}
+	return out
+}
+
+// Cross entropy of softmax of logits with class and its gradient by logits
+func CrossEntropyLoss(logits []float64, class int) (float64, []float64) {
+	if class < 0 || class >= len(logits) {
+		panic(ErrInputMismatch)
+	}
+	grad := Softmax(logits)
+	loss := -math.Log(math.Max(grad[class], 1e-300))
+	grad[class]--
+	return loss, grad
+}
@@ -0,0 +1,161 @@
+package layers
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math/rand"
+)
+
+// Network of layers applied in order
+type Sequential struct {
+	layers []Layer
+	params []float64
+	grads  []float64
+}
+
+// Create network of layers with parameters initialized from seed
+func NewSequential(seed int64, layers ...Layer) *Sequential {
+	net := newSequential(layers)
+	rnd := rand.New(rand.NewSource(seed))
+	for _, l := range net.layers {
+		l.init(rnd)
+	}
+	return net
+}
+
+func newSequential(layers []Layer) *Sequential {
+	size := 0
+	for _, l := range layers {
+		size += l.Size()
+	}
+	net := &Sequential{layers: layers, params: make([]float64, size), grads: make([]float64, size)}
+	offset := 0
+	for _, l := range layers {
+		n := l.Size()
+		l.bind(net.params[offset:offset+n:offset+n], net.grads[offset:offset+n:offset+n])
+		offset += n
+	}
+	return net
+}
+
+// Layers of network
+func (net *Sequential) Layers() []Layer {
+	return net.layers
+}
+
+// Parameters of every layer, changes are seen by layers
+func (net *Sequential) Params() []float64 {
+	return net.params
+}
+
+// Accumulated gradients of every parameter
+func (net *Sequential) Grads() []float64 {
+	return net.grads
+}
+
+// Clear accumulated gradients
+func (net *Sequential) ZeroGrad() {
+	for i := range net.grads {
+		net.grads[i] = 0
+	}
+}
+
+// Output of network for input x
+func (net *Sequential) Forward(x []float64) []float64 {
+	for _, l := range net.layers {
+		x = l.Forward(x)
+	}
+	return x
+}
+
+// Backpropagate gradient of output of the last forward, gradients of parameters are accumulated
+func (net *Sequential) Backward(grad []float64) []float64 {
+	for i := len(net.layers) - 1; i >= 0; i-- {
+		grad = net.layers[i].Backward(grad)
+	}
+	return grad
+}
+
+// Network with the same architecture and a copy of parameters
+func (net *Sequential) Clone() *Sequential {
+	layers := make([]Layer, len(net.layers))
+	for i, l := range net.layers {
+		layers[i] = l.clone()
+	}
+	out := newSequential(layers)
+	copy(out.params, net.params)
+	return out
+}
+
+// Copy parameters of other network with the same architecture
+func (net *Sequential) CopyFrom(other *Sequential) {
+	if len(net.params) != len(other.params) {
+		panic(ErrNetworkMismatch)
+	}
+	copy(net.params, other.params)
+}
+
+type layerSpec struct {
+	Kind    string //dense or name of activation
+	In, Out int
+}
+
+type sequentialSpec struct {
+	Layers []layerSpec
+	Params []float64
+}
+
+// Encode architecture and parameters of network, it is the native file format of networks
+func (net *Sequential) GobEncode() ([]byte, error) {
+	spec := sequentialSpec{Layers: make([]layerSpec, len(net.layers)), Params: net.params}
+	for i, l := range net.layers {
+		switch l := l.(type) {
+		case *Dense:
+			spec.Layers[i] = layerSpec{Kind: "dense", In: l.In, Out: l.Out}
+		case *activation:
+			spec.Layers[i] = layerSpec{Kind: l.name}
+		default:
+			return nil, fmt.Errorf("%w: %T", ErrLayerNotValid, l)
+		}
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&spec)
+	return buf.Bytes(), err
+}
+
+// Decode network encoded by GobEncode
+func (net *Sequential) GobDecode(b []byte) error {
+	var spec sequentialSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return err
+	}
+	activations := map[string]func() Layer{"relu": NewReLU, "tanh": NewTanh, "sigmoid": NewSigmoid}
+	layers := make([]Layer, len(spec.Layers))
+	size := 0
+	for i, ls := range spec.Layers {
+		if ls.Kind == "dense" {
+			// parameters of layer are checked before allocation of network
+			if ls.In <= 0 || ls.Out <= 0 || ls.In > len(spec.Params) || ls.Out > len(spec.Params) {
+				return fmt.Errorf("%w: dense layer %d of %dx%d", ErrLayerNotValid, i, ls.In, ls.Out)
+			}
+			layers[i] = NewDense(ls.In, ls.Out)
+			if size += layers[i].Size(); size > len(spec.Params) {
+				return ErrNetworkMismatch
+			}
+			continue
+		}
+		create, ok := activations[ls.Kind]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrLayerNotValid, ls.Kind)
+		}
+		layers[i] = create()
+	}
+	if size != len(spec.Params) {
+		return ErrNetworkMismatch
+	}
+	decoded := newSequential(layers)
+	copy(decoded.params, spec.Params)
+	*net = *decoded
+	return nil
+}
@@ -0,0 +1,528 @@
+// Package onnx imports feed-forward networks of ONNX models as networks of package layers
+//
+// Only the subset of ONNX used by exported multilayer perceptrons is supported: a chain of Gemm or MatMul and Add
+// nodes with weights in initializers, Relu, Tanh and Sigmoid activations and the shape preserving nodes Flatten,
+// Identity and Dropout. A Softmax or LogSoftmax node at the end is dropped, so imported networks output logits.
+package onnx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/stellviaproject/go-ia/float16"
+	"github.com/stellviaproject/go-ia/nn/layers"
+)
+
+var (
+	ErrInvalidModel     = errors.New("onnx model is not valid")
+	ErrOpNotSupported   = errors.New("onnx operator is not supported")
+	ErrNotSequential    = errors.New("onnx graph is not a chain of layers")
+	ErrTypeNotSupported = errors.New("onnx tensor type is not supported")
+)
+
+// element types of tensors
+const (
+	typeFloat   = 1
+	typeFloat16 = 10
+	typeDouble  = 11
+)
+
+type tensor struct {
+	name   string
+	dims   []int64
+	dtype  int64
+	values []float64
+	int32s []int64
+	raw    []byte
+}
+
+// number of elements of tensor
+func (t *tensor) size() int64 {
+	n := int64(1)
+	for _, d := range t.dims {
+		if d < 0 || (d > 0 && n > math.MaxInt32/d) {
+			return -1
+		}
+		n *= d
+	}
+	return n
+}
+
+// values of tensor as float64
+func (t *tensor) floats() ([]float64, error) {
+	values := t.values
+	switch {
+	case t.raw != nil && t.dtype == typeFloat:
+		if len(t.raw)%4 != 0 {
+			return nil, ErrInvalidModel
+		}
+		values = make([]float64, len(t.raw)/4)
+		for i := range values {
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(t.raw[4*i:])))
+		}
+	case t.raw != nil && t.dtype == typeDouble:
+		if len(t.raw)%8 != 0 {
+			return nil, ErrInvalidModel
+		}
+		values = make([]float64, len(t.raw)/8)
+		for i := range values {
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(t.raw[8*i:]))
+		}
+	case t.dtype == typeFloat16:
+		bits := t.int32s
+		if t.raw != nil {
+			if len(t.raw)%2 != 0 {
+				return nil, ErrInvalidModel
+			}
+			bits = make([]int64, len(t.raw)/2)
+			for i := range bits {
+				bits[i] = int64(binary.LittleEndian.Uint16(t.raw[2*i:]))
+			}
+		}
+		values = make([]float64, len(bits))
+		for i, b := range bits {
+			values[i] = float64(float16.Float16(b).ToF32())
+		}
+	case t.dtype != typeFloat && t.dtype != typeDouble:
+		return nil, fmt.Errorf("%w: %d of %s", ErrTypeNotSupported, t.dtype, t.name)
+	}
+	if int64(len(values)) != t.size() {
+		return nil, fmt.Errorf("%w: %d values of %s with dims %v", ErrInvalidModel, len(values), t.name, t.dims)
+	}
+	return values, nil
+}
+
+func parseTensor(b []byte) (*tensor, error) {
+	t := &tensor{}
+	r := &reader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return t, err
+		}
+		switch {
+		case field == 1:
+			t.dims, err = r.varints(wire, t.dims)
+		case field == 2 && wire == wireVarint:
+			var v uint64
+			v, err = r.varint()
+			t.dtype = int64(v)
+		case field == 4:
+			t.values, err = r.floats(wire, t.values)
+		case field == 5:
+			t.int32s, err = r.varints(wire, t.int32s)
+		case field == 8 && wire == wireBytes:
+			var name []byte
+			name, err = r.bytes()
+			t.name = string(name)
+		case field == 9 && wire == wireBytes:
+			t.raw, err = r.bytes()
+		case field == 10:
+			t.values, err = r.doubles(wire, t.values)
+		case field == 14 && wire == wireVarint:
+			var location uint64
+			if location, err = r.varint(); err == nil && location != 0 {
+				err = fmt.Errorf("%w: external data of %s", ErrTypeNotSupported, t.name)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+type attribute struct {
+	f float64
+	i int64
+}
+
+type node struct {
+	op      string
+	inputs  []string
+	outputs []string
+	attrs   map[string]attribute
+}
+
+// integer attribute of node or def if it is missing
+func (n *node) int(name string, def int64) int64 {
+	if a, ok := n.attrs[name]; ok {
+		return a.i
+	}
+	return def
+}
+
+// float attribute of node or def if it is missing
+func (n *node) float(name string, def float64) float64 {
+	if a, ok := n.attrs[name]; ok {
+		return a.f
+	}
+	return def
+}
+
+func parseAttribute(b []byte) (string, attribute, error) {
+	var name string
+	var a attribute
+	r := &reader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return name, a, err
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			var s []byte
+			s, err = r.bytes()
+			name = string(s)
+		case field == 2 && wire == wireFixed32:
+			var v uint32
+			v, err = r.fixed32()
+			a.f = float64(math.Float32frombits(v))
+		case field == 3 && wire == wireVarint:
+			var v uint64
+			v, err = r.varint()
+			a.i = int64(v)
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return "", a, err
+		}
+	}
+}
+
+func parseNode(b []byte) (*node, error) {
+	n := &node{attrs: make(map[string]attribute)}
+	r := &reader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return n, err
+		}
+		if wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		switch field {
+		case 1:
+			n.inputs = append(n.inputs, string(value))
+		case 2:
+			n.outputs = append(n.outputs, string(value))
+		case 4:
+			n.op = string(value)
+		case 5:
+			name, a, err := parseAttribute(value)
+			if err != nil {
+				return nil, err
+			}
+			n.attrs[name] = a
+		}
+	}
+}
+
+// name of a ValueInfoProto
+func parseValueName(b []byte) (string, error) {
+	r := &reader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return "", err
+		}
+		if field == 1 && wire == wireBytes {
+			name, err := r.bytes()
+			return string(name), err
+		}
+		if err := r.skip(wire); err != nil {
+			return "", err
+		}
+	}
+}
+
+type graph struct {
+	nodes   []*node
+	init    map[string]*tensor
+	inputs  []string
+	outputs []string
+}
+
+func parseGraph(b []byte) (*graph, error) {
+	g := &graph{init: make(map[string]*tensor)}
+	r := &reader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return g, err
+		}
+		if wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		switch field {
+		case 1:
+			n, err := parseNode(value)
+			if err != nil {
+				return nil, err
+			}
+			g.nodes = append(g.nodes, n)
+		case 5:
+			t, err := parseTensor(value)
+			if err != nil {
+				return nil, err
+			}
+			g.init[t.name] = t
+		case 11, 12:
+			name, err := parseValueName(value)
+			if err != nil {
+				return nil, err
+			}
+			if field == 11 {
+				g.inputs = append(g.inputs, name)
+			} else {
+				g.outputs = append(g.outputs, name)
+			}
+		}
+	}
+}
+
+// graph of a ModelProto
+func parseModel(b []byte) (*graph, error) {
+	r := &reader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: model has no graph", ErrInvalidModel)
+		}
+		if field == 7 && wire == wireBytes {
+			value, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			return parseGraph(value)
+		}
+		if err := r.skip(wire); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// dense layer being imported, w by rows of outputs
+type dense struct {
+	in, out int
+	w, b    []float64
+}
+
+// network being imported from the chain of nodes
+type builder struct {
+	g       *graph
+	current string //output of the last imported node
+	layers  []any  //*dense or activation constructors
+	width   int    //outputs of the last dense layer, zero before the first one
+}
+
+// weights of initializer name of a matrix with rows by cols
+func (bd *builder) matrix(name string) ([]float64, int, int, error) {
+	t, ok := bd.g.init[name]
+	if !ok {
+		return nil, 0, 0, fmt.Errorf("%w: weights %s are not an initializer", ErrNotSequential, name)
+	}
+	if len(t.dims) != 2 || t.dims[0] <= 0 || t.dims[1] <= 0 {
+		return nil, 0, 0, fmt.Errorf("%w: weights %s of dims %v", ErrInvalidModel, name, t.dims)
+	}
+	values, err := t.floats()
+	return values, int(t.dims[0]), int(t.dims[1]), err
+}
+
+// add dense layer of weights w of rows by cols, w is transposed if it is stored by rows of inputs
+func (bd *builder) dense(w []float64, rows, cols int, byInputs bool, alpha float64) error {
+	in, out := cols, rows
+	if byInputs {
+		in, out = rows, cols
+	}
+	if bd.width != 0 && in != bd.width {
+		return fmt.Errorf("%w: layer of %d inputs after layer of %d outputs", ErrInvalidModel, in, bd.width)
+	}
+	d := &dense{in: in, out: out, w: make([]float64, in*out), b: make([]float64, out)}
+	for o := 0; o < out; o++ {
+		for i := 0; i < in; i++ {
+			v := w[o*in+i]
+			if byInputs {
+				v = w[i*out+o]
+			}
+			d.w[o*in+i] = alpha * v
+		}
+	}
+	bd.layers = append(bd.layers, d)
+	bd.width = out
+	return nil
+}
+
+// add bias of initializer name scaled by beta to the last dense layer
+func (bd *builder) bias(name string, beta float64) error {
+	t, ok := bd.g.init[name]
+	if !ok {
+		return fmt.Errorf("%w: bias %s is not an initializer", ErrNotSequential, name)
+	}
+	values, err := t.floats()
+	if err != nil {
+		return err
+	}
+	d, ok := bd.layers[len(bd.layers)-1].(*dense)
+	if !ok {
+		return fmt.Errorf("%w: bias %s does not follow a dense layer", ErrNotSequential, name)
+	}
+	if len(values) != 1 && len(values) != d.out {
+		return fmt.Errorf("%w: bias %s of %d values for %d outputs", ErrInvalidModel, name, len(values), d.out)
+	}
+	for o := range d.b {
+		d.b[o] += beta * values[o%len(values)]
+	}
+	return nil
+}
+
+// import node, its data input must be the output of the previous node
+func (bd *builder) add(n *node, last bool) error {
+	if len(n.outputs) == 0 {
+		return fmt.Errorf("%w: %s node has no output", ErrInvalidModel, n.op)
+	}
+	data := -1
+	for i, in := range n.inputs {
+		if in == bd.current {
+			data = i
+		}
+	}
+	if data < 0 {
+		return fmt.Errorf("%w: %s node doesn't take %s", ErrNotSequential, n.op, bd.current)
+	}
+	switch n.op {
+	case "Gemm":
+		if len(n.inputs) < 2 || data != 0 || n.int("transA", 0) != 0 {
+			return fmt.Errorf("%w: Gemm of %v", ErrNotSequential, n.inputs)
+		}
+		w, rows, cols, err := bd.matrix(n.inputs[1])
+		if err != nil {
+			return err
+		}
+		if err := bd.dense(w, rows, cols, n.int("transB", 0) == 0, n.float("alpha", 1)); err != nil {
+			return err
+		}
+		if len(n.inputs) > 2 && n.inputs[2] != "" {
+			if err := bd.bias(n.inputs[2], n.float("beta", 1)); err != nil {
+				return err
+			}
+		}
+	case "MatMul":
+		if len(n.inputs) != 2 || data != 0 {
+			return fmt.Errorf("%w: MatMul of %v", ErrNotSequential, n.inputs)
+		}
+		w, rows, cols, err := bd.matrix(n.inputs[1])
+		if err != nil {
+			return err
+		}
+		if err := bd.dense(w, rows, cols, true, 1); err != nil {
+			return err
+		}
+	case "Add":
+		if len(n.inputs) != 2 || len(bd.layers) == 0 {
+			return fmt.Errorf("%w: Add of %v", ErrNotSequential, n.inputs)
+		}
+		if err := bd.bias(n.inputs[1-data], 1); err != nil {
+			return err
+		}
+	case "Relu":
+		bd.layers = append(bd.layers, layers.NewReLU)
+	case "Tanh":
+		bd.layers = append(bd.layers, layers.NewTanh)
+	case "Sigmoid":
+		bd.layers = append(bd.layers, layers.NewSigmoid)
+	case "Flatten", "Identity", "Dropout":
+	case "Softmax", "LogSoftmax":
+		if !last {
+			return fmt.Errorf("%w: %s before the last node", ErrOpNotSupported, n.op)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrOpNotSupported, n.op)
+	}
+	bd.current = n.outputs[0]
+	return nil
+}
+
+// network of imported layers
+func (bd *builder) network() (*layers.Sequential, error) {
+	if bd.width == 0 {
+		return nil, fmt.Errorf("%w: graph has no dense layer", ErrNotSequential)
+	}
+	ls := make([]layers.Layer, len(bd.layers))
+	for i, l := range bd.layers {
+		switch l := l.(type) {
+		case *dense:
+			ls[i] = layers.NewDense(l.in, l.out)
+		case func() layers.Layer:
+			ls[i] = l()
+		}
+	}
+	net := layers.NewSequential(0, ls...)
+	for i, l := range bd.layers {
+		if d, ok := l.(*dense); ok {
+			copy(ls[i].(*layers.Dense).Weights(), d.w)
+			copy(ls[i].(*layers.Dense).Bias(), d.b)
+		}
+	}
+	return net, nil
+}
+
+// Decode network of ONNX model
+func Decode(b []byte) (*layers.Sequential, error) {
+	g, err := parseModel(b)
+	if err != nil {
+		return nil, err
+	}
+	bd := &builder{g: g}
+	for _, in := range g.inputs {
+		if _, ok := g.init[in]; !ok {
+			if bd.current != "" {
+				return nil, fmt.Errorf("%w: graph has several inputs", ErrNotSequential)
+			}
+			bd.current = in
+		}
+	}
+	if bd.current == "" {
+		return nil, fmt.Errorf("%w: graph has no input", ErrInvalidModel)
+	}
+	for i, n := range g.nodes {
+		if err := bd.add(n, i == len(g.nodes)-1); err != nil {
+			return nil, err
+		}
+	}
+	if len(g.outputs) != 1 || g.outputs[0] != bd.current {
+		return nil, fmt.Errorf("%w: output of graph is not the output of the last node", ErrNotSequential)
+	}
+	return bd.network()
+}
+
+// Read network of ONNX model from r
+func Read(r io.Reader) (*layers.Sequential, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(b)
+}
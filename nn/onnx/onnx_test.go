@@ -0,0 +1,154 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"testing"
+)
+
+// protobuf encoding of fields of test models
+func key(field, wire int) []byte {
+	return binary.AppendUvarint(nil, uint64(field<<3|wire))
+}
+
+func varintField(field int, v int64) []byte {
+	return binary.AppendUvarint(key(field, wireVarint), uint64(v))
+}
+
+func bytesField(field int, b []byte) []byte {
+	return append(binary.AppendUvarint(key(field, wireBytes), uint64(len(b))), b...)
+}
+
+func floatField(field int, v float32) []byte {
+	return binary.LittleEndian.AppendUint32(key(field, wireFixed32), math.Float32bits(v))
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// float tensor initializer with packed float_data
+func floatTensor(name string, dims []int64, values []float32) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = binary.LittleEndian.AppendUint32(packed, math.Float32bits(v))
+	}
+	out := []byte{}
+	for _, d := range dims {
+		out = append(out, varintField(1, d)...)
+	}
+	return concat(out, varintField(2, typeFloat), bytesField(4, packed), bytesField(8, []byte(name)))
+}
+
+// double tensor initializer with raw_data
+func doubleTensor(name string, dims []int64, values []float64) []byte {
+	var raw []byte
+	for _, v := range values {
+		raw = binary.LittleEndian.AppendUint64(raw, math.Float64bits(v))
+	}
+	out := []byte{}
+	for _, d := range dims {
+		out = append(out, varintField(1, d)...)
+	}
+	return concat(out, varintField(2, typeDouble), bytesField(8, []byte(name)), bytesField(9, raw))
+}
+
+func testNode(op string, inputs, outputs []string, attrs ...[]byte) []byte {
+	var out []byte
+	for _, in := range inputs {
+		out = append(out, bytesField(1, []byte(in))...)
+	}
+	for _, o := range outputs {
+		out = append(out, bytesField(2, []byte(o))...)
+	}
+	out = append(out, bytesField(4, []byte(op))...)
+	for _, a := range attrs {
+		out = append(out, bytesField(5, a)...)
+	}
+	return out
+}
+
+func testModel(nodes ...[]byte) []byte {
+	graph := concat(
+		bytesField(5, floatTensor("w1", []int64{3, 2}, []float32{1, 0, 0, 1, 1, -1})),
+		bytesField(5, floatTensor("b1", []int64{3}, []float32{0, 0.5, -3})),
+		bytesField(5, doubleTensor("w2", []int64{3, 2}, []float64{1, 2, 3, 4, 5, 6})),
+		bytesField(5, floatTensor("b2", []int64{1, 2}, []float32{0.25, -0.25})),
+		bytesField(11, bytesField(1, []byte("x"))),
+		bytesField(12, bytesField(1, []byte("p"))),
+	)
+	for _, n := range nodes {
+		graph = append(graph, bytesField(1, n)...)
+	}
+	return concat(varintField(1, 8), bytesField(2, []byte("test")), bytesField(7, graph))
+}
+
+func mlpNodes() [][]byte {
+	return [][]byte{
+		testNode("Gemm", []string{"x", "w1", "b1"}, []string{"h"}, concat(bytesField(1, []byte("transB")), varintField(3, 1)), concat(bytesField(1, []byte("alpha")), floatField(2, 2))),
+		testNode("Relu", []string{"h"}, []string{"r"}),
+		testNode("MatMul", []string{"r", "w2"}, []string{"m"}),
+		testNode("Add", []string{"m", "b2"}, []string{"y"}),
+		testNode("Softmax", []string{"y"}, []string{"p"}),
+	}
+}
+
+func TestDecode(t *testing.T) {
+	net, err := Decode(testModel(mlpNodes()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(net.Layers()) != 3 {
+		t.Fatalf("Decode failed. Expected 3 layers, but got %d", len(net.Layers()))
+	}
+	// h = 2 W1 x + b1 = (2, 4.5, -1), r = (2, 4.5, 0), y = r W2 + b2
+	y := net.Forward([]float64{1, 2})
+	expected := []float64{2*1 + 4.5*3 + 0.25, 2*2 + 4.5*4 - 0.25}
+	for i := range y {
+		if math.Abs(y[i]-expected[i]) > 1e-6 {
+			t.Errorf("Forward failed. Expected %v, but got %v", expected, y)
+			break
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	file, err := os.Open("testdata/mlp.onnx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	net, err := Read(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if y := net.Forward([]float64{1, 2}); math.Abs(y[0]-15.75) > 1e-6 {
+		t.Errorf("Read failed. Expected first output 15.75, but got %v", y)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	nodes := mlpNodes()
+	model := testModel(nodes...)
+	if _, err := Decode(model[:len(model)-3]); !errors.Is(err, ErrInvalidModel) {
+		t.Errorf("Decode failed. Expected %v for truncated model, but got %v", ErrInvalidModel, err)
+	}
+	conv := append([][]byte{testNode("Conv", []string{"x", "w1"}, []string{"c"})}, nodes[1:]...)
+	if _, err := Decode(testModel(conv...)); !errors.Is(err, ErrOpNotSupported) {
+		t.Errorf("Decode failed. Expected %v, but got %v", ErrOpNotSupported, err)
+	}
+	branch := append([][]byte{}, nodes...)
+	branch[1] = testNode("Relu", []string{"x"}, []string{"r"})
+	if _, err := Decode(testModel(branch...)); !errors.Is(err, ErrNotSequential) {
+		t.Errorf("Decode failed. Expected %v, but got %v", ErrNotSequential, err)
+	}
+	if _, err := Decode(testModel(nodes[:2]...)); !errors.Is(err, ErrNotSequential) {
+		t.Errorf("Decode failed. Expected %v for output that is not the last node, but got %v", ErrNotSequential, err)
+	}
+}
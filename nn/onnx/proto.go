@@ -0,0 +1,160 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// reader of fields of a protobuf message
+type reader struct {
+	b []byte
+}
+
+// next field number and wire type, ok is false at the end of message
+func (r *reader) next() (field, wire int, ok bool, err error) {
+	if len(r.b) == 0 {
+		return 0, 0, false, nil
+	}
+	key, err := r.varint()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if key>>3 == 0 || key>>3 > math.MaxInt32 {
+		return 0, 0, false, ErrInvalidModel
+	}
+	return int(key >> 3), int(key & 7), true, nil
+}
+
+func (r *reader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, ErrInvalidModel
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *reader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.b)) {
+		return nil, ErrInvalidModel
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b, nil
+}
+
+func (r *reader) fixed32() (uint32, error) {
+	if len(r.b) < 4 {
+		return 0, ErrInvalidModel
+	}
+	v := binary.LittleEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v, nil
+}
+
+func (r *reader) fixed64() (uint64, error) {
+	if len(r.b) < 8 {
+		return 0, ErrInvalidModel
+	}
+	v := binary.LittleEndian.Uint64(r.b)
+	r.b = r.b[8:]
+	return v, nil
+}
+
+// skip value of a field of wire type
+func (r *reader) skip(wire int) error {
+	var err error
+	switch wire {
+	case wireVarint:
+		_, err = r.varint()
+	case wireFixed64:
+		_, err = r.fixed64()
+	case wireBytes:
+		_, err = r.bytes()
+	case wireFixed32:
+		_, err = r.fixed32()
+	default:
+		err = ErrInvalidModel
+	}
+	return err
+}
+
+// append integers of a repeated varint field, packed or not
+func (r *reader) varints(wire int, out []int64) ([]int64, error) {
+	if wire == wireVarint {
+		v, err := r.varint()
+		return append(out, int64(v)), err
+	}
+	if wire != wireBytes {
+		return out, ErrInvalidModel
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return out, err
+	}
+	packed := &reader{b: b}
+	for len(packed.b) > 0 {
+		v, err := packed.varint()
+		if err != nil {
+			return out, err
+		}
+		out = append(out, int64(v))
+	}
+	return out, nil
+}
+
+// append floats of a repeated float field, packed or not
+func (r *reader) floats(wire int, out []float64) ([]float64, error) {
+	if wire == wireFixed32 {
+		v, err := r.fixed32()
+		return append(out, float64(math.Float32frombits(v))), err
+	}
+	if wire != wireBytes {
+		return out, ErrInvalidModel
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return out, err
+	}
+	if len(b)%4 != 0 {
+		return out, ErrInvalidModel
+	}
+	for i := 0; i < len(b); i += 4 {
+		out = append(out, float64(math.Float32frombits(binary.LittleEndian.Uint32(b[i:]))))
+	}
+	return out, nil
+}
+
+// append doubles of a repeated double field, packed or not
+func (r *reader) doubles(wire int, out []float64) ([]float64, error) {
+	if wire == wireFixed64 {
+		v, err := r.fixed64()
+		return append(out, math.Float64frombits(v)), err
+	}
+	if wire != wireBytes {
+		return out, ErrInvalidModel
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return out, err
+	}
+	if len(b)%8 != 0 {
+		return out, ErrInvalidModel
+	}
+	for i := 0; i < len(b); i += 8 {
+		out = append(out, math.Float64frombits(binary.LittleEndian.Uint64(b[i:])))
+	}
+	return out, nil
+}
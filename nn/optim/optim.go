@@ -0,0 +1,212 @@
+// Package optim implements gradient-based optimizers of parameter vectors
+package optim
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math"
+)
+
+var (
+	ErrLengthMismatch = errors.New("length of parameters and gradients is not the same")
+	ErrRateNotValid   = errors.New("learning rate is not greater than zero")
+)
+
+// Optimizer updates parameters with their gradients, it keeps the state of one parameter vector
+type Optimizer interface {
+	Step(params, grads []float64)
+	Reset()
+}
+
+// Optimizer that may fail, like optimizers of distributed training, trainers stop when Err isn't nil
+type Failer interface {
+	Err() error
+}
+
+func check(params, grads []float64, rate float64) {
+	if len(params) != len(grads) {
+		panic(ErrLengthMismatch)
+	}
+	if rate <= 0 {
+		panic(ErrRateNotValid)
+	}
+}
+
+// Stochastic gradient descent with optional momentum
+type SGD struct {
+	Rate     float64
+	Momentum float64
+	Nesterov bool
+	velocity []float64
+}
+
+// Create stochastic gradient descent with learning rate and momentum
+func NewSGD(rate, momentum float64) *SGD {
+	return &SGD{Rate: rate, Momentum: momentum}
+}
+
+// Update parameters with gradients
+func (sgd *SGD) Step(params, grads []float64) {
+	check(params, grads, sgd.Rate)
+	if sgd.Momentum == 0 {
+		for i, g := range grads {
+			params[i] -= sgd.Rate * g
+		}
+		return
+	}
+	if len(sgd.velocity) != len(params) {
+		sgd.velocity = make([]float64, len(params))
+	}
+	for i, g := range grads {
+		sgd.velocity[i] = sgd.Momentum*sgd.velocity[i] + g
+		if sgd.Nesterov {
+			params[i] -= sgd.Rate * (g + sgd.Momentum*sgd.velocity[i])
+		} else {
+			params[i] -= sgd.Rate * sgd.velocity[i]
+		}
+	}
+}
+
+// Clear momentum
+func (sgd *SGD) Reset() {
+	sgd.velocity = nil
+}
+
+type sgdSpec struct {
+	Rate, Momentum float64
+	Nesterov       bool
+	Velocity       []float64
+}
+
+// Encode configuration and momentum, like in checkpoints of training
+func (sgd *SGD) GobEncode() ([]byte, error) {
+	return encode(&sgdSpec{Rate: sgd.Rate, Momentum: sgd.Momentum, Nesterov: sgd.Nesterov, Velocity: sgd.velocity})
+}
+
+// Decode optimizer encoded by GobEncode
+func (sgd *SGD) GobDecode(b []byte) error {
+	var spec sgdSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return err
+	}
+	*sgd = SGD{Rate: spec.Rate, Momentum: spec.Momentum, Nesterov: spec.Nesterov, velocity: spec.Velocity}
+	return nil
+}
+
+// Adam optimizer with bias-corrected moment estimates
+type Adam struct {
+	Rate    float64
+	Beta1   float64
+	Beta2   float64
+	Epsilon float64
+	m       []float64
+	v       []float64
+	t       int
+}
+
+// Create Adam with learning rate and the usual decays 0.9 and 0.999
+func NewAdam(rate float64) *Adam {
+	return &Adam{Rate: rate, Beta1: 0.9, Beta2: 0.999, Epsilon: 1e-8}
+}
+
+// Update parameters with gradients
+func (adam *Adam) Step(params, grads []float64) {
+	check(params, grads, adam.Rate)
+	if len(adam.m) != len(params) {
+		adam.m = make([]float64, len(params))
+		adam.v = make([]float64, len(params))
+		adam.t = 0
+	}
+	adam.t++
+	c1 := 1 - math.Pow(adam.Beta1, float64(adam.t))
+	c2 := 1 - math.Pow(adam.Beta2, float64(adam.t))
+	for i, g := range grads {
+		adam.m[i] = adam.Beta1*adam.m[i] + (1-adam.Beta1)*g
+		adam.v[i] = adam.Beta2*adam.v[i] + (1-adam.Beta2)*g*g
+		params[i] -= adam.Rate * (adam.m[i] / c1) / (math.Sqrt(adam.v[i]/c2) + adam.Epsilon)
+	}
+}
+
+// Clear moment estimates
+func (adam *Adam) Reset() {
+	adam.m, adam.v, adam.t = nil, nil, 0
+}
+
+type adamSpec struct {
+	Rate, Beta1, Beta2, Epsilon float64
+	M, V                        []float64
+	T                           int
+}
+
+// Encode configuration and moment estimates, like in checkpoints of training
+func (adam *Adam) GobEncode() ([]byte, error) {
+	return encode(&adamSpec{Rate: adam.Rate, Beta1: adam.Beta1, Beta2: adam.Beta2, Epsilon: adam.Epsilon,
+		M: adam.m, V: adam.v, T: adam.t})
+}
+
+// Decode optimizer encoded by GobEncode
+func (adam *Adam) GobDecode(b []byte) error {
+	var spec adamSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return err
+	}
+	*adam = Adam{Rate: spec.Rate, Beta1: spec.Beta1, Beta2: spec.Beta2, Epsilon: spec.Epsilon, m: spec.M, v: spec.V, t: spec.T}
+	return nil
+}
+
+// RMSProp optimizer
+type RMSProp struct {
+	Rate    float64
+	Decay   float64
+	Epsilon float64
+	sq      []float64
+}
+
+// Create RMSProp with learning rate and decay 0.9
+func NewRMSProp(rate float64) *RMSProp {
+	return &RMSProp{Rate: rate, Decay: 0.9, Epsilon: 1e-8}
+}
+
+// Update parameters with gradients
+func (rms *RMSProp) Step(params, grads []float64) {
+	check(params, grads, rms.Rate)
+	if len(rms.sq) != len(params) {
+		rms.sq = make([]float64, len(params))
+	}
+	for i, g := range grads {
+		rms.sq[i] = rms.Decay*rms.sq[i] + (1-rms.Decay)*g*g
+		params[i] -= rms.Rate * g / (math.Sqrt(rms.sq[i]) + rms.Epsilon)
+	}
+}
+
+// Clear mean of squared gradients
+func (rms *RMSProp) Reset() {
+	rms.sq = nil
+}
+
+type rmsPropSpec struct {
+	Rate, Decay, Epsilon float64
+	Sq                   []float64
+}
+
+// Encode configuration and mean of squared gradients, like in checkpoints of training
+func (rms *RMSProp) GobEncode() ([]byte, error) {
+	return encode(&rmsPropSpec{Rate: rms.Rate, Decay: rms.Decay, Epsilon: rms.Epsilon, Sq: rms.sq})
+}
+
+// Decode optimizer encoded by GobEncode
+func (rms *RMSProp) GobDecode(b []byte) error {
+	var spec rmsPropSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return err
+	}
+	*rms = RMSProp{Rate: spec.Rate, Decay: spec.Decay, Epsilon: spec.Epsilon, sq: spec.Sq}
+	return nil
+}
+
+func encode(spec any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(spec)
+	return buf.Bytes(), err
+}
@@ -0,0 +1,71 @@
+package optim
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"testing"
+)
+
+// minimize (x-3)^2 + 10(y+1)^2
+func minimize(opt Optimizer, steps int) []float64 {
+	params := []float64{0, 0}
+	grads := make([]float64, 2)
+	for i := 0; i < steps; i++ {
+		grads[0] = 2 * (params[0] - 3)
+		grads[1] = 20 * (params[1] + 1)
+		opt.Step(params, grads)
+	}
+	return params
+}
+
+func TestOptimizers(t *testing.T) {
+	opts := map[string]Optimizer{
+		"SGD":      NewSGD(0.04, 0),
+		"Momentum": NewSGD(0.01, 0.9),
+		"Nesterov": &SGD{Rate: 0.01, Momentum: 0.9, Nesterov: true},
+		"Adam":     NewAdam(0.05),
+		"RMSProp":  NewRMSProp(0.01),
+	}
+	for name, opt := range opts {
+		params := minimize(opt, 2000)
+		if math.Abs(params[0]-3) > 1e-2 || math.Abs(params[1]+1) > 1e-2 {
+			t.Errorf("%s failed. Expected [3 -1], but got %v", name, params)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	adam := NewAdam(0.1)
+	minimize(adam, 10)
+	adam.Reset()
+	if adam.t != 0 || adam.m != nil {
+		t.Errorf("Reset failed. State was not cleared")
+	}
+}
+
+func TestOptimizerGob(t *testing.T) {
+	opts := map[string][2]Optimizer{
+		"SGD":     {&SGD{Rate: 0.01, Momentum: 0.9, Nesterov: true}, &SGD{}},
+		"Adam":    {NewAdam(0.05), &Adam{}},
+		"RMSProp": {NewRMSProp(0.01), &RMSProp{}},
+	}
+	for name, pair := range opts {
+		opt, decoded := pair[0], pair[1]
+		minimize(opt, 10)
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(opt); err != nil {
+			t.Fatal(err)
+		}
+		if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+			t.Fatal(err)
+		}
+		// decoded optimizer continues with the same state
+		a, b := []float64{1, 2}, []float64{1, 2}
+		opt.Step(a, []float64{0.5, -0.5})
+		decoded.Step(b, []float64{0.5, -0.5})
+		if a[0] != b[0] || a[1] != b[1] {
+			t.Errorf("%s failed. Expected %v after decoding, but got %v", name, a, b)
+		}
+	}
+}
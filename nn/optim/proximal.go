@@ -0,0 +1,105 @@
+package optim
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrNoStepSize = errors.New("optimizer doesn't report the step size of parameters")
+
+// Optimizer that reports the step size of every parameter in its last step, the learning rate scaled by adaptive
+// optimizers. Proximal steps use it to shrink parameters by the same scale
+type StepSizer interface {
+	Optimizer
+	StepSize(i int) float64
+}
+
+// Step size of every parameter, the learning rate
+func (sgd *SGD) StepSize(i int) float64 {
+	return sgd.Rate
+}
+
+// Step size of parameter i, the learning rate divided by the root of its second moment estimate
+func (adam *Adam) StepSize(i int) float64 {
+	if i >= len(adam.v) || adam.t == 0 {
+		return adam.Rate
+	}
+	c2 := 1 - math.Pow(adam.Beta2, float64(adam.t))
+	return adam.Rate / (math.Sqrt(adam.v[i]/c2) + adam.Epsilon)
+}
+
+// Step size of parameter i, the learning rate divided by the root of its mean squared gradient
+func (rms *RMSProp) StepSize(i int) float64 {
+	if i >= len(rms.sq) {
+		return rms.Rate
+	}
+	return rms.Rate / (math.Sqrt(rms.sq[i]) + rms.Epsilon)
+}
+
+// Proximal gradient optimizer of objectives with an L1 penalty, the optimizer steps with the gradients of the smooth
+// part and then soft-thresholds penalized parameters, so they reach exact zeros instead of oscillating around them
+type ProximalL1 struct {
+	opt       StepSizer
+	lambda    float64
+	penalized []bool
+}
+
+// Create proximal optimizer of opt with penalty lambda of parameters that are true in penalized, every parameter
+// is penalized if it is nil
+//
+// panics with ErrNoStepSize if opt isn't a StepSizer
+func NewProximalL1(opt Optimizer, lambda float64, penalized []bool) *ProximalL1 {
+	sizer, ok := opt.(StepSizer)
+	if !ok {
+		panic(ErrNoStepSize)
+	}
+	return &ProximalL1{opt: sizer, lambda: lambda, penalized: penalized}
+}
+
+// Update parameters with gradients of the smooth part of objective and apply the proximal operator of penalty
+func (pr *ProximalL1) Step(params, grads []float64) {
+	pr.opt.Step(params, grads)
+	for i, p := range params {
+		if pr.penalized != nil && !pr.penalized[i] {
+			continue
+		}
+		params[i] = SoftThreshold(p, pr.lambda*pr.opt.StepSize(i))
+	}
+}
+
+// Reset state of optimizer
+func (pr *ProximalL1) Reset() {
+	pr.opt.Reset()
+}
+
+// Proximal operator of the L1 penalty, it shrinks value toward zero by threshold
+func SoftThreshold(value, threshold float64) float64 {
+	switch {
+	case value > threshold:
+		return value - threshold
+	case value < -threshold:
+		return value + threshold
+	}
+	return 0
+}
+
+// Greatest violation of the optimality conditions of an L1 penalized objective with gradients of its smooth part,
+// it is zero at the minimum, unlike the subgradient of the penalty at zero parameters
+func ProximalResidual(params, grads []float64, lambda float64, penalized []bool) float64 {
+	greatest := 0.0
+	for i, g := range grads {
+		r := math.Abs(g)
+		if penalized == nil || penalized[i] {
+			switch {
+			case params[i] > 0:
+				r = math.Abs(g + lambda)
+			case params[i] < 0:
+				r = math.Abs(g - lambda)
+			default:
+				r = math.Max(r-lambda, 0)
+			}
+		}
+		greatest = math.Max(greatest, r)
+	}
+	return greatest
+}
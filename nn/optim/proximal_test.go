@@ -0,0 +1,28 @@
+package optim
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProximalL1(t *testing.T) {
+	// minimize (x-3)^2 + (y-0.1)^2 + |x| + |y|, the minimum is x = 2.5 and y = 0
+	for name, opt := range map[string]Optimizer{"SGD": NewSGD(0.1, 0), "Adam": NewAdam(0.05), "RMSProp": NewRMSProp(0.01)} {
+		prox := NewProximalL1(opt, 1, nil)
+		params, grads := []float64{0, 0}, make([]float64, 2)
+		for i := 0; i < 3000; i++ {
+			grads[0], grads[1] = 2*(params[0]-3), 2*(params[1]-0.1)
+			prox.Step(params, grads)
+		}
+		if math.Abs(params[0]-2.5) > 1e-2 || params[1] != 0 {
+			t.Errorf("ProximalL1 %s failed. Expected [2.5 0], but got %v", name, params)
+		}
+		grads[0], grads[1] = 2*(params[0]-3), 2*(params[1]-0.1)
+		if r := ProximalResidual(params, grads, 1, nil); r > 1e-2 {
+			t.Errorf("ProximalResidual %s failed. Expected about 0 at minimum, but got %v", name, r)
+		}
+	}
+	if v := SoftThreshold(-3, 1); v != -2 {
+		t.Errorf("SoftThreshold failed. Expected -2, but got %v", v)
+	}
+}
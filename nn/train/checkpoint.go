@@ -0,0 +1,112 @@
+package train
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrCheckpointMismatch = errors.New("parameters of checkpoint don't match parameters of network")
+	ErrOptimizerNotSaved  = errors.New("optimizer doesn't implement gob encoding of its state")
+)
+
+// Checkpoint of a training job, it has everything needed to continue training after a restart
+type Checkpoint struct {
+	Epoch     int       //epochs finished
+	Params    []float64 //parameters of network
+	Optimizer []byte    //state of optimizer encoded by its GobEncode
+	Loader    int       //epochs started by data loader, it restores the state of shuffling
+	History   []float64 //mean loss of every finished epoch
+}
+
+// Save checkpoint to path, it writes a temporary file first so an interrupted save keeps the previous checkpoint
+func (cp *Checkpoint) Save(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(tmp).Encode(cp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load checkpoint saved by Save
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	cp := &Checkpoint{}
+	if err := gob.NewDecoder(file).Decode(cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Save a checkpoint to path every epochs of Fit and after its last epoch, Fit fails before training if the
+// optimizer doesn't implement gob.GobEncoder
+func (t *Trainer) Checkpoint(path string, every int) {
+	if every <= 0 {
+		every = 1
+	}
+	t.checkpointPath, t.checkpointEvery = path, every
+}
+
+// Restore network and optimizer from checkpoint at path, the next Fit restores shuffling of its loader and
+// continues from the epoch of checkpoint
+func (t *Trainer) Resume(path string) error {
+	cp, err := LoadCheckpoint(path)
+	if err != nil {
+		return err
+	}
+	if len(cp.Params) != len(t.net.Params()) {
+		return ErrCheckpointMismatch
+	}
+	if cp.Optimizer != nil {
+		dec, ok := t.opt.(gob.GobDecoder)
+		if !ok {
+			return fmt.Errorf("%w: %T", ErrOptimizerNotSaved, t.opt)
+		}
+		if err := dec.GobDecode(cp.Optimizer); err != nil {
+			return err
+		}
+	}
+	copy(t.net.Params(), cp.Params)
+	t.resume = cp
+	return nil
+}
+
+// state of optimizer saved by checkpoints, Fit checks it before the first epoch so it fails before training
+func (t *Trainer) optimizerState() ([]byte, error) {
+	enc, ok := t.opt.(gob.GobEncoder)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrOptimizerNotSaved, t.opt)
+	}
+	return enc.GobEncode()
+}
+
+// checkpoint of state of trainer after epochs of history
+func (t *Trainer) snapshot(loader int, history []float64) (*Checkpoint, error) {
+	state, err := t.optimizerState()
+	if err != nil {
+		return nil, err
+	}
+	return &Checkpoint{
+		Epoch:     len(history),
+		Params:    append([]float64(nil), t.net.Params()...),
+		Optimizer: state,
+		Loader:    loader,
+		History:   append([]float64(nil), history...),
+	}, nil
+}
@@ -0,0 +1,87 @@
+package train
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+func TestResume(t *testing.T) {
+	loader := func() *data.DataLoader {
+		return data.NewDataLoader(blobs(32, 4), data.LoaderConfig{BatchSize: 8, Shuffle: true, Seed: 3})
+	}
+	full := NewTrainer(layers.NewSequential(1, layers.NewDense(2, 2)), optim.NewAdam(0.05), CrossEntropy)
+	expected, err := full.Fit(loader(), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "train.ckpt")
+	first := NewTrainer(layers.NewSequential(1, layers.NewDense(2, 2)), optim.NewAdam(0.05), CrossEntropy)
+	first.Checkpoint(path, 2)
+	if _, err := first.Fit(loader(), 3); err != nil {
+		t.Fatal(err)
+	}
+	cp, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Epoch != 3 || cp.Loader != 3 {
+		t.Errorf("Checkpoint failed. Expected checkpoint after the last epoch, but got epoch %v", cp.Epoch)
+	}
+	// restarted job with other initial weights and a new optimizer
+	resumed := NewTrainer(layers.NewSequential(7, layers.NewDense(2, 2)), optim.NewAdam(0.05), CrossEntropy)
+	if err := resumed.Resume(path); err != nil {
+		t.Fatal(err)
+	}
+	history, err := resumed.Fit(loader(), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(expected) {
+		t.Fatalf("Resume failed. Expected %v epochs, but got %v", len(expected), len(history))
+	}
+	for i := range expected {
+		if history[i] != expected[i] {
+			t.Errorf("Resume failed. Expected losses %v, but got %v", expected, history)
+			break
+		}
+	}
+	for i, p := range full.Net().Params() {
+		if resumed.Net().Params()[i] != p {
+			t.Fatalf("Resume failed. Expected parameters %v, but got %v", full.Net().Params(), resumed.Net().Params())
+		}
+	}
+	other := NewTrainer(layers.NewSequential(1, layers.NewDense(2, 3)), optim.NewAdam(0.05), CrossEntropy)
+	if err := other.Resume(path); err != ErrCheckpointMismatch {
+		t.Errorf("Resume failed. Expected %v, but got %v", ErrCheckpointMismatch, err)
+	}
+}
+
+// optimizer without gob encoding of its state
+type plainSGD struct{}
+
+func (plainSGD) Step(params, grads []float64) {
+	for i := range params {
+		params[i] -= 0.1 * grads[i]
+	}
+}
+
+func (plainSGD) Reset() {}
+
+func TestCheckpointOptimizerNotSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "train.ckpt")
+	trainer := NewTrainer(layers.NewSequential(1, layers.NewDense(2, 2)), plainSGD{}, CrossEntropy)
+	trainer.Checkpoint(path, 1)
+	history, err := trainer.Fit(data.NewDataLoader(blobs(16, 1), data.LoaderConfig{BatchSize: 8}), 2)
+	if !errors.Is(err, ErrOptimizerNotSaved) || len(history) != 0 {
+		t.Errorf("Fit failed. Expected %v before training, but got %v after %d epochs", ErrOptimizerNotSaved, err, len(history))
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Fit failed. Expected no checkpoint, but got %v", err)
+	}
+}
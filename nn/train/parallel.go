@@ -0,0 +1,66 @@
+package train
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/stellviaproject/go-ia/nn/layers"
+)
+
+// Compute gradients of every batch with replicas of network in workers goroutines, every replica computes the
+// gradient of a shard of the batch and the optimizer applies their mean. Workers lesser than 1 are GOMAXPROCS,
+// one worker trains without replicas
+func (t *Trainer) Parallel(workers int) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	t.replicas = nil
+	if workers == 1 {
+		return
+	}
+	t.replicas = make([]*layers.Sequential, workers)
+	for i := range t.replicas {
+		t.replicas[i] = t.net.Clone()
+	}
+}
+
+// sum of losses of items, gradients of network are the sum of gradients of replicas scaled by scale
+func (t *Trainer) parallelStep(inputs, targets [][]float64, scale float64) float64 {
+	shard := (len(inputs) + len(t.replicas) - 1) / len(t.replicas)
+	losses := make([]float64, len(t.replicas))
+	used := 0
+	var wg sync.WaitGroup
+	for start := 0; start < len(inputs); start += shard {
+		end := start + shard
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		replica, k := t.replicas[used], used
+		used++
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			replica.CopyFrom(t.net)
+			replica.ZeroGrad()
+			for i := start; i < end; i++ {
+				l, grad := t.loss(replica.Forward(inputs[i]), targets[i])
+				losses[k] += l
+				for g := range grad {
+					grad[g] *= scale
+				}
+				replica.Backward(grad)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	// sum in order of shards, so training is deterministic
+	sum := 0.0
+	grads := t.net.Grads()
+	for k, replica := range t.replicas[:used] {
+		sum += losses[k]
+		for i, g := range replica.Grads() {
+			grads[i] += g
+		}
+	}
+	return sum
+}
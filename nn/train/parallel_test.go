@@ -0,0 +1,57 @@
+package train
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+func TestParallel(t *testing.T) {
+	newTrainer := func() *Trainer {
+		net := layers.NewSequential(1, layers.NewDense(2, 8), layers.NewTanh(), layers.NewDense(8, 2))
+		return NewTrainer(net, optim.NewSGD(0.1, 0.9), CrossEntropy)
+	}
+	loader := func() *data.DataLoader {
+		return data.NewDataLoader(blobs(40, 4), data.LoaderConfig{BatchSize: 8, Shuffle: true, Seed: 5})
+	}
+	serial := newTrainer()
+	expected, err := serial.Fit(loader(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{3, 16} {
+		parallel := newTrainer()
+		parallel.Parallel(workers)
+		history, err := parallel.Fit(loader(), 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range expected {
+			if math.Abs(history[i]-expected[i]) > 1e-9 {
+				t.Errorf("Parallel failed. Expected losses %v with %d workers, but got %v", expected, workers, history)
+				break
+			}
+		}
+		for i, p := range serial.Net().Params() {
+			if math.Abs(parallel.Net().Params()[i]-p) > 1e-9 {
+				t.Fatalf("Parallel failed. Expected parameters of serial training with %d workers", workers)
+			}
+		}
+	}
+}
+
+func BenchmarkParallel(b *testing.B) {
+	net := layers.NewSequential(1, layers.NewDense(2, 256), layers.NewReLU(), layers.NewDense(256, 2))
+	trainer := NewTrainer(net, optim.NewAdam(0.01), CrossEntropy)
+	trainer.Parallel(0)
+	loader := data.NewDataLoader(blobs(1024, 4), data.LoaderConfig{BatchSize: 256})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := trainer.Train(loader.Iter()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
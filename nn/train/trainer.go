@@ -0,0 +1,205 @@
+// Package train implements training loops of networks over data loaders
+package train
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/progress"
+)
+
+var (
+	ErrNoTargets = errors.New("batch has no targets")
+	ErrNoSamples = errors.New("loader has no samples")
+)
+
+// Loss of prediction for target and its gradient by prediction
+type Loss func(pred, target []float64) (float64, []float64)
+
+// Cross entropy of logits with the class stored as the only element of target
+func CrossEntropy(logits, target []float64) (float64, []float64) {
+	return layers.CrossEntropyLoss(logits, int(target[0]))
+}
+
+// Cross entropy weighted by the weight of the class, like balanced weights of imbalanced classes
+func WeightedCrossEntropy(weights []float64) Loss {
+	return func(logits, target []float64) (float64, []float64) {
+		w := weights[int(target[0])]
+		l, grad := layers.CrossEntropyLoss(logits, int(target[0]))
+		for i := range grad {
+			grad[i] *= w
+		}
+		return w * l, grad
+	}
+}
+
+// Callback called by Fit after every epoch with its mean loss, returning false stops training
+type Callback func(epoch int, loss float64) bool
+
+// Trainer fits a network to batches of a data loader with an optimizer
+type Trainer struct {
+	net       *layers.Sequential
+	opt       optim.Optimizer
+	loss      Loss
+	callbacks []Callback
+	progress  progress.Func
+	// checkpoints
+	checkpointPath  string
+	checkpointEvery int
+	resume          *Checkpoint
+	// data parallelism
+	replicas []*layers.Sequential
+}
+
+// Create trainer of network minimizing loss with optimizer
+func NewTrainer(net *layers.Sequential, opt optim.Optimizer, loss Loss) *Trainer {
+	return &Trainer{net: net, opt: opt, loss: loss}
+}
+
+// Network of trainer
+func (t *Trainer) Net() *layers.Sequential {
+	return t.net
+}
+
+// Step of optimizer with gradient of mean loss of batch, it returns the mean loss
+func (t *Trainer) Step(batch data.Batch) (float64, error) {
+	if batch.Targets == nil {
+		return 0, ErrNoTargets
+	}
+	inputs, targets := data.Rows(batch.Inputs), data.Rows(batch.Targets)
+	t.net.ZeroGrad()
+	scale := 1 / float64(len(inputs))
+	sum := 0.0
+	if t.replicas != nil {
+		sum = t.parallelStep(inputs, targets, scale)
+	} else {
+		for i, x := range inputs {
+			l, grad := t.loss(t.net.Forward(x), targets[i])
+			sum += l
+			for k := range grad {
+				grad[k] *= scale
+			}
+			t.net.Backward(grad)
+		}
+	}
+	t.opt.Step(t.net.Params(), t.net.Grads())
+	if f, ok := t.opt.(optim.Failer); ok && f.Err() != nil {
+		return 0, f.Err()
+	}
+	return sum * scale, nil
+}
+
+// Train network with one pass over batches and return mean loss of items
+func (t *Trainer) Train(batches data.Batches) (float64, error) {
+	return t.train(context.Background(), batches, nil)
+}
+
+func (t *Trainer) train(ctx context.Context, batches data.Batches, tracker *progress.Tracker) (float64, error) {
+	defer batches.Close()
+	sum, count := 0.0, 0
+	for batches.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		l, err := t.Step(batches.Batch())
+		if err != nil {
+			return 0, err
+		}
+		sum += l * float64(batches.Batch().Len())
+		count += batches.Batch().Len()
+		tracker.Step(1)
+	}
+	if err := batches.Err(); err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return sum / float64(count), nil
+}
+
+// Add callbacks called after every epoch of Fit, like loggers and metrics
+func (t *Trainer) OnEpoch(callbacks ...Callback) {
+	t.callbacks = append(t.callbacks, callbacks...)
+}
+
+// Set function receiving progress of Fit after every batch
+func (t *Trainer) OnProgress(fn progress.Func) {
+	t.progress = fn
+}
+
+// Train network for epochs and return mean loss of every epoch, it stops early if a callback returns false.
+// After Resume, epochs counts the epochs of checkpoint and the history starts with their losses
+func (t *Trainer) Fit(loader *data.DataLoader, epochs int) ([]float64, error) {
+	return t.FitContext(context.Background(), loader, epochs)
+}
+
+// Fit until ctx is done, then it returns the losses of finished epochs and the error of ctx
+func (t *Trainer) FitContext(ctx context.Context, loader *data.DataLoader, epochs int) ([]float64, error) {
+	history := make([]float64, 0, epochs)
+	if t.checkpointPath != "" {
+		if _, err := t.optimizerState(); err != nil {
+			return history, err
+		}
+	}
+	if t.resume != nil {
+		history = append(history, t.resume.History...)
+		loader.Seek(t.resume.Loader)
+		t.resume = nil
+	}
+	tracker := progress.NewTracker((epochs-len(history))*loader.Len(), t.progress)
+	for epoch := len(history); epoch < epochs; epoch++ {
+		l, err := t.train(ctx, loader.Iter(), tracker)
+		if err != nil {
+			return history, err
+		}
+		history = append(history, l)
+		proceed := true
+		for _, cb := range t.callbacks {
+			proceed = cb(epoch, l) && proceed
+		}
+		if t.checkpointPath != "" && (!proceed || epoch == epochs-1 || (epoch+1)%t.checkpointEvery == 0) {
+			cp, err := t.snapshot(loader.Epochs(), history)
+			if err == nil {
+				err = cp.Save(t.checkpointPath)
+			}
+			if err != nil {
+				return history, err
+			}
+		}
+		if !proceed {
+			break
+		}
+	}
+	tracker.Finish()
+	return history, nil
+}
+
+// Mean loss of network over batches of loader, it returns ErrNoSamples if loader is empty
+func (t *Trainer) Evaluate(loader *data.DataLoader) (float64, error) {
+	sum, count := 0.0, 0
+	it := loader.Iter()
+	for it.Next() {
+		b := it.Batch()
+		if b.Targets == nil {
+			it.Close()
+			return 0, ErrNoTargets
+		}
+		targets := data.Rows(b.Targets)
+		for i, x := range data.Rows(b.Inputs) {
+			l, _ := t.loss(t.net.Forward(x), targets[i])
+			sum += l
+			count++
+		}
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, ErrNoSamples
+	}
+	return sum / float64(count), nil
+}
@@ -0,0 +1,125 @@
+package train
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/progress"
+)
+
+// two gaussian blobs with class targets
+func blobs(n int, seed int64) data.Dataset {
+	rnd := rand.New(rand.NewSource(seed))
+	inputs := make([][]float64, n)
+	targets := make([][]float64, n)
+	for i := range inputs {
+		c := float64(i % 2)
+		inputs[i] = []float64{2*c - 1 + 0.5*rnd.NormFloat64(), 1 - 2*c + 0.5*rnd.NormFloat64()}
+		targets[i] = []float64{c}
+	}
+	return data.NewSliceDataset(inputs, targets)
+}
+
+func TestTrainer(t *testing.T) {
+	net := layers.NewSequential(1, layers.NewDense(2, 8), layers.NewReLU(), layers.NewDense(8, 2))
+	trainer := NewTrainer(net, optim.NewAdam(0.05), CrossEntropy)
+	loader := data.NewDataLoader(blobs(200, 1), data.LoaderConfig{BatchSize: 16, Shuffle: true, Workers: 2, Seed: 1})
+	history, err := trainer.Fit(loader, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 20 || history[19] >= history[0] {
+		t.Errorf("Fit failed. Expected decreasing loss, but got %v", history)
+	}
+	test, err := trainer.Evaluate(data.NewDataLoader(blobs(100, 2), data.LoaderConfig{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test > 0.2 {
+		t.Errorf("Evaluate failed. Expected test loss lesser than 0.2, but got %v", test)
+	}
+	empty := data.NewDataLoader(data.NewSliceDataset([][]float64{}, [][]float64{}), data.LoaderConfig{})
+	if l, err := trainer.Evaluate(empty); err != ErrNoSamples || l != 0 {
+		t.Errorf("Evaluate failed. Expected %v, but got %v (%v)", ErrNoSamples, err, l)
+	}
+	unlabeled := data.NewDataLoader(data.NewSliceDataset([][]float64{{0, 0}}, nil), data.LoaderConfig{})
+	if _, err := trainer.Fit(unlabeled, 1); err != ErrNoTargets {
+		t.Errorf("Fit failed. Expected %v, but got %v", ErrNoTargets, err)
+	}
+}
+
+func TestTrainStream(t *testing.T) {
+	ds := blobs(64, 3)
+	ch := make(chan data.Item)
+	go func() {
+		for i := 0; i < ds.Len(); i++ {
+			item, _ := ds.GetItem(i)
+			ch <- item
+		}
+		close(ch)
+	}()
+	net := layers.NewSequential(1, layers.NewDense(2, 2))
+	trainer := NewTrainer(net, optim.NewSGD(0.1, 0), CrossEntropy)
+	l, err := trainer.Train(data.NewStreamIterator(context.Background(), data.NewChannelDataset(ch), data.StreamConfig{BatchSize: 8}))
+	if err != nil || l <= 0 {
+		t.Errorf("Train failed. Expected positive loss, but got %v (%v)", l, err)
+	}
+}
+
+func TestWeightedCrossEntropy(t *testing.T) {
+	logits, target := []float64{0.5, -0.5}, []float64{1}
+	l, grad := CrossEntropy(logits, target)
+	wl, wgrad := WeightedCrossEntropy([]float64{1, 3})(logits, target)
+	if wl != 3*l || wgrad[0] != 3*grad[0] {
+		t.Errorf("WeightedCrossEntropy failed. Expected loss %v, but got %v", 3*l, wl)
+	}
+}
+
+func TestOnEpoch(t *testing.T) {
+	net := layers.NewSequential(1, layers.NewDense(2, 2))
+	trainer := NewTrainer(net, optim.NewAdam(0.05), CrossEntropy)
+	var epochs []int
+	trainer.OnEpoch(func(epoch int, loss float64) bool {
+		epochs = append(epochs, epoch)
+		return epoch < 2
+	})
+	history, err := trainer.Fit(data.NewDataLoader(blobs(32, 4), data.LoaderConfig{BatchSize: 8}), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || len(epochs) != 3 {
+		t.Errorf("OnEpoch failed. Expected training stopped after 3 epochs, but got %v", epochs)
+	}
+}
+
+func TestFitContext(t *testing.T) {
+	net := layers.NewSequential(1, layers.NewDense(2, 2))
+	trainer := NewTrainer(net, optim.NewAdam(0.05), CrossEntropy)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trainer.OnEpoch(func(epoch int, loss float64) bool {
+		if epoch == 1 {
+			cancel()
+		}
+		return true
+	})
+	var reports []progress.Report
+	trainer.OnProgress(func(r progress.Report) {
+		reports = append(reports, r)
+	})
+	history, err := trainer.FitContext(ctx, data.NewDataLoader(blobs(32, 4), data.LoaderConfig{BatchSize: 8}), 10)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("FitContext failed. Expected %v, but got %v", context.Canceled, err)
+	}
+	if len(history) != 2 {
+		t.Errorf("FitContext failed. Expected 2 epochs, but got %v", len(history))
+	}
+	if len(reports) != 8 || reports[7].Done != 8 || reports[7].Total != 40 || reports[7].Percent != 20 {
+		t.Errorf("OnProgress failed. Expected 8 reports of 40 batches, but got %v", reports)
+	}
+}
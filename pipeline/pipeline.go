@@ -0,0 +1,144 @@
+// Package pipeline chains preprocessing transformers with a final estimator
+package pipeline
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/stellviaproject/go-ia/bayes"
+	"github.com/stellviaproject/go-ia/decomposition"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linear"
+	"github.com/stellviaproject/go-ia/tree"
+)
+
+var (
+	ErrNoEstimator = errors.New("pipeline has no estimator")
+	ErrNotFitted   = errors.New("pipeline is not fitted")
+)
+
+// Transformer of points, like scalers and PCA
+type Transformer interface {
+	Fit(points []knn.Point)
+	Transform(point knn.Point) knn.Point
+}
+
+// Estimator predicting labels of points, like classifiers
+type Estimator interface {
+	Fit(data []knn.DataPoint)
+	Predict(point knn.Point) any
+}
+
+// Pipeline applies transformers in order before the estimator
+type Pipeline struct {
+	steps     []Transformer
+	estimator Estimator
+	fitted    bool
+}
+
+// Create pipeline of transformers and a final estimator, estimator may be nil for pipelines that only transform
+func NewPipeline(estimator Estimator, steps ...Transformer) *Pipeline {
+	return &Pipeline{steps: steps, estimator: estimator}
+}
+
+// Transformers of pipeline
+func (p *Pipeline) Steps() []Transformer {
+	return p.steps
+}
+
+// Estimator of pipeline
+func (p *Pipeline) Estimator() Estimator {
+	return p.estimator
+}
+
+// Fit every transformer to the output of the previous ones and the estimator to the output of the last one
+func (p *Pipeline) Fit(data []knn.DataPoint) {
+	points := make([]knn.Point, len(data))
+	for i, dp := range data {
+		points[i] = dp.Point()
+	}
+	for _, step := range p.steps {
+		step.Fit(points)
+		for i, pt := range points {
+			points[i] = step.Transform(pt)
+		}
+	}
+	if p.estimator != nil {
+		transformed := make([]knn.DataPoint, len(data))
+		for i, dp := range data {
+			transformed[i] = knn.NewDataPoint(dp.Label(), points[i])
+		}
+		p.estimator.Fit(transformed)
+	}
+	p.fitted = true
+}
+
+// Point transformed by every transformer
+func (p *Pipeline) Transform(point knn.Point) knn.Point {
+	if !p.fitted {
+		panic(ErrNotFitted)
+	}
+	for _, step := range p.steps {
+		point = step.Transform(point)
+	}
+	return point
+}
+
+// Predict label of point
+func (p *Pipeline) Predict(point knn.Point) any {
+	if p.estimator == nil {
+		panic(ErrNoEstimator)
+	}
+	return p.estimator.Predict(p.Transform(point))
+}
+
+// Predict labels of points
+func (p *Pipeline) PredictAll(points []knn.Point) []any {
+	out := make([]any, len(points))
+	for i, pt := range points {
+		out[i] = p.Predict(pt)
+	}
+	return out
+}
+
+func init() {
+	Register(&knn.StandardScaler{})
+	Register(&knn.MinMaxScaler{})
+	Register(&knn.RobustScaler{})
+	Register(&decomposition.PCA{})
+	Register(&linear.LogisticRegression{})
+	Register(&bayes.GaussianNB{})
+	Register(&tree.Tree{})
+}
+
+// Register type of transformer or estimator so pipelines with it can be saved, it must be encodable by gob
+func Register(value any) {
+	gob.Register(value)
+}
+
+type pipelineSpec struct {
+	Steps     []Transformer
+	Estimator Estimator
+}
+
+// Save transformers and estimator, their types must be registered with Register
+func (p *Pipeline) Save(w io.Writer) error {
+	if !p.fitted {
+		return ErrNotFitted
+	}
+	if err := gob.NewEncoder(w).Encode(&pipelineSpec{Steps: p.steps, Estimator: p.estimator}); err != nil {
+		return fmt.Errorf("pipeline can't be saved: %w", err)
+	}
+	return nil
+}
+
+// Load a pipeline saved with Pipeline.Save
+func Load(r io.Reader) (*Pipeline, error) {
+	var spec pipelineSpec
+	if err := gob.NewDecoder(r).Decode(&spec); err != nil {
+		return nil, err
+	}
+	return &Pipeline{steps: spec.Steps, estimator: spec.Estimator, fitted: true}, nil
+}
@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/bayes"
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/decomposition"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linear"
+)
+
+func accuracy(p *Pipeline, data []knn.DataPoint) float64 {
+	right := 0
+	for _, dp := range data {
+		if p.Predict(dp.Point()) == dp.Label() {
+			right++
+		}
+	}
+	return float64(right) / float64(len(data))
+}
+
+func TestPipeline(t *testing.T) {
+	centers := []knn.Point{{0, 0, 100}, {3, 3, 100}, {0, 3, 200}}
+	data := dataset.MakeBlobs(300, centers, 1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.7, true, 1)
+	for _, estimator := range []Estimator{linear.NewLogisticRegression(linear.LogisticConfig{}), bayes.NewGaussianNB(1e-9)} {
+		p := NewPipeline(estimator, knn.NewStandardScaler(), decomposition.NewPCA(2, false))
+		p.Fit(train)
+		if len(p.Transform(test[0].Point())) != 2 {
+			t.Errorf("Transform failed. Expected 2 components")
+		}
+		acc := accuracy(p, test)
+		if acc < 0.9 {
+			t.Errorf("Predict failed. Expected accuracy greater than 0.9, but got %v", acc)
+		}
+		var buf bytes.Buffer
+		if err := p.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, dp := range test {
+			if loaded.Predict(dp.Point()) != p.Predict(dp.Point()) {
+				t.Fatalf("Load failed. Loaded pipeline predicts different labels")
+			}
+		}
+	}
+}
+
+type unregistered struct{ knn.StandardScaler }
+
+func TestSaveUnregistered(t *testing.T) {
+	p := NewPipeline(nil, &unregistered{})
+	p.Fit([]knn.DataPoint{knn.NewDataPoint(0, knn.Point{1}), knn.NewDataPoint(0, knn.Point{2})})
+	if err := p.Save(&bytes.Buffer{}); err == nil {
+		t.Errorf("Save failed. Expected error of unregistered type")
+	}
+	if !func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		p.Predict(knn.Point{1})
+		return
+	}() {
+		t.Errorf("Predict failed. Expected panic without estimator")
+	}
+}
@@ -0,0 +1,313 @@
+// Package preprocessing implements encoders of categorical features and expansions of numeric features into
+// Float64 tensors of shape (samples, features)
+package preprocessing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrEmptyData         = errors.New("there are no rows to fit")
+	ErrColumnsMismatch   = errors.New("number of columns doesn't match fitted columns")
+	ErrTargetsMismatch   = errors.New("number of targets doesn't match number of rows")
+	ErrNotFitted         = errors.New("encoder is not fitted")
+	ErrUnknownCategory   = errors.New("category was not seen in fit")
+	ErrSmoothingNotValid = errors.New("smoothing is lesser than zero")
+)
+
+// Handling of categories not seen in fit
+type Unknown int
+
+const (
+	UnknownError  Unknown = iota //transform fails with ErrUnknownCategory
+	UnknownIgnore                //ordinal encodes -1, one-hot encodes zeros and target encodes the prior
+)
+
+// categories of every column in order of first appearance
+type categories struct {
+	unknown Unknown
+	values  [][]string
+	index   []map[string]int
+}
+
+func (c *categories) fit(rows [][]string) error {
+	if len(rows) == 0 {
+		return ErrEmptyData
+	}
+	cols := len(rows[0])
+	c.values = make([][]string, cols)
+	c.index = make([]map[string]int, cols)
+	for j := range c.index {
+		c.index[j] = make(map[string]int)
+	}
+	for _, row := range rows {
+		if len(row) != cols {
+			return ErrColumnsMismatch
+		}
+		for j, v := range row {
+			if _, ok := c.index[j][v]; !ok {
+				c.index[j][v] = len(c.values[j])
+				c.values[j] = append(c.values[j], v)
+			}
+		}
+	}
+	return nil
+}
+
+// index of category of every value of rows, -1 for ignored unknown categories
+func (c *categories) lookup(rows [][]string) ([][]int, error) {
+	if c.index == nil {
+		return nil, ErrNotFitted
+	}
+	if len(rows) == 0 {
+		return nil, ErrEmptyData
+	}
+	ids := make([][]int, len(rows))
+	for i, row := range rows {
+		if len(row) != len(c.index) {
+			return nil, ErrColumnsMismatch
+		}
+		ids[i] = make([]int, len(row))
+		for j, v := range row {
+			id, ok := c.index[j][v]
+			if !ok {
+				if c.unknown == UnknownError {
+					return nil, fmt.Errorf("%w: %q in column %d", ErrUnknownCategory, v, j)
+				}
+				id = -1
+			}
+			ids[i][j] = id
+		}
+	}
+	return ids, nil
+}
+
+// Categories of every column in order of first appearance
+func (c *categories) Categories() [][]string {
+	return c.values
+}
+
+func newMatrix(rows, cols int) *graph.Tensor {
+	return graph.NewTensor(nil, graph.Float64, graph.NewShape(rows, cols))
+}
+
+// Encoder of categories by their index in order of first appearance
+type OrdinalEncoder struct {
+	categories
+}
+
+// Create ordinal encoder
+func NewOrdinalEncoder(unknown Unknown) *OrdinalEncoder {
+	return &OrdinalEncoder{categories{unknown: unknown}}
+}
+
+// Fit categories of every column of rows
+func (oe *OrdinalEncoder) Fit(rows [][]string) error {
+	return oe.fit(rows)
+}
+
+// Encode rows as a tensor with one feature by column
+func (oe *OrdinalEncoder) Transform(rows [][]string) (*graph.Tensor, error) {
+	ids, err := oe.lookup(rows)
+	if err != nil {
+		return nil, err
+	}
+	out := newMatrix(len(rows), len(oe.index))
+	index := make([]int, 2)
+	for i, row := range ids {
+		index[0] = i
+		for j, id := range row {
+			index[1] = j
+			out.SetF64(index, float64(id))
+		}
+	}
+	return out, nil
+}
+
+// Fit and transform rows
+func (oe *OrdinalEncoder) FitTransform(rows [][]string) (*graph.Tensor, error) {
+	if err := oe.Fit(rows); err != nil {
+		return nil, err
+	}
+	return oe.Transform(rows)
+}
+
+// Encoder of categories by one indicator feature for every category of every column
+type OneHotEncoder struct {
+	categories
+}
+
+// Create one-hot encoder
+func NewOneHotEncoder(unknown Unknown) *OneHotEncoder {
+	return &OneHotEncoder{categories{unknown: unknown}}
+}
+
+// Fit categories of every column of rows
+func (oh *OneHotEncoder) Fit(rows [][]string) error {
+	return oh.fit(rows)
+}
+
+// Encode rows as a tensor with one feature by category of every column, in order of columns
+func (oh *OneHotEncoder) Transform(rows [][]string) (*graph.Tensor, error) {
+	ids, err := oh.lookup(rows)
+	if err != nil {
+		return nil, err
+	}
+	offsets := make([]int, len(oh.values))
+	width := 0
+	for j, values := range oh.values {
+		offsets[j] = width
+		width += len(values)
+	}
+	out := newMatrix(len(rows), width)
+	index := make([]int, 2)
+	for i, row := range ids {
+		index[0] = i
+		for j, id := range row {
+			if id >= 0 {
+				index[1] = offsets[j] + id
+				out.SetF64(index, 1)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Fit and transform rows
+func (oh *OneHotEncoder) FitTransform(rows [][]string) (*graph.Tensor, error) {
+	if err := oh.Fit(rows); err != nil {
+		return nil, err
+	}
+	return oh.Transform(rows)
+}
+
+// Names of encoded features as column=category, names are the names of columns
+func (oh *OneHotEncoder) FeatureNames(names []string) []string {
+	if len(names) != len(oh.values) {
+		panic(ErrColumnsMismatch)
+	}
+	out := make([]string, 0, len(names))
+	for j, values := range oh.values {
+		for _, v := range values {
+			out = append(out, names[j]+"="+v)
+		}
+	}
+	return out
+}
+
+// Encoder of categories by the mean target of their rows, smoothed towards the mean target of every row
+//
+// The encoding of a category with n rows and mean m is (n*m + smoothing*prior) / (n + smoothing), so rare
+// categories don't overfit their few targets. Unknown categories always encode the prior.
+type TargetEncoder struct {
+	categories
+	smoothing float64
+	prior     float64
+	means     [][]float64
+}
+
+// Create target encoder, targets of classification are usually 0 or 1 for the positive class
+func NewTargetEncoder(smoothing float64) *TargetEncoder {
+	if smoothing < 0 {
+		panic(ErrSmoothingNotValid)
+	}
+	return &TargetEncoder{categories: categories{unknown: UnknownIgnore}, smoothing: smoothing}
+}
+
+// Fit smoothed mean target of categories of every column of rows
+func (te *TargetEncoder) Fit(rows [][]string, targets []float64) error {
+	if len(rows) != len(targets) {
+		return ErrTargetsMismatch
+	}
+	if err := te.fit(rows); err != nil {
+		return err
+	}
+	te.prior = 0
+	for _, y := range targets {
+		te.prior += y
+	}
+	te.prior /= float64(len(targets))
+	te.means = make([][]float64, len(te.values))
+	for j, values := range te.values {
+		sums := make([]float64, len(values))
+		counts := make([]float64, len(values))
+		for i, row := range rows {
+			id := te.index[j][row[j]]
+			sums[id] += targets[i]
+			counts[id]++
+		}
+		te.means[j] = make([]float64, len(values))
+		for id := range values {
+			te.means[j][id] = (sums[id] + te.smoothing*te.prior) / (counts[id] + te.smoothing)
+		}
+	}
+	return nil
+}
+
+// Encode rows as a tensor with one feature by column
+func (te *TargetEncoder) Transform(rows [][]string) (*graph.Tensor, error) {
+	ids, err := te.lookup(rows)
+	if err != nil {
+		return nil, err
+	}
+	out := newMatrix(len(rows), len(te.index))
+	index := make([]int, 2)
+	for i, row := range ids {
+		index[0] = i
+		for j, id := range row {
+			index[1] = j
+			if id >= 0 {
+				out.SetF64(index, te.means[j][id])
+			} else {
+				out.SetF64(index, te.prior)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Fit and transform rows
+func (te *TargetEncoder) FitTransform(rows [][]string, targets []float64) (*graph.Tensor, error) {
+	if err := te.Fit(rows, targets); err != nil {
+		return nil, err
+	}
+	return te.Transform(rows)
+}
+
+// Mean target of every row
+func (te *TargetEncoder) Prior() float64 {
+	return te.prior
+}
+
+// Concatenate features of tensors of shape (samples, features) with the same samples, like numeric features and
+// encoded categorical features
+func Concat(tensors ...*graph.Tensor) *graph.Tensor {
+	if len(tensors) == 0 {
+		panic(ErrEmptyData)
+	}
+	rows, width := tensors[0].Shape()[0], 0
+	for _, t := range tensors {
+		if t.Shape().Dim() != 2 || t.Shape()[0] != rows {
+			panic(ErrColumnsMismatch)
+		}
+		width += t.Shape()[1]
+	}
+	out := newMatrix(rows, width)
+	index := make([]int, 2)
+	offset := 0
+	for _, t := range tensors {
+		for i, p := range knn.PointsFromTensor(t) {
+			index[0] = i
+			for j, v := range p {
+				index[1] = offset + j
+				out.SetF64(index, v)
+			}
+		}
+		offset += t.Shape()[1]
+	}
+	return out
+}
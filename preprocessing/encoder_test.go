@@ -0,0 +1,97 @@
+package preprocessing
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var rows = [][]string{
+	{"red", "small"},
+	{"green", "large"},
+	{"red", "large"},
+	{"blue", "small"},
+}
+
+func TestOrdinalEncoder(t *testing.T) {
+	oe := NewOrdinalEncoder(UnknownError)
+	x, err := oe.FitTransform(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := knn.PointsFromTensor(x)
+	if points[2][0] != 0 || points[3][0] != 2 || points[1][1] != 1 {
+		t.Errorf("OrdinalEncoder failed. Expected indices of categories, but got %v", points)
+	}
+	if _, err := oe.Transform([][]string{{"pink", "small"}}); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("OrdinalEncoder failed. Expected %v, but got %v", ErrUnknownCategory, err)
+	}
+	ignore := NewOrdinalEncoder(UnknownIgnore)
+	ignore.Fit(rows)
+	x, _ = ignore.Transform([][]string{{"pink", "small"}})
+	if p := knn.PointsFromTensor(x)[0]; p[0] != -1 || p[1] != 0 {
+		t.Errorf("OrdinalEncoder failed. Expected [-1 0], but got %v", p)
+	}
+}
+
+func TestOneHotEncoder(t *testing.T) {
+	oh := NewOneHotEncoder(UnknownIgnore)
+	x, err := oh.FitTransform(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := x.Shape(); s[0] != 4 || s[1] != 5 {
+		t.Fatalf("OneHotEncoder failed. Expected shape (4, 5), but got %v", s)
+	}
+	names := oh.FeatureNames([]string{"color", "size"})
+	if names[2] != "color=blue" || names[4] != "size=large" {
+		t.Errorf("FeatureNames failed. Expected column=category names, but got %v", names)
+	}
+	x, _ = oh.Transform([][]string{{"pink", "large"}})
+	expected := knn.Point{0, 0, 0, 0, 1}
+	for j, v := range knn.PointsFromTensor(x)[0] {
+		if v != expected[j] {
+			t.Errorf("OneHotEncoder failed. Expected %v, but got %v", expected, knn.PointsFromTensor(x)[0])
+			break
+		}
+	}
+	if _, err := oh.Transform([][]string{{"red"}}); err != ErrColumnsMismatch {
+		t.Errorf("OneHotEncoder failed. Expected %v, but got %v", ErrColumnsMismatch, err)
+	}
+}
+
+func TestTargetEncoder(t *testing.T) {
+	te := NewTargetEncoder(1)
+	targets := []float64{1, 0, 1, 0}
+	x, err := te.FitTransform(rows, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// red has two targets 1 and prior 0.5, so (2 + 0.5) / 3
+	if p := knn.PointsFromTensor(x)[0]; math.Abs(p[0]-2.5/3) > 1e-12 || math.Abs(p[1]-1.5/3) > 1e-12 {
+		t.Errorf("TargetEncoder failed. Expected [%v %v], but got %v", 2.5/3, 1.5/3, p)
+	}
+	x, _ = te.Transform([][]string{{"pink", "huge"}})
+	if p := knn.PointsFromTensor(x)[0]; p[0] != te.Prior() || p[1] != te.Prior() {
+		t.Errorf("TargetEncoder failed. Expected prior %v of unknown categories, but got %v", te.Prior(), p)
+	}
+	if err := te.Fit(rows, targets[:2]); err != ErrTargetsMismatch {
+		t.Errorf("TargetEncoder failed. Expected %v, but got %v", ErrTargetsMismatch, err)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	oe := NewOrdinalEncoder(UnknownError)
+	a, _ := oe.FitTransform(rows)
+	oh := NewOneHotEncoder(UnknownError)
+	b, _ := oh.FitTransform(rows)
+	x := Concat(a, b)
+	if s := x.Shape(); s[0] != 4 || s[1] != 7 {
+		t.Fatalf("Concat failed. Expected shape (4, 7), but got %v", s)
+	}
+	if p := knn.PointsFromTensor(x)[3]; p[0] != 2 || p[4] != 1 {
+		t.Errorf("Concat failed. Expected features of both tensors, but got %v", p)
+	}
+}
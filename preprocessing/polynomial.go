@@ -0,0 +1,128 @@
+package preprocessing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var ErrDegreeNotValid = errors.New("degree is lesser than one")
+
+// Expansion of features into every product of features up to a degree, so linear models fit polynomials
+type PolynomialFeatures struct {
+	degree          int
+	interactionOnly bool
+	bias            bool
+	dim             int
+	powers          [][]int
+}
+
+// Create polynomial expansion, with interactionOnly no feature is multiplied by itself and with bias the first
+// output feature is constant one
+func NewPolynomialFeatures(degree int, interactionOnly, bias bool) *PolynomialFeatures {
+	if degree < 1 {
+		panic(ErrDegreeNotValid)
+	}
+	return &PolynomialFeatures{degree: degree, interactionOnly: interactionOnly, bias: bias}
+}
+
+// Fit terms of features of x of shape (samples, features), terms are in increasing degree and lexicographic order
+func (pf *PolynomialFeatures) Fit(x *graph.Tensor) error {
+	if x.Shape().Dim() != 2 {
+		return knn.ErrTensorNotMatrix
+	}
+	pf.dim = x.Shape()[1]
+	pf.powers = nil
+	if pf.bias {
+		pf.powers = append(pf.powers, make([]int, pf.dim))
+	}
+	// combinations of features with repetition unless interactionOnly, by nondecreasing feature indices
+	var combine func(start, left int, powers []int)
+	combine = func(start, left int, powers []int) {
+		if left == 0 {
+			pf.powers = append(pf.powers, append([]int(nil), powers...))
+			return
+		}
+		for f := start; f < pf.dim; f++ {
+			powers[f]++
+			next := f
+			if pf.interactionOnly {
+				next = f + 1
+			}
+			combine(next, left-1, powers)
+			powers[f]--
+		}
+	}
+	for d := 1; d <= pf.degree; d++ {
+		combine(0, d, make([]int, pf.dim))
+	}
+	return nil
+}
+
+// Expand features of x, panics if it isn't fitted or features don't match fitted features
+func (pf *PolynomialFeatures) Transform(x *graph.Tensor) *graph.Tensor {
+	if pf.powers == nil {
+		panic(ErrNotFitted)
+	}
+	points := knn.PointsFromTensor(x)
+	if x.Shape()[1] != pf.dim {
+		panic(ErrColumnsMismatch)
+	}
+	out := newMatrix(len(points), len(pf.powers))
+	index := make([]int, 2)
+	for i, p := range points {
+		index[0] = i
+		for j, powers := range pf.powers {
+			v := 1.0
+			for f, e := range powers {
+				for ; e > 0; e-- {
+					v *= p[f]
+				}
+			}
+			index[1] = j
+			out.SetF64(index, v)
+		}
+	}
+	return out
+}
+
+// Fit and transform x
+func (pf *PolynomialFeatures) FitTransform(x *graph.Tensor) (*graph.Tensor, error) {
+	if err := pf.Fit(x); err != nil {
+		return nil, err
+	}
+	return pf.Transform(x), nil
+}
+
+// Power of every input feature of every output feature
+func (pf *PolynomialFeatures) Powers() [][]int {
+	return pf.powers
+}
+
+// Names of output features as products of names of input features, like "a^2 b", the bias is named "1"
+func (pf *PolynomialFeatures) FeatureNames(names []string) []string {
+	if len(names) != pf.dim {
+		panic(ErrColumnsMismatch)
+	}
+	out := make([]string, len(pf.powers))
+	for j, powers := range pf.powers {
+		terms := make([]string, 0, pf.degree)
+		for f, e := range powers {
+			switch {
+			case e == 1:
+				terms = append(terms, names[f])
+			case e > 1:
+				terms = append(terms, fmt.Sprintf("%s^%d", names[f], e))
+			}
+		}
+		if len(terms) == 0 {
+			out[j] = "1"
+		} else {
+			out[j] = strings.Join(terms, " ")
+		}
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package preprocessing
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/linear"
+	"github.com/stellviaproject/go-ia/model"
+)
+
+func TestPolynomialFeatures(t *testing.T) {
+	x := model.Tensor([]knn.Point{{2, 3}})
+	pf := NewPolynomialFeatures(2, false, true)
+	out, err := pf.FitTransform(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := knn.Point{1, 2, 3, 4, 6, 9}
+	got := knn.PointsFromTensor(out)[0]
+	if len(got) != len(expected) {
+		t.Fatalf("PolynomialFeatures failed. Expected %v, but got %v", expected, got)
+	}
+	for j := range expected {
+		if got[j] != expected[j] {
+			t.Errorf("PolynomialFeatures failed. Expected %v, but got %v", expected, got)
+			break
+		}
+	}
+	names := pf.FeatureNames([]string{"a", "b"})
+	if names[0] != "1" || names[3] != "a^2" || names[4] != "a b" {
+		t.Errorf("FeatureNames failed. Expected [1 a b a^2 a b b^2], but got %v", names)
+	}
+	interaction := NewPolynomialFeatures(3, true, false)
+	interaction.Fit(model.Tensor([]knn.Point{{1, 2, 3}}))
+	if n := len(interaction.Powers()); n != 7 {
+		t.Errorf("PolynomialFeatures failed. Expected 7 interaction terms, but got %v", n)
+	}
+}
+
+func TestPolynomialRegression(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := make([]knn.Point, 100)
+	y := make([]any, len(points))
+	for i := range points {
+		a, b := rnd.Float64()*4-2, rnd.Float64()*4-2
+		points[i] = knn.Point{a, b}
+		y[i] = 1 + a*a - 2*a*b + 0.01*rnd.NormFloat64()
+	}
+	x := model.Tensor(points)
+	linearFit := model.NewLinearRegression(linear.NewOLS())
+	linearFit.Fit(x, y)
+	pf := NewPolynomialFeatures(2, false, false)
+	expanded, _ := pf.FitTransform(x)
+	polyFit := model.NewLinearRegression(linear.NewOLS())
+	polyFit.Fit(expanded, y)
+	if s := polyFit.Score(expanded, y); s < 0.99 || s <= linearFit.Score(x, y) {
+		t.Errorf("PolynomialFeatures failed. Expected R2 greater than 0.99, but got %v", s)
+	}
+}
@@ -0,0 +1,72 @@
+// Package progress reports the advance of long-running jobs, like training and searches
+package progress
+
+import (
+	"sync"
+	"time"
+)
+
+// Report of advance of a job
+type Report struct {
+	Done    int           //steps done
+	Total   int           //steps of job, it may be an upper bound like the max of iterations
+	Percent float64       //percent of steps done in [0, 100]
+	Elapsed time.Duration //time since start of job
+	ETA     time.Duration //estimated time to finish job from the mean time of steps done
+}
+
+// Func receives reports of a job, like a progress bar of a UI or a log
+type Func func(Report)
+
+// Tracker counts steps of a job and reports them to a Func, it is safe for concurrent use
+type Tracker struct {
+	mu    sync.Mutex
+	fn    Func
+	total int
+	done  int
+	start time.Time
+	now   func() time.Time
+}
+
+// Create tracker of job with total steps, a nil fn reports nothing
+func NewTracker(total int, fn Func) *Tracker {
+	return &Tracker{fn: fn, total: total, start: time.Now(), now: time.Now}
+}
+
+// Add n steps done and report the advance
+func (t *Tracker) Step(n int) {
+	if t == nil || t.fn == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done += n
+	if t.done > t.total {
+		t.done = t.total
+	}
+	t.fn(t.report())
+}
+
+// Report all steps done, like when a job converges before its upper bound of steps
+func (t *Tracker) Finish() {
+	if t == nil || t.fn == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done < t.total {
+		t.done = t.total
+		t.fn(t.report())
+	}
+}
+
+func (t *Tracker) report() Report {
+	r := Report{Done: t.done, Total: t.total, Elapsed: t.now().Sub(t.start)}
+	if t.total > 0 {
+		r.Percent = 100 * float64(t.done) / float64(t.total)
+	}
+	if t.done > 0 {
+		r.ETA = time.Duration(float64(r.Elapsed) / float64(t.done) * float64(t.total-t.done))
+	}
+	return r
+}
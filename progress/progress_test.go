@@ -0,0 +1,44 @@
+package progress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	var reports []Report
+	tr := NewTracker(4, func(r Report) {
+		reports = append(reports, r)
+	})
+	start := tr.start
+	clock := start
+	tr.now = func() time.Time {
+		return clock
+	}
+	clock = start.Add(2 * time.Second)
+	tr.Step(1)
+	clock = start.Add(4 * time.Second)
+	tr.Step(1)
+	tr.Finish()
+	expected := []Report{
+		{Done: 1, Total: 4, Percent: 25, Elapsed: 2 * time.Second, ETA: 6 * time.Second},
+		{Done: 2, Total: 4, Percent: 50, Elapsed: 4 * time.Second, ETA: 4 * time.Second},
+		{Done: 4, Total: 4, Percent: 100, Elapsed: 4 * time.Second},
+	}
+	if len(reports) != len(expected) {
+		t.Fatalf("Tracker failed. Expected %v reports, but got %v", len(expected), len(reports))
+	}
+	for i := range expected {
+		if reports[i] != expected[i] {
+			t.Errorf("Tracker failed. Expected %v, but got %v", expected[i], reports[i])
+		}
+	}
+	// finished tracker doesn't report again
+	tr.Finish()
+	if len(reports) != len(expected) {
+		t.Errorf("Finish failed. Expected %v reports, but got %v", len(expected), len(reports))
+	}
+	var nilTracker *Tracker
+	nilTracker.Step(1)
+	NewTracker(1, nil).Step(1)
+}
@@ -0,0 +1,317 @@
+// Package recommender implements collaborative filtering by matrix factorization
+package recommender
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/stellviaproject/go-ia/linalg"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrEmptyRatings   = errors.New("there are no ratings")
+	ErrIndexNotValid  = errors.New("user or item index is negative")
+	ErrNotFitted      = errors.New("recommender is not fitted")
+	ErrSolverNotValid = errors.New("solver is not valid")
+)
+
+// Rating of item by user
+type Rating struct {
+	User, Item int
+	Value      float64
+}
+
+// Get ratings from a tensor of shape (users, items), zero elements are unrated
+func RatingsFromTensor(t *graph.Tensor) []Rating {
+	shape := t.Shape()
+	if shape.Dim() != 2 {
+		panic(graph.ErrInvalidShape)
+	}
+	ratings := make([]Rating, 0)
+	index := make([]int, 2)
+	for u := 0; u < shape[0]; u++ {
+		index[0] = u
+		for i := 0; i < shape[1]; i++ {
+			index[1] = i
+			var v float64
+			switch x := t.Get(index).(type) {
+			case float32:
+				v = float64(x)
+			case float64:
+				v = x
+			default:
+				v = t.GetF16At(index).ToF64()
+			}
+			if v != 0 {
+				ratings = append(ratings, Rating{User: u, Item: i, Value: v})
+			}
+		}
+	}
+	return ratings
+}
+
+// Algorithm of factorization
+type Solver int
+
+const (
+	SGD Solver = iota //stochastic gradient descent
+	ALS               //alternating least squares
+)
+
+// Configuration of matrix factorization, zero fields take the default values
+type Config struct {
+	Factors int     //latent factors, 10 by default
+	Lambda  float64 //L2 regularization, 0.02 by default
+	Rate    float64 //learning rate of SGD, 0.01 by default
+	Epochs  int     //passes over ratings, 20 by default
+	Solver  Solver
+	Seed    int64
+}
+
+// Matrix factorization with global mean, user and item biases and latent factors
+//
+// Rating of item i by user u is predicted as mean + bu + bi + pu·qi
+type MF struct {
+	config   Config
+	mean     float64
+	userBias []float64
+	itemBias []float64
+	users    [][]float64
+	items    [][]float64
+	rated    []map[int]bool
+}
+
+// Create matrix factorization recommender
+func NewMF(config Config) *MF {
+	if config.Factors == 0 {
+		config.Factors = 10
+	}
+	if config.Lambda == 0 {
+		config.Lambda = 0.02
+	}
+	if config.Rate == 0 {
+		config.Rate = 0.01
+	}
+	if config.Epochs == 0 {
+		config.Epochs = 20
+	}
+	if config.Solver != SGD && config.Solver != ALS {
+		panic(ErrSolverNotValid)
+	}
+	return &MF{config: config}
+}
+
+// Fit factors to ratings and return RMSE of training ratings after every epoch
+func (mf *MF) Fit(ratings []Rating) []float64 {
+	if len(ratings) == 0 {
+		panic(ErrEmptyRatings)
+	}
+	users, items := 0, 0
+	mf.mean = 0
+	for _, r := range ratings {
+		if r.User < 0 || r.Item < 0 {
+			panic(ErrIndexNotValid)
+		}
+		if r.User >= users {
+			users = r.User + 1
+		}
+		if r.Item >= items {
+			items = r.Item + 1
+		}
+		mf.mean += r.Value
+	}
+	mf.mean /= float64(len(ratings))
+	rnd := rand.New(rand.NewSource(mf.config.Seed))
+	init := func(n int) [][]float64 {
+		out := make([][]float64, n)
+		for i := range out {
+			out[i] = make([]float64, mf.config.Factors)
+			for k := range out[i] {
+				out[i][k] = 0.1 * rnd.NormFloat64()
+			}
+		}
+		return out
+	}
+	mf.users, mf.items = init(users), init(items)
+	mf.userBias, mf.itemBias = make([]float64, users), make([]float64, items)
+	mf.rated = make([]map[int]bool, users)
+	for _, r := range ratings {
+		if mf.rated[r.User] == nil {
+			mf.rated[r.User] = make(map[int]bool)
+		}
+		mf.rated[r.User][r.Item] = true
+	}
+	history := make([]float64, 0, mf.config.Epochs)
+	order := make([]int, len(ratings))
+	for i := range order {
+		order[i] = i
+	}
+	for epoch := 0; epoch < mf.config.Epochs; epoch++ {
+		if mf.config.Solver == SGD {
+			rnd.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+			mf.sgd(ratings, order)
+		} else {
+			mf.als(ratings, true)
+			mf.als(ratings, false)
+		}
+		history = append(history, mf.RMSE(ratings))
+	}
+	return history
+}
+
+// one epoch of stochastic gradient descent
+func (mf *MF) sgd(ratings []Rating, order []int) {
+	rate, lambda := mf.config.Rate, mf.config.Lambda
+	for _, idx := range order {
+		r := ratings[idx]
+		p, q := mf.users[r.User], mf.items[r.Item]
+		e := r.Value - mf.predict(r.User, r.Item)
+		mf.userBias[r.User] += rate * (e - lambda*mf.userBias[r.User])
+		mf.itemBias[r.Item] += rate * (e - lambda*mf.itemBias[r.Item])
+		for k := range p {
+			pk := p[k]
+			p[k] += rate * (e*q[k] - lambda*pk)
+			q[k] += rate * (e*pk - lambda*q[k])
+		}
+	}
+}
+
+// solve factors and bias of users, or items, with the other side fixed
+//
+// Fixed vectors are extended by a constant 1 so bias is solved together with factors, and regularization is
+// weighted by number of ratings
+func (mf *MF) als(ratings []Rating, users bool) {
+	factors, bias := mf.items, mf.itemBias
+	solve, solveBias := mf.users, mf.userBias
+	if !users {
+		factors, bias = mf.users, mf.userBias
+		solve, solveBias = mf.items, mf.itemBias
+	}
+	dim := mf.config.Factors + 1
+	grouped := make([][]Rating, len(solve))
+	for _, r := range ratings {
+		if users {
+			grouped[r.User] = append(grouped[r.User], r)
+		} else {
+			grouped[r.Item] = append(grouped[r.Item], r)
+		}
+	}
+	x := make([]float64, dim)
+	for s, group := range grouped {
+		if len(group) == 0 {
+			continue
+		}
+		a := linalg.NewMatrix(dim, dim, nil)
+		b := make([]float64, dim)
+		for _, r := range group {
+			other := r.Item
+			if !users {
+				other = r.User
+			}
+			copy(x, factors[other])
+			x[dim-1] = 1
+			target := r.Value - mf.mean - bias[other]
+			for i := 0; i < dim; i++ {
+				b[i] += x[i] * target
+				for j := 0; j < dim; j++ {
+					a.Set(i, j, a.At(i, j)+x[i]*x[j])
+				}
+			}
+		}
+		reg := mf.config.Lambda * float64(len(group))
+		for i := 0; i < dim; i++ {
+			a.Set(i, i, a.At(i, i)+reg)
+		}
+		sol, err := a.Solve(b)
+		if err != nil {
+			continue
+		}
+		copy(solve[s], sol[:dim-1])
+		solveBias[s] = sol[dim-1]
+	}
+}
+
+func (mf *MF) predict(user, item int) float64 {
+	v := mf.mean
+	if user < len(mf.users) {
+		v += mf.userBias[user]
+	}
+	if item < len(mf.items) {
+		v += mf.itemBias[item]
+	}
+	if user < len(mf.users) && item < len(mf.items) {
+		p, q := mf.users[user], mf.items[item]
+		for k := range p {
+			v += p[k] * q[k]
+		}
+	}
+	return v
+}
+
+// Predict rating of item by user, unknown users or items are predicted by biases and global mean
+func (mf *MF) Predict(user, item int) float64 {
+	if mf.users == nil {
+		panic(ErrNotFitted)
+	}
+	if user < 0 || item < 0 {
+		panic(ErrIndexNotValid)
+	}
+	return mf.predict(user, item)
+}
+
+// Root mean squared error of predicted ratings
+func (mf *MF) RMSE(ratings []Rating) float64 {
+	if len(ratings) == 0 {
+		panic(ErrEmptyRatings)
+	}
+	sum := 0.0
+	for _, r := range ratings {
+		e := r.Value - mf.Predict(r.User, r.Item)
+		sum += e * e
+	}
+	return math.Sqrt(sum / float64(len(ratings)))
+}
+
+// Recommended item with its predicted rating
+type Recommendation struct {
+	Item  int
+	Score float64
+}
+
+// Recommend n items with greatest predicted rating for user, items rated in training are excluded
+func (mf *MF) Recommend(user, n int) []Recommendation {
+	if mf.users == nil {
+		panic(ErrNotFitted)
+	}
+	if user < 0 {
+		panic(ErrIndexNotValid)
+	}
+	var rated map[int]bool
+	if user < len(mf.rated) {
+		rated = mf.rated[user]
+	}
+	recs := make([]Recommendation, 0, len(mf.items))
+	for item := range mf.items {
+		if !rated[item] {
+			recs = append(recs, Recommendation{Item: item, Score: mf.predict(user, item)})
+		}
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].Score > recs[j].Score })
+	if n < len(recs) {
+		recs = recs[:n]
+	}
+	return recs
+}
+
+// Latent factors of user
+func (mf *MF) UserFactors(user int) []float64 {
+	return mf.users[user]
+}
+
+// Latent factors of item
+func (mf *MF) ItemFactors(item int) []float64 {
+	return mf.items[item]
+}
@@ -0,0 +1,98 @@
+package recommender
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// ratings of rank 2 with noise, 30% of them observed
+func lowRank(users, items int, seed int64) []Rating {
+	rnd := rand.New(rand.NewSource(seed))
+	p, q := make([][2]float64, users), make([][2]float64, items)
+	for u := range p {
+		p[u] = [2]float64{rnd.NormFloat64(), rnd.NormFloat64()}
+	}
+	for i := range q {
+		q[i] = [2]float64{rnd.NormFloat64(), rnd.NormFloat64()}
+	}
+	ratings := make([]Rating, 0)
+	for u := range p {
+		for i := range q {
+			if rnd.Float64() < 0.3 {
+				v := 3 + p[u][0]*q[i][0] + p[u][1]*q[i][1] + 0.1*rnd.NormFloat64()
+				ratings = append(ratings, Rating{User: u, Item: i, Value: v})
+			}
+		}
+	}
+	return ratings
+}
+
+func TestMF(t *testing.T) {
+	ratings := lowRank(100, 80, 1)
+	rand.New(rand.NewSource(1)).Shuffle(len(ratings), func(i, j int) { ratings[i], ratings[j] = ratings[j], ratings[i] })
+	split := len(ratings) * 4 / 5
+	train, test := ratings[:split], ratings[split:]
+	mean := 0.0
+	for _, r := range train {
+		mean += r.Value
+	}
+	mean /= float64(len(train))
+	baseline := 0.0
+	for _, r := range test {
+		baseline += (r.Value - mean) * (r.Value - mean)
+	}
+	baseline = math.Sqrt(baseline / float64(len(test)))
+	for _, config := range []Config{
+		{Factors: 2, Lambda: 0.05, Rate: 0.02, Epochs: 100, Solver: SGD, Seed: 1},
+		{Factors: 2, Lambda: 0.05, Epochs: 15, Solver: ALS, Seed: 1},
+	} {
+		mf := NewMF(config)
+		history := mf.Fit(train)
+		if history[len(history)-1] >= history[0] {
+			t.Errorf("Fit failed. Training error didn't decrease %v", history)
+		}
+		if rmse := mf.RMSE(test); rmse > 0.5*baseline {
+			t.Errorf("RMSE failed. Expected lesser than half of baseline %v, but got %v with solver %v", baseline, rmse, config.Solver)
+		}
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	ratings := lowRank(30, 20, 2)
+	mf := NewMF(Config{Factors: 2, Solver: ALS})
+	mf.Fit(ratings)
+	recs := mf.Recommend(0, 5)
+	if len(recs) != 5 {
+		t.Fatalf("Recommend failed. Expected 5 items, but got %v", len(recs))
+	}
+	for i, rec := range recs {
+		for _, r := range ratings {
+			if r.User == 0 && r.Item == rec.Item {
+				t.Errorf("Recommend failed. Item %v was rated by user", rec.Item)
+			}
+		}
+		if i > 0 && rec.Score > recs[i-1].Score {
+			t.Errorf("Recommend failed. Items are not sorted by score")
+		}
+	}
+}
+
+func TestRatingsFromTensor(t *testing.T) {
+	ts := graph.NewTensor(nil, graph.Float64, graph.NewShape(2, 3))
+	ts.Set([]int{0, 0}, 5.0)
+	ts.Set([]int{0, 2}, 3.0)
+	ts.Set([]int{1, 2}, 1.0)
+	ratings := RatingsFromTensor(ts)
+	expected := []Rating{{0, 0, 5}, {0, 2, 3}, {1, 2, 1}}
+	if len(ratings) != len(expected) {
+		t.Fatalf("RatingsFromTensor failed. Expected %v, but got %v", expected, ratings)
+	}
+	for i := range expected {
+		if ratings[i] != expected[i] {
+			t.Errorf("RatingsFromTensor failed. Expected %v, but got %v", expected[i], ratings[i])
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package rl
+
+import (
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+// Transition of an environment, states are observation vectors
+type Transition struct {
+	State  []float64
+	Action int
+	Reward float64
+	Next   []float64
+	Done   bool
+}
+
+// Ring buffer of the last transitions
+type ReplayBuffer struct {
+	items    []Transition
+	capacity int
+	next     int
+}
+
+// Create replay buffer keeping capacity transitions
+func NewReplayBuffer(capacity int) *ReplayBuffer {
+	if capacity < 1 {
+		panic(ErrParamNotValid)
+	}
+	return &ReplayBuffer{items: make([]Transition, 0, capacity), capacity: capacity}
+}
+
+// Add transition, replacing the oldest one when buffer is full
+func (rb *ReplayBuffer) Add(tr Transition) {
+	if len(rb.items) < rb.capacity {
+		rb.items = append(rb.items, tr)
+	} else {
+		rb.items[rb.next] = tr
+	}
+	rb.next = (rb.next + 1) % rb.capacity
+}
+
+// Number of transitions in buffer
+func (rb *ReplayBuffer) Len() int {
+	return len(rb.items)
+}
+
+// Sample n transitions uniformly with replacement
+func (rb *ReplayBuffer) Sample(rnd *rand.Rand, n int) []Transition {
+	out := make([]Transition, n)
+	for i := range out {
+		out[i] = rb.items[rnd.Intn(len(rb.items))]
+	}
+	return out
+}
+
+// Observation of state i as a vector of states values with 1 at i
+func OneHot(states int) func(state int) []float64 {
+	return func(state int) []float64 {
+		out := make([]float64, states)
+		out[state] = 1
+		return out
+	}
+}
+
+// Configuration of DQN, zero fields take the default values
+type DQNConfig struct {
+	Gamma      float64 //discount of future rewards
+	BatchSize  int     //transitions by gradient step, 32 by default
+	BufferSize int     //capacity of replay buffer, 10000 by default
+	Warmup     int     //transitions before the first gradient step, BatchSize by default
+	SyncEvery  int     //steps between copies of network to target network, 100 by default
+	TrainEvery int     //steps between gradient steps, 1 by default
+	Delta      float64 //threshold of Huber loss of temporal differences, 1 by default
+}
+
+// Deep Q-network agent, action values are the outputs of a network of the state observation
+//
+// Transitions are learned from a replay buffer and targets come from a copy of the network synchronized every
+// SyncEvery steps
+type DQN struct {
+	config  DQNConfig
+	net     *layers.Sequential
+	target  *layers.Sequential
+	opt     optim.Optimizer
+	policy  Policy
+	observe func(state int) []float64
+	buffer  *ReplayBuffer
+	rnd     *rand.Rand
+	steps   int
+}
+
+// Create DQN agent with network of one output by action, its optimizer, exploration policy and observation
+// of states, like OneHot
+func NewDQN(net *layers.Sequential, opt optim.Optimizer, policy Policy, observe func(state int) []float64, config DQNConfig, seed int64) *DQN {
+	if config.Gamma < 0 || config.Gamma > 1 {
+		panic(ErrParamNotValid)
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 32
+	}
+	if config.BufferSize == 0 {
+		config.BufferSize = 10000
+	}
+	if config.Warmup == 0 {
+		config.Warmup = config.BatchSize
+	}
+	if config.SyncEvery == 0 {
+		config.SyncEvery = 100
+	}
+	if config.TrainEvery == 0 {
+		config.TrainEvery = 1
+	}
+	if config.Delta == 0 {
+		config.Delta = 1
+	}
+	return &DQN{
+		config:  config,
+		net:     net,
+		target:  net.Clone(),
+		opt:     opt,
+		policy:  policy,
+		observe: observe,
+		buffer:  NewReplayBuffer(config.BufferSize),
+		rnd:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Action values of state
+func (dqn *DQN) Values(state int) []float64 {
+	return dqn.net.Forward(dqn.observe(state))
+}
+
+// Best action of state
+func (dqn *DQN) Best(state int) int {
+	values := dqn.Values(state)
+	best := 0
+	for a, v := range values {
+		if v > values[best] {
+			best = a
+		}
+	}
+	return best
+}
+
+func (dqn *DQN) Act(state int, rnd *rand.Rand) int {
+	return dqn.policy.Select(dqn.Values(state), rnd)
+}
+
+func (dqn *DQN) Learn(state, action int, reward float64, next, nextAction int, done bool) {
+	dqn.buffer.Add(Transition{State: dqn.observe(state), Action: action, Reward: reward, Next: dqn.observe(next), Done: done})
+	dqn.steps++
+	if dqn.buffer.Len() >= dqn.config.Warmup && dqn.steps%dqn.config.TrainEvery == 0 {
+		dqn.train(dqn.buffer.Sample(dqn.rnd, dqn.config.BatchSize))
+	}
+	if dqn.steps%dqn.config.SyncEvery == 0 {
+		dqn.target.CopyFrom(dqn.net)
+	}
+}
+
+// gradient step of the mean Huber loss of temporal differences of batch
+func (dqn *DQN) train(batch []Transition) {
+	dqn.net.ZeroGrad()
+	for _, tr := range batch {
+		target := tr.Reward
+		if !tr.Done {
+			values := dqn.target.Forward(tr.Next)
+			best := values[0]
+			for _, v := range values[1:] {
+				if v > best {
+					best = v
+				}
+			}
+			target += dqn.config.Gamma * best
+		}
+		q := dqn.net.Forward(tr.State)
+		_, g := layers.HuberLoss(q[tr.Action:tr.Action+1], []float64{target}, dqn.config.Delta)
+		grad := make([]float64, len(q))
+		grad[tr.Action] = g[0] / float64(len(batch))
+		dqn.net.Backward(grad)
+	}
+	dqn.opt.Step(dqn.net.Params(), dqn.net.Grads())
+}
+
+func (dqn *DQN) EndEpisode() {
+	dqn.policy.EndEpisode()
+}
@@ -0,0 +1,45 @@
+package rl
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+)
+
+func TestReplayBuffer(t *testing.T) {
+	rb := NewReplayBuffer(3)
+	for i := 0; i < 5; i++ {
+		rb.Add(Transition{Action: i})
+	}
+	if rb.Len() != 3 {
+		t.Errorf("Len failed. Expected 3, but got %d", rb.Len())
+	}
+	for _, tr := range rb.Sample(rand.New(rand.NewSource(1)), 20) {
+		if tr.Action < 2 {
+			t.Errorf("Sample failed. Old transition %d was not replaced", tr.Action)
+		}
+	}
+}
+
+func TestDQN(t *testing.T) {
+	env := NewGridWorld(4, 4, [2]int{0, 0}, [2]int{3, 3}, [2]int{1, 1}, [2]int{2, 1})
+	net := layers.NewSequential(1, layers.NewDense(16, 32), layers.NewReLU(), layers.NewDense(32, 4))
+	policy := &EpsilonGreedy{Epsilon: 1, Decay: 0.98, Min: 0.05}
+	agent := NewDQN(net, optim.NewAdam(0.005), policy, OneHot(16), DQNConfig{Gamma: 0.95, SyncEvery: 50}, 1)
+	Train(env, agent, 300, 100, 1)
+	// greedy path avoids holes and reaches goal in 6 moves
+	state := env.Reset(nil)
+	for step := 1; step <= 20; step++ {
+		next, reward, done := env.Step(agent.Best(state))
+		if done {
+			if reward != env.GoalReward || step != 6 {
+				t.Errorf("DQN failed. Expected goal in 6 moves, but got reward %v in %d moves", reward, step)
+			}
+			return
+		}
+		state = next
+	}
+	t.Errorf("DQN failed. Greedy policy doesn't reach goal")
+}
@@ -0,0 +1,130 @@
+package rl
+
+import "math/rand"
+
+// Moves of grid world
+const (
+	Up = iota
+	Right
+	Down
+	Left
+)
+
+// Grid world, the agent moves from start to goal and falls in holes, every step costs StepReward
+//
+// Falling in a hole ends the episode, or it sends the agent back to start if ResetOnHole is set
+type GridWorld struct {
+	Width, Height int
+	Start, Goal   [2]int //column and row
+	Holes         map[[2]int]bool
+	StepReward    float64 //reward of every move, -1 by default
+	GoalReward    float64
+	HoleReward    float64
+	ResetOnHole   bool
+	pos           [2]int
+}
+
+// Create grid world with -1 by step, 0 at goal and -100 in holes
+func NewGridWorld(width, height int, start, goal [2]int, holes ...[2]int) *GridWorld {
+	gw := &GridWorld{Width: width, Height: height, Start: start, Goal: goal, Holes: make(map[[2]int]bool),
+		StepReward: -1, HoleReward: -100}
+	for _, h := range holes {
+		gw.Holes[h] = true
+	}
+	return gw
+}
+
+// Create the cliff walking world of Sutton and Barto, a 12x4 grid with a cliff between start and goal
+//
+// Falling from the cliff costs 100 and sends the agent back to start
+func NewCliffWalking() *GridWorld {
+	holes := make([][2]int, 0, 10)
+	for x := 1; x < 11; x++ {
+		holes = append(holes, [2]int{x, 3})
+	}
+	gw := NewGridWorld(12, 4, [2]int{0, 3}, [2]int{11, 3}, holes...)
+	gw.ResetOnHole = true
+	return gw
+}
+
+func (gw *GridWorld) States() int {
+	return gw.Width * gw.Height
+}
+
+func (gw *GridWorld) Actions() int {
+	return 4
+}
+
+// State of cell
+func (gw *GridWorld) State(cell [2]int) int {
+	return cell[1]*gw.Width + cell[0]
+}
+
+func (gw *GridWorld) Reset(rnd *rand.Rand) int {
+	gw.pos = gw.Start
+	return gw.State(gw.pos)
+}
+
+func (gw *GridWorld) Step(action int) (int, float64, bool) {
+	next := gw.pos
+	switch action {
+	case Up:
+		next[1]--
+	case Right:
+		next[0]++
+	case Down:
+		next[1]++
+	case Left:
+		next[0]--
+	default:
+		panic(ErrActionNotValid)
+	}
+	if next[0] >= 0 && next[0] < gw.Width && next[1] >= 0 && next[1] < gw.Height {
+		gw.pos = next
+	}
+	switch {
+	case gw.pos == gw.Goal:
+		return gw.State(gw.pos), gw.GoalReward, true
+	case gw.Holes[gw.pos]:
+		if gw.ResetOnHole {
+			gw.pos = gw.Start
+			return gw.State(gw.pos), gw.HoleReward, false
+		}
+		return gw.State(gw.pos), gw.HoleReward, true
+	}
+	return gw.State(gw.pos), gw.StepReward, false
+}
+
+// Multi-armed bandit with a single state, every pull ends the episode with a reward of 1 with the arm probability
+type Bandit struct {
+	Probs []float64
+	rnd   *rand.Rand
+}
+
+// Create bandit with success probabilities of arms
+func NewBandit(probs ...float64) *Bandit {
+	return &Bandit{Probs: probs}
+}
+
+func (b *Bandit) States() int {
+	return 1
+}
+
+func (b *Bandit) Actions() int {
+	return len(b.Probs)
+}
+
+func (b *Bandit) Reset(rnd *rand.Rand) int {
+	b.rnd = rnd
+	return 0
+}
+
+func (b *Bandit) Step(action int) (int, float64, bool) {
+	if action < 0 || action >= len(b.Probs) {
+		panic(ErrActionNotValid)
+	}
+	if b.rnd.Float64() < b.Probs[action] {
+		return 0, 1, true
+	}
+	return 0, 0, true
+}
@@ -0,0 +1,108 @@
+// Package rl implements reinforcement learning agents of environments with discrete actions
+package rl
+
+import (
+	"errors"
+	"math/rand"
+)
+
+var (
+	ErrActionNotValid = errors.New("action is not in range [0, actions)")
+	ErrParamNotValid  = errors.New("learning parameter is not valid")
+)
+
+// Environment with discrete states and actions
+type Environment interface {
+	States() int
+	Actions() int
+	// start an episode and return its first state
+	Reset(rnd *rand.Rand) int
+	// apply action and return next state, reward and if the episode ended
+	Step(action int) (int, float64, bool)
+}
+
+// Agent that learns from transitions
+type Agent interface {
+	// select action of state while training
+	Act(state int, rnd *rand.Rand) int
+	// learn from transition, nextAction is the action the agent takes in next state
+	Learn(state, action int, reward float64, next, nextAction int, done bool)
+	// called at the end of every episode
+	EndEpisode()
+}
+
+// Policy selecting actions from action values
+type Policy interface {
+	Select(values []float64, rnd *rand.Rand) int
+	EndEpisode()
+}
+
+// Epsilon-greedy policy, it selects a random action with probability Epsilon and the best one otherwise
+//
+// Epsilon is multiplied by Decay at the end of every episode down to Min
+type EpsilonGreedy struct {
+	Epsilon float64
+	Decay   float64
+	Min     float64
+}
+
+// Create epsilon-greedy policy with constant epsilon
+func NewEpsilonGreedy(epsilon float64) *EpsilonGreedy {
+	return &EpsilonGreedy{Epsilon: epsilon, Decay: 1, Min: epsilon}
+}
+
+func (eg *EpsilonGreedy) Select(values []float64, rnd *rand.Rand) int {
+	if rnd.Float64() < eg.Epsilon {
+		return rnd.Intn(len(values))
+	}
+	return argmax(values, rnd)
+}
+
+func (eg *EpsilonGreedy) EndEpisode() {
+	eg.Epsilon *= eg.Decay
+	if eg.Epsilon < eg.Min {
+		eg.Epsilon = eg.Min
+	}
+}
+
+// index of greatest value, ties are broken at random
+func argmax(values []float64, rnd *rand.Rand) int {
+	best, ties := 0, 1
+	for i := 1; i < len(values); i++ {
+		switch {
+		case values[i] > values[best]:
+			best, ties = i, 1
+		case values[i] == values[best]:
+			ties++
+			if rnd.Intn(ties) == 0 {
+				best = i
+			}
+		}
+	}
+	return best
+}
+
+// Train agent on env for episodes of at most maxSteps steps, it returns the total reward of every episode
+func Train(env Environment, agent Agent, episodes, maxSteps int, seed int64) []float64 {
+	rnd := rand.New(rand.NewSource(seed))
+	returns := make([]float64, episodes)
+	for ep := range returns {
+		state := env.Reset(rnd)
+		action := agent.Act(state, rnd)
+		for step := 0; step < maxSteps; step++ {
+			next, reward, done := env.Step(action)
+			returns[ep] += reward
+			nextAction := -1
+			if !done {
+				nextAction = agent.Act(next, rnd)
+			}
+			agent.Learn(state, action, reward, next, nextAction, done)
+			if done {
+				break
+			}
+			state, action = next, nextAction
+		}
+		agent.EndEpisode()
+	}
+	return returns
+}
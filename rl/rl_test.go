@@ -0,0 +1,86 @@
+package rl
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// follow greedy actions of table from start
+func greedyPath(gw *GridWorld, q QTable) ([]int, bool) {
+	state := gw.Reset(nil)
+	path := []int{state}
+	for step := 0; step < 100; step++ {
+		next, reward, done := gw.Step(q.Best(state))
+		path = append(path, next)
+		if done {
+			return path, reward == gw.GoalReward
+		}
+		state = next
+	}
+	return path, false
+}
+
+func TestQLearningCliff(t *testing.T) {
+	env := NewCliffWalking()
+	agent := NewQLearning(env.States(), env.Actions(), 0.5, 1, NewEpsilonGreedy(0.1))
+	Train(env, agent, 500, 1000, 1)
+	// Q-learning finds the optimal path along the cliff, 13 moves
+	path, ok := greedyPath(env, agent.Q())
+	if !ok || len(path)-1 != 13 {
+		t.Errorf("QLearning failed. Expected path of 13 moves to goal, but got %v", path)
+	}
+}
+
+func TestSARSACliff(t *testing.T) {
+	mean := func(xs []float64) float64 {
+		sum := 0.0
+		for _, x := range xs {
+			sum += x
+		}
+		return sum / float64(len(xs))
+	}
+	env := NewCliffWalking()
+	sarsa := NewSARSA(env.States(), env.Actions(), 0.5, 1, NewEpsilonGreedy(0.1))
+	qlearning := NewQLearning(env.States(), env.Actions(), 0.5, 1, NewEpsilonGreedy(0.1))
+	// SARSA learns a safe path away from the cliff, so it falls less while exploring
+	sarsaReturns := mean(Train(env, sarsa, 500, 1000, 2)[400:])
+	qReturns := mean(Train(env, qlearning, 500, 1000, 2)[400:])
+	if sarsaReturns <= qReturns {
+		t.Errorf("SARSA failed. Expected online return greater than %v, but got %v", qReturns, sarsaReturns)
+	}
+}
+
+func TestBandit(t *testing.T) {
+	env := NewBandit(0.2, 0.8, 0.5)
+	policy := &EpsilonGreedy{Epsilon: 1, Decay: 0.99, Min: 0.05}
+	agent := NewQLearning(1, 3, 0.05, 0, policy)
+	Train(env, agent, 2000, 1, 3)
+	if best := agent.Q().Best(0); best != 1 {
+		t.Errorf("Bandit failed. Expected best arm 1, but got %d with values %v", best, agent.Q()[0])
+	}
+	if policy.Epsilon != 0.05 {
+		t.Errorf("EpsilonGreedy failed. Expected epsilon 0.05, but got %v", policy.Epsilon)
+	}
+}
+
+func TestArgmaxTies(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	counts := make([]int, 3)
+	for i := 0; i < 3000; i++ {
+		counts[argmax([]float64{1, 0, 1}, rnd)]++
+	}
+	if counts[1] != 0 || counts[0] < 1300 || counts[2] < 1300 {
+		t.Errorf("argmax failed. Expected random ties, but got %v", counts)
+	}
+}
+
+func TestGridWorld(t *testing.T) {
+	gw := NewGridWorld(3, 1, [2]int{0, 0}, [2]int{2, 0}, [2]int{1, 0})
+	gw.Reset(nil)
+	if state, reward, done := gw.Step(Left); state != 0 || reward != -1 || done {
+		t.Errorf("Step failed. Expected to stay at wall, but got %d %v %v", state, reward, done)
+	}
+	if state, reward, done := gw.Step(Right); state != 1 || reward != -100 || !done {
+		t.Errorf("Step failed. Expected to fall in hole, but got %d %v %v", state, reward, done)
+	}
+}
@@ -0,0 +1,107 @@
+package rl
+
+import (
+	"math/rand"
+)
+
+// Table of action values of every state
+type QTable [][]float64
+
+// Create table of zero values
+func NewQTable(states, actions int) QTable {
+	q := make(QTable, states)
+	for s := range q {
+		q[s] = make([]float64, actions)
+	}
+	return q
+}
+
+// Greatest value of state
+func (q QTable) Max(state int) float64 {
+	best := q[state][0]
+	for _, v := range q[state][1:] {
+		if v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+// Best action of state, the first one on ties
+func (q QTable) Best(state int) int {
+	best := 0
+	for a, v := range q[state] {
+		if v > q[state][best] {
+			best = a
+		}
+	}
+	return best
+}
+
+type tabular struct {
+	q      QTable
+	alpha  float64
+	gamma  float64
+	policy Policy
+}
+
+func newTabular(states, actions int, alpha, gamma float64, policy Policy) tabular {
+	if alpha <= 0 || alpha > 1 || gamma < 0 || gamma > 1 || states < 1 || actions < 1 {
+		panic(ErrParamNotValid)
+	}
+	return tabular{q: NewQTable(states, actions), alpha: alpha, gamma: gamma, policy: policy}
+}
+
+func (tb *tabular) Act(state int, rnd *rand.Rand) int {
+	return tb.policy.Select(tb.q[state], rnd)
+}
+
+func (tb *tabular) EndEpisode() {
+	tb.policy.EndEpisode()
+}
+
+// Action values learned by agent
+func (tb *tabular) Q() QTable {
+	return tb.q
+}
+
+// move value of state and action towards target
+func (tb *tabular) update(state, action int, target float64) {
+	tb.q[state][action] += tb.alpha * (target - tb.q[state][action])
+}
+
+// Off-policy temporal difference control, it learns the value of the greedy policy
+type QLearning struct {
+	tabular
+}
+
+// Create Q-learning agent with learning rate alpha, discount gamma and exploration policy
+func NewQLearning(states, actions int, alpha, gamma float64, policy Policy) *QLearning {
+	return &QLearning{newTabular(states, actions, alpha, gamma, policy)}
+}
+
+func (ql *QLearning) Learn(state, action int, reward float64, next, nextAction int, done bool) {
+	target := reward
+	if !done {
+		target += ql.gamma * ql.q.Max(next)
+	}
+	ql.update(state, action, target)
+}
+
+// On-policy temporal difference control, it learns the value of the exploration policy
+type SARSA struct {
+	tabular
+}
+
+// Create SARSA agent with learning rate alpha, discount gamma and exploration policy
+func NewSARSA(states, actions int, alpha, gamma float64, policy Policy) *SARSA {
+	return &SARSA{newTabular(states, actions, alpha, gamma, policy)}
+}
+
+func (sa *SARSA) Learn(state, action int, reward float64, next, nextAction int, done bool) {
+	target := reward
+	if !done {
+		target += sa.gamma * sa.q[next][nextAction]
+	}
+	sa.update(state, action, target)
+}
@@ -0,0 +1,151 @@
+package serving
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Path of Predict method of Inference service
+const GRPCPredictPath = "/goia.serving.Inference/Predict"
+
+// Status codes of gRPC used by server
+const (
+	CodeOK                = 0
+	CodeInvalidArgument   = 3
+	CodeResourceExhausted = 8
+	CodeUnimplemented     = 12
+	CodeInternal          = 13
+)
+
+// Error of a gRPC call with a status code that is not CodeOK
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("grpc status %d: %s", e.Code, e.Message)
+}
+
+func isGRPC(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// message of frame of length prefixed message
+func readFrame(r io.Reader, limit int64) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if header[0] != 0 {
+		return nil, &StatusError{Code: CodeUnimplemented, Message: "compressed messages are not supported"}
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if int64(size) > limit {
+		return nil, &StatusError{Code: CodeResourceExhausted, Message: "message is too large"}
+	}
+	message := make([]byte, size)
+	_, err := io.ReadFull(r, message)
+	return message, err
+}
+
+func frame(message []byte) []byte {
+	b := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(b[1:], uint32(len(message)))
+	return append(b, message...)
+}
+
+func (s *Server) serveGRPC(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/grpc")
+	// errors are sent as Trailers-Only responses, with the status in the headers and without body
+	fail := func(code int, message string) {
+		w.Header().Set("Grpc-Status", strconv.Itoa(code))
+		w.Header().Set("Grpc-Message", message)
+		w.WriteHeader(http.StatusOK)
+	}
+	if r.URL.Path != GRPCPredictPath {
+		fail(CodeUnimplemented, "unknown method "+r.URL.Path)
+		return
+	}
+	message, err := readFrame(r.Body, s.config.MaxBodyBytes)
+	var x *graph.Tensor
+	if err == nil {
+		x, err = unmarshalRequest(message)
+	}
+	var prediction *Prediction
+	if err == nil {
+		prediction, err = s.predict(x)
+		// models panic with inputs they can't predict
+		if err != nil && !errors.Is(err, ErrPanic) && !errors.Is(err, ErrShapeNotValid) && !errors.Is(err, ErrDTypeNotValid) {
+			err = &StatusError{Code: CodeInternal, Message: err.Error()}
+		}
+	}
+	var se *StatusError
+	switch {
+	case errors.As(err, &se):
+		fail(se.Code, se.Message)
+	case err != nil:
+		fail(CodeInvalidArgument, err.Error())
+	default:
+		w.WriteHeader(http.StatusOK)
+		w.Write(frame(marshalResponse(prediction)))
+		w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(CodeOK))
+	}
+}
+
+// Client of Inference service over HTTP/2
+type GRPCClient struct {
+	client *http.Client
+	url    string
+}
+
+// Create client of server at base url, it needs a TLS server and a client with HTTP/2 enabled since there is no h2c
+func NewGRPCClient(client *http.Client, url string) *GRPCClient {
+	return &GRPCClient{client: client, url: strings.TrimSuffix(url, "/")}
+}
+
+// Predict inputs with the served model
+func (c *GRPCClient) Predict(ctx context.Context, inputs *graph.Tensor) (*Prediction, error) {
+	body := frame(marshalRequest(inputs))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+GRPCPredictPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/grpc")
+	request.Header.Set("TE", "trailers")
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	message, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	// status is in the headers of Trailers-Only responses, otherwise trailers are read with the body
+	status := response.Header
+	if status.Get("Grpc-Status") == "" {
+		status = response.Trailer
+	}
+	if code := status.Get("Grpc-Status"); code != "0" {
+		n, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, &StatusError{Code: CodeInternal, Message: "response has no grpc status"}
+		}
+		return nil, &StatusError{Code: n, Message: status.Get("Grpc-Message")}
+	}
+	message, err = readFrame(bytes.NewReader(message), int64(len(message)))
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalResponse(message)
+}
@@ -0,0 +1,52 @@
+package serving
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+	"github.com/stellviaproject/go-ia/nn/layers"
+)
+
+func TestGRPC(t *testing.T) {
+	net := layers.NewSequential(1, layers.NewDense(3, 2))
+	ts := httptest.NewUnstartedServer(NewServer(NetworkPredictor(net), Config{MaxBodyBytes: 1024}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+	client := NewGRPCClient(ts.Client(), ts.URL)
+	x, _ := DecodeTensor(&Tensor{DType: "float32", Shape: []int{2, 3}, Data: []float64{1, 2, 3, 4, 5, 6}})
+	prediction, err := client.Predict(context.Background(), x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := net.Clone().Forward([]float64{4, 5, 6})
+	if s := prediction.Outputs.Shape(); s[0] != 2 || s[1] != 2 {
+		t.Fatalf("Predict failed. Expected outputs of shape (2, 2), but got %v", s)
+	}
+	if v := prediction.Outputs.GetF64At([]int{1, 1}); v != expected[1] {
+		t.Errorf("Predict failed. Expected %v, but got %v", expected[1], v)
+	}
+	var se *StatusError
+	_, err = client.Predict(context.Background(), graph.NewTensor(nil, graph.Float64, graph.NewShape(2, 5)))
+	if !errors.As(err, &se) || se.Code != CodeInvalidArgument {
+		t.Errorf("Predict failed. Expected status %v of wrong dimension, but got %v", CodeInvalidArgument, err)
+	}
+	_, err = client.Predict(context.Background(), graph.NewTensor(nil, graph.Float64, graph.NewShape(100, 3)))
+	if !errors.As(err, &se) || se.Code != CodeResourceExhausted {
+		t.Errorf("Predict failed. Expected status %v of large message, but got %v", CodeResourceExhausted, err)
+	}
+	// errors of predictor that are not caused by inputs
+	failing := httptest.NewUnstartedServer(NewServer(PredictorFunc(func(x *graph.Tensor) (*Prediction, error) {
+		return nil, errors.New("model store is down")
+	}), Config{}))
+	failing.EnableHTTP2 = true
+	failing.StartTLS()
+	defer failing.Close()
+	_, err = NewGRPCClient(failing.Client(), failing.URL).Predict(context.Background(), x)
+	if !errors.As(err, &se) || se.Code != CodeInternal || se.Message != "model store is down" {
+		t.Errorf("Predict failed. Expected status %v of failing predictor, but got %v", CodeInternal, err)
+	}
+}
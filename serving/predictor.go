@@ -0,0 +1,74 @@
+package serving
+
+import (
+	"sync"
+
+	"github.com/stellviaproject/go-ia/model"
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/graph"
+	"github.com/stellviaproject/go-ia/nn/layers"
+)
+
+// Prediction of a model, outputs of networks or labels of estimators
+type Prediction struct {
+	Outputs *graph.Tensor
+	Labels  []any
+}
+
+// Model served by a server, it must be safe for concurrent use
+type Predictor interface {
+	Predict(x *graph.Tensor) (*Prediction, error)
+}
+
+// PredictorFunc is a function used as a Predictor
+type PredictorFunc func(x *graph.Tensor) (*Prediction, error)
+
+func (fn PredictorFunc) Predict(x *graph.Tensor) (*Prediction, error) {
+	return fn(x)
+}
+
+// Predictor of labels of a fitted estimator, x has shape (samples, features)
+func EstimatorPredictor(e model.Estimator) Predictor {
+	return PredictorFunc(func(x *graph.Tensor) (prediction *Prediction, err error) {
+		if x.Shape().Dim() != 2 {
+			return nil, ErrShapeNotValid
+		}
+		// models panic with points of other dimension
+		defer func() {
+			if r := recover(); r != nil {
+				prediction, err = nil, panicError(r)
+			}
+		}()
+		return &Prediction{Labels: e.Predict(x)}, nil
+	})
+}
+
+// Predictor of outputs of a network, x has shape (samples, inputs) and outputs have shape (samples, outputs)
+//
+// Forward keeps state of the last input, so every concurrent request uses its own clone of network
+func NetworkPredictor(net *layers.Sequential) Predictor {
+	pool := sync.Pool{New: func() any {
+		return net.Clone()
+	}}
+	return PredictorFunc(func(x *graph.Tensor) (prediction *Prediction, err error) {
+		if x.Shape().Dim() != 2 {
+			return nil, ErrShapeNotValid
+		}
+		if d, ok := net.Layers()[0].(*layers.Dense); ok && x.Shape()[1] != d.In {
+			return nil, ErrShapeNotValid
+		}
+		clone := pool.Get().(*layers.Sequential)
+		defer pool.Put(clone)
+		defer func() {
+			if r := recover(); r != nil {
+				prediction, err = nil, panicError(r)
+			}
+		}()
+		rows := data.Rows(x)
+		outputs := make([]*graph.Tensor, len(rows))
+		for i, row := range rows {
+			outputs[i] = data.Vector(clone.Forward(row))
+		}
+		return &Prediction{Outputs: data.Stack(outputs)}, nil
+	})
+}
@@ -0,0 +1,245 @@
+package serving
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+	"github.com/stellviaproject/go-ia/telemetry"
+)
+
+var ErrPanic = errors.New("model panicked")
+
+func panicError(r any) error {
+	if err, ok := r.(error); ok {
+		return fmt.Errorf("%w: %v", ErrPanic, err)
+	}
+	return fmt.Errorf("%w: %v", ErrPanic, r)
+}
+
+// Request of POST /predict
+type PredictRequest struct {
+	Inputs *Tensor `json:"inputs"`
+}
+
+// Response of POST /predict, outputs of networks or labels of estimators
+type PredictResponse struct {
+	Outputs *Tensor `json:"outputs,omitempty"`
+	Labels  []any   `json:"labels,omitempty"`
+}
+
+// Response of failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Configuration of server
+type Config struct {
+	MaxBodyBytes  int64 //greatest size of request body, 4 MiB if zero
+	MaxConcurrent int   //predictions run at the same time, other requests wait, unlimited if zero
+	Window        int   //latencies kept to compute quantiles, 1024 if zero
+	// metrics goia_inference_requests_total, goia_inference_errors_total and goia_inference_latency_seconds are
+	// registered and served at GET /metrics if it isn't nil, servers of the same registry share them
+	Registry *telemetry.Registry
+	Logger   telemetry.Logger //logger of failed predictions, none if nil
+}
+
+// Latency metrics of served predictions
+type Stats struct {
+	Requests int64         `json:"requests"`
+	Errors   int64         `json:"errors"`
+	Mean     time.Duration `json:"mean"`
+	P50      time.Duration `json:"p50"`
+	P95      time.Duration `json:"p95"`
+	P99      time.Duration `json:"p99"`
+	Max      time.Duration `json:"max"`
+}
+
+// HTTP server of a predictor
+//
+// POST /predict predicts a PredictRequest, GET /stats returns Stats, GET /metrics returns metrics of the registry of
+// config and GET /healthz returns ok. Requests of gRPC over HTTP/2 are served by the Inference service of
+// proto/inference.proto. Package net/http only speaks HTTP/2 over TLS, without h2c, so gRPC clients need a server
+// started with TLS, like http.Server.ServeTLS or httptest.Server.StartTLS with EnableHTTP2.
+type Server struct {
+	predictor Predictor
+	config    Config
+	slots     chan struct{}
+	mux       *http.ServeMux
+	mu        sync.Mutex
+	requests  int64
+	errors    int64
+	total     time.Duration
+	max       time.Duration
+	latencies []time.Duration //ring of the last latencies
+	next      int
+	// metrics of registry
+	requestsTotal *telemetry.Counter
+	errorsTotal   *telemetry.Counter
+	latency       *telemetry.Histogram
+}
+
+// Create server of predictor
+func NewServer(predictor Predictor, config Config) *Server {
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = 4 << 20
+	}
+	if config.Window <= 0 {
+		config.Window = 1024
+	}
+	s := &Server{predictor: predictor, config: config, mux: http.NewServeMux(),
+		latencies: make([]time.Duration, 0, config.Window)}
+	if config.MaxConcurrent > 0 {
+		s.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	if config.Logger == nil {
+		s.config.Logger = telemetry.NewNopLogger()
+	}
+	if config.Registry != nil {
+		s.requestsTotal = config.Registry.Counter("goia_inference_requests_total", "Served predictions.")
+		s.errorsTotal = config.Registry.Counter("goia_inference_errors_total", "Failed predictions.")
+		s.latency = config.Registry.Histogram("goia_inference_latency_seconds", "Latency of predictions.", nil)
+		s.mux.Handle("/metrics", config.Registry.Handler())
+	}
+	s.mux.HandleFunc("/predict", s.handlePredict)
+	s.mux.HandleFunc("/stats", s.handleStats)
+	s.mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isGRPC(r) {
+		s.serveGRPC(w, r)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// Predict inputs with the predictor, waiting for a free slot if concurrency is limited
+func (s *Server) Predict(inputs *Tensor) (*PredictResponse, error) {
+	x, err := DecodeTensor(inputs)
+	if err != nil {
+		return nil, err
+	}
+	prediction, err := s.predict(x)
+	if err != nil {
+		return nil, err
+	}
+	response := &PredictResponse{Labels: prediction.Labels}
+	if prediction.Outputs != nil {
+		response.Outputs = EncodeTensor(prediction.Outputs)
+	}
+	return response, nil
+}
+
+// prediction of protocols, it observes latency of predictor
+func (s *Server) predict(x *graph.Tensor) (*Prediction, error) {
+	if s.slots != nil {
+		s.slots <- struct{}{}
+		defer func() { <-s.slots }()
+	}
+	start := time.Now()
+	prediction, err := s.predictor.Predict(x)
+	s.observe(time.Since(start), err)
+	return prediction, err
+}
+
+func (s *Server) handlePredict(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "method not allowed"})
+		return
+	}
+	var request PredictRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.config.MaxBodyBytes))
+	if err := decoder.Decode(&request); err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeJSON(w, status, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if request.Inputs == nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "inputs are missing"})
+		return
+	}
+	response, err := s.Predict(request.Inputs)
+	switch {
+	case errors.Is(err, ErrDTypeNotValid) || errors.Is(err, ErrShapeNotValid):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Stats())
+}
+
+func (s *Server) observe(latency time.Duration, err error) {
+	if s.requestsTotal != nil {
+		s.requestsTotal.Inc()
+		s.latency.Observe(latency.Seconds())
+		if err != nil {
+			s.errorsTotal.Inc()
+		}
+	}
+	if err != nil {
+		s.config.Logger.Error("prediction failed", "error", err, "latency", latency)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if err != nil {
+		s.errors++
+	}
+	s.total += latency
+	if latency > s.max {
+		s.max = latency
+	}
+	if len(s.latencies) < s.config.Window {
+		s.latencies = append(s.latencies, latency)
+	} else {
+		s.latencies[s.next] = latency
+		s.next = (s.next + 1) % s.config.Window
+	}
+}
+
+// Latency metrics of predictions, quantiles are computed over the last predictions of the window
+func (s *Server) Stats() Stats {
+	s.mu.Lock()
+	stats := Stats{Requests: s.requests, Errors: s.errors, Max: s.max}
+	if s.requests > 0 {
+		stats.Mean = s.total / time.Duration(s.requests)
+	}
+	sorted := append([]time.Duration(nil), s.latencies...)
+	s.mu.Unlock()
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	quantile := func(q float64) time.Duration {
+		if len(sorted) == 0 {
+			return 0
+		}
+		return sorted[int(q*float64(len(sorted)-1))]
+	}
+	stats.P50, stats.P95, stats.P99 = quantile(0.5), quantile(0.95), quantile(0.99)
+	return stats
+}
@@ -0,0 +1,115 @@
+package serving
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/model"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/telemetry"
+)
+
+func post(t *testing.T, url string, request any) (*http.Response, PredictResponse) {
+	body, _ := json.Marshal(request)
+	resp, err := http.Post(url+"/predict", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var response PredictResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	return resp, response
+}
+
+func TestEstimatorServer(t *testing.T) {
+	data := dataset.MakeBlobs(100, []knn.Point{{0, 0}, {10, 10}}, 1, 1)
+	points := make([]knn.Point, len(data))
+	labels := make([]any, len(data))
+	for i, dp := range data {
+		points[i], labels[i] = dp.Point(), dp.Label()
+	}
+	e := model.NewKNN(3, knn.NewEuclideanDist(), knn.NewMultiClassSelector())
+	e.Fit(model.Tensor(points), labels)
+	registry := telemetry.NewRegistry()
+	var logs strings.Builder
+	server := NewServer(EstimatorPredictor(e), Config{MaxConcurrent: 2, Registry: registry, Logger: telemetry.NewTextLogger(&logs)})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	request := PredictRequest{Inputs: &Tensor{Shape: []int{2, 2}, Data: []float64{0, 0, 10, 10}}}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, response := post(t, ts.URL, request)
+			if resp.StatusCode != http.StatusOK || len(response.Labels) != 2 || response.Labels[0] != 0.0 || response.Labels[1] != 1.0 {
+				t.Errorf("Predict failed. Expected labels [0 1], but got %v with status %v", response.Labels, resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+	if resp, _ := post(t, ts.URL, PredictRequest{Inputs: &Tensor{Shape: []int{1, 3}, Data: []float64{0, 0, 0}}}); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Predict failed. Expected status %v of wrong dimension, but got %v", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+	if resp, _ := post(t, ts.URL, PredictRequest{Inputs: &Tensor{Shape: []int{2}, Data: []float64{0}}}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Predict failed. Expected status %v of wrong shape, but got %v", http.StatusBadRequest, resp.StatusCode)
+	}
+	stats := server.Stats()
+	if stats.Requests != 9 || stats.Errors != 1 || stats.Max < stats.P50 {
+		t.Errorf("Stats failed. Expected 9 requests with 1 error, but got %+v", stats)
+	}
+	if c := registry.Get("goia_inference_errors_total").(*telemetry.Counter).Value(); c != 1 {
+		t.Errorf("Registry failed. Expected 1 error, but got %v", c)
+	}
+	if !strings.Contains(logs.String(), "msg=\"prediction failed\"") {
+		t.Errorf("Logger failed. Expected failed prediction record, but got %q", logs.String())
+	}
+	metrics, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(metrics.Body)
+	metrics.Body.Close()
+	if !strings.Contains(string(body), "goia_inference_requests_total 9") {
+		t.Errorf("Metrics failed. Expected 9 requests, but got %q", body)
+	}
+	resp, err := http.Get(ts.URL + "/predict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Predict failed. Expected status %v, but got %v", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+}
+
+func TestNetworkServer(t *testing.T) {
+	net := layers.NewSequential(1, layers.NewDense(3, 4), layers.NewReLU(), layers.NewDense(4, 2))
+	ts := httptest.NewServer(NewServer(NetworkPredictor(net), Config{MaxBodyBytes: 256}))
+	defer ts.Close()
+	resp, response := post(t, ts.URL, PredictRequest{Inputs: &Tensor{DType: "float32", Shape: []int{2, 3}, Data: []float64{1, 2, 3, 4, 5, 6}}})
+	if resp.StatusCode != http.StatusOK || response.Outputs == nil {
+		t.Fatalf("Predict failed. Expected outputs, but got status %v", resp.StatusCode)
+	}
+	expected := net.Clone().Forward([]float64{4, 5, 6})
+	if s := response.Outputs.Shape; s[0] != 2 || s[1] != 2 || response.Outputs.Data[2] != expected[0] {
+		t.Errorf("Predict failed. Expected second output %v, but got %v", expected, response.Outputs.Data)
+	}
+	large := `{"inputs":{"shape":[1000],"data":[` + strings.Repeat("0,", 999) + `0]}}`
+	r, err := http.Post(ts.URL+"/predict", "application/json", strings.NewReader(large))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Body.Close()
+	if r.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Predict failed. Expected status %v, but got %v", http.StatusRequestEntityTooLarge, r.StatusCode)
+	}
+}
@@ -0,0 +1,112 @@
+// Package serving implements inference servers of models over HTTP with a JSON tensor protocol and over gRPC
+package serving
+
+import (
+	"errors"
+
+	"github.com/stellviaproject/go-ia/float16"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrDTypeNotValid = errors.New("dtype is not float16, float32 or float64")
+	ErrShapeNotValid = errors.New("shape doesn't match number of elements")
+)
+
+// Tensor of JSON protocol, data is stored in row-major order, the last index changes fastest
+type Tensor struct {
+	DType string    `json:"dtype"`
+	Shape []int     `json:"shape"`
+	Data  []float64 `json:"data"`
+}
+
+// Names of tensor types in protocol
+var dtypes = map[graph.Type]string{graph.Float16: "float16", graph.Float32: "float32", graph.Float64: "float64"}
+
+// Type of dtype name
+func ParseDType(name string) (graph.Type, error) {
+	for typ, n := range dtypes {
+		if n == name {
+			return typ, nil
+		}
+	}
+	return 0, ErrDTypeNotValid
+}
+
+// visit indices of shape in row-major order
+func eachIndex(shape graph.Shape, fn func(index []int)) {
+	index := make([]int, len(shape))
+	for n := shape.Len(); n > 0; n-- {
+		fn(index)
+		for d := len(index) - 1; d >= 0; d-- {
+			index[d]++
+			if index[d] < shape[d] {
+				break
+			}
+			index[d] = 0
+		}
+	}
+}
+
+func float(value any) float64 {
+	switch v := value.(type) {
+	case float16.Float16:
+		return v.ToF64()
+	case float32:
+		return float64(v)
+	}
+	return value.(float64)
+}
+
+// Encode tensor in protocol
+func EncodeTensor(t *graph.Tensor) *Tensor {
+	out := &Tensor{DType: dtypes[t.Type()], Shape: t.Shape(), Data: make([]float64, 0, t.Shape().Len())}
+	eachIndex(t.Shape(), func(index []int) {
+		out.Data = append(out.Data, float(t.Get(index)))
+	})
+	return out
+}
+
+// Shape of dims with exactly elements, dims come from clients so their product is checked for overflow before
+// it is compared with elements, and elements is bounded by the size of the request body
+func checkShape(dims []int, elements int) (graph.Shape, error) {
+	if len(dims) == 0 {
+		return nil, ErrShapeNotValid
+	}
+	product := 1
+	for _, n := range dims {
+		if n < 1 || product > elements/n {
+			return nil, ErrShapeNotValid
+		}
+		product *= n
+	}
+	if product != elements {
+		return nil, ErrShapeNotValid
+	}
+	return graph.NewShape(dims...), nil
+}
+
+// Decode tensor of protocol, float64 if dtype is empty
+func DecodeTensor(t *Tensor) (*graph.Tensor, error) {
+	typ := graph.Float64
+	if t.DType != "" {
+		var err error
+		if typ, err = ParseDType(t.DType); err != nil {
+			return nil, err
+		}
+	}
+	shape, err := checkShape(t.Shape, len(t.Data))
+	if err != nil {
+		return nil, err
+	}
+	out := graph.NewTensor(nil, graph.Float64, shape)
+	k := 0
+	eachIndex(shape, func(index []int) {
+		out.SetF64(index, t.Data[k])
+		k++
+	})
+	if typ != graph.Float64 {
+		out = graph.NewTensor(out.F64Slice(), typ, shape)
+	}
+	return out, nil
+}
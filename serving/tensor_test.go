@@ -0,0 +1,39 @@
+package serving
+
+import (
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+func TestTensor(t *testing.T) {
+	in := &Tensor{DType: "float32", Shape: []int{2, 3}, Data: []float64{1, 2, 3, 4, 5, 6}}
+	x, err := DecodeTensor(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Type() != graph.Float32 || x.GetF32At([]int{0, 2}) != 3 || x.GetF32At([]int{1, 0}) != 4 {
+		t.Errorf("DecodeTensor failed. Expected row-major float32 tensor, but got %v", x)
+	}
+	out := EncodeTensor(x)
+	if out.DType != "float32" || len(out.Shape) != 2 {
+		t.Fatalf("EncodeTensor failed. Expected dtype float32 and shape (2, 3), but got %v %v", out.DType, out.Shape)
+	}
+	for i, v := range in.Data {
+		if out.Data[i] != v {
+			t.Fatalf("EncodeTensor failed. Expected %v, but got %v", in.Data, out.Data)
+		}
+	}
+	if _, err := DecodeTensor(&Tensor{DType: "int8", Shape: []int{1}, Data: []float64{1}}); err != ErrDTypeNotValid {
+		t.Errorf("DecodeTensor failed. Expected %v, but got %v", ErrDTypeNotValid, err)
+	}
+	if _, err := DecodeTensor(&Tensor{Shape: []int{2, 2}, Data: []float64{1}}); err != ErrShapeNotValid {
+		t.Errorf("DecodeTensor failed. Expected %v, but got %v", ErrShapeNotValid, err)
+	}
+	// product of dims overflows to zero
+	for _, shape := range [][]int{{1 << 16, 1 << 16, 1 << 16, 1 << 16}, {1 << 16, 1 << 16, 1 << 16, 1 << 16, 0}, {-1, -1}} {
+		if _, err := DecodeTensor(&Tensor{DType: "float64", Shape: shape}); err != ErrShapeNotValid {
+			t.Errorf("DecodeTensor failed. Expected %v with shape %v, but got %v", ErrShapeNotValid, shape, err)
+		}
+	}
+}
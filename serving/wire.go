@@ -0,0 +1,260 @@
+package serving
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/stellviaproject/go-ia/float16"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Messages of proto/inference.proto encoded in protobuf wire format without generated code
+
+var ErrWireNotValid = errors.New("protobuf message is not valid")
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func appendTag(b []byte, field, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field<<3|wire))
+}
+
+func appendBytes(b []byte, field int, value []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+// visit fields of message, value is the varint or the fixed value or the bytes of field
+func eachField(b []byte, fn func(field, wire int, varint uint64, bytes []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrWireNotValid
+		}
+		b = b[n:]
+		field, wire := int(tag>>3), int(tag&7)
+		var varint uint64
+		var bytes []byte
+		switch wire {
+		case wireVarint:
+			if varint, n = binary.Uvarint(b); n <= 0 {
+				return ErrWireNotValid
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return ErrWireNotValid
+			}
+			varint, b = binary.LittleEndian.Uint64(b), b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return ErrWireNotValid
+			}
+			varint, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return ErrWireNotValid
+			}
+			bytes, b = b[n:n+int(size)], b[n+int(size):]
+		default:
+			return ErrWireNotValid
+		}
+		if err := fn(field, wire, varint, bytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// size of element of type in raw bytes
+var elementSize = map[graph.Type]int{graph.Float16: 2, graph.Float32: 4, graph.Float64: 8}
+
+// Encode tensor message
+func marshalTensor(t *graph.Tensor) []byte {
+	var b []byte
+	b = appendBytes(b, 1, []byte(dtypes[t.Type()]))
+	var shape []byte
+	for _, n := range t.Shape() {
+		shape = binary.AppendUvarint(shape, uint64(n))
+	}
+	b = appendBytes(b, 2, shape)
+	size := elementSize[t.Type()]
+	data := make([]byte, 0, size*t.Shape().Len())
+	eachIndex(t.Shape(), func(index []int) {
+		switch v := t.Get(index).(type) {
+		case float16.Float16:
+			data = binary.LittleEndian.AppendUint16(data, uint16(v))
+		case float32:
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+		case float64:
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+		}
+	})
+	return appendBytes(b, 3, data)
+}
+
+// Decode tensor message
+func unmarshalTensor(b []byte) (*graph.Tensor, error) {
+	var dtype string
+	var shape graph.Shape
+	var data []byte
+	err := eachField(b, func(field, wire int, varint uint64, bytes []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			dtype = string(bytes)
+		case field == 2 && wire == wireBytes:
+			// packed repeated shape
+			for len(bytes) > 0 {
+				n, k := binary.Uvarint(bytes)
+				if k <= 0 {
+					return ErrWireNotValid
+				}
+				shape, bytes = append(shape, int(n)), bytes[k:]
+			}
+		case field == 2 && wire == wireVarint:
+			shape = append(shape, int(varint))
+		case field == 3 && wire == wireBytes:
+			data = bytes
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	typ, err := ParseDType(dtype)
+	if err != nil {
+		return nil, err
+	}
+	size := elementSize[typ]
+	if len(data)%size != 0 {
+		return nil, ErrShapeNotValid
+	}
+	if shape, err = checkShape(shape, len(data)/size); err != nil {
+		return nil, err
+	}
+	t := graph.NewTensor(nil, typ, shape)
+	k := 0
+	eachIndex(shape, func(index []int) {
+		raw := data[k*size : (k+1)*size]
+		switch typ {
+		case graph.Float16:
+			t.SetF16(index, float16.Float16(binary.LittleEndian.Uint16(raw)))
+		case graph.Float32:
+			t.SetF32(index, math.Float32frombits(binary.LittleEndian.Uint32(raw)))
+		default:
+			t.SetF64(index, math.Float64frombits(binary.LittleEndian.Uint64(raw)))
+		}
+		k++
+	})
+	return t, nil
+}
+
+// Encode label message, labels that are not strings, numbers or booleans are encoded as text
+func marshalLabel(label any) []byte {
+	var b []byte
+	switch v := label.(type) {
+	case string:
+		b = appendBytes(b, 1, []byte(v))
+	case float64:
+		b = appendTag(b, 2, wireFixed64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	case float32:
+		b = appendTag(b, 2, wireFixed64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(float64(v)))
+	case int:
+		b = appendTag(b, 3, wireVarint)
+		b = binary.AppendUvarint(b, uint64(v))
+	case int64:
+		b = appendTag(b, 3, wireVarint)
+		b = binary.AppendUvarint(b, uint64(v))
+	case bool:
+		b = appendTag(b, 4, wireVarint)
+		if v {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+	default:
+		b = appendBytes(b, 1, []byte(fmt.Sprint(v)))
+	}
+	return b
+}
+
+// Decode label message, integers are decoded as int
+func unmarshalLabel(b []byte) (any, error) {
+	var label any
+	err := eachField(b, func(field, wire int, varint uint64, bytes []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			label = string(bytes)
+		case field == 2 && wire == wireFixed64:
+			label = math.Float64frombits(varint)
+		case field == 3 && wire == wireVarint:
+			label = int(int64(varint))
+		case field == 4 && wire == wireVarint:
+			label = varint != 0
+		}
+		return nil
+	})
+	return label, err
+}
+
+// Encode PredictRequest message
+func marshalRequest(inputs *graph.Tensor) []byte {
+	return appendBytes(nil, 1, marshalTensor(inputs))
+}
+
+// Decode PredictRequest message
+func unmarshalRequest(b []byte) (*graph.Tensor, error) {
+	var inputs *graph.Tensor
+	err := eachField(b, func(field, wire int, varint uint64, bytes []byte) error {
+		if field == 1 && wire == wireBytes {
+			var err error
+			inputs, err = unmarshalTensor(bytes)
+			return err
+		}
+		return nil
+	})
+	if err == nil && inputs == nil {
+		err = ErrShapeNotValid
+	}
+	return inputs, err
+}
+
+// Encode PredictResponse message
+func marshalResponse(prediction *Prediction) []byte {
+	var b []byte
+	if prediction.Outputs != nil {
+		b = appendBytes(b, 1, marshalTensor(prediction.Outputs))
+	}
+	for _, label := range prediction.Labels {
+		b = appendBytes(b, 2, marshalLabel(label))
+	}
+	return b
+}
+
+// Decode PredictResponse message
+func unmarshalResponse(b []byte) (*Prediction, error) {
+	prediction := &Prediction{}
+	err := eachField(b, func(field, wire int, varint uint64, bytes []byte) error {
+		var err error
+		switch {
+		case field == 1 && wire == wireBytes:
+			prediction.Outputs, err = unmarshalTensor(bytes)
+		case field == 2 && wire == wireBytes:
+			var label any
+			label, err = unmarshalLabel(bytes)
+			prediction.Labels = append(prediction.Labels, label)
+		}
+		return err
+	})
+	return prediction, err
+}
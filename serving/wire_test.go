@@ -0,0 +1,51 @@
+package serving
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+func TestWire(t *testing.T) {
+	for _, typ := range []graph.Type{graph.Float16, graph.Float32, graph.Float64} {
+		x := graph.NewTensor([]float64{1, -2, 0.5, 4, 5, 6}, typ, graph.NewShape(3, 2))
+		y, err := unmarshalTensor(marshalTensor(x))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if y.Type() != typ || !y.Shape().Equal(x.Shape()) {
+			t.Fatalf("Wire failed. Expected tensor of type %v and shape %v, but got %v %v", typ, x.Shape(), y.Type(), y.Shape())
+		}
+		a, b := EncodeTensor(x).Data, EncodeTensor(y).Data
+		for i := range a {
+			if a[i] != b[i] {
+				t.Errorf("Wire failed. Expected %v, but got %v", a, b)
+				break
+			}
+		}
+	}
+	labels := []any{"cat", 2.5, 7, true, -3}
+	prediction, err := unmarshalResponse(marshalResponse(&Prediction{Labels: labels}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range labels {
+		if prediction.Labels[i] != labels[i] {
+			t.Errorf("Wire failed. Expected labels %v, but got %v", labels, prediction.Labels)
+			break
+		}
+	}
+	if _, err := unmarshalRequest([]byte{0x0a, 0x05, 0x01}); err != ErrWireNotValid {
+		t.Errorf("Wire failed. Expected %v, but got %v", ErrWireNotValid, err)
+	}
+	// product of dims overflows to zero
+	var dims []byte
+	for i := 0; i < 4; i++ {
+		dims = binary.AppendUvarint(dims, 1<<16)
+	}
+	huge := appendBytes(appendBytes(nil, 1, []byte("float64")), 2, dims)
+	if _, err := unmarshalTensor(huge); err != ErrShapeNotValid {
+		t.Errorf("Wire failed. Expected %v, but got %v", ErrShapeNotValid, err)
+	}
+}
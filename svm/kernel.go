@@ -0,0 +1,71 @@
+// Package svm implements support vector machines for binary classification
+package svm
+
+import (
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// Kernel is an inner product in a feature space
+type Kernel interface {
+	Eval(p1, p2 knn.Point) float64
+}
+
+func dot(p1, p2 knn.Point) float64 {
+	if len(p1) != len(p2) {
+		panic(knn.ErrPointDimensionMismatch)
+	}
+	sum := 0.0
+	for i, v := range p1 {
+		sum += v * p2[i]
+	}
+	return sum
+}
+
+type linearKernel struct{}
+
+// Create kernel x . y
+func NewLinearKernel() Kernel {
+	return linearKernel{}
+}
+
+func (linearKernel) Eval(p1, p2 knn.Point) float64 {
+	return dot(p1, p2)
+}
+
+type rbfKernel struct {
+	gamma float64
+}
+
+// Create gaussian kernel exp(-gamma * |x - y|^2)
+func NewRBFKernel(gamma float64) Kernel {
+	return rbfKernel{gamma: gamma}
+}
+
+func (k rbfKernel) Eval(p1, p2 knn.Point) float64 {
+	if len(p1) != len(p2) {
+		panic(knn.ErrPointDimensionMismatch)
+	}
+	sum := 0.0
+	for i, v := range p1 {
+		dif := v - p2[i]
+		sum += dif * dif
+	}
+	return math.Exp(-k.gamma * sum)
+}
+
+type polyKernel struct {
+	degree int
+	gamma  float64
+	coef0  float64
+}
+
+// Create polynomial kernel (gamma * x . y + coef0)^degree
+func NewPolyKernel(degree int, gamma, coef0 float64) Kernel {
+	return polyKernel{degree: degree, gamma: gamma, coef0: coef0}
+}
+
+func (k polyKernel) Eval(p1, p2 knn.Point) float64 {
+	return math.Pow(k.gamma*dot(p1, p2)+k.coef0, float64(k.degree))
+}
@@ -0,0 +1,352 @@
+package svm
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var (
+	ErrNotFitted         = errors.New("model is not fitted")
+	ErrEmptyData         = errors.New("there are no data points to fit")
+	ErrNotBinary         = errors.New("labels of binary classification are not two classes")
+	ErrDimensionMismatch = errors.New("point dimension doesn't match fitted dimension")
+	ErrParamNotValid     = errors.New("regularization parameter is not greater than zero")
+)
+
+// first class is negative and second class is positive, in order of appearance
+func binaryTargets(data []knn.DataPoint) ([2]any, []float64) {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	var classes [2]any
+	count := 0
+	y := make([]float64, len(data))
+	dim := len(data[0].Point())
+	for i, dp := range data {
+		if len(dp.Point()) != dim {
+			panic(ErrDimensionMismatch)
+		}
+		if count == 0 || count == 1 && dp.Label() != classes[0] {
+			classes[count] = dp.Label()
+			count++
+		}
+		switch dp.Label() {
+		case classes[0]:
+			y[i] = -1
+		case classes[1]:
+			y[i] = 1
+		default:
+			panic(ErrNotBinary)
+		}
+	}
+	if count != 2 {
+		panic(ErrNotBinary)
+	}
+	return classes, y
+}
+
+// Linear SVM trained by stochastic subgradient descent of the hinge loss (Pegasos)
+type LinearSVM struct {
+	lambda  float64
+	epochs  int
+	seed    int64
+	classes [2]any
+	weights []float64
+	bias    float64
+}
+
+// Create linear SVM minimizing lambda/2 * |w|^2 + mean hinge loss, epochs is 10 if zero
+func NewLinearSVM(lambda float64, epochs int, seed int64) *LinearSVM {
+	if lambda <= 0 {
+		panic(ErrParamNotValid)
+	}
+	if epochs <= 0 {
+		epochs = 10
+	}
+	return &LinearSVM{lambda: lambda, epochs: epochs, seed: seed}
+}
+
+// Fit weights with one data point by step in shuffled order
+//
+// Steps are 1 / (lambda * (t + 1/lambda)), so the unregularized bias doesn't take huge first steps,
+// and the iterates of the last half of epochs are averaged, which removes most of the noise of the steps
+func (svm *LinearSVM) Fit(data []knn.DataPoint) {
+	classes, y := binaryTargets(data)
+	svm.classes = classes
+	dim := len(data[0].Point())
+	weights := make([]float64, dim)
+	bias := 0.0
+	svm.weights = make([]float64, dim)
+	svm.bias = 0
+	averaged := 0
+	rnd := rand.New(rand.NewSource(svm.seed))
+	t := 1 / svm.lambda
+	for epoch := 0; epoch < svm.epochs; epoch++ {
+		for _, i := range rnd.Perm(len(data)) {
+			t++
+			eta := 1 / (svm.lambda * t)
+			x := data[i].Point()
+			margin := y[i] * (dot(weights, x) + bias)
+			scale := 1 - eta*svm.lambda
+			for d := range weights {
+				weights[d] *= scale
+			}
+			if margin < 1 {
+				for d, v := range x {
+					weights[d] += eta * y[i] * v
+				}
+				bias += eta * y[i]
+			}
+			if 2*epoch >= svm.epochs {
+				averaged++
+				for d, w := range weights {
+					svm.weights[d] += (w - svm.weights[d]) / float64(averaged)
+				}
+				svm.bias += (bias - svm.bias) / float64(averaged)
+			}
+		}
+	}
+}
+
+func (svm *LinearSVM) check(point knn.Point) {
+	if svm.weights == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != len(svm.weights) {
+		panic(ErrDimensionMismatch)
+	}
+}
+
+// Signed distance score of point, positive for the second class
+func (svm *LinearSVM) DecisionFunction(point knn.Point) float64 {
+	svm.check(point)
+	return dot(svm.weights, point) + svm.bias
+}
+
+// Predict label of point
+func (svm *LinearSVM) Predict(point knn.Point) any {
+	if svm.DecisionFunction(point) > 0 {
+		return svm.classes[1]
+	}
+	return svm.classes[0]
+}
+
+// Weights of features
+func (svm *LinearSVM) Coef() []float64 {
+	return append([]float64(nil), svm.weights...)
+}
+
+// Bias of decision function
+func (svm *LinearSVM) Intercept() float64 {
+	return svm.bias
+}
+
+// Kernel SVM trained by sequential minimal optimization with maximal violating pairs
+type KernelSVM struct {
+	c       float64
+	kernel  Kernel
+	tol     float64
+	maxIter int
+	classes [2]any
+	support []knn.Point
+	coef    []float64 //alpha * y of support vectors
+	rho     float64
+	dim     int
+}
+
+// Create kernel SVM with box constraint c, tol is 1e-3 and maxIter is 100000 if zero
+func NewKernelSVM(c float64, kernel Kernel, tol float64, maxIter int) *KernelSVM {
+	if c <= 0 {
+		panic(ErrParamNotValid)
+	}
+	if tol <= 0 {
+		tol = 1e-3
+	}
+	if maxIter <= 0 {
+		maxIter = 100000
+	}
+	return &KernelSVM{c: c, kernel: kernel, tol: tol, maxIter: maxIter}
+}
+
+// Fit dual coefficients of support vectors
+func (svm *KernelSVM) Fit(data []knn.DataPoint) {
+	classes, y := binaryTargets(data)
+	svm.classes = classes
+	svm.dim = len(data[0].Point())
+	n := len(data)
+	// rows of Q = y_i y_j K(x_i, x_j) computed when they are needed
+	rows := make([][]float64, n)
+	row := func(i int) []float64 {
+		if rows[i] == nil {
+			rows[i] = make([]float64, n)
+			for j := range rows[i] {
+				rows[i][j] = y[i] * y[j] * svm.kernel.Eval(data[i].Point(), data[j].Point())
+			}
+		}
+		return rows[i]
+	}
+	diag := make([]float64, n)
+	for i := range diag {
+		diag[i] = svm.kernel.Eval(data[i].Point(), data[i].Point())
+	}
+	alpha := make([]float64, n)
+	grad := make([]float64, n) //gradient of the dual objective Q alpha - 1
+	for i := range grad {
+		grad[i] = -1
+	}
+	up := func(t int) bool {
+		return y[t] > 0 && alpha[t] < svm.c || y[t] < 0 && alpha[t] > 0
+	}
+	low := func(t int) bool {
+		return y[t] < 0 && alpha[t] < svm.c || y[t] > 0 && alpha[t] > 0
+	}
+	for iter := 0; iter < svm.maxIter; iter++ {
+		i, j := -1, -1
+		gmax, gmin := math.Inf(-1), math.Inf(1)
+		for t := 0; t < n; t++ {
+			v := -y[t] * grad[t]
+			if up(t) && v > gmax {
+				i, gmax = t, v
+			}
+			if low(t) && v < gmin {
+				j, gmin = t, v
+			}
+		}
+		if i == -1 || j == -1 || gmax-gmin < svm.tol {
+			break
+		}
+		qi, qj := row(i), row(j)
+		oldI, oldJ := alpha[i], alpha[j]
+		if y[i] != y[j] {
+			quad := diag[i] + diag[j] + 2*qi[j]
+			if quad <= 0 {
+				quad = 1e-12
+			}
+			delta := (-grad[i] - grad[j]) / quad
+			dif := alpha[i] - alpha[j]
+			alpha[i] += delta
+			alpha[j] += delta
+			if dif > 0 {
+				if alpha[j] < 0 {
+					alpha[j], alpha[i] = 0, dif
+				}
+			} else if alpha[i] < 0 {
+				alpha[i], alpha[j] = 0, -dif
+			}
+			if dif > 0 {
+				if alpha[i] > svm.c {
+					alpha[i], alpha[j] = svm.c, svm.c-dif
+				}
+			} else if alpha[j] > svm.c {
+				alpha[j], alpha[i] = svm.c, svm.c+dif
+			}
+		} else {
+			quad := diag[i] + diag[j] - 2*qi[j]
+			if quad <= 0 {
+				quad = 1e-12
+			}
+			delta := (grad[i] - grad[j]) / quad
+			sum := alpha[i] + alpha[j]
+			alpha[i] -= delta
+			alpha[j] += delta
+			if sum > svm.c {
+				if alpha[i] > svm.c {
+					alpha[i], alpha[j] = svm.c, sum-svm.c
+				}
+				if alpha[j] > svm.c {
+					alpha[j], alpha[i] = svm.c, sum-svm.c
+				}
+			} else {
+				if alpha[j] < 0 {
+					alpha[j], alpha[i] = 0, sum
+				}
+				if alpha[i] < 0 {
+					alpha[i], alpha[j] = 0, sum
+				}
+			}
+		}
+		di, dj := alpha[i]-oldI, alpha[j]-oldJ
+		for t := range grad {
+			grad[t] += qi[t]*di + qj[t]*dj
+		}
+	}
+	// bias from free support vectors, or middle of the feasible interval
+	ub, lb := math.Inf(1), math.Inf(-1)
+	sumFree, free := 0.0, 0
+	for t := range alpha {
+		yg := y[t] * grad[t]
+		switch {
+		case alpha[t] >= svm.c:
+			if y[t] < 0 {
+				ub = math.Min(ub, yg)
+			} else {
+				lb = math.Max(lb, yg)
+			}
+		case alpha[t] <= 0:
+			if y[t] > 0 {
+				ub = math.Min(ub, yg)
+			} else {
+				lb = math.Max(lb, yg)
+			}
+		default:
+			free++
+			sumFree += yg
+		}
+	}
+	if free > 0 {
+		svm.rho = sumFree / float64(free)
+	} else {
+		svm.rho = (ub + lb) / 2
+	}
+	svm.support, svm.coef = nil, nil
+	for t, a := range alpha {
+		if a > 0 {
+			svm.support = append(svm.support, data[t].Point())
+			svm.coef = append(svm.coef, a*y[t])
+		}
+	}
+	if svm.coef == nil {
+		svm.coef = []float64{}
+	}
+}
+
+// Score of point, positive for the second class
+func (svm *KernelSVM) DecisionFunction(point knn.Point) float64 {
+	if svm.coef == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != svm.dim {
+		panic(ErrDimensionMismatch)
+	}
+	sum := -svm.rho
+	for i, sv := range svm.support {
+		sum += svm.coef[i] * svm.kernel.Eval(sv, point)
+	}
+	return sum
+}
+
+// Predict label of point
+func (svm *KernelSVM) Predict(point knn.Point) any {
+	if svm.DecisionFunction(point) > 0 {
+		return svm.classes[1]
+	}
+	return svm.classes[0]
+}
+
+// Support vectors, data points with nonzero dual coefficient
+func (svm *KernelSVM) SupportVectors() []knn.Point {
+	return svm.support
+}
+
+// Dual coefficients alpha * y of support vectors, y is 1 for the second class and -1 for the first one
+func (svm *KernelSVM) DualCoef() []float64 {
+	return svm.coef
+}
+
+// Intercept of decision function
+func (svm *KernelSVM) Intercept() float64 {
+	return -svm.rho
+}
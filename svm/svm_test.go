@@ -0,0 +1,71 @@
+package svm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/metrics"
+)
+
+func accuracy(model interface{ Predict(knn.Point) any }, data []knn.DataPoint) float64 {
+	expected := make([]any, len(data))
+	predicted := make([]any, len(data))
+	for i, dp := range data {
+		expected[i] = dp.Label()
+		predicted[i] = model.Predict(dp.Point())
+	}
+	return metrics.Accuracy(expected, predicted)
+}
+
+func TestLinearSVM(t *testing.T) {
+	data := dataset.MakeBlobs(400, []knn.Point{{-2, -2}, {2, 2}}, 1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.25, true, 1)
+	svm := NewLinearSVM(0.01, 20, 1)
+	svm.Fit(train)
+	if acc := accuracy(svm, test); acc < 0.95 {
+		t.Errorf("LinearSVM failed. Expected accuracy greater than 0.95, but got %v", acc)
+	}
+}
+
+func TestKernelSVMSeparable(t *testing.T) {
+	// hard margin of two points is the bisector, both are support vectors
+	data := []knn.DataPoint{
+		knn.NewDataPoint("a", knn.Point{0, 0}),
+		knn.NewDataPoint("b", knn.Point{2, 2}),
+		knn.NewDataPoint("a", knn.Point{-1, -1}),
+		knn.NewDataPoint("b", knn.Point{3, 4}),
+	}
+	svm := NewKernelSVM(1000, NewLinearKernel(), 1e-6, 0)
+	svm.Fit(data)
+	if len(svm.SupportVectors()) != 2 {
+		t.Errorf("SupportVectors failed. Expected 2, but got %v", svm.SupportVectors())
+	}
+	for _, p := range []knn.Point{{0, 0}, {2, 2}} {
+		if got := math.Abs(svm.DecisionFunction(p)); math.Abs(got-1) > 1e-4 {
+			t.Errorf("DecisionFunction failed. Expected margin 1 at %v, but got %v", p, got)
+		}
+	}
+	if got := svm.Predict(knn.Point{0.9, 0.9}); got != "a" {
+		t.Errorf("Predict failed. Expected a, but got %v", got)
+	}
+}
+
+func TestKernelSVM(t *testing.T) {
+	data := dataset.MakeCircles(300, 0.05, 0.5, 1)
+	train, test := dataset.SplitTrainTest(data, 0.25, true, 1)
+	rbf := NewKernelSVM(10, NewRBFKernel(2), 0, 0)
+	rbf.Fit(train)
+	if acc := accuracy(rbf, test); acc < 0.95 {
+		t.Errorf("RBF failed. Expected accuracy greater than 0.95, but got %v", acc)
+	}
+	if n := len(rbf.SupportVectors()); n == 0 || n == len(train) {
+		t.Errorf("SupportVectors failed. Unexpected count %d", n)
+	}
+	poly := NewKernelSVM(10, NewPolyKernel(2, 1, 1), 0, 0)
+	poly.Fit(train)
+	if acc := accuracy(poly, test); acc < 0.95 {
+		t.Errorf("Poly failed. Expected accuracy greater than 0.95, but got %v", acc)
+	}
+}
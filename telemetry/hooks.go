@@ -0,0 +1,40 @@
+package telemetry
+
+import (
+	"time"
+
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+// Callback of Trainer.OnEpoch that sets the gauge goia_training_loss, counts epochs in goia_training_epochs_total,
+// observes durations of epochs in goia_training_epoch_seconds and logs every epoch, registry or logger may be nil
+//
+// Callbacks of the same registry share metrics. The first epoch is timed from the creation of the callback, so it
+// must be created just before Fit
+func TrainingCallback(registry *Registry, logger Logger) train.Callback {
+	if logger == nil {
+		logger = NewNopLogger()
+	}
+	var loss *Gauge
+	var epochs *Counter
+	var durations *Histogram
+	if registry != nil {
+		loss = registry.Gauge("goia_training_loss", "Mean loss of the last training epoch.")
+		epochs = registry.Counter("goia_training_epochs_total", "Finished training epochs.")
+		durations = registry.Histogram("goia_training_epoch_seconds", "Duration of training epochs.",
+			[]float64{0.01, 0.1, 1, 10, 60, 600, 3600})
+	}
+	last := time.Now()
+	return func(epoch int, l float64) bool {
+		now := time.Now()
+		elapsed := now.Sub(last)
+		last = now
+		if registry != nil {
+			loss.Set(l)
+			epochs.Inc()
+			durations.Observe(elapsed.Seconds())
+		}
+		logger.Info("epoch finished", "epoch", epoch, "loss", l, "duration", elapsed)
+		return true
+	}
+}
@@ -0,0 +1,42 @@
+package telemetry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/layers"
+	"github.com/stellviaproject/go-ia/nn/optim"
+	"github.com/stellviaproject/go-ia/nn/train"
+)
+
+func TestTrainingCallback(t *testing.T) {
+	inputs := [][]float64{{0, 1}, {1, 0}, {0, 2}, {2, 0}}
+	targets := [][]float64{{0}, {1}, {0}, {1}}
+	trainer := train.NewTrainer(layers.NewSequential(1, layers.NewDense(2, 2)), optim.NewAdam(0.1), train.CrossEntropy)
+	registry := NewRegistry()
+	var b strings.Builder
+	trainer.OnEpoch(TrainingCallback(registry, NewTextLogger(&b)))
+	history, err := trainer.Fit(data.NewDataLoader(data.NewSliceDataset(inputs, targets), data.LoaderConfig{BatchSize: 2}), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := registry.Get("goia_training_loss").(*Gauge).Value(); v != history[2] {
+		t.Errorf("TrainingCallback failed. Expected loss %v, but got %v", history[2], v)
+	}
+	if v := registry.Get("goia_training_epochs_total").(*Counter).Value(); v != 3 {
+		t.Errorf("TrainingCallback failed. Expected 3 epochs, but got %v", v)
+	}
+	if n := strings.Count(b.String(), "msg=\"epoch finished\""); n != 3 {
+		t.Errorf("TrainingCallback failed. Expected 3 log records, but got %v", n)
+	}
+	// a second callback of the registry shares its metrics
+	second := train.NewTrainer(layers.NewSequential(1, layers.NewDense(2, 2)), optim.NewAdam(0.1), train.CrossEntropy)
+	second.OnEpoch(TrainingCallback(registry, nil))
+	if _, err := second.Fit(data.NewDataLoader(data.NewSliceDataset(inputs, targets), data.LoaderConfig{BatchSize: 2}), 1); err != nil {
+		t.Fatal(err)
+	}
+	if v := registry.Get("goia_training_epochs_total").(*Counter).Value(); v != 4 {
+		t.Errorf("TrainingCallback failed. Expected 4 epochs, but got %v", v)
+	}
+}
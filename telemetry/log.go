@@ -0,0 +1,79 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Structured logger with alternating keys and values in args, *slog.Logger satisfies it and other loggers,
+// like zap sugared loggers, are adapted with a small wrapper
+type Logger interface {
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+type nopLogger struct{}
+
+// Logger that discards every record
+func NewNopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Info(msg string, args ...any)  {}
+func (nopLogger) Error(msg string, args ...any) {}
+
+type textLogger struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// Logger writing one line of key=value pairs by record, like time=... level=INFO msg=... key=value
+func NewTextLogger(w io.Writer) Logger {
+	return &textLogger{w: w, now: time.Now}
+}
+
+func (tl *textLogger) Info(msg string, args ...any) {
+	tl.log("INFO", msg, args)
+}
+
+func (tl *textLogger) Error(msg string, args ...any) {
+	tl.log("ERROR", msg, args)
+}
+
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\n\t") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+func (tl *textLogger) log(level, msg string, args []any) {
+	var b strings.Builder
+	b.WriteString("time=")
+	b.WriteString(tl.now().Format(time.RFC3339Nano))
+	b.WriteString(" level=")
+	b.WriteString(level)
+	b.WriteString(" msg=")
+	b.WriteString(quote(msg))
+	for i := 0; i < len(args); i += 2 {
+		b.WriteByte(' ')
+		if i+1 == len(args) {
+			// a key without value is written as a bad key, like slog does
+			b.WriteString("!BADKEY=")
+			b.WriteString(quote(fmt.Sprint(args[i])))
+			break
+		}
+		b.WriteString(quote(fmt.Sprint(args[i])))
+		b.WriteByte('=')
+		b.WriteString(quote(fmt.Sprint(args[i+1])))
+	}
+	b.WriteByte('\n')
+	tl.mu.Lock()
+	io.WriteString(tl.w, b.String())
+	tl.mu.Unlock()
+}
@@ -0,0 +1,22 @@
+package telemetry
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextLogger(t *testing.T) {
+	var b strings.Builder
+	logger := NewTextLogger(&b).(*textLogger)
+	logger.now = func() time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	logger.Info("epoch finished", "epoch", 3, "loss", 0.5)
+	logger.Error("failed", "error", "bad input", "dangling")
+	expected := "time=2024-01-02T03:04:05Z level=INFO msg=\"epoch finished\" epoch=3 loss=0.5\n" +
+		"time=2024-01-02T03:04:05Z level=ERROR msg=failed error=\"bad input\" !BADKEY=dangling\n"
+	if b.String() != expected {
+		t.Errorf("TextLogger failed. Expected:\n%v\nbut got:\n%v", expected, b.String())
+	}
+}
@@ -0,0 +1,303 @@
+// Package telemetry implements metrics in the Prometheus text format and hooks of structured loggers for
+// training and serving
+package telemetry
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNameNotValid    = errors.New("metric name is not valid")
+	ErrNameRegistered  = errors.New("metric name is already registered")
+	ErrBucketsNotValid = errors.New("histogram buckets are not increasing")
+)
+
+// Default buckets of latency histograms in seconds
+var DefaultBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metric written in the Prometheus text format
+type Metric interface {
+	Name() string
+	write(w *bufio.Writer)
+}
+
+type meta struct {
+	name, help string
+}
+
+func (m meta) Name() string {
+	return m.name
+}
+
+// escaper of backslashes and line feeds of help text
+var helpEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+
+func (m meta) header(w *bufio.Writer, typ string) {
+	if m.help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", m.name, helpEscaper.Replace(m.help))
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, typ)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Counter of events, it only increases
+type Counter struct {
+	meta
+	mu    sync.Mutex
+	value float64
+}
+
+// Increase counter by one
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Increase counter by delta, negative deltas are ignored
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	c.value += delta
+	c.mu.Unlock()
+}
+
+// Value of counter
+func (c *Counter) Value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.header(w, "counter")
+	fmt.Fprintf(w, "%s %s\n", c.name, formatFloat(c.Value()))
+}
+
+// Gauge of a value that goes up and down, like the loss of training
+type Gauge struct {
+	meta
+	mu    sync.Mutex
+	value float64
+}
+
+// Set value of gauge
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	g.value = value
+	g.mu.Unlock()
+}
+
+// Value of gauge
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.header(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.Value()))
+}
+
+// Histogram of observed values in cumulative buckets, like latencies
+type Histogram struct {
+	meta
+	mu      sync.Mutex
+	buckets []float64 //upper bounds
+	counts  []uint64  //observations of every bucket, the last one is +Inf
+	count   uint64
+	sum     float64
+}
+
+// Observe value
+func (h *Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.buckets, value)
+	h.mu.Lock()
+	h.counts[i]++
+	h.count++
+	h.sum += value
+	h.mu.Unlock()
+}
+
+// Number and sum of observed values
+func (h *Histogram) Count() (uint64, float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count, h.sum
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	counts := append([]uint64(nil), h.counts...)
+	count, sum := h.count, h.sum
+	h.mu.Unlock()
+	h.header(w, "histogram")
+	cumulative := uint64(0)
+	for i, bound := range append(append([]float64(nil), h.buckets...), math.Inf(1)) {
+		cumulative += counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.name, formatFloat(bound), cumulative)
+	}
+	fmt.Fprintf(w, "%s_sum %s\n", h.name, formatFloat(sum))
+	fmt.Fprintf(w, "%s_count %d\n", h.name, count)
+}
+
+// Registry of metrics written in order of name
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]Metric
+}
+
+// Create empty registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]Metric)}
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		letter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r == ':'
+		if !letter && !(i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Registry) register(m Metric) {
+	if r.getOrRegister(m) != m {
+		panic(ErrNameRegistered)
+	}
+}
+
+// registered metric with the name of m, m is registered if there isn't one
+func (r *Registry) getOrRegister(m Metric) Metric {
+	if !validName(m.Name()) {
+		panic(ErrNameNotValid)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.metrics[m.Name()]; ok {
+		return old
+	}
+	r.metrics[m.Name()] = m
+	return m
+}
+
+// Create and register counter
+func (r *Registry) NewCounter(name, help string) *Counter {
+	c := &Counter{meta: meta{name: name, help: help}}
+	r.register(c)
+	return c
+}
+
+// Create and register gauge
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	g := &Gauge{meta: meta{name: name, help: help}}
+	r.register(g)
+	return g
+}
+
+// Counter of name, it is created and registered if there isn't one
+//
+// panics with ErrNameRegistered if name is registered by a metric of other type
+func (r *Registry) Counter(name, help string) *Counter {
+	c, ok := r.getOrRegister(&Counter{meta: meta{name: name, help: help}}).(*Counter)
+	if !ok {
+		panic(ErrNameRegistered)
+	}
+	return c
+}
+
+// Gauge of name, it is created and registered if there isn't one, see Counter
+func (r *Registry) Gauge(name, help string) *Gauge {
+	g, ok := r.getOrRegister(&Gauge{meta: meta{name: name, help: help}}).(*Gauge)
+	if !ok {
+		panic(ErrNameRegistered)
+	}
+	return g
+}
+
+// Histogram of name, it is created and registered if there isn't one, see Counter
+//
+// buckets of an existing histogram are kept
+func (r *Registry) Histogram(name, help string, buckets []float64) *Histogram {
+	h, ok := r.getOrRegister(newHistogram(name, help, buckets)).(*Histogram)
+	if !ok {
+		panic(ErrNameRegistered)
+	}
+	return h
+}
+
+// Create and register histogram with increasing upper bounds of buckets, DefaultBuckets if nil
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := newHistogram(name, help, buckets)
+	r.register(h)
+	return h
+}
+
+func newHistogram(name, help string, buckets []float64) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			panic(ErrBucketsNotValid)
+		}
+	}
+	return &Histogram{meta: meta{name: name, help: help}, buckets: append([]float64(nil), buckets...),
+		counts: make([]uint64, len(buckets)+1)}
+}
+
+// Metric of name, nil if it isn't registered
+func (r *Registry) Get(name string) Metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics[name]
+}
+
+// Write metrics in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := make([]Metric, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		metrics = append(metrics, m)
+	}
+	r.mu.Unlock()
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name() < metrics[j].Name()
+	})
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler of scrapes of metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		r.WriteText(w)
+	})
+}
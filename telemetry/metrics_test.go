@@ -0,0 +1,80 @@
+package telemetry
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("requests_total", "Requests.")
+	g := r.NewGauge("loss", "")
+	h := r.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 1})
+	c.Inc()
+	c.Add(2)
+	c.Add(-5)
+	g.Set(0.25)
+	for _, v := range []float64{0.05, 0.5, 0.5, 3} {
+		h.Observe(v)
+	}
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 3
+latency_seconds_bucket{le="+Inf"} 4
+latency_seconds_sum 4.05
+latency_seconds_count 4
+# TYPE loss gauge
+loss 0.25
+# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total 3
+`
+	if got := b.String(); !strings.HasSuffix(got, expected) || !strings.HasPrefix(got, "# HELP latency_seconds Latency.") {
+		t.Errorf("WriteText failed. Expected:\n%v\nbut got:\n%v", expected, got)
+	}
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if body, _ := io.ReadAll(rec.Body); string(body) != b.String() {
+		t.Errorf("Handler failed. Expected metrics text, but got %q", body)
+	}
+	for _, name := range []string{"requests_total", "1bad", "bad-name"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewCounter failed. Expected panic with name %q", name)
+				}
+			}()
+			r.NewCounter(name, "")
+		}()
+	}
+}
+
+func TestRegistryGetOrCreate(t *testing.T) {
+	r := NewRegistry()
+	if r.Counter("events_total", "") != r.Counter("events_total", "") {
+		t.Errorf("Counter failed. Expected the registered counter")
+	}
+	if r.Histogram("latency_seconds", "", nil) != r.Histogram("latency_seconds", "", []float64{1}) {
+		t.Errorf("Histogram failed. Expected the registered histogram")
+	}
+	func() {
+		defer func() {
+			if err := recover(); err != ErrNameRegistered {
+				t.Errorf("Gauge failed. Expected panic %v, but got %v", ErrNameRegistered, err)
+			}
+		}()
+		r.Gauge("events_total", "")
+	}()
+	r.NewGauge("loss", "Loss \\ of\nlast epoch.")
+	var b strings.Builder
+	r.WriteText(&b)
+	if expected := "# HELP loss Loss \\\\ of\\nlast epoch.\n"; !strings.Contains(b.String(), expected) {
+		t.Errorf("WriteText failed. Expected escaped help %q, but got:\n%v", expected, b.String())
+	}
+}
@@ -0,0 +1,315 @@
+package text
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var ErrVocabNotValid = errors.New("vocabulary size is not valid")
+
+const (
+	PadToken  = "<pad>"
+	UnkToken  = "<unk>"
+	endOfWord = "</w>"
+)
+
+// Byte pair encoding tokenizer with subwords merged by frequency, ids 0 and 1 are padding and unknown tokens
+type BPE struct {
+	tokens   []string
+	ids      map[string]int
+	specials int //padding, unknown and special tokens are the first ids
+	merges   [][2]string
+	ranks    map[[2]string]int
+	cache    map[string][]int
+}
+
+// split text in words and single punctuation characters
+func pretokenize(text string) []string {
+	words := make([]string, 0)
+	start := -1
+	for i, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+			continue
+		case start >= 0:
+			words = append(words, text[start:i])
+			start = -1
+		}
+		if !unicode.IsSpace(r) {
+			words = append(words, string(r))
+		}
+	}
+	if start >= 0 {
+		words = append(words, text[start:])
+	}
+	return words
+}
+
+// symbols of word, last one is marked as end of word
+func symbols(word string) []string {
+	out := make([]string, 0, len(word))
+	for _, r := range word {
+		out = append(out, string(r))
+	}
+	out[len(out)-1] += endOfWord
+	return out
+}
+
+// Train byte pair encoding on documents until vocabulary has vocabSize tokens or no pair is repeated
+// minFrequency times, special tokens are added after padding and unknown tokens
+func TrainBPE(docs []string, vocabSize, minFrequency int, special ...string) *BPE {
+	if minFrequency < 1 {
+		minFrequency = 1
+	}
+	freq := make(map[string]int)
+	for _, doc := range docs {
+		for _, w := range pretokenize(doc) {
+			freq[w]++
+		}
+	}
+	if len(freq) == 0 {
+		panic(ErrEmptyDocs)
+	}
+	words := make([]string, 0, len(freq))
+	for w := range freq {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	split := make([][]string, len(words))
+	bpe := &BPE{ids: make(map[string]int), ranks: make(map[[2]string]int)}
+	for _, tok := range append([]string{PadToken, UnkToken}, special...) {
+		bpe.add(tok)
+	}
+	bpe.specials = len(bpe.tokens)
+	alphabet := make([]string, 0)
+	for i, w := range words {
+		split[i] = symbols(w)
+		for _, s := range split[i] {
+			if _, ok := bpe.ids[s]; !ok {
+				alphabet = append(alphabet, s)
+				bpe.ids[s] = -1
+			}
+		}
+	}
+	sort.Strings(alphabet)
+	for _, s := range alphabet {
+		delete(bpe.ids, s)
+		bpe.add(s)
+	}
+	if vocabSize < len(bpe.tokens) {
+		panic(ErrVocabNotValid)
+	}
+	for len(bpe.tokens) < vocabSize {
+		pairs := make(map[[2]string]int)
+		for i, s := range split {
+			for j := 0; j+1 < len(s); j++ {
+				pairs[[2]string{s[j], s[j+1]}] += freq[words[i]]
+			}
+		}
+		// most frequent pair, ties are broken by order of strings
+		var best [2]string
+		count := 0
+		for p, c := range pairs {
+			if c > count || c == count && (p[0] < best[0] || p[0] == best[0] && p[1] < best[1]) {
+				best, count = p, c
+			}
+		}
+		if count < minFrequency {
+			break
+		}
+		for i, s := range split {
+			split[i] = merge(s, best)
+		}
+		bpe.ranks[best] = len(bpe.merges)
+		bpe.merges = append(bpe.merges, best)
+		bpe.add(best[0] + best[1])
+	}
+	return bpe
+}
+
+func (bpe *BPE) add(tok string) {
+	if _, ok := bpe.ids[tok]; !ok {
+		bpe.ids[tok] = len(bpe.tokens)
+		bpe.tokens = append(bpe.tokens, tok)
+	}
+}
+
+// replace every occurrence of pair in symbols by its merge
+func merge(s []string, pair [2]string) []string {
+	out := s[:0:0]
+	for j := 0; j < len(s); j++ {
+		if j+1 < len(s) && s[j] == pair[0] && s[j+1] == pair[1] {
+			out = append(out, pair[0]+pair[1])
+			j++
+		} else {
+			out = append(out, s[j])
+		}
+	}
+	return out
+}
+
+// Number of tokens of vocabulary
+func (bpe *BPE) VocabSize() int {
+	return len(bpe.tokens)
+}
+
+// Token of id
+func (bpe *BPE) Token(id int) string {
+	return bpe.tokens[id]
+}
+
+// Id of token
+func (bpe *BPE) ID(token string) (int, bool) {
+	id, ok := bpe.ids[token]
+	return id, ok
+}
+
+// ids of word applying merges in order of training
+func (bpe *BPE) encodeWord(word string) []int {
+	if bpe.cache == nil {
+		bpe.cache = make(map[string][]int)
+	}
+	if ids, ok := bpe.cache[word]; ok {
+		return ids
+	}
+	s := symbols(word)
+	for len(s) > 1 {
+		best, rank := -1, len(bpe.merges)
+		for j := 0; j+1 < len(s); j++ {
+			if r, ok := bpe.ranks[[2]string{s[j], s[j+1]}]; ok && r < rank {
+				best, rank = j, r
+			}
+		}
+		if best < 0 {
+			break
+		}
+		s = merge(s, bpe.merges[rank])
+	}
+	ids := make([]int, len(s))
+	for i, sym := range s {
+		if id, ok := bpe.ids[sym]; ok {
+			ids[i] = id
+		} else {
+			ids[i] = bpe.ids[UnkToken]
+		}
+	}
+	bpe.cache[word] = ids
+	return ids
+}
+
+// Encode text in token ids, special tokens of vocabulary found as words are kept whole
+func (bpe *BPE) Encode(text string) []int {
+	ids := make([]int, 0, len(text))
+	for _, field := range strings.Fields(text) {
+		if id, ok := bpe.ids[field]; ok && id < bpe.specials {
+			ids = append(ids, id)
+			continue
+		}
+		for _, w := range pretokenize(field) {
+			ids = append(ids, bpe.encodeWord(w)...)
+		}
+	}
+	return ids
+}
+
+// Decode token ids in text, padding is skipped and punctuation is separated by spaces
+func (bpe *BPE) Decode(ids []int) string {
+	var sb strings.Builder
+	for _, id := range ids {
+		if id == bpe.ids[PadToken] {
+			continue
+		}
+		tok := bpe.tokens[id]
+		switch {
+		case id < bpe.specials:
+			sb.WriteString(tok)
+			sb.WriteByte(' ')
+		case strings.HasSuffix(tok, endOfWord):
+			sb.WriteString(strings.TrimSuffix(tok, endOfWord))
+			sb.WriteByte(' ')
+		default:
+			sb.WriteString(tok)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// Encode texts in a tensor of shape (texts, length) of float32 ids, longer texts are truncated and shorter
+// ones are padded
+func (bpe *BPE) EncodeTensor(texts []string, length int) *graph.Tensor {
+	if len(texts) == 0 {
+		panic(ErrEmptyDocs)
+	}
+	ts := graph.NewTensor(nil, graph.Float32, graph.NewShape(len(texts), length))
+	index := make([]int, 2)
+	for i, text := range texts {
+		index[0] = i
+		for j, id := range bpe.Encode(text) {
+			if j == length {
+				break
+			}
+			index[1] = j
+			ts.SetF32(index, float32(id))
+		}
+	}
+	return ts
+}
+
+// Decode every row of a tensor of shape (texts, length) of ids
+func (bpe *BPE) DecodeTensor(ts *graph.Tensor) []string {
+	shape := ts.Shape()
+	if shape.Dim() != 2 {
+		panic(graph.ErrInvalidShape)
+	}
+	out := make([]string, shape[0])
+	index := make([]int, 2)
+	ids := make([]int, shape[1])
+	for i := range out {
+		index[0] = i
+		for j := range ids {
+			index[1] = j
+			ids[j] = int(ts.GetF32At(index))
+		}
+		out[i] = bpe.Decode(ids)
+	}
+	return out
+}
+
+type bpeSpec struct {
+	Tokens   []string
+	Specials int
+	Merges   [][2]string
+}
+
+// Save vocabulary and merges
+func (bpe *BPE) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(&bpeSpec{Tokens: bpe.tokens, Specials: bpe.specials, Merges: bpe.merges})
+}
+
+// Load a tokenizer saved with BPE.Save
+func LoadBPE(r io.Reader) (*BPE, error) {
+	var spec bpeSpec
+	if err := gob.NewDecoder(r).Decode(&spec); err != nil {
+		return nil, err
+	}
+	if len(spec.Tokens) < 2 || spec.Tokens[0] != PadToken || spec.Tokens[1] != UnkToken || spec.Specials < 2 || spec.Specials > len(spec.Tokens) {
+		return nil, ErrVocabNotValid
+	}
+	bpe := &BPE{tokens: spec.Tokens, specials: spec.Specials, merges: spec.Merges, ids: make(map[string]int), ranks: make(map[[2]string]int)}
+	for i, tok := range spec.Tokens {
+		bpe.ids[tok] = i
+	}
+	for i, m := range spec.Merges {
+		bpe.ranks[m] = i
+	}
+	return bpe, nil
+}
@@ -0,0 +1,52 @@
+package text
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestBPE(t *testing.T) {
+	docs := []string{"low lower lowest", "newer newest wider", "low low newest newest"}
+	bpe := TrainBPE(docs, 40, 2, "<cls>")
+	if id, _ := bpe.ID("<cls>"); id != 2 || bpe.Token(0) != PadToken || bpe.Token(1) != UnkToken {
+		t.Fatalf("TrainBPE failed. Special tokens have wrong ids")
+	}
+	if _, ok := bpe.ID("low</w>"); !ok {
+		t.Errorf("TrainBPE failed. Expected frequent word low</w> in vocabulary")
+	}
+	ids := bpe.Encode("<cls> lowest newer")
+	if got := bpe.Decode(ids); got != "<cls> lowest newer" {
+		t.Errorf("Decode failed. Expected <cls> lowest newer, but got %v", got)
+	}
+	// unseen characters are unknown
+	if ids := bpe.Encode("z"); !reflect.DeepEqual(ids, []int{1}) {
+		t.Errorf("Encode failed. Expected unknown id, but got %v", ids)
+	}
+	// frequent words are encoded with fewer tokens than rare ones of same length
+	if len(bpe.Encode("newest")) >= len(bpe.Encode("wider!")) {
+		t.Errorf("Encode failed. Expected less tokens for frequent word newest")
+	}
+}
+
+func TestBPETensor(t *testing.T) {
+	bpe := TrainBPE([]string{"hello world, hello go"}, 30, 1)
+	ts := bpe.EncodeTensor([]string{"hello go", "world"}, 6)
+	if shape := ts.Shape(); shape[0] != 2 || shape[1] != 6 {
+		t.Fatalf("EncodeTensor failed. Expected shape (2, 6), but got %v", shape)
+	}
+	if texts := bpe.DecodeTensor(ts); !reflect.DeepEqual(texts, []string{"hello go", "world"}) {
+		t.Errorf("DecodeTensor failed. Expected [hello go world], but got %v", texts)
+	}
+	var buf bytes.Buffer
+	if err := bpe.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBPE(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := bpe.Encode("hello, world"), loaded.Encode("hello, world"); !reflect.DeepEqual(a, b) {
+		t.Errorf("LoadBPE failed. Expected %v, but got %v", a, b)
+	}
+}
@@ -0,0 +1,138 @@
+package text
+
+import (
+	"math"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// TFIDF transformer weights token counts by inverse document frequency
+type TFIDF struct {
+	smooth    bool
+	sublinear bool
+	normalize bool
+	idf       []float64
+}
+
+// Create TF-IDF transformer, smooth adds one to document frequencies as if a document had every term,
+// sublinear replaces counts by 1 + log(count) and normalize scales vectors to unit euclidean norm
+func NewTFIDF(smooth, sublinear, normalize bool) *TFIDF {
+	return &TFIDF{smooth: smooth, sublinear: sublinear, normalize: normalize}
+}
+
+// Fit inverse document frequencies to count vectors
+func (tf *TFIDF) Fit(counts []*knn.SparsePoint) {
+	if len(counts) == 0 {
+		panic(ErrEmptyDocs)
+	}
+	dim := counts[0].Dim()
+	df := make([]float64, dim)
+	for _, c := range counts {
+		if c.Dim() != dim {
+			panic(knn.ErrPointDimensionMismatch)
+		}
+		c.Range(func(i int, _ float64) { df[i]++ })
+	}
+	n := float64(len(counts))
+	s := 0.0
+	if tf.smooth {
+		s = 1
+	}
+	tf.idf = make([]float64, dim)
+	for i, d := range df {
+		if d+s == 0 {
+			continue
+		}
+		tf.idf[i] = math.Log((n+s)/(d+s)) + 1
+	}
+}
+
+// Weighted vector of count vector
+func (tf *TFIDF) Transform(counts *knn.SparsePoint) *knn.SparsePoint {
+	if tf.idf == nil {
+		panic(ErrNotFitted)
+	}
+	if counts.Dim() != len(tf.idf) {
+		panic(knn.ErrPointDimensionMismatch)
+	}
+	indices := make([]int, 0, counts.NonZero())
+	values := make([]float64, 0, counts.NonZero())
+	norm := 0.0
+	counts.Range(func(i int, v float64) {
+		if tf.sublinear {
+			v = 1 + math.Log(v)
+		}
+		v *= tf.idf[i]
+		indices = append(indices, i)
+		values = append(values, v)
+		norm += v * v
+	})
+	if tf.normalize && norm > 0 {
+		norm = math.Sqrt(norm)
+		for k := range values {
+			values[k] /= norm
+		}
+	}
+	return knn.NewSparsePoint(counts.Dim(), indices, values)
+}
+
+// Weighted vectors of count vectors
+func (tf *TFIDF) TransformAll(counts []*knn.SparsePoint) []*knn.SparsePoint {
+	out := make([]*knn.SparsePoint, len(counts))
+	for i, c := range counts {
+		out[i] = tf.Transform(c)
+	}
+	return out
+}
+
+// Inverse document frequency of every term
+func (tf *TFIDF) IDF() []float64 {
+	return tf.idf
+}
+
+// TFIDFVectorizer converts documents to TF-IDF vectors with a count vectorizer and a TF-IDF transformer
+type TFIDFVectorizer struct {
+	*CountVectorizer
+	tfidf *TFIDF
+}
+
+// Create TF-IDF vectorizer of documents
+func NewTFIDFVectorizer(counter *CountVectorizer, tfidf *TFIDF) *TFIDFVectorizer {
+	if counter == nil {
+		counter = NewCountVectorizer(nil, 1, 0, false)
+	}
+	if tfidf == nil {
+		tfidf = NewTFIDF(true, false, true)
+	}
+	return &TFIDFVectorizer{CountVectorizer: counter, tfidf: tfidf}
+}
+
+// Fit vocabulary and inverse document frequencies to documents
+func (tv *TFIDFVectorizer) Fit(docs []string) {
+	tv.tfidf.Fit(tv.CountVectorizer.FitTransform(docs))
+}
+
+// TF-IDF vector of document
+func (tv *TFIDFVectorizer) Transform(doc string) *knn.SparsePoint {
+	return tv.tfidf.Transform(tv.CountVectorizer.Transform(doc))
+}
+
+// TF-IDF vectors of documents
+func (tv *TFIDFVectorizer) TransformAll(docs []string) []*knn.SparsePoint {
+	out := make([]*knn.SparsePoint, len(docs))
+	for i, doc := range docs {
+		out[i] = tv.Transform(doc)
+	}
+	return out
+}
+
+// Fit and transform documents
+func (tv *TFIDFVectorizer) FitTransform(docs []string) []*knn.SparsePoint {
+	tv.Fit(docs)
+	return tv.TransformAll(docs)
+}
+
+// Inverse document frequency of every term
+func (tv *TFIDFVectorizer) IDF() []float64 {
+	return tv.tfidf.IDF()
+}
@@ -0,0 +1,51 @@
+package text
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func TestTFIDF(t *testing.T) {
+	docs := []string{"apple banana", "apple cherry", "apple banana banana"}
+	tv := NewTFIDFVectorizer(nil, NewTFIDF(true, false, false))
+	vectors := tv.FitTransform(docs)
+	apple, _ := tv.Index("apple")
+	banana, _ := tv.Index("banana")
+	// smoothed idf is log((1 + n) / (1 + df)) + 1
+	if idf := tv.IDF()[apple]; math.Abs(idf-1) > 1e-12 {
+		t.Errorf("IDF failed. Expected 1 for term in every document, but got %v", idf)
+	}
+	if expected := 2 * (math.Log(4.0/3) + 1); math.Abs(vectors[2].At(banana)-expected) > 1e-12 {
+		t.Errorf("Transform failed. Expected %v, but got %v", expected, vectors[2].At(banana))
+	}
+	normalized := NewTFIDFVectorizer(nil, nil).FitTransform(docs)
+	for _, v := range normalized {
+		norm := 0.0
+		v.Range(func(_ int, x float64) { norm += x * x })
+		if math.Abs(norm-1) > 1e-12 {
+			t.Errorf("Transform failed. Expected unit norm, but got %v", math.Sqrt(norm))
+		}
+	}
+}
+
+func TestTFIDFKNN(t *testing.T) {
+	docs := []string{
+		"the striker scored a goal in the football match",
+		"the team won the football league after the match",
+		"the goalkeeper saved a penalty in the match",
+		"the compiler optimizes go code for speed",
+		"the go program has a bug in its code",
+		"the developer wrote code with a new compiler",
+	}
+	labels := []any{"sport", "sport", "sport", "tech", "tech", "tech"}
+	tv := NewTFIDFVectorizer(NewCountVectorizer(NewTokenizer(true, EnglishStopWords, 1, 1), 1, 0, false), nil)
+	model := knn.NewKNN(1, knn.NewSparseCosineDist(), knn.NewMultiClassSelector(), DataPoints(tv.FitTransform(docs), labels))
+	if label := model.PredictVector(tv.Transform("a great goal won the match")); label != "sport" {
+		t.Errorf("PredictVector failed. Expected sport, but got %v", label)
+	}
+	if label := model.PredictVector(tv.Transform("fix the bug in the compiler")); label != "tech" {
+		t.Errorf("PredictVector failed. Expected tech, but got %v", label)
+	}
+}
@@ -0,0 +1,82 @@
+// Package text implements tokenization and vectorization of text documents
+package text
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrNGramNotValid = errors.New("n-gram range is not valid")
+	ErrNotFitted     = errors.New("vectorizer is not fitted")
+	ErrEmptyDocs     = errors.New("there are no documents")
+)
+
+// Common english stop words
+var EnglishStopWords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from", "has", "have", "he", "her", "his",
+	"i", "if", "in", "into", "is", "it", "its", "me", "my", "no", "not", "of", "on", "or", "our", "she",
+	"so", "such", "that", "the", "their", "them", "then", "there", "these", "they", "this", "to", "was",
+	"we", "were", "will", "with", "you", "your",
+}
+
+// Tokenizer splits text in words of letters and digits and builds n-grams of them
+type Tokenizer struct {
+	lowercase bool
+	stopWords map[string]bool
+	minN      int
+	maxN      int
+}
+
+// Create tokenizer producing n-grams with n in range [minN, maxN], stop words are removed before building n-grams
+func NewTokenizer(lowercase bool, stopWords []string, minN, maxN int) *Tokenizer {
+	if minN < 1 || maxN < minN {
+		panic(ErrNGramNotValid)
+	}
+	tk := &Tokenizer{lowercase: lowercase, stopWords: make(map[string]bool, len(stopWords)), minN: minN, maxN: maxN}
+	for _, w := range stopWords {
+		if lowercase {
+			w = strings.ToLower(w)
+		}
+		tk.stopWords[w] = true
+	}
+	return tk
+}
+
+// Create tokenizer of lowercase words without stop words
+func NewDefaultTokenizer() *Tokenizer {
+	return NewTokenizer(true, nil, 1, 1)
+}
+
+// Words of text without stop words
+func (tk *Tokenizer) Words(text string) []string {
+	if tk.lowercase {
+		text = strings.ToLower(text)
+	}
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	words := make([]string, 0, len(fields))
+	for _, w := range fields {
+		if w = strings.Trim(w, "'"); w != "" && !tk.stopWords[w] {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// Tokens of text, n-grams are words joined by a space
+func (tk *Tokenizer) Tokenize(text string) []string {
+	words := tk.Words(text)
+	if tk.minN == 1 && tk.maxN == 1 {
+		return words
+	}
+	tokens := make([]string, 0, len(words)*(tk.maxN-tk.minN+1))
+	for n := tk.minN; n <= tk.maxN; n++ {
+		for i := 0; i+n <= len(words); i++ {
+			tokens = append(tokens, strings.Join(words[i:i+n], " "))
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,19 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tk := NewTokenizer(true, EnglishStopWords, 1, 2)
+	tokens := tk.Tokenize("The cat sat on the MAT, isn't it?")
+	expected := []string{"cat", "sat", "mat", "isn't", "cat sat", "sat mat", "mat isn't"}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Tokenize failed. Expected %v, but got %v", expected, tokens)
+	}
+	words := NewTokenizer(false, nil, 1, 1).Words("Go 1.19 is 'fast'")
+	if expected := []string{"Go", "1", "19", "is", "fast"}; !reflect.DeepEqual(words, expected) {
+		t.Errorf("Words failed. Expected %v, but got %v", expected, words)
+	}
+}
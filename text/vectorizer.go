@@ -0,0 +1,146 @@
+package text
+
+import (
+	"sort"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// CountVectorizer converts documents to sparse vectors of token counts
+type CountVectorizer struct {
+	tokenizer   *Tokenizer
+	minDF       int
+	maxFeatures int
+	binary      bool
+	vocab       map[string]int
+	terms       []string
+}
+
+// Create count vectorizer, tokens in less than minDF documents are ignored, maxFeatures keeps the most
+// frequent tokens when it isn't zero and binary counts every token once by document
+func NewCountVectorizer(tokenizer *Tokenizer, minDF, maxFeatures int, binary bool) *CountVectorizer {
+	if tokenizer == nil {
+		tokenizer = NewDefaultTokenizer()
+	}
+	return &CountVectorizer{tokenizer: tokenizer, minDF: minDF, maxFeatures: maxFeatures, binary: binary}
+}
+
+// Fit vocabulary to documents, terms are sorted alphabetically
+func (cv *CountVectorizer) Fit(docs []string) {
+	if len(docs) == 0 {
+		panic(ErrEmptyDocs)
+	}
+	df := make(map[string]int)
+	total := make(map[string]int)
+	for _, doc := range docs {
+		seen := make(map[string]bool)
+		for _, tok := range cv.tokenizer.Tokenize(doc) {
+			total[tok]++
+			if !seen[tok] {
+				seen[tok] = true
+				df[tok]++
+			}
+		}
+	}
+	terms := make([]string, 0, len(df))
+	for tok, n := range df {
+		if n >= cv.minDF {
+			terms = append(terms, tok)
+		}
+	}
+	if cv.maxFeatures > 0 && len(terms) > cv.maxFeatures {
+		sort.Slice(terms, func(i, j int) bool {
+			if total[terms[i]] != total[terms[j]] {
+				return total[terms[i]] > total[terms[j]]
+			}
+			return terms[i] < terms[j]
+		})
+		terms = terms[:cv.maxFeatures]
+	}
+	sort.Strings(terms)
+	cv.terms = terms
+	cv.vocab = make(map[string]int, len(terms))
+	for i, tok := range terms {
+		cv.vocab[tok] = i
+	}
+}
+
+// Vector of token counts of document, tokens out of vocabulary are ignored
+func (cv *CountVectorizer) Transform(doc string) *knn.SparsePoint {
+	if cv.vocab == nil {
+		panic(ErrNotFitted)
+	}
+	counts := make(map[int]float64)
+	for _, tok := range cv.tokenizer.Tokenize(doc) {
+		if i, ok := cv.vocab[tok]; ok {
+			if cv.binary {
+				counts[i] = 1
+			} else {
+				counts[i]++
+			}
+		}
+	}
+	indices := make([]int, 0, len(counts))
+	values := make([]float64, 0, len(counts))
+	for i, v := range counts {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	return knn.NewSparsePoint(len(cv.terms), indices, values)
+}
+
+// Vectors of token counts of documents
+func (cv *CountVectorizer) TransformAll(docs []string) []*knn.SparsePoint {
+	out := make([]*knn.SparsePoint, len(docs))
+	for i, doc := range docs {
+		out[i] = cv.Transform(doc)
+	}
+	return out
+}
+
+// Fit vocabulary and transform documents
+func (cv *CountVectorizer) FitTransform(docs []string) []*knn.SparsePoint {
+	cv.Fit(docs)
+	return cv.TransformAll(docs)
+}
+
+// Terms of vocabulary by index
+func (cv *CountVectorizer) Vocabulary() []string {
+	return cv.terms
+}
+
+// Index of term in vocabulary
+func (cv *CountVectorizer) Index(term string) (int, bool) {
+	i, ok := cv.vocab[term]
+	return i, ok
+}
+
+// Dense tensor of shape (docs, terms) of sparse vectors
+func Tensor(vectors []*knn.SparsePoint) *graph.Tensor {
+	if len(vectors) == 0 {
+		panic(ErrEmptyDocs)
+	}
+	ts := graph.NewTensor(nil, graph.Float64, graph.NewShape(len(vectors), vectors[0].Dim()))
+	index := make([]int, 2)
+	for d, v := range vectors {
+		index[0] = d
+		v.Range(func(i int, value float64) {
+			index[1] = i
+			ts.SetF64(index, value)
+		})
+	}
+	return ts
+}
+
+// Data points of sparse vectors with labels, consumable by knn
+func DataPoints(vectors []*knn.SparsePoint, labels []any) []knn.DataPoint {
+	if len(vectors) != len(labels) {
+		panic(knn.ErrPointDimensionMismatch)
+	}
+	data := make([]knn.DataPoint, len(vectors))
+	for i, v := range vectors {
+		data[i] = knn.NewSparseDataPoint(labels[i], v)
+	}
+	return data
+}
@@ -0,0 +1,35 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountVectorizer(t *testing.T) {
+	docs := []string{"apple banana apple", "banana cherry", "apple date"}
+	cv := NewCountVectorizer(nil, 1, 0, false)
+	vectors := cv.FitTransform(docs)
+	if expected := []string{"apple", "banana", "cherry", "date"}; !reflect.DeepEqual(cv.Vocabulary(), expected) {
+		t.Fatalf("Fit failed. Expected vocabulary %v, but got %v", expected, cv.Vocabulary())
+	}
+	if v := vectors[0].Dense(); v[0] != 2 || v[1] != 1 || v[2] != 0 {
+		t.Errorf("Transform failed. Expected [2 1 0 0], but got %v", v)
+	}
+	// unknown tokens are ignored
+	if v := cv.Transform("kiwi cherry"); v.NonZero() != 1 || v.At(2) != 1 {
+		t.Errorf("Transform failed. Expected only cherry, but got %v", v.Dense())
+	}
+	ts := Tensor(vectors)
+	if ts.GetF64At([]int{0, 0}) != 2 || ts.GetF64At([]int{2, 3}) != 1 || ts.GetF64At([]int{1, 0}) != 0 {
+		t.Errorf("Tensor failed. Unexpected tensor %v", ts)
+	}
+	// minimum document frequency and most frequent features
+	cv = NewCountVectorizer(nil, 2, 1, true)
+	cv.Fit(docs)
+	if expected := []string{"apple"}; !reflect.DeepEqual(cv.Vocabulary(), expected) {
+		t.Errorf("Fit failed. Expected vocabulary %v, but got %v", expected, cv.Vocabulary())
+	}
+	if v := cv.Transform(docs[0]); v.At(0) != 1 {
+		t.Errorf("Transform failed. Expected binary count 1, but got %v", v.At(0))
+	}
+}
@@ -0,0 +1,340 @@
+package text
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var ErrWordNotFound = errors.New("word is not in vocabulary")
+
+// Corpus of sentences visited once by every epoch of training
+type Corpus interface {
+	Each(fn func(sentence []string))
+}
+
+type sliceCorpus [][]string
+
+// Create corpus of tokenized sentences
+func NewSliceCorpus(sentences [][]string) Corpus {
+	return sliceCorpus(sentences)
+}
+
+func (sc sliceCorpus) Each(fn func(sentence []string)) {
+	for _, s := range sc {
+		fn(s)
+	}
+}
+
+type docCorpus struct {
+	docs      []string
+	tokenizer *Tokenizer
+}
+
+// Create corpus of documents split in words by tokenizer
+func NewDocCorpus(docs []string, tokenizer *Tokenizer) Corpus {
+	if tokenizer == nil {
+		tokenizer = NewDefaultTokenizer()
+	}
+	return &docCorpus{docs: docs, tokenizer: tokenizer}
+}
+
+func (dc *docCorpus) Each(fn func(sentence []string)) {
+	for _, doc := range dc.docs {
+		fn(dc.tokenizer.Words(doc))
+	}
+}
+
+// Configuration of skip-gram with negative sampling, zero fields take the default values
+type Word2VecConfig struct {
+	Dim       int     //size of embeddings, 100 by default
+	Window    int     //greatest distance between center and context words, 5 by default
+	Negative  int     //negative samples by context word, 5 by default
+	MinCount  int     //words with lesser count are ignored, 1 by default
+	Epochs    int     //5 by default
+	Rate      float64 //initial learning rate decayed linearly to zero, 0.025 by default
+	Subsample float64 //threshold of subsampling of frequent words, zero disables it
+	Seed      int64
+}
+
+func (config *Word2VecConfig) defaults() {
+	if config.Dim == 0 {
+		config.Dim = 100
+	}
+	if config.Window == 0 {
+		config.Window = 5
+	}
+	if config.Negative == 0 {
+		config.Negative = 5
+	}
+	if config.MinCount == 0 {
+		config.MinCount = 1
+	}
+	if config.Epochs == 0 {
+		config.Epochs = 5
+	}
+	if config.Rate == 0 {
+		config.Rate = 0.025
+	}
+}
+
+// Train word embeddings on corpus with skip-gram and negative sampling
+func TrainWord2Vec(corpus Corpus, config Word2VecConfig) *Embedding {
+	config.defaults()
+	counts := make(map[string]int)
+	corpus.Each(func(sentence []string) {
+		for _, w := range sentence {
+			counts[w]++
+		}
+	})
+	words := make([]string, 0, len(counts))
+	total := 0
+	for w, c := range counts {
+		if c >= config.MinCount {
+			words = append(words, w)
+			total += c
+		}
+	}
+	if len(words) == 0 {
+		panic(ErrEmptyDocs)
+	}
+	// words sorted by decreasing count
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	index := make(map[string]int, len(words))
+	for i, w := range words {
+		index[w] = i
+	}
+	// table of negative samples with unigram distribution raised to 3/4
+	table := make([]int, 0, 10*len(words)+1000)
+	norm := 0.0
+	for _, w := range words {
+		norm += math.Pow(float64(counts[w]), 0.75)
+	}
+	for i, w := range words {
+		n := int(math.Ceil(math.Pow(float64(counts[w]), 0.75) / norm * float64(cap(table))))
+		for k := 0; k < n; k++ {
+			table = append(table, i)
+		}
+	}
+	rnd := rand.New(rand.NewSource(config.Seed))
+	dim := config.Dim
+	in := make([]float64, len(words)*dim)
+	out := make([]float64, len(words)*dim)
+	for i := range in {
+		in[i] = (rnd.Float64() - 0.5) / float64(dim)
+	}
+	keep := func(w int) bool {
+		if config.Subsample <= 0 {
+			return true
+		}
+		f := float64(counts[words[w]]) / float64(total)
+		p := (math.Sqrt(f/config.Subsample) + 1) * config.Subsample / f
+		return rnd.Float64() < p
+	}
+	steps := float64(config.Epochs * total)
+	done := 0.0
+	grad := make([]float64, dim)
+	ids := make([]int, 0)
+	for epoch := 0; epoch < config.Epochs; epoch++ {
+		corpus.Each(func(sentence []string) {
+			ids = ids[:0]
+			for _, w := range sentence {
+				if i, ok := index[w]; ok {
+					done++
+					if keep(i) {
+						ids = append(ids, i)
+					}
+				}
+			}
+			rate := math.Max(config.Rate*(1-done/steps), config.Rate*1e-4)
+			for pos, center := range ids {
+				// window is shrunk at random like in original implementation
+				window := 1 + rnd.Intn(config.Window)
+				for c := pos - window; c <= pos+window; c++ {
+					if c < 0 || c >= len(ids) || c == pos {
+						continue
+					}
+					v := in[ids[c]*dim : (ids[c]+1)*dim]
+					for k := range grad {
+						grad[k] = 0
+					}
+					for n := 0; n <= config.Negative; n++ {
+						target, label := center, 1.0
+						if n > 0 {
+							if target = table[rnd.Intn(len(table))]; target == center {
+								continue
+							}
+							label = 0
+						}
+						u := out[target*dim : (target+1)*dim]
+						dot := 0.0
+						for k := range v {
+							dot += v[k] * u[k]
+						}
+						g := rate * (label - 1/(1+math.Exp(-dot)))
+						for k := range v {
+							grad[k] += g * u[k]
+							u[k] += g * v[k]
+						}
+					}
+					for k := range v {
+						v[k] += grad[k]
+					}
+				}
+			}
+		})
+	}
+	return &Embedding{words: words, index: index, vectors: in, dim: dim}
+}
+
+// Embedding of words in vector space
+type Embedding struct {
+	words   []string
+	index   map[string]int
+	vectors []float64 //rows of words
+	dim     int
+	model   *knn.KNN
+}
+
+// Create embedding from a tensor of shape (words, dim) whose rows are vectors of words
+func NewEmbedding(words []string, vectors *graph.Tensor) *Embedding {
+	points := knn.PointsFromTensor(vectors)
+	if len(points) != len(words) {
+		panic(knn.ErrTensorNotMatrix)
+	}
+	e := &Embedding{words: words, index: make(map[string]int, len(words)), dim: len(points[0])}
+	e.vectors = make([]float64, 0, len(words)*e.dim)
+	for i, w := range words {
+		e.index[w] = i
+		e.vectors = append(e.vectors, points[i]...)
+	}
+	return e
+}
+
+// Words of vocabulary sorted by decreasing count in corpus
+func (e *Embedding) Words() []string {
+	return e.words
+}
+
+// Size of vectors
+func (e *Embedding) Dim() int {
+	return e.dim
+}
+
+// Vector of word
+func (e *Embedding) Vector(word string) (knn.Point, bool) {
+	i, ok := e.index[word]
+	if !ok {
+		return nil, false
+	}
+	return knn.Point(e.vectors[i*e.dim : (i+1)*e.dim]), true
+}
+
+// Tensor of shape (words, dim) whose rows are vectors of words
+func (e *Embedding) Tensor() *graph.Tensor {
+	ts := graph.NewTensor(nil, graph.Float64, graph.NewShape(len(e.words), e.dim))
+	index := make([]int, 2)
+	for i := range e.words {
+		index[0] = i
+		for j := 0; j < e.dim; j++ {
+			index[1] = j
+			ts.SetF64(index, e.vectors[i*e.dim+j])
+		}
+	}
+	return ts
+}
+
+// Similar word with its cosine similarity
+type Similar struct {
+	Word       string
+	Similarity float64
+}
+
+// Nearest n words to a vector by cosine similarity, words in exclude are skipped
+func (e *Embedding) NearestVector(vector knn.Point, n int, exclude ...string) []Similar {
+	if e.model == nil {
+		data := make([]knn.DataPoint, len(e.words))
+		for i, w := range e.words {
+			p, _ := e.Vector(w)
+			data[i] = knn.NewDataPoint(w, p)
+		}
+		e.model = knn.NewKNN(1, knn.NewCosineDist(false), knn.NewMultiClassSelector(), data)
+	}
+	skip := make(map[string]bool, len(exclude))
+	for _, w := range exclude {
+		skip[w] = true
+	}
+	k := n + len(exclude)
+	if k > len(e.words) {
+		k = len(e.words)
+	}
+	out := make([]Similar, 0, n)
+	for _, dd := range e.model.KNeighbors(vector, k) {
+		if w := dd.DataPoint().Label().(string); !skip[w] && len(out) < n {
+			out = append(out, Similar{Word: w, Similarity: 1 - dd.Dist()})
+		}
+	}
+	return out
+}
+
+// Nearest n words to word by cosine similarity
+func (e *Embedding) Nearest(word string, n int) ([]Similar, error) {
+	v, ok := e.Vector(word)
+	if !ok {
+		return nil, ErrWordNotFound
+	}
+	return e.NearestVector(v, n, word), nil
+}
+
+// Words nearest to b - a + c, the answer of analogy a is to b as c is to ?
+func (e *Embedding) Analogy(a, b, c string, n int) ([]Similar, error) {
+	va, okA := e.Vector(a)
+	vb, okB := e.Vector(b)
+	vc, okC := e.Vector(c)
+	if !okA || !okB || !okC {
+		return nil, ErrWordNotFound
+	}
+	v := knn.NewPoint(e.dim)
+	for i := range v {
+		v[i] = vb[i] - va[i] + vc[i]
+	}
+	return e.NearestVector(v, n, a, b, c), nil
+}
+
+type embeddingSpec struct {
+	Words   []string
+	Dim     int
+	Vectors []float64
+}
+
+// Save words and vectors of embedding
+func (e *Embedding) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(&embeddingSpec{Words: e.words, Dim: e.dim, Vectors: e.vectors})
+}
+
+// Load an embedding saved with Embedding.Save
+func LoadEmbedding(r io.Reader) (*Embedding, error) {
+	var spec embeddingSpec
+	if err := gob.NewDecoder(r).Decode(&spec); err != nil {
+		return nil, err
+	}
+	if spec.Dim <= 0 || len(spec.Vectors) != len(spec.Words)*spec.Dim {
+		return nil, knn.ErrTensorNotMatrix
+	}
+	e := &Embedding{words: spec.Words, index: make(map[string]int, len(spec.Words)), vectors: spec.Vectors, dim: spec.Dim}
+	for i, w := range spec.Words {
+		e.index[w] = i
+	}
+	return e, nil
+}
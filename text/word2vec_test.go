@@ -0,0 +1,70 @@
+package text
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// sentences of words of one of two topics
+func topicCorpus(sentences int, seed int64) Corpus {
+	topics := [][]string{
+		{"cat", "dog", "mouse", "horse", "cow", "sheep"},
+		{"red", "blue", "green", "yellow", "black", "white"},
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	out := make([][]string, sentences)
+	for i := range out {
+		topic := topics[rnd.Intn(2)]
+		s := make([]string, 8)
+		for j := range s {
+			s[j] = topic[rnd.Intn(len(topic))]
+		}
+		out[i] = s
+	}
+	return NewSliceCorpus(out)
+}
+
+func TestWord2Vec(t *testing.T) {
+	e := TrainWord2Vec(topicCorpus(500, 1), Word2VecConfig{Dim: 10, Window: 3, Epochs: 10, Seed: 1})
+	if len(e.Words()) != 12 || e.Dim() != 10 {
+		t.Fatalf("TrainWord2Vec failed. Expected 12 words of 10 dimensions, but got %v of %v", len(e.Words()), e.Dim())
+	}
+	nearest, err := e.Nearest("cat", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range nearest {
+		if strings.Contains("red blue green yellow black white", s.Word) {
+			t.Errorf("Nearest failed. Expected animals near cat, but got %v", nearest)
+		}
+	}
+	if _, err := e.Nearest("car", 3); err != ErrWordNotFound {
+		t.Errorf("Nearest failed. Expected %v, but got %v", ErrWordNotFound, err)
+	}
+}
+
+func TestEmbeddingSave(t *testing.T) {
+	e := TrainWord2Vec(NewDocCorpus([]string{"a b c", "b c d", "c d a"}, nil), Word2VecConfig{Dim: 4})
+	var buf bytes.Buffer
+	if err := e.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadEmbedding(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromTensor := NewEmbedding(e.Words(), e.Tensor())
+	for _, w := range e.Words() {
+		v, _ := e.Vector(w)
+		lv, _ := loaded.Vector(w)
+		tv, _ := fromTensor.Vector(w)
+		for i := range v {
+			if v[i] != lv[i] || v[i] != tv[i] {
+				t.Errorf("LoadEmbedding failed. Expected %v for %v, but got %v and %v", v, w, lv, tv)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Package timeseries implements forecasting of univariate time series
+package timeseries
+
+import (
+	"errors"
+	"math"
+
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+var (
+	ErrOrderNotValid = errors.New("model order is lesser than zero")
+	ErrTooShort      = errors.New("series is too short for the model order")
+	ErrNotFitted     = errors.New("model is not fitted")
+	ErrLevelNotValid = errors.New("confidence level is not in range (0, 1)")
+)
+
+// Forecast with confidence interval of every step
+type Forecast struct {
+	Mean  []float64
+	Lower []float64
+	Upper []float64
+}
+
+// quantile of standard normal distribution at (1 + level) / 2
+func normalQuantile(level float64) float64 {
+	if level <= 0 || level >= 1 {
+		panic(ErrLevelNotValid)
+	}
+	return math.Sqrt2 * math.Erfinv(level)
+}
+
+// Differences of order d of series, it has len(series) - d values
+func Difference(series []float64, d int) []float64 {
+	if d < 0 {
+		panic(ErrOrderNotValid)
+	}
+	out := append([]float64(nil), series...)
+	for k := 0; k < d; k++ {
+		if len(out) == 0 {
+			return out
+		}
+		for i := 0; i < len(out)-1; i++ {
+			out[i] = out[i+1] - out[i]
+		}
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// least squares coefficients of rows of x against y by the normal equations
+func leastSquares(x [][]float64, y []float64) ([]float64, error) {
+	cols := len(x[0])
+	m := linalg.NewMatrix(cols, cols, nil)
+	b := make([]float64, cols)
+	for r, row := range x {
+		for i := 0; i < cols; i++ {
+			b[i] += row[i] * y[r]
+			for j := 0; j < cols; j++ {
+				m.Set(i, j, m.At(i, j)+row[i]*row[j])
+			}
+		}
+	}
+	if l, err := m.Cholesky(); err == nil {
+		return linalg.CholeskySolve(l, b), nil
+	}
+	return m.Solve(b)
+}
+
+// ARIMA(p, d, q) model, autoregressive and moving average model of the differences of order d
+type ARIMA struct {
+	p, d, q int
+	mean    float64   //mean of differences, zero if d > 0
+	phi     []float64 //autoregressive coefficients
+	theta   []float64 //moving average coefficients
+	sigma2  float64   //variance of innovations
+	diffs   [][]float64
+	resid   []float64
+}
+
+// Create ARIMA(p, d, q) model
+func NewARIMA(p, d, q int) *ARIMA {
+	if p < 0 || d < 0 || q < 0 {
+		panic(ErrOrderNotValid)
+	}
+	return &ARIMA{p: p, d: d, q: q}
+}
+
+// Create autoregressive AR(p) model
+func NewAR(p int) *ARIMA {
+	return NewARIMA(p, 0, 0)
+}
+
+// regression of w on its p lags and q lags of innovations e from start
+func lagRegression(w, e []float64, p, q, start int) ([]float64, error) {
+	rows := make([][]float64, 0, len(w)-start)
+	y := make([]float64, 0, len(w)-start)
+	for t := start; t < len(w); t++ {
+		row := make([]float64, 0, p+q)
+		for i := 1; i <= p; i++ {
+			row = append(row, w[t-i])
+		}
+		for j := 1; j <= q; j++ {
+			row = append(row, e[t-j])
+		}
+		rows = append(rows, row)
+		y = append(y, w[t])
+	}
+	return leastSquares(rows, y)
+}
+
+// Fit coefficients by the Hannan-Rissanen two stage regression
+//
+// Innovations are estimated with the residuals of a long autoregression, then the series is regressed on its
+// lags and the lags of those innovations. Without moving average part it is least squares autoregression.
+func (ar *ARIMA) Fit(series []float64) error {
+	ar.diffs = make([][]float64, ar.d+1)
+	for k := range ar.diffs {
+		ar.diffs[k] = Difference(series, k)
+	}
+	w := append([]float64(nil), ar.diffs[ar.d]...)
+	long := 0
+	if ar.q > 0 {
+		long = ar.p + ar.q + int(math.Ceil(math.Log(float64(len(w)+1))))
+	}
+	if len(w) < 2*(ar.p+ar.q+long)+2 {
+		return ErrTooShort
+	}
+	ar.mean = 0
+	if ar.d == 0 {
+		for _, v := range w {
+			ar.mean += v
+		}
+		ar.mean /= float64(len(w))
+	}
+	for t := range w {
+		w[t] -= ar.mean
+	}
+	ar.phi, ar.theta = make([]float64, ar.p), make([]float64, ar.q)
+	if ar.p+ar.q > 0 {
+		e := make([]float64, len(w))
+		start := ar.p
+		if ar.q > 0 {
+			longCoef, err := lagRegression(w, nil, long, 0, long)
+			if err != nil {
+				return err
+			}
+			for t := long; t < len(w); t++ {
+				e[t] = w[t]
+				for i, c := range longCoef {
+					e[t] -= c * w[t-1-i]
+				}
+			}
+			start = long + ar.q
+			if ar.p > start {
+				start = ar.p
+			}
+		}
+		coef, err := lagRegression(w, e, ar.p, ar.q, start)
+		if err != nil {
+			return err
+		}
+		copy(ar.phi, coef[:ar.p])
+		copy(ar.theta, coef[ar.p:])
+	}
+	// innovations of fitted model from the start of series
+	ar.resid = make([]float64, len(w))
+	from := ar.p
+	if ar.q > from {
+		from = ar.q
+	}
+	sum := 0.0
+	for t := from; t < len(w); t++ {
+		ar.resid[t] = w[t]
+		for i, c := range ar.phi {
+			ar.resid[t] -= c * w[t-1-i]
+		}
+		for j, c := range ar.theta {
+			ar.resid[t] -= c * ar.resid[t-1-j]
+		}
+		sum += ar.resid[t] * ar.resid[t]
+	}
+	ar.sigma2 = sum / float64(len(w)-from)
+	return nil
+}
+
+// Autoregressive coefficients
+func (ar *ARIMA) AR() []float64 {
+	return ar.phi
+}
+
+// Moving average coefficients
+func (ar *ARIMA) MA() []float64 {
+	return ar.theta
+}
+
+// Variance of innovations
+func (ar *ARIMA) Sigma2() float64 {
+	return ar.sigma2
+}
+
+// Residuals of differenced series, the first max(p, q) are zero
+func (ar *ARIMA) Residuals() []float64 {
+	return ar.resid
+}
+
+// weights of innovations in forecast errors, of the model with autoregressive polynomial times (1 - B)^d
+func (ar *ARIMA) psi(steps int) []float64 {
+	// coefficients of phi(B) (1 - B)^d as x_t = sum full[i] x_(t-1-i) + ...
+	poly := make([]float64, ar.p+1)
+	poly[0] = 1
+	for i, c := range ar.phi {
+		poly[i+1] = -c
+	}
+	for k := 0; k < ar.d; k++ {
+		next := make([]float64, len(poly)+1)
+		for i, c := range poly {
+			next[i] += c
+			next[i+1] -= c
+		}
+		poly = next
+	}
+	psi := make([]float64, steps)
+	for j := range psi {
+		if j == 0 {
+			psi[j] = 1
+			continue
+		}
+		if j <= len(ar.theta) {
+			psi[j] = ar.theta[j-1]
+		}
+		for i := 1; i < len(poly) && i <= j; i++ {
+			psi[j] -= poly[i] * psi[j-i]
+		}
+	}
+	return psi
+}
+
+// Forecast steps values after the series with confidence intervals of level, like 0.95
+func (ar *ARIMA) Forecast(steps int, level float64) Forecast {
+	if ar.diffs == nil {
+		panic(ErrNotFitted)
+	}
+	z := normalQuantile(level)
+	w := ar.diffs[ar.d]
+	n := len(w)
+	// forecast of centered differences, future innovations are zero
+	ext := make([]float64, n+steps)
+	res := make([]float64, n+steps)
+	for t := 0; t < n; t++ {
+		ext[t] = w[t] - ar.mean
+		res[t] = ar.resid[t]
+	}
+	for t := n; t < n+steps; t++ {
+		for i, c := range ar.phi {
+			if t-1-i >= 0 {
+				ext[t] += c * ext[t-1-i]
+			}
+		}
+		for j, c := range ar.theta {
+			if t-1-j >= 0 {
+				ext[t] += c * res[t-1-j]
+			}
+		}
+	}
+	mean := make([]float64, steps)
+	for h := range mean {
+		mean[h] = ext[n+h] + ar.mean
+	}
+	// integrate from differences of order d to the series
+	for k := ar.d - 1; k >= 0; k-- {
+		last := ar.diffs[k][len(ar.diffs[k])-1]
+		for h := range mean {
+			last += mean[h]
+			mean[h] = last
+		}
+	}
+	psi := ar.psi(steps)
+	out := Forecast{Mean: mean, Lower: make([]float64, steps), Upper: make([]float64, steps)}
+	sum := 0.0
+	for h := range mean {
+		sum += psi[h] * psi[h]
+		width := z * math.Sqrt(ar.sigma2*sum)
+		out.Lower[h], out.Upper[h] = mean[h]-width, mean[h]+width
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+package timeseries
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrSmoothingNotValid = errors.New("smoothing parameter is not in range [0, 1]")
+
+// Additive Holt-Winters exponential smoothing of level, trend and season
+//
+// Beta zero keeps the trend at zero and period zero disables the season, so it is simple exponential smoothing
+// with both of them
+type HoltWinters struct {
+	alpha, beta, gamma float64
+	period             int
+	level, trend       float64
+	season             []float64
+	sigma2             float64
+	n                  int
+	fitted             bool
+}
+
+// Create Holt-Winters smoothing with level alpha, trend beta, season gamma and season period
+func NewHoltWinters(alpha, beta, gamma float64, period int) *HoltWinters {
+	for _, v := range []float64{alpha, beta, gamma} {
+		if v < 0 || v > 1 {
+			panic(ErrSmoothingNotValid)
+		}
+	}
+	if period < 0 {
+		panic(ErrOrderNotValid)
+	}
+	return &HoltWinters{alpha: alpha, beta: beta, gamma: gamma, period: period}
+}
+
+// Create simple exponential smoothing
+func NewExponentialSmoothing(alpha float64) *HoltWinters {
+	return NewHoltWinters(alpha, 0, 0, 0)
+}
+
+// Fit smoothing states to series, the first two periods (or values) initialize them
+func (hw *HoltWinters) Fit(series []float64) error {
+	m := hw.period
+	need := 2
+	if m > 0 {
+		need = 2 * m
+	}
+	if len(series) < need {
+		return ErrTooShort
+	}
+	hw.season = make([]float64, m)
+	hw.trend = 0
+	if m > 0 {
+		first, second := 0.0, 0.0
+		for i := 0; i < m; i++ {
+			first += series[i]
+			second += series[m+i]
+		}
+		first /= float64(m)
+		second /= float64(m)
+		hw.level = first
+		if hw.beta > 0 {
+			hw.trend = (second - first) / float64(m)
+		}
+		for i := 0; i < m; i++ {
+			hw.season[i] = series[i] - first
+		}
+	} else {
+		hw.level = series[0]
+		if hw.beta > 0 {
+			hw.trend = series[1] - series[0]
+		}
+	}
+	start := 1
+	if m > 0 {
+		start = m
+	}
+	sum := 0.0
+	for t := start; t < len(series); t++ {
+		s := 0.0
+		if m > 0 {
+			s = hw.season[t%m]
+		}
+		forecast := hw.level + hw.trend + s
+		err := series[t] - forecast
+		sum += err * err
+		prevLevel := hw.level
+		hw.level = hw.alpha*(series[t]-s) + (1-hw.alpha)*(hw.level+hw.trend)
+		if hw.beta > 0 {
+			hw.trend = hw.beta*(hw.level-prevLevel) + (1-hw.beta)*hw.trend
+		}
+		if m > 0 {
+			hw.season[t%m] = hw.gamma*(series[t]-hw.level) + (1-hw.gamma)*s
+		}
+	}
+	hw.sigma2 = sum / float64(len(series)-start)
+	hw.n = len(series)
+	hw.fitted = true
+	return nil
+}
+
+// Variance of one step forecast errors on the fitted series
+func (hw *HoltWinters) Sigma2() float64 {
+	return hw.sigma2
+}
+
+// Forecast steps values after the series with confidence intervals of level, like 0.95
+func (hw *HoltWinters) Forecast(steps int, level float64) Forecast {
+	if !hw.fitted {
+		panic(ErrNotFitted)
+	}
+	z := normalQuantile(level)
+	out := Forecast{Mean: make([]float64, steps), Lower: make([]float64, steps), Upper: make([]float64, steps)}
+	variance := 0.0
+	for h := 1; h <= steps; h++ {
+		mean := hw.level + float64(h)*hw.trend
+		if hw.period > 0 {
+			mean += hw.season[(hw.n+h-1)%hw.period]
+		}
+		// error of h steps is the sum of weighted innovations of steps before it
+		if h == 1 {
+			variance = 1
+		} else {
+			j := float64(h - 1)
+			c := hw.alpha * (1 + j*hw.beta)
+			if hw.period > 0 && (h-1)%hw.period == 0 {
+				c += hw.gamma * (1 - hw.alpha)
+			}
+			variance += c * c
+		}
+		width := z * math.Sqrt(hw.sigma2*variance)
+		out.Mean[h-1] = mean
+		out.Lower[h-1], out.Upper[h-1] = mean-width, mean+width
+	}
+	return out
+}
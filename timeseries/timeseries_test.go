@@ -0,0 +1,125 @@
+package timeseries
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// simulate ARMA process with gaussian innovations
+func simulate(phi, theta []float64, n int, seed int64) []float64 {
+	rnd := rand.New(rand.NewSource(seed))
+	burn := 200
+	x := make([]float64, n+burn)
+	e := make([]float64, n+burn)
+	for t := range x {
+		e[t] = rnd.NormFloat64()
+		x[t] = e[t]
+		for i, c := range phi {
+			if t-1-i >= 0 {
+				x[t] += c * x[t-1-i]
+			}
+		}
+		for j, c := range theta {
+			if t-1-j >= 0 {
+				x[t] += c * e[t-1-j]
+			}
+		}
+	}
+	return x[burn:]
+}
+
+func TestDifference(t *testing.T) {
+	got := Difference([]float64{1, 4, 9, 16, 25}, 2)
+	if len(got) != 3 || got[0] != 2 || got[2] != 2 {
+		t.Errorf("Difference failed. Expected [2 2 2], but got %v", got)
+	}
+}
+
+func TestAR(t *testing.T) {
+	series := simulate([]float64{0.6, -0.3}, nil, 3000, 1)
+	ar := NewAR(2)
+	if err := ar.Fit(series); err != nil {
+		t.Fatal(err)
+	}
+	if phi := ar.AR(); math.Abs(phi[0]-0.6) > 0.05 || math.Abs(phi[1]+0.3) > 0.05 {
+		t.Errorf("AR failed. Expected [0.6 -0.3], but got %v", phi)
+	}
+	if math.Abs(ar.Sigma2()-1) > 0.1 {
+		t.Errorf("Sigma2 failed. Expected 1, but got %v", ar.Sigma2())
+	}
+	if err := NewAR(5).Fit(series[:8]); err != ErrTooShort {
+		t.Errorf("Fit failed. Expected ErrTooShort, but got %v", err)
+	}
+}
+
+func TestARMA(t *testing.T) {
+	series := simulate([]float64{0.5}, []float64{0.4}, 5000, 2)
+	arma := NewARIMA(1, 0, 1)
+	if err := arma.Fit(series); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(arma.AR()[0]-0.5) > 0.1 || math.Abs(arma.MA()[0]-0.4) > 0.1 {
+		t.Errorf("ARMA failed. Expected AR 0.5 and MA 0.4, but got %v and %v", arma.AR(), arma.MA())
+	}
+}
+
+func TestARIMAForecast(t *testing.T) {
+	// random walk with drift 2
+	rnd := rand.New(rand.NewSource(3))
+	series := make([]float64, 500)
+	for t := 1; t < len(series); t++ {
+		series[t] = series[t-1] + 2 + 0.1*rnd.NormFloat64()
+	}
+	model := NewARIMA(1, 1, 0)
+	if err := model.Fit(series); err != nil {
+		t.Fatal(err)
+	}
+	fc := model.Forecast(10, 0.95)
+	last := series[len(series)-1]
+	for h, v := range fc.Mean {
+		if math.Abs(v-(last+2*float64(h+1))) > 0.5 {
+			t.Errorf("Forecast failed. Expected %v at step %d, but got %v", last+2*float64(h+1), h+1, v)
+		}
+		if h > 0 && fc.Upper[h]-fc.Lower[h] <= fc.Upper[h-1]-fc.Lower[h-1] {
+			t.Errorf("Forecast failed. Interval doesn't grow at step %d", h+1)
+		}
+	}
+	// one step interval of a white noise model is the normal quantile
+	noise := NewAR(0)
+	noise.Fit(simulate(nil, nil, 1000, 4))
+	one := noise.Forecast(1, 0.95)
+	if width := (one.Upper[0] - one.Lower[0]) / 2; math.Abs(width-1.959964*math.Sqrt(noise.Sigma2())) > 1e-5 {
+		t.Errorf("Forecast failed. Unexpected interval half width %v", width)
+	}
+}
+
+func TestHoltWinters(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	season := []float64{5, -2, 0, -3}
+	series := make([]float64, 120)
+	for t := range series {
+		series[t] = 10 + 0.5*float64(t) + season[t%4] + 0.2*rnd.NormFloat64()
+	}
+	hw := NewHoltWinters(0.3, 0.1, 0.2, 4)
+	if err := hw.Fit(series[:100]); err != nil {
+		t.Fatal(err)
+	}
+	fc := hw.Forecast(20, 0.9)
+	for h, v := range fc.Mean {
+		expected := 10 + 0.5*float64(100+h) + season[(100+h)%4]
+		if math.Abs(v-expected) > 1.5 {
+			t.Errorf("Forecast failed. Expected %v at step %d, but got %v", expected, h+1, v)
+		}
+		if fc.Lower[h] > v || fc.Upper[h] < v {
+			t.Errorf("Forecast failed. Mean is not in interval at step %d", h+1)
+		}
+	}
+	// simple smoothing forecasts a flat line
+	ses := NewExponentialSmoothing(0.5)
+	ses.Fit([]float64{1, 2, 3, 2, 1, 2})
+	flat := ses.Forecast(3, 0.8).Mean
+	if flat[0] != flat[2] {
+		t.Errorf("ExponentialSmoothing failed. Expected flat forecast, but got %v", flat)
+	}
+}
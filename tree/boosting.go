@@ -0,0 +1,252 @@
+package tree
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+var ErrNotBinary = errors.New("labels of binary classification are not two classes")
+
+// Loss minimized by gradient boosting
+type Loss int
+
+const (
+	SquaredLoss  Loss = iota //regression of float64 labels
+	LogisticLoss             //binary classification
+)
+
+// Configuration of gradient boosting
+type BoostConfig struct {
+	Trees         int     //greatest number of trees
+	LearningRate  float64 //shrinkage of every tree, 0.1 if zero
+	Subsample     float64 //fraction of data points used by every tree, all of them if zero
+	EarlyStopping int     //rounds without improvement of validation loss before stopping, zero disables it
+	Tree          Config  //configuration of regression trees, depth 3 if MaxDepth is zero
+	Seed          int64
+}
+
+// Gradient boosted regression trees
+type GradientBoosting struct {
+	config  BoostConfig
+	loss    Loss
+	init    float64
+	trees   []*Tree
+	classes [2]any //negative and positive class of classification
+	dim     int
+}
+
+// Create gradient boosting for regression with squared loss
+func NewGBRegressor(config BoostConfig) *GradientBoosting {
+	return newGradientBoosting(config, SquaredLoss)
+}
+
+// Create gradient boosting for binary classification with logistic loss
+func NewGBClassifier(config BoostConfig) *GradientBoosting {
+	return newGradientBoosting(config, LogisticLoss)
+}
+
+func newGradientBoosting(config BoostConfig, loss Loss) *GradientBoosting {
+	if config.LearningRate <= 0 {
+		config.LearningRate = 0.1
+	}
+	if config.Subsample <= 0 || config.Subsample > 1 {
+		config.Subsample = 1
+	}
+	if config.Tree.MaxDepth == 0 {
+		config.Tree.MaxDepth = 3
+	}
+	config.Tree.Criterion = MSE
+	return &GradientBoosting{config: config, loss: loss}
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// targets of data points, float64 labels for regression and 0 or 1 for classification
+func (gb *GradientBoosting) targets(data []knn.DataPoint, fit bool) []float64 {
+	y := make([]float64, len(data))
+	if gb.loss == SquaredLoss {
+		for i, dp := range data {
+			v, ok := dp.Label().(float64)
+			if !ok {
+				panic(ErrLabelNotNumeric)
+			}
+			y[i] = v
+		}
+		return y
+	}
+	if fit {
+		gb.classes = [2]any{}
+		count := 0
+		for _, dp := range data {
+			if count == 0 || count == 1 && dp.Label() != gb.classes[0] {
+				gb.classes[count] = dp.Label()
+				count++
+			}
+		}
+		if count != 2 {
+			panic(ErrNotBinary)
+		}
+	}
+	for i, dp := range data {
+		switch dp.Label() {
+		case gb.classes[0]:
+		case gb.classes[1]:
+			y[i] = 1
+		default:
+			panic(ErrNotBinary)
+		}
+	}
+	return y
+}
+
+// loss of raw scores
+func (gb *GradientBoosting) lossOf(y, f []float64) float64 {
+	sum := 0.0
+	for i := range y {
+		if gb.loss == SquaredLoss {
+			sum += (y[i] - f[i]) * (y[i] - f[i])
+		} else {
+			// log(1 + e^f) - y f computed without overflow
+			sum += math.Max(f[i], 0) + math.Log1p(math.Exp(-math.Abs(f[i]))) - y[i]*f[i]
+		}
+	}
+	return sum / float64(len(y))
+}
+
+// Fit trees to data points, validation data is used for early stopping and it can be nil
+func (gb *GradientBoosting) Fit(data, validation []knn.DataPoint) {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	gb.dim = len(data[0].Point())
+	y := gb.targets(data, true)
+	mean := 0.0
+	for _, v := range y {
+		mean += v
+	}
+	mean /= float64(len(y))
+	gb.init = mean
+	if gb.loss == LogisticLoss {
+		gb.init = math.Log(mean / (1 - mean))
+	}
+	f := make([]float64, len(data))
+	for i := range f {
+		f[i] = gb.init
+	}
+	var yVal, fVal []float64
+	if len(validation) > 0 {
+		yVal = gb.targets(validation, false)
+		fVal = make([]float64, len(validation))
+		for i := range fVal {
+			fVal[i] = gb.init
+		}
+	}
+	rnd := rand.New(rand.NewSource(gb.config.Seed))
+	gb.trees = gb.trees[:0]
+	bestLoss, bestTrees := math.Inf(1), 0
+	for round := 0; round < gb.config.Trees; round++ {
+		sample := make([]int, 0, len(data))
+		for i := range data {
+			if gb.config.Subsample >= 1 || rnd.Float64() < gb.config.Subsample {
+				sample = append(sample, i)
+			}
+		}
+		if len(sample) == 0 {
+			continue
+		}
+		// fit tree to negative gradient
+		residuals := make([]knn.DataPoint, len(sample))
+		for k, i := range sample {
+			r := y[i] - f[i]
+			if gb.loss == LogisticLoss {
+				r = y[i] - sigmoid(f[i])
+			}
+			residuals[k] = knn.NewDataPoint(r, data[i].Point())
+		}
+		t := NewRegressor(gb.config.Tree)
+		t.Fit(residuals)
+		if gb.loss == LogisticLoss {
+			gb.newtonLeaves(t, data, sample, y, f)
+		}
+		gb.trees = append(gb.trees, t)
+		for i, dp := range data {
+			f[i] += gb.config.LearningRate * t.Predict(dp.Point()).(float64)
+		}
+		if yVal == nil {
+			continue
+		}
+		for i, dp := range validation {
+			fVal[i] += gb.config.LearningRate * t.Predict(dp.Point()).(float64)
+		}
+		if l := gb.lossOf(yVal, fVal); l < bestLoss {
+			bestLoss, bestTrees = l, len(gb.trees)
+		} else if gb.config.EarlyStopping > 0 && len(gb.trees)-bestTrees >= gb.config.EarlyStopping {
+			break
+		}
+	}
+	if yVal != nil && gb.config.EarlyStopping > 0 {
+		gb.trees = gb.trees[:bestTrees]
+	}
+}
+
+// replace leaf values by a Newton step of logistic loss, sum of residuals over sum of p(1-p)
+func (gb *GradientBoosting) newtonLeaves(t *Tree, data []knn.DataPoint, sample []int, y, f []float64) {
+	num := make(map[*Node]float64)
+	den := make(map[*Node]float64)
+	for _, i := range sample {
+		leaf := t.find(data[i].Point())
+		p := sigmoid(f[i])
+		num[leaf] += y[i] - p
+		den[leaf] += p * (1 - p)
+	}
+	for leaf, n := range num {
+		if d := den[leaf]; d > 1e-12 {
+			leaf.Value = n / d
+		} else {
+			leaf.Value = 0.0
+		}
+	}
+}
+
+// Number of fitted trees
+func (gb *GradientBoosting) Len() int {
+	return len(gb.trees)
+}
+
+// Raw score of point, prediction for regression and log odds of positive class for classification
+func (gb *GradientBoosting) DecisionFunction(point knn.Point) float64 {
+	if gb.dim == 0 {
+		panic(ErrNotFitted)
+	}
+	f := gb.init
+	for _, t := range gb.trees {
+		f += gb.config.LearningRate * t.Predict(point).(float64)
+	}
+	return f
+}
+
+// Predict float64 value for regression or class label for classification
+func (gb *GradientBoosting) Predict(point knn.Point) any {
+	f := gb.DecisionFunction(point)
+	if gb.loss == SquaredLoss {
+		return f
+	}
+	if f > 0 {
+		return gb.classes[1]
+	}
+	return gb.classes[0]
+}
+
+// Predict probabilities of both classes of classification
+func (gb *GradientBoosting) PredictProba(point knn.Point) map[any]float64 {
+	if gb.loss != LogisticLoss {
+		panic(ErrNotClassifier)
+	}
+	p := sigmoid(gb.DecisionFunction(point))
+	return map[any]float64{gb.classes[0]: 1 - p, gb.classes[1]: p}
+}
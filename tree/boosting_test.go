@@ -0,0 +1,54 @@
+package tree
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+func TestGBClassifier(t *testing.T) {
+	data := dataset.MakeCircles(600, 0.08, 0.5, 1)
+	train, test := dataset.SplitTrainTest(data, 0.3, true, 1)
+	train, validation := dataset.SplitTrainTest(train, 0.2, true, 2)
+	gb := NewGBClassifier(BoostConfig{Trees: 300, LearningRate: 0.2, Subsample: 0.8, EarlyStopping: 10, Seed: 1})
+	gb.Fit(train, validation)
+	if gb.Len() == 0 || gb.Len() >= 300 {
+		t.Errorf("GBClassifier failed. Expected early stopping, but got %d trees", gb.Len())
+	}
+	hits := 0
+	for _, dp := range test {
+		if gb.Predict(dp.Point()) == dp.Label() {
+			hits++
+		}
+	}
+	if acc := float64(hits) / float64(len(test)); acc < 0.9 {
+		t.Errorf("GBClassifier failed. Expected accuracy greater than 0.9, but got %v", acc)
+	}
+	proba := gb.PredictProba(test[0].Point())
+	if math.Abs(proba[0]+proba[1]-1) > 1e-9 {
+		t.Errorf("PredictProba failed. Unexpected probabilities %v", proba)
+	}
+}
+
+func TestGBRegressor(t *testing.T) {
+	data := make([]knn.DataPoint, 0, 300)
+	for i := 0; i < 300; i++ {
+		x := float64(i) / 30
+		data = append(data, knn.NewDataPoint(math.Sin(x)*x, knn.Point{x}))
+	}
+	gb := NewGBRegressor(BoostConfig{Trees: 200, LearningRate: 0.1})
+	gb.Fit(data, nil)
+	if gb.Len() != 200 {
+		t.Errorf("GBRegressor failed. Expected 200 trees, but got %d", gb.Len())
+	}
+	sq := 0.0
+	for _, dp := range data {
+		d := gb.Predict(dp.Point()).(float64) - dp.Label().(float64)
+		sq += d * d
+	}
+	if rmse := math.Sqrt(sq / float64(len(data))); rmse > 0.1 {
+		t.Errorf("GBRegressor failed. Expected RMSE lesser than 0.1, but got %v", rmse)
+	}
+}
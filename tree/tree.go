@@ -0,0 +1,517 @@
+// Package tree implements decision trees and ensembles of them
+package tree
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/nn/graph"
+	"github.com/stellviaproject/go-ia/progress"
+)
+
+var (
+	ErrNotFitted         = errors.New("tree is not fitted")
+	ErrEmptyData         = errors.New("there are no data points to fit")
+	ErrLabelNotNumeric   = errors.New("label of regression tree is not float64")
+	ErrCriterionNotValid = errors.New("criterion is not valid for the tree")
+	ErrDimensionMismatch = errors.New("point dimension doesn't match fitted dimension")
+	ErrNotClassifier     = errors.New("tree is not a classifier")
+)
+
+// Impurity criterion of splits
+type Criterion int
+
+const (
+	Gini    Criterion = iota //Gini impurity of classes
+	Entropy                  //entropy of classes in bits
+	MSE                      //variance of labels, for regression
+)
+
+// Configuration of a decision tree
+type Config struct {
+	Criterion       Criterion
+	MaxDepth        int   //greatest depth of leaves, zero is unlimited
+	MinSamplesSplit int   //least samples of a node to split it, two if lesser
+	MinSamplesLeaf  int   //least samples of every leaf, one if lesser
+	Categorical     []int //features with categories, they split by equality to a category
+}
+
+// Node of a decision tree
+type Node struct {
+	Feature     int             //feature of split
+	Threshold   float64         //numeric split goes left if feature <= threshold, categorical if feature == threshold
+	Categorical bool            //split by category
+	Left, Right *Node           //children, both nil in leaves
+	Value       any             //prediction of node, majority label or mean
+	Proba       map[any]float64 //class probabilities of classification nodes
+	Samples     int             //number of fitted samples of node
+	Impurity    float64         //impurity of fitted samples of node
+}
+
+// Test if node is a leaf
+func (n *Node) IsLeaf() bool {
+	return n.Left == nil
+}
+
+// CART decision tree for classification or regression
+type Tree struct {
+	config      Config
+	regression  bool
+	categorical map[int]bool
+	dim         int
+	root        *Node
+	progress    progress.Func
+	// data of fit
+	x       []knn.Point
+	classes []any
+	yClass  []int
+	yValue  []float64
+	ctx     context.Context
+	tracker *progress.Tracker
+}
+
+// Create a classification tree, criterion must be Gini or Entropy
+func NewClassifier(config Config) *Tree {
+	if config.Criterion != Gini && config.Criterion != Entropy {
+		panic(ErrCriterionNotValid)
+	}
+	return newTree(config, false)
+}
+
+// Create a regression tree of float64 labels, criterion must be MSE
+func NewRegressor(config Config) *Tree {
+	if config.Criterion != MSE {
+		panic(ErrCriterionNotValid)
+	}
+	return newTree(config, true)
+}
+
+func newTree(config Config, regression bool) *Tree {
+	if config.MinSamplesSplit < 2 {
+		config.MinSamplesSplit = 2
+	}
+	if config.MinSamplesLeaf < 1 {
+		config.MinSamplesLeaf = 1
+	}
+	t := &Tree{config: config, regression: regression, categorical: make(map[int]bool)}
+	for _, f := range config.Categorical {
+		t.categorical[f] = true
+	}
+	return t
+}
+
+// Set function receiving progress of Fit, its steps are the samples that reach leaves
+func (t *Tree) OnProgress(fn progress.Func) {
+	t.progress = fn
+}
+
+// Fit tree to data points
+func (t *Tree) Fit(data []knn.DataPoint) {
+	t.FitContext(context.Background(), data)
+}
+
+// Fit until ctx is done, then tree is not fitted and it returns the error of ctx
+func (t *Tree) FitContext(ctx context.Context, data []knn.DataPoint) error {
+	if len(data) == 0 {
+		panic(ErrEmptyData)
+	}
+	t.dim = len(data[0].Point())
+	t.x = make([]knn.Point, len(data))
+	for i, dp := range data {
+		if len(dp.Point()) != t.dim {
+			panic(ErrDimensionMismatch)
+		}
+		t.x[i] = dp.Point()
+	}
+	if t.regression {
+		t.yValue = make([]float64, len(data))
+		for i, dp := range data {
+			v, ok := dp.Label().(float64)
+			if !ok {
+				panic(ErrLabelNotNumeric)
+			}
+			t.yValue[i] = v
+		}
+	} else {
+		ids := make(map[any]int)
+		t.classes = t.classes[:0]
+		t.yClass = make([]int, len(data))
+		for i, dp := range data {
+			id, ok := ids[dp.Label()]
+			if !ok {
+				id = len(t.classes)
+				ids[dp.Label()] = id
+				t.classes = append(t.classes, dp.Label())
+			}
+			t.yClass[i] = id
+		}
+	}
+	idx := make([]int, len(data))
+	for i := range idx {
+		idx[i] = i
+	}
+	t.ctx, t.tracker = ctx, progress.NewTracker(len(data), t.progress)
+	t.root = t.build(idx, 0)
+	// release fit data
+	t.x, t.yClass, t.yValue, t.ctx, t.tracker = nil, nil, nil, nil, nil
+	if err := ctx.Err(); err != nil {
+		t.root = nil
+		return err
+	}
+	return nil
+}
+
+// statistics of samples of a node
+type stats struct {
+	n      int
+	counts []int   //class counts
+	sum    float64 //sum of labels
+	sumSq  float64 //sum of squared labels
+}
+
+func (t *Tree) newStats() *stats {
+	return &stats{counts: make([]int, len(t.classes))}
+}
+
+func (t *Tree) add(s *stats, i int, sign int) {
+	s.n += sign
+	if t.regression {
+		y := t.yValue[i]
+		s.sum += float64(sign) * y
+		s.sumSq += float64(sign) * y * y
+	} else {
+		s.counts[t.yClass[i]] += sign
+	}
+}
+
+func (t *Tree) impurity(s *stats) float64 {
+	if s.n == 0 {
+		return 0
+	}
+	n := float64(s.n)
+	switch t.config.Criterion {
+	case Gini:
+		sum := 0.0
+		for _, c := range s.counts {
+			p := float64(c) / n
+			sum += p * p
+		}
+		return 1 - sum
+	case Entropy:
+		h := 0.0
+		for _, c := range s.counts {
+			if c > 0 {
+				p := float64(c) / n
+				h -= p * math.Log2(p)
+			}
+		}
+		return h
+	}
+	mean := s.sum / n
+	return math.Max(s.sumSq/n-mean*mean, 0)
+}
+
+// fill prediction of node from statistics
+func (t *Tree) leaf(s *stats) *Node {
+	node := &Node{Samples: s.n, Impurity: t.impurity(s)}
+	if t.regression {
+		node.Value = s.sum / float64(s.n)
+		return node
+	}
+	node.Proba = make(map[any]float64)
+	best := -1
+	for c, count := range s.counts {
+		if count > 0 {
+			node.Proba[t.classes[c]] = float64(count) / float64(s.n)
+		}
+		if best < 0 || count > s.counts[best] {
+			best = c
+		}
+	}
+	node.Value = t.classes[best]
+	return node
+}
+
+// split of node
+type split struct {
+	feature     int
+	threshold   float64
+	categorical bool
+	impurity    float64 //weighted impurity of children
+}
+
+func (t *Tree) build(idx []int, depth int) *Node {
+	s := t.newStats()
+	for _, i := range idx {
+		t.add(s, i, 1)
+	}
+	node := t.leaf(s)
+	if t.ctx.Err() != nil {
+		return node
+	}
+	if len(idx) < t.config.MinSamplesSplit || (t.config.MaxDepth > 0 && depth >= t.config.MaxDepth) || node.Impurity == 0 {
+		t.tracker.Step(len(idx))
+		return node
+	}
+	best := split{feature: -1, impurity: node.Impurity}
+	for f := 0; f < t.dim; f++ {
+		var sp split
+		var ok bool
+		if t.categorical[f] {
+			sp, ok = t.bestCategorical(idx, f, s)
+		} else {
+			sp, ok = t.bestNumeric(idx, f, s)
+		}
+		// splits must decrease impurity
+		if ok && sp.impurity < best.impurity-1e-12 {
+			best = sp
+		}
+	}
+	if best.feature < 0 {
+		t.tracker.Step(len(idx))
+		return node
+	}
+	left, right := make([]int, 0, len(idx)), make([]int, 0, len(idx))
+	for _, i := range idx {
+		if goesLeft(t.x[i][best.feature], best.threshold, best.categorical) {
+			left = append(left, i)
+		} else {
+			right = append(right, i)
+		}
+	}
+	node.Feature, node.Threshold, node.Categorical = best.feature, best.threshold, best.categorical
+	node.Left = t.build(left, depth+1)
+	node.Right = t.build(right, depth+1)
+	return node
+}
+
+func goesLeft(v, threshold float64, categorical bool) bool {
+	if categorical {
+		return v == threshold
+	}
+	return v <= threshold
+}
+
+// best threshold of a numeric feature by a sweep of sorted values, NaN values go right
+func (t *Tree) bestNumeric(idx []int, f int, total *stats) (split, bool) {
+	sorted := make([]int, 0, len(idx))
+	nan := make([]int, 0)
+	for _, i := range idx {
+		if math.IsNaN(t.x[i][f]) {
+			nan = append(nan, i)
+		} else {
+			sorted = append(sorted, i)
+		}
+	}
+	sort.Slice(sorted, func(a, b int) bool {
+		return t.x[sorted[a]][f] < t.x[sorted[b]][f]
+	})
+	left, right := t.newStats(), t.newStats()
+	for _, i := range idx {
+		t.add(right, i, 1)
+	}
+	best, found := split{feature: f, impurity: math.Inf(1)}, false
+	minLeaf := t.config.MinSamplesLeaf
+	n := float64(total.n)
+	for k := 0; k < len(sorted)-1; k++ {
+		t.add(left, sorted[k], 1)
+		t.add(right, sorted[k], -1)
+		v, next := t.x[sorted[k]][f], t.x[sorted[k+1]][f]
+		if v == next || left.n < minLeaf || right.n < minLeaf {
+			continue
+		}
+		imp := (float64(left.n)*t.impurity(left) + float64(right.n)*t.impurity(right)) / n
+		if imp < best.impurity {
+			best.impurity, best.threshold, found = imp, v+(next-v)/2, true
+		}
+	}
+	return best, found
+}
+
+// best category of a categorical feature, the category goes left and the rest right
+func (t *Tree) bestCategorical(idx []int, f int, total *stats) (split, bool) {
+	groups := make(map[float64]*stats)
+	values := make([]float64, 0, 10)
+	for _, i := range idx {
+		v := t.x[i][f]
+		g, ok := groups[v]
+		if !ok {
+			g = t.newStats()
+			groups[v] = g
+			values = append(values, v)
+		}
+		t.add(g, i, 1)
+	}
+	best, found := split{feature: f, categorical: true, impurity: math.Inf(1)}, false
+	minLeaf := t.config.MinSamplesLeaf
+	n := float64(total.n)
+	for _, v := range values {
+		left := groups[v]
+		right := t.newStats()
+		for _, i := range idx {
+			if t.x[i][f] != v {
+				t.add(right, i, 1)
+			}
+		}
+		if left.n < minLeaf || right.n < minLeaf {
+			continue
+		}
+		imp := (float64(left.n)*t.impurity(left) + float64(right.n)*t.impurity(right)) / n
+		if imp < best.impurity {
+			best.impurity, best.threshold, found = imp, v, true
+		}
+	}
+	return best, found
+}
+
+// leaf of point
+func (t *Tree) find(point knn.Point) *Node {
+	if t.root == nil {
+		panic(ErrNotFitted)
+	}
+	if len(point) != t.dim {
+		panic(ErrDimensionMismatch)
+	}
+	node := t.root
+	for !node.IsLeaf() {
+		if goesLeft(point[node.Feature], node.Threshold, node.Categorical) {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	return node
+}
+
+// Predict label of point, majority label for classification and mean for regression
+func (t *Tree) Predict(point knn.Point) any {
+	return t.find(point).Value
+}
+
+// Predict class probabilities of point from its leaf
+func (t *Tree) PredictProba(point knn.Point) map[any]float64 {
+	if t.regression {
+		panic(ErrNotClassifier)
+	}
+	return t.find(point).Proba
+}
+
+// Root of fitted tree
+func (t *Tree) Root() *Node {
+	return t.root
+}
+
+// Depth of fitted tree, a single leaf has depth zero
+func (t *Tree) Depth() int {
+	var depth func(n *Node) int
+	depth = func(n *Node) int {
+		if n == nil || n.IsLeaf() {
+			return 0
+		}
+		l, r := depth(n.Left), depth(n.Right)
+		if l > r {
+			return l + 1
+		}
+		return r + 1
+	}
+	return depth(t.root)
+}
+
+// Importance of every feature, the total decrease of weighted impurity of its splits normalized to sum one
+func (t *Tree) FeatureImportances() []float64 {
+	if t.root == nil {
+		panic(ErrNotFitted)
+	}
+	importances := make([]float64, t.dim)
+	var visit func(n *Node)
+	visit = func(n *Node) {
+		if n.IsLeaf() {
+			return
+		}
+		importances[n.Feature] += float64(n.Samples)*n.Impurity -
+			float64(n.Left.Samples)*n.Left.Impurity - float64(n.Right.Samples)*n.Right.Impurity
+		visit(n.Left)
+		visit(n.Right)
+	}
+	visit(t.root)
+	sum := 0.0
+	for _, v := range importances {
+		sum += v
+	}
+	if sum > 0 {
+		for i := range importances {
+			importances[i] /= sum
+		}
+	}
+	return importances
+}
+
+// Graph of fitted tree, every node has an edge to its children and node values are the tree nodes
+//
+// Split nodes are named by its feature and threshold and leaves by its prediction
+func (t *Tree) Graph() graph.Graph {
+	if t.root == nil {
+		panic(ErrNotFitted)
+	}
+	g := graph.New("tree")
+	var add func(n *Node) int
+	add = func(n *Node) int {
+		id := g.LenNodes()
+		var name string
+		switch {
+		case n.IsLeaf():
+			name = fmt.Sprintf("n%d_leaf_%v", id, n.Value)
+		case n.Categorical:
+			name = fmt.Sprintf("n%d_x%d_eq_%g", id, n.Feature, n.Threshold)
+		default:
+			name = fmt.Sprintf("n%d_x%d_le_%g", id, n.Feature, n.Threshold)
+		}
+		g.AddNode(name, n)
+		if !n.IsLeaf() {
+			g.AddEdge(id, add(n.Left))
+			g.AddEdge(id, add(n.Right))
+		}
+		return id
+	}
+	add(t.root)
+	return g
+}
+
+// Write DOT representation of fitted tree to file
+func (t *Tree) ToDot(fileName string) error {
+	g := t.Graph()
+	return g.ToDot(fileName)
+}
+
+type treeSpec struct {
+	Config     Config
+	Regression bool
+	Dim        int
+	Classes    []any
+	Root       *Node
+}
+
+// Encode configuration and nodes of tree, labels must be encodable by gob
+func (t *Tree) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&treeSpec{Config: t.config, Regression: t.regression, Dim: t.dim,
+		Classes: t.classes, Root: t.root})
+	return buf.Bytes(), err
+}
+
+// Decode tree encoded by GobEncode
+func (t *Tree) GobDecode(b []byte) error {
+	var spec treeSpec
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return err
+	}
+	*t = *newTree(spec.Config, spec.Regression)
+	t.dim, t.classes, t.root = spec.Dim, spec.Classes, spec.Root
+	return nil
+}
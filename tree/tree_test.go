@@ -0,0 +1,143 @@
+package tree
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+	"github.com/stellviaproject/go-ia/progress"
+)
+
+func accuracy(t *Tree, data []knn.DataPoint) float64 {
+	hits := 0
+	for _, dp := range data {
+		if t.Predict(dp.Point()) == dp.Label() {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(data))
+}
+
+func TestClassifier(t *testing.T) {
+	data := dataset.MakeMoons(400, 0.1, 1)
+	train, test := dataset.SplitTrainTest(data, 0.25, true, 1)
+	for _, criterion := range []Criterion{Gini, Entropy} {
+		tree := NewClassifier(Config{Criterion: criterion, MaxDepth: 8})
+		tree.Fit(train)
+		if acc := accuracy(tree, test); acc < 0.9 {
+			t.Errorf("Classifier failed. Expected accuracy greater than 0.9 with criterion %d, but got %v", criterion, acc)
+		}
+		if d := tree.Depth(); d > 8 {
+			t.Errorf("Classifier failed. Expected depth up to 8, but got %d", d)
+		}
+	}
+	stump := NewClassifier(Config{MaxDepth: 1})
+	stump.Fit(train)
+	if stump.Depth() != 1 {
+		t.Errorf("Classifier failed. Expected stump of depth 1, but got %d", stump.Depth())
+	}
+	proba := stump.PredictProba(test[0].Point())
+	if s := proba[0] + proba[1]; math.Abs(s-1) > 1e-9 {
+		t.Errorf("PredictProba failed. Expected probabilities summing one, but got %v", proba)
+	}
+}
+
+func TestFitContext(t *testing.T) {
+	data := dataset.MakeMoons(200, 0.1, 1)
+	tree := NewClassifier(Config{})
+	var reports []progress.Report
+	tree.OnProgress(func(r progress.Report) {
+		reports = append(reports, r)
+	})
+	if err := tree.FitContext(context.Background(), data); err != nil {
+		t.Fatal(err)
+	}
+	if last := reports[len(reports)-1]; last.Done != 200 || last.Percent != 100 {
+		t.Errorf("OnProgress failed. Expected every sample in leaves, but got %v", last)
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i].Done <= reports[i-1].Done {
+			t.Fatalf("OnProgress failed. Expected increasing progress, but got %v", reports)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tree.FitContext(ctx, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("FitContext failed. Expected %v, but got %v", context.Canceled, err)
+	}
+	if tree.Root() != nil {
+		t.Errorf("FitContext failed. Expected tree not fitted after cancellation")
+	}
+}
+
+func TestCategorical(t *testing.T) {
+	// label is "b" only for category 2 of feature 0, a numeric split needs two thresholds
+	data := make([]knn.DataPoint, 0, 30)
+	for i := 0; i < 30; i++ {
+		label := "a"
+		if i%3 == 2 {
+			label = "b"
+		}
+		data = append(data, knn.NewDataPoint(label, knn.Point{float64(i % 3), 0}))
+	}
+	tree := NewClassifier(Config{Categorical: []int{0}, MaxDepth: 1})
+	tree.Fit(data)
+	root := tree.Root()
+	if !root.Categorical || root.Threshold != 2 || accuracy(tree, data) != 1 {
+		t.Errorf("Categorical failed. Unexpected root split %v", root)
+	}
+}
+
+func TestRegressor(t *testing.T) {
+	data := make([]knn.DataPoint, 0, 200)
+	for i := 0; i < 200; i++ {
+		x := float64(i) / 20
+		data = append(data, knn.NewDataPoint(math.Sin(x), knn.Point{x}))
+	}
+	tree := NewRegressor(Config{Criterion: MSE, MinSamplesLeaf: 2})
+	tree.Fit(data)
+	for _, dp := range data {
+		if d := math.Abs(tree.Predict(dp.Point()).(float64) - dp.Label().(float64)); d > 0.05 {
+			t.Fatalf("Regressor failed. Error %v is too big at %v", d, dp.Point())
+		}
+	}
+	g := NewRegressor(Config{Criterion: MSE, MaxDepth: 2})
+	g.Fit(data)
+	dot := g.Graph()
+	if dot.LenNodes() != 7 || !strings.Contains(dot.String(), "n0_x0_le_") {
+		t.Errorf("Graph failed. Unexpected dot %s", dot.String())
+	}
+}
+
+func TestFeatureImportances(t *testing.T) {
+	data := dataset.MakeBlobs(200, []knn.Point{{0, 0, 0}, {5, 0, 0}}, 1, 1)
+	tree := NewClassifier(Config{MaxDepth: 3})
+	tree.Fit(data)
+	importances := tree.FeatureImportances()
+	if len(importances) != 3 || importances[0] < 0.8 {
+		t.Errorf("FeatureImportances failed. Expected greatest importance of feature 0, but got %v", importances)
+	}
+}
+
+func TestTreeGob(t *testing.T) {
+	data := dataset.MakeMoons(200, 0.1, 3)
+	tree := NewClassifier(Config{MaxDepth: 5})
+	tree.Fit(data)
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(tree); err != nil {
+		t.Fatal(err)
+	}
+	var loaded Tree
+	if err := gob.NewDecoder(&buf).Decode(&loaded); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := accuracy(tree, data), accuracy(&loaded, data); a != b {
+		t.Errorf("GobDecode failed. Expected accuracy %v, but got %v", a, b)
+	}
+}
@@ -0,0 +1,106 @@
+package tuning
+
+import (
+	"math"
+
+	"github.com/stellviaproject/go-ia/linalg"
+)
+
+// Gaussian process regression with RBF kernel over the unit cube and normalized targets
+type gp struct {
+	x           [][]float64
+	chol        *linalg.Matrix
+	alpha       []float64
+	mean, scale float64
+	length      float64
+	noise       float64
+}
+
+func rbf(a, b []float64, length float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Exp(-sum / (2 * length * length))
+}
+
+// fit gaussian process choosing length scale with greatest marginal likelihood
+func fitGP(x [][]float64, y []float64, noise float64) *gp {
+	mean, scale := 0.0, 0.0
+	for _, v := range y {
+		mean += v
+	}
+	mean /= float64(len(y))
+	for _, v := range y {
+		scale += (v - mean) * (v - mean)
+	}
+	scale = math.Sqrt(scale / float64(len(y)))
+	if scale == 0 {
+		scale = 1
+	}
+	z := make([]float64, len(y))
+	for i, v := range y {
+		z[i] = (v - mean) / scale
+	}
+	var best *gp
+	bestLik := math.Inf(-1)
+	for _, length := range []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.8, 1.2} {
+		g := &gp{x: x, mean: mean, scale: scale, length: length, noise: noise}
+		lik, ok := g.fit(z)
+		if ok && lik > bestLik {
+			best, bestLik = g, lik
+		}
+	}
+	return best
+}
+
+// fit to normalized targets and return log marginal likelihood
+func (g *gp) fit(z []float64) (float64, bool) {
+	n := len(g.x)
+	k := linalg.NewMatrix(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			v := rbf(g.x[i], g.x[j], g.length)
+			k.Set(i, j, v)
+			k.Set(j, i, v)
+		}
+		k.Set(i, i, 1+g.noise)
+	}
+	chol, err := k.Cholesky()
+	if err != nil {
+		return 0, false
+	}
+	g.chol = chol
+	g.alpha = linalg.CholeskySolve(chol, z)
+	lik := 0.0
+	for i := 0; i < n; i++ {
+		lik -= 0.5*z[i]*g.alpha[i] + math.Log(chol.At(i, i))
+	}
+	return lik, true
+}
+
+// predictive mean and deviation at point
+func (g *gp) predict(u []float64) (float64, float64) {
+	k := make([]float64, len(g.x))
+	mu := 0.0
+	for i, x := range g.x {
+		k[i] = rbf(u, x, g.length)
+		mu += k[i] * g.alpha[i]
+	}
+	v := linalg.CholeskySolve(g.chol, k)
+	variance := 1.0
+	for i := range k {
+		variance -= k[i] * v[i]
+	}
+	return g.mean + g.scale*mu, g.scale * math.Sqrt(math.Max(variance, 1e-12))
+}
+
+// expected improvement over best value when maximizing
+func expectedImprovement(mu, sigma, best, xi float64) float64 {
+	d := mu - best - xi
+	z := d / sigma
+	cdf := 0.5 * math.Erfc(-z/math.Sqrt2)
+	pdf := math.Exp(-0.5*z*z) / math.Sqrt(2*math.Pi)
+	return d*cdf + sigma*pdf
+}
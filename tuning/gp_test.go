@@ -0,0 +1,29 @@
+package tuning
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGP(t *testing.T) {
+	x := [][]float64{{0}, {0.25}, {0.5}, {0.75}, {1}}
+	y := make([]float64, len(x))
+	for i, p := range x {
+		y[i] = math.Sin(2 * math.Pi * p[0])
+	}
+	g := fitGP(x, y, 1e-6)
+	for i, p := range x {
+		if mu, sigma := g.predict(p); math.Abs(mu-y[i]) > 1e-3 || sigma > 1e-2 {
+			t.Errorf("GP failed. Expected %v without uncertainty, but got %v and deviation %v", y[i], mu, sigma)
+		}
+	}
+	// uncertainty grows away from data
+	_, near := g.predict([]float64{0.6})
+	_, far := g.predict([]float64{3})
+	if near >= far {
+		t.Errorf("GP failed. Expected greater deviation far from data, but got %v and %v", near, far)
+	}
+	if ei := expectedImprovement(0, 1, 0, 0); math.Abs(ei-1/math.Sqrt(2*math.Pi)) > 1e-12 {
+		t.Errorf("ExpectedImprovement failed. Expected %v, but got %v", 1/math.Sqrt(2*math.Pi), ei)
+	}
+}
@@ -0,0 +1,96 @@
+// Package tuning implements bayesian optimization of hyperparameters
+package tuning
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrSpaceNotValid  = errors.New("search space is not valid")
+	ErrConfigNotValid = errors.New("tuner configuration is not valid")
+	ErrParamNotFound  = errors.New("parameter is not in search space")
+)
+
+// Hyperparameter in range [Lo, Hi]
+type Param struct {
+	Name    string
+	Lo, Hi  float64
+	Log     bool //search in logarithmic scale, Lo must be positive
+	Integer bool //values are rounded to integers
+}
+
+// Create float parameter
+func Float(name string, lo, hi float64) Param {
+	return Param{Name: name, Lo: lo, Hi: hi}
+}
+
+// Create float parameter searched in logarithmic scale, like learning rates or regularization
+func LogFloat(name string, lo, hi float64) Param {
+	return Param{Name: name, Lo: lo, Hi: hi, Log: true}
+}
+
+// Create integer parameter, like k of knn or depth of trees
+func Int(name string, lo, hi int) Param {
+	return Param{Name: name, Lo: float64(lo), Hi: float64(hi), Integer: true}
+}
+
+func checkSpace(space []Param) {
+	if len(space) == 0 {
+		panic(ErrSpaceNotValid)
+	}
+	names := make(map[string]bool, len(space))
+	for _, p := range space {
+		if p.Lo > p.Hi || p.Log && p.Lo <= 0 || names[p.Name] {
+			panic(ErrSpaceNotValid)
+		}
+		names[p.Name] = true
+	}
+}
+
+// value of parameter at position u in [0, 1]
+func (p Param) decode(u float64) float64 {
+	lo, hi := p.Lo, p.Hi
+	if p.Integer {
+		// every integer takes an equal part of unit range
+		lo, hi = lo-0.5+1e-9, hi+0.5-1e-9
+	}
+	var v float64
+	if p.Log {
+		v = math.Exp(math.Log(lo) + u*(math.Log(hi)-math.Log(lo)))
+	} else {
+		v = lo + u*(hi-lo)
+	}
+	if p.Integer {
+		v = math.Max(p.Lo, math.Min(p.Hi, math.Round(v)))
+	}
+	return v
+}
+
+// position in [0, 1] of parameter value
+func (p Param) encode(v float64) float64 {
+	if p.Hi == p.Lo {
+		return 0.5
+	}
+	if p.Log {
+		return (math.Log(v) - math.Log(p.Lo)) / (math.Log(p.Hi) - math.Log(p.Lo))
+	}
+	return (v - p.Lo) / (p.Hi - p.Lo)
+}
+
+// Values of hyperparameters by name
+type Params map[string]float64
+
+// Get float value of parameter
+func (ps Params) Float(name string) float64 {
+	v, ok := ps[name]
+	if !ok {
+		panic(ErrParamNotFound)
+	}
+	return v
+}
+
+// Get integer value of parameter
+func (ps Params) Int(name string) int {
+	return int(math.Round(ps.Float(name)))
+}
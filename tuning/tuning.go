@@ -0,0 +1,188 @@
+package tuning
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Trial of hyperparameters with its score
+type Trial struct {
+	Params Params
+	Score  float64
+}
+
+// Configuration of tuner, zero fields take the default values
+type Config struct {
+	Trials     int     //evaluations of objective, 30 by default
+	Initial    int     //random trials before the gaussian process is used, 5 by default
+	Candidates int     //random candidates scored by expected improvement every trial, 1000 by default
+	Xi         float64 //exploration margin of expected improvement, 0.01 by default
+	Seed       int64
+}
+
+func (config *Config) defaults() {
+	if config.Trials == 0 {
+		config.Trials = 30
+	}
+	if config.Initial == 0 {
+		config.Initial = 5
+	}
+	if config.Candidates == 0 {
+		config.Candidates = 1000
+	}
+	if config.Xi == 0 {
+		config.Xi = 0.01
+	}
+	if config.Trials < 1 || config.Initial < 1 || config.Candidates < 1 || config.Xi < 0 {
+		panic(ErrConfigNotValid)
+	}
+}
+
+// Bayesian optimizer of hyperparameters with a gaussian process and expected improvement
+type Tuner struct {
+	space  []Param
+	config Config
+	rnd    *rand.Rand
+	x      [][]float64
+	trials []Trial
+	seen   map[string]bool
+}
+
+// Create tuner of hyperparameters in search space
+func NewTuner(space []Param, config Config) *Tuner {
+	checkSpace(space)
+	config.defaults()
+	return &Tuner{space: space, config: config, rnd: rand.New(rand.NewSource(config.Seed)), seen: make(map[string]bool)}
+}
+
+func (t *Tuner) decode(u []float64) Params {
+	ps := make(Params, len(t.space))
+	for i, p := range t.space {
+		ps[p.Name] = p.decode(u[i])
+	}
+	return ps
+}
+
+// key of decoded values, integer parameters make different positions equal
+func (t *Tuner) key(ps Params) string {
+	b := make([]byte, 0, 8*len(t.space))
+	for _, p := range t.space {
+		bits := math.Float64bits(ps[p.Name])
+		for i := 0; i < 8; i++ {
+			b = append(b, byte(bits>>(8*i)))
+		}
+	}
+	return string(b)
+}
+
+func (t *Tuner) random() []float64 {
+	u := make([]float64, len(t.space))
+	for i := range u {
+		u[i] = t.rnd.Float64()
+	}
+	return u
+}
+
+// Suggest next hyperparameters to evaluate
+func (t *Tuner) Suggest() Params {
+	if len(t.trials) < t.config.Initial {
+		return t.decode(t.random())
+	}
+	y := make([]float64, len(t.trials))
+	best := math.Inf(-1)
+	for i, trial := range t.trials {
+		y[i] = trial.Score
+		best = math.Max(best, trial.Score)
+	}
+	g := fitGP(t.x, y, 1e-6)
+	if g == nil {
+		return t.decode(t.random())
+	}
+	// half of candidates are uniform and half are near best trial
+	top := t.x[t.bestIndex()]
+	var choice Params
+	bestEI := math.Inf(-1)
+	for c := 0; c < t.config.Candidates; c++ {
+		u := t.random()
+		if c%2 == 1 {
+			for i := range u {
+				u[i] = math.Max(0, math.Min(1, top[i]+0.1*t.rnd.NormFloat64()))
+			}
+		}
+		ps := t.decode(u)
+		if t.seen[t.key(ps)] {
+			continue
+		}
+		mu, sigma := g.predict(t.position(ps))
+		if ei := expectedImprovement(mu, sigma, best, t.config.Xi); ei > bestEI {
+			choice, bestEI = ps, ei
+		}
+	}
+	if choice == nil {
+		return t.decode(t.random())
+	}
+	return choice
+}
+
+// position of values in unit cube
+func (t *Tuner) position(ps Params) []float64 {
+	u := make([]float64, len(t.space))
+	for i, p := range t.space {
+		u[i] = p.encode(ps[p.Name])
+	}
+	return u
+}
+
+// Observe score of evaluated hyperparameters, greater score is better
+func (t *Tuner) Observe(ps Params, score float64) {
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		// failed trials are kept with worst score seen so model avoids them
+		score = math.Inf(1)
+		for _, trial := range t.trials {
+			score = math.Min(score, trial.Score)
+		}
+		if math.IsInf(score, 1) {
+			score = 0
+		}
+	}
+	t.x = append(t.x, t.position(ps))
+	t.trials = append(t.trials, Trial{Params: ps, Score: score})
+	t.seen[t.key(ps)] = true
+}
+
+func (t *Tuner) bestIndex() int {
+	best := 0
+	for i, trial := range t.trials {
+		if trial.Score > t.trials[best].Score {
+			best = i
+		}
+	}
+	return best
+}
+
+// Best trial observed
+func (t *Tuner) Best() Trial {
+	if len(t.trials) == 0 {
+		panic(ErrConfigNotValid)
+	}
+	return t.trials[t.bestIndex()]
+}
+
+// Trials observed in order of evaluation
+func (t *Tuner) Trials() []Trial {
+	return t.trials
+}
+
+// Maximize objective over search space, objective trains and evaluates a model with given hyperparameters
+// and returns its score; it returns best trial and all trials sorted by decreasing score
+func Optimize(space []Param, objective func(Params) float64, config Config) (Trial, []Trial) {
+	t := NewTuner(space, config)
+	for i := 0; i < t.config.Trials; i++ {
+		ps := t.Suggest()
+		t.Observe(ps, objective(ps))
+	}
+	trials := append([]Trial(nil), t.trials...)
+	sort.SliceStable(trials, func(i, j int) bool { return trials[i].Score > trials[j].Score })
+	return trials[0], trials
+}
@@ -0,0 +1,80 @@
+package tuning
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/dataset"
+	"github.com/stellviaproject/go-ia/knn"
+)
+
+// negative branin function, maximum is -0.397887
+func branin(ps Params) float64 {
+	x, y := ps.Float("x"), ps.Float("y")
+	a := y - 5.1/(4*math.Pi*math.Pi)*x*x + 5/math.Pi*x - 6
+	return -(a*a + 10*(1-1/(8*math.Pi))*math.Cos(x) + 10)
+}
+
+func TestOptimize(t *testing.T) {
+	space := []Param{Float("x", -5, 10), Float("y", 0, 15)}
+	best, trials := Optimize(space, branin, Config{Trials: 30, Seed: 1})
+	if len(trials) != 30 || trials[0].Score != best.Score {
+		t.Fatalf("Optimize failed. Expected 30 trials sorted by score")
+	}
+	if best.Score < -0.5 {
+		t.Errorf("Optimize failed. Expected score near -0.398, but got %v at %v", best.Score, best.Params)
+	}
+	// random search with same budget is worse
+	rnd := rand.New(rand.NewSource(1))
+	random := math.Inf(-1)
+	for i := 0; i < 30; i++ {
+		random = math.Max(random, branin(Params{"x": -5 + 15*rnd.Float64(), "y": 15 * rnd.Float64()}))
+	}
+	if best.Score <= random {
+		t.Errorf("Optimize failed. Expected better score than random search %v, but got %v", random, best.Score)
+	}
+}
+
+func TestSpace(t *testing.T) {
+	k := Int("k", 1, 3)
+	counts := make(map[float64]int)
+	for u := 0.0; u <= 1; u += 0.001 {
+		counts[k.decode(u)]++
+	}
+	if len(counts) != 3 || counts[1] < 300 || counts[2] < 300 || counts[3] < 300 {
+		t.Errorf("Int failed. Expected uniform integers 1, 2 and 3, but got %v", counts)
+	}
+	rate := LogFloat("rate", 1e-4, 1)
+	if v := rate.decode(0.5); math.Abs(v-1e-2) > 1e-12 {
+		t.Errorf("LogFloat failed. Expected 0.01 at middle, but got %v", v)
+	}
+	if u := rate.encode(1e-3); math.Abs(u-0.25) > 1e-12 {
+		t.Errorf("LogFloat failed. Expected position 0.25, but got %v", u)
+	}
+}
+
+func TestTuneKNN(t *testing.T) {
+	data := dataset.MakeMoons(200, 0.3, 1)
+	objective := func(ps Params) float64 {
+		config := knn.Config{K: ps.Int("k"), Dist: knn.NewEuclideanDist(), Selector: knn.NewMultiClassSelector()}
+		return knn.CrossValidate(data, config, 5, knn.Accuracy, 1).Mean
+	}
+	tuner := NewTuner([]Param{Int("k", 1, 40)}, Config{Seed: 2})
+	for i := 0; i < 12; i++ {
+		ps := tuner.Suggest()
+		tuner.Observe(ps, objective(ps))
+	}
+	// integer values are never suggested twice by the model
+	seen := make(map[int]bool)
+	for _, trial := range tuner.Trials()[5:] {
+		if seen[trial.Params.Int("k")] {
+			t.Errorf("Suggest failed. Value k=%d was suggested twice", trial.Params.Int("k"))
+		}
+		seen[trial.Params.Int("k")] = true
+	}
+	best := tuner.Best()
+	if best.Score < 0.85 || best.Params.Int("k") == 1 {
+		t.Errorf("Tuner failed. Expected accuracy greater than 0.85, but got %v with k=%v", best.Score, best.Params.Int("k"))
+	}
+}
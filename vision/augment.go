@@ -0,0 +1,213 @@
+package vision
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Augmentation of CHW image tensors drawing random values from rnd
+type Augmentation interface {
+	Apply(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor
+}
+
+// Function implementing Augmentation
+type AugmentFunc func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor
+
+func (fn AugmentFunc) Apply(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+	return fn(t, rnd)
+}
+
+// Create augmentation applying augmentations in order
+func Compose(augs ...Augmentation) Augmentation {
+	return AugmentFunc(func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+		for _, aug := range augs {
+			t = aug.Apply(t, rnd)
+		}
+		return t
+	})
+}
+
+// Create augmentation applying aug with probability p
+func RandomApply(aug Augmentation, p float64) Augmentation {
+	return AugmentFunc(func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+		if rnd.Float64() < p {
+			return aug.Apply(t, rnd)
+		}
+		return t
+	})
+}
+
+// Create augmentation mirroring images left to right with probability p
+func RandomHorizontalFlip(p float64) Augmentation {
+	return AugmentFunc(func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+		if rnd.Float64() >= p {
+			return t
+		}
+		src := fromTensor(t)
+		dst := newPlanes(src.c, src.h, src.w)
+		for c := 0; c < src.c; c++ {
+			for y := 0; y < src.h; y++ {
+				for x := 0; x < src.w; x++ {
+					dst.set(c, y, x, src.at(c, y, src.w-1-x))
+				}
+			}
+		}
+		return dst.tensor()
+	})
+}
+
+// Create augmentation mirroring images top to bottom with probability p
+func RandomVerticalFlip(p float64) Augmentation {
+	return AugmentFunc(func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+		if rnd.Float64() >= p {
+			return t
+		}
+		src := fromTensor(t)
+		dst := newPlanes(src.c, src.h, src.w)
+		for c := 0; c < src.c; c++ {
+			for y := 0; y < src.h; y++ {
+				for x := 0; x < src.w; x++ {
+					dst.set(c, y, x, src.at(c, src.h-1-y, x))
+				}
+			}
+		}
+		return dst.tensor()
+	})
+}
+
+// Create augmentation cropping a random region of height and width after zero padding of borders
+func RandomCrop(height, width, padding int) Augmentation {
+	return AugmentFunc(func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+		src := fromTensor(t)
+		h, w := src.h+2*padding, src.w+2*padding
+		if height > h || width > w {
+			panic(ErrSizeNotValid)
+		}
+		top, left := rnd.Intn(h-height+1)-padding, rnd.Intn(w-width+1)-padding
+		dst := newPlanes(src.c, height, width)
+		for c := 0; c < src.c; c++ {
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					if sy, sx := top+y, left+x; sy >= 0 && sy < src.h && sx >= 0 && sx < src.w {
+						dst.set(c, y, x, src.at(c, sy, sx))
+					}
+				}
+			}
+		}
+		return dst.tensor()
+	})
+}
+
+// Create augmentation rotating images around their center by a random angle in [-degrees, degrees],
+// with bilinear sampling and zero outside of image
+func RandomRotation(degrees float64) Augmentation {
+	return AugmentFunc(func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+		angle := (rnd.Float64()*2 - 1) * degrees * math.Pi / 180
+		return rotate(fromTensor(t), angle).tensor()
+	})
+}
+
+func rotate(src *planes, angle float64) *planes {
+	dst := newPlanes(src.c, src.h, src.w)
+	sin, cos := math.Sin(angle), math.Cos(angle)
+	cy, cx := float64(src.h-1)/2, float64(src.w-1)/2
+	for y := 0; y < src.h; y++ {
+		for x := 0; x < src.w; x++ {
+			// source position by inverse rotation
+			dy, dx := float64(y)-cy, float64(x)-cx
+			sy := cos*dy - sin*dx + cy
+			sx := sin*dy + cos*dx + cx
+			y0, x0 := int(math.Floor(sy)), int(math.Floor(sx))
+			wy, wx := float32(sy-float64(y0)), float32(sx-float64(x0))
+			for c := 0; c < src.c; c++ {
+				pixel := func(y, x int) float32 {
+					if y < 0 || y >= src.h || x < 0 || x >= src.w {
+						return 0
+					}
+					return src.at(c, y, x)
+				}
+				top := pixel(y0, x0)*(1-wx) + pixel(y0, x0+1)*wx
+				bottom := pixel(y0+1, x0)*(1-wx) + pixel(y0+1, x0+1)*wx
+				dst.set(c, y, x, top*(1-wy)+bottom*wy)
+			}
+		}
+	}
+	return dst
+}
+
+// Create augmentation changing brightness, contrast and saturation by random factors in [1 - x, 1 + x],
+// values are kept in [0, 1] and saturation is changed only in three channel images
+func ColorJitter(brightness, contrast, saturation float64) Augmentation {
+	return AugmentFunc(func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+		p := fromTensor(t)
+		factor := func(x float64) float32 {
+			return float32(1 + (rnd.Float64()*2-1)*x)
+		}
+		b, c, s := factor(brightness), factor(contrast), factor(saturation)
+		plane := p.h * p.w
+		for i := range p.data {
+			p.data[i] *= b
+		}
+		mean := float32(0)
+		for _, v := range p.data {
+			mean += v
+		}
+		mean /= float32(len(p.data))
+		for i := range p.data {
+			p.data[i] = mean + (p.data[i]-mean)*c
+		}
+		if p.c == 3 {
+			// blend with gray levels of pixels
+			for i := 0; i < plane; i++ {
+				gray := 0.299*p.data[i] + 0.587*p.data[plane+i] + 0.114*p.data[2*plane+i]
+				for ch := 0; ch < 3; ch++ {
+					p.data[ch*plane+i] = gray + (p.data[ch*plane+i]-gray)*s
+				}
+			}
+		}
+		for i, v := range p.data {
+			p.data[i] = float32(math.Max(0, math.Min(1, float64(v))))
+		}
+		return p.tensor()
+	})
+}
+
+// Create augmentation setting to zero a random square of size whose center is inside image
+func Cutout(size int) Augmentation {
+	return AugmentFunc(func(t *graph.Tensor, rnd *rand.Rand) *graph.Tensor {
+		p := fromTensor(t)
+		cy, cx := rnd.Intn(p.h), rnd.Intn(p.w)
+		for c := 0; c < p.c; c++ {
+			for y := cy - size/2; y < cy-size/2+size; y++ {
+				for x := cx - size/2; x < cx-size/2+size; x++ {
+					if y >= 0 && y < p.h && x >= 0 && x < p.w {
+						p.set(c, y, x, 0)
+					}
+				}
+			}
+		}
+		return p.tensor()
+	})
+}
+
+// Augmenter applies an augmentation with its own seeded random generator, it is safe for concurrent use
+type Augmenter struct {
+	aug Augmentation
+	rnd *rand.Rand
+	mu  sync.Mutex
+}
+
+// Create augmenter of composed augmentations
+func NewAugmenter(seed int64, augs ...Augmentation) *Augmenter {
+	return &Augmenter{aug: Compose(augs...), rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Augment tensor
+func (a *Augmenter) Apply(t *graph.Tensor) *graph.Tensor {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.aug.Apply(t, a.rnd)
+}
@@ -0,0 +1,66 @@
+package vision
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFlip(t *testing.T) {
+	ts := grayTensor([][]float32{{1, 2, 3}, {4, 5, 6}})
+	rnd := rand.New(rand.NewSource(1))
+	h := fromTensor(RandomHorizontalFlip(1).Apply(ts, rnd))
+	if h.at(0, 0, 0) != 3 || h.at(0, 1, 2) != 4 {
+		t.Errorf("RandomHorizontalFlip failed. Unexpected image %v", h.data)
+	}
+	v := fromTensor(RandomVerticalFlip(1).Apply(ts, rnd))
+	if v.at(0, 0, 0) != 4 || v.at(0, 1, 2) != 3 {
+		t.Errorf("RandomVerticalFlip failed. Unexpected image %v", v.data)
+	}
+	if RandomHorizontalFlip(0).Apply(ts, rnd) != ts {
+		t.Errorf("RandomHorizontalFlip failed. Expected unchanged tensor with probability 0")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	ts := grayTensor([][]float32{{1, 0, 0}, {0, 0, 0}, {0, 0, 0}})
+	// rotation by 90 degrees moves top left corner to another corner
+	p := rotate(fromTensor(ts), math.Pi/2)
+	sum := p.at(0, 0, 2) + p.at(0, 2, 0)
+	if math.Abs(float64(sum)-1) > 1e-5 || p.at(0, 0, 0) > 1e-5 {
+		t.Errorf("rotate failed. Unexpected image %v", p.data)
+	}
+	if r := fromTensor(RandomRotation(0).Apply(ts, rand.New(rand.NewSource(1)))); r.at(0, 0, 0) != 1 {
+		t.Errorf("RandomRotation failed. Expected unchanged image for zero degrees, but got %v", r.data)
+	}
+}
+
+func TestAugmenter(t *testing.T) {
+	p := newPlanes(3, 8, 8)
+	for i := range p.data {
+		p.data[i] = 0.5
+	}
+	ts := p.tensor()
+	aug := NewAugmenter(7, RandomCrop(6, 6, 2), ColorJitter(0.4, 0.4, 0.4), Cutout(3))
+	out := fromTensor(aug.Apply(ts))
+	if out.c != 3 || out.h != 6 || out.w != 6 {
+		t.Fatalf("Augmenter failed. Expected shape (3, 6, 6), but got (%v, %v, %v)", out.c, out.h, out.w)
+	}
+	zeros := 0
+	for _, v := range out.data {
+		if v < 0 || v > 1 {
+			t.Fatalf("ColorJitter failed. Value %v out of range", v)
+		}
+		if v == 0 {
+			zeros++
+		}
+	}
+	if zeros == 0 {
+		t.Errorf("Cutout failed. Expected zero pixels")
+	}
+	// same seed gives same augmentations
+	again := NewAugmenter(7, RandomCrop(6, 6, 2), ColorJitter(0.4, 0.4, 0.4), Cutout(3))
+	if !again.Apply(ts).Equal(out.tensor()) {
+		t.Errorf("Augmenter failed. Expected same result with same seed")
+	}
+}
@@ -0,0 +1,208 @@
+// Package vision converts images to tensors and implements the standard input pipeline of vision models
+package vision
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"os"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+var (
+	ErrNotImage      = errors.New("tensor is not an image of shape (channels, height, width)")
+	ErrSizeNotValid  = errors.New("image size is not valid")
+	ErrChannelsCount = errors.New("number of channels doesn't match")
+)
+
+// Layout of dimensions of image tensors
+type Layout int
+
+const (
+	CHW Layout = iota //channels, height, width
+	HWC               //height, width, channels
+)
+
+// Image of float values stored by channel planes, the internal form of CHW tensors
+type planes struct {
+	c, h, w int
+	data    []float32 //index c*h*w + y*w + x
+}
+
+func newPlanes(c, h, w int) *planes {
+	if c <= 0 || h <= 0 || w <= 0 {
+		panic(ErrSizeNotValid)
+	}
+	return &planes{c: c, h: h, w: w, data: make([]float32, c*h*w)}
+}
+
+func (p *planes) at(c, y, x int) float32 {
+	return p.data[(c*p.h+y)*p.w+x]
+}
+
+func (p *planes) set(c, y, x int, v float32) {
+	p.data[(c*p.h+y)*p.w+x] = v
+}
+
+// planes of a CHW tensor of float32 or float64 elements
+func fromTensor(t *graph.Tensor) *planes {
+	shape := t.Shape()
+	if shape.Dim() != 3 {
+		panic(ErrNotImage)
+	}
+	p := newPlanes(shape[0], shape[1], shape[2])
+	index := make([]int, 3)
+	for c := 0; c < p.c; c++ {
+		index[0] = c
+		for y := 0; y < p.h; y++ {
+			index[1] = y
+			for x := 0; x < p.w; x++ {
+				index[2] = x
+				switch v := t.Get(index).(type) {
+				case float32:
+					p.set(c, y, x, v)
+				case float64:
+					p.set(c, y, x, float32(v))
+				default:
+					p.set(c, y, x, float32(t.GetF16At(index).ToF64()))
+				}
+			}
+		}
+	}
+	return p
+}
+
+// CHW tensor of float32 elements
+func (p *planes) tensor() *graph.Tensor {
+	t := graph.NewTensor(nil, graph.Float32, graph.NewShape(p.c, p.h, p.w))
+	index := make([]int, 3)
+	for c := 0; c < p.c; c++ {
+		index[0] = c
+		for y := 0; y < p.h; y++ {
+			index[1] = y
+			for x := 0; x < p.w; x++ {
+				index[2] = x
+				t.SetF32(index, p.at(c, y, x))
+			}
+		}
+	}
+	return t
+}
+
+// Decode image in PNG, JPEG or GIF format
+func Load(r io.Reader) (image.Image, error) {
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
+// Decode image file in PNG, JPEG or GIF format
+func LoadFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Convert image to float32 tensor with values in [0, 1], gray images have one channel and others have
+// three RGB channels
+func ToTensor(img image.Image, layout Layout) *graph.Tensor {
+	b := img.Bounds()
+	gray := false
+	switch img.ColorModel() {
+	case color.GrayModel, color.Gray16Model:
+		gray = true
+	}
+	channels := 3
+	if gray {
+		channels = 1
+	}
+	p := newPlanes(channels, b.Dy(), b.Dx())
+	for y := 0; y < p.h; y++ {
+		for x := 0; x < p.w; x++ {
+			col := img.At(b.Min.X+x, b.Min.Y+y)
+			if gray {
+				g := color.Gray16Model.Convert(col).(color.Gray16)
+				p.set(0, y, x, float32(g.Y)/0xffff)
+				continue
+			}
+			r, g, bl, _ := col.RGBA()
+			p.set(0, y, x, float32(r)/0xffff)
+			p.set(1, y, x, float32(g)/0xffff)
+			p.set(2, y, x, float32(bl)/0xffff)
+		}
+	}
+	t := p.tensor()
+	if layout == HWC {
+		return ToHWC(t)
+	}
+	return t
+}
+
+// Convert tensor with values in [0, 1] and one or three channels to image, values out of range are clamped
+func FromTensor(t *graph.Tensor, layout Layout) image.Image {
+	if layout == HWC {
+		t = ToCHW(t)
+	}
+	p := fromTensor(t)
+	if p.c != 1 && p.c != 3 {
+		panic(ErrChannelsCount)
+	}
+	unit := func(v float32) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, float64(v))) * 255))
+	}
+	if p.c == 1 {
+		img := image.NewGray(image.Rect(0, 0, p.w, p.h))
+		for y := 0; y < p.h; y++ {
+			for x := 0; x < p.w; x++ {
+				img.SetGray(x, y, color.Gray{Y: unit(p.at(0, y, x))})
+			}
+		}
+		return img
+	}
+	img := image.NewRGBA(image.Rect(0, 0, p.w, p.h))
+	for y := 0; y < p.h; y++ {
+		for x := 0; x < p.w; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: unit(p.at(0, y, x)), G: unit(p.at(1, y, x)), B: unit(p.at(2, y, x)), A: 255})
+		}
+	}
+	return img
+}
+
+// permute dimensions of a rank 3 tensor, element at index is moved to index permuted by perm
+func permute(t *graph.Tensor, perm [3]int) *graph.Tensor {
+	shape := t.Shape()
+	if shape.Dim() != 3 {
+		panic(ErrNotImage)
+	}
+	out := graph.NewTensor(nil, t.Type(), graph.NewShape(shape[perm[0]], shape[perm[1]], shape[perm[2]]))
+	src, dst := make([]int, 3), make([]int, 3)
+	for src[0] = 0; src[0] < shape[0]; src[0]++ {
+		for src[1] = 0; src[1] < shape[1]; src[1]++ {
+			for src[2] = 0; src[2] < shape[2]; src[2]++ {
+				for i, p := range perm {
+					dst[i] = src[p]
+				}
+				out.Set(dst, t.Get(src))
+			}
+		}
+	}
+	return out
+}
+
+// Convert CHW tensor to HWC layout
+func ToHWC(t *graph.Tensor) *graph.Tensor {
+	return permute(t, [3]int{1, 2, 0})
+}
+
+// Convert HWC tensor to CHW layout
+func ToCHW(t *graph.Tensor) *graph.Tensor {
+	return permute(t, [3]int{2, 0, 1})
+}
@@ -0,0 +1,57 @@
+package vision
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func testImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(60 * x), G: uint8(100 * y), B: 255, A: 255})
+		}
+	}
+	return img
+}
+
+func TestToTensor(t *testing.T) {
+	img := testImage()
+	ts := ToTensor(img, CHW)
+	if shape := ts.Shape(); shape[0] != 3 || shape[1] != 3 || shape[2] != 4 {
+		t.Fatalf("ToTensor failed. Expected shape (3, 3, 4), but got %v", shape)
+	}
+	if v := ts.GetF32At([]int{0, 1, 2}); v != 120.0/255 {
+		t.Errorf("ToTensor failed. Expected red %v, but got %v", 120.0/255, v)
+	}
+	hwc := ToTensor(img, HWC)
+	if v := hwc.GetF32At([]int{2, 1, 1}); v != 200.0/255 {
+		t.Errorf("ToTensor failed. Expected green %v, but got %v", 200.0/255, v)
+	}
+	back := FromTensor(hwc, HWC).(*image.RGBA)
+	if !bytes.Equal(back.Pix, img.Pix) {
+		t.Errorf("FromTensor failed. Image changed in round trip")
+	}
+	gray := image.NewGray(image.Rect(0, 0, 2, 2))
+	gray.SetGray(1, 0, color.Gray{Y: 255})
+	if ts := ToTensor(gray, CHW); ts.Shape()[0] != 1 || ts.GetF32At([]int{0, 0, 1}) != 1 {
+		t.Errorf("ToTensor failed. Expected one channel with white pixel")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	img, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 4 || b.Dy() != 3 {
+		t.Errorf("Load failed. Expected 4x3 image, but got %v", b)
+	}
+}
@@ -0,0 +1,121 @@
+package vision
+
+import (
+	"math"
+
+	"github.com/stellviaproject/go-ia/nn/data"
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// Resize CHW tensor to height and width with bilinear interpolation, pixel centers are aligned
+func Resize(t *graph.Tensor, height, width int) *graph.Tensor {
+	src := fromTensor(t)
+	dst := newPlanes(src.c, height, width)
+	sy := float64(src.h) / float64(height)
+	sx := float64(src.w) / float64(width)
+	for y := 0; y < height; y++ {
+		fy := math.Max(0, (float64(y)+0.5)*sy-0.5)
+		y0 := int(fy)
+		y1 := y0 + 1
+		if y1 >= src.h {
+			y0, y1 = src.h-1, src.h-1
+		}
+		wy := float32(fy - float64(y0))
+		for x := 0; x < width; x++ {
+			fx := math.Max(0, (float64(x)+0.5)*sx-0.5)
+			x0 := int(fx)
+			x1 := x0 + 1
+			if x1 >= src.w {
+				x0, x1 = src.w-1, src.w-1
+			}
+			wx := float32(fx - float64(x0))
+			for c := 0; c < src.c; c++ {
+				top := src.at(c, y0, x0)*(1-wx) + src.at(c, y0, x1)*wx
+				bottom := src.at(c, y1, x0)*(1-wx) + src.at(c, y1, x1)*wx
+				dst.set(c, y, x, top*(1-wy)+bottom*wy)
+			}
+		}
+	}
+	return dst.tensor()
+}
+
+// Crop CHW tensor to region of height and width with top left corner at (top, left)
+func Crop(t *graph.Tensor, top, left, height, width int) *graph.Tensor {
+	src := fromTensor(t)
+	if top < 0 || left < 0 || height <= 0 || width <= 0 || top+height > src.h || left+width > src.w {
+		panic(ErrSizeNotValid)
+	}
+	dst := newPlanes(src.c, height, width)
+	for c := 0; c < src.c; c++ {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				dst.set(c, y, x, src.at(c, top+y, left+x))
+			}
+		}
+	}
+	return dst.tensor()
+}
+
+// Crop center region of height and width of CHW tensor
+func CenterCrop(t *graph.Tensor, height, width int) *graph.Tensor {
+	shape := t.Shape()
+	if shape.Dim() != 3 {
+		panic(ErrNotImage)
+	}
+	return Crop(t, (shape[1]-height)/2, (shape[2]-width)/2, height, width)
+}
+
+// Normalize every channel of CHW tensor by (value - mean) / std
+func Normalize(t *graph.Tensor, mean, std []float64) *graph.Tensor {
+	p := fromTensor(t)
+	if len(mean) != p.c || len(std) != p.c {
+		panic(ErrChannelsCount)
+	}
+	plane := p.h * p.w
+	for c := 0; c < p.c; c++ {
+		m, s := float32(mean[c]), float32(std[c])
+		for i := c * plane; i < (c+1)*plane; i++ {
+			p.data[i] = (p.data[i] - m) / s
+		}
+	}
+	return p.tensor()
+}
+
+// Mean and standard deviation of every channel over CHW tensors, used as parameters of Normalize
+func ChannelStats(ts []*graph.Tensor) ([]float64, []float64) {
+	if len(ts) == 0 {
+		panic(ErrNotImage)
+	}
+	var mean, sq []float64
+	count := 0.0
+	for _, t := range ts {
+		p := fromTensor(t)
+		if mean == nil {
+			mean, sq = make([]float64, p.c), make([]float64, p.c)
+		} else if len(mean) != p.c {
+			panic(ErrChannelsCount)
+		}
+		plane := p.h * p.w
+		for c := 0; c < p.c; c++ {
+			for _, v := range p.data[c*plane : (c+1)*plane] {
+				mean[c] += float64(v)
+				sq[c] += float64(v) * float64(v)
+			}
+		}
+		count += float64(plane)
+	}
+	std := make([]float64, len(mean))
+	for c := range mean {
+		mean[c] /= count
+		std[c] = math.Sqrt(math.Max(sq[c]/count-mean[c]*mean[c], 0))
+	}
+	return mean, std
+}
+
+// Stack tensors of equal shape in a batch tensor with a new first dimension
+func Batch(ts []*graph.Tensor) *graph.Tensor {
+	if len(ts) == 0 {
+		panic(ErrNotImage)
+	}
+	return data.Stack(ts)
+}
@@ -0,0 +1,61 @@
+package vision
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellviaproject/go-ia/nn/graph"
+)
+
+// one channel CHW tensor of rows of values
+func grayTensor(rows [][]float32) *graph.Tensor {
+	p := newPlanes(1, len(rows), len(rows[0]))
+	for y, row := range rows {
+		for x, v := range row {
+			p.set(0, y, x, v)
+		}
+	}
+	return p.tensor()
+}
+
+func TestResize(t *testing.T) {
+	ts := grayTensor([][]float32{{0, 1}, {2, 3}})
+	big := fromTensor(Resize(ts, 4, 4))
+	// corners keep values and centers are interpolated
+	if big.at(0, 0, 0) != 0 || big.at(0, 3, 3) != 3 {
+		t.Errorf("Resize failed. Expected corners 0 and 3, but got %v and %v", big.at(0, 0, 0), big.at(0, 3, 3))
+	}
+	if v := big.at(0, 1, 1); math.Abs(float64(v)-0.75) > 1e-6 {
+		t.Errorf("Resize failed. Expected 0.75, but got %v", v)
+	}
+	small := fromTensor(Resize(big.tensor(), 2, 2))
+	if small.h != 2 || small.w != 2 || math.Abs(float64(small.at(0, 0, 0))-0.375) > 1e-6 {
+		t.Errorf("Resize failed. Unexpected downsampled image %v", small.data)
+	}
+}
+
+func TestCropNormalize(t *testing.T) {
+	ts := grayTensor([][]float32{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9, 10, 11}})
+	crop := fromTensor(CenterCrop(ts, 1, 2))
+	if crop.at(0, 0, 0) != 5 || crop.at(0, 0, 1) != 6 {
+		t.Errorf("CenterCrop failed. Expected [5 6], but got %v", crop.data)
+	}
+	mean, std := ChannelStats([]*graph.Tensor{ts})
+	norm := Normalize(ts, mean, std)
+	m, s := ChannelStats([]*graph.Tensor{norm})
+	if math.Abs(m[0]) > 1e-6 || math.Abs(s[0]-1) > 1e-6 {
+		t.Errorf("Normalize failed. Expected mean 0 and std 1, but got %v and %v", m[0], s[0])
+	}
+}
+
+func TestBatch(t *testing.T) {
+	a := grayTensor([][]float32{{1, 2}})
+	b := grayTensor([][]float32{{3, 4}})
+	batch := Batch([]*graph.Tensor{a, b})
+	if shape := batch.Shape(); len(shape) != 4 || shape[0] != 2 {
+		t.Fatalf("Batch failed. Expected shape (2, 1, 1, 2), but got %v", shape)
+	}
+	if v := batch.GetF32At([]int{1, 0, 0, 1}); v != 4 {
+		t.Errorf("Batch failed. Expected 4, but got %v", v)
+	}
+}